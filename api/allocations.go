@@ -271,6 +271,7 @@ type AllocationMetric struct {
 	AllocationTime    time.Duration
 	CoalescedFailures int
 	ScoreMetaData     []*NodeScoreMeta
+	ScoreExplanation  []*NodeScoreMeta
 }
 
 // NodeScoreMeta is used to serialize node scoring metadata
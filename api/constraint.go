@@ -9,8 +9,17 @@ const (
 	ConstraintSetContains       = "set_contains"
 	ConstraintSetContainsAll    = "set_contains_all"
 	ConstraintSetContainsAny    = "set_contains_any"
+	ConstraintSetContainsNone   = "set_contains_none"
 	ConstraintAttributeIsSet    = "is_set"
 	ConstraintAttributeIsNotSet = "is_not_set"
+	ConstraintCIDRContains      = "cidr_contains"
+
+	// The numeric operators compare both values as numbers, and are never
+	// satisfied if either value is not a number.
+	ConstraintNumericLess         = "num_lt"
+	ConstraintNumericLessEqual    = "num_lte"
+	ConstraintNumericGreater      = "num_gt"
+	ConstraintNumericGreaterEqual = "num_gte"
 )
 
 // Constraint is used to serialize a job placement constraint.
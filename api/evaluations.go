@@ -97,6 +97,7 @@ type Evaluation struct {
 	EscapedComputedClass bool
 	QuotaLimitReached    string
 	AnnotatePlan         bool
+	ExplainScores        bool
 	QueuedAllocations    map[string]int
 	SnapshotIndex        uint64
 	CreateIndex          uint64
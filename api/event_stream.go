@@ -17,6 +17,10 @@ const (
 	TopicJob        Topic = "Job"
 	TopicNode       Topic = "Node"
 	TopicService    Topic = "Service"
+	TopicVariable   Topic = "Variable"
+	TopicNamespace  Topic = "Namespace"
+	TopicACLRole    Topic = "ACLRole"
+	TopicRootKey    Topic = "RootKey"
 	TopicAll        Topic = "*"
 )
 
@@ -102,13 +106,58 @@ func (e *Event) Service() (*ServiceRegistration, error) {
 	return out.Service, nil
 }
 
+// Variable returns a VariableMetadata struct from a given event payload. If
+// the Event Topic is Variable this will return valid VariableMetadata. The
+// items of the variable are never included within events.
+func (e *Event) Variable() (*VariableMetadata, error) {
+	out, err := e.decodePayload()
+	if err != nil {
+		return nil, err
+	}
+	return out.Variable, nil
+}
+
+// Namespace returns a Namespace struct from a given event payload. If the
+// Event Topic is Namespace this will return a valid Namespace.
+func (e *Event) Namespace() (*Namespace, error) {
+	out, err := e.decodePayload()
+	if err != nil {
+		return nil, err
+	}
+	return out.Namespace, nil
+}
+
+// ACLRole returns an ACLRole struct from a given event payload. If the Event
+// Topic is ACLRole this will return a valid ACLRole.
+func (e *Event) ACLRole() (*ACLRole, error) {
+	out, err := e.decodePayload()
+	if err != nil {
+		return nil, err
+	}
+	return out.ACLRole, nil
+}
+
+// RootKeyMeta returns a RootKeyMeta struct from a given event payload. If the
+// Event Topic is RootKey this will return valid RootKeyMeta.
+func (e *Event) RootKeyMeta() (*RootKeyMeta, error) {
+	out, err := e.decodePayload()
+	if err != nil {
+		return nil, err
+	}
+	return out.RootKeyMeta, nil
+}
+
 type eventPayload struct {
-	Allocation *Allocation          `mapstructure:"Allocation"`
-	Deployment *Deployment          `mapstructure:"Deployment"`
-	Evaluation *Evaluation          `mapstructure:"Evaluation"`
-	Job        *Job                 `mapstructure:"Job"`
-	Node       *Node                `mapstructure:"Node"`
-	Service    *ServiceRegistration `mapstructure:"Service"`
+	Allocation  *Allocation          `mapstructure:"Allocation"`
+	Deployment  *Deployment          `mapstructure:"Deployment"`
+	Evaluation  *Evaluation          `mapstructure:"Evaluation"`
+	Job         *Job                 `mapstructure:"Job"`
+	Node        *Node                `mapstructure:"Node"`
+	Service     *ServiceRegistration `mapstructure:"Service"`
+	Variable    *VariableMetadata    `mapstructure:"Variable"`
+	Namespace   *Namespace           `mapstructure:"Namespace"`
+	ACLRole     *ACLRole             `mapstructure:"ACLRole"`
+	RootKeyMeta *RootKeyMeta         `mapstructure:"RootKeyMeta"`
 }
 
 func (e *Event) decodePayload() (*eventPayload, error) {
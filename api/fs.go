@@ -6,6 +6,7 @@ import (
 	"io"
 	"net"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 )
@@ -225,6 +226,25 @@ func queryClientNode(c *Client, alloc *Allocation, reqPath string, q *QueryOptio
 	return r, err
 }
 
+// LogsFilter filters the lines of a task's logs on the client before they're
+// streamed.
+type LogsFilter struct {
+	// Match is a regular expression that lines must match.
+	Match string
+
+	// Since and Until bound when lines were written. A zero value leaves
+	// that end of the range open.
+	Since time.Time
+	Until time.Time
+
+	// JSON parses each line as a JSON object so it can be filtered by its
+	// timestamp and have its fields selected.
+	JSON bool
+
+	// Fields are the fields of JSON lines to output.
+	Fields []string
+}
+
 // Logs streams the content of a tasks logs blocking on EOF.
 // The parameters are:
 // * allocation: the allocation to stream from.
@@ -246,6 +266,14 @@ func queryClientNode(c *Client, alloc *Allocation, reqPath string, q *QueryOptio
 // long pauses on this API call.
 func (a *AllocFS) Logs(alloc *Allocation, follow bool, task, logType, origin string,
 	offset int64, cancel <-chan struct{}, q *QueryOptions) (<-chan *StreamFrame, <-chan error) {
+	return a.LogsWithFilter(alloc, follow, task, logType, origin, offset, nil, cancel, q)
+}
+
+// LogsWithFilter streams the lines of a task's logs that match the filter.
+// The parameters are the same as Logs, and the filter may be nil. Offsets
+// refer to the unfiltered logs.
+func (a *AllocFS) LogsWithFilter(alloc *Allocation, follow bool, task, logType, origin string,
+	offset int64, filter *LogsFilter, cancel <-chan struct{}, q *QueryOptions) (<-chan *StreamFrame, <-chan error) {
 
 	errCh := make(chan error, 1)
 
@@ -257,6 +285,7 @@ func (a *AllocFS) Logs(alloc *Allocation, follow bool, task, logType, origin str
 			q.Params["type"] = logType
 			q.Params["origin"] = origin
 			q.Params["offset"] = strconv.FormatInt(offset, 10)
+			filter.setParams(q.Params)
 		})
 	if err != nil {
 		errCh <- err
@@ -305,6 +334,28 @@ func (a *AllocFS) Logs(alloc *Allocation, follow bool, task, logType, origin str
 	return frames, errCh
 }
 
+// setParams sets the query parameters of the filter. A nil filter sets none.
+func (f *LogsFilter) setParams(params map[string]string) {
+	if f == nil {
+		return
+	}
+	if f.Match != "" {
+		params["match"] = f.Match
+	}
+	if !f.Since.IsZero() {
+		params["since"] = f.Since.Format(time.RFC3339Nano)
+	}
+	if !f.Until.IsZero() {
+		params["until"] = f.Until.Format(time.RFC3339Nano)
+	}
+	if f.JSON {
+		params["json"] = "true"
+	}
+	if len(f.Fields) > 0 {
+		params["fields"] = strings.Join(f.Fields, ",")
+	}
+}
+
 // FrameReader is used to convert a stream of frames into a read closer.
 type FrameReader struct {
 	frames   <-chan *StreamFrame
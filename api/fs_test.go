@@ -291,3 +291,26 @@ func TestFS_FrameReader_Error(t *testing.T) {
 		t.Fatalf("bad error: %v", err)
 	}
 }
+
+func TestFS_LogsFilter_setParams(t *testing.T) {
+	testutil.Parallel(t)
+
+	params := map[string]string{}
+	var nilFilter *LogsFilter
+	nilFilter.setParams(params)
+	require.Empty(t, params)
+
+	filter := &LogsFilter{
+		Match:  "error",
+		Since:  time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC),
+		JSON:   true,
+		Fields: []string{"msg", "level"},
+	}
+	filter.setParams(params)
+	require.Equal(t, map[string]string{
+		"match":  "error",
+		"since":  "2022-06-01T12:00:00Z",
+		"json":   "true",
+		"fields": "msg,level",
+	}, params)
+}
@@ -392,6 +392,9 @@ func (j *Jobs) PeriodicForce(jobID string, q *WriteOptions) (string, *WriteMeta,
 type PlanOptions struct {
 	Diff           bool
 	PolicyOverride bool
+
+	// Explain requests the scores of every node scored for each placement
+	Explain bool
 }
 
 func (j *Jobs) Plan(job *Job, diff bool, q *WriteOptions) (*JobPlanResponse, *WriteMeta, error) {
@@ -411,6 +414,7 @@ func (j *Jobs) PlanOpts(job *Job, opts *PlanOptions, q *WriteOptions) (*JobPlanR
 	if opts != nil {
 		req.Diff = opts.Diff
 		req.PolicyOverride = opts.PolicyOverride
+		req.Explain = opts.Explain
 	}
 
 	var resp JobPlanResponse
@@ -768,6 +772,26 @@ type MultiregionRegion struct {
 	Meta        map[string]string `hcl:"meta,block"`
 }
 
+const (
+	JobDependencyConditionHealthy  = "healthy"
+	JobDependencyConditionRunning  = "running"
+	JobDependencyConditionComplete = "complete"
+)
+
+// JobDependency holds back the placement of a job until another job of the
+// same namespace, or one of its groups, reaches the required state.
+type JobDependency struct {
+	Job       string `hcl:"job,optional"`
+	Group     string `hcl:"group,optional"`
+	Condition string `hcl:"condition,optional"`
+}
+
+func (d *JobDependency) Canonicalize() {
+	if d.Condition == "" {
+		d.Condition = JobDependencyConditionHealthy
+	}
+}
+
 // PeriodicConfig is for serializing periodic config for a job.
 type PeriodicConfig struct {
 	Enabled         *bool   `hcl:"enabled,optional"`
@@ -845,27 +869,30 @@ type ParameterizedJobConfig struct {
 type Job struct {
 	/* Fields parsed from HCL config */
 
-	Region           *string                 `hcl:"region,optional"`
-	Namespace        *string                 `hcl:"namespace,optional"`
-	ID               *string                 `hcl:"id,optional"`
-	Name             *string                 `hcl:"name,optional"`
-	Type             *string                 `hcl:"type,optional"`
-	Priority         *int                    `hcl:"priority,optional"`
-	AllAtOnce        *bool                   `mapstructure:"all_at_once" hcl:"all_at_once,optional"`
-	Datacenters      []string                `hcl:"datacenters,optional"`
-	Constraints      []*Constraint           `hcl:"constraint,block"`
-	Affinities       []*Affinity             `hcl:"affinity,block"`
-	TaskGroups       []*TaskGroup            `hcl:"group,block"`
-	Update           *UpdateStrategy         `hcl:"update,block"`
-	Multiregion      *Multiregion            `hcl:"multiregion,block"`
-	Spreads          []*Spread               `hcl:"spread,block"`
-	Periodic         *PeriodicConfig         `hcl:"periodic,block"`
-	ParameterizedJob *ParameterizedJobConfig `hcl:"parameterized,block"`
-	Reschedule       *ReschedulePolicy       `hcl:"reschedule,block"`
-	Migrate          *MigrateStrategy        `hcl:"migrate,block"`
-	Meta             map[string]string       `hcl:"meta,block"`
-	ConsulToken      *string                 `mapstructure:"consul_token" hcl:"consul_token,optional"`
-	VaultToken       *string                 `mapstructure:"vault_token" hcl:"vault_token,optional"`
+	Region             *string                 `hcl:"region,optional"`
+	Namespace          *string                 `hcl:"namespace,optional"`
+	ID                 *string                 `hcl:"id,optional"`
+	Name               *string                 `hcl:"name,optional"`
+	Type               *string                 `hcl:"type,optional"`
+	Priority           *int                    `hcl:"priority,optional"`
+	AllAtOnce          *bool                   `mapstructure:"all_at_once" hcl:"all_at_once,optional"`
+	NonPreemptible     *bool                   `mapstructure:"non_preemptible" hcl:"non_preemptible,optional"`
+	SchedulerAlgorithm *string                 `mapstructure:"scheduler_algorithm" hcl:"scheduler_algorithm,optional"`
+	Datacenters        []string                `hcl:"datacenters,optional"`
+	Constraints        []*Constraint           `hcl:"constraint,block"`
+	Affinities         []*Affinity             `hcl:"affinity,block"`
+	DependsOn          []*JobDependency        `mapstructure:"depends_on" hcl:"depends_on,block"`
+	TaskGroups         []*TaskGroup            `hcl:"group,block"`
+	Update             *UpdateStrategy         `hcl:"update,block"`
+	Multiregion        *Multiregion            `hcl:"multiregion,block"`
+	Spreads            []*Spread               `hcl:"spread,block"`
+	Periodic           *PeriodicConfig         `hcl:"periodic,block"`
+	ParameterizedJob   *ParameterizedJobConfig `hcl:"parameterized,block"`
+	Reschedule         *ReschedulePolicy       `hcl:"reschedule,block"`
+	Migrate            *MigrateStrategy        `hcl:"migrate,block"`
+	Meta               map[string]string       `hcl:"meta,block"`
+	ConsulToken        *string                 `mapstructure:"consul_token" hcl:"consul_token,optional"`
+	VaultToken         *string                 `mapstructure:"vault_token" hcl:"vault_token,optional"`
 
 	/* Fields set by server, not sourced from job config file */
 
@@ -933,6 +960,12 @@ func (j *Job) Canonicalize() {
 	if j.AllAtOnce == nil {
 		j.AllAtOnce = pointerOf(false)
 	}
+	if j.NonPreemptible == nil {
+		j.NonPreemptible = pointerOf(false)
+	}
+	if j.SchedulerAlgorithm == nil {
+		j.SchedulerAlgorithm = pointerOf("")
+	}
 	if j.ConsulToken == nil {
 		j.ConsulToken = pointerOf("")
 	}
@@ -991,6 +1024,9 @@ func (j *Job) Canonicalize() {
 	for _, a := range j.Affinities {
 		a.Canonicalize()
 	}
+	for _, d := range j.DependsOn {
+		d.Canonicalize()
+	}
 }
 
 // LookupTaskGroup finds a task group by name
@@ -1270,6 +1306,7 @@ type JobPlanRequest struct {
 	Job            *Job
 	Diff           bool
 	PolicyOverride bool
+	Explain        bool
 	WriteRequest
 }
 
@@ -1284,6 +1321,19 @@ type JobPlanResponse struct {
 	// Warnings contains any warnings about the given job. These may include
 	// deprecation warnings.
 	Warnings string
+
+	// ScoreExplanations explains the node selected for each placement. It is
+	// only populated when the plan was requested with Explain.
+	ScoreExplanations []*PlacementExplanation
+}
+
+// PlacementExplanation lists the scores of every node scored for a
+// placement, sorted by normalized score.
+type PlacementExplanation struct {
+	AllocName string
+	TaskGroup string
+	NodeID    string
+	Scores    []*NodeScoreMeta
 }
 
 type JobDiff struct {
@@ -1326,8 +1376,9 @@ type ObjectDiff struct {
 }
 
 type PlanAnnotations struct {
-	DesiredTGUpdates map[string]*DesiredUpdates
-	PreemptedAllocs  []*AllocationListStub
+	DesiredTGUpdates   map[string]*DesiredUpdates
+	PreemptedAllocs    []*AllocationListStub
+	PreemptionPriority int
 }
 
 type DesiredUpdates struct {
@@ -1389,4 +1440,5 @@ type JobEvaluateRequest struct {
 // EvalOptions is used to encapsulate options when forcing a job evaluation
 type EvalOptions struct {
 	ForceReschedule bool
+	ExplainScores   bool
 }
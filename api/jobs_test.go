@@ -288,27 +288,29 @@ func TestJobs_Canonicalize(t *testing.T) {
 				},
 			},
 			expected: &Job{
-				ID:                pointerOf(""),
-				Name:              pointerOf(""),
-				Region:            pointerOf("global"),
-				Namespace:         pointerOf(DefaultNamespace),
-				Type:              pointerOf("service"),
-				ParentID:          pointerOf(""),
-				Priority:          pointerOf(50),
-				AllAtOnce:         pointerOf(false),
-				ConsulToken:       pointerOf(""),
-				ConsulNamespace:   pointerOf(""),
-				VaultToken:        pointerOf(""),
-				VaultNamespace:    pointerOf(""),
-				NomadTokenID:      pointerOf(""),
-				Status:            pointerOf(""),
-				StatusDescription: pointerOf(""),
-				Stop:              pointerOf(false),
-				Stable:            pointerOf(false),
-				Version:           pointerOf(uint64(0)),
-				CreateIndex:       pointerOf(uint64(0)),
-				ModifyIndex:       pointerOf(uint64(0)),
-				JobModifyIndex:    pointerOf(uint64(0)),
+				ID:                 pointerOf(""),
+				Name:               pointerOf(""),
+				Region:             pointerOf("global"),
+				Namespace:          pointerOf(DefaultNamespace),
+				Type:               pointerOf("service"),
+				ParentID:           pointerOf(""),
+				Priority:           pointerOf(50),
+				AllAtOnce:          pointerOf(false),
+				ConsulToken:        pointerOf(""),
+				ConsulNamespace:    pointerOf(""),
+				VaultToken:         pointerOf(""),
+				VaultNamespace:     pointerOf(""),
+				NomadTokenID:       pointerOf(""),
+				Status:             pointerOf(""),
+				StatusDescription:  pointerOf(""),
+				Stop:               pointerOf(false),
+				NonPreemptible:     pointerOf(false),
+				SchedulerAlgorithm: pointerOf(""),
+				Stable:             pointerOf(false),
+				Version:            pointerOf(uint64(0)),
+				CreateIndex:        pointerOf(uint64(0)),
+				ModifyIndex:        pointerOf(uint64(0)),
+				JobModifyIndex:     pointerOf(uint64(0)),
 				Update: &UpdateStrategy{
 					Stagger:          pointerOf(30 * time.Second),
 					MaxParallel:      pointerOf(1),
@@ -324,6 +326,7 @@ func TestJobs_Canonicalize(t *testing.T) {
 					{
 						Name:  pointerOf(""),
 						Count: pointerOf(1),
+						Gang:  pointerOf(false),
 						EphemeralDisk: &EphemeralDisk{
 							Sticky:  pointerOf(false),
 							Migrate: pointerOf(false),
@@ -383,31 +386,34 @@ func TestJobs_Canonicalize(t *testing.T) {
 				},
 			},
 			expected: &Job{
-				ID:                pointerOf(""),
-				Name:              pointerOf(""),
-				Region:            pointerOf("global"),
-				Namespace:         pointerOf(DefaultNamespace),
-				Type:              pointerOf("batch"),
-				ParentID:          pointerOf(""),
-				Priority:          pointerOf(50),
-				AllAtOnce:         pointerOf(false),
-				ConsulToken:       pointerOf(""),
-				ConsulNamespace:   pointerOf(""),
-				VaultToken:        pointerOf(""),
-				VaultNamespace:    pointerOf(""),
-				NomadTokenID:      pointerOf(""),
-				Status:            pointerOf(""),
-				StatusDescription: pointerOf(""),
-				Stop:              pointerOf(false),
-				Stable:            pointerOf(false),
-				Version:           pointerOf(uint64(0)),
-				CreateIndex:       pointerOf(uint64(0)),
-				ModifyIndex:       pointerOf(uint64(0)),
-				JobModifyIndex:    pointerOf(uint64(0)),
+				ID:                 pointerOf(""),
+				Name:               pointerOf(""),
+				Region:             pointerOf("global"),
+				Namespace:          pointerOf(DefaultNamespace),
+				Type:               pointerOf("batch"),
+				ParentID:           pointerOf(""),
+				Priority:           pointerOf(50),
+				AllAtOnce:          pointerOf(false),
+				ConsulToken:        pointerOf(""),
+				ConsulNamespace:    pointerOf(""),
+				VaultToken:         pointerOf(""),
+				VaultNamespace:     pointerOf(""),
+				NomadTokenID:       pointerOf(""),
+				Status:             pointerOf(""),
+				StatusDescription:  pointerOf(""),
+				Stop:               pointerOf(false),
+				NonPreemptible:     pointerOf(false),
+				SchedulerAlgorithm: pointerOf(""),
+				Stable:             pointerOf(false),
+				Version:            pointerOf(uint64(0)),
+				CreateIndex:        pointerOf(uint64(0)),
+				ModifyIndex:        pointerOf(uint64(0)),
+				JobModifyIndex:     pointerOf(uint64(0)),
 				TaskGroups: []*TaskGroup{
 					{
 						Name:  pointerOf(""),
 						Count: pointerOf(1),
+						Gang:  pointerOf(false),
 						EphemeralDisk: &EphemeralDisk{
 							Sticky:  pointerOf(false),
 							Migrate: pointerOf(false),
@@ -461,27 +467,29 @@ func TestJobs_Canonicalize(t *testing.T) {
 				},
 			},
 			expected: &Job{
-				Namespace:         pointerOf("bar"),
-				ID:                pointerOf("bar"),
-				Name:              pointerOf("foo"),
-				Region:            pointerOf("global"),
-				Type:              pointerOf("service"),
-				ParentID:          pointerOf("lol"),
-				Priority:          pointerOf(50),
-				AllAtOnce:         pointerOf(false),
-				ConsulToken:       pointerOf(""),
-				ConsulNamespace:   pointerOf(""),
-				VaultToken:        pointerOf(""),
-				VaultNamespace:    pointerOf(""),
-				NomadTokenID:      pointerOf(""),
-				Stop:              pointerOf(false),
-				Stable:            pointerOf(false),
-				Version:           pointerOf(uint64(0)),
-				Status:            pointerOf(""),
-				StatusDescription: pointerOf(""),
-				CreateIndex:       pointerOf(uint64(0)),
-				ModifyIndex:       pointerOf(uint64(0)),
-				JobModifyIndex:    pointerOf(uint64(0)),
+				Namespace:          pointerOf("bar"),
+				ID:                 pointerOf("bar"),
+				Name:               pointerOf("foo"),
+				Region:             pointerOf("global"),
+				Type:               pointerOf("service"),
+				ParentID:           pointerOf("lol"),
+				Priority:           pointerOf(50),
+				AllAtOnce:          pointerOf(false),
+				ConsulToken:        pointerOf(""),
+				ConsulNamespace:    pointerOf(""),
+				VaultToken:         pointerOf(""),
+				VaultNamespace:     pointerOf(""),
+				NomadTokenID:       pointerOf(""),
+				Stop:               pointerOf(false),
+				NonPreemptible:     pointerOf(false),
+				SchedulerAlgorithm: pointerOf(""),
+				Stable:             pointerOf(false),
+				Version:            pointerOf(uint64(0)),
+				Status:             pointerOf(""),
+				StatusDescription:  pointerOf(""),
+				CreateIndex:        pointerOf(uint64(0)),
+				ModifyIndex:        pointerOf(uint64(0)),
+				JobModifyIndex:     pointerOf(uint64(0)),
 				Update: &UpdateStrategy{
 					Stagger:          pointerOf(30 * time.Second),
 					MaxParallel:      pointerOf(1),
@@ -497,6 +505,7 @@ func TestJobs_Canonicalize(t *testing.T) {
 					{
 						Name:  pointerOf("bar"),
 						Count: pointerOf(1),
+						Gang:  pointerOf(false),
 						EphemeralDisk: &EphemeralDisk{
 							Sticky:  pointerOf(false),
 							Migrate: pointerOf(false),
@@ -568,6 +577,7 @@ func TestJobs_Canonicalize(t *testing.T) {
 						Update: &UpdateStrategy{
 							AutoRevert: pointerOf(true),
 						},
+						Gang: pointerOf(false),
 						EphemeralDisk: &EphemeralDisk{
 							SizeMB: pointerOf(300),
 						},
@@ -632,28 +642,30 @@ func TestJobs_Canonicalize(t *testing.T) {
 				},
 			},
 			expected: &Job{
-				Namespace:         pointerOf(DefaultNamespace),
-				ID:                pointerOf("example_template"),
-				Name:              pointerOf("example_template"),
-				ParentID:          pointerOf(""),
-				Priority:          pointerOf(50),
-				Region:            pointerOf("global"),
-				Type:              pointerOf("service"),
-				AllAtOnce:         pointerOf(false),
-				ConsulToken:       pointerOf(""),
-				ConsulNamespace:   pointerOf(""),
-				VaultToken:        pointerOf(""),
-				VaultNamespace:    pointerOf(""),
-				NomadTokenID:      pointerOf(""),
-				Stop:              pointerOf(false),
-				Stable:            pointerOf(false),
-				Version:           pointerOf(uint64(0)),
-				Status:            pointerOf(""),
-				StatusDescription: pointerOf(""),
-				CreateIndex:       pointerOf(uint64(0)),
-				ModifyIndex:       pointerOf(uint64(0)),
-				JobModifyIndex:    pointerOf(uint64(0)),
-				Datacenters:       []string{"dc1"},
+				Namespace:          pointerOf(DefaultNamespace),
+				ID:                 pointerOf("example_template"),
+				Name:               pointerOf("example_template"),
+				ParentID:           pointerOf(""),
+				Priority:           pointerOf(50),
+				Region:             pointerOf("global"),
+				Type:               pointerOf("service"),
+				AllAtOnce:          pointerOf(false),
+				ConsulToken:        pointerOf(""),
+				ConsulNamespace:    pointerOf(""),
+				VaultToken:         pointerOf(""),
+				VaultNamespace:     pointerOf(""),
+				NomadTokenID:       pointerOf(""),
+				Stop:               pointerOf(false),
+				NonPreemptible:     pointerOf(false),
+				SchedulerAlgorithm: pointerOf(""),
+				Stable:             pointerOf(false),
+				Version:            pointerOf(uint64(0)),
+				Status:             pointerOf(""),
+				StatusDescription:  pointerOf(""),
+				CreateIndex:        pointerOf(uint64(0)),
+				ModifyIndex:        pointerOf(uint64(0)),
+				JobModifyIndex:     pointerOf(uint64(0)),
+				Datacenters:        []string{"dc1"},
 				Update: &UpdateStrategy{
 					Stagger:          pointerOf(30 * time.Second),
 					MaxParallel:      pointerOf(1),
@@ -683,6 +695,7 @@ func TestJobs_Canonicalize(t *testing.T) {
 							MaxDelay:      pointerOf(1 * time.Hour),
 							Unlimited:     pointerOf(true),
 						},
+						Gang: pointerOf(false),
 						EphemeralDisk: &EphemeralDisk{
 							Sticky:  pointerOf(false),
 							Migrate: pointerOf(false),
@@ -798,27 +811,29 @@ func TestJobs_Canonicalize(t *testing.T) {
 				Periodic: &PeriodicConfig{},
 			},
 			expected: &Job{
-				Namespace:         pointerOf(DefaultNamespace),
-				ID:                pointerOf("bar"),
-				ParentID:          pointerOf(""),
-				Name:              pointerOf("bar"),
-				Region:            pointerOf("global"),
-				Type:              pointerOf("service"),
-				Priority:          pointerOf(50),
-				AllAtOnce:         pointerOf(false),
-				ConsulToken:       pointerOf(""),
-				ConsulNamespace:   pointerOf(""),
-				VaultToken:        pointerOf(""),
-				VaultNamespace:    pointerOf(""),
-				NomadTokenID:      pointerOf(""),
-				Stop:              pointerOf(false),
-				Stable:            pointerOf(false),
-				Version:           pointerOf(uint64(0)),
-				Status:            pointerOf(""),
-				StatusDescription: pointerOf(""),
-				CreateIndex:       pointerOf(uint64(0)),
-				ModifyIndex:       pointerOf(uint64(0)),
-				JobModifyIndex:    pointerOf(uint64(0)),
+				Namespace:          pointerOf(DefaultNamespace),
+				ID:                 pointerOf("bar"),
+				ParentID:           pointerOf(""),
+				Name:               pointerOf("bar"),
+				Region:             pointerOf("global"),
+				Type:               pointerOf("service"),
+				Priority:           pointerOf(50),
+				AllAtOnce:          pointerOf(false),
+				ConsulToken:        pointerOf(""),
+				ConsulNamespace:    pointerOf(""),
+				VaultToken:         pointerOf(""),
+				VaultNamespace:     pointerOf(""),
+				NomadTokenID:       pointerOf(""),
+				Stop:               pointerOf(false),
+				NonPreemptible:     pointerOf(false),
+				SchedulerAlgorithm: pointerOf(""),
+				Stable:             pointerOf(false),
+				Version:            pointerOf(uint64(0)),
+				Status:             pointerOf(""),
+				StatusDescription:  pointerOf(""),
+				CreateIndex:        pointerOf(uint64(0)),
+				ModifyIndex:        pointerOf(uint64(0)),
+				JobModifyIndex:     pointerOf(uint64(0)),
 				Update: &UpdateStrategy{
 					Stagger:          pointerOf(30 * time.Second),
 					MaxParallel:      pointerOf(1),
@@ -889,27 +904,29 @@ func TestJobs_Canonicalize(t *testing.T) {
 				},
 			},
 			expected: &Job{
-				Namespace:         pointerOf(DefaultNamespace),
-				ID:                pointerOf("bar"),
-				Name:              pointerOf("foo"),
-				Region:            pointerOf("global"),
-				Type:              pointerOf("service"),
-				ParentID:          pointerOf("lol"),
-				Priority:          pointerOf(50),
-				AllAtOnce:         pointerOf(false),
-				ConsulToken:       pointerOf(""),
-				ConsulNamespace:   pointerOf(""),
-				VaultToken:        pointerOf(""),
-				VaultNamespace:    pointerOf(""),
-				NomadTokenID:      pointerOf(""),
-				Stop:              pointerOf(false),
-				Stable:            pointerOf(false),
-				Version:           pointerOf(uint64(0)),
-				Status:            pointerOf(""),
-				StatusDescription: pointerOf(""),
-				CreateIndex:       pointerOf(uint64(0)),
-				ModifyIndex:       pointerOf(uint64(0)),
-				JobModifyIndex:    pointerOf(uint64(0)),
+				Namespace:          pointerOf(DefaultNamespace),
+				ID:                 pointerOf("bar"),
+				Name:               pointerOf("foo"),
+				Region:             pointerOf("global"),
+				Type:               pointerOf("service"),
+				ParentID:           pointerOf("lol"),
+				Priority:           pointerOf(50),
+				AllAtOnce:          pointerOf(false),
+				ConsulToken:        pointerOf(""),
+				ConsulNamespace:    pointerOf(""),
+				VaultToken:         pointerOf(""),
+				VaultNamespace:     pointerOf(""),
+				NomadTokenID:       pointerOf(""),
+				Stop:               pointerOf(false),
+				NonPreemptible:     pointerOf(false),
+				SchedulerAlgorithm: pointerOf(""),
+				Stable:             pointerOf(false),
+				Version:            pointerOf(uint64(0)),
+				Status:             pointerOf(""),
+				StatusDescription:  pointerOf(""),
+				CreateIndex:        pointerOf(uint64(0)),
+				ModifyIndex:        pointerOf(uint64(0)),
+				JobModifyIndex:     pointerOf(uint64(0)),
 				Update: &UpdateStrategy{
 					Stagger:          pointerOf(1 * time.Second),
 					MaxParallel:      pointerOf(1),
@@ -925,6 +942,7 @@ func TestJobs_Canonicalize(t *testing.T) {
 					{
 						Name:  pointerOf("bar"),
 						Count: pointerOf(1),
+						Gang:  pointerOf(false),
 						EphemeralDisk: &EphemeralDisk{
 							Sticky:  pointerOf(false),
 							Migrate: pointerOf(false),
@@ -972,6 +990,7 @@ func TestJobs_Canonicalize(t *testing.T) {
 					{
 						Name:  pointerOf("baz"),
 						Count: pointerOf(1),
+						Gang:  pointerOf(false),
 						EphemeralDisk: &EphemeralDisk{
 							Sticky:  pointerOf(false),
 							Migrate: pointerOf(false),
@@ -1065,27 +1084,29 @@ func TestJobs_Canonicalize(t *testing.T) {
 				},
 			},
 			expected: &Job{
-				Namespace:         pointerOf(DefaultNamespace),
-				ID:                pointerOf("bar"),
-				Name:              pointerOf("foo"),
-				Region:            pointerOf("global"),
-				Type:              pointerOf("service"),
-				ParentID:          pointerOf("lol"),
-				Priority:          pointerOf(50),
-				AllAtOnce:         pointerOf(false),
-				ConsulToken:       pointerOf(""),
-				ConsulNamespace:   pointerOf(""),
-				VaultToken:        pointerOf(""),
-				VaultNamespace:    pointerOf(""),
-				NomadTokenID:      pointerOf(""),
-				Stop:              pointerOf(false),
-				Stable:            pointerOf(false),
-				Version:           pointerOf(uint64(0)),
-				Status:            pointerOf(""),
-				StatusDescription: pointerOf(""),
-				CreateIndex:       pointerOf(uint64(0)),
-				ModifyIndex:       pointerOf(uint64(0)),
-				JobModifyIndex:    pointerOf(uint64(0)),
+				Namespace:          pointerOf(DefaultNamespace),
+				ID:                 pointerOf("bar"),
+				Name:               pointerOf("foo"),
+				Region:             pointerOf("global"),
+				Type:               pointerOf("service"),
+				ParentID:           pointerOf("lol"),
+				Priority:           pointerOf(50),
+				AllAtOnce:          pointerOf(false),
+				ConsulToken:        pointerOf(""),
+				ConsulNamespace:    pointerOf(""),
+				VaultToken:         pointerOf(""),
+				VaultNamespace:     pointerOf(""),
+				NomadTokenID:       pointerOf(""),
+				Stop:               pointerOf(false),
+				NonPreemptible:     pointerOf(false),
+				SchedulerAlgorithm: pointerOf(""),
+				Stable:             pointerOf(false),
+				Version:            pointerOf(uint64(0)),
+				Status:             pointerOf(""),
+				StatusDescription:  pointerOf(""),
+				CreateIndex:        pointerOf(uint64(0)),
+				ModifyIndex:        pointerOf(uint64(0)),
+				JobModifyIndex:     pointerOf(uint64(0)),
 				Update: &UpdateStrategy{
 					Stagger:          pointerOf(30 * time.Second),
 					MaxParallel:      pointerOf(1),
@@ -1101,6 +1122,7 @@ func TestJobs_Canonicalize(t *testing.T) {
 					{
 						Name:  pointerOf("bar"),
 						Count: pointerOf(1),
+						Gang:  pointerOf(false),
 						EphemeralDisk: &EphemeralDisk{
 							Sticky:  pointerOf(false),
 							Migrate: pointerOf(false),
@@ -1153,6 +1175,7 @@ func TestJobs_Canonicalize(t *testing.T) {
 					{
 						Name:  pointerOf("baz"),
 						Count: pointerOf(1),
+						Gang:  pointerOf(false),
 						EphemeralDisk: &EphemeralDisk{
 							Sticky:  pointerOf(false),
 							Migrate: pointerOf(false),
@@ -1236,27 +1259,29 @@ func TestJobs_Canonicalize(t *testing.T) {
 						},
 					},
 				},
-				Namespace:         pointerOf(DefaultNamespace),
-				ID:                pointerOf("bar"),
-				Name:              pointerOf("foo"),
-				Region:            pointerOf("global"),
-				Type:              pointerOf("service"),
-				ParentID:          pointerOf("lol"),
-				Priority:          pointerOf(50),
-				AllAtOnce:         pointerOf(false),
-				ConsulToken:       pointerOf(""),
-				ConsulNamespace:   pointerOf(""),
-				VaultToken:        pointerOf(""),
-				VaultNamespace:    pointerOf(""),
-				NomadTokenID:      pointerOf(""),
-				Stop:              pointerOf(false),
-				Stable:            pointerOf(false),
-				Version:           pointerOf(uint64(0)),
-				Status:            pointerOf(""),
-				StatusDescription: pointerOf(""),
-				CreateIndex:       pointerOf(uint64(0)),
-				ModifyIndex:       pointerOf(uint64(0)),
-				JobModifyIndex:    pointerOf(uint64(0)),
+				Namespace:          pointerOf(DefaultNamespace),
+				ID:                 pointerOf("bar"),
+				Name:               pointerOf("foo"),
+				Region:             pointerOf("global"),
+				Type:               pointerOf("service"),
+				ParentID:           pointerOf("lol"),
+				Priority:           pointerOf(50),
+				AllAtOnce:          pointerOf(false),
+				ConsulToken:        pointerOf(""),
+				ConsulNamespace:    pointerOf(""),
+				VaultToken:         pointerOf(""),
+				VaultNamespace:     pointerOf(""),
+				NomadTokenID:       pointerOf(""),
+				Stop:               pointerOf(false),
+				NonPreemptible:     pointerOf(false),
+				SchedulerAlgorithm: pointerOf(""),
+				Stable:             pointerOf(false),
+				Version:            pointerOf(uint64(0)),
+				Status:             pointerOf(""),
+				StatusDescription:  pointerOf(""),
+				CreateIndex:        pointerOf(uint64(0)),
+				ModifyIndex:        pointerOf(uint64(0)),
+				JobModifyIndex:     pointerOf(uint64(0)),
 				Update: &UpdateStrategy{
 					Stagger:          pointerOf(30 * time.Second),
 					MaxParallel:      pointerOf(1),
@@ -1282,6 +1307,20 @@ func TestJobs_Canonicalize(t *testing.T) {
 	}
 }
 
+func TestJobDependency_Canonicalize(t *testing.T) {
+	testutil.Parallel(t)
+
+	job := &Job{
+		DependsOn: []*JobDependency{
+			{Job: "db"},
+			{Job: "migrations", Condition: JobDependencyConditionComplete},
+		},
+	}
+	job.Canonicalize()
+	require.Equal(t, JobDependencyConditionHealthy, job.DependsOn[0].Condition)
+	require.Equal(t, JobDependencyConditionComplete, job.DependsOn[1].Condition)
+}
+
 func TestJobs_EnforceRegister(t *testing.T) {
 	testutil.Parallel(t)
 	require := require.New(t)
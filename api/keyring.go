@@ -38,6 +38,10 @@ type RootKeyMeta struct {
 	CreateIndex uint64
 	ModifyIndex uint64
 	State       RootKeyState
+
+	// RekeyRemaining is the number of variables still to be re-encrypted
+	// with the active key while this key is in the rekeying state.
+	RekeyRemaining int
 }
 
 // RootKeyState enum describes the lifecycle of a root key.
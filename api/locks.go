@@ -0,0 +1,206 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultLockTTL is the lease TTL of the locks acquired by a LockLeaser,
+	// unless set otherwise.
+	DefaultLockTTL = 15 * time.Second
+
+	// DefaultLockRetryInterval is the interval at which a LockLeaser retries
+	// acquiring a lock held by another holder, unless set otherwise.
+	DefaultLockRetryInterval = 5 * time.Second
+)
+
+// ErrLockNotHeld is returned when renewing or releasing a lock which is not
+// held by the LockLeaser.
+var ErrLockNotHeld = errors.New("lock not held")
+
+// LockLeaser acquires and releases a lock on a variable, and renews the lease
+// of the lock while it is held.
+type LockLeaser struct {
+	variables     *Variables
+	variable      *Variable
+	wo            *WriteOptions
+	ttl           time.Duration
+	retryInterval time.Duration
+	allocID       string
+
+	heldLock sync.Mutex
+	held     *Variable
+}
+
+// LockOption is used to configure a LockLeaser.
+type LockOption func(*LockLeaser)
+
+// WithLockTTL sets the lease TTL of the lock. The lease is renewed at half of
+// the TTL.
+func WithLockTTL(ttl time.Duration) LockOption {
+	return func(l *LockLeaser) {
+		l.ttl = ttl
+	}
+}
+
+// WithLockRetryInterval sets the interval at which Start retries acquiring a
+// lock held by another holder.
+func WithLockRetryInterval(interval time.Duration) LockOption {
+	return func(l *LockLeaser) {
+		l.retryInterval = interval
+	}
+}
+
+// WithLockAllocID sets the ID of the allocation holding the lock. The lock is
+// released automatically when the allocation stops.
+func WithLockAllocID(allocID string) LockOption {
+	return func(l *LockLeaser) {
+		l.allocID = allocID
+	}
+}
+
+// Locks returns a new LockLeaser for the lock on the given variable. The items
+// of the variable are written when the lock is acquired, if any are set.
+func (c *Client) Locks(v *Variable, wo *WriteOptions, opts ...LockOption) *LockLeaser {
+	l := &LockLeaser{
+		variables:     c.Variables(),
+		variable:      v.Copy(),
+		wo:            wo,
+		ttl:           DefaultLockTTL,
+		retryInterval: DefaultLockRetryInterval,
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+// Acquire attempts to acquire the lock once. It returns false if the lock is
+// held by another holder.
+func (l *LockLeaser) Acquire() (bool, error) {
+	v := l.variable.Copy()
+	v.Lock = &VariableLock{
+		TTL:     l.ttl,
+		AllocID: l.allocID,
+	}
+
+	out, _, err := l.variables.AcquireLock(v, l.wo)
+	if err != nil {
+		var cErr ErrLockConflict
+		if errors.As(err, &cErr) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	l.heldLock.Lock()
+	defer l.heldLock.Unlock()
+	l.held = out
+	return true, nil
+}
+
+// Renew renews the lease of the held lock.
+func (l *LockLeaser) Renew() error {
+	held := l.getHeld()
+	if held == nil {
+		return ErrLockNotHeld
+	}
+
+	_, _, err := l.variables.RenewLock(held, l.wo)
+	return err
+}
+
+// Release releases the held lock.
+func (l *LockLeaser) Release() error {
+	held := l.getHeld()
+	if held == nil {
+		return ErrLockNotHeld
+	}
+
+	_, _, err := l.variables.ReleaseLock(held, l.wo)
+	var cErr ErrLockConflict
+	if err != nil && !errors.As(err, &cErr) {
+		return err
+	}
+
+	l.heldLock.Lock()
+	defer l.heldLock.Unlock()
+	l.held = nil
+
+	// The lease of the lock expired, or the lock was released by the
+	// servers when its allocation stopped
+	if err != nil {
+		return ErrLockNotHeld
+	}
+	return nil
+}
+
+// LockID returns the ID of the held lock, or an empty string if the lock is
+// not held.
+func (l *LockLeaser) LockID() string {
+	held := l.getHeld()
+	if held == nil || held.Lock == nil {
+		return ""
+	}
+	return held.Lock.ID
+}
+
+func (l *LockLeaser) getHeld() *Variable {
+	l.heldLock.Lock()
+	defer l.heldLock.Unlock()
+	return l.held
+}
+
+// Start blocks until the lock is acquired, and then calls protectedFn while
+// renewing the lease of the lock. The lock is released once protectedFn
+// returns. If the lease can't be renewed, the context passed to protectedFn
+// is canceled and Start returns once protectedFn does.
+func (l *LockLeaser) Start(ctx context.Context, protectedFn func(ctx context.Context) error) error {
+	for {
+		acquired, err := l.Acquire()
+		if err != nil {
+			return fmt.Errorf("failed to acquire lock: %w", err)
+		}
+		if acquired {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(l.retryInterval):
+		}
+	}
+
+	protectedCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- protectedFn(protectedCtx)
+	}()
+
+	ticker := time.NewTicker(l.ttl / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case err := <-errCh:
+			if rErr := l.Release(); rErr != nil && err == nil {
+				err = fmt.Errorf("failed to release lock: %w", rErr)
+			}
+			return err
+
+		case <-ticker.C:
+			if err := l.Renew(); err != nil {
+				cancel()
+				<-errCh
+				return fmt.Errorf("failed to renew lock: %w", err)
+			}
+		}
+	}
+}
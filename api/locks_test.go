@@ -0,0 +1,88 @@
+package api
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/api/internal/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocks_AcquireRenewRelease(t *testing.T) {
+	testutil.Parallel(t)
+	c, s := makeClient(t, nil, nil)
+	defer s.Stop()
+
+	sv := NewVariable("locks/acquire")
+	sv.Items["owner"] = "first"
+
+	first := c.Locks(sv, nil, WithLockTTL(10*time.Second))
+	second := c.Locks(NewVariable(sv.Path), nil)
+
+	// the lock can only be held once
+	acquired, err := first.Acquire()
+	require.NoError(t, err)
+	require.True(t, acquired)
+	require.NotEmpty(t, first.LockID())
+
+	acquired, err = second.Acquire()
+	require.NoError(t, err)
+	require.False(t, acquired)
+	require.Empty(t, second.LockID())
+	require.ErrorIs(t, second.Renew(), ErrLockNotHeld)
+
+	// the lock ID is only returned to the holder
+	read, _, err := c.Variables().Read(sv.Path, nil)
+	require.NoError(t, err)
+	require.NotNil(t, read.Lock)
+	require.Empty(t, read.Lock.ID)
+	require.Equal(t, 10*time.Second, read.Lock.TTL)
+
+	require.NoError(t, first.Renew())
+	require.NoError(t, first.Release())
+	require.Empty(t, first.LockID())
+
+	// once released, the lock can be acquired without writing items
+	acquired, err = second.Acquire()
+	require.NoError(t, err)
+	require.True(t, acquired)
+
+	read, _, err = c.Variables().Read(sv.Path, nil)
+	require.NoError(t, err)
+	require.Equal(t, "first", read.Items["owner"])
+
+	// releasing a lock which is not held conflicts
+	_, _, err = c.Variables().ReleaseLock(&Variable{
+		Path: sv.Path,
+		Lock: &VariableLock{ID: "not-the-lock"},
+	}, nil)
+	require.ErrorAs(t, err, &ErrLockConflict{})
+}
+
+func TestLocks_Start(t *testing.T) {
+	testutil.Parallel(t)
+	c, s := makeClient(t, nil, nil)
+	defer s.Stop()
+
+	sv := NewVariable("locks/start")
+	sv.Items["owner"] = "leaser"
+
+	leaser := c.Locks(sv, nil, WithLockRetryInterval(100*time.Millisecond))
+
+	var ran bool
+	err := leaser.Start(context.Background(), func(ctx context.Context) error {
+		read, _, err := c.Variables().Read(sv.Path, nil)
+		require.NoError(t, err)
+		require.NotNil(t, read.Lock)
+		ran = true
+		return nil
+	})
+	require.NoError(t, err)
+	require.True(t, ran)
+
+	// the lock is released once the protected function returns
+	read, _, err := c.Variables().Read(sv.Path, nil)
+	require.NoError(t, err)
+	require.Nil(t, read.Lock)
+}
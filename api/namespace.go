@@ -67,13 +67,15 @@ func (n *Namespaces) Delete(namespace string, q *WriteOptions) (*WriteMeta, erro
 
 // Namespace is used to serialize a namespace.
 type Namespace struct {
-	Name         string
-	Description  string
-	Quota        string
-	Capabilities *NamespaceCapabilities `hcl:"capabilities,block"`
-	Meta         map[string]string
-	CreateIndex  uint64
-	ModifyIndex  uint64
+	Name                      string
+	Description               string
+	Quota                     string
+	Capabilities              *NamespaceCapabilities `hcl:"capabilities,block"`
+	PreemptionPriorityCeiling int                    `mapstructure:"preemption_priority_ceiling" hcl:"preemption_priority_ceiling,optional"`
+	SchedulerAlgorithm        SchedulerAlgorithm     `mapstructure:"scheduler_algorithm" hcl:"scheduler_algorithm,optional"`
+	Meta                      map[string]string
+	CreateIndex               uint64
+	ModifyIndex               uint64
 }
 
 type NamespaceCapabilities struct {
@@ -123,6 +123,10 @@ type SchedulerConfiguration struct {
 	// SchedulerAlgorithm lets you select between available scheduling algorithms.
 	SchedulerAlgorithm SchedulerAlgorithm
 
+	// HeadroomPercent is the percentage of the CPU and memory of each node
+	// which the least-requested-with-headroom algorithm keeps free.
+	HeadroomPercent int
+
 	// PreemptionConfig specifies whether to enable eviction of lower
 	// priority jobs to place higher priority jobs.
 	PreemptionConfig PreemptionConfig
@@ -167,8 +171,9 @@ type SchedulerSetConfigurationResponse struct {
 type SchedulerAlgorithm string
 
 const (
-	SchedulerAlgorithmBinpack SchedulerAlgorithm = "binpack"
-	SchedulerAlgorithmSpread  SchedulerAlgorithm = "spread"
+	SchedulerAlgorithmBinpack                SchedulerAlgorithm = "binpack"
+	SchedulerAlgorithmSpread                 SchedulerAlgorithm = "spread"
+	SchedulerAlgorithmLeastRequestedHeadroom SchedulerAlgorithm = "least-requested-with-headroom"
 )
 
 // PreemptionConfig specifies whether preemption is enabled based on scheduler type
@@ -177,6 +182,7 @@ type PreemptionConfig struct {
 	SysBatchSchedulerEnabled bool
 	BatchSchedulerEnabled    bool
 	ServiceSchedulerEnabled  bool
+	MaxPreemptionsPerMinute  int
 }
 
 // SchedulerGetConfiguration is used to query the current Scheduler configuration.
@@ -199,6 +205,59 @@ func (op *Operator) SchedulerSetConfiguration(conf *SchedulerConfiguration, q *W
 	return &out, wm, nil
 }
 
+// SchedulerRebalanceRequest is used to migrate allocations of service jobs to
+// nodes where they would score better.
+type SchedulerRebalanceRequest struct {
+	// JobID limits the rebalance to a single job in the namespace of the
+	// request.
+	JobID string
+
+	// MinScoreImprovement is the minimum improvement of the normalized
+	// placement score for an allocation to be migrated. The server default
+	// is used if zero.
+	MinScoreImprovement float64
+
+	// DryRun reports the migrations without performing them.
+	DryRun bool
+}
+
+// RebalanceMigration is a migration of an allocation to a node where it
+// would have a better placement score.
+type RebalanceMigration struct {
+	AllocID       string
+	AllocName     string
+	Namespace     string
+	JobID         string
+	TaskGroup     string
+	FromNodeID    string
+	ToNodeID      string
+	CurrentScore  float64
+	ExpectedScore float64
+}
+
+// SchedulerRebalanceResponse is the response object of a rebalance.
+type SchedulerRebalanceResponse struct {
+	// Migrations are the allocations migrated, or that would be migrated for
+	// a dry-run.
+	Migrations []*RebalanceMigration
+
+	// EvalIDs are the evaluations created to migrate the allocations.
+	EvalIDs []string
+
+	WriteMeta
+}
+
+// SchedulerRebalance is used to migrate allocations of service jobs to nodes
+// where they would score better, or to report the migrations for a dry-run.
+func (op *Operator) SchedulerRebalance(req *SchedulerRebalanceRequest, q *WriteOptions) (*SchedulerRebalanceResponse, *WriteMeta, error) {
+	var out SchedulerRebalanceResponse
+	wm, err := op.c.write("/v1/operator/scheduler/rebalance", req, &out, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &out, wm, nil
+}
+
 // SchedulerCASConfiguration is used to perform a Check-And-Set update on the
 // Scheduler configuration. The ModifyIndex value will be respected. Returns
 // true on success or false on failures.
@@ -47,6 +47,14 @@ type ServiceRegistration struct {
 	// services depending on their state and role.
 	Tags []string
 
+	// Meta is determined from either Service.Meta or Service.CanaryMeta and
+	// provides arbitrary metadata about this service.
+	Meta map[string]string
+
+	// Weight is the relative weight of this service registration compared to
+	// other registrations of the same service.
+	Weight int
+
 	// Address is the IP address of this service registration. This information
 	// comes from the client and is not guaranteed to be routable; this depends
 	// on cluster network topology.
@@ -56,10 +64,49 @@ type ServiceRegistration struct {
 	// is determined by a combination of factors on the client.
 	Port int
 
+	// Checks contains the latest result of each check defined on the service
+	// block, as reported by the client running the allocation.
+	Checks []*ServiceRegistrationCheck
+
 	CreateIndex uint64
 	ModifyIndex uint64
 }
 
+// Healthy returns whether all checks of the service registration are
+// currently passing. A registration without checks is always considered
+// healthy.
+func (s *ServiceRegistration) Healthy() bool {
+	for _, check := range s.Checks {
+		if check.Status != "success" {
+			return false
+		}
+	}
+	return true
+}
+
+// ServiceRegistrationCheck is the latest result of a single check of a
+// service registration.
+type ServiceRegistrationCheck struct {
+
+	// Name is the name of the check as defined within the service block.
+	Name string
+
+	// Mode indicates whether the check is a "healthiness" or "readiness"
+	// check.
+	Mode string
+
+	// Status is the result of the most recent execution of the check and is
+	// one of "success", "failure" or "pending".
+	Status string
+
+	// Output is the output of the most recent execution of the check.
+	Output string
+
+	// Timestamp is the time, in seconds since the Unix epoch, the most recent
+	// execution of the check took place.
+	Timestamp int64
+}
+
 // ServiceRegistrationListStub represents all service registrations held within a
 // single namespace.
 type ServiceRegistrationListStub struct {
@@ -243,6 +290,11 @@ type Service struct {
 	// Provider defines which backend system provides the service registration,
 	// either "consul" (default) or "nomad".
 	Provider string `hcl:"provider,optional"`
+
+	// Weight is the relative weight of the service compared to other
+	// instances, for use by load balancers. Only supported by the Nomad
+	// provider.
+	Weight int `hcl:"weight,optional"`
 }
 
 const (
@@ -275,6 +275,7 @@ func (p *ReschedulePolicy) String() string {
 type Spread struct {
 	Attribute    string          `hcl:"attribute,optional"`
 	Weight       *int8           `hcl:"weight,optional"`
+	MaxSkew      *int            `mapstructure:"max_skew" hcl:"max_skew,optional"`
 	SpreadTarget []*SpreadTarget `hcl:"target,block"`
 }
 
@@ -450,6 +451,7 @@ type TaskGroup struct {
 	ShutdownDelay             *time.Duration            `mapstructure:"shutdown_delay" hcl:"shutdown_delay,optional"`
 	StopAfterClientDisconnect *time.Duration            `mapstructure:"stop_after_client_disconnect" hcl:"stop_after_client_disconnect,optional"`
 	MaxClientDisconnect       *time.Duration            `mapstructure:"max_client_disconnect" hcl:"max_client_disconnect,optional"`
+	Gang                      *bool                     `mapstructure:"gang" hcl:"gang,optional"`
 	Scaling                   *ScalingPolicy            `hcl:"scaling,block"`
 	Consul                    *Consul                   `hcl:"consul,block"`
 }
@@ -478,6 +480,9 @@ func (g *TaskGroup) Canonicalize(job *Job) {
 	if g.Scaling != nil {
 		g.Scaling.Canonicalize(*g.Count)
 	}
+	if g.Gang == nil {
+		g.Gang = pointerOf(false)
+	}
 	if g.EphemeralDisk == nil {
 		g.EphemeralDisk = DefaultEphemeralDisk()
 	} else {
@@ -635,14 +640,34 @@ func (g *TaskGroup) AddSpread(s *Spread) *TaskGroup {
 
 // LogConfig provides configuration for log rotation
 type LogConfig struct {
-	MaxFiles      *int `mapstructure:"max_files" hcl:"max_files,optional"`
-	MaxFileSizeMB *int `mapstructure:"max_file_size" hcl:"max_file_size,optional"`
+	MaxFiles         *int           `mapstructure:"max_files" hcl:"max_files,optional"`
+	MaxFileSizeMB    *int           `mapstructure:"max_file_size" hcl:"max_file_size,optional"`
+	RotationInterval *time.Duration `mapstructure:"rotation_interval" hcl:"rotation_interval,optional"`
+	Compress         *bool          `mapstructure:"compress" hcl:"compress,optional"`
+	MaxTotalSizeMB   *int           `mapstructure:"max_total_size" hcl:"max_total_size,optional"`
+	Sinks            []*LogSink     `mapstructure:"sink" hcl:"sink,block"`
+}
+
+// LogSink is an external destination task logs are shipped to.
+type LogSink struct {
+	Type          string        `mapstructure:"type" hcl:"type,optional"`
+	Address       string        `mapstructure:"address" hcl:"address,optional"`
+	Protocol      string        `mapstructure:"protocol" hcl:"protocol,optional"`
+	Facility      string        `mapstructure:"facility" hcl:"facility,optional"`
+	Tag           string        `mapstructure:"tag" hcl:"tag,optional"`
+	BatchSize     int           `mapstructure:"batch_size" hcl:"batch_size,optional"`
+	BatchInterval time.Duration `mapstructure:"batch_interval" hcl:"batch_interval,optional"`
+	BufferSize    int           `mapstructure:"buffer_size" hcl:"buffer_size,optional"`
+	Backpressure  bool          `mapstructure:"backpressure" hcl:"backpressure,optional"`
 }
 
 func DefaultLogConfig() *LogConfig {
 	return &LogConfig{
-		MaxFiles:      pointerOf(10),
-		MaxFileSizeMB: pointerOf(10),
+		MaxFiles:         pointerOf(10),
+		MaxFileSizeMB:    pointerOf(10),
+		RotationInterval: pointerOf(time.Duration(0)),
+		Compress:         pointerOf(false),
+		MaxTotalSizeMB:   pointerOf(0),
 	}
 }
 
@@ -653,6 +678,15 @@ func (l *LogConfig) Canonicalize() {
 	if l.MaxFileSizeMB == nil {
 		l.MaxFileSizeMB = pointerOf(10)
 	}
+	if l.RotationInterval == nil {
+		l.RotationInterval = pointerOf(time.Duration(0))
+	}
+	if l.Compress == nil {
+		l.Compress = pointerOf(false)
+	}
+	if l.MaxTotalSizeMB == nil {
+		l.MaxTotalSizeMB = pointerOf(0)
+	}
 }
 
 // DispatchPayloadConfig configures how a task gets its input from a job dispatch
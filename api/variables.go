@@ -69,6 +69,93 @@ func (sv *Variables) Read(path string, qo *QueryOptions) (*Variable, *QueryMeta,
 	return svar, qm, nil
 }
 
+// ReadVersion is used to query a single version of a variable by path and
+// the modify index of the version. Prior versions are only available when
+// the servers are configured to retain them. This will error if the version
+// is not found.
+func (sv *Variables) ReadVersion(path string, version uint64, qo *QueryOptions) (*Variable, *QueryMeta, error) {
+
+	path = cleanPathString(path)
+	var svar = new(Variable)
+	qm, err := sv.readInternal("/v1/var/"+path+"?version="+fmt.Sprint(version), &svar, qo)
+	if err != nil {
+		return nil, nil, err
+	}
+	if svar == nil {
+		return nil, qm, errors.New(ErrVariableNotFound)
+	}
+	return svar, qm, nil
+}
+
+// ListVersions is used to list the metadata of the current version and the
+// retained prior versions of a variable, ordered from newest to oldest.
+func (sv *Variables) ListVersions(path string, qo *QueryOptions) ([]*VariableMetadata, *QueryMeta, error) {
+
+	path = cleanPathString(path)
+	var resp []*VariableMetadata
+	qm, err := sv.client.query("/v1/var/"+path+"?versions", &resp, qo)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, qm, nil
+}
+
+// Rollback is used to restore a prior version of a variable, identified by
+// the modify index of the version. The current version of the variable is
+// retained as a prior version in turn.
+func (sv *Variables) Rollback(path string, version uint64, qo *WriteOptions) (*Variable, *WriteMeta, error) {
+
+	path = cleanPathString(path)
+	var out Variable
+	wm, err := sv.client.write("/v1/var/"+path+"?rollback="+fmt.Sprint(version), nil, &out, qo)
+	if err != nil {
+		return nil, wm, err
+	}
+	return &out, wm, nil
+}
+
+// AcquireLock is used to acquire a lock on a variable, creating the variable
+// if it does not exist. The items of the variable are only written if any are
+// given. If the variable is already locked, it will return an ErrLockConflict
+// that can be unwrapped for more details.
+func (sv *Variables) AcquireLock(v *Variable, qo *WriteOptions) (*Variable, *WriteMeta, error) {
+
+	v.Path = cleanPathString(v.Path)
+	var out Variable
+	wm, err := sv.writeLock("/v1/var/"+v.Path+"?lock-acquire", v, &out, qo)
+	if err != nil {
+		return nil, wm, err
+	}
+	return &out, wm, nil
+}
+
+// ReleaseLock is used to release the lock held on a variable, identified by
+// the ID of the lock. If the lock is not held, it will return an
+// ErrLockConflict that can be unwrapped for more details.
+func (sv *Variables) ReleaseLock(v *Variable, qo *WriteOptions) (*Variable, *WriteMeta, error) {
+
+	v.Path = cleanPathString(v.Path)
+	var out Variable
+	wm, err := sv.writeLock("/v1/var/"+v.Path+"?lock-release", v, &out, qo)
+	if err != nil {
+		return nil, wm, err
+	}
+	return &out, wm, nil
+}
+
+// RenewLock is used to renew the lease of the lock held on a variable,
+// identified by the ID of the lock.
+func (sv *Variables) RenewLock(v *Variable, qo *WriteOptions) (*VariableMetadata, *WriteMeta, error) {
+
+	v.Path = cleanPathString(v.Path)
+	var out VariableMetadata
+	wm, err := sv.client.write("/v1/var/"+v.Path+"?lock-renew", v, &out, qo)
+	if err != nil {
+		return nil, wm, err
+	}
+	return &out, wm, nil
+}
+
 // Peek is used to query a single variable by path, but does not error
 // when the variable is not found
 func (sv *Variables) Peek(path string, qo *QueryOptions) (*Variable, *QueryMeta, error) {
@@ -309,6 +396,18 @@ func (sv *Variables) writeChecked(endpoint string, in *Variable, out *Variable,
 	return wm, nil
 }
 
+// writeLock exists because lock operations return a 409 (Conflict) when the
+// variable is already locked, or the lock is not held.
+func (sv *Variables) writeLock(endpoint string, in *Variable, out *Variable, q *WriteOptions) (*WriteMeta, error) {
+
+	wm, err := sv.writeChecked(endpoint, in, out, q)
+	var cErr ErrCASConflict
+	if errors.As(err, &cErr) {
+		return nil, ErrLockConflict{Conflict: cErr.Conflict}
+	}
+	return wm, err
+}
+
 // Variable specifies the metadata and contents to be stored in the
 // encrypted Nomad backend.
 type Variable struct {
@@ -325,6 +424,10 @@ type Variable struct {
 	CreateTime int64
 	ModifyTime int64
 
+	// Lock is the lock held on the variable, if any. The ID of the lock is
+	// only returned to the lock holder.
+	Lock *VariableLock
+
 	Items VariableItems
 }
 
@@ -343,6 +446,25 @@ type VariableMetadata struct {
 	// Times provided as a convenience for operators expressed time.UnixNanos
 	CreateTime int64
 	ModifyTime int64
+
+	// Lock is the lock held on the variable, if any.
+	Lock *VariableLock
+}
+
+// VariableLock is a lock held on a variable. The lock is a lease which
+// expires after TTL unless it is renewed by its holder, and is released
+// automatically when the holding allocation stops. Only the holder may write
+// a locked variable, and it can't be deleted or rolled back.
+type VariableLock struct {
+	// ID is generated by the servers when the lock is acquired, and is
+	// required to renew or release the lock, and to write the variable.
+	ID string
+
+	// TTL is the duration of the lease.
+	TTL time.Duration
+
+	// AllocID is the optional ID of the allocation holding the lock.
+	AllocID string
 }
 
 type VariableItems map[string]string
@@ -365,6 +487,10 @@ func (sv1 *Variable) Copy() *Variable {
 	for k, v := range sv1.Items {
 		out.Items[k] = v
 	}
+	if sv1.Lock != nil {
+		lock := *sv1.Lock
+		out.Lock = &lock
+	}
 	return &out
 }
 
@@ -380,6 +506,7 @@ func (sv *Variable) Metadata() *VariableMetadata {
 		ModifyIndex: sv.ModifyIndex,
 		CreateTime:  sv.CreateTime,
 		ModifyTime:  sv.ModifyTime,
+		Lock:        sv.Lock,
 	}
 }
 
@@ -420,6 +547,17 @@ func (e ErrCASConflict) Error() string {
 	return fmt.Sprintf("cas conflict: expected ModifyIndex %v; found %v", e.CheckIndex, e.Conflict.ModifyIndex)
 }
 
+// ErrLockConflict is returned when a lock can't be acquired because the
+// variable is already locked, or can't be released because the lock is not
+// held.
+type ErrLockConflict struct {
+	Conflict *Variable
+}
+
+func (e ErrLockConflict) Error() string {
+	return fmt.Sprintf("lock conflict: variable %q is locked, or the lock is not held", e.Conflict.Path)
+}
+
 // doRequestWrapper is a function that wraps the client's doRequest method
 // and can be used to provide error and response handling
 type doRequestWrapper = func(time.Duration, *http.Response, error) (time.Duration, *http.Response, error)
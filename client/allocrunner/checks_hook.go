@@ -2,11 +2,14 @@ package allocrunner
 
 import (
 	"context"
+	"sort"
 	"sync"
 	"time"
 
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	tinterfaces "github.com/hashicorp/nomad/client/allocrunner/taskrunner/interfaces"
+	"github.com/hashicorp/nomad/client/serviceregistration"
 	"github.com/hashicorp/nomad/client/serviceregistration/checks"
 	"github.com/hashicorp/nomad/client/serviceregistration/checks/checkstore"
 	"github.com/hashicorp/nomad/helper"
@@ -18,6 +21,12 @@ const (
 	checksHookName = "checks_hook"
 )
 
+// scriptExecGetter is used to get the executor of a task in the allocation, for
+// running script checks.
+type scriptExecGetter interface {
+	GetTaskScriptExecutor(taskName string) tinterfaces.ScriptExecutor
+}
+
 // observers maintains a map from check_id -> observer for a particular check. Each
 // observer in the map must share the same context.
 type observers map[structs.CheckID]*observer
@@ -33,6 +42,14 @@ type observer struct {
 	qc      *checks.QueryContext
 	check   *structs.ServiceCheck
 	allocID string
+
+	// serviceID is the ID of the service registration the check belongs to.
+	serviceID string
+
+	// status is the status of the most recent check result, and notify is
+	// called whenever it changes.
+	status structs.CheckStatus
+	notify func()
 }
 
 // start checking our check on its interval
@@ -58,6 +75,12 @@ func (o *observer) start() {
 			// and put the results into the store (already logged)
 			_ = o.checkStore.Set(o.allocID, result)
 
+			// let the hook know the service registration needs updating
+			if result.Status != o.status {
+				o.status = result.Status
+				o.notify()
+			}
+
 			// setup timer for next interval
 			timer.Reset(o.check.Interval)
 		}
@@ -74,11 +97,18 @@ func (o *observer) stop() {
 //
 // Does not manage Consul service checks; see groupServiceHook instead.
 type checksHook struct {
-	logger  hclog.Logger
-	network structs.NetworkStatus
-	shim    checkstore.Shim
-	checker checks.Checker
-	allocID string
+	logger     hclog.Logger
+	network    structs.NetworkStatus
+	exec       scriptExecGetter
+	shim       checkstore.Shim
+	checker    checks.Checker
+	rpc        RPCer
+	nodeSecret string
+	allocID    string
+
+	// updateCh is used to coalesce check status changes into service
+	// registration updates sent to the servers.
+	updateCh chan struct{}
 
 	// fields that get re-initialized on allocation update
 	lock      sync.RWMutex
@@ -93,14 +123,21 @@ func newChecksHook(
 	alloc *structs.Allocation,
 	shim checkstore.Shim,
 	network structs.NetworkStatus,
+	exec scriptExecGetter,
+	rpc RPCer,
+	nodeSecret string,
 ) *checksHook {
 	h := &checksHook{
-		logger:  logger.Named(checksHookName),
-		allocID: alloc.ID,
-		alloc:   alloc,
-		shim:    shim,
-		network: network,
-		checker: checks.New(logger),
+		logger:     logger.Named(checksHookName),
+		allocID:    alloc.ID,
+		alloc:      alloc,
+		shim:       shim,
+		network:    network,
+		exec:       exec,
+		checker:    checks.New(logger),
+		rpc:        rpc,
+		nodeSecret: nodeSecret,
+		updateCh:   make(chan struct{}, 1),
 	}
 	h.initialize(alloc)
 	return h
@@ -142,6 +179,14 @@ func (h *checksHook) observe(alloc *structs.Allocation, services []*structs.Serv
 	}
 
 	for _, service := range services {
+
+		// the workload name is used to generate the service registration ID
+		workloadName := service.TaskName
+		if workloadName == "" {
+			workloadName = "group-" + alloc.TaskGroup
+		}
+		serviceID := serviceregistration.MakeAllocServiceID(alloc.ID, workloadName, service)
+
 		for _, check := range service.Checks {
 
 			// remember the initialization time
@@ -157,6 +202,32 @@ func (h *checksHook) observe(alloc *structs.Allocation, services []*structs.Serv
 
 			ctx, cancel := context.WithCancel(h.ctx)
 
+			qc := &checks.QueryContext{
+				ID:               id,
+				CustomAddress:    service.Address,
+				ServicePortLabel: service.PortLabel,
+				Ports:            ports,
+				Networks:         networks,
+				NetworkStatus:    h.network,
+				Group:            alloc.Name,
+				Task:             service.TaskName,
+				Service:          service.Name,
+				Check:            check.Name,
+			}
+
+			// script checks are run in the task of the check, or else
+			// the task of the service; without either the check fails
+			// as requiring a task
+			taskName := check.TaskName
+			if taskName == "" {
+				taskName = service.TaskName
+			}
+			if check.Type == structs.ServiceCheckScript && h.exec != nil && taskName != "" {
+				qc.TaskExec = func() tinterfaces.ScriptExecutor {
+					return h.exec.GetTaskScriptExecutor(taskName)
+				}
+			}
+
 			// create the observer for this check
 			h.observers[id] = &observer{
 				ctx:        ctx,
@@ -165,18 +236,9 @@ func (h *checksHook) observe(alloc *structs.Allocation, services []*structs.Serv
 				checkStore: h.shim,
 				checker:    h.checker,
 				allocID:    h.allocID,
-				qc: &checks.QueryContext{
-					ID:               id,
-					CustomAddress:    service.Address,
-					ServicePortLabel: service.PortLabel,
-					Ports:            ports,
-					Networks:         networks,
-					NetworkStatus:    h.network,
-					Group:            alloc.Name,
-					Task:             service.TaskName,
-					Service:          service.Name,
-					Check:            check.Name,
-				},
+				serviceID:  serviceID,
+				notify:     h.notify,
+				qc:         qc,
 			}
 
 			// insert a pending result into state store for each check
@@ -192,6 +254,64 @@ func (h *checksHook) observe(alloc *structs.Allocation, services []*structs.Serv
 	}
 }
 
+// notify signals that the check results of the allocation have changed and
+// the service registrations held by the servers need updating.
+func (h *checksHook) notify() {
+	select {
+	case h.updateCh <- struct{}{}:
+	default:
+	}
+}
+
+// run sends updated check results to the servers each time a check status
+// changes, until ctx is cancelled.
+func (h *checksHook) run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-h.updateCh:
+			h.updateChecks()
+		}
+	}
+}
+
+// updateChecks sends the latest results of every check in the allocation to
+// the servers, grouped by the service registration each check belongs to.
+func (h *checksHook) updateChecks() {
+	h.lock.RLock()
+	results := h.shim.List(h.allocID)
+	updates := make(map[string][]*structs.ServiceRegistrationCheck)
+	for id, o := range h.observers {
+		if result, exists := results[id]; exists {
+			updates[o.serviceID] = append(updates[o.serviceID], result.ToServiceRegistrationCheck())
+		}
+	}
+	req := structs.ServiceRegistrationUpdateChecksRequest{
+		AllocID: h.allocID,
+		Checks:  updates,
+		WriteRequest: structs.WriteRequest{
+			Region:    h.alloc.Job.Region,
+			Namespace: h.alloc.Job.Namespace,
+			AuthToken: h.nodeSecret,
+		},
+	}
+	h.lock.RUnlock()
+
+	// sort the checks of each service, so the servers can detect updates
+	// which do not change anything
+	for _, serviceChecks := range updates {
+		sort.Slice(serviceChecks, func(i, j int) bool {
+			return serviceChecks[i].Name < serviceChecks[j].Name
+		})
+	}
+
+	var resp structs.ServiceRegistrationUpdateChecksResponse
+	if err := h.rpc.RPC(structs.ServiceRegistrationUpdateChecksRPCMethod, &req, &resp); err != nil {
+		h.logger.Error("failed to update service registration checks", "alloc_id", h.allocID, "error", err)
+	}
+}
+
 func (h *checksHook) Name() string {
 	return checksHookName
 }
@@ -208,6 +328,11 @@ func (h *checksHook) Prerun() error {
 	// create and start observers of nomad service checks in alloc
 	h.observe(h.alloc, group.NomadServices())
 
+	// start sending check results to the servers, if we are able to
+	if h.rpc != nil {
+		go h.run(h.ctx)
+	}
+
 	return nil
 }
 
@@ -253,6 +378,9 @@ func (h *checksHook) Update(request *interfaces.RunnerUpdateRequest) error {
 	// ensure we are observing new checks (idempotent)
 	h.observe(request.Alloc, services)
 
+	// the set of checks may have changed, so update the servers
+	h.notify()
+
 	return nil
 }
 
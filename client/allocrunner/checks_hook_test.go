@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -166,7 +167,7 @@ func TestCheckHook_Checks_ResultsSet(t *testing.T) {
 
 		alloc := allocWithNomadChecks(addr, port, tc.onGroup)
 
-		h := newChecksHook(logger, alloc, checkStore, network)
+		h := newChecksHook(logger, alloc, checkStore, network, nil, nil, "")
 
 		// initialize is called; observers are created but not started yet
 		must.MapEmpty(t, h.observers)
@@ -231,7 +232,7 @@ func TestCheckHook_Checks_UpdateSet(t *testing.T) {
 
 	alloc := allocWithNomadChecks(addr, port, true)
 
-	h := newChecksHook(logger, alloc, shim, network)
+	h := newChecksHook(logger, alloc, shim, network, nil, nil, "")
 
 	// calling pre-run starts the observers
 	err := h.Prerun()
@@ -310,3 +311,94 @@ func TestCheckHook_Checks_UpdateSet(t *testing.T) {
 	results := shim.List(alloc.ID)
 	must.MapEmpty(t, results)
 }
+
+// checksRPCer records the most recent check update sent to the servers.
+type checksRPCer struct {
+	lock sync.Mutex
+	last *structs.ServiceRegistrationUpdateChecksRequest
+}
+
+func (r *checksRPCer) RPC(method string, args interface{}, _ interface{}) error {
+	if method != structs.ServiceRegistrationUpdateChecksRPCMethod {
+		return fmt.Errorf("unexpected method %q", method)
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.last = args.(*structs.ServiceRegistrationUpdateChecksRequest)
+	return nil
+}
+
+func (r *checksRPCer) get() *structs.ServiceRegistrationUpdateChecksRequest {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.last
+}
+
+func TestCheckHook_Checks_UpdateServers(t *testing.T) {
+	ci.Parallel(t)
+
+	logger := testlog.HCLogger(t)
+
+	// create an http server with various responses
+	ts := httptest.NewServer(checkHandler)
+	defer ts.Close()
+
+	// get the address and port for http server
+	tokens := strings.Split(ts.URL, ":")
+	addr, port := strings.TrimPrefix(tokens[1], "//"), tokens[2]
+
+	shim := makeCheckStore(logger)
+
+	network := mock.NewNetworkStatus(addr)
+
+	alloc := allocWithNomadChecks(addr, port, true)
+
+	rpc := new(checksRPCer)
+
+	h := newChecksHook(logger, alloc, shim, network, nil, rpc, "secret")
+
+	// calling pre-run starts the observers and the updater
+	err := h.Prerun()
+	must.NoError(t, err)
+	defer h.PreKill()
+
+	serviceID := "_nomad-task-" + alloc.ID + "-web-service-one-" + port
+
+	testutil.WaitForResultUntil(
+		5*time.Second,
+		func() (bool, error) {
+			req := rpc.get()
+			if req == nil {
+				return false, fmt.Errorf("no check update sent")
+			}
+			results := req.Checks[serviceID]
+			if len(results) != 3 {
+				return false, fmt.Errorf("expected 3 check results, got %d", len(results))
+			}
+			passing, failing := 0, 0
+			for _, result := range results {
+				switch result.Status {
+				case structs.CheckSuccess:
+					passing++
+				case structs.CheckFailure:
+					failing++
+				}
+			}
+			if passing != 1 || failing != 2 {
+				return false, fmt.Errorf(
+					"expected 1 passing, 2 failing, got %d passing, %d failing",
+					passing, failing,
+				)
+			}
+			return true, nil
+		},
+		func(err error) {
+			t.Fatalf(err.Error())
+		},
+	)
+
+	req := rpc.get()
+	must.Eq(t, alloc.ID, req.AllocID)
+	must.Eq(t, "secret", req.AuthToken)
+	must.Eq(t, alloc.Job.Namespace, req.Namespace)
+}
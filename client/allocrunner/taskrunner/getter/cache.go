@@ -0,0 +1,331 @@
+package getter
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	gg "github.com/hashicorp/go-getter"
+	hclog "github.com/hashicorp/go-hclog"
+)
+
+const (
+	// cacheTmpPrefix is the prefix of the directories artifacts are
+	// downloaded to before being added to the cache.
+	cacheTmpPrefix = ".tmp-"
+
+	// cacheDataName is the name of the downloaded artifact within an entry.
+	cacheDataName = "data"
+)
+
+// Cache is a node-local cache of artifacts shared across allocations. Entries
+// are addressed by the namespace and the artifact's source, options and
+// checksum so only artifacts with a checksum are cached. Cached artifacts are
+// copied into the task directory, so tasks modifying their artifacts never
+// modify the cache. The least recently used entries are evicted once the
+// cache grows over its maximum size.
+type Cache struct {
+	dir      string
+	maxBytes int64
+	logger   hclog.Logger
+
+	lock    sync.Mutex
+	entries map[string]*cacheEntry
+	size    int64
+}
+
+// cacheEntry is an artifact in the cache.
+type cacheEntry struct {
+	key      string
+	size     int64
+	lastUsed time.Time
+
+	// refs is the number of artifacts being copied out of the entry. Entries
+	// with references are not evicted.
+	refs int
+}
+
+// NewCache returns a cache of artifacts stored in dir, loading any entries
+// left from a previous run. A nil cache is returned if maxBytes isn't
+// positive, which disables caching.
+func NewCache(logger hclog.Logger, dir string, maxBytes int64) (*Cache, error) {
+	if maxBytes <= 0 {
+		return nil, nil
+	}
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create artifact cache dir: %v", err)
+	}
+
+	c := &Cache{
+		dir:      dir,
+		maxBytes: maxBytes,
+		logger:   logger.Named("artifact_cache"),
+		entries:  make(map[string]*cacheEntry),
+	}
+
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list artifact cache dir: %v", err)
+	}
+	for _, fi := range files {
+		path := filepath.Join(dir, fi.Name())
+
+		// Remove downloads interrupted by a restart
+		if !fi.IsDir() || strings.HasPrefix(fi.Name(), cacheTmpPrefix) {
+			os.RemoveAll(path)
+			continue
+		}
+
+		size, err := diskUsage(path)
+		if err != nil {
+			c.logger.Warn("removing unreadable artifact cache entry", "key", fi.Name(), "error", err)
+			os.RemoveAll(path)
+			continue
+		}
+		c.entries[fi.Name()] = &cacheEntry{
+			key:      fi.Name(),
+			size:     size,
+			lastUsed: fi.ModTime(),
+		}
+		c.size += size
+	}
+
+	c.lock.Lock()
+	c.evictLocked()
+	c.lock.Unlock()
+
+	return c, nil
+}
+
+// cacheKey returns the key of an artifact of the namespace downloaded from the
+// go-getter URL, which includes the checksum, with the given mode. Entries
+// aren't shared across namespaces, so jobs can't get artifacts they couldn't
+// download themselves.
+func cacheKey(namespace, src string, mode gg.ClientMode) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%d\x00%s", namespace, mode, src)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// getOrFetch places the artifact with the given key at dst, calling fetch to
+// download it into the cache if it isn't cached already.
+func (c *Cache) getOrFetch(key, dst string, fetch func(dst string) error) error {
+	entry := c.acquire(key)
+	if entry == nil {
+		var err error
+		if entry, err = c.add(key, dst, fetch); err != nil || entry == nil {
+			return err
+		}
+	}
+	defer c.release(entry)
+
+	return copyTree(filepath.Join(c.dir, key, cacheDataName), dst)
+}
+
+// acquire returns the entry with the given key, marking it as used and
+// preventing it from being evicted until it's released. Nil is returned on
+// a cache miss.
+func (c *Cache) acquire(key string) *cacheEntry {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil
+	}
+	entry.refs++
+	entry.lastUsed = time.Now()
+
+	// Record the use so the eviction order survives a restart
+	path := filepath.Join(c.dir, key)
+	if err := os.Chtimes(path, entry.lastUsed, entry.lastUsed); err != nil {
+		c.logger.Debug("failed to update artifact cache entry time", "key", key, "error", err)
+	}
+	return entry
+}
+
+// release allows the entry to be evicted again, evicting entries if the cache
+// is over its maximum size.
+func (c *Cache) release(entry *cacheEntry) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	entry.refs--
+	c.evictLocked()
+}
+
+// add downloads an artifact and adds it to the cache, returning the acquired
+// entry. The artifact is downloaded to a temporary directory and renamed into
+// place, so concurrent downloads of the same artifact are safe. Artifacts
+// larger than the cache are placed at dst without being cached, in which case
+// no entry is returned.
+func (c *Cache) add(key, dst string, fetch func(dst string) error) (*cacheEntry, error) {
+	tmp, err := ioutil.TempDir(c.dir, cacheTmpPrefix)
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmp)
+
+	data := filepath.Join(tmp, cacheDataName)
+	if err := fetch(data); err != nil {
+		return nil, err
+	}
+	size, err := diskUsage(tmp)
+	if err != nil {
+		return nil, err
+	}
+	if size > c.maxBytes {
+		c.logger.Debug("artifact is too large to cache", "key", key, "size", size)
+		return nil, copyTree(data, dst)
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	// Another download of the artifact may have finished first
+	if entry, ok := c.entries[key]; ok {
+		entry.refs++
+		entry.lastUsed = time.Now()
+		return entry, nil
+	}
+
+	if err := os.Rename(tmp, filepath.Join(c.dir, key)); err != nil {
+		return nil, err
+	}
+	entry := &cacheEntry{
+		key:      key,
+		size:     size,
+		lastUsed: time.Now(),
+		refs:     1,
+	}
+	c.entries[key] = entry
+	c.size += size
+	return entry, nil
+}
+
+// EvictOldest removes the least recently used entry from the cache. It
+// returns false if there was no entry to remove.
+func (c *Cache) EvictOldest() bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	entries := c.evictableLocked()
+	if len(entries) == 0 {
+		return false
+	}
+	c.removeLocked(entries[0])
+	return true
+}
+
+// evictLocked removes the least recently used entries until the cache is
+// within its maximum size. Must be called with the lock held.
+func (c *Cache) evictLocked() {
+	for _, entry := range c.evictableLocked() {
+		if c.size <= c.maxBytes {
+			return
+		}
+		c.removeLocked(entry)
+	}
+}
+
+// evictableLocked returns the entries that aren't in use, least recently used
+// first. Must be called with the lock held.
+func (c *Cache) evictableLocked() []*cacheEntry {
+	entries := make([]*cacheEntry, 0, len(c.entries))
+	for _, entry := range c.entries {
+		if entry.refs == 0 {
+			entries = append(entries, entry)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].lastUsed.Before(entries[j].lastUsed)
+	})
+	return entries
+}
+
+// removeLocked deletes an entry. Artifacts copied into task directories are
+// unaffected. Must be called with the lock held.
+func (c *Cache) removeLocked(entry *cacheEntry) {
+	c.logger.Debug("evicting artifact", "key", entry.key, "size", entry.size)
+	if err := os.RemoveAll(filepath.Join(c.dir, entry.key)); err != nil {
+		c.logger.Warn("failed to remove artifact cache entry", "key", entry.key, "error", err)
+	}
+	delete(c.entries, entry.key)
+	c.size -= entry.size
+}
+
+// diskUsage returns the total size of the regular files under path.
+func diskUsage(path string) (int64, error) {
+	var size int64
+	err := filepath.Walk(path, func(_ string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if fi.Mode().IsRegular() {
+			size += fi.Size()
+		}
+		return nil
+	})
+	return size, err
+}
+
+// copyTree copies the file or directory tree at src to dst, merging into any
+// existing directories.
+func copyTree(src, dst string) error {
+	return filepath.Walk(src, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		switch {
+		case fi.IsDir():
+			return os.MkdirAll(target, fi.Mode().Perm())
+		case fi.Mode().IsRegular():
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+
+			// Replace existing files as a download would
+			if err := os.Remove(target); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			return copyFile(path, target, fi.Mode().Perm())
+		default:
+			return fmt.Errorf("unsupported file type in cached artifact: %s", rel)
+		}
+	})
+}
+
+// copyFile copies the file at src to dst with the given permissions.
+func copyFile(src, dst string, perm os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,256 @@
+package getter
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	clientconfig "github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/client/taskenv"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+	"github.com/stretchr/testify/require"
+)
+
+// testCachingGetter returns a getter with a cache of the given size, and the
+// directory of the cache.
+func testCachingGetter(t *testing.T, maxBytes int64) (*Getter, *Cache) {
+	getterConf, err := clientconfig.ArtifactConfigFromAgent(config.DefaultArtifactConfig())
+	require.NoError(t, err)
+
+	cache, err := NewCache(testlog.HCLogger(t), t.TempDir(), maxBytes)
+	require.NoError(t, err)
+	return NewGetter(testlog.HCLogger(t), getterConf, cache), cache
+}
+
+// countingFileServer serves the test fixtures, counting the requests.
+func countingFileServer(t *testing.T) (*httptest.Server, *int32) {
+	var requests int32
+	fs := http.FileServer(http.Dir("./test-fixtures/"))
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		fs.ServeHTTP(w, r)
+	}))
+	t.Cleanup(ts.Close)
+	return ts, &requests
+}
+
+func TestCache_GetArtifact(t *testing.T) {
+	ts, requests := countingFileServer(t)
+	getter, cache := testCachingGetter(t, 1024*1024)
+
+	artifact := &structs.TaskArtifact{
+		GetterSource: fmt.Sprintf("%s/test.sh", ts.URL),
+		GetterOptions: map[string]string{
+			"checksum": "md5:bce963762aa2dbfed13caf492a45fb72",
+		},
+	}
+
+	// Both tasks get the artifact but it's only downloaded once
+	taskDirs := []string{t.TempDir(), t.TempDir()}
+	for _, taskDir := range taskDirs {
+		require.NoError(t, getter.GetArtifact(noopTaskEnv(taskDir), artifact))
+	}
+	require.Equal(t, int32(1), atomic.LoadInt32(requests))
+	require.Len(t, cache.entries, 1)
+
+	// The artifact is copied from the cache, so a task modifying it doesn't
+	// modify the artifact of other tasks
+	require.NoError(t, ioutil.WriteFile(filepath.Join(taskDirs[0], "test.sh"), []byte("modified"), 0644))
+	checkContents(taskDirs[1], map[string]string{"test.sh": "sleep 1\n"}, t)
+
+	taskDir := t.TempDir()
+	require.NoError(t, getter.GetArtifact(noopTaskEnv(taskDir), artifact))
+	checkContents(taskDir, map[string]string{"test.sh": "sleep 1\n"}, t)
+	require.Equal(t, int32(1), atomic.LoadInt32(requests))
+}
+
+// namespaceReplacer is a task environment of a task in the namespace.
+type namespaceReplacer struct {
+	noopReplacer
+	namespace string
+}
+
+func (r namespaceReplacer) ReplaceEnv(s string) string {
+	return strings.ReplaceAll(s, "${"+taskenv.Namespace+"}", r.namespace)
+}
+
+func TestCache_GetArtifact_Namespace(t *testing.T) {
+	ts, requests := countingFileServer(t)
+	getter, cache := testCachingGetter(t, 1024*1024)
+
+	artifact := &structs.TaskArtifact{
+		GetterSource: fmt.Sprintf("%s/test.sh", ts.URL),
+		GetterOptions: map[string]string{
+			"checksum": "md5:bce963762aa2dbfed13caf492a45fb72",
+		},
+	}
+
+	// Entries aren't shared across namespaces
+	for _, namespace := range []string{"default", "other", "default"} {
+		taskEnv := namespaceReplacer{noopReplacer{taskDir: t.TempDir()}, namespace}
+		require.NoError(t, getter.GetArtifact(taskEnv, artifact))
+	}
+	require.Equal(t, int32(2), atomic.LoadInt32(requests))
+	require.Len(t, cache.entries, 2)
+}
+
+func TestCache_GetArtifact_Headers(t *testing.T) {
+	ts, requests := countingFileServer(t)
+	getter, cache := testCachingGetter(t, 1024*1024)
+
+	artifact := &structs.TaskArtifact{
+		GetterSource: fmt.Sprintf("%s/test.sh", ts.URL),
+		GetterOptions: map[string]string{
+			"checksum": "md5:bce963762aa2dbfed13caf492a45fb72",
+		},
+		GetterHeaders: map[string]string{
+			"Authorization": "Bearer secret",
+		},
+	}
+
+	// Artifacts downloaded with headers are never cached
+	for i := 0; i < 2; i++ {
+		require.NoError(t, getter.GetArtifact(noopTaskEnv(t.TempDir()), artifact))
+	}
+	require.Equal(t, int32(2), atomic.LoadInt32(requests))
+	require.Empty(t, cache.entries)
+}
+
+func TestCache_GetArtifact_Archive(t *testing.T) {
+	ts, requests := countingFileServer(t)
+	getter, _ := testCachingGetter(t, 1024*1024)
+
+	artifact := &structs.TaskArtifact{
+		GetterSource: fmt.Sprintf("%s/archive.tar.gz", ts.URL),
+		GetterOptions: map[string]string{
+			"checksum": "sha1:20bab73c72c56490856f913cf594bad9a4d730f6",
+		},
+	}
+
+	for i := 0; i < 2; i++ {
+		// Existing files are replaced as they are when downloading
+		taskDir := t.TempDir()
+		createContents(taskDir, map[string]string{
+			"exist/my.config": "to be replaced",
+			"untouched":       "existing top-level",
+		}, t)
+
+		require.NoError(t, getter.GetArtifact(noopTaskEnv(taskDir), artifact))
+		checkContents(taskDir, map[string]string{
+			"untouched":       "existing top-level",
+			"exist/my.config": "hello world\n",
+			"new/my.config":   "hello world\n",
+			"test.sh":         "sleep 1\n",
+		}, t)
+	}
+	require.Equal(t, int32(1), atomic.LoadInt32(requests))
+}
+
+func TestCache_GetArtifact_NoChecksum(t *testing.T) {
+	ts, requests := countingFileServer(t)
+	getter, cache := testCachingGetter(t, 1024*1024)
+
+	artifact := &structs.TaskArtifact{
+		GetterSource: fmt.Sprintf("%s/test.sh", ts.URL),
+	}
+
+	for i := 0; i < 2; i++ {
+		require.NoError(t, getter.GetArtifact(noopTaskEnv(t.TempDir()), artifact))
+	}
+	require.Equal(t, int32(2), atomic.LoadInt32(requests))
+	require.Empty(t, cache.entries)
+}
+
+func TestCache_GetArtifact_InvalidChecksum(t *testing.T) {
+	ts, _ := countingFileServer(t)
+	getter, cache := testCachingGetter(t, 1024*1024)
+
+	artifact := &structs.TaskArtifact{
+		GetterSource: fmt.Sprintf("%s/test.sh", ts.URL),
+		GetterOptions: map[string]string{
+			"checksum": "md5:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
+		},
+	}
+
+	require.Error(t, getter.GetArtifact(noopTaskEnv(t.TempDir()), artifact))
+	require.Empty(t, cache.entries)
+
+	// The failed download isn't left behind
+	files, err := ioutil.ReadDir(cache.dir)
+	require.NoError(t, err)
+	require.Empty(t, files)
+}
+
+// testFetch returns a fetch function writing a file of the given size.
+func testFetch(size int) func(string) error {
+	return func(dst string) error {
+		if err := os.MkdirAll(dst, 0755); err != nil {
+			return err
+		}
+		return ioutil.WriteFile(filepath.Join(dst, "artifact"), make([]byte, size), 0644)
+	}
+}
+
+func TestCache_Evict(t *testing.T) {
+	cache, err := NewCache(testlog.HCLogger(t), t.TempDir(), 250)
+	require.NoError(t, err)
+
+	for _, key := range []string{"a", "b"} {
+		require.NoError(t, cache.getOrFetch(key, t.TempDir(), testFetch(100)))
+	}
+
+	// Using a makes b the least recently used entry
+	require.NoError(t, cache.getOrFetch("a", t.TempDir(), func(string) error {
+		return fmt.Errorf("should be cached")
+	}))
+
+	// Adding c goes over the size limit and evicts b
+	require.NoError(t, cache.getOrFetch("c", t.TempDir(), testFetch(100)))
+	require.Contains(t, cache.entries, "a")
+	require.NotContains(t, cache.entries, "b")
+	require.Contains(t, cache.entries, "c")
+	require.Equal(t, int64(200), cache.size)
+	require.NoDirExists(t, filepath.Join(cache.dir, "b"))
+
+	// Artifacts larger than the cache are not kept
+	dst := t.TempDir()
+	require.NoError(t, cache.getOrFetch("d", dst, testFetch(300)))
+	require.FileExists(t, filepath.Join(dst, "artifact"))
+	require.NotContains(t, cache.entries, "d")
+
+	// The garbage collector evicts the least recently used entry
+	require.True(t, cache.EvictOldest())
+	require.NotContains(t, cache.entries, "a")
+	require.True(t, cache.EvictOldest())
+	require.False(t, cache.EvictOldest())
+	require.Zero(t, cache.size)
+}
+
+func TestNewCache_Restore(t *testing.T) {
+	dir := t.TempDir()
+	cache, err := NewCache(testlog.HCLogger(t), dir, 1024)
+	require.NoError(t, err)
+	require.NoError(t, cache.getOrFetch("a", t.TempDir(), testFetch(100)))
+
+	// Interrupted downloads are removed
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, cacheTmpPrefix+"1"), 0755))
+
+	cache, err = NewCache(testlog.HCLogger(t), dir, 1024)
+	require.NoError(t, err)
+	require.Contains(t, cache.entries, "a")
+	require.Equal(t, int64(100), cache.size)
+	require.NoDirExists(t, filepath.Join(dir, cacheTmpPrefix+"1"))
+
+	// A cache of zero size is disabled
+	cache, err = NewCache(testlog.HCLogger(t), dir, 0)
+	require.NoError(t, err)
+	require.Nil(t, cache)
+}
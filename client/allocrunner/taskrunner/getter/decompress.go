@@ -0,0 +1,226 @@
+package getter
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/bzip2"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"math"
+	"os"
+
+	gg "github.com/hashicorp/go-getter"
+	"github.com/klauspost/compress/zstd"
+	"github.com/ulikunitz/xz"
+)
+
+// decompressionLimits limit the files decompressed from an artifact. Zero
+// values are unlimited.
+type decompressionLimits struct {
+	size  int64
+	files int
+}
+
+// check returns an error if the total size or number of files decompressed
+// so far is over the limits.
+func (l decompressionLimits) check(size int64, files int) error {
+	if l.size > 0 && size > l.size {
+		return fmt.Errorf("artifact exceeds decompression size limit of %d bytes", l.size)
+	}
+	if l.files > 0 && files > l.files {
+		return fmt.Errorf("artifact exceeds decompression file count limit of %d files", l.files)
+	}
+	return nil
+}
+
+// archiveScanner reads an archive to check it's within the limits without
+// writing any of its files.
+type archiveScanner func(path string, limits decompressionLimits) error
+
+// archiveScanners are the scanners for each of the extensions supported by
+// go-getter.
+var archiveScanners = map[string]archiveScanner{
+	"bz2":     scanCompressed(openBzip2),
+	"gz":      scanCompressed(openGzip),
+	"xz":      scanCompressed(openXz),
+	"zst":     scanCompressed(openZstd),
+	"tar":     scanTar(openTar),
+	"tar.bz2": scanTar(openBzip2),
+	"tar.gz":  scanTar(openGzip),
+	"tar.xz":  scanTar(openXz),
+	"tar.zst": scanTar(openZstd),
+	"tbz2":    scanTar(openBzip2),
+	"tgz":     scanTar(openGzip),
+	"txz":     scanTar(openXz),
+	"tzst":    scanTar(openZstd),
+	"zip":     scanZip,
+}
+
+// limitedDecompressor scans an archive before decompressing it with the
+// wrapped decompressor, so archives over the limits are rejected before
+// anything is written.
+type limitedDecompressor struct {
+	gg.Decompressor
+	scan   archiveScanner
+	limits decompressionLimits
+}
+
+func (d *limitedDecompressor) Decompress(dst, src string, dir bool, umask os.FileMode) error {
+	if err := d.scan(src, d.limits); err != nil {
+		return err
+	}
+	return d.Decompressor.Decompress(dst, src, dir, umask)
+}
+
+// decompressors returns the go-getter decompressors enforcing the limits.
+func decompressors(limits decompressionLimits) map[string]gg.Decompressor {
+	if limits.size <= 0 && limits.files <= 0 {
+		return gg.Decompressors
+	}
+
+	m := make(map[string]gg.Decompressor, len(gg.Decompressors))
+	for ext, d := range gg.Decompressors {
+		scan, ok := archiveScanners[ext]
+		if !ok {
+			// Formats that can't be checked are not decompressed, rather
+			// than decompressed without limits
+			continue
+		}
+		m[ext] = &limitedDecompressor{
+			Decompressor: d,
+			scan:         scan,
+			limits:       limits,
+		}
+	}
+	return m
+}
+
+// opener returns a reader of the decompressed contents of r.
+type opener func(r io.Reader) (io.ReadCloser, error)
+
+func openTar(r io.Reader) (io.ReadCloser, error) {
+	return io.NopCloser(r), nil
+}
+
+func openBzip2(r io.Reader) (io.ReadCloser, error) {
+	return io.NopCloser(bzip2.NewReader(r)), nil
+}
+
+func openGzip(r io.Reader) (io.ReadCloser, error) {
+	return gzip.NewReader(r)
+}
+
+func openXz(r io.Reader) (io.ReadCloser, error) {
+	xzr, err := xz.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	return io.NopCloser(xzr), nil
+}
+
+func openZstd(r io.Reader) (io.ReadCloser, error) {
+	zr, err := zstd.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	return zr.IOReadCloser(), nil
+}
+
+// openArchive opens the file at path with the opener.
+func openArchive(path string, open opener) (io.ReadCloser, func(), error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	r, err := open(f)
+	if err != nil {
+		f.Close()
+		return nil, nil, err
+	}
+	return r, func() {
+		r.Close()
+		f.Close()
+	}, nil
+}
+
+// scanCompressed returns a scanner of a single compressed file, which is
+// decompressed up to the size limit.
+func scanCompressed(open opener) archiveScanner {
+	return func(path string, limits decompressionLimits) error {
+		if err := limits.check(0, 1); err != nil {
+			return err
+		}
+		if limits.size <= 0 {
+			return nil
+		}
+
+		r, closer, err := openArchive(path, open)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		n, err := io.Copy(io.Discard, io.LimitReader(r, limits.size+1))
+		if err != nil {
+			return err
+		}
+		return limits.check(n, 1)
+	}
+}
+
+// scanTar returns a scanner of a tar archive, summing the sizes in the
+// headers of its entries. The tar reader skips the contents of each entry
+// without buffering them, and won't read more than the size in the header.
+func scanTar(open opener) archiveScanner {
+	return func(path string, limits decompressionLimits) error {
+		r, closer, err := openArchive(path, open)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		var size int64
+		var files int
+		tr := tar.NewReader(r)
+		for {
+			hdr, err := tr.Next()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+
+			files++
+			size += hdr.Size
+			if err := limits.check(size, files); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// scanZip checks the sizes in the central directory of a zip archive. The
+// zip reader errors if an entry is larger than its recorded size.
+func scanZip(path string, limits decompressionLimits) error {
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return err
+	}
+	defer zr.Close()
+
+	var size int64
+	for i, f := range zr.File {
+		// Saturate rather than overflow on sizes no archive could hold
+		if f.UncompressedSize64 > uint64(math.MaxInt64-size) {
+			size = math.MaxInt64
+		} else {
+			size += int64(f.UncompressedSize64)
+		}
+		if err := limits.check(size, i+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package getter
+
+import (
+	"archive/zip"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	gg "github.com/hashicorp/go-getter"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecompress_scanTar(t *testing.T) {
+	scan := archiveScanners["tar.gz"]
+	path := "./test-fixtures/archive.tar.gz"
+
+	// The archive has 5 entries holding 32 bytes
+	require.NoError(t, scan(path, decompressionLimits{}))
+	require.NoError(t, scan(path, decompressionLimits{size: 32, files: 5}))
+
+	err := scan(path, decompressionLimits{size: 31})
+	require.EqualError(t, err, "artifact exceeds decompression size limit of 31 bytes")
+
+	err = scan(path, decompressionLimits{files: 4})
+	require.EqualError(t, err, "artifact exceeds decompression file count limit of 4 files")
+}
+
+func TestDecompress_scanZip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "archive.zip")
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	zw := zip.NewWriter(f)
+	for _, name := range []string{"a", "b", "c"} {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write(make([]byte, 100))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	require.NoError(t, f.Close())
+
+	require.NoError(t, scanZip(path, decompressionLimits{size: 300, files: 3}))
+	require.Error(t, scanZip(path, decompressionLimits{size: 299}))
+	require.Error(t, scanZip(path, decompressionLimits{files: 2}))
+}
+
+func TestDecompress_scanCompressed(t *testing.T) {
+	// Highly compressible files are only decompressed up to the limit
+	path := filepath.Join(t.TempDir(), "file.gz")
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	gw := gzip.NewWriter(f)
+	_, err = gw.Write(make([]byte, 1024*1024))
+	require.NoError(t, err)
+	require.NoError(t, gw.Close())
+	require.NoError(t, f.Close())
+
+	scan := archiveScanners["gz"]
+	require.NoError(t, scan(path, decompressionLimits{size: 1024 * 1024}))
+	require.EqualError(t, scan(path, decompressionLimits{size: 1024}),
+		"artifact exceeds decompression size limit of 1024 bytes")
+}
+
+func TestDecompress_decompressors(t *testing.T) {
+	// Without limits the go-getter decompressors are used
+	require.Equal(t, gg.Decompressors, decompressors(decompressionLimits{}))
+
+	for ext, d := range decompressors(decompressionLimits{files: 1}) {
+		require.IsType(t, &limitedDecompressor{}, d, ext)
+	}
+}
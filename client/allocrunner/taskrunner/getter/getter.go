@@ -5,13 +5,18 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"path/filepath"
 	"strings"
+	"sync"
 
 	"github.com/hashicorp/go-cleanhttp"
 	gg "github.com/hashicorp/go-getter"
+	hclog "github.com/hashicorp/go-hclog"
 
 	"github.com/hashicorp/nomad/client/config"
 	"github.com/hashicorp/nomad/client/interfaces"
+	"github.com/hashicorp/nomad/client/taskenv"
+	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/nomad/structs"
 )
 
@@ -22,23 +27,41 @@ const (
 
 // Getter wraps go-getter calls in an artifact configuration.
 type Getter struct {
+	logger hclog.Logger
+
 	// httpClient is a shared HTTP client for use across all http/https
 	// Getter instantiations. The HTTP client is designed to be
 	// thread-safe, and using a pooled transport will help reduce excessive
 	// connections when clients are downloading lots of artifacts.
 	httpClient *http.Client
 	config     *config.ArtifactConfig
+
+	// cache holds artifacts with a checksum so they're only downloaded once
+	// per node. It's nil if caching is disabled.
+	cache *Cache
+
+	// cgroupWarning ensures failing to limit the sandbox's resources is only
+	// logged once.
+	cgroupWarning sync.Once
 }
 
 // NewGetter returns a new Getter instance. This function is called once per
-// client and shared across alloc and task runners.
-func NewGetter(config *config.ArtifactConfig) *Getter {
-	return &Getter{
+// client and shared across alloc and task runners. The cache may be nil.
+func NewGetter(logger hclog.Logger, config *config.ArtifactConfig, cache *Cache) *Getter {
+	g := &Getter{
+		logger: logger.Named("artifact_getter"),
 		httpClient: &http.Client{
 			Transport: cleanhttp.DefaultPooledTransport(),
 		},
 		config: config,
+		cache:  cache,
 	}
+
+	sandboxed := config != nil && !config.DisableSandbox
+	if sandboxed && !config.DisableFilesystemIsolation && !filesystemIsolationSupported() {
+		g.logger.Warn("filesystem isolation of artifact downloads is not supported on this system")
+	}
+	return g
 }
 
 // GetArtifact downloads an artifact into the specified task directory.
@@ -48,6 +71,14 @@ func (g *Getter) GetArtifact(taskEnv interfaces.EnvReplacer, artifact *structs.T
 		return newGetError(artifact.GetterSource, err, false)
 	}
 
+	// The sandbox may write to the task directory and the shared alloc
+	// directory, as destinations may be in either
+	taskDir, _ := taskEnv.ClientPath(".", false)
+	dirs := []string{taskDir}
+	allocDir, _ := taskEnv.ClientPath("${"+taskenv.AllocDir+"}", false)
+	if helper.PathEscapesSandbox(taskDir, allocDir) {
+		dirs = append(dirs, allocDir)
+	}
 	dest, escapes := taskEnv.ClientPath(artifact.RelativeDest, true)
 	// Verify the destination is still in the task sandbox after interpolation
 	if escapes {
@@ -66,13 +97,44 @@ func (g *Getter) GetArtifact(taskEnv interfaces.EnvReplacer, artifact *structs.T
 	}
 
 	headers := getHeaders(taskEnv, artifact.GetterHeaders)
-	if err := g.getClient(ggURL, headers, mode, dest).Get(); err != nil {
+
+	// Only artifacts with a checksum are cached, as the checksum ensures
+	// the cached artifact is the one that would be downloaded. Artifacts
+	// with headers aren't cached, as the headers may hold credentials that
+	// other jobs don't have. They're downloaded to a directory within the
+	// cache, which the sandbox is confined to instead of the task's
+	// directories.
+	if g.cache != nil && artifact.GetterOptions["checksum"] != "" && len(headers) == 0 {
+		namespace := taskEnv.ReplaceEnv("${" + taskenv.Namespace + "}")
+		err = g.cache.getOrFetch(cacheKey(namespace, ggURL, mode), dest, func(dst string) error {
+			return g.get([]string{filepath.Dir(dst)}, ggURL, headers, mode, dst)
+		})
+	} else {
+		err = g.get(dirs, ggURL, headers, mode, dest)
+	}
+	if err != nil {
 		return newGetError(ggURL, err, true)
 	}
 
 	return nil
 }
 
+// get downloads the artifact to dst, in a sandboxed process confined to dirs
+// unless the sandbox is disabled.
+func (g *Getter) get(dirs []string, src string, headers http.Header, mode gg.ClientMode, dst string) error {
+	if g.config.DisableSandbox {
+		return g.getClient(src, headers, mode, dst).Get()
+	}
+
+	return g.sandboxGet(dirs, &parameters{
+		Config:      g.config,
+		Source:      src,
+		Destination: dst,
+		Mode:        mode,
+		Headers:     headers,
+	})
+}
+
 // getClient returns a client that is suitable for Nomad downloading artifacts.
 func (g *Getter) getClient(src string, headers http.Header, mode gg.ClientMode, dst string) *gg.Client {
 	return &gg.Client{
@@ -82,6 +144,12 @@ func (g *Getter) getClient(src string, headers http.Header, mode gg.ClientMode,
 		Umask:   060000000,
 		Getters: g.createGetters(headers),
 
+		// Archives over the limits are rejected before being decompressed
+		Decompressors: decompressors(decompressionLimits{
+			size:  g.config.DecompressionSizeLimit,
+			files: g.config.DecompressionFileCountLimit,
+		}),
+
 		// This will prevent copying or writing files through symlinks
 		DisableSymlinks: true,
 	}
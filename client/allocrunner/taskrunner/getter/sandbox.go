@@ -0,0 +1,134 @@
+package getter
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+
+	gg "github.com/hashicorp/go-getter"
+	hclog "github.com/hashicorp/go-hclog"
+
+	"github.com/hashicorp/nomad/client/config"
+)
+
+const (
+	// sandboxCommand is the hidden command the sandboxed process is run
+	// with.
+	sandboxCommand = "artifact-isolation"
+
+	// sandboxIsolatedEnv is set once the sandboxed process has confined
+	// itself to its directories and executed itself again.
+	sandboxIsolatedEnv = "NOMAD_ARTIFACT_ISOLATED"
+
+	// sandboxTmpPrefix is the prefix of the temporary directory created for
+	// the sandboxed process.
+	sandboxTmpPrefix = ".artifact-tmp-"
+)
+
+var bin = getBin()
+
+func getBin() string {
+	b, err := os.Executable()
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// parameters are the download passed to the sandboxed process on stdin.
+type parameters struct {
+	Config      *config.ArtifactConfig
+	Source      string
+	Destination string
+	Mode        gg.ClientMode
+	Headers     http.Header
+}
+
+// sandboxGet downloads an artifact by running the Nomad binary as a separate
+// process. The process is confined to writing within dirs, which the
+// destination must be within, and limited to the CPU and memory set in the
+// artifact config.
+func (g *Getter) sandboxGet(dirs []string, params *parameters) error {
+	// go-getter downloads archives and git SSH keys to the temporary
+	// directory, so it's created within the first directory the process may
+	// write
+	tmp, err := ioutil.TempDir(dirs[0], sandboxTmpPrefix)
+	if err != nil {
+		return fmt.Errorf("failed to create artifact sandbox temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	args := []string{sandboxCommand}
+	if !g.config.DisableFilesystemIsolation {
+		args = append(args, dirs...)
+	}
+
+	var stderr bytes.Buffer
+	cmd := exec.Command(bin, args...)
+	cmd.Env = append(os.Environ(), "TMPDIR="+tmp)
+	cmd.Stderr = &stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start artifact sandbox: %v", err)
+	}
+
+	// The process waits for the parameters before downloading anything, so
+	// it's limited before it starts
+	cgroup, err := newSandboxCgroup(cmd.Process.Pid, g.config.SandboxCPULimit, g.config.SandboxMemoryLimit)
+	if err != nil {
+		g.cgroupWarning.Do(func() {
+			g.logger.Warn("failed to limit artifact sandbox resources", "error", err)
+		})
+	}
+	defer cgroup.destroy()
+
+	err = json.NewEncoder(stdin).Encode(params)
+	stdin.Close()
+	if werr := cmd.Wait(); werr != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return errors.New(msg)
+		}
+		return fmt.Errorf("artifact sandbox failed: %v", werr)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to send parameters to artifact sandbox: %v", err)
+	}
+	return nil
+}
+
+// runSandbox is the entry point of the sandboxed process, which confines
+// itself to dirs before downloading the artifact described by the parameters
+// on stdin. It returns the exit code of the process, writing any error to
+// stderr.
+func runSandbox(dirs []string) int {
+	if len(dirs) > 0 && os.Getenv(sandboxIsolatedEnv) == "" {
+		// isolate only returns if filesystem isolation isn't supported,
+		// which the client warns of when it starts
+		if err := isolate(dirs); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to isolate artifact sandbox: %v", err)
+			return 1
+		}
+	}
+
+	var params parameters
+	if err := json.NewDecoder(os.Stdin).Decode(&params); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read artifact sandbox parameters: %v", err)
+		return 1
+	}
+
+	g := NewGetter(hclog.NewNullLogger(), params.Config, nil)
+	if err := g.getClient(params.Source, params.Headers, params.Mode, params.Destination).Get(); err != nil {
+		fmt.Fprint(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
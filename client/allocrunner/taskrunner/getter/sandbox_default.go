@@ -0,0 +1,24 @@
+//go:build !linux
+
+package getter
+
+// filesystemIsolationSupported returns false as filesystem isolation is only
+// supported on Linux.
+func filesystemIsolationSupported() bool {
+	return false
+}
+
+// isolate does nothing on non-Linux systems.
+func isolate([]string) error {
+	return nil
+}
+
+// sandboxCgroup does nothing on non-Linux systems.
+type sandboxCgroup struct{}
+
+// newSandboxCgroup does nothing on non-Linux systems.
+func newSandboxCgroup(int, int, int64) (*sandboxCgroup, error) {
+	return nil, nil
+}
+
+func (*sandboxCgroup) destroy() {}
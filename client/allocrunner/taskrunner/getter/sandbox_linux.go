@@ -0,0 +1,254 @@
+//go:build linux
+
+package getter
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"syscall"
+	"unsafe"
+
+	"github.com/hashicorp/nomad/client/lib/cgutil"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/opencontainers/runc/libcontainer/cgroups"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// landlockFileAccess are the accesses that apply to files rather than
+	// directories.
+	landlockFileAccess = unix.LANDLOCK_ACCESS_FS_EXECUTE |
+		unix.LANDLOCK_ACCESS_FS_WRITE_FILE |
+		unix.LANDLOCK_ACCESS_FS_READ_FILE
+
+	// landlockReadAccess allows reading and executing files.
+	landlockReadAccess = unix.LANDLOCK_ACCESS_FS_EXECUTE |
+		unix.LANDLOCK_ACCESS_FS_READ_FILE |
+		unix.LANDLOCK_ACCESS_FS_READ_DIR
+
+	// landlockAllAccess are all the accesses handled by the first version
+	// of landlock, which are denied outside the allowed paths.
+	landlockAllAccess = landlockReadAccess |
+		unix.LANDLOCK_ACCESS_FS_WRITE_FILE |
+		unix.LANDLOCK_ACCESS_FS_REMOVE_DIR |
+		unix.LANDLOCK_ACCESS_FS_REMOVE_FILE |
+		unix.LANDLOCK_ACCESS_FS_MAKE_CHAR |
+		unix.LANDLOCK_ACCESS_FS_MAKE_DIR |
+		unix.LANDLOCK_ACCESS_FS_MAKE_REG |
+		unix.LANDLOCK_ACCESS_FS_MAKE_SOCK |
+		unix.LANDLOCK_ACCESS_FS_MAKE_FIFO |
+		unix.LANDLOCK_ACCESS_FS_MAKE_BLOCK |
+		unix.LANDLOCK_ACCESS_FS_MAKE_SYM
+)
+
+// sandboxReadPaths are the system paths the sandbox may read, for the git
+// and hg binaries, their libraries, and DNS and TLS configuration.
+var sandboxReadPaths = []string{
+	"/bin",
+	"/etc",
+	"/lib",
+	"/lib32",
+	"/lib64",
+	"/run/systemd/resolve",
+	"/sbin",
+	"/usr",
+}
+
+// filesystemIsolationSupported returns whether the kernel supports landlock.
+func filesystemIsolationSupported() bool {
+	abi, _, errno := unix.Syscall(unix.SYS_LANDLOCK_CREATE_RULESET, 0, 0, unix.LANDLOCK_CREATE_RULESET_VERSION)
+	return errno == 0 && abi >= 1
+}
+
+// isolate confines the process to writing within dirs and reading the system
+// paths it needs. Landlock only confines the calling thread and the processes
+// it executes, so the process executes itself again from the confined thread
+// for every thread to be confined. isolate returns nil without confining the
+// process if landlock isn't supported.
+func isolate(dirs []string) error {
+	if !filesystemIsolationSupported() {
+		return nil
+	}
+
+	runtime.LockOSThread()
+	if err := lockdown(dirs); err != nil {
+		return err
+	}
+	env := append(os.Environ(), sandboxIsolatedEnv+"=1")
+	return syscall.Exec(bin, os.Args, env)
+}
+
+// lockdown applies a landlock ruleset to the calling thread.
+func lockdown(dirs []string) error {
+	attr := unix.LandlockRulesetAttr{Access_fs: landlockAllAccess}
+	fd, _, errno := unix.Syscall(unix.SYS_LANDLOCK_CREATE_RULESET,
+		uintptr(unsafe.Pointer(&attr)), unsafe.Sizeof(attr), 0)
+	if errno != 0 {
+		return fmt.Errorf("failed to create landlock ruleset: %v", errno)
+	}
+	ruleset := int(fd)
+	defer unix.Close(ruleset)
+
+	paths := map[string]uint64{
+		bin:         landlockReadAccess,
+		"/dev/null": landlockFileAccess,
+	}
+	for _, path := range sandboxReadPaths {
+		paths[path] = landlockReadAccess
+	}
+	// The home directory holds the .netrc file and git's SSH configuration
+	if home, err := os.UserHomeDir(); err == nil {
+		paths[home] = landlockReadAccess
+	}
+	for _, dir := range dirs {
+		paths[dir] = landlockAllAccess
+	}
+
+	for path, access := range paths {
+		if err := landlockAllow(ruleset, path, access); err != nil {
+			return err
+		}
+	}
+
+	// Required to restrict the thread without CAP_SYS_ADMIN
+	if err := unix.Prctl(unix.PR_SET_NO_NEW_PRIVS, 1, 0, 0, 0); err != nil {
+		return fmt.Errorf("failed to set no_new_privs: %v", err)
+	}
+	if _, _, errno := unix.Syscall(unix.SYS_LANDLOCK_RESTRICT_SELF, uintptr(ruleset), 0, 0); errno != 0 {
+		return fmt.Errorf("failed to apply landlock ruleset: %v", errno)
+	}
+	return nil
+}
+
+// landlockAllow adds a rule allowing the access to everything beneath path.
+// Paths that don't exist are skipped.
+func landlockAllow(ruleset int, path string, access uint64) error {
+	fd, err := unix.Open(path, unix.O_PATH|unix.O_CLOEXEC, 0)
+	if errors.Is(err, unix.ENOENT) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to open %q: %v", path, err)
+	}
+	defer unix.Close(fd)
+
+	var stat unix.Stat_t
+	if err := unix.Fstat(fd, &stat); err != nil {
+		return fmt.Errorf("failed to stat %q: %v", path, err)
+	}
+	if stat.Mode&unix.S_IFMT != unix.S_IFDIR {
+		access &= landlockFileAccess
+	}
+
+	attr := unix.LandlockPathBeneathAttr{
+		Allowed_access: access,
+		Parent_fd:      int32(fd),
+	}
+	_, _, errno := unix.Syscall6(unix.SYS_LANDLOCK_ADD_RULE, uintptr(ruleset),
+		unix.LANDLOCK_RULE_PATH_BENEATH, uintptr(unsafe.Pointer(&attr)), 0, 0, 0)
+	if errno != 0 {
+		return fmt.Errorf("failed to add landlock rule for %q: %v", path, errno)
+	}
+	return nil
+}
+
+// sandboxCgroup is the cgroup limiting the resources of a sandboxed process.
+// With cgroups v1 there's a cgroup for each limited controller.
+type sandboxCgroup struct {
+	paths []string
+}
+
+// newSandboxCgroup creates a cgroup with the CPU limit, as a percentage of
+// one core, and memory limit in bytes, and adds the process to it. No cgroup
+// is created if neither resource is limited.
+func newSandboxCgroup(pid int, cpuLimit int, memoryLimit int64) (*sandboxCgroup, error) {
+	if cpuLimit <= 0 && memoryLimit <= 0 {
+		return nil, nil
+	}
+
+	// Limits are applied per period of CPU time
+	const period = 100000
+	quota := strconv.Itoa(cpuLimit * period / 100)
+
+	name := "artifact-" + uuid.Short()
+	cg := new(sandboxCgroup)
+	if cgutil.UseV2 {
+		parent := filepath.Join(cgutil.CgroupRoot, cgutil.GetCgroupParent(""))
+		if err := os.MkdirAll(parent, 0755); err != nil {
+			return nil, err
+		}
+		if err := cgroups.WriteFile(parent, "cgroup.subtree_control", "+cpu +memory"); err != nil {
+			return nil, err
+		}
+
+		path := filepath.Join(parent, name+".scope")
+		files := make(map[string]string)
+		if cpuLimit > 0 {
+			files["cpu.max"] = fmt.Sprintf("%s %d", quota, period)
+		}
+		if memoryLimit > 0 {
+			files["memory.max"] = strconv.FormatInt(memoryLimit, 10)
+		}
+		if err := cg.add(path, files, pid); err != nil {
+			cg.destroy()
+			return nil, err
+		}
+		return cg, nil
+	}
+
+	subsystems := make(map[string]map[string]string)
+	if cpuLimit > 0 {
+		subsystems["cpu"] = map[string]string{
+			"cpu.cfs_period_us": strconv.Itoa(period),
+			"cpu.cfs_quota_us":  quota,
+		}
+	}
+	if memoryLimit > 0 {
+		subsystems["memory"] = map[string]string{
+			"memory.limit_in_bytes": strconv.FormatInt(memoryLimit, 10),
+		}
+	}
+	for subsystem, files := range subsystems {
+		path, err := cgutil.GetCgroupPathHelperV1(subsystem, filepath.Join(cgutil.DefaultCgroupV1Parent, name))
+		if err == nil {
+			err = cg.add(path, files, pid)
+		}
+		if err != nil {
+			cg.destroy()
+			return nil, err
+		}
+	}
+	return cg, nil
+}
+
+// add creates the cgroup at path, writes the files limiting it and adds the
+// process to it.
+func (c *sandboxCgroup) add(path string, files map[string]string, pid int) error {
+	if err := os.MkdirAll(path, 0755); err != nil {
+		return err
+	}
+	c.paths = append(c.paths, path)
+
+	// The period must be written before the quota
+	for _, file := range []string{"cpu.cfs_period_us", "cpu.cfs_quota_us", "cpu.max", "memory.limit_in_bytes", "memory.max"} {
+		if data, ok := files[file]; ok {
+			if err := cgroups.WriteFile(path, file, data); err != nil {
+				return err
+			}
+		}
+	}
+	return cgroups.WriteFile(path, "cgroup.procs", strconv.Itoa(pid))
+}
+
+// destroy removes the cgroup once the process has exited.
+func (c *sandboxCgroup) destroy() {
+	if c == nil {
+		return
+	}
+	for _, path := range c.paths {
+		os.Remove(path)
+	}
+}
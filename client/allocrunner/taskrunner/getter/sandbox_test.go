@@ -0,0 +1,91 @@
+package getter
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	gg "github.com/hashicorp/go-getter"
+	"github.com/hashicorp/nomad/client/taskenv"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetter_Sandbox_DecompressionLimits(t *testing.T) {
+	ts := httptest.NewServer(http.FileServer(http.Dir("./test-fixtures/")))
+	defer ts.Close()
+
+	getter := TestDefaultGetter(t)
+	getter.config.DecompressionFileCountLimit = 2
+
+	artifact := &structs.TaskArtifact{
+		GetterSource: fmt.Sprintf("%s/archive.tar.gz", ts.URL),
+	}
+	taskDir := t.TempDir()
+	err := getter.GetArtifact(noopTaskEnv(taskDir), artifact)
+	require.EqualError(t, err, "artifact exceeds decompression file count limit of 2 files")
+	require.NoFileExists(t, filepath.Join(taskDir, "test.sh"))
+}
+
+func TestGetter_Sandbox_Isolation(t *testing.T) {
+	if !filesystemIsolationSupported() {
+		t.Skip("filesystem isolation is not supported")
+	}
+
+	ts := httptest.NewServer(http.FileServer(http.Dir("./test-fixtures/")))
+	defer ts.Close()
+
+	getter := TestDefaultGetter(t)
+	params := func(dst string) *parameters {
+		return &parameters{
+			Config:      getter.config,
+			Source:      fmt.Sprintf("%s/test.sh", ts.URL),
+			Destination: dst,
+			Mode:        gg.ClientModeFile,
+		}
+	}
+
+	// The sandbox may write within its root
+	root := t.TempDir()
+	require.NoError(t, getter.sandboxGet([]string{root}, params(filepath.Join(root, "test.sh"))))
+	require.FileExists(t, filepath.Join(root, "test.sh"))
+
+	// But nowhere else
+	outside := t.TempDir()
+	require.Error(t, getter.sandboxGet([]string{root}, params(filepath.Join(outside, "test.sh"))))
+	require.NoFileExists(t, filepath.Join(outside, "test.sh"))
+
+	// Unless filesystem isolation is disabled
+	getter.config.DisableFilesystemIsolation = true
+	require.NoError(t, getter.sandboxGet([]string{root}, params(filepath.Join(outside, "test.sh"))))
+	require.FileExists(t, filepath.Join(outside, "test.sh"))
+}
+
+func TestGetter_Sandbox_AllocDir(t *testing.T) {
+	ts := httptest.NewServer(http.FileServer(http.Dir("./test-fixtures/")))
+	defer ts.Close()
+
+	// Destinations in the shared alloc dir are within the sandbox, whether
+	// or not filesystem isolation is supported
+	root := t.TempDir()
+	taskDir := filepath.Join(root, "web")
+	allocDir := filepath.Join(root, "alloc")
+	require.NoError(t, os.Mkdir(taskDir, 0755))
+	require.NoError(t, os.Mkdir(allocDir, 0755))
+	envClient := map[string]string{taskenv.AllocDir: allocDir}
+	taskEnv := taskenv.NewTaskEnv(nil, envClient, nil, nil, taskDir, allocDir)
+
+	getter := TestDefaultGetter(t)
+	for _, dest := range []string{"${NOMAD_ALLOC_DIR}/data", "../alloc/other"} {
+		artifact := &structs.TaskArtifact{
+			GetterSource: fmt.Sprintf("%s/test.sh", ts.URL),
+			RelativeDest: dest,
+		}
+		require.NoError(t, getter.GetArtifact(taskEnv, artifact), dest)
+	}
+	require.FileExists(t, filepath.Join(allocDir, "data", "test.sh"))
+	require.FileExists(t, filepath.Join(allocDir, "other", "test.sh"))
+}
@@ -0,0 +1,15 @@
+package getter
+
+import (
+	"os"
+)
+
+// Install a cli handler for the sandboxed process that downloads artifacts.
+// This init() must be initialized last in package required by the child
+// process. It's recommended to avoid any other `init()` or inline any
+// necessary calls here. See eeaa95d commit message for more details.
+func init() {
+	if len(os.Args) > 1 && os.Args[1] == sandboxCommand {
+		os.Exit(runSandbox(os.Args[2:]))
+	}
+}
@@ -177,13 +177,18 @@ func (h *logmonHook) prestartOneLoop(ctx context.Context, req *interfaces.TaskPr
 	}
 
 	err := h.logmon.Start(&logmon.LogConfig{
-		LogDir:        h.config.logDir,
-		StdoutLogFile: fmt.Sprintf("%s.stdout", req.Task.Name),
-		StderrLogFile: fmt.Sprintf("%s.stderr", req.Task.Name),
-		StdoutFifo:    h.config.stdoutFifo,
-		StderrFifo:    h.config.stderrFifo,
-		MaxFiles:      req.Task.LogConfig.MaxFiles,
-		MaxFileSizeMB: req.Task.LogConfig.MaxFileSizeMB,
+		LogDir:           h.config.logDir,
+		StdoutLogFile:    fmt.Sprintf("%s.stdout", req.Task.Name),
+		StderrLogFile:    fmt.Sprintf("%s.stderr", req.Task.Name),
+		StdoutFifo:       h.config.stdoutFifo,
+		StderrFifo:       h.config.stderrFifo,
+		MaxFiles:         req.Task.LogConfig.MaxFiles,
+		MaxFileSizeMB:    req.Task.LogConfig.MaxFileSizeMB,
+		RotationInterval: req.Task.LogConfig.RotationInterval,
+		Compress:         req.Task.LogConfig.Compress,
+		MaxTotalSizeMB:   req.Task.LogConfig.MaxTotalSizeMB,
+		Sinks:            logmonSinks(req.Task.LogConfig.Sinks),
+		Labels:           h.sinkLabels(req.Task),
 	})
 	if err != nil {
 		h.logger.Error("failed to start logmon", "error", err)
@@ -193,6 +198,46 @@ func (h *logmonHook) prestartOneLoop(ctx context.Context, req *interfaces.TaskPr
 	return nil
 }
 
+// sinkLabels returns the labels attached to each line shipped to the task's
+// log sinks.
+func (h *logmonHook) sinkLabels(task *structs.Task) map[string]string {
+	if len(task.LogConfig.Sinks) == 0 {
+		return nil
+	}
+
+	labels := map[string]string{
+		"task": task.Name,
+	}
+	if alloc := h.runner.Alloc(); alloc != nil {
+		labels["alloc_id"] = alloc.ID
+		labels["namespace"] = alloc.Namespace
+		labels["job"] = alloc.JobID
+		labels["group"] = alloc.TaskGroup
+	}
+	return labels
+}
+
+func logmonSinks(sinks []*structs.LogSink) []*logmon.SinkConfig {
+	if len(sinks) == 0 {
+		return nil
+	}
+	out := make([]*logmon.SinkConfig, len(sinks))
+	for i, s := range sinks {
+		out[i] = &logmon.SinkConfig{
+			Type:          s.Type,
+			Address:       s.Address,
+			Protocol:      s.Protocol,
+			Facility:      s.Facility,
+			Tag:           s.Tag,
+			BatchSize:     s.BatchSize,
+			BatchInterval: s.BatchInterval,
+			BufferSize:    s.BufferSize,
+			Backpressure:  s.Backpressure,
+		}
+	}
+	return out
+}
+
 func (h *logmonHook) Stop(_ context.Context, req *interfaces.TaskStopRequest, _ *interfaces.TaskStopResponse) error {
 
 	// It's possible that Stop was called without calling Prestart on agent
@@ -178,6 +178,10 @@ func (h *scriptCheckHook) newScriptChecks() map[string]*scriptCheck {
 	scriptChecks := make(map[string]*scriptCheck)
 	interpolatedTaskServices := taskenv.InterpolateServices(h.taskEnv, h.task.Services)
 	for _, service := range interpolatedTaskServices {
+		// script checks of nomad services are run by the checks hook
+		if service.Provider == structs.ServiceProviderNomad {
+			continue
+		}
 		for _, check := range service.Checks {
 			if check.Type != structs.ServiceCheckScript {
 				continue
@@ -214,6 +218,9 @@ func (h *scriptCheckHook) newScriptChecks() map[string]*scriptCheck {
 	tg := h.alloc.Job.LookupTaskGroup(h.alloc.TaskGroup)
 	interpolatedGroupServices := taskenv.InterpolateServices(h.taskEnv, tg.Services)
 	for _, service := range interpolatedGroupServices {
+		if service.Provider == structs.ServiceProviderNomad {
+			continue
+		}
 		for _, check := range service.Checks {
 			if check.Type != structs.ServiceCheckScript {
 				continue
@@ -16,6 +16,7 @@ import (
 	"github.com/hashicorp/hcl/v2/hcldec"
 	"github.com/hashicorp/nomad/client/allocdir"
 	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	tinterfaces "github.com/hashicorp/nomad/client/allocrunner/taskrunner/interfaces"
 	"github.com/hashicorp/nomad/client/allocrunner/taskrunner/restarts"
 	"github.com/hashicorp/nomad/client/allocrunner/taskrunner/state"
 	"github.com/hashicorp/nomad/client/config"
@@ -1566,6 +1567,32 @@ func (tr *TaskRunner) TaskExecHandler() drivermanager.TaskExecHandler {
 	return handle.ExecStreaming
 }
 
+// ScriptExecutor returns an executor for running script checks in the task,
+// or nil if the task is not running. The command and arguments are
+// interpolated with the task environment before being executed.
+func (tr *TaskRunner) ScriptExecutor() tinterfaces.ScriptExecutor {
+	// Check it is running
+	handle := tr.getDriverHandle()
+	if handle == nil {
+		return nil
+	}
+	return &envScriptExecutor{
+		exec:    handle,
+		taskEnv: tr.envBuilder.Build(),
+	}
+}
+
+// envScriptExecutor is a ScriptExecutor which interpolates the command and
+// arguments with the task environment.
+type envScriptExecutor struct {
+	exec    tinterfaces.ScriptExecutor
+	taskEnv *taskenv.TaskEnv
+}
+
+func (e *envScriptExecutor) Exec(timeout time.Duration, cmd string, args []string) ([]byte, int, error) {
+	return e.exec.Exec(timeout, e.taskEnv.ReplaceEnv(cmd), e.taskEnv.ParseAndReplace(args))
+}
+
 func (tr *TaskRunner) DriverCapabilities() (*drivers.Capabilities, error) {
 	return tr.driver.Capabilities()
 }
@@ -8,6 +8,7 @@ import (
 	log "github.com/hashicorp/go-hclog"
 
 	"github.com/hashicorp/nomad/client/allocrunner/taskrunner/interfaces"
+	"github.com/hashicorp/nomad/client/serviceregistration/checks"
 )
 
 // contextExec allows canceling a interfaces.ScriptExecutor with a context.
@@ -37,26 +38,7 @@ type execResult struct {
 // Exec a command until the timeout expires, the context is canceled, or the
 // underlying Exec returns.
 func (c *contextExec) Exec(timeout time.Duration, cmd string, args []string) ([]byte, int, error) {
-	resCh := make(chan execResult, 1)
-
-	// Don't trust the underlying implementation to obey timeout
-	ctx, cancel := context.WithTimeout(c.pctx, timeout)
-	defer cancel()
-
-	go func() {
-		output, code, err := c.exec.Exec(timeout, cmd, args)
-		select {
-		case resCh <- execResult{output, code, err}:
-		case <-ctx.Done():
-		}
-	}()
-
-	select {
-	case res := <-resCh:
-		return res.output, res.code, res.err
-	case <-ctx.Done():
-		return nil, 0, ctx.Err()
-	}
+	return checks.ExecScript(c.pctx, c.exec, timeout, cmd, args)
 }
 
 // tasklet is an abstraction around periodically running a script within
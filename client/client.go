@@ -395,7 +395,6 @@ func NewClient(cfg *config.Config, consulCatalog consul.CatalogAPI, consulProxie
 		serversContactedCh:   make(chan struct{}),
 		serversContactedOnce: sync.Once{},
 		cpusetManager:        cgutil.CreateCPUSetManager(cfg.CgroupParent, cfg.ReservableCores, logger),
-		getter:               getter.NewGetter(cfg.Artifact),
 		EnterpriseClient:     newEnterpriseClient(logger),
 	}
 
@@ -416,6 +415,19 @@ func NewClient(cfg *config.Config, consulCatalog consul.CatalogAPI, consulProxie
 		return nil, fmt.Errorf("failed to initialize client: %v", err)
 	}
 
+	// Create the artifact getter, sharing cached artifacts across allocations
+	// (needs to happen after init)
+	var artifactCache *getter.Cache
+	if cfg.Artifact != nil {
+		var err error
+		artifactCache, err = getter.NewCache(c.logger,
+			filepath.Join(c.GetConfig().StateDir, "artifacts"), cfg.Artifact.CacheMaxBytes)
+		if err != nil {
+			c.logger.Warn("failed to create artifact cache, artifacts will not be cached", "error", err)
+		}
+	}
+	c.getter = getter.NewGetter(c.logger, cfg.Artifact, artifactCache)
+
 	// initialize the dynamic registry (needs to happen after init)
 	c.dynamicRegistry =
 		dynamicplugins.NewRegistry(c.stateDB, map[string]dynamicplugins.PluginDispenser{
@@ -527,6 +539,9 @@ func NewClient(cfg *config.Config, consulCatalog consul.CatalogAPI, consulProxie
 		ReservedDiskMB:      cfg.Node.Reserved.DiskMB,
 	}
 	c.garbageCollector = NewAllocGarbageCollector(c.logger, statsCollector, c, gcConfig)
+	if artifactCache != nil {
+		c.garbageCollector.artifactCache = artifactCache
+	}
 	go c.garbageCollector.Run()
 
 	// Set the preconfigured list of static servers
@@ -2599,6 +2614,7 @@ func (c *Client) setupNomadServiceRegistrationHandler() {
 		NodeSecret: c.secretNodeID(),
 		Region:     c.Region(),
 		RPCFn:      c.RPC,
+		CheckStore: c.checkStore,
 	}
 	c.nomadService = nsd.NewServiceRegistrationHandler(c.logger, &cfg)
 }
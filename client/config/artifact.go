@@ -18,6 +18,27 @@ type ArtifactConfig struct {
 	GitTimeout time.Duration
 	HgTimeout  time.Duration
 	S3Timeout  time.Duration
+
+	// CacheMaxBytes is the maximum size of the artifact cache. Zero
+	// disables the cache.
+	CacheMaxBytes int64
+
+	// DisableSandbox downloads artifacts within the client process.
+	DisableSandbox bool
+
+	// DisableFilesystemIsolation allows the sandbox to access the whole
+	// filesystem.
+	DisableFilesystemIsolation bool
+
+	// SandboxCPULimit is the percentage of one core the sandbox may use and
+	// SandboxMemoryLimit the bytes of memory. Zero is unlimited.
+	SandboxCPULimit    int
+	SandboxMemoryLimit int64
+
+	// DecompressionSizeLimit and DecompressionFileCountLimit limit the files
+	// decompressed from an artifact. Zero is unlimited.
+	DecompressionSizeLimit      int64
+	DecompressionFileCountLimit int
 }
 
 // ArtifactConfigFromAgent creates a new internal readonly copy of the client
@@ -61,6 +82,43 @@ func ArtifactConfigFromAgent(c *config.ArtifactConfig) (*ArtifactConfig, error)
 	}
 	newConfig.S3Timeout = t
 
+	if c.CacheMaxSize != nil {
+		s, err = humanize.ParseBytes(*c.CacheMaxSize)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing CacheMaxSize: %w", err)
+		}
+		newConfig.CacheMaxBytes = int64(s)
+	}
+
+	if c.DisableSandbox != nil {
+		newConfig.DisableSandbox = *c.DisableSandbox
+	}
+	if c.DisableFilesystemIsolation != nil {
+		newConfig.DisableFilesystemIsolation = *c.DisableFilesystemIsolation
+	}
+	if c.SandboxCPULimit != nil {
+		newConfig.SandboxCPULimit = *c.SandboxCPULimit
+	}
+
+	if c.SandboxMemoryLimit != nil {
+		s, err = humanize.ParseBytes(*c.SandboxMemoryLimit)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing SandboxMemoryLimit: %w", err)
+		}
+		newConfig.SandboxMemoryLimit = int64(s)
+	}
+
+	if c.DecompressionSizeLimit != nil {
+		s, err = humanize.ParseBytes(*c.DecompressionSizeLimit)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing DecompressionSizeLimit: %w", err)
+		}
+		newConfig.DecompressionSizeLimit = int64(s)
+	}
+	if c.DecompressionFileCountLimit != nil {
+		newConfig.DecompressionFileCountLimit = *c.DecompressionFileCountLimit
+	}
+
 	return newConfig, nil
 }
 
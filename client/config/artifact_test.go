@@ -29,6 +29,12 @@ func TestArtifactConfigFromAgent(t *testing.T) {
 				GitTimeout:      30 * time.Minute,
 				HgTimeout:       30 * time.Minute,
 				S3Timeout:       30 * time.Minute,
+				CacheMaxBytes:   10_000_000_000,
+
+				SandboxCPULimit:             100,
+				SandboxMemoryLimit:          1_000_000_000,
+				DecompressionSizeLimit:      100_000_000_000,
+				DecompressionFileCountLimit: 4096,
 			},
 		},
 		{
@@ -103,6 +109,45 @@ func TestArtifactConfigFromAgent(t *testing.T) {
 			},
 			expectedError: "error parsing S3Timeout",
 		},
+		{
+			name: "invalid cache max size",
+			config: &config.ArtifactConfig{
+				HTTPReadTimeout: pointer.Of("30m"),
+				HTTPMaxSize:     pointer.Of("100GB"),
+				GCSTimeout:      pointer.Of("30m"),
+				GitTimeout:      pointer.Of("30m"),
+				HgTimeout:       pointer.Of("30m"),
+				S3Timeout:       pointer.Of("30m"),
+				CacheMaxSize:    pointer.Of("invalid"),
+			},
+			expectedError: "error parsing CacheMaxSize",
+		},
+		{
+			name: "invalid sandbox memory limit",
+			config: &config.ArtifactConfig{
+				HTTPReadTimeout:    pointer.Of("30m"),
+				HTTPMaxSize:        pointer.Of("100GB"),
+				GCSTimeout:         pointer.Of("30m"),
+				GitTimeout:         pointer.Of("30m"),
+				HgTimeout:          pointer.Of("30m"),
+				S3Timeout:          pointer.Of("30m"),
+				SandboxMemoryLimit: pointer.Of("invalid"),
+			},
+			expectedError: "error parsing SandboxMemoryLimit",
+		},
+		{
+			name: "invalid decompression size limit",
+			config: &config.ArtifactConfig{
+				HTTPReadTimeout:        pointer.Of("30m"),
+				HTTPMaxSize:            pointer.Of("100GB"),
+				GCSTimeout:             pointer.Of("30m"),
+				GitTimeout:             pointer.Of("30m"),
+				HgTimeout:              pointer.Of("30m"),
+				S3Timeout:              pointer.Of("30m"),
+				DecompressionSizeLimit: pointer.Of("invalid"),
+			},
+			expectedError: "error parsing DecompressionSizeLimit",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -2,9 +2,12 @@ package client
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
+	"encoding/binary"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"math"
 	"net/http"
 	"os"
@@ -22,6 +25,7 @@ import (
 	"github.com/hashicorp/nomad/acl"
 	"github.com/hashicorp/nomad/client/allocdir"
 	sframer "github.com/hashicorp/nomad/client/lib/streamframer"
+	"github.com/hashicorp/nomad/client/logmon/logging"
 	cstructs "github.com/hashicorp/nomad/client/structs"
 	"github.com/hashicorp/nomad/helper/pointer"
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -216,6 +220,13 @@ func (f *FileSystem) stream(conn io.ReadWriteCloser) {
 
 	// Calculate the offset
 	fileInfo, err := fs.Stat(req.Path)
+	if os.IsNotExist(err) && isLogPath(req.Path) {
+		// The rotated log may have been compressed since it was listed
+		if gzInfo, gzErr := fs.Stat(req.Path + logging.CompressedSuffix); gzErr == nil {
+			req.Path += logging.CompressedSuffix
+			fileInfo, err = gzInfo, nil
+		}
+	}
 	if err != nil {
 		handleStreamResultError(err, pointer.Of(int64(400)), encoder)
 		return
@@ -226,6 +237,13 @@ func (f *FileSystem) stream(conn io.ReadWriteCloser) {
 			pointer.Of(int64(400)), encoder)
 		return
 	}
+	if isCompressedLog(req.Path) {
+		fileInfo.Size, err = uncompressedSize(fs, req.Path, fileInfo.Size)
+		if err != nil {
+			handleStreamResultError(err, pointer.Of(int64(500)), encoder)
+			return
+		}
+	}
 
 	// If offsetting from the end subtract from the size
 	if req.Origin == "end" {
@@ -387,6 +405,11 @@ func (f *FileSystem) logs(conn io.ReadWriteCloser) {
 		handleStreamResultError(invalidOrigin, pointer.Of(int64(400)), encoder)
 		return
 	}
+	filter, err := newLogsFilter(req.Filter)
+	if err != nil {
+		handleStreamResultError(err, pointer.Of(int64(400)), encoder)
+		return
+	}
 
 	fs, err := f.c.GetAllocFS(req.AllocID)
 	if err != nil {
@@ -437,7 +460,7 @@ func (f *FileSystem) logs(conn io.ReadWriteCloser) {
 	// Start streaming
 	go func() {
 		if err := f.logsImpl(ctx, req.Follow, req.PlainText,
-			req.Offset, req.Origin, req.Task, req.LogType, fs, frames); err != nil {
+			req.Offset, req.Origin, req.Task, req.LogType, filter, fs, frames); err != nil {
 			select {
 			case errCh <- err:
 			case <-ctx.Done():
@@ -520,11 +543,19 @@ OUTER:
 
 // logsImpl is used to stream the logs of a the given task. Output is sent on
 // the passed frames channel and the method will return on EOF if follow is not
-// true otherwise when the context is cancelled or on an error.
+// true otherwise when the context is cancelled or on an error. If a filter is
+// given only the lines it matches are sent.
 func (f *FileSystem) logsImpl(ctx context.Context, follow, plain bool, offset int64,
-	origin, task, logType string,
+	origin, task, logType string, filter *logsFilter,
 	fs allocdir.AllocDirFS, frames chan<- *sframer.StreamFrame) error {
 
+	// Filter the frames before they're sent
+	if filter != nil {
+		unfiltered := make(chan *sframer.StreamFrame, streamFramesBuffer)
+		go filter.filterFrames(ctx, unfiltered, frames)
+		frames = unfiltered
+	}
+
 	// Create the framer
 	framer := sframer.NewStreamFramer(frames, streamHeartbeatRate, streamBatchWindow, streamFrameSize)
 	framer.Run()
@@ -533,11 +564,19 @@ func (f *FileSystem) logsImpl(ctx context.Context, follow, plain bool, offset in
 	// Path to the logs
 	logPath := filepath.Join(allocdir.SharedAllocName, allocdir.LogDirName)
 
+	// Find the rotated files written to during the time range
+	var segments []logging.Segment
+	if filter.hasTimeRange() {
+		segments = readLogSegments(fs, logPath, task, logType)
+	}
+
 	// nextIdx is the next index to read logs from
 	var nextIdx int64
 	switch origin {
 	case "start":
-		nextIdx = 0
+		if filter.hasTimeRange() {
+			nextIdx = filter.startIndex(segments)
+		}
 	case "end":
 		nextIdx = math.MaxInt64
 		offset *= -1
@@ -556,6 +595,9 @@ func (f *FileSystem) logsImpl(ctx context.Context, follow, plain bool, offset in
 		if err != nil {
 			return fmt.Errorf("failed to list entries: %v", err)
 		}
+		if err := resolveCompressedLogSizes(fs, logPath, entries, task, logType); err != nil {
+			return err
+		}
 
 		// If we are not following logs, determine the max index for the logs we are
 		// interested in so we can stop there.
@@ -573,6 +615,14 @@ func (f *FileSystem) logsImpl(ctx context.Context, follow, plain bool, offset in
 			return err
 		}
 
+		// Stop once the files were rotated after the end of the time range
+		if filter.hasTimeRange() {
+			segments = readLogSegments(fs, logPath, task, logType)
+			if filter.pastRange(segments, idx) {
+				return nil
+			}
+		}
+
 		var eofCancelCh chan error
 		cancelAfterFirstEof := false
 		exitAfter := false
@@ -640,7 +690,7 @@ func (f *FileSystem) streamFile(ctx context.Context, offset int64, path string,
 	fs allocdir.AllocDirFS, framer *sframer.StreamFramer, eofCancelCh chan error, cancelAfterFirstEof bool) error {
 
 	// Get the reader
-	file, err := fs.ReadAt(path, offset)
+	file, err := openFile(fs, path, offset)
 	if err != nil {
 		return err
 	}
@@ -664,8 +714,9 @@ func (f *FileSystem) streamFile(ctx context.Context, offset int64, path string,
 	// read and reach EOF.
 	var changes *watch.FileChanges
 
-	// Only watch file when there is a need for it
-	cancelReceived := cancelAfterFirstEof
+	// Only watch file when there is a need for it. Compressed logs are never
+	// written to again.
+	cancelReceived := cancelAfterFirstEof || isCompressedLog(path)
 
 	// Start streaming the data
 	bufSize := int64(streamFrameSize)
@@ -851,9 +902,11 @@ func (a indexTupleArray) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
 
 // logIndexes takes a set of entries and returns a indexTupleArray of
 // the desired log file entries. If the indexes could not be determined, an
-// error is returned.
+// error is returned. If a log file is listed both before and after being
+// compressed, the uncompressed file is used.
 func logIndexes(entries []*cstructs.AllocFileInfo, task, logType string) (indexTupleArray, error) {
 	var indexes []indexTuple
+	positions := make(map[int64]int)
 	prefix := fmt.Sprintf("%s.%s.", task, logType)
 	for _, entry := range entries {
 		if entry.IsDir {
@@ -865,6 +918,8 @@ func logIndexes(entries []*cstructs.AllocFileInfo, task, logType string) (indexT
 		if idxStr == entry.Name {
 			continue
 		}
+		compressed := strings.HasSuffix(idxStr, logging.CompressedSuffix)
+		idxStr = strings.TrimSuffix(idxStr, logging.CompressedSuffix)
 
 		// Convert to an int
 		idx, err := strconv.Atoi(idxStr)
@@ -872,12 +927,112 @@ func logIndexes(entries []*cstructs.AllocFileInfo, task, logType string) (indexT
 			return nil, fmt.Errorf("failed to convert %q to a log index: %v", idxStr, err)
 		}
 
+		if pos, ok := positions[int64(idx)]; ok {
+			if !compressed {
+				indexes[pos].entry = entry
+			}
+			continue
+		}
+		positions[int64(idx)] = len(indexes)
 		indexes = append(indexes, indexTuple{idx: int64(idx), entry: entry})
 	}
 
 	return indexTupleArray(indexes), nil
 }
 
+// isLogPath returns whether the path is within the alloc's log directory.
+func isLogPath(path string) bool {
+	return filepath.Dir(filepath.Clean(path)) == filepath.Join(allocdir.SharedAllocName, allocdir.LogDirName)
+}
+
+// isCompressedLog returns whether the path is a compressed rotated log file.
+// These are decompressed when read so that offsets match the uncompressed
+// log.
+func isCompressedLog(path string) bool {
+	return strings.HasSuffix(path, logging.CompressedSuffix) && isLogPath(path)
+}
+
+// gzipReadCloser closes both the gzip reader and the underlying file.
+type gzipReadCloser struct {
+	*gzip.Reader
+	file io.Closer
+}
+
+func (g *gzipReadCloser) Close() error {
+	g.Reader.Close()
+	return g.file.Close()
+}
+
+// openFile returns a reader of the file starting at the offset. Compressed
+// logs are decompressed with the offset counted in uncompressed bytes.
+func openFile(fs allocdir.AllocDirFS, path string, offset int64) (io.ReadCloser, error) {
+	if !isCompressedLog(path) {
+		return fs.ReadAt(path, offset)
+	}
+
+	file, err := fs.ReadAt(path, 0)
+	if err != nil {
+		return nil, err
+	}
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to decompress %q: %v", path, err)
+	}
+	if _, err := io.CopyN(ioutil.Discard, gz, offset); err != nil && err != io.EOF {
+		gz.Close()
+		file.Close()
+		return nil, fmt.Errorf("failed to decompress %q: %v", path, err)
+	}
+	return &gzipReadCloser{Reader: gz, file: file}, nil
+}
+
+// uncompressedSize returns the size of a compressed log once decompressed.
+// It's read from the gzip trailer, which records the size modulo 2^32. Log
+// config validation keeps compressed logs' max_file_size under 4GiB so that
+// the trailer holds the full size.
+func uncompressedSize(fs allocdir.AllocDirFS, path string, size int64) (int64, error) {
+	if size < 4 {
+		return 0, fmt.Errorf("compressed log %q is truncated", path)
+	}
+
+	r, err := fs.ReadAt(path, size-4)
+	if err != nil {
+		return 0, err
+	}
+	defer r.Close()
+
+	var trailer [4]byte
+	if _, err := io.ReadFull(r, trailer[:]); err != nil {
+		return 0, fmt.Errorf("failed to read size of compressed log %q: %v", path, err)
+	}
+	return int64(binary.LittleEndian.Uint32(trailer[:])), nil
+}
+
+// resolveCompressedLogSizes replaces the size of the task's compressed log
+// entries with their uncompressed size, so that offsets can be calculated
+// across compressed and uncompressed files.
+func resolveCompressedLogSizes(fs allocdir.AllocDirFS, logPath string, entries []*cstructs.AllocFileInfo, task, logType string) error {
+	prefix := fmt.Sprintf("%s.%s.", task, logType)
+	for _, entry := range entries {
+		if entry.IsDir || !strings.HasPrefix(entry.Name, prefix) ||
+			!strings.HasSuffix(entry.Name, logging.CompressedSuffix) {
+			continue
+		}
+
+		size, err := uncompressedSize(fs, filepath.Join(logPath, entry.Name), entry.Size)
+		if err != nil {
+			if os.IsNotExist(err) {
+				// Purged since being listed
+				continue
+			}
+			return err
+		}
+		entry.Size = size
+	}
+	return nil
+}
+
 // notFoundErr is returned when a log is requested but cannot be found.
 // Implements agent.HTTPCodedError but does not reference it to avoid circular
 // imports.
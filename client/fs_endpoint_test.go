@@ -1,6 +1,8 @@
 package client
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
 	"fmt"
 	"io"
@@ -11,6 +13,7 @@ import (
 	"path/filepath"
 	"reflect"
 	"runtime"
+	"sort"
 	"strings"
 	"sync"
 	"testing"
@@ -1651,6 +1654,52 @@ func TestFS_streamFile_Modify(t *testing.T) {
 	}
 }
 
+func TestFS_streamFile_Compressed(t *testing.T) {
+	ci.Parallel(t)
+
+	c, cleanup := TestClient(t, nil)
+	defer cleanup()
+
+	// Get a temp alloc dir and create the log dir
+	ad := tempAllocDir(t)
+	require.NoError(t, ad.Build())
+	defer ad.Destroy()
+
+	logDir := filepath.Join(ad.SharedDir, allocdir.LogDirName)
+	require.NoError(t, os.MkdirAll(logDir, 0777))
+
+	// Create a compressed rotated log
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	_, err := gz.Write([]byte("helloworld"))
+	require.NoError(t, err)
+	require.NoError(t, gz.Close())
+	require.NoError(t, ioutil.WriteFile(filepath.Join(logDir, "foo.stdout.0.gz"), buf.Bytes(), 0777))
+
+	path := filepath.Join(allocdir.SharedAllocName, allocdir.LogDirName, "foo.stdout.0.gz")
+	size, err := uncompressedSize(ad, path, int64(buf.Len()))
+	require.NoError(t, err)
+	require.Equal(t, int64(10), size)
+
+	frames := make(chan *sframer.StreamFrame, 32)
+	framer := sframer.NewStreamFramer(frames, streamHeartbeatRate, streamBatchWindow, streamFrameSize)
+	framer.Run()
+
+	// The compressed log is never written to again so streaming stops at
+	// EOF even when following, and the offset is in uncompressed bytes
+	require.NoError(t, c.endpoints.FileSystem.streamFile(
+		context.Background(), 5, path, 0, ad, framer, nil, false))
+	framer.Destroy()
+
+	var received []byte
+	for frame := range frames {
+		if !frame.IsHeartbeat() {
+			received = append(received, frame.Data...)
+		}
+	}
+	require.Equal(t, "world", string(received))
+}
+
 func TestFS_streamFile_Truncate(t *testing.T) {
 	ci.Parallel(t)
 
@@ -1889,7 +1938,7 @@ func TestFS_logsImpl_NoFollow(t *testing.T) {
 
 	if err := c.endpoints.FileSystem.logsImpl(
 		ctx, false, false, 0,
-		OriginStart, task, logType, ad, frames); err != nil {
+		OriginStart, task, logType, nil, ad, frames); err != nil {
 		t.Fatalf("logsImpl failed: %v", err)
 	}
 
@@ -1900,6 +1949,93 @@ func TestFS_logsImpl_NoFollow(t *testing.T) {
 	}
 }
 
+func TestFS_logsImpl_Compressed(t *testing.T) {
+	ci.Parallel(t)
+
+	c, cleanup := TestClient(t, nil)
+	defer cleanup()
+
+	// Get a temp alloc dir and create the log dir
+	ad := tempAllocDir(t)
+	require.NoError(t, ad.Build())
+	defer ad.Destroy()
+
+	logDir := filepath.Join(ad.SharedDir, allocdir.LogDirName)
+	require.NoError(t, os.MkdirAll(logDir, 0777))
+
+	// Create a series of log files where the rotated files are compressed
+	task := "foo"
+	logType := "stdout"
+	for i, contents := range []string{"hello\n", "world\n", "!\n"} {
+		logFile := fmt.Sprintf("%s.%s.%d", task, logType, i)
+		if i == 2 {
+			require.NoError(t, ioutil.WriteFile(filepath.Join(logDir, logFile), []byte(contents), 0777))
+			continue
+		}
+
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		_, err := gz.Write([]byte(contents))
+		require.NoError(t, err)
+		require.NoError(t, gz.Close())
+		require.NoError(t, ioutil.WriteFile(filepath.Join(logDir, logFile+".gz"), buf.Bytes(), 0777))
+	}
+
+	cases := []struct {
+		origin   string
+		offset   int64
+		expected string
+	}{
+		{origin: OriginStart, offset: 0, expected: "hello\nworld\n!\n"},
+		{origin: OriginStart, offset: 8, expected: "rld\n!\n"},
+		{origin: OriginEnd, offset: 9, expected: "\nworld\n!\n"},
+	}
+
+	for _, tc := range cases {
+		t.Run(fmt.Sprintf("%s %d", tc.origin, tc.offset), func(t *testing.T) {
+			frames := make(chan *sframer.StreamFrame, 32)
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			require.NoError(t, c.endpoints.FileSystem.logsImpl(
+				ctx, false, false, tc.offset,
+				tc.origin, task, logType, nil, ad, frames))
+
+			// The frames are flushed and closed when logsImpl returns
+			var received []byte
+			for frame := range frames {
+				if !frame.IsHeartbeat() {
+					received = append(received, frame.Data...)
+				}
+			}
+			require.Equal(t, tc.expected, string(received))
+		})
+	}
+}
+
+func TestFS_logIndexes_Compressed(t *testing.T) {
+	ci.Parallel(t)
+
+	entries := []*cstructs.AllocFileInfo{
+		{Name: "foo.stdout.0.gz"},
+		{Name: "foo.stdout.1.gz"},
+		{Name: "foo.stdout.1"},
+		{Name: "foo.stdout.2"},
+		{Name: ".foo.stdout.1.gz.tmp"},
+	}
+
+	// A file listed both before and after compression is only read once
+	indexes, err := logIndexes(entries, "foo", "stdout")
+	require.NoError(t, err)
+	sort.Sort(indexes)
+
+	var names []string
+	for _, idx := range indexes {
+		names = append(names, idx.entry.Name)
+	}
+	require.Equal(t, []string{"foo.stdout.0.gz", "foo.stdout.1", "foo.stdout.2"}, names)
+}
+
 func TestFS_logsImpl_Follow(t *testing.T) {
 	ci.Parallel(t)
 
@@ -1979,7 +2115,7 @@ func TestFS_logsImpl_Follow(t *testing.T) {
 	// Start streaming logs
 	go c.endpoints.FileSystem.logsImpl(
 		context.Background(), true, false, 0,
-		OriginStart, task, logType, ad, frames)
+		OriginStart, task, logType, nil, ad, frames)
 
 	select {
 	case <-firstResultCh:
@@ -0,0 +1,318 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/nomad/client/allocdir"
+	sframer "github.com/hashicorp/nomad/client/lib/streamframer"
+	"github.com/hashicorp/nomad/client/logmon/logging"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+)
+
+// maxFilterLineSize is the size a partial line may grow to before it's
+// filtered without waiting for the rest of it.
+const maxFilterLineSize = 4 * streamFrameSize
+
+// logTimeFields are the fields of JSON log lines checked, in order, for the
+// time the line was written.
+var logTimeFields = []string{"time", "timestamp", "ts", "@timestamp"}
+
+// logsFilter filters the lines of a log stream.
+type logsFilter struct {
+	match  *regexp.Regexp
+	since  time.Time
+	until  time.Time
+	json   bool
+	fields []string
+}
+
+// newLogsFilter validates the requested filter. A nil filter is returned if
+// nothing is filtered.
+func newLogsFilter(req *cstructs.LogsFilter) (*logsFilter, error) {
+	if req == nil {
+		return nil, nil
+	}
+
+	f := &logsFilter{
+		since:  req.Since,
+		until:  req.Until,
+		json:   req.JSON,
+		fields: req.Fields,
+	}
+	if req.Match != "" {
+		match, err := regexp.Compile(req.Match)
+		if err != nil {
+			return nil, fmt.Errorf("invalid match expression: %v", err)
+		}
+		f.match = match
+	}
+	if !f.since.IsZero() && !f.until.IsZero() && f.until.Before(f.since) {
+		return nil, fmt.Errorf("until must not be before since")
+	}
+	if len(f.fields) > 0 && !f.json {
+		return nil, fmt.Errorf("fields can only be selected from JSON logs")
+	}
+	if f.match == nil && !f.hasTimeRange() && !f.json {
+		return nil, nil
+	}
+	return f, nil
+}
+
+// hasTimeRange returns whether lines are filtered by when they were written.
+func (f *logsFilter) hasTimeRange() bool {
+	return f != nil && (!f.since.IsZero() || !f.until.IsZero())
+}
+
+// inRange returns whether t is within the time range.
+func (f *logsFilter) inRange(t time.Time) bool {
+	return (f.since.IsZero() || !t.Before(f.since)) &&
+		(f.until.IsZero() || !t.After(f.until))
+}
+
+// startIndex returns the index of the rotated file written to at the start
+// of the time range. Every earlier file was rotated before the range began.
+func (f *logsFilter) startIndex(segments []logging.Segment) int64 {
+	var idx int64
+	if f.since.IsZero() {
+		return idx
+	}
+	for _, s := range segments {
+		if s.Start.After(f.since) {
+			break
+		}
+		idx = int64(s.Index)
+	}
+	return idx
+}
+
+// pastRange returns whether the rotated file with the given index was created
+// after the time range ended. Files missing from the segment index may
+// overlap the range.
+func (f *logsFilter) pastRange(segments []logging.Segment, idx int64) bool {
+	if f.until.IsZero() {
+		return false
+	}
+	for _, s := range segments {
+		if int64(s.Index) == idx {
+			return s.Start.After(f.until)
+		}
+	}
+	return false
+}
+
+// readLogSegments returns the segment index recorded by logmon for the task's
+// logs. Logs without an index return no segments so no files are skipped.
+func readLogSegments(fs allocdir.AllocDirFS, logPath, task, logType string) []logging.Segment {
+	name := logging.SegmentIndexFile(fmt.Sprintf("%s.%s", task, logType))
+	r, err := fs.ReadAt(filepath.Join(logPath, name), 0)
+	if err != nil {
+		return nil
+	}
+	defer r.Close()
+
+	segments, err := logging.ReadSegments(r)
+	if err != nil {
+		return nil
+	}
+	return segments
+}
+
+// filterLine returns the line to output, including its trailing new line, and
+// whether it should be output at all.
+func (f *logsFilter) filterLine(line []byte) ([]byte, bool) {
+	if f.match != nil && !f.match.Match(bytes.TrimRight(line, "\r\n")) {
+		return nil, false
+	}
+	if !f.json {
+		return line, true
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(line))
+	dec.UseNumber()
+	var obj map[string]interface{}
+	if err := dec.Decode(&obj); err != nil || obj == nil {
+		// Lines that aren't JSON objects can't be filtered by time
+		return line, len(f.fields) == 0
+	}
+
+	if f.hasTimeRange() {
+		if t, ok := jsonLogTime(obj); ok && !f.inRange(t) {
+			return nil, false
+		}
+	}
+	if len(f.fields) == 0 {
+		return line, true
+	}
+
+	selected := make(map[string]interface{}, len(f.fields))
+	for _, field := range f.fields {
+		if v, ok := jsonField(obj, field); ok {
+			selected[field] = v
+		}
+	}
+	if len(selected) == 0 {
+		return nil, false
+	}
+	out, err := json.Marshal(selected)
+	if err != nil {
+		return nil, false
+	}
+	return append(out, '\n'), true
+}
+
+// filterLines filters each complete line of data.
+func (f *logsFilter) filterLines(data []byte) []byte {
+	var out []byte
+	for len(data) > 0 {
+		end := bytes.IndexByte(data, '\n') + 1
+		if end == 0 {
+			end = len(data)
+		}
+		if line, ok := f.filterLine(data[:end]); ok {
+			out = append(out, line...)
+		}
+		data = data[end:]
+	}
+	return out
+}
+
+// filterFrames filters the data of the frames from in and sends them to out,
+// closing out once in is closed. Lines may be split across frames and across
+// rotated files, so a partial line is held until the rest of it arrives.
+func (f *logsFilter) filterFrames(ctx context.Context, in <-chan *sframer.StreamFrame, out chan<- *sframer.StreamFrame) {
+	defer close(out)
+
+	send := func(frame *sframer.StreamFrame) bool {
+		select {
+		case out <- frame:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	var partial []byte
+	var last *sframer.StreamFrame
+	for frame := range in {
+		if frame.IsHeartbeat() || len(frame.Data) == 0 {
+			if !send(frame) {
+				break
+			}
+			continue
+		}
+		last = frame
+
+		data := append(partial, frame.Data...)
+		end := bytes.LastIndexByte(data, '\n') + 1
+		if end == 0 && len(data) >= maxFilterLineSize {
+			end = len(data)
+		}
+		partial = append([]byte(nil), data[end:]...)
+
+		filtered := f.filterLines(data[:end])
+		if len(filtered) == 0 && frame.FileEvent == "" {
+			continue
+		}
+		frame = frame.Copy()
+		frame.Data = filtered
+		if !send(frame) {
+			break
+		}
+	}
+
+	// Drain the framer if the stream was cancelled
+	for range in {
+	}
+
+	if len(partial) > 0 && last != nil && ctx.Err() == nil {
+		if filtered := f.filterLines(partial); len(filtered) > 0 {
+			frame := last.Copy()
+			frame.FileEvent = ""
+			frame.Data = filtered
+			send(frame)
+		}
+	}
+}
+
+// jsonLogTime returns the time a JSON log line was written, from the first of
+// the logTimeFields that holds an RFC 3339 timestamp or Unix time.
+func jsonLogTime(obj map[string]interface{}) (time.Time, bool) {
+	for _, field := range logTimeFields {
+		switch v := obj[field].(type) {
+		case string:
+			if t, err := time.Parse(time.RFC3339Nano, v); err == nil {
+				return t, true
+			}
+		case json.Number:
+			if t, ok := unixLogTime(v); ok {
+				return t, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// unixLogTime returns the time of a Unix timestamp in seconds, milliseconds,
+// microseconds or nanoseconds. The unit is detected by the magnitude of the
+// timestamp, so seconds are assumed up to 1e11 (the year 5138), milliseconds
+// up to 1e14 and microseconds up to 1e17.
+func unixLogTime(n json.Number) (time.Time, bool) {
+	// Integers are converted exactly, as nanoseconds don't fit in a float64
+	if i, err := n.Int64(); err == nil {
+		switch {
+		case i < 0:
+			return time.Time{}, false
+		case i < 1e11:
+			return time.Unix(i, 0), true
+		case i < 1e14:
+			return time.UnixMilli(i), true
+		case i < 1e17:
+			return time.UnixMicro(i), true
+		default:
+			return time.Unix(0, i), true
+		}
+	}
+
+	f, err := n.Float64()
+	if err != nil || f < 0 {
+		return time.Time{}, false
+	}
+	unit := time.Second
+	switch {
+	case f < 1e11:
+	case f < 1e14:
+		unit = time.Millisecond
+	case f < 1e17:
+		unit = time.Microsecond
+	default:
+		unit = time.Nanosecond
+	}
+	secs, frac := math.Modf(f * float64(unit) / float64(time.Second))
+	return time.Unix(int64(secs), int64(frac*float64(time.Second))), true
+}
+
+// jsonField returns the value of a field of a JSON object, descending into
+// nested objects for each dot separated part of the field.
+func jsonField(obj map[string]interface{}, field string) (interface{}, bool) {
+	if v, ok := obj[field]; ok {
+		return v, true
+	}
+
+	parts := strings.SplitN(field, ".", 2)
+	if len(parts) != 2 {
+		return nil, false
+	}
+	nested, ok := obj[parts[0]].(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	return jsonField(nested, parts[1])
+}
@@ -0,0 +1,317 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/client/allocdir"
+	sframer "github.com/hashicorp/nomad/client/lib/streamframer"
+	"github.com/hashicorp/nomad/client/logmon/logging"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogsFilter_New(t *testing.T) {
+	ci.Parallel(t)
+
+	now := time.Now()
+	cases := []struct {
+		name   string
+		filter *cstructs.LogsFilter
+		err    string
+		isNil  bool
+	}{
+		{name: "nil", isNil: true},
+		{name: "empty", filter: &cstructs.LogsFilter{}, isNil: true},
+		{name: "match", filter: &cstructs.LogsFilter{Match: "err(or)?"}},
+		{name: "bad match", filter: &cstructs.LogsFilter{Match: "("}, err: "invalid match expression"},
+		{name: "until before since", filter: &cstructs.LogsFilter{Since: now, Until: now.Add(-time.Minute)}, err: "until must not be before since"},
+		{name: "fields without json", filter: &cstructs.LogsFilter{Fields: []string{"msg"}}, err: "fields can only be selected from JSON logs"},
+		{name: "fields", filter: &cstructs.LogsFilter{JSON: true, Fields: []string{"msg"}}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			f, err := newLogsFilter(tc.filter)
+			if tc.err != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.isNil, f == nil)
+		})
+	}
+}
+
+func TestLogsFilter_unixLogTime(t *testing.T) {
+	ci.Parallel(t)
+
+	ts := time.Date(2022, 6, 1, 12, 0, 0, 123456789, time.UTC)
+	cases := []struct {
+		name     string
+		number   string
+		expected time.Time
+	}{
+		{name: "seconds", number: fmt.Sprint(ts.Unix()), expected: ts.Truncate(time.Second)},
+		{name: "fractional seconds", number: fmt.Sprintf("%d.5", ts.Unix()), expected: ts.Truncate(time.Second).Add(500 * time.Millisecond)},
+		{name: "milliseconds", number: fmt.Sprint(ts.UnixMilli()), expected: ts.Truncate(time.Millisecond)},
+		{name: "fractional milliseconds", number: fmt.Sprintf("%d.5", ts.UnixMilli()), expected: ts.Truncate(time.Millisecond).Add(500 * time.Microsecond)},
+		{name: "microseconds", number: fmt.Sprint(ts.UnixMicro()), expected: ts.Truncate(time.Microsecond)},
+		{name: "nanoseconds", number: fmt.Sprint(ts.UnixNano()), expected: ts},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			out, ok := unixLogTime(json.Number(tc.number))
+			require.True(t, ok)
+			require.WithinDuration(t, tc.expected, out, time.Microsecond)
+		})
+	}
+
+	_, ok := unixLogTime(json.Number("-1"))
+	require.False(t, ok)
+}
+
+func TestLogsFilter_filterLine(t *testing.T) {
+	ci.Parallel(t)
+
+	since := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+	until := since.Add(time.Hour)
+
+	cases := []struct {
+		name     string
+		filter   *cstructs.LogsFilter
+		line     string
+		expected string
+		ok       bool
+	}{
+		{
+			name:     "match",
+			filter:   &cstructs.LogsFilter{Match: "^ERR"},
+			line:     "ERROR: boom\n",
+			expected: "ERROR: boom\n",
+			ok:       true,
+		},
+		{
+			name:   "no match",
+			filter: &cstructs.LogsFilter{Match: "^ERR"},
+			line:   "INFO: ok\n",
+		},
+		{
+			name:     "plain line within range",
+			filter:   &cstructs.LogsFilter{Since: since, Until: until},
+			line:     "no timestamp\n",
+			expected: "no timestamp\n",
+			ok:       true,
+		},
+		{
+			name:     "json within range",
+			filter:   &cstructs.LogsFilter{JSON: true, Since: since, Until: until},
+			line:     `{"time":"2022-06-01T12:30:00Z","msg":"hi"}` + "\n",
+			expected: `{"time":"2022-06-01T12:30:00Z","msg":"hi"}` + "\n",
+			ok:       true,
+		},
+		{
+			name:   "json before range",
+			filter: &cstructs.LogsFilter{JSON: true, Since: since},
+			line:   `{"time":"2022-06-01T11:59:59Z","msg":"hi"}` + "\n",
+		},
+		{
+			name:   "json unix time after range",
+			filter: &cstructs.LogsFilter{JSON: true, Until: until},
+			line:   fmt.Sprintf(`{"ts":%d.5,"msg":"hi"}`, until.Unix()) + "\n",
+		},
+		{
+			name:     "json unix millis within range",
+			filter:   &cstructs.LogsFilter{JSON: true, Since: since, Until: until},
+			line:     fmt.Sprintf(`{"ts":%d,"msg":"hi"}`, since.Add(time.Minute).UnixMilli()) + "\n",
+			expected: fmt.Sprintf(`{"ts":%d,"msg":"hi"}`, since.Add(time.Minute).UnixMilli()) + "\n",
+			ok:       true,
+		},
+		{
+			name:   "json unix nanos before range",
+			filter: &cstructs.LogsFilter{JSON: true, Since: since},
+			line:   fmt.Sprintf(`{"ts":%d,"msg":"hi"}`, since.Add(-time.Nanosecond).UnixNano()) + "\n",
+		},
+		{
+			name:     "json fields",
+			filter:   &cstructs.LogsFilter{JSON: true, Fields: []string{"msg", "http.status", "missing"}},
+			line:     `{"level":"info","msg":"served","http":{"status":200,"path":"/"}}` + "\n",
+			expected: `{"http.status":200,"msg":"served"}` + "\n",
+			ok:       true,
+		},
+		{
+			name:   "json fields none present",
+			filter: &cstructs.LogsFilter{JSON: true, Fields: []string{"missing"}},
+			line:   `{"msg":"served"}` + "\n",
+		},
+		{
+			name:     "json passes through other lines",
+			filter:   &cstructs.LogsFilter{JSON: true},
+			line:     "panic: boom\n",
+			expected: "panic: boom\n",
+			ok:       true,
+		},
+		{
+			name:   "json fields drops other lines",
+			filter: &cstructs.LogsFilter{JSON: true, Fields: []string{"msg"}},
+			line:   "panic: boom\n",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			f, err := newLogsFilter(tc.filter)
+			require.NoError(t, err)
+
+			out, ok := f.filterLine([]byte(tc.line))
+			require.Equal(t, tc.ok, ok)
+			if ok {
+				require.Equal(t, tc.expected, string(out))
+			}
+		})
+	}
+}
+
+func TestLogsFilter_Segments(t *testing.T) {
+	ci.Parallel(t)
+
+	start := time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)
+	segments := []logging.Segment{
+		{Index: 2, Start: start},
+		{Index: 3, Start: start.Add(time.Hour)},
+		{Index: 5, Start: start.Add(3 * time.Hour)},
+	}
+
+	f := &logsFilter{
+		since: start.Add(90 * time.Minute),
+		until: start.Add(150 * time.Minute),
+	}
+	require.Equal(t, int64(3), f.startIndex(segments))
+	require.False(t, f.pastRange(segments, 3))
+	require.False(t, f.pastRange(segments, 4), "files missing from the index may overlap")
+	require.True(t, f.pastRange(segments, 5))
+
+	// Files from before the index began are all read
+	f.since = start.Add(-time.Minute)
+	require.Equal(t, int64(0), f.startIndex(segments))
+}
+
+func TestLogsFilter_filterFrames(t *testing.T) {
+	ci.Parallel(t)
+
+	f, err := newLogsFilter(&cstructs.LogsFilter{Match: "keep"})
+	require.NoError(t, err)
+
+	in := make(chan *sframer.StreamFrame, 10)
+	out := make(chan *sframer.StreamFrame, 10)
+
+	// Lines are split across frames and files
+	in <- &sframer.StreamFrame{File: "foo.stdout.0", Data: []byte("keep 1\ndrop 2\nke")}
+	in <- sframer.HeartbeatStreamFrame
+	in <- &sframer.StreamFrame{File: "foo.stdout.1", Data: []byte("ep 3\ndrop")}
+	in <- &sframer.StreamFrame{File: "foo.stdout.1", Data: []byte(" 4\nkeep 5")}
+	close(in)
+
+	f.filterFrames(context.Background(), in, out)
+
+	var received []byte
+	var heartbeats int
+	for frame := range out {
+		if frame.IsHeartbeat() {
+			heartbeats++
+			continue
+		}
+		received = append(received, frame.Data...)
+	}
+	require.Equal(t, "keep 1\nkeep 3\nkeep 5", string(received))
+	require.Equal(t, 1, heartbeats)
+}
+
+func TestFS_logsImpl_Filter(t *testing.T) {
+	ci.Parallel(t)
+
+	c, cleanup := TestClient(t, nil)
+	defer cleanup()
+
+	// Get a temp alloc dir and create the log dir
+	ad := tempAllocDir(t)
+	require.NoError(t, ad.Build())
+	defer ad.Destroy()
+
+	logDir := filepath.Join(ad.SharedDir, allocdir.LogDirName)
+	require.NoError(t, os.MkdirAll(logDir, 0777))
+
+	// Create a series of log files, each started an hour after the last
+	task := "foo"
+	logType := "stdout"
+	start := time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)
+	var index string
+	for i := 0; i < 4; i++ {
+		logFile := fmt.Sprintf("%s.%s.%d", task, logType, i)
+		contents := fmt.Sprintf("file %d ok\nfile %d error\n", i, i)
+		require.NoError(t, ioutil.WriteFile(filepath.Join(logDir, logFile), []byte(contents), 0777))
+		index += fmt.Sprintf("%d %d\n", i, start.Add(time.Duration(i)*time.Hour).UnixNano())
+	}
+	indexFile := logging.SegmentIndexFile(fmt.Sprintf("%s.%s", task, logType))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(logDir, indexFile), []byte(index), 0777))
+
+	cases := []struct {
+		name     string
+		filter   *cstructs.LogsFilter
+		expected string
+	}{
+		{
+			name:     "match",
+			filter:   &cstructs.LogsFilter{Match: "error"},
+			expected: "file 0 error\nfile 1 error\nfile 2 error\nfile 3 error\n",
+		},
+		{
+			name: "time range",
+			filter: &cstructs.LogsFilter{
+				Since: start.Add(90 * time.Minute),
+				Until: start.Add(150 * time.Minute),
+			},
+			expected: "file 1 ok\nfile 1 error\nfile 2 ok\nfile 2 error\n",
+		},
+		{
+			name: "match and since",
+			filter: &cstructs.LogsFilter{
+				Match: "ok",
+				Since: start.Add(3 * time.Hour),
+			},
+			expected: "file 3 ok\n",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			filter, err := newLogsFilter(tc.filter)
+			require.NoError(t, err)
+
+			frames := make(chan *sframer.StreamFrame, 32)
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			require.NoError(t, c.endpoints.FileSystem.logsImpl(
+				ctx, false, false, 0,
+				OriginStart, task, logType, filter, ad, frames))
+
+			var received []byte
+			for frame := range frames {
+				if !frame.IsHeartbeat() {
+					received = append(received, frame.Data...)
+				}
+			}
+			require.Equal(t, tc.expected, string(received))
+		})
+	}
+}
@@ -34,6 +34,14 @@ type AllocCounter interface {
 	NumAllocs() int
 }
 
+// ArtifactCache is a cache of artifacts whose entries are evicted by the
+// AllocGarbageCollector to free up disk before it collects allocations.
+type ArtifactCache interface {
+	// EvictOldest evicts the least recently used entry, returning false if
+	// there was no entry to evict.
+	EvictOldest() bool
+}
+
 // AllocGarbageCollector garbage collects terminated allocations on a node
 type AllocGarbageCollector struct {
 	config *GCConfig
@@ -47,6 +55,10 @@ type AllocGarbageCollector struct {
 	// allocCounter return the number of un-GC'd allocs on this node
 	allocCounter AllocCounter
 
+	// artifactCache is evicted from when disk usage is over the thresholds.
+	// It's nil if artifacts aren't cached.
+	artifactCache ArtifactCache
+
 	// destroyCh is a semaphore for rate limiting concurrent garbage
 	// collections
 	destroyCh chan struct{}
@@ -133,14 +145,17 @@ func (a *AllocGarbageCollector) keepUsageBelowThreshold() error {
 		logf := a.logger.Warn
 
 		liveAllocs := a.allocCounter.NumAllocs()
+		diskPressure := false
 
 		switch {
 		case diskStats.UsedPercent > a.config.DiskUsageThreshold:
 			reason = fmt.Sprintf("disk usage of %.0f is over gc threshold of %.0f",
 				diskStats.UsedPercent, a.config.DiskUsageThreshold)
+			diskPressure = true
 		case diskStats.InodesUsedPercent > a.config.InodeUsageThreshold:
 			reason = fmt.Sprintf("inode usage of %.0f is over gc threshold of %.0f",
 				diskStats.InodesUsedPercent, a.config.InodeUsageThreshold)
+			diskPressure = true
 		case liveAllocs > a.config.MaxAllocs:
 			// if we're unable to gc, don't WARN until at least 2x over limit
 			if liveAllocs < (a.config.MaxAllocs * 2) {
@@ -154,6 +169,13 @@ func (a *AllocGarbageCollector) keepUsageBelowThreshold() error {
 			break
 		}
 
+		// Cached artifacts can be downloaded again, so evict them before
+		// collecting allocations
+		if diskPressure && a.artifactCache != nil && a.artifactCache.EvictOldest() {
+			a.logger.Debug("evicted cached artifact", "reason", reason)
+			continue
+		}
+
 		// Collect an allocation
 		gcAlloc := a.allocRunners.Pop()
 		if gcAlloc == nil {
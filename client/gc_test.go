@@ -123,6 +123,21 @@ func (m *MockStatsCollector) Stats() *stats.HostStats {
 	}
 }
 
+// MockArtifactCache is an ArtifactCache with a number of entries.
+type MockArtifactCache struct {
+	entries int
+	evicted int
+}
+
+func (m *MockArtifactCache) EvictOldest() bool {
+	if m.entries == 0 {
+		return false
+	}
+	m.entries--
+	m.evicted++
+	return true
+}
+
 func TestAllocGarbageCollector_MarkForCollection(t *testing.T) {
 	ci.Parallel(t)
 
@@ -544,6 +559,44 @@ func TestAllocGarbageCollector_UsageBelowThreshold(t *testing.T) {
 	}
 }
 
+func TestAllocGarbageCollector_ArtifactCache(t *testing.T) {
+	ci.Parallel(t)
+
+	logger := testlog.HCLogger(t)
+	statsCollector := &MockStatsCollector{}
+	conf := gcConfig()
+	conf.ReservedDiskMB = 20
+	gc := NewAllocGarbageCollector(logger, statsCollector, &MockAllocCounter{}, conf)
+	cache := &MockArtifactCache{entries: 1}
+	gc.artifactCache = cache
+
+	ar1, cleanup1 := allocrunner.TestAllocRunnerFromAlloc(t, mock.Alloc())
+	defer cleanup1()
+	ar2, cleanup2 := allocrunner.TestAllocRunnerFromAlloc(t, mock.Alloc())
+	defer cleanup2()
+
+	go ar1.Run()
+	go ar2.Run()
+
+	gc.MarkForCollection(ar1.Alloc().ID, ar1)
+	gc.MarkForCollection(ar2.Alloc().ID, ar2)
+
+	// Exit the alloc runners
+	exitAllocRunner(ar1, ar2)
+
+	statsCollector.availableValues = []uint64{1000, 1000, 800}
+	statsCollector.usedPercents = []float64{85, 85, 60}
+	statsCollector.inodePercents = []float64{50, 50, 30}
+
+	require.NoError(t, gc.keepUsageBelowThreshold())
+
+	// The cached artifact is evicted before one of the alloc runners is
+	// collected
+	require.Equal(t, 1, cache.evicted)
+	require.NotNil(t, gc.allocRunners.Pop())
+	require.Nil(t, gc.allocRunners.Pop())
+}
+
 func TestAllocGarbageCollector_UsedPercentThreshold(t *testing.T) {
 	ci.Parallel(t)
 
@@ -19,13 +19,30 @@ const logmonRPCTimeout = 1 * time.Minute
 
 func (c *logmonClient) Start(cfg *LogConfig) error {
 	req := &proto.StartRequest{
-		LogDir:         cfg.LogDir,
-		StdoutFileName: cfg.StdoutLogFile,
-		StderrFileName: cfg.StderrLogFile,
-		MaxFiles:       uint32(cfg.MaxFiles),
-		MaxFileSizeMb:  uint32(cfg.MaxFileSizeMB),
-		StdoutFifo:     cfg.StdoutFifo,
-		StderrFifo:     cfg.StderrFifo,
+		LogDir:           cfg.LogDir,
+		StdoutFileName:   cfg.StdoutLogFile,
+		StderrFileName:   cfg.StderrLogFile,
+		MaxFiles:         uint32(cfg.MaxFiles),
+		MaxFileSizeMb:    uint32(cfg.MaxFileSizeMB),
+		StdoutFifo:       cfg.StdoutFifo,
+		StderrFifo:       cfg.StderrFifo,
+		Labels:           cfg.Labels,
+		RotationInterval: int64(cfg.RotationInterval),
+		Compress:         cfg.Compress,
+		MaxTotalSizeMb:   uint32(cfg.MaxTotalSizeMB),
+	}
+	for _, s := range cfg.Sinks {
+		req.Sinks = append(req.Sinks, &proto.LogSink{
+			Type:          s.Type,
+			Address:       s.Address,
+			Protocol:      s.Protocol,
+			Facility:      s.Facility,
+			Tag:           s.Tag,
+			BatchSize:     uint32(s.BatchSize),
+			BatchInterval: int64(s.BatchInterval),
+			BufferSize:    uint32(s.BufferSize),
+			Backpressure:  s.Backpressure,
+		})
 	}
 	ctx, cancel := context.WithTimeout(context.Background(), logmonRPCTimeout)
 	defer cancel()
@@ -3,7 +3,9 @@ package logging
 import (
 	"bufio"
 	"bytes"
+	"compress/gzip"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
@@ -31,28 +33,72 @@ const (
 
 	// newLineDelimiter is the delimiter used for new lines.
 	newLineDelimiter = '\n'
+
+	// CompressedSuffix is appended to the name of rotated files once they
+	// have been compressed.
+	CompressedSuffix = ".gz"
+
+	// compressCloseTimeout is the length of time Close waits for rotated
+	// files to finish being compressed.
+	compressCloseTimeout = 5 * time.Second
 )
 
+// RotatorConfig configures the optional rotation policies of a FileRotator.
+type RotatorConfig struct {
+	// RotationInterval rotates the current file once it has been open for
+	// this long, regardless of its size. Zero disables time-based rotation.
+	RotationInterval time.Duration
+
+	// Compress gzips files once they are rotated.
+	Compress bool
+
+	// MaxTotalBytes caps the combined size of the files of SizeCapFiles,
+	// deleting the oldest rotated files first. Zero disables the cap.
+	MaxTotalBytes int64
+
+	// SizeCapFiles are the base file names whose files count against
+	// MaxTotalBytes, such as both the stdout and stderr of a task. Defaults
+	// to the rotator's own base file name.
+	SizeCapFiles []string
+
+	// RecordSegments records when each file is created in the segment index
+	// so readers can find the files covering a time range.
+	RecordSegments bool
+}
+
 // FileRotator writes bytes to a rotated set of files
 type FileRotator struct {
 	MaxFiles int   // MaxFiles is the maximum number of rotated files allowed in a path
 	FileSize int64 // FileSize is the size a rotated file is allowed to grow
 
+	config RotatorConfig // config holds the optional rotation policies
+
 	path             string // path is the path on the file system where the rotated set of files are opened
 	baseFileName     string // baseFileName is the base file name of the rotated files
 	logFileIdx       int    // logFileIdx is the current index of the rotated files
 	oldestLogFileIdx int    // oldestLogFileIdx is the index of the oldest log file in a path
 
-	currentFile *os.File // currentFile is the file that is currently getting written
-	currentWr   int64    // currentWr is the number of bytes written to the current file
-	bufw        *bufio.Writer
-	bufLock     sync.Mutex
+	currentFile   *os.File  // currentFile is the file that is currently getting written
+	currentWr     int64     // currentWr is the number of bytes written to the current file
+	currentOpened time.Time // currentOpened is when the current file was opened
+	segments      []Segment // segments is the segment index if it's recorded
+	bufw          *bufio.Writer
+	bufLock       sync.Mutex
+
+	// rotateLock serializes writes with the rotations made by the flush
+	// ticker once the rotation interval elapses
+	rotateLock sync.Mutex
 
 	flushTicker *time.Ticker
 	logger      hclog.Logger
 	purgeCh     chan struct{}
 	doneCh      chan struct{}
 
+	// compressCh triggers compressing the rotated files and compressDoneCh
+	// is closed once the compressor exits
+	compressCh     chan struct{}
+	compressDoneCh chan struct{}
+
 	closed     bool
 	closedLock sync.Mutex
 }
@@ -60,6 +106,13 @@ type FileRotator struct {
 // NewFileRotator returns a new file rotator
 func NewFileRotator(path string, baseFile string, maxFiles int,
 	fileSize int64, logger hclog.Logger) (*FileRotator, error) {
+	return NewFileRotatorWithConfig(path, baseFile, maxFiles, fileSize, nil, logger)
+}
+
+// NewFileRotatorWithConfig returns a new file rotator using the optional
+// rotation policies of the config.
+func NewFileRotatorWithConfig(path string, baseFile string, maxFiles int,
+	fileSize int64, config *RotatorConfig, logger hclog.Logger) (*FileRotator, error) {
 	logger = logger.Named("rotator")
 	rotator := &FileRotator{
 		MaxFiles: maxFiles,
@@ -68,10 +121,18 @@ func NewFileRotator(path string, baseFile string, maxFiles int,
 		path:         path,
 		baseFileName: baseFile,
 
-		flushTicker: time.NewTicker(bufferFlushDuration),
-		logger:      logger,
-		purgeCh:     make(chan struct{}, 1),
-		doneCh:      make(chan struct{}),
+		flushTicker:    time.NewTicker(bufferFlushDuration),
+		logger:         logger,
+		purgeCh:        make(chan struct{}, 1),
+		doneCh:         make(chan struct{}),
+		compressCh:     make(chan struct{}, 1),
+		compressDoneCh: make(chan struct{}),
+	}
+	if config != nil {
+		rotator.config = *config
+	}
+	if len(rotator.config.SizeCapFiles) == 0 {
+		rotator.config.SizeCapFiles = []string{baseFile}
 	}
 
 	if err := rotator.lastFile(); err != nil {
@@ -79,23 +140,31 @@ func NewFileRotator(path string, baseFile string, maxFiles int,
 	}
 	go rotator.purgeOldFiles()
 	go rotator.flushPeriodically()
+	if rotator.config.Compress {
+		// Compress any files rotated before a restart
+		rotator.compressCh <- struct{}{}
+		go rotator.compressRotatedFiles()
+	} else {
+		close(rotator.compressDoneCh)
+	}
 	return rotator, nil
 }
 
 // Write writes a byte array to a file and rotates the file if it's size becomes
 // equal to the maximum size the user has defined.
 func (f *FileRotator) Write(p []byte) (n int, err error) {
+	f.rotateLock.Lock()
+	defer f.rotateLock.Unlock()
+
 	n = 0
 	var forceRotate bool
 
 	for n < len(p) {
 		// Check if we still have space in the current file, otherwise close and
 		// open the next file
-		if forceRotate || f.currentWr >= f.FileSize {
+		if forceRotate || f.currentWr >= f.FileSize || f.intervalElapsed() {
 			forceRotate = false
-			f.flushBuffer()
-			f.currentFile.Close()
-			if err := f.nextFile(); err != nil {
+			if err := f.rotate(); err != nil {
 				f.logger.Error("error creating next file", "err", err)
 				return 0, err
 			}
@@ -157,6 +226,38 @@ func (f *FileRotator) Write(p []byte) (n int, err error) {
 	return
 }
 
+// intervalElapsed returns whether the current file has been written to and
+// open for longer than the rotation interval.
+func (f *FileRotator) intervalElapsed() bool {
+	return f.config.RotationInterval > 0 && f.currentWr > 0 &&
+		time.Since(f.currentOpened) >= f.config.RotationInterval
+}
+
+// rotateIfElapsed rotates the current file once the rotation interval has
+// elapsed, so the file is rotated even if the task stops writing to it.
+func (f *FileRotator) rotateIfElapsed() {
+	f.rotateLock.Lock()
+	defer f.rotateLock.Unlock()
+
+	f.closedLock.Lock()
+	closed := f.closed
+	f.closedLock.Unlock()
+	if closed || !f.intervalElapsed() {
+		return
+	}
+
+	if err := f.rotate(); err != nil {
+		f.logger.Error("error creating next file", "err", err)
+	}
+}
+
+// rotate flushes and closes the current file and opens the next one
+func (f *FileRotator) rotate() error {
+	f.flushBuffer()
+	f.currentFile.Close()
+	return f.nextFile()
+}
+
 // nextFile opens the next file and purges older files if the number of rotated
 // files is larger than the maximum files configured by the user
 func (f *FileRotator) nextFile() error {
@@ -169,16 +270,33 @@ func (f *FileRotator) nextFile() error {
 				continue
 			}
 		}
+		if _, err := os.Stat(logFileName + CompressedSuffix); err == nil {
+			continue
+		}
 		f.logFileIdx = nextFileIdx
 		if err := f.createFile(); err != nil {
 			return err
 		}
 		break
 	}
-	// Purge old files if we have more files than MaxFiles
+
 	f.closedLock.Lock()
 	defer f.closedLock.Unlock()
-	if f.logFileIdx-f.oldestLogFileIdx >= f.MaxFiles && !f.closed {
+	if f.closed {
+		return nil
+	}
+
+	// Compress the file that was just rotated
+	if f.config.Compress {
+		select {
+		case f.compressCh <- struct{}{}:
+		default:
+		}
+	}
+
+	// Purge old files if we have more files than MaxFiles or the total size
+	// may have exceeded the cap
+	if f.logFileIdx-f.oldestLogFileIdx >= f.MaxFiles || f.config.MaxTotalBytes > 0 {
 		select {
 		case f.purgeCh <- struct{}{}:
 		default:
@@ -187,6 +305,24 @@ func (f *FileRotator) nextFile() error {
 	return nil
 }
 
+// rotatedIndex returns the index of a rotated file of the base file name and
+// whether it has been compressed. The last return value is false if the file
+// isn't one of the base file name's rotated files.
+func rotatedIndex(name, baseFileName string) (int, bool, bool) {
+	idxStr := strings.TrimPrefix(name, baseFileName+".")
+	if idxStr == name {
+		return 0, false, false
+	}
+
+	compressed := strings.HasSuffix(idxStr, CompressedSuffix)
+	idxStr = strings.TrimSuffix(idxStr, CompressedSuffix)
+	idx, err := strconv.Atoi(idxStr)
+	if err != nil {
+		return 0, false, false
+	}
+	return idx, compressed, true
+}
+
 // lastFile finds out the rotated file with the largest index in a path.
 func (f *FileRotator) lastFile() error {
 	finfos, err := ioutil.ReadDir(f.path)
@@ -194,20 +330,27 @@ func (f *FileRotator) lastFile() error {
 		return err
 	}
 
-	prefix := fmt.Sprintf("%s.", f.baseFileName)
+	if f.config.RecordSegments {
+		if err := f.loadSegments(); err != nil {
+			f.logger.Warn("error reading segment index", "err", err)
+		}
+	}
+
 	for _, fi := range finfos {
 		if fi.IsDir() {
 			continue
 		}
-		if strings.HasPrefix(fi.Name(), prefix) {
-			fileIdx := strings.TrimPrefix(fi.Name(), prefix)
-			n, err := strconv.Atoi(fileIdx)
-			if err != nil {
-				continue
-			}
-			if n > f.logFileIdx {
-				f.logFileIdx = n
-			}
+		n, compressed, ok := rotatedIndex(fi.Name(), f.baseFileName)
+		if !ok {
+			continue
+		}
+
+		// A compressed file is never written to again
+		if compressed {
+			n++
+		}
+		if n > f.logFileIdx {
+			f.logFileIdx = n
 		}
 	}
 	if err := f.createFile(); err != nil {
@@ -225,22 +368,28 @@ func (f *FileRotator) createFile() error {
 	}
 
 	f.currentFile = cFile
+	f.currentOpened = time.Now()
 	fi, err := f.currentFile.Stat()
 	if err != nil {
 		return err
 	}
 	f.currentWr = fi.Size()
 	f.createOrResetBuffer()
+
+	if f.config.RecordSegments && f.currentWr == 0 {
+		f.recordSegment(f.currentOpened)
+	}
 	return nil
 }
 
 // flushPeriodically flushes the buffered writer every 100ms to the underlying
-// file
+// file, and rotates the file once the rotation interval has elapsed
 func (f *FileRotator) flushPeriodically() {
 	for {
 		select {
 		case <-f.flushTicker.C:
 			f.flushBuffer()
+			f.rotateIfElapsed()
 		case <-f.doneCh:
 			return
 		}
@@ -250,6 +399,8 @@ func (f *FileRotator) flushPeriodically() {
 
 // Close flushes and closes the rotator. It never returns an error.
 func (f *FileRotator) Close() error {
+	f.rotateLock.Lock()
+	defer f.rotateLock.Unlock()
 	f.closedLock.Lock()
 	defer f.closedLock.Unlock()
 
@@ -265,58 +416,229 @@ func (f *FileRotator) Close() error {
 		f.currentFile.Close()
 	}
 
+	// Give the compressor a chance to finish with the last rotated file
+	select {
+	case <-f.compressDoneCh:
+	case <-time.After(compressCloseTimeout):
+		f.logger.Warn("timed out waiting for rotated files to be compressed")
+	}
+
 	return nil
 }
 
+// compressRotatedFiles gzips the rotated files each time a file is rotated.
+// Every file but the one with the highest index, which is being written to,
+// is compressed.
+func (f *FileRotator) compressRotatedFiles() {
+	defer close(f.compressDoneCh)
+	for {
+		select {
+		case <-f.compressCh:
+		case <-f.doneCh:
+			// Compress any file rotated just before closing
+			select {
+			case <-f.compressCh:
+			default:
+				return
+			}
+		}
+
+		files, err := ioutil.ReadDir(f.path)
+		if err != nil {
+			f.logger.Error("error getting directory listing", "err", err)
+			continue
+		}
+
+		var indexes []int
+		for _, fi := range files {
+			if idx, compressed, ok := rotatedIndex(fi.Name(), f.baseFileName); ok && !compressed {
+				indexes = append(indexes, idx)
+			}
+		}
+		sort.Ints(indexes)
+		for i := 0; i < len(indexes)-1; i++ {
+			if err := f.compressFile(indexes[i]); err != nil {
+				f.logger.Error("error compressing file", "index", indexes[i], "err", err)
+			}
+		}
+	}
+}
+
+// compressFile gzips the file with the given index. The compressed file is
+// written to a hidden temporary file and renamed into place before the
+// uncompressed file is removed, so readers always find one or the other.
+func (f *FileRotator) compressFile(idx int) error {
+	name := filepath.Join(f.path, fmt.Sprintf("%s.%d", f.baseFileName, idx))
+	src, err := os.Open(name)
+	if err != nil {
+		if os.IsNotExist(err) {
+			// Already purged
+			return nil
+		}
+		return err
+	}
+	defer src.Close()
+
+	fi, err := src.Stat()
+	if err != nil {
+		return err
+	}
+
+	tmpName := filepath.Join(f.path, fmt.Sprintf(".%s.%d%s.tmp", f.baseFileName, idx, CompressedSuffix))
+	dst, err := os.OpenFile(tmpName, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+
+	gz := gzip.NewWriter(dst)
+	_, err = io.Copy(gz, src)
+	if err == nil {
+		err = gz.Close()
+	}
+	if cerr := dst.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmpName)
+		return err
+	}
+
+	// Keep the modification time of the uncompressed file so the age of
+	// the file is preserved when purging
+	if err := os.Chtimes(tmpName, fi.ModTime(), fi.ModTime()); err != nil {
+		f.logger.Debug("error setting modification time of compressed file", "err", err)
+	}
+	if err := os.Rename(tmpName, name+CompressedSuffix); err != nil {
+		os.Remove(tmpName)
+		return err
+	}
+	return os.Remove(name)
+}
+
 // purgeOldFiles removes older files and keeps only the last N files rotated for
-// a file
+// a file, and then removes the oldest files until the total size of the files
+// is within the cap
 func (f *FileRotator) purgeOldFiles() {
 	for {
 		select {
 		case <-f.purgeCh:
-			var fIndexes []int
 			files, err := ioutil.ReadDir(f.path)
 			if err != nil {
 				f.logger.Error("error getting directory listing", "err", err)
 				return
 			}
+
 			// Inserting all the rotated files in a slice
+			seen := make(map[int]struct{})
+			var fIndexes []int
 			for _, fi := range files {
-				if strings.HasPrefix(fi.Name(), f.baseFileName) {
-					fileIdx := strings.TrimPrefix(fi.Name(), fmt.Sprintf("%s.", f.baseFileName))
-					n, err := strconv.Atoi(fileIdx)
-					if err != nil {
-						f.logger.Error("error extracting file index", "err", err)
-						continue
-					}
+				n, _, ok := rotatedIndex(fi.Name(), f.baseFileName)
+				if !ok {
+					continue
+				}
+				if _, ok := seen[n]; !ok {
+					seen[n] = struct{}{}
 					fIndexes = append(fIndexes, n)
 				}
 			}
-
-			// Not continuing to delete files if the number of files is not more
-			// than MaxFiles
-			if len(fIndexes) <= f.MaxFiles {
-				continue
-			}
+			sort.Ints(fIndexes)
 
 			// Sorting the file indexes so that we can purge the older files and keep
 			// only the number of files as configured by the user
-			sort.Ints(fIndexes)
-			toDelete := fIndexes[0 : len(fIndexes)-f.MaxFiles]
-			for _, fIndex := range toDelete {
-				fname := filepath.Join(f.path, fmt.Sprintf("%s.%d", f.baseFileName, fIndex))
-				err := os.RemoveAll(fname)
-				if err != nil {
-					f.logger.Error("error removing file", "filename", fname, "err", err)
+			deleted := make(map[string]struct{})
+			if len(fIndexes) > f.MaxFiles {
+				toDelete := fIndexes[0 : len(fIndexes)-f.MaxFiles]
+				for _, fIndex := range toDelete {
+					fname := filepath.Join(f.path, fmt.Sprintf("%s.%d", f.baseFileName, fIndex))
+					for _, name := range []string{fname, fname + CompressedSuffix} {
+						if err := os.RemoveAll(name); err != nil {
+							f.logger.Error("error removing file", "filename", name, "err", err)
+						}
+						deleted[filepath.Base(name)] = struct{}{}
+					}
 				}
+				fIndexes = fIndexes[len(toDelete):]
+			}
+			if len(fIndexes) > 0 {
+				f.closedLock.Lock()
+				f.oldestLogFileIdx = fIndexes[0]
+				f.closedLock.Unlock()
+			}
+
+			if f.config.MaxTotalBytes > 0 {
+				f.purgeOverSizeCap(files, deleted)
 			}
-			f.oldestLogFileIdx = fIndexes[0]
 		case <-f.doneCh:
 			return
 		}
 	}
 }
 
+// purgeOverSizeCap removes the oldest files of the size cap group until their
+// total size is within MaxTotalBytes. The file with the highest index of each
+// base file name is being written to and is never removed, and room is left
+// for it to grow to the full file size.
+func (f *FileRotator) purgeOverSizeCap(files []os.FileInfo, deleted map[string]struct{}) {
+	type rotatedFile struct {
+		fi   os.FileInfo
+		base string
+		idx  int
+	}
+
+	var total int64
+	var candidates []rotatedFile
+	current := make(map[string]int)
+	for _, fi := range files {
+		if _, ok := deleted[fi.Name()]; ok || fi.IsDir() {
+			continue
+		}
+		for _, base := range f.config.SizeCapFiles {
+			idx, _, ok := rotatedIndex(fi.Name(), base)
+			if !ok {
+				continue
+			}
+			candidates = append(candidates, rotatedFile{fi: fi, base: base, idx: idx})
+			if cur, ok := current[base]; !ok || idx > cur {
+				current[base] = idx
+			}
+			break
+		}
+	}
+	for _, c := range candidates {
+		size := c.fi.Size()
+		if c.idx == current[c.base] && size < f.FileSize {
+			size = f.FileSize
+		}
+		total += size
+	}
+	if total <= f.config.MaxTotalBytes {
+		return
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if !a.fi.ModTime().Equal(b.fi.ModTime()) {
+			return a.fi.ModTime().Before(b.fi.ModTime())
+		}
+		return a.idx < b.idx
+	})
+	for _, c := range candidates {
+		if total <= f.config.MaxTotalBytes {
+			return
+		}
+		if c.idx == current[c.base] {
+			continue
+		}
+
+		fname := filepath.Join(f.path, c.fi.Name())
+		if err := os.RemoveAll(fname); err != nil {
+			f.logger.Error("error removing file", "filename", fname, "err", err)
+			continue
+		}
+		total -= c.fi.Size()
+	}
+}
+
 // flushBuffer flushes the buffer
 func (f *FileRotator) flushBuffer() error {
 	f.bufLock.Lock()
@@ -1,12 +1,16 @@
 package logging
 
 import (
+	"compress/gzip"
 	"fmt"
 	"io/ioutil"
 	"math/rand"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/nomad/helper/testlog"
 	"github.com/hashicorp/nomad/testutil"
@@ -273,6 +277,227 @@ func TestFileRotator_PurgeOldFiles(t *testing.T) {
 	})
 }
 
+func TestFileRotator_Compress(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	path := t.TempDir()
+
+	fr, err := NewFileRotatorWithConfig(path, baseFileName, 10, 5,
+		&RotatorConfig{Compress: true}, testlog.HCLogger(t))
+	require.NoError(t, err)
+	defer fr.Close()
+
+	for _, line := range []string{"abcd\n", "efgh\n", "ijkl\n"} {
+		_, err := fr.Write([]byte(line))
+		require.NoError(t, err)
+	}
+
+	testutil.WaitForResult(func() (bool, error) {
+		for _, name := range []string{"redis.stdout.0.gz", "redis.stdout.1.gz", "redis.stdout.2"} {
+			if _, err := os.Stat(filepath.Join(path, name)); err != nil {
+				return false, err
+			}
+		}
+		for _, name := range []string{"redis.stdout.0", "redis.stdout.1"} {
+			if _, err := os.Stat(filepath.Join(path, name)); err == nil {
+				return false, fmt.Errorf("expected %q to be removed", name)
+			}
+		}
+		return true, nil
+	}, func(err error) {
+		require.NoError(t, err)
+	})
+
+	f, err := os.Open(filepath.Join(path, "redis.stdout.1.gz"))
+	require.NoError(t, err)
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	require.NoError(t, err)
+	raw, err := ioutil.ReadAll(gz)
+	require.NoError(t, err)
+	require.Equal(t, "efgh\n", string(raw))
+}
+
+func TestFileRotator_Compress_OpenLastFile(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	path := t.TempDir()
+
+	for _, name := range []string{"redis.stdout.0", "redis.stdout.1.gz"} {
+		f, err := os.Create(filepath.Join(path, name))
+		require.NoError(t, err)
+		f.Close()
+	}
+
+	fr, err := NewFileRotatorWithConfig(path, baseFileName, 10, 10,
+		&RotatorConfig{Compress: true}, testlog.HCLogger(t))
+	require.NoError(t, err)
+	defer fr.Close()
+
+	// Compressed files are never written to again, and files rotated before
+	// a restart are compressed
+	require.Equal(t, 2, fr.logFileIdx)
+	testutil.WaitForResult(func() (bool, error) {
+		_, err := os.Stat(filepath.Join(path, "redis.stdout.0.gz"))
+		return err == nil, err
+	}, func(err error) {
+		require.NoError(t, err)
+	})
+}
+
+func TestFileRotator_RotationInterval(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	path := t.TempDir()
+
+	fr, err := NewFileRotatorWithConfig(path, baseFileName, 10, 1024,
+		&RotatorConfig{RotationInterval: 50 * time.Millisecond}, testlog.HCLogger(t))
+	require.NoError(t, err)
+	defer fr.Close()
+
+	_, err = fr.Write([]byte("abcd\n"))
+	require.NoError(t, err)
+
+	time.Sleep(100 * time.Millisecond)
+	_, err = fr.Write([]byte("efgh\n"))
+	require.NoError(t, err)
+
+	testutil.WaitForResult(func() (bool, error) {
+		for name, expected := range map[string]string{
+			"redis.stdout.0": "abcd\n",
+			"redis.stdout.1": "efgh\n",
+		} {
+			raw, err := ioutil.ReadFile(filepath.Join(path, name))
+			if err != nil {
+				return false, err
+			}
+			if string(raw) != expected {
+				return false, fmt.Errorf("expected %q in %q, got %q", expected, name, raw)
+			}
+		}
+		return true, nil
+	}, func(err error) {
+		require.NoError(t, err)
+	})
+}
+
+func TestFileRotator_RotationInterval_Idle(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	path := t.TempDir()
+
+	fr, err := NewFileRotatorWithConfig(path, baseFileName, 10, 1024,
+		&RotatorConfig{RotationInterval: 50 * time.Millisecond, Compress: true}, testlog.HCLogger(t))
+	require.NoError(t, err)
+	defer fr.Close()
+
+	_, err = fr.Write([]byte("abcd\n"))
+	require.NoError(t, err)
+
+	// The file is rotated and compressed without any further writes
+	testutil.WaitForResult(func() (bool, error) {
+		if _, err := os.Stat(filepath.Join(path, "redis.stdout.0"+CompressedSuffix)); err != nil {
+			return false, err
+		}
+		return true, nil
+	}, func(err error) {
+		require.NoError(t, err)
+	})
+}
+
+func TestFileRotator_MaxTotalBytes(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	path := t.TempDir()
+
+	config := &RotatorConfig{
+		MaxTotalBytes: 10,
+		SizeCapFiles:  []string{"redis.stdout", "redis.stderr"},
+	}
+	stdout, err := NewFileRotatorWithConfig(path, "redis.stdout", 100, 5, config, testlog.HCLogger(t))
+	require.NoError(t, err)
+	defer stdout.Close()
+	stderr, err := NewFileRotatorWithConfig(path, "redis.stderr", 100, 5, config, testlog.HCLogger(t))
+	require.NoError(t, err)
+	defer stderr.Close()
+
+	for _, fr := range []*FileRotator{stdout, stderr} {
+		for i := 0; i < 4; i++ {
+			_, err := fr.Write([]byte("abcd\n"))
+			require.NoError(t, err)
+		}
+	}
+
+	// Only the files being written to fit within the cap
+	testutil.WaitForResult(func() (bool, error) {
+		files, err := ioutil.ReadDir(path)
+		if err != nil {
+			return false, err
+		}
+
+		var names []string
+		for _, fi := range files {
+			names = append(names, fi.Name())
+		}
+		expected := []string{"redis.stderr.3", "redis.stdout.3"}
+		if !reflect.DeepEqual(expected, names) {
+			return false, fmt.Errorf("expected files %v, got %v", expected, names)
+		}
+		return true, nil
+	}, func(err error) {
+		require.NoError(t, err)
+	})
+}
+
+func TestFileRotator_RecordSegments(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	path := t.TempDir()
+
+	before := time.Now()
+	fr, err := NewFileRotatorWithConfig(path, baseFileName, 10, 5,
+		&RotatorConfig{RecordSegments: true}, testlog.HCLogger(t))
+	require.NoError(t, err)
+
+	for _, line := range []string{"abcd\n", "efgh\n", "ijkl\n"} {
+		_, err := fr.Write([]byte(line))
+		require.NoError(t, err)
+	}
+	require.NoError(t, fr.Close())
+
+	f, err := os.Open(filepath.Join(path, SegmentIndexFile(baseFileName)))
+	require.NoError(t, err)
+	defer f.Close()
+
+	segments, err := ReadSegments(f)
+	require.NoError(t, err)
+	require.Len(t, segments, 3)
+	for i, s := range segments {
+		require.Equal(t, i, s.Index)
+		require.False(t, s.Start.Before(before))
+		if i > 0 {
+			require.False(t, s.Start.Before(segments[i-1].Start))
+		}
+	}
+
+	// Reopening the last file doesn't record a new segment
+	fr, err = NewFileRotatorWithConfig(path, baseFileName, 10, 5,
+		&RotatorConfig{RecordSegments: true}, testlog.HCLogger(t))
+	require.NoError(t, err)
+	require.Equal(t, segments, fr.segments)
+	require.NoError(t, fr.Close())
+}
+
+func TestReadSegments(t *testing.T) {
+	segments, err := ReadSegments(strings.NewReader("2 300\nbad line\n0 100\n1 200\n"))
+	require.NoError(t, err)
+	require.Equal(t, []Segment{
+		{Index: 0, Start: time.Unix(0, 100)},
+		{Index: 1, Start: time.Unix(0, 200)},
+		{Index: 2, Start: time.Unix(0, 300)},
+	}, segments)
+}
+
 func BenchmarkRotator(b *testing.B) {
 	kb := 1024
 	for _, inputSize := range []int{kb, 2 * kb, 4 * kb, 8 * kb, 16 * kb, 32 * kb, 64 * kb, 128 * kb, 256 * kb} {
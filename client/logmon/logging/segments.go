@@ -0,0 +1,123 @@
+package logging
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Segment records when a rotated file was created so readers can find the
+// files covering a time range without reading them.
+type Segment struct {
+	// Index is the index of the rotated file
+	Index int
+
+	// Start is when the rotated file was created. Everything written to the
+	// file was written at or after this time, and before the Start of the
+	// next segment.
+	Start time.Time
+}
+
+// SegmentIndexFile returns the name of the file the segments of the base file
+// name are recorded in. It's hidden so it isn't mistaken for a rotated file.
+func SegmentIndexFile(baseFileName string) string {
+	return fmt.Sprintf(".%s.segments", baseFileName)
+}
+
+// ReadSegments parses a segment index, returning the segments sorted by
+// index. Malformed lines are skipped.
+func ReadSegments(r io.Reader) ([]Segment, error) {
+	var segments []Segment
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		idx, err := strconv.Atoi(fields[0])
+		if err != nil {
+			continue
+		}
+		start, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		segments = append(segments, Segment{Index: idx, Start: time.Unix(0, start)})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(segments, func(i, j int) bool {
+		return segments[i].Index < segments[j].Index
+	})
+	return segments, nil
+}
+
+// writeSegments replaces the segment index at path. The index is written to a
+// temporary file and renamed into place so readers never see a partial index.
+func writeSegments(path string, segments []Segment) error {
+	tmp := path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(f)
+	for _, s := range segments {
+		fmt.Fprintf(w, "%d %d\n", s.Index, s.Start.UnixNano())
+	}
+	err = w.Flush()
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// loadSegments reads the rotator's segment index, if any.
+func (f *FileRotator) loadSegments() error {
+	file, err := os.Open(filepath.Join(f.path, SegmentIndexFile(f.baseFileName)))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer file.Close()
+
+	f.segments, err = ReadSegments(file)
+	return err
+}
+
+// recordSegment adds the current file to the segment index if it was just
+// created, dropping the segments of files that have been purged. The start
+// of an existing file isn't known, so it's left out of the index and readers
+// treat it as overlapping any time range.
+func (f *FileRotator) recordSegment(created time.Time) {
+	f.closedLock.Lock()
+	oldest := f.oldestLogFileIdx
+	f.closedLock.Unlock()
+
+	segments := make([]Segment, 0, len(f.segments)+1)
+	for _, s := range f.segments {
+		if s.Index >= oldest && s.Index != f.logFileIdx {
+			segments = append(segments, s)
+		}
+	}
+	f.segments = append(segments, Segment{Index: f.logFileIdx, Start: created})
+
+	path := filepath.Join(f.path, SegmentIndexFile(f.baseFileName))
+	if err := writeSegments(path, f.segments); err != nil {
+		f.logger.Warn("error writing segment index", "err", err)
+	}
+}
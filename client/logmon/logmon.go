@@ -41,6 +41,24 @@ type LogConfig struct {
 
 	// MaxFileSizeMB is the max log file size in MB allowed before rotation occures
 	MaxFileSizeMB int
+
+	// RotationInterval is the max time a log file is written to before
+	// rotation occurs
+	RotationInterval time.Duration
+
+	// Compress gzips log files once they are rotated
+	Compress bool
+
+	// MaxTotalSizeMB caps the combined size of the stdout and stderr log
+	// files in MB
+	MaxTotalSizeMB int
+
+	// Sinks are external destinations logs are shipped to in addition to
+	// the rotated log files
+	Sinks []*SinkConfig
+
+	// Labels are attached to every record shipped to the sinks
+	Labels map[string]string
 }
 
 type LogMon interface {
@@ -106,6 +124,9 @@ type TaskLogger struct {
 
 	// rotator for stderr
 	lre *logRotatorWrapper
+
+	// sinks logs are shipped to
+	sinks []*logSink
 }
 
 // IsRunning will return true as long as one rotator wrapper is still running
@@ -133,33 +154,68 @@ func (tl *TaskLogger) Close() {
 		}()
 	}
 	wg.Wait()
+
+	// Sinks are closed once the rotators have stopped writing to them so
+	// that the last lines are flushed
+	for _, s := range tl.sinks {
+		wg.Add(1)
+		go func(s *logSink) {
+			s.Close()
+			wg.Done()
+		}(s)
+	}
+	wg.Wait()
 }
 
 func NewTaskLogger(cfg *LogConfig, logger hclog.Logger) (*TaskLogger, error) {
 	tl := &TaskLogger{config: cfg}
 
+	for _, sc := range cfg.Sinks {
+		s, err := newLogSink(sc, cfg.Labels, logger)
+		if err != nil {
+			tl.Close()
+			return nil, fmt.Errorf("failed to create log sink: %v", err)
+		}
+		tl.sinks = append(tl.sinks, s)
+	}
+
 	logFileSize := int64(cfg.MaxFileSizeMB * 1024 * 1024)
-	lro, err := logging.NewFileRotator(cfg.LogDir, cfg.StdoutLogFile,
-		cfg.MaxFiles, logFileSize, logger)
+	rotatorConfig := &logging.RotatorConfig{
+		RotationInterval: cfg.RotationInterval,
+		Compress:         cfg.Compress,
+		MaxTotalBytes:    int64(cfg.MaxTotalSizeMB) * 1024 * 1024,
+		SizeCapFiles:     []string{cfg.StdoutLogFile, cfg.StderrLogFile},
+		RecordSegments:   true,
+	}
+	// The rotators and sinks created so far are closed on error so the
+	// sinks' goroutines and connections aren't leaked
+	lro, err := logging.NewFileRotatorWithConfig(cfg.LogDir, cfg.StdoutLogFile,
+		cfg.MaxFiles, logFileSize, rotatorConfig, logger)
 	if err != nil {
+		tl.Close()
 		return nil, fmt.Errorf("failed to create stdout logfile for %q: %v", cfg.StdoutLogFile, err)
 	}
 
-	wrapperOut, err := newLogRotatorWrapper(cfg.StdoutFifo, logger, lro)
+	wrapperOut, err := newLogRotatorWrapper(cfg.StdoutFifo, logger, tl.withSinks("stdout", lro))
 	if err != nil {
+		lro.Close()
+		tl.Close()
 		return nil, err
 	}
 
 	tl.lro = wrapperOut
 
-	lre, err := logging.NewFileRotator(cfg.LogDir, cfg.StderrLogFile,
-		cfg.MaxFiles, logFileSize, logger)
+	lre, err := logging.NewFileRotatorWithConfig(cfg.LogDir, cfg.StderrLogFile,
+		cfg.MaxFiles, logFileSize, rotatorConfig, logger)
 	if err != nil {
+		tl.Close()
 		return nil, fmt.Errorf("failed to create stderr logfile for %q: %v", cfg.StderrLogFile, err)
 	}
 
-	wrapperErr, err := newLogRotatorWrapper(cfg.StderrFifo, logger, lre)
+	wrapperErr, err := newLogRotatorWrapper(cfg.StderrFifo, logger, tl.withSinks("stderr", lre))
 	if err != nil {
+		lre.Close()
+		tl.Close()
 		return nil, err
 	}
 
@@ -169,6 +225,18 @@ func NewTaskLogger(cfg *LogConfig, logger hclog.Logger) (*TaskLogger, error) {
 
 }
 
+// withSinks returns a writer teeing the stream to the rotator and the sinks,
+// or the rotator itself if there are no sinks.
+func (tl *TaskLogger) withSinks(stream string, rotator io.WriteCloser) io.WriteCloser {
+	if len(tl.sinks) == 0 {
+		return rotator
+	}
+	return &teeWriteCloser{
+		rotator: rotator,
+		sinks:   newSinkWriter(stream, tl.config.Labels, tl.sinks),
+	}
+}
+
 // logRotatorWrapper wraps our log rotator and exposes a pipe that can feed the
 // log rotator data. The processOutWriter should be attached to the process and
 // data will be copied from the reader to the rotator.
@@ -25,16 +25,21 @@ var _ = math.Inf
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
 type StartRequest struct {
-	LogDir               string   `protobuf:"bytes,1,opt,name=log_dir,json=logDir,proto3" json:"log_dir,omitempty"`
-	StdoutFileName       string   `protobuf:"bytes,2,opt,name=stdout_file_name,json=stdoutFileName,proto3" json:"stdout_file_name,omitempty"`
-	StderrFileName       string   `protobuf:"bytes,3,opt,name=stderr_file_name,json=stderrFileName,proto3" json:"stderr_file_name,omitempty"`
-	MaxFiles             uint32   `protobuf:"varint,4,opt,name=max_files,json=maxFiles,proto3" json:"max_files,omitempty"`
-	MaxFileSizeMb        uint32   `protobuf:"varint,5,opt,name=max_file_size_mb,json=maxFileSizeMb,proto3" json:"max_file_size_mb,omitempty"`
-	StdoutFifo           string   `protobuf:"bytes,6,opt,name=stdout_fifo,json=stdoutFifo,proto3" json:"stdout_fifo,omitempty"`
-	StderrFifo           string   `protobuf:"bytes,7,opt,name=stderr_fifo,json=stderrFifo,proto3" json:"stderr_fifo,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	LogDir               string            `protobuf:"bytes,1,opt,name=log_dir,json=logDir,proto3" json:"log_dir,omitempty"`
+	StdoutFileName       string            `protobuf:"bytes,2,opt,name=stdout_file_name,json=stdoutFileName,proto3" json:"stdout_file_name,omitempty"`
+	StderrFileName       string            `protobuf:"bytes,3,opt,name=stderr_file_name,json=stderrFileName,proto3" json:"stderr_file_name,omitempty"`
+	MaxFiles             uint32            `protobuf:"varint,4,opt,name=max_files,json=maxFiles,proto3" json:"max_files,omitempty"`
+	MaxFileSizeMb        uint32            `protobuf:"varint,5,opt,name=max_file_size_mb,json=maxFileSizeMb,proto3" json:"max_file_size_mb,omitempty"`
+	StdoutFifo           string            `protobuf:"bytes,6,opt,name=stdout_fifo,json=stdoutFifo,proto3" json:"stdout_fifo,omitempty"`
+	StderrFifo           string            `protobuf:"bytes,7,opt,name=stderr_fifo,json=stderrFifo,proto3" json:"stderr_fifo,omitempty"`
+	Sinks                []*LogSink        `protobuf:"bytes,8,rep,name=sinks,proto3" json:"sinks,omitempty"`
+	Labels               map[string]string `protobuf:"bytes,9,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	RotationInterval     int64             `protobuf:"varint,10,opt,name=rotation_interval,json=rotationInterval,proto3" json:"rotation_interval,omitempty"`
+	Compress             bool              `protobuf:"varint,11,opt,name=compress,proto3" json:"compress,omitempty"`
+	MaxTotalSizeMb       uint32            `protobuf:"varint,12,opt,name=max_total_size_mb,json=maxTotalSizeMb,proto3" json:"max_total_size_mb,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
 }
 
 func (m *StartRequest) Reset()         { *m = StartRequest{} }
@@ -111,6 +116,41 @@ func (m *StartRequest) GetStderrFifo() string {
 	return ""
 }
 
+func (m *StartRequest) GetSinks() []*LogSink {
+	if m != nil {
+		return m.Sinks
+	}
+	return nil
+}
+
+func (m *StartRequest) GetLabels() map[string]string {
+	if m != nil {
+		return m.Labels
+	}
+	return nil
+}
+
+func (m *StartRequest) GetRotationInterval() int64 {
+	if m != nil {
+		return m.RotationInterval
+	}
+	return 0
+}
+
+func (m *StartRequest) GetCompress() bool {
+	if m != nil {
+		return m.Compress
+	}
+	return false
+}
+
+func (m *StartRequest) GetMaxTotalSizeMb() uint32 {
+	if m != nil {
+		return m.MaxTotalSizeMb
+	}
+	return 0
+}
+
 type StartResponse struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
@@ -204,11 +244,116 @@ func (m *StopResponse) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_StopResponse proto.InternalMessageInfo
 
+type LogSink struct {
+	Type                 string   `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Address              string   `protobuf:"bytes,2,opt,name=address,proto3" json:"address,omitempty"`
+	Protocol             string   `protobuf:"bytes,3,opt,name=protocol,proto3" json:"protocol,omitempty"`
+	Facility             string   `protobuf:"bytes,4,opt,name=facility,proto3" json:"facility,omitempty"`
+	Tag                  string   `protobuf:"bytes,5,opt,name=tag,proto3" json:"tag,omitempty"`
+	BatchSize            uint32   `protobuf:"varint,6,opt,name=batch_size,json=batchSize,proto3" json:"batch_size,omitempty"`
+	BatchInterval        int64    `protobuf:"varint,7,opt,name=batch_interval,json=batchInterval,proto3" json:"batch_interval,omitempty"`
+	BufferSize           uint32   `protobuf:"varint,8,opt,name=buffer_size,json=bufferSize,proto3" json:"buffer_size,omitempty"`
+	Backpressure         bool     `protobuf:"varint,9,opt,name=backpressure,proto3" json:"backpressure,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LogSink) Reset()         { *m = LogSink{} }
+func (m *LogSink) String() string { return proto.CompactTextString(m) }
+func (*LogSink) ProtoMessage()    {}
+func (*LogSink) Descriptor() ([]byte, []int) {
+	return fileDescriptor_be72d5e24d2ecba6, []int{4}
+}
+
+func (m *LogSink) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LogSink.Unmarshal(m, b)
+}
+func (m *LogSink) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LogSink.Marshal(b, m, deterministic)
+}
+func (m *LogSink) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LogSink.Merge(m, src)
+}
+func (m *LogSink) XXX_Size() int {
+	return xxx_messageInfo_LogSink.Size(m)
+}
+func (m *LogSink) XXX_DiscardUnknown() {
+	xxx_messageInfo_LogSink.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LogSink proto.InternalMessageInfo
+
+func (m *LogSink) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *LogSink) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *LogSink) GetProtocol() string {
+	if m != nil {
+		return m.Protocol
+	}
+	return ""
+}
+
+func (m *LogSink) GetFacility() string {
+	if m != nil {
+		return m.Facility
+	}
+	return ""
+}
+
+func (m *LogSink) GetTag() string {
+	if m != nil {
+		return m.Tag
+	}
+	return ""
+}
+
+func (m *LogSink) GetBatchSize() uint32 {
+	if m != nil {
+		return m.BatchSize
+	}
+	return 0
+}
+
+func (m *LogSink) GetBatchInterval() int64 {
+	if m != nil {
+		return m.BatchInterval
+	}
+	return 0
+}
+
+func (m *LogSink) GetBufferSize() uint32 {
+	if m != nil {
+		return m.BufferSize
+	}
+	return 0
+}
+
+func (m *LogSink) GetBackpressure() bool {
+	if m != nil {
+		return m.Backpressure
+	}
+	return false
+}
+
 func init() {
 	proto.RegisterType((*StartRequest)(nil), "hashicorp.nomad.client.logmon.proto.StartRequest")
+	proto.RegisterMapType((map[string]string)(nil), "hashicorp.nomad.client.logmon.proto.StartRequest.LabelsEntry")
 	proto.RegisterType((*StartResponse)(nil), "hashicorp.nomad.client.logmon.proto.StartResponse")
 	proto.RegisterType((*StopRequest)(nil), "hashicorp.nomad.client.logmon.proto.StopRequest")
 	proto.RegisterType((*StopResponse)(nil), "hashicorp.nomad.client.logmon.proto.StopResponse")
+	proto.RegisterType((*LogSink)(nil), "hashicorp.nomad.client.logmon.proto.LogSink")
 }
 
 func init() {
@@ -216,27 +361,44 @@ func init() {
 }
 
 var fileDescriptor_be72d5e24d2ecba6 = []byte{
-	// 320 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x90, 0x31, 0x6f, 0xc2, 0x30,
-	0x10, 0x85, 0x1b, 0x0a, 0xa1, 0x1c, 0x0d, 0x45, 0x5e, 0x1a, 0xd1, 0xa1, 0x28, 0x1d, 0xca, 0x14,
-	0x0a, 0xfd, 0x07, 0x55, 0xd5, 0xa9, 0x74, 0x80, 0xad, 0x4b, 0x64, 0xc0, 0x09, 0x96, 0x62, 0x5f,
-	0x6a, 0x1b, 0x09, 0xb1, 0xf6, 0xd7, 0xf6, 0x5f, 0x54, 0x71, 0x4c, 0x94, 0x11, 0x26, 0xeb, 0xee,
-	0x7d, 0x4f, 0xf7, 0xfc, 0x60, 0xbc, 0xc9, 0x39, 0x93, 0x66, 0x9a, 0x63, 0x26, 0x50, 0x4e, 0x0b,
-	0x85, 0x06, 0xdd, 0x10, 0xdb, 0x81, 0x3c, 0xed, 0xa8, 0xde, 0xf1, 0x0d, 0xaa, 0x22, 0x96, 0x28,
-	0xe8, 0x36, 0xae, 0x1c, 0x71, 0x13, 0x8a, 0x7e, 0x5b, 0x70, 0xbb, 0x32, 0x54, 0x99, 0x25, 0xfb,
-	0xd9, 0x33, 0x6d, 0xc8, 0x3d, 0x74, 0x73, 0xcc, 0x92, 0x2d, 0x57, 0xa1, 0x37, 0xf6, 0x26, 0xbd,
-	0xa5, 0x9f, 0x63, 0xf6, 0xce, 0x15, 0x99, 0xc0, 0x50, 0x9b, 0x2d, 0xee, 0x4d, 0x92, 0xf2, 0x9c,
-	0x25, 0x92, 0x0a, 0x16, 0xb6, 0x2c, 0x31, 0xa8, 0xf6, 0x1f, 0x3c, 0x67, 0x5f, 0x54, 0x30, 0x47,
-	0x32, 0xa5, 0x1a, 0xe4, 0x75, 0x4d, 0x32, 0xa5, 0x6a, 0xf2, 0x01, 0x7a, 0x82, 0x1e, 0x2c, 0xa6,
-	0xc3, 0xf6, 0xd8, 0x9b, 0x04, 0xcb, 0x1b, 0x41, 0x0f, 0xa5, 0xae, 0xc9, 0x33, 0x0c, 0x4f, 0x62,
-	0xa2, 0xf9, 0x91, 0x25, 0x62, 0x1d, 0x76, 0x2c, 0x13, 0x38, 0x66, 0xc5, 0x8f, 0x6c, 0xb1, 0x26,
-	0x8f, 0xd0, 0xaf, 0x93, 0xa5, 0x18, 0xfa, 0xf6, 0x14, 0x9c, 0x42, 0xa5, 0xe8, 0x80, 0x2a, 0x50,
-	0x8a, 0x61, 0xb7, 0x06, 0x6c, 0x96, 0x14, 0xa3, 0x3b, 0x08, 0x5c, 0x09, 0xba, 0x40, 0xa9, 0x59,
-	0x14, 0x40, 0x7f, 0x65, 0xb0, 0x70, 0xa5, 0x44, 0x83, 0xb2, 0xa4, 0x72, 0xac, 0xe4, 0xf9, 0x9f,
-	0x07, 0xfe, 0x27, 0x66, 0x0b, 0x94, 0xa4, 0x80, 0x8e, 0xb5, 0x92, 0x59, 0x7c, 0x46, 0xdf, 0x71,
-	0xb3, 0xeb, 0xd1, 0xfc, 0x12, 0x8b, 0x4b, 0x76, 0x45, 0x04, 0xb4, 0xcb, 0x30, 0xe4, 0xe5, 0x4c,
-	0x77, 0xfd, 0x8d, 0xd1, 0xec, 0x02, 0xc7, 0xe9, 0xdc, 0x5b, 0xf7, 0xbb, 0x63, 0xf7, 0x6b, 0xdf,
-	0x3e, 0xaf, 0xff, 0x01, 0x00, 0x00, 0xff, 0xff, 0xd8, 0xb4, 0x87, 0xfc, 0x7a, 0x02, 0x00, 0x00,
+	// 587 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x52, 0xcd, 0x6e, 0xd3, 0x40,
+	0x10, 0xc6, 0xcd, 0xaf, 0x27, 0x3f, 0xa4, 0x2b, 0x24, 0x56, 0x41, 0xa8, 0x51, 0x10, 0x22, 0x08,
+	0xe4, 0xd2, 0x72, 0x01, 0x24, 0x2e, 0x15, 0x20, 0x21, 0xb5, 0x1c, 0x1c, 0xb8, 0x70, 0x89, 0xd6,
+	0xce, 0xda, 0x5d, 0x65, 0xed, 0x35, 0xbb, 0x9b, 0xaa, 0xe9, 0x33, 0xf0, 0x6a, 0x3c, 0x08, 0x6f,
+	0x81, 0x3c, 0x5e, 0x5b, 0xe1, 0xd6, 0x9c, 0xec, 0xef, 0x9b, 0x6f, 0x66, 0x67, 0xbe, 0x19, 0x98,
+	0xc5, 0x52, 0xf0, 0xdc, 0x9e, 0x4a, 0x95, 0x66, 0x2a, 0x3f, 0x2d, 0xb4, 0xb2, 0xca, 0x81, 0x00,
+	0x01, 0x79, 0x76, 0xcd, 0xcc, 0xb5, 0x88, 0x95, 0x2e, 0x82, 0x5c, 0x65, 0x6c, 0x1d, 0x54, 0x19,
+	0xc1, 0xbe, 0x68, 0xfe, 0xa7, 0x0d, 0xc3, 0xa5, 0x65, 0xda, 0x86, 0xfc, 0xd7, 0x96, 0x1b, 0x4b,
+	0x1e, 0x43, 0x4f, 0xaa, 0x74, 0xb5, 0x16, 0x9a, 0x7a, 0x33, 0x6f, 0xe1, 0x87, 0x5d, 0xa9, 0xd2,
+	0x4f, 0x42, 0x93, 0x05, 0x4c, 0x8c, 0x5d, 0xab, 0xad, 0x5d, 0x25, 0x42, 0xf2, 0x55, 0xce, 0x32,
+	0x4e, 0x8f, 0x50, 0x31, 0xae, 0xf8, 0x2f, 0x42, 0xf2, 0x6f, 0x2c, 0xe3, 0x4e, 0xc9, 0xb5, 0xde,
+	0x53, 0xb6, 0x1a, 0x25, 0xd7, 0xba, 0x51, 0x3e, 0x01, 0x3f, 0x63, 0xb7, 0x28, 0x33, 0xb4, 0x3d,
+	0xf3, 0x16, 0xa3, 0xb0, 0x9f, 0xb1, 0xdb, 0x32, 0x6e, 0xc8, 0x0b, 0x98, 0xd4, 0xc1, 0x95, 0x11,
+	0x77, 0x7c, 0x95, 0x45, 0xb4, 0x83, 0x9a, 0x91, 0xd3, 0x2c, 0xc5, 0x1d, 0xbf, 0x8a, 0xc8, 0x09,
+	0x0c, 0x9a, 0xce, 0x12, 0x45, 0xbb, 0xf8, 0x14, 0xd4, 0x4d, 0x25, 0xca, 0x09, 0xaa, 0x86, 0x12,
+	0x45, 0x7b, 0x8d, 0x00, 0x7b, 0x49, 0x14, 0xb9, 0x80, 0x8e, 0x11, 0xf9, 0xc6, 0xd0, 0xfe, 0xac,
+	0xb5, 0x18, 0x9c, 0xbf, 0x0e, 0xee, 0x61, 0x5d, 0x70, 0xa9, 0xd2, 0xa5, 0xc8, 0x37, 0x61, 0x95,
+	0x4a, 0x7e, 0x40, 0x57, 0xb2, 0x88, 0x4b, 0x43, 0x7d, 0x2c, 0xf2, 0xf1, 0x5e, 0x45, 0xf6, 0xbd,
+	0x0f, 0x2e, 0x31, 0xff, 0x73, 0x6e, 0xf5, 0x2e, 0x74, 0xc5, 0xc8, 0x2b, 0x38, 0xd6, 0xca, 0x32,
+	0x2b, 0x54, 0xbe, 0x12, 0xb9, 0xe5, 0xfa, 0x86, 0x49, 0x0a, 0x33, 0x6f, 0xd1, 0x0a, 0x27, 0x75,
+	0xe0, 0xab, 0xe3, 0xc9, 0x14, 0xfa, 0xb1, 0xca, 0x0a, 0xcd, 0x8d, 0xa1, 0x83, 0x99, 0xb7, 0xe8,
+	0x87, 0x0d, 0x26, 0x2f, 0xe1, 0xb8, 0xb4, 0xd3, 0x2a, 0xcb, 0x64, 0xe3, 0xe7, 0x10, 0xfd, 0x1c,
+	0x67, 0xec, 0xf6, 0x7b, 0xc9, 0x57, 0x86, 0x4e, 0xdf, 0xc3, 0x60, 0xaf, 0x15, 0x32, 0x81, 0xd6,
+	0x86, 0xef, 0xdc, 0x39, 0x94, 0xbf, 0xe4, 0x11, 0x74, 0x6e, 0x98, 0xdc, 0xd6, 0x07, 0x50, 0x81,
+	0x0f, 0x47, 0xef, 0xbc, 0xf9, 0x43, 0x18, 0xb9, 0x91, 0x4c, 0xa1, 0x72, 0xc3, 0xe7, 0x23, 0x18,
+	0x2c, 0xad, 0x2a, 0xdc, 0x88, 0xf3, 0x31, 0x0c, 0x2b, 0xe8, 0xc2, 0xbf, 0x8f, 0xa0, 0xe7, 0x8c,
+	0x24, 0x04, 0xda, 0x76, 0x57, 0x70, 0xf7, 0x10, 0xfe, 0x13, 0x0a, 0x3d, 0xb6, 0x5e, 0xe3, 0x40,
+	0xd5, 0x5b, 0x35, 0x2c, 0x67, 0x45, 0x0b, 0x63, 0x25, 0xdd, 0x75, 0x35, 0xb8, 0x8c, 0x25, 0x2c,
+	0x16, 0x52, 0xd8, 0x1d, 0x9e, 0x95, 0x1f, 0x36, 0xb8, 0x9c, 0xc6, 0xb2, 0x14, 0x2f, 0xc9, 0x0f,
+	0xcb, 0x5f, 0xf2, 0x14, 0x20, 0x62, 0x36, 0xbe, 0x46, 0x57, 0xf0, 0x7c, 0x46, 0xa1, 0x8f, 0x4c,
+	0xe9, 0x07, 0x79, 0x0e, 0xe3, 0x2a, 0xdc, 0xd8, 0xdf, 0x43, 0xfb, 0x47, 0xc8, 0x36, 0xde, 0x9f,
+	0xc0, 0x20, 0xda, 0x26, 0x09, 0xd7, 0x55, 0x99, 0x3e, 0x96, 0x81, 0x8a, 0xc2, 0x3a, 0x73, 0x18,
+	0x46, 0x2c, 0xde, 0xe0, 0x36, 0xb6, 0x9a, 0x53, 0x1f, 0x17, 0xf4, 0x1f, 0x77, 0xfe, 0xd7, 0x83,
+	0xee, 0xa5, 0x4a, 0xaf, 0x54, 0x4e, 0x0a, 0xe8, 0xa0, 0x93, 0xe4, 0xec, 0xe0, 0x43, 0x9a, 0x9e,
+	0x1f, 0x92, 0xe2, 0x36, 0xf1, 0x80, 0x64, 0xd0, 0x2e, 0x77, 0x43, 0xde, 0xdc, 0x33, 0xbb, 0xd9,
+	0xea, 0xf4, 0xec, 0x80, 0x8c, 0xfa, 0xb9, 0x8b, 0xde, 0xcf, 0x0e, 0xf2, 0x51, 0x17, 0x3f, 0x6f,
+	0xff, 0x0d, 0x00, 0xa6, 0xd1, 0x43, 0x4e, 0xd3, 0x04, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -2,6 +2,7 @@ package logmon
 
 import (
 	"context"
+	"time"
 
 	"github.com/hashicorp/go-plugin"
 	"github.com/hashicorp/nomad/client/logmon/proto"
@@ -14,13 +15,30 @@ type logmonServer struct {
 
 func (s *logmonServer) Start(ctx context.Context, req *proto.StartRequest) (*proto.StartResponse, error) {
 	cfg := &LogConfig{
-		LogDir:        req.LogDir,
-		StdoutLogFile: req.StdoutFileName,
-		StderrLogFile: req.StderrFileName,
-		MaxFiles:      int(req.MaxFiles),
-		MaxFileSizeMB: int(req.MaxFileSizeMb),
-		StdoutFifo:    req.StdoutFifo,
-		StderrFifo:    req.StderrFifo,
+		LogDir:           req.LogDir,
+		StdoutLogFile:    req.StdoutFileName,
+		StderrLogFile:    req.StderrFileName,
+		MaxFiles:         int(req.MaxFiles),
+		MaxFileSizeMB:    int(req.MaxFileSizeMb),
+		StdoutFifo:       req.StdoutFifo,
+		StderrFifo:       req.StderrFifo,
+		Labels:           req.Labels,
+		RotationInterval: time.Duration(req.RotationInterval),
+		Compress:         req.Compress,
+		MaxTotalSizeMB:   int(req.MaxTotalSizeMb),
+	}
+	for _, s := range req.Sinks {
+		cfg.Sinks = append(cfg.Sinks, &SinkConfig{
+			Type:          s.Type,
+			Address:       s.Address,
+			Protocol:      s.Protocol,
+			Facility:      s.Facility,
+			Tag:           s.Tag,
+			BatchSize:     int(s.BatchSize),
+			BatchInterval: time.Duration(s.BatchInterval),
+			BufferSize:    int(s.BufferSize),
+			Backpressure:  s.Backpressure,
+		})
 	}
 
 	err := s.impl.Start(cfg)
@@ -0,0 +1,516 @@
+package logmon
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	hclog "github.com/hashicorp/go-hclog"
+)
+
+const (
+	SinkTypeSyslog = "syslog"
+	SinkTypeTCP    = "tcp"
+	SinkTypeUDP    = "udp"
+	SinkTypeHTTP   = "http"
+
+	// defaultSinkBufferSize is the number of lines buffered for a sink if
+	// the buffer size isn't set.
+	defaultSinkBufferSize = 1024
+
+	// defaultSinkBatchSize is the number of lines sent per http request if
+	// the batch size isn't set.
+	defaultSinkBatchSize = 100
+
+	// defaultSinkBatchInterval is the maximum time lines are held before
+	// being sent if the batch interval isn't set.
+	defaultSinkBatchInterval = time.Second
+
+	// sinkMaxAttempts is the number of times a batch is sent before being
+	// dropped, unless the sink applies backpressure.
+	sinkMaxAttempts = 3
+
+	// sinkRetryBackoff is the initial wait between attempts, doubled up to
+	// sinkMaxRetryBackoff.
+	sinkRetryBackoff    = 250 * time.Millisecond
+	sinkMaxRetryBackoff = 10 * time.Second
+
+	// sinkIOTimeout bounds dialing and writing to a sink.
+	sinkIOTimeout = 10 * time.Second
+
+	// sinkCloseTimeout is the length of time buffered lines are given to be
+	// flushed when the sink is closed.
+	sinkCloseTimeout = 5 * time.Second
+
+	// maxSinkLineSize is the size at which a line without a newline is
+	// split into multiple records.
+	maxSinkLineSize = 64 * 1024
+)
+
+// SinkConfig configures an external destination task logs are shipped to.
+type SinkConfig struct {
+	// Type is the kind of sink: syslog, tcp, udp or http
+	Type string
+
+	// Address is the host:port of the sink, or the URL for http sinks
+	Address string
+
+	// Protocol is the transport used by syslog sinks, udp or tcp
+	Protocol string
+
+	// Facility is the syslog facility, defaulting to local0
+	Facility string
+
+	// Tag is the syslog app name, defaulting to the task label
+	Tag string
+
+	// BatchSize is the maximum number of lines sent per batch
+	BatchSize int
+
+	// BatchInterval is the maximum time lines are held before being sent
+	BatchInterval time.Duration
+
+	// BufferSize is the number of lines buffered while the sink is slow or
+	// unavailable
+	BufferSize int
+
+	// Backpressure blocks the task's output while the buffer is full
+	// instead of dropping lines
+	Backpressure bool
+}
+
+// Record is a single line of task output shipped to a sink.
+type Record struct {
+	Time    time.Time         `json:"time"`
+	Stream  string            `json:"stream"`
+	Message string            `json:"message"`
+	Labels  map[string]string `json:"labels,omitempty"`
+}
+
+// shipper delivers batches of records to a sink.
+type shipper interface {
+	Ship(records []*Record) error
+	Close() error
+}
+
+// newShipper returns the shipper for the sink's type.
+func newShipper(cfg *SinkConfig, labels map[string]string) (shipper, error) {
+	switch cfg.Type {
+	case SinkTypeTCP, SinkTypeUDP:
+		return &lineShipper{
+			network: cfg.Type,
+			address: cfg.Address,
+			encode:  encodeJSONLine,
+		}, nil
+	case SinkTypeSyslog:
+		network := cfg.Protocol
+		if network == "" {
+			network = "udp"
+		}
+		encode, err := newSyslogEncoder(cfg, labels)
+		if err != nil {
+			return nil, err
+		}
+		return &lineShipper{
+			network: network,
+			address: cfg.Address,
+			encode:  encode,
+		}, nil
+	case SinkTypeHTTP:
+		return &httpShipper{
+			url:    cfg.Address,
+			client: &http.Client{Timeout: sinkIOTimeout},
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown log sink type %q", cfg.Type)
+	}
+}
+
+// lineShipper writes each record as a line over a TCP connection or as a
+// datagram over UDP. The connection is dialed lazily and redialed after
+// errors.
+type lineShipper struct {
+	network string
+	address string
+	encode  func(*Record) []byte
+	conn    net.Conn
+}
+
+func (l *lineShipper) Ship(records []*Record) error {
+	if l.conn == nil {
+		conn, err := net.DialTimeout(l.network, l.address, sinkIOTimeout)
+		if err != nil {
+			return err
+		}
+		l.conn = conn
+	}
+
+	l.conn.SetWriteDeadline(time.Now().Add(sinkIOTimeout))
+	for _, r := range records {
+		if _, err := l.conn.Write(l.encode(r)); err != nil {
+			l.conn.Close()
+			l.conn = nil
+			return err
+		}
+	}
+	return nil
+}
+
+func (l *lineShipper) Close() error {
+	if l.conn == nil {
+		return nil
+	}
+	return l.conn.Close()
+}
+
+// encodeJSONLine encodes the record as newline delimited JSON.
+func encodeJSONLine(r *Record) []byte {
+	buf, _ := json.Marshal(r)
+	return append(buf, '\n')
+}
+
+// syslogFacilities maps facility names to their syslog codes.
+var syslogFacilities = map[string]int{
+	"kern": 0, "user": 1, "mail": 2, "daemon": 3, "auth": 4, "syslog": 5,
+	"lpr": 6, "news": 7, "uucp": 8, "cron": 9, "authpriv": 10, "ftp": 11,
+	"local0": 16, "local1": 17, "local2": 18, "local3": 19,
+	"local4": 20, "local5": 21, "local6": 22, "local7": 23,
+}
+
+const (
+	syslogSeverityErr  = 3
+	syslogSeverityInfo = 6
+)
+
+// newSyslogEncoder returns an encoder formatting records as RFC 5424 syslog
+// messages. Lines from stderr are sent with the err severity and lines from
+// stdout with info.
+func newSyslogEncoder(cfg *SinkConfig, labels map[string]string) (func(*Record) []byte, error) {
+	facilityName := cfg.Facility
+	if facilityName == "" {
+		facilityName = "local0"
+	}
+	facility, ok := syslogFacilities[facilityName]
+	if !ok {
+		return nil, fmt.Errorf("unknown syslog facility %q", cfg.Facility)
+	}
+
+	tag := cfg.Tag
+	if tag == "" {
+		tag = labels["task"]
+	}
+	if tag == "" {
+		tag = "nomad"
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "-"
+	}
+
+	return func(r *Record) []byte {
+		severity := syslogSeverityInfo
+		if r.Stream == "stderr" {
+			severity = syslogSeverityErr
+		}
+		return []byte(fmt.Sprintf("<%d>1 %s %s %s - %s - %s\n",
+			facility*8+severity, r.Time.Format(time.RFC3339Nano),
+			hostname, tag, r.Stream, r.Message))
+	}, nil
+}
+
+// httpShipper posts each batch as a JSON array of records.
+type httpShipper struct {
+	url    string
+	client *http.Client
+}
+
+func (h *httpShipper) Ship(records []*Record) error {
+	body, err := json.Marshal(records)
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.client.Post(h.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected response code %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (h *httpShipper) Close() error {
+	h.client.CloseIdleConnections()
+	return nil
+}
+
+// logSink buffers records and ships them to an external destination in the
+// background so a slow or unavailable sink never blocks the task unless
+// backpressure is enabled.
+type logSink struct {
+	config  *SinkConfig
+	shipper shipper
+	logger  hclog.Logger
+
+	queue   chan *Record
+	dropped uint64
+
+	batchSize     int
+	batchInterval time.Duration
+
+	// stopCh is closed to flush the buffered records and stop the sink,
+	// killCh is closed to abandon retrying once the close timeout passes.
+	stopCh    chan struct{}
+	killCh    chan struct{}
+	doneCh    chan struct{}
+	closeOnce sync.Once
+}
+
+func newLogSink(cfg *SinkConfig, labels map[string]string, logger hclog.Logger) (*logSink, error) {
+	shipper, err := newShipper(cfg, labels)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &logSink{
+		config:        cfg,
+		shipper:       shipper,
+		logger:        logger.With("sink", cfg.Type, "address", cfg.Address),
+		batchSize:     cfg.BatchSize,
+		batchInterval: cfg.BatchInterval,
+		stopCh:        make(chan struct{}),
+		killCh:        make(chan struct{}),
+		doneCh:        make(chan struct{}),
+	}
+
+	bufferSize := cfg.BufferSize
+	if bufferSize <= 0 {
+		bufferSize = defaultSinkBufferSize
+	}
+	s.queue = make(chan *Record, bufferSize)
+
+	if s.batchSize <= 0 {
+		s.batchSize = defaultSinkBatchSize
+	}
+	if s.batchInterval <= 0 {
+		s.batchInterval = defaultSinkBatchInterval
+	}
+
+	go s.run()
+	return s, nil
+}
+
+// Send buffers the record to be shipped. If the buffer is full the record is
+// dropped, or with backpressure Send blocks until there is room.
+func (s *logSink) Send(r *Record) {
+	if s.config.Backpressure {
+		select {
+		case s.queue <- r:
+		case <-s.stopCh:
+		}
+		return
+	}
+
+	select {
+	case s.queue <- r:
+	default:
+		atomic.AddUint64(&s.dropped, 1)
+	}
+}
+
+// Dropped returns the number of records that were never shipped.
+func (s *logSink) Dropped() uint64 {
+	return atomic.LoadUint64(&s.dropped)
+}
+
+// run batches buffered records and ships them. Batches are sent once full or
+// once the batch interval passes. Line based sinks also send as soon as the
+// buffer is drained since each record is written individually anyway.
+func (s *logSink) run() {
+	defer close(s.doneCh)
+
+	ticker := time.NewTicker(s.batchInterval)
+	defer ticker.Stop()
+
+	flushWhenIdle := s.config.Type != SinkTypeHTTP
+	var batch []*Record
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		s.ship(batch)
+		batch = nil
+	}
+
+	for {
+		select {
+		case r := <-s.queue:
+			batch = append(batch, r)
+			if len(batch) >= s.batchSize || (flushWhenIdle && len(s.queue) == 0) {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-s.stopCh:
+			for {
+				select {
+				case r := <-s.queue:
+					batch = append(batch, r)
+					if len(batch) >= s.batchSize {
+						flush()
+					}
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
+
+// ship sends the batch, retrying with backoff. Without backpressure the batch
+// is dropped after sinkMaxAttempts, otherwise it is retried until the sink is
+// killed.
+func (s *logSink) ship(batch []*Record) {
+	backoff := sinkRetryBackoff
+	for attempt := 1; ; attempt++ {
+		err := s.shipper.Ship(batch)
+		if err == nil {
+			return
+		}
+
+		if !s.config.Backpressure && attempt >= sinkMaxAttempts {
+			s.logger.Warn("failed to ship logs, dropping lines", "lines", len(batch), "error", err)
+			atomic.AddUint64(&s.dropped, uint64(len(batch)))
+			return
+		}
+		s.logger.Debug("failed to ship logs, retrying", "attempt", attempt, "error", err)
+
+		select {
+		case <-time.After(backoff):
+		case <-s.killCh:
+			atomic.AddUint64(&s.dropped, uint64(len(batch)))
+			return
+		}
+		backoff *= 2
+		if backoff > sinkMaxRetryBackoff {
+			backoff = sinkMaxRetryBackoff
+		}
+	}
+}
+
+// Close flushes the buffered records, waiting up to sinkCloseTimeout before
+// abandoning them, and closes the sink.
+func (s *logSink) Close() {
+	s.closeOnce.Do(func() {
+		close(s.stopCh)
+		select {
+		case <-s.doneCh:
+		case <-time.After(sinkCloseTimeout):
+			s.logger.Warn("timed out flushing logs to sink")
+			close(s.killCh)
+			<-s.doneCh
+		}
+
+		if dropped := s.Dropped(); dropped > 0 {
+			s.logger.Warn("log lines were dropped", "dropped", dropped)
+		}
+		if err := s.shipper.Close(); err != nil {
+			s.logger.Debug("error closing sink", "error", err)
+		}
+	})
+}
+
+// sinkWriter splits a stream of task output into lines and sends a record
+// for each to every sink.
+type sinkWriter struct {
+	stream string
+	labels map[string]string
+	sinks  []*logSink
+	buf    []byte
+}
+
+func newSinkWriter(stream string, labels map[string]string, sinks []*logSink) *sinkWriter {
+	return &sinkWriter{
+		stream: stream,
+		labels: labels,
+		sinks:  sinks,
+	}
+}
+
+func (w *sinkWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		w.send(w.buf[:i])
+		w.buf = w.buf[i+1:]
+	}
+
+	for len(w.buf) >= maxSinkLineSize {
+		w.send(w.buf[:maxSinkLineSize])
+		w.buf = w.buf[maxSinkLineSize:]
+	}
+
+	// Avoid holding on to the underlying array of large writes
+	if len(w.buf) == 0 {
+		w.buf = nil
+	}
+	return len(p), nil
+}
+
+// Close sends any remaining partial line.
+func (w *sinkWriter) Close() error {
+	if len(w.buf) > 0 {
+		w.send(w.buf)
+		w.buf = nil
+	}
+	return nil
+}
+
+func (w *sinkWriter) send(line []byte) {
+	r := &Record{
+		Time:    time.Now().UTC(),
+		Stream:  w.stream,
+		Message: string(bytes.TrimSuffix(line, []byte{'\r'})),
+		Labels:  w.labels,
+	}
+	for _, s := range w.sinks {
+		s.Send(r)
+	}
+}
+
+// teeWriteCloser writes to the log rotator and then to the sinks, closing both
+// when closed.
+type teeWriteCloser struct {
+	rotator io.WriteCloser
+	sinks   io.WriteCloser
+}
+
+func (t *teeWriteCloser) Write(p []byte) (int, error) {
+	n, err := t.rotator.Write(p)
+	if err != nil {
+		return n, err
+	}
+	t.sinks.Write(p)
+	return n, nil
+}
+
+func (t *teeWriteCloser) Close() error {
+	t.sinks.Close()
+	return t.rotator.Close()
+}
@@ -0,0 +1,291 @@
+package logmon
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/client/lib/fifo"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+// testHTTPSink is a stand-in for an HTTP log collector that records the
+// batches it receives.
+type testHTTPSink struct {
+	srv *httptest.Server
+
+	lock    sync.Mutex
+	batches [][]*Record
+	fail    int
+}
+
+func newTestHTTPSink(t *testing.T) *testHTTPSink {
+	s := &testHTTPSink{}
+	s.srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.lock.Lock()
+		defer s.lock.Unlock()
+
+		if s.fail > 0 {
+			s.fail--
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		var batch []*Record
+		if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		s.batches = append(s.batches, batch)
+	}))
+	t.Cleanup(s.srv.Close)
+	return s
+}
+
+func (s *testHTTPSink) messages() []string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var out []string
+	for _, batch := range s.batches {
+		for _, r := range batch {
+			out = append(out, r.Message)
+		}
+	}
+	return out
+}
+
+func TestLogSink_HTTP(t *testing.T) {
+	ci.Parallel(t)
+
+	collector := newTestHTTPSink(t)
+	collector.fail = 1
+
+	labels := map[string]string{"task": "web"}
+	sink, err := newLogSink(&SinkConfig{
+		Type:          SinkTypeHTTP,
+		Address:       collector.srv.URL,
+		BatchSize:     2,
+		BatchInterval: time.Hour,
+	}, labels, testlog.HCLogger(t))
+	require.NoError(t, err)
+
+	w := newSinkWriter("stdout", labels, []*logSink{sink})
+	_, err = w.Write([]byte("one\ntwo\nthr"))
+	require.NoError(t, err)
+	_, err = w.Write([]byte("ee"))
+	require.NoError(t, err)
+
+	// The first batch is sent once full, and retried after the failure
+	testutil.WaitForResult(func() (bool, error) {
+		msgs := collector.messages()
+		return len(msgs) == 2, fmt.Errorf("expected 2 messages, got %v", msgs)
+	}, func(err error) {
+		require.NoError(t, err)
+	})
+
+	// The partial line is flushed on close
+	require.NoError(t, w.Close())
+	sink.Close()
+	require.Equal(t, []string{"one", "two", "three"}, collector.messages())
+	require.Zero(t, sink.Dropped())
+
+	collector.lock.Lock()
+	defer collector.lock.Unlock()
+	require.Equal(t, "stdout", collector.batches[0][0].Stream)
+	require.Equal(t, labels, collector.batches[0][0].Labels)
+}
+
+func TestLogSink_Dropped(t *testing.T) {
+	ci.Parallel(t)
+
+	// Hold every request until the test finishes so the buffer fills up
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer srv.Close()
+
+	sink, err := newLogSink(&SinkConfig{
+		Type:          SinkTypeHTTP,
+		Address:       srv.URL,
+		BatchSize:     1,
+		BatchInterval: time.Millisecond,
+		BufferSize:    1,
+	}, nil, testlog.HCLogger(t))
+	require.NoError(t, err)
+
+	for i := 0; i < 10; i++ {
+		sink.Send(&Record{Message: fmt.Sprintf("line %d", i)})
+	}
+	require.NotZero(t, sink.Dropped())
+
+	close(release)
+	sink.Close()
+}
+
+func TestLogSink_TCP(t *testing.T) {
+	ci.Parallel(t)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	lines := make(chan string, 10)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}()
+
+	sink, err := newLogSink(&SinkConfig{
+		Type:    SinkTypeTCP,
+		Address: ln.Addr().String(),
+	}, nil, testlog.HCLogger(t))
+	require.NoError(t, err)
+	defer sink.Close()
+
+	w := newSinkWriter("stderr", map[string]string{"alloc_id": "abc"}, []*logSink{sink})
+	_, err = w.Write([]byte("hello\r\nworld\n"))
+	require.NoError(t, err)
+
+	for _, expected := range []string{"hello", "world"} {
+		select {
+		case line := <-lines:
+			var r Record
+			require.NoError(t, json.Unmarshal([]byte(line), &r))
+			require.Equal(t, expected, r.Message)
+			require.Equal(t, "stderr", r.Stream)
+			require.Equal(t, "abc", r.Labels["alloc_id"])
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for %q", expected)
+		}
+	}
+}
+
+func TestLogSink_Syslog(t *testing.T) {
+	ci.Parallel(t)
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	sink, err := newLogSink(&SinkConfig{
+		Type:     SinkTypeSyslog,
+		Address:  conn.LocalAddr().String(),
+		Facility: "local1",
+	}, map[string]string{"task": "web"}, testlog.HCLogger(t))
+	require.NoError(t, err)
+	defer sink.Close()
+
+	sink.Send(&Record{Time: time.Now(), Stream: "stdout", Message: "hello"})
+	sink.Send(&Record{Time: time.Now(), Stream: "stderr", Message: "oops"})
+
+	// local1 is facility 17, info is severity 6 and err is severity 3
+	for _, expected := range []string{"<142>1 ", "<139>1 "} {
+		buf := make([]byte, 1024)
+		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		n, _, err := conn.ReadFrom(buf)
+		require.NoError(t, err)
+
+		msg := string(buf[:n])
+		require.True(t, strings.HasPrefix(msg, expected), "unexpected message %q", msg)
+		require.Contains(t, msg, " web - ")
+	}
+}
+
+func TestLogSink_UnknownType(t *testing.T) {
+	ci.Parallel(t)
+
+	_, err := newLogSink(&SinkConfig{Type: "kafka"}, nil, testlog.HCLogger(t))
+	require.EqualError(t, err, `unknown log sink type "kafka"`)
+}
+
+// asserts that logs are shipped to the sinks and still written to the rotated
+// log files.
+func TestLogmon_Start_sinks(t *testing.T) {
+	ci.Parallel(t)
+
+	var stdoutFifoPath, stderrFifoPath string
+
+	dir := t.TempDir()
+
+	if runtime.GOOS == "windows" {
+		stdoutFifoPath = "//./pipe/test-sinks.stdout"
+		stderrFifoPath = "//./pipe/test-sinks.stderr"
+	} else {
+		stdoutFifoPath = filepath.Join(dir, "stdout.fifo")
+		stderrFifoPath = filepath.Join(dir, "stderr.fifo")
+	}
+
+	collector := newTestHTTPSink(t)
+	cfg := &LogConfig{
+		LogDir:        dir,
+		StdoutLogFile: "stdout",
+		StdoutFifo:    stdoutFifoPath,
+		StderrLogFile: "stderr",
+		StderrFifo:    stderrFifoPath,
+		MaxFiles:      2,
+		MaxFileSizeMB: 1,
+		Sinks: []*SinkConfig{{
+			Type:          SinkTypeHTTP,
+			Address:       collector.srv.URL,
+			BatchInterval: 10 * time.Millisecond,
+		}},
+		Labels: map[string]string{"task": "web"},
+	}
+
+	lm := NewLogMon(testlog.HCLogger(t))
+	require.NoError(t, lm.Start(cfg))
+
+	stdout, err := fifo.OpenWriter(stdoutFifoPath)
+	require.NoError(t, err)
+	stderr, err := fifo.OpenWriter(stderrFifoPath)
+	require.NoError(t, err)
+
+	_, err = stdout.Write([]byte("hello from the task\n"))
+	require.NoError(t, err)
+
+	testutil.WaitForResult(func() (bool, error) {
+		msgs := collector.messages()
+		if len(msgs) != 1 || msgs[0] != "hello from the task" {
+			return false, fmt.Errorf("unexpected messages %v", msgs)
+		}
+		raw, err := os.ReadFile(filepath.Join(dir, "stdout.0"))
+		if err != nil {
+			return false, err
+		}
+		if string(raw) != "hello from the task\n" {
+			return false, fmt.Errorf("unexpected log file contents %q", raw)
+		}
+		return true, nil
+	}, func(err error) {
+		require.NoError(t, err)
+	})
+
+	require.NoError(t, stdout.Close())
+	require.NoError(t, stderr.Close())
+	require.NoError(t, lm.Stop())
+
+	_, err = os.Stat(filepath.Join(dir, "stderr.0"))
+	require.NoError(t, err)
+}
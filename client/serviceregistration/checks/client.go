@@ -3,6 +3,7 @@ package checks
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
 	"fmt"
 	"io"
 	"net"
@@ -14,8 +15,13 @@ import (
 
 	"github.com/hashicorp/go-cleanhttp"
 	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/client/allocrunner/taskrunner/interfaces"
 	"github.com/hashicorp/nomad/client/serviceregistration"
 	"github.com/hashicorp/nomad/nomad/structs"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"oss.indeed.com/go/libtime"
 )
 
@@ -31,7 +37,8 @@ type Checker interface {
 	Do(context.Context, *QueryContext, *Query) *structs.CheckQueryResult
 }
 
-// New creates a new Checker capable of executing HTTP and TCP checks.
+// New creates a new Checker capable of executing HTTP, TCP, gRPC and script
+// checks.
 func New(log hclog.Logger) Checker {
 	httpClient := cleanhttp.DefaultPooledClient()
 	httpClient.Timeout = maxTimeoutHTTP
@@ -60,8 +67,12 @@ func (c *checker) Do(ctx context.Context, qc *QueryContext, q *Query) *structs.C
 	defer cancel()
 
 	switch q.Type {
-	case "http":
+	case structs.ServiceCheckHTTP:
 		qr = c.checkHTTP(timeout, qc, q)
+	case structs.ServiceCheckGRPC:
+		qr = c.checkGRPC(timeout, qc, q)
+	case structs.ServiceCheckScript:
+		qr = c.checkScript(timeout, qc, q)
 	default:
 		qr = c.checkTCP(timeout, qc, q)
 	}
@@ -195,9 +206,129 @@ func (c *checker) checkHTTP(ctx context.Context, qc *QueryContext, q *Query) *st
 	return qr
 }
 
+func (c *checker) checkGRPC(ctx context.Context, qc *QueryContext, q *Query) *structs.CheckQueryResult {
+	qr := &structs.CheckQueryResult{
+		Mode:      q.Mode,
+		Timestamp: c.now(),
+		Status:    structs.CheckPending,
+	}
+
+	addr, err := address(qc, q)
+	if err != nil {
+		qr.Output = err.Error()
+		qr.Status = structs.CheckFailure
+		return qr
+	}
+
+	creds := insecure.NewCredentials()
+	if q.GRPCUseTLS {
+		creds = credentials.NewTLS(&tls.Config{
+			InsecureSkipVerify: q.TLSSkipVerify,
+		})
+	}
+
+	conn, err := grpc.DialContext(ctx, addr, grpc.WithTransportCredentials(creds), grpc.WithBlock())
+	if err != nil {
+		qr.Output = fmt.Sprintf("nomad: %s", err.Error())
+		qr.Status = structs.CheckFailure
+		return qr
+	}
+	defer func() { _ = conn.Close() }()
+
+	// use the standard grpc health checking protocol
+	// https://github.com/grpc/grpc/blob/master/doc/health-checking.md
+	request := &healthpb.HealthCheckRequest{Service: q.GRPCService}
+	result, err := healthpb.NewHealthClient(conn).Check(ctx, request)
+	if err != nil {
+		qr.Output = fmt.Sprintf("nomad: %s", err.Error())
+		qr.Status = structs.CheckFailure
+		return qr
+	}
+
+	if status := result.GetStatus(); status != healthpb.HealthCheckResponse_SERVING {
+		qr.Output = fmt.Sprintf("nomad: grpc status %s", status)
+		qr.Status = structs.CheckFailure
+		return qr
+	}
+
+	qr.Output = "nomad: grpc ok"
+	qr.Status = structs.CheckSuccess
+	return qr
+}
+
+// execResult contains the outputs of a script check execution
+type execResult struct {
+	output []byte
+	code   int
+	err    error
+}
+
+// ExecScript runs cmd with the executor until the timeout expires, the context
+// is canceled, or the executor returns. It is shared by Nomad and Consul script
+// checks, which can't trust the task driver to obey the timeout.
+func ExecScript(ctx context.Context, exec interfaces.ScriptExecutor, timeout time.Duration, cmd string, args []string) ([]byte, int, error) {
+	resultCh := make(chan execResult, 1)
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	go func() {
+		output, code, err := exec.Exec(timeout, cmd, args)
+		select {
+		case resultCh <- execResult{output: output, code: code, err: err}:
+		case <-ctx.Done():
+		}
+	}()
+
+	select {
+	case result := <-resultCh:
+		return result.output, result.code, result.err
+	case <-ctx.Done():
+		return nil, 0, ctx.Err()
+	}
+}
+
+func (c *checker) checkScript(ctx context.Context, qc *QueryContext, q *Query) *structs.CheckQueryResult {
+	qr := &structs.CheckQueryResult{
+		Mode:      q.Mode,
+		Timestamp: c.now(),
+		Status:    structs.CheckPending,
+	}
+
+	if qc.TaskExec == nil {
+		qr.Output = "nomad: script checks require a task"
+		qr.Status = structs.CheckFailure
+		return qr
+	}
+
+	exec := qc.TaskExec()
+	if exec == nil {
+		qr.Output = "nomad: task is not running"
+		qr.Status = structs.CheckFailure
+		return qr
+	}
+
+	output, code, err := ExecScript(ctx, exec, q.Timeout, q.Command, q.Args)
+	switch {
+	case err != nil:
+		qr.Output = fmt.Sprintf("nomad: %s", err.Error())
+		qr.Status = structs.CheckFailure
+	case code == 0:
+		qr.Output = limitRead(bytes.NewReader(output))
+		qr.Status = structs.CheckSuccess
+	default:
+		// nomad checks do not have warnings, so any non-zero exit code is
+		// considered a failure
+		qr.Output = limitRead(bytes.NewReader(output))
+		qr.Status = structs.CheckFailure
+	}
+
+	return qr
+}
+
 const (
 	// outputSizeLimit is the maximum number of bytes to read and store of an http
-	// check output. Set to 3kb which fits in 1 page with room for other fields.
+	// or script check output. Set to 3kb which fits in 1 page with room for other fields.
 	outputSizeLimit = 3 * 1024
 )
 
@@ -12,12 +12,16 @@ import (
 	"time"
 
 	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/client/allocrunner/taskrunner/interfaces"
 	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/helper/freeport"
 	"github.com/hashicorp/nomad/helper/testlog"
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/shoenig/test/must"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"oss.indeed.com/go/libtime/libtimetest"
 )
 
@@ -469,3 +473,202 @@ func tcpServer(t *testing.T, ctx context.Context, port int) {
 		}
 	}()
 }
+
+func TestChecker_Do_GRPC(t *testing.T) {
+	ci.Parallel(t)
+
+	// create a mock clock so we can assert time is set
+	now := time.Date(2022, 1, 2, 3, 4, 5, 6, time.UTC)
+	clock := libtimetest.NewClockMock(t).NowMock.Return(now)
+
+	// create a grpc server implementing the health checking protocol
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	must.NoError(t, err)
+
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("ok", healthpb.HealthCheckResponse_SERVING)
+	healthServer.SetServingStatus("sick", healthpb.HealthCheckResponse_NOT_SERVING)
+
+	server := grpc.NewServer()
+	healthpb.RegisterHealthServer(server, healthServer)
+	go func() { _ = server.Serve(listener) }()
+	defer server.Stop()
+
+	addr, port := splitURL("http://" + listener.Addr().String())
+
+	makeQueryContext := func() *QueryContext {
+		return &QueryContext{
+			ID:               "abc123",
+			CustomAddress:    addr,
+			ServicePortLabel: port,
+			Networks:         nil,
+			NetworkStatus:    mock.NewNetworkStatus(addr),
+			Ports:            nil,
+			Group:            "group",
+			Task:             "task",
+			Service:          "service",
+			Check:            "check",
+		}
+	}
+
+	makeQuery := func(service string) *Query {
+		return &Query{
+			Mode:        structs.Healthiness,
+			Type:        "grpc",
+			Timeout:     1 * time.Second,
+			AddressMode: "auto",
+			PortLabel:   port,
+			GRPCService: service,
+		}
+	}
+
+	makeExpResult := func(status structs.CheckStatus, output string) *structs.CheckQueryResult {
+		return &structs.CheckQueryResult{
+			ID:        "abc123",
+			Mode:      structs.Healthiness,
+			Status:    status,
+			Output:    output,
+			Timestamp: now.Unix(),
+			Group:     "group",
+			Task:      "task",
+			Service:   "service",
+			Check:     "check",
+		}
+	}
+
+	cases := []struct {
+		name      string
+		q         *Query
+		expResult *structs.CheckQueryResult
+	}{{
+		name:      "grpc server ok",
+		q:         makeQuery(""),
+		expResult: makeExpResult(structs.CheckSuccess, "nomad: grpc ok"),
+	}, {
+		name:      "grpc service ok",
+		q:         makeQuery("ok"),
+		expResult: makeExpResult(structs.CheckSuccess, "nomad: grpc ok"),
+	}, {
+		name:      "grpc service not serving",
+		q:         makeQuery("sick"),
+		expResult: makeExpResult(structs.CheckFailure, "nomad: grpc status NOT_SERVING"),
+	}, {
+		name:      "grpc service unknown",
+		q:         makeQuery("unknown"),
+		expResult: makeExpResult(structs.CheckFailure, "nomad: rpc error: code = NotFound desc = unknown service"),
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			logger := testlog.HCLogger(t)
+
+			c := New(logger)
+			c.(*checker).clock = clock
+
+			ctx := context.Background()
+			result := c.Do(ctx, makeQueryContext(), tc.q)
+			must.Eq(t, tc.expResult, result)
+		})
+	}
+}
+
+// mockExec is a mock interfaces.ScriptExecutor
+type mockExec struct {
+	output []byte
+	code   int
+	err    error
+	delay  time.Duration
+}
+
+func (m *mockExec) Exec(_ time.Duration, _ string, _ []string) ([]byte, int, error) {
+	time.Sleep(m.delay)
+	return m.output, m.code, m.err
+}
+
+func TestChecker_Do_Script(t *testing.T) {
+	ci.Parallel(t)
+
+	// create a mock clock so we can assert time is set
+	now := time.Date(2022, 1, 2, 3, 4, 5, 6, time.UTC)
+	clock := libtimetest.NewClockMock(t).NowMock.Return(now)
+
+	makeQueryContext := func(exec interfaces.ScriptExecutor) *QueryContext {
+		return &QueryContext{
+			ID: "abc123",
+			TaskExec: func() interfaces.ScriptExecutor {
+				return exec
+			},
+			Group:   "group",
+			Task:    "task",
+			Service: "service",
+			Check:   "check",
+		}
+	}
+
+	makeQuery := func() *Query {
+		return &Query{
+			Mode:    structs.Healthiness,
+			Type:    "script",
+			Timeout: 100 * time.Millisecond,
+			Command: "/bin/check",
+			Args:    []string{"-v"},
+		}
+	}
+
+	makeExpResult := func(status structs.CheckStatus, output string) *structs.CheckQueryResult {
+		return &structs.CheckQueryResult{
+			ID:        "abc123",
+			Mode:      structs.Healthiness,
+			Status:    status,
+			Output:    output,
+			Timestamp: now.Unix(),
+			Group:     "group",
+			Task:      "task",
+			Service:   "service",
+			Check:     "check",
+		}
+	}
+
+	cases := []struct {
+		name      string
+		exec      interfaces.ScriptExecutor
+		expResult *structs.CheckQueryResult
+	}{{
+		name:      "script ok",
+		exec:      &mockExec{output: []byte("all good"), code: 0},
+		expResult: makeExpResult(structs.CheckSuccess, "all good"),
+	}, {
+		name:      "script warning",
+		exec:      &mockExec{output: []byte("not great"), code: 1},
+		expResult: makeExpResult(structs.CheckFailure, "not great"),
+	}, {
+		name:      "script critical",
+		exec:      &mockExec{output: []byte("oh no"), code: 2},
+		expResult: makeExpResult(structs.CheckFailure, "oh no"),
+	}, {
+		name:      "script exec error",
+		exec:      &mockExec{err: fmt.Errorf("exec not supported")},
+		expResult: makeExpResult(structs.CheckFailure, "nomad: exec not supported"),
+	}, {
+		name:      "script timeout",
+		exec:      &mockExec{output: []byte("too slow"), delay: 1 * time.Second},
+		expResult: makeExpResult(structs.CheckFailure, "nomad: context deadline exceeded"),
+	}, {
+		name:      "task not running",
+		exec:      nil,
+		expResult: makeExpResult(structs.CheckFailure, "nomad: task is not running"),
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			logger := testlog.HCLogger(t)
+
+			c := New(logger)
+			c.(*checker).clock = clock
+
+			ctx := context.Background()
+			result := c.Do(ctx, makeQueryContext(tc.exec), makeQuery())
+			must.Eq(t, tc.expResult, result)
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/hashicorp/nomad/client/allocrunner/taskrunner/interfaces"
 	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/nomad/structs"
 )
@@ -15,16 +16,21 @@ func GetCheckQuery(c *structs.ServiceCheck) *Query {
 		protocol = "http"
 	}
 	return &Query{
-		Mode:        structs.GetCheckMode(c),
-		Type:        c.Type,
-		Timeout:     c.Timeout,
-		AddressMode: c.AddressMode,
-		PortLabel:   c.PortLabel,
-		Protocol:    protocol,
-		Path:        c.Path,
-		Method:      c.Method,
-		Headers:     helper.CopyMap(c.Header),
-		Body:        c.Body,
+		Mode:          structs.GetCheckMode(c),
+		Type:          c.Type,
+		Timeout:       c.Timeout,
+		AddressMode:   c.AddressMode,
+		PortLabel:     c.PortLabel,
+		Protocol:      protocol,
+		Path:          c.Path,
+		Method:        c.Method,
+		Headers:       helper.CopyMap(c.Header),
+		Body:          c.Body,
+		GRPCService:   c.GRPCService,
+		GRPCUseTLS:    c.GRPCUseTLS,
+		TLSSkipVerify: c.TLSSkipVerify,
+		Command:       c.Command,
+		Args:          helper.CopySliceString(c.Args),
 	}
 }
 
@@ -32,7 +38,7 @@ func GetCheckQuery(c *structs.ServiceCheck) *Query {
 // amount of information needed to actually execute that check.
 type Query struct {
 	Mode structs.CheckMode // readiness or healthiness
-	Type string            // tcp or http
+	Type string            // tcp, http, grpc, or script
 
 	Timeout time.Duration // connection / request timeout
 
@@ -44,6 +50,13 @@ type Query struct {
 	Method   string      // http checks only
 	Headers  http.Header // http checks only
 	Body     string      // http checks only
+
+	GRPCService   string // grpc checks only
+	GRPCUseTLS    bool   // grpc checks only
+	TLSSkipVerify bool   // grpc checks only
+
+	Command string   // script checks only
+	Args    []string // script checks only
 }
 
 // A QueryContext contains allocation and service parameters necessary for
@@ -56,6 +69,10 @@ type QueryContext struct {
 	NetworkStatus    structs.NetworkStatus
 	Ports            structs.AllocatedPorts
 
+	// TaskExec returns the executor of the task in which a script check is
+	// run, or nil if the task is not running. Script checks only.
+	TaskExec func() interfaces.ScriptExecutor
+
 	Group   string
 	Task    string
 	Service string
@@ -87,6 +87,31 @@ func TestChecks_GetCheckQuery(t *testing.T) {
 	}
 }
 
+func TestChecks_GetCheckQuery_GRPCScript(t *testing.T) {
+	grpcQuery := GetCheckQuery(&structs.ServiceCheck{
+		Type:          "grpc",
+		PortLabel:     "web",
+		Interval:      10 * time.Second,
+		Timeout:       2 * time.Second,
+		GRPCService:   "health",
+		GRPCUseTLS:    true,
+		TLSSkipVerify: true,
+	})
+	must.Eq(t, "health", grpcQuery.GRPCService)
+	must.True(t, grpcQuery.GRPCUseTLS)
+	must.True(t, grpcQuery.TLSSkipVerify)
+
+	scriptQuery := GetCheckQuery(&structs.ServiceCheck{
+		Type:     "script",
+		Interval: 10 * time.Second,
+		Timeout:  2 * time.Second,
+		Command:  "/bin/check",
+		Args:     []string{"-a", "-b"},
+	})
+	must.Eq(t, "/bin/check", scriptQuery.Command)
+	must.Eq(t, []string{"-a", "-b"}, scriptQuery.Args)
+}
+
 func TestChecks_Stub(t *testing.T) {
 	now := time.Date(2020, 1, 2, 3, 4, 5, 6, time.UTC).Unix()
 	result := Stub(
@@ -8,6 +8,8 @@ import (
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/nomad/client/serviceregistration"
+	"github.com/hashicorp/nomad/client/serviceregistration/checks/checkstore"
+	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/nomad/structs"
 )
 
@@ -49,6 +51,12 @@ type ServiceRegistrationHandlerCfg struct {
 	// server service registration RPC calls. This RPC function has basic retry
 	// functionality.
 	RPCFn func(method string, args, resp interface{}) error
+
+	// CheckStore contains the latest check results of the Nomad service
+	// checks running on this client. The results are included within service
+	// registrations, so the servers do not consider a newly registered service
+	// healthy before its checks have run.
+	CheckStore checkstore.Shim
 }
 
 // NewServiceRegistrationHandler returns a ready to use
@@ -289,6 +297,15 @@ func (s *ServiceRegistrationHandler) generateNomadServiceRegistration(
 		copy(tags, serviceSpec.Tags)
 	}
 
+	// Determine whether to use meta or canary_meta, in the same way as the
+	// Consul provider.
+	var meta map[string]string
+	if workload.Canary && len(serviceSpec.CanaryMeta) > 0 {
+		meta = helper.CopyMapStringString(serviceSpec.CanaryMeta)
+	} else {
+		meta = helper.CopyMapStringString(serviceSpec.Meta)
+	}
+
 	return &structs.ServiceRegistration{
 		ID:          serviceregistration.MakeAllocServiceID(workload.AllocID, workload.Name(), serviceSpec),
 		ServiceName: serviceSpec.Name,
@@ -298,7 +315,41 @@ func (s *ServiceRegistrationHandler) generateNomadServiceRegistration(
 		Namespace:   workload.Namespace,
 		Datacenter:  s.cfg.Datacenter,
 		Tags:        tags,
+		Meta:        meta,
+		Weight:      serviceSpec.Weight,
 		Address:     ip,
 		Port:        port,
+		Checks:      s.generateNomadServiceRegistrationChecks(serviceSpec, workload),
 	}, nil
 }
+
+// generateNomadServiceRegistrationChecks builds the check results to include
+// within a service registration. Checks which do not have a result within the
+// check store are marked as pending.
+func (s *ServiceRegistrationHandler) generateNomadServiceRegistrationChecks(
+	serviceSpec *structs.Service, workload *serviceregistration.WorkloadServices) []*structs.ServiceRegistrationCheck {
+
+	if len(serviceSpec.Checks) == 0 {
+		return nil
+	}
+
+	var results map[structs.CheckID]*structs.CheckQueryResult
+	if s.cfg.CheckStore != nil {
+		results = s.cfg.CheckStore.List(workload.AllocID)
+	}
+
+	checks := make([]*structs.ServiceRegistrationCheck, len(serviceSpec.Checks))
+	for i, check := range serviceSpec.Checks {
+		id := structs.NomadCheckID(workload.AllocID, workload.Group, check)
+		if result, ok := results[id]; ok {
+			checks[i] = result.ToServiceRegistrationCheck()
+			continue
+		}
+		checks[i] = &structs.ServiceRegistrationCheck{
+			Name:   check.Name,
+			Mode:   structs.GetCheckMode(check),
+			Status: structs.CheckPending,
+		}
+	}
+	return checks
+}
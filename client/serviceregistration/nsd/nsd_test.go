@@ -487,6 +487,61 @@ func TestServiceRegistrationHandler_dedupUpdatedWorkload(t *testing.T) {
 	}
 }
 
+func TestServiceRegistrationHandler_generateNomadServiceRegistration(t *testing.T) {
+	testCases := []struct {
+		canary         bool
+		expectedTags   []string
+		expectedMeta   map[string]string
+		expectedWeight int
+		name           string
+	}{
+		{
+			canary:         false,
+			expectedTags:   []string{"stable"},
+			expectedMeta:   map[string]string{"version": "1"},
+			expectedWeight: 10,
+			name:           "not canary",
+		},
+		{
+			canary:         true,
+			expectedTags:   []string{"canary"},
+			expectedMeta:   map[string]string{"version": "2"},
+			expectedWeight: 10,
+			name:           "canary",
+		},
+	}
+
+	h := NewServiceRegistrationHandler(hclog.NewNullLogger(), &ServiceRegistrationHandlerCfg{
+		Enabled:    true,
+		NodeID:     "6f8f4dcb-1d7e-4d9b-8d4e-1d9b3b4e1c55",
+		Datacenter: "dc1",
+	}).(*ServiceRegistrationHandler)
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			workload := mockWorkload()
+			workload.Canary = tc.canary
+
+			service := workload.Services[0]
+			service.Tags = []string{"stable"}
+			service.CanaryTags = []string{"canary"}
+			service.Meta = map[string]string{"version": "1"}
+			service.CanaryMeta = map[string]string{"version": "2"}
+			service.Weight = 10
+
+			reg, err := h.generateNomadServiceRegistration(service, workload)
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedTags, reg.Tags)
+			require.Equal(t, tc.expectedMeta, reg.Meta)
+			require.Equal(t, tc.expectedWeight, reg.Weight)
+
+			// The registration must not share meta with the job spec.
+			reg.Meta["version"] = "3"
+			require.Equal(t, "1", service.Meta["version"])
+		})
+	}
+}
+
 func mockWorkload() *serviceregistration.WorkloadServices {
 	return &serviceregistration.WorkloadServices{
 		AllocID:   "98ea220b-7ebe-4662-6d74-9868e797717c",
@@ -153,9 +153,35 @@ type FsLogsRequest struct {
 	// Follow follows logs.
 	Follow bool
 
+	// Filter optionally filters the lines of the logs before they're
+	// streamed.
+	Filter *LogsFilter
+
 	structs.QueryOptions
 }
 
+// LogsFilter filters the lines of streamed logs on the client so only the
+// matching lines are sent.
+type LogsFilter struct {
+	// Match is a regular expression that lines must match.
+	Match string
+
+	// Since and Until bound when lines were written. A zero value leaves
+	// that end of the range open. Lines are filtered by the rotated files
+	// they were written to, and by their timestamp if JSON is set.
+	Since time.Time
+	Until time.Time
+
+	// JSON parses each line as a JSON object so it can be filtered by its
+	// timestamp and have its fields selected. Lines that aren't JSON objects
+	// are passed through unless Fields is set.
+	JSON bool
+
+	// Fields are the fields of JSON lines to output. Nested fields are
+	// selected with dots, such as "http.status".
+	Fields []string
+}
+
 // StreamErrWrapper is used to serialize output of a stream of a file or logs.
 type StreamErrWrapper struct {
 	// Error stores any error that may have occurred.
@@ -268,6 +268,18 @@ func convertServerConfig(agentConfig *Config) (*nomad.Config, error) {
 		}
 		conf.EventBufferSize = int64(*agentConfig.Server.EventBufferSize)
 	}
+	if agentConfig.Server.VariablesVersionLimit != nil {
+		if *agentConfig.Server.VariablesVersionLimit < 0 {
+			return nil, fmt.Errorf("Invalid Config, variables_version_limit must be non-negative")
+		}
+		conf.VariablesVersionLimit = *agentConfig.Server.VariablesVersionLimit
+	}
+	if wrapperConf := agentConfig.Server.KeystoreWrapper; wrapperConf != nil {
+		if err := wrapperConf.Validate(); err != nil {
+			return nil, fmt.Errorf("Invalid Config, %v", err)
+		}
+		conf.KeystoreWrapper = wrapperConf.Copy()
+	}
 	if agentConfig.Autopilot != nil {
 		if agentConfig.Autopilot.CleanupDeadServers != nil {
 			conf.AutopilotConfig.CleanupDeadServers = *agentConfig.Autopilot.CleanupDeadServers
@@ -390,6 +402,36 @@ func convertServerConfig(agentConfig *Config) (*nomad.Config, error) {
 		}
 		conf.ACLTokenExpirationGCThreshold = dur
 	}
+	if gcInterval := agentConfig.Server.RootKeyGCInterval; gcInterval != "" {
+		dur, err := time.ParseDuration(gcInterval)
+		if err != nil {
+			return nil, err
+		}
+		if dur <= 0 {
+			return nil, fmt.Errorf("Invalid Config, root_key_gc_interval must be positive")
+		}
+		conf.RootKeyGCInterval = dur
+	}
+	if gcThreshold := agentConfig.Server.RootKeyGCThreshold; gcThreshold != "" {
+		dur, err := time.ParseDuration(gcThreshold)
+		if err != nil {
+			return nil, err
+		}
+		conf.RootKeyGCThreshold = dur
+	}
+	if rotationThreshold := agentConfig.Server.RootKeyRotationThreshold; rotationThreshold != "" {
+		dur, err := time.ParseDuration(rotationThreshold)
+		if err != nil {
+			return nil, err
+		}
+		if dur <= 0 {
+			return nil, fmt.Errorf("Invalid Config, root_key_rotation_threshold must be positive")
+		}
+		conf.RootKeyRotationThreshold = dur
+	}
+	if rekey := agentConfig.Server.RootKeyRotationRekey; rekey != nil {
+		conf.RootKeyRotationRekey = *rekey
+	}
 
 	if heartbeatGrace := agentConfig.Server.HeartbeatGrace; heartbeatGrace != 0 {
 		conf.HeartbeatGrace = heartbeatGrace
@@ -99,6 +99,11 @@ func (s *HTTPServer) AgentSelfRequest(resp http.ResponseWriter, req *http.Reques
 		self.Config.Telemetry.CirconusAPIToken = "<redacted>"
 	}
 
+	if self.Config != nil && self.Config.Server != nil && self.Config.Server.KeystoreWrapper != nil &&
+		self.Config.Server.KeystoreWrapper.Token != "" {
+		self.Config.Server.KeystoreWrapper.Token = "<redacted>"
+	}
+
 	return self, nil
 }
 
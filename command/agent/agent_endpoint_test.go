@@ -26,6 +26,7 @@ import (
 	"github.com/hashicorp/nomad/helper/pool"
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/nomad/structs/config"
 	"github.com/hashicorp/nomad/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -91,6 +92,17 @@ func TestHTTP_AgentSelf(t *testing.T) {
 		require.NoError(err)
 		self = obj.(agentSelf)
 		require.Equal("<redacted>", self.Config.Telemetry.CirconusAPIToken)
+
+		// Assign a keystore wrapper token and require it is redacted.
+		s.Config.Server.KeystoreWrapper = &config.KeystoreWrapperConfig{
+			Provider: config.KeystoreWrapperProviderTransit,
+			Token:    "badc0deb-adc0-deba-dc0d-ebadc0debadc",
+		}
+		respW = httptest.NewRecorder()
+		obj, err = s.Server.AgentSelfRequest(respW, req)
+		require.NoError(err)
+		self = obj.(agentSelf)
+		require.Equal("<redacted>", self.Config.Server.KeystoreWrapper.Token)
 	})
 }
 
@@ -142,6 +142,13 @@ func TestAgent_ServerConfig(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, 337*time.Second, out.FailoverHeartbeatTTL)
 
+	conf.Server.RootKeyRotationThreshold = "168h"
+	conf.Server.RootKeyRotationRekey = pointer.Of(true)
+	out, err = a.serverConfig()
+	require.NoError(t, err)
+	require.Equal(t, 168*time.Hour, out.RootKeyRotationThreshold)
+	require.True(t, out.RootKeyRotationRekey)
+
 	// Defaults to the global bind addr
 	conf.Addresses.RPC = ""
 	conf.Addresses.Serf = ""
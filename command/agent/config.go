@@ -495,6 +495,19 @@ type ServerConfig struct {
 	// collection interval.
 	RootKeyRotationThreshold string `hcl:"root_key_rotation_threshold"`
 
+	// RootKeyRotationRekey controls whether the variables encrypted with
+	// the previous encryption key are re-encrypted with the new key when
+	// it is automatically rotated.
+	RootKeyRotationRekey *bool `hcl:"root_key_rotation_rekey"`
+
+	// VariablesVersionLimit is the number of prior versions of each variable
+	// retained by the servers.
+	VariablesVersionLimit *int `hcl:"variables_version_limit"`
+
+	// KeystoreWrapper configures the wrapping of the root keys persisted to
+	// the local keystore by an external key encryption key.
+	KeystoreWrapper *config.KeystoreWrapperConfig `hcl:"keystore_wrapper"`
+
 	// HeartbeatGrace is the grace period beyond the TTL to account for network,
 	// processing delays and clock skew before marking a node as "down".
 	HeartbeatGrace    time.Duration
@@ -621,6 +634,9 @@ func (s *ServerConfig) Copy() *ServerConfig {
 	ns.PlanRejectionTracker = s.PlanRejectionTracker.Copy()
 	ns.EnableEventBroker = pointer.Copy(s.EnableEventBroker)
 	ns.EventBufferSize = pointer.Copy(s.EventBufferSize)
+	ns.RootKeyRotationRekey = pointer.Copy(s.RootKeyRotationRekey)
+	ns.VariablesVersionLimit = pointer.Copy(s.VariablesVersionLimit)
+	ns.KeystoreWrapper = s.KeystoreWrapper.Copy()
 	ns.licenseAdditionalPublicKeys = slices.Clone(s.licenseAdditionalPublicKeys)
 	ns.ExtraKeysHCL = slices.Clone(s.ExtraKeysHCL)
 	ns.Search = s.Search.Copy()
@@ -1834,6 +1850,15 @@ func (s *ServerConfig) Merge(b *ServerConfig) *ServerConfig {
 	if b.RootKeyRotationThreshold != "" {
 		result.RootKeyRotationThreshold = b.RootKeyRotationThreshold
 	}
+	if b.RootKeyRotationRekey != nil {
+		result.RootKeyRotationRekey = b.RootKeyRotationRekey
+	}
+	if b.VariablesVersionLimit != nil {
+		result.VariablesVersionLimit = b.VariablesVersionLimit
+	}
+	if b.KeystoreWrapper != nil {
+		result.KeystoreWrapper = result.KeystoreWrapper.Merge(b.KeystoreWrapper)
+	}
 	if b.HeartbeatGrace != 0 {
 		result.HeartbeatGrace = b.HeartbeatGrace
 	}
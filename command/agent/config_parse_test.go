@@ -108,6 +108,10 @@ var basicConfig = &Config{
 		CSIVolumeClaimGCThreshold: "12h",
 		CSIPluginGCThreshold:      "12h",
 		ACLTokenGCThreshold:       "12h",
+		RootKeyGCInterval:         "11m",
+		RootKeyGCThreshold:        "2h",
+		RootKeyRotationThreshold:  "168h",
+		RootKeyRotationRekey:      pointer.Of(true),
 		HeartbeatGrace:            30 * time.Second,
 		HeartbeatGraceHCL:         "30s",
 		MinHeartbeatTTL:           33 * time.Second,
@@ -127,6 +131,15 @@ var basicConfig = &Config{
 		EncryptKey:                "abc",
 		EnableEventBroker:         pointer.Of(false),
 		EventBufferSize:           pointer.Of(200),
+		VariablesVersionLimit:     pointer.Of(3),
+		KeystoreWrapper: &config.KeystoreWrapperConfig{
+			Provider:  config.KeystoreWrapperProviderTransit,
+			Address:   "https://vault.example.com:8200",
+			Token:     "12345",
+			MountPath: "transit",
+			KeyName:   "nomad-keystore",
+			Namespace: "ns1",
+		},
 		PlanRejectionTracker: &PlanRejectionTracker{
 			Enabled:       pointer.Of(true),
 			NodeThreshold: 100,
@@ -141,10 +154,12 @@ var basicConfig = &Config{
 		},
 		DefaultSchedulerConfig: &structs.SchedulerConfiguration{
 			SchedulerAlgorithm: "spread",
+			HeadroomPercent:    10,
 			PreemptionConfig: structs.PreemptionConfig{
 				SystemSchedulerEnabled:  true,
 				BatchSchedulerEnabled:   true,
 				ServiceSchedulerEnabled: true,
+				MaxPreemptionsPerMinute: 30,
 			},
 		},
 		LicensePath: "/tmp/nomad.hclic",
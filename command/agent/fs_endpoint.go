@@ -7,8 +7,10 @@ import (
 	"io"
 	"net"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/docker/docker/pkg/ioutils"
 	"github.com/hashicorp/go-msgpack/codec"
@@ -312,6 +314,11 @@ func (s *HTTPServer) Logs(resp http.ResponseWriter, req *http.Request) (interfac
 		return nil, invalidOrigin
 	}
 
+	filter, err := parseLogsFilter(q)
+	if err != nil {
+		return nil, err
+	}
+
 	// Create the request arguments
 	fsReq := &cstructs.FsLogsRequest{
 		AllocID:   allocID,
@@ -321,6 +328,7 @@ func (s *HTTPServer) Logs(resp http.ResponseWriter, req *http.Request) (interfac
 		Origin:    origin,
 		PlainText: plain,
 		Follow:    follow,
+		Filter:    filter,
 	}
 	s.parse(resp, req, &fsReq.QueryOptions.Region, &fsReq.QueryOptions)
 
@@ -336,6 +344,47 @@ func (s *HTTPServer) Logs(resp http.ResponseWriter, req *http.Request) (interfac
 	return s.fsStreamImpl(resp, req, "FileSystem.Logs", fsReq, fsReq.AllocID)
 }
 
+// parseLogsFilter parses the optional filter of a logs request. Nil is
+// returned if no filter was given.
+func parseLogsFilter(q url.Values) (*cstructs.LogsFilter, error) {
+	filter := &cstructs.LogsFilter{
+		Match: q.Get("match"),
+	}
+
+	for _, bound := range []struct {
+		param string
+		value *time.Time
+	}{
+		{"since", &filter.Since},
+		{"until", &filter.Until},
+	} {
+		if v := q.Get(bound.param); v != "" {
+			t, err := time.Parse(time.RFC3339Nano, v)
+			if err != nil {
+				return nil, CodedError(400, fmt.Sprintf("failed to parse %s field as an RFC 3339 time: %v", bound.param, err))
+			}
+			*bound.value = t
+		}
+	}
+
+	if jsonStr := q.Get("json"); jsonStr != "" {
+		var err error
+		if filter.JSON, err = strconv.ParseBool(jsonStr); err != nil {
+			return nil, CodedError(400, fmt.Sprintf("failed to parse json field to boolean: %v", err))
+		}
+	}
+
+	if fields := q.Get("fields"); fields != "" {
+		filter.Fields = strings.Split(fields, ",")
+	}
+
+	if filter.Match == "" && filter.Since.IsZero() && filter.Until.IsZero() &&
+		!filter.JSON && len(filter.Fields) == 0 {
+		return nil, nil
+	}
+	return filter, nil
+}
+
 // fsStreamImpl is used to make a streaming filesystem call that serializes the
 // args and then expects a stream of StreamErrWrapper results where the payload
 // is copied to the response body.
@@ -6,6 +6,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strings"
 	"testing"
 	"time"
@@ -262,6 +263,45 @@ func TestHTTP_FS_Logs_MissingParams(t *testing.T) {
 	})
 }
 
+func TestHTTP_FS_parseLogsFilter(t *testing.T) {
+	ci.Parallel(t)
+
+	since := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+	cases := []struct {
+		query    string
+		expected *cstructs.LogsFilter
+		err      string
+	}{
+		{query: ""},
+		{
+			query:    "match=err&since=2022-06-01T12:00:00Z",
+			expected: &cstructs.LogsFilter{Match: "err", Since: since},
+		},
+		{
+			query:    "json=true&fields=msg,http.status&until=2022-06-01T12:00:00Z",
+			expected: &cstructs.LogsFilter{JSON: true, Fields: []string{"msg", "http.status"}, Until: since},
+		},
+		{query: "since=yesterday", err: "failed to parse since field"},
+		{query: "json=maybe", err: "failed to parse json field"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.query, func(t *testing.T) {
+			q, err := url.ParseQuery(tc.query)
+			require.NoError(t, err)
+
+			filter, err := parseLogsFilter(q)
+			if tc.err != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, filter)
+		})
+	}
+}
+
 func TestHTTP_FS_List(t *testing.T) {
 	ci.Parallel(t)
 	require := require.New(t)
@@ -32,6 +32,10 @@ import (
 )
 
 const (
+	// builtinHTTPAddr is the address of the HTTP server consul-template
+	// connects to in process to render templates.
+	builtinHTTPAddr = "builtin"
+
 	// ErrInvalidMethod is used if the HTTP method is not supported
 	ErrInvalidMethod = "Invalid method"
 
@@ -179,7 +183,7 @@ func NewHTTPServers(agent *Agent, config *Config) ([]*HTTPServer, error) {
 			listener:   agent.builtinListener,
 			listenerCh: make(chan struct{}),
 			logger:     agent.httpLogger,
-			Addr:       "builtin",
+			Addr:       builtinHTTPAddr,
 			wsUpgrader: wsUpgrader,
 		}
 
@@ -440,6 +444,7 @@ func (s HTTPServer) registerHandlers(enableDebug bool) {
 	s.mux.HandleFunc("/v1/system/reconcile/summaries", s.wrap(s.ReconcileJobSummaries))
 
 	s.mux.HandleFunc("/v1/operator/scheduler/configuration", s.wrap(s.OperatorSchedulerConfiguration))
+	s.mux.HandleFunc("/v1/operator/scheduler/rebalance", s.wrap(s.OperatorSchedulerRebalance))
 
 	s.mux.HandleFunc("/v1/event/stream", s.wrap(s.EventStream))
 
@@ -157,6 +157,7 @@ func (s *HTTPServer) jobPlan(resp http.ResponseWriter, req *http.Request,
 	planReq := structs.JobPlanRequest{
 		Job:            sJob,
 		Diff:           args.Diff,
+		Explain:        args.Explain,
 		PolicyOverride: args.PolicyOverride,
 		WriteRequest:   *writeReq,
 	}
@@ -898,22 +899,24 @@ func ApiJobToStructJob(job *api.Job) *structs.Job {
 	job.Canonicalize()
 
 	j := &structs.Job{
-		Stop:           *job.Stop,
-		Region:         *job.Region,
-		Namespace:      *job.Namespace,
-		ID:             *job.ID,
-		Name:           *job.Name,
-		Type:           *job.Type,
-		Priority:       *job.Priority,
-		AllAtOnce:      *job.AllAtOnce,
-		Datacenters:    job.Datacenters,
-		Payload:        job.Payload,
-		Meta:           job.Meta,
-		ConsulToken:    *job.ConsulToken,
-		VaultToken:     *job.VaultToken,
-		VaultNamespace: *job.VaultNamespace,
-		Constraints:    ApiConstraintsToStructs(job.Constraints),
-		Affinities:     ApiAffinitiesToStructs(job.Affinities),
+		Stop:               *job.Stop,
+		Region:             *job.Region,
+		Namespace:          *job.Namespace,
+		ID:                 *job.ID,
+		Name:               *job.Name,
+		Type:               *job.Type,
+		Priority:           *job.Priority,
+		AllAtOnce:          *job.AllAtOnce,
+		NonPreemptible:     *job.NonPreemptible,
+		SchedulerAlgorithm: structs.SchedulerAlgorithm(*job.SchedulerAlgorithm),
+		Datacenters:        job.Datacenters,
+		Payload:            job.Payload,
+		Meta:               job.Meta,
+		ConsulToken:        *job.ConsulToken,
+		VaultToken:         *job.VaultToken,
+		VaultNamespace:     *job.VaultNamespace,
+		Constraints:        ApiConstraintsToStructs(job.Constraints),
+		Affinities:         ApiAffinitiesToStructs(job.Affinities),
 	}
 
 	// Update has been pushed into the task groups. stagger and max_parallel are
@@ -937,6 +940,17 @@ func ApiJobToStructJob(job *api.Job) *structs.Job {
 		}
 	}
 
+	if len(job.DependsOn) > 0 {
+		j.DependsOn = make([]*structs.JobDependency, len(job.DependsOn))
+		for i, dep := range job.DependsOn {
+			j.DependsOn[i] = &structs.JobDependency{
+				Job:       dep.Job,
+				Group:     dep.Group,
+				Condition: dep.Condition,
+			}
+		}
+	}
+
 	if job.Periodic != nil {
 		j.Periodic = &structs.PeriodicConfig{
 			Enabled:         *job.Periodic.Enabled,
@@ -1016,6 +1030,10 @@ func ApiTgToStructsTG(job *structs.Job, taskGroup *api.TaskGroup, tg *structs.Ta
 		tg.MaxClientDisconnect = taskGroup.MaxClientDisconnect
 	}
 
+	if taskGroup.Gang != nil {
+		tg.Gang = *taskGroup.Gang
+	}
+
 	if taskGroup.ReschedulePolicy != nil {
 		tg.ReschedulePolicy = &structs.ReschedulePolicy{
 			Attempts:      *taskGroup.ReschedulePolicy.Attempts,
@@ -1178,8 +1196,12 @@ func ApiTaskToStructsTask(job *structs.Job, group *structs.TaskGroup,
 	structsTask.Resources = ApiResourcesToStructs(apiTask.Resources)
 
 	structsTask.LogConfig = &structs.LogConfig{
-		MaxFiles:      *apiTask.LogConfig.MaxFiles,
-		MaxFileSizeMB: *apiTask.LogConfig.MaxFileSizeMB,
+		MaxFiles:         *apiTask.LogConfig.MaxFiles,
+		MaxFileSizeMB:    *apiTask.LogConfig.MaxFileSizeMB,
+		RotationInterval: *apiTask.LogConfig.RotationInterval,
+		Compress:         *apiTask.LogConfig.Compress,
+		MaxTotalSizeMB:   *apiTask.LogConfig.MaxTotalSizeMB,
+		Sinks:            apiLogSinksToStructs(apiTask.LogConfig.Sinks),
 	}
 
 	if len(apiTask.Artifacts) > 0 {
@@ -1397,6 +1419,7 @@ func ApiServicesToStructs(in []*api.Service, group bool) []*structs.Service {
 			TaggedAddresses:   helper.CopyMapStringString(s.TaggedAddresses),
 			OnUpdate:          s.OnUpdate,
 			Provider:          s.Provider,
+			Weight:            s.Weight,
 		}
 
 		if l := len(s.Checks); l != 0 {
@@ -1726,10 +1749,40 @@ func apiLogConfigToStructs(in *api.LogConfig) *structs.LogConfig {
 	if in == nil {
 		return nil
 	}
-	return &structs.LogConfig{
-		MaxFiles:      dereferenceInt(in.MaxFiles),
-		MaxFileSizeMB: dereferenceInt(in.MaxFileSizeMB),
+	out := &structs.LogConfig{
+		MaxFiles:       dereferenceInt(in.MaxFiles),
+		MaxFileSizeMB:  dereferenceInt(in.MaxFileSizeMB),
+		MaxTotalSizeMB: dereferenceInt(in.MaxTotalSizeMB),
+		Sinks:          apiLogSinksToStructs(in.Sinks),
+	}
+	if in.RotationInterval != nil {
+		out.RotationInterval = *in.RotationInterval
+	}
+	if in.Compress != nil {
+		out.Compress = *in.Compress
+	}
+	return out
+}
+
+func apiLogSinksToStructs(in []*api.LogSink) []*structs.LogSink {
+	if len(in) == 0 {
+		return nil
+	}
+	out := make([]*structs.LogSink, len(in))
+	for i, sink := range in {
+		out[i] = &structs.LogSink{
+			Type:          sink.Type,
+			Address:       sink.Address,
+			Protocol:      sink.Protocol,
+			Facility:      sink.Facility,
+			Tag:           sink.Tag,
+			BatchSize:     sink.BatchSize,
+			BatchInterval: sink.BatchInterval,
+			BufferSize:    sink.BufferSize,
+			Backpressure:  sink.Backpressure,
+		}
 	}
+	return out
 }
 
 func dereferenceInt(in *int) int {
@@ -1790,6 +1843,9 @@ func ApiSpreadToStructs(a1 *api.Spread) *structs.Spread {
 	ret := &structs.Spread{}
 	ret.Attribute = a1.Attribute
 	ret.Weight = *a1.Weight
+	if a1.MaxSkew != nil {
+		ret.MaxSkew = *a1.MaxSkew
+	}
 	if a1.SpreadTarget != nil {
 		ret.SpreadTarget = make([]*structs.SpreadTarget, len(a1.SpreadTarget))
 		for i, st := range a1.SpreadTarget {
@@ -2360,16 +2360,18 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 	ci.Parallel(t)
 
 	apiJob := &api.Job{
-		Stop:        pointer.Of(true),
-		Region:      pointer.Of("global"),
-		Namespace:   pointer.Of("foo"),
-		ID:          pointer.Of("foo"),
-		ParentID:    pointer.Of("lol"),
-		Name:        pointer.Of("name"),
-		Type:        pointer.Of("service"),
-		Priority:    pointer.Of(50),
-		AllAtOnce:   pointer.Of(true),
-		Datacenters: []string{"dc1", "dc2"},
+		Stop:               pointer.Of(true),
+		Region:             pointer.Of("global"),
+		Namespace:          pointer.Of("foo"),
+		ID:                 pointer.Of("foo"),
+		ParentID:           pointer.Of("lol"),
+		Name:               pointer.Of("name"),
+		Type:               pointer.Of("service"),
+		Priority:           pointer.Of(50),
+		AllAtOnce:          pointer.Of(true),
+		NonPreemptible:     pointer.Of(true),
+		SchedulerAlgorithm: pointer.Of("spread"),
+		Datacenters:        []string{"dc1", "dc2"},
 		Constraints: []*api.Constraint{
 			{
 				LTarget: "a",
@@ -2385,6 +2387,12 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 				Weight:  pointer.Of(int8(50)),
 			},
 		},
+		DependsOn: []*api.JobDependency{
+			{
+				Job:       "db",
+				Condition: "healthy",
+			},
+		},
 		Update: &api.UpdateStrategy{
 			Stagger:          pointer.Of(1 * time.Second),
 			MaxParallel:      pointer.Of(5),
@@ -2487,6 +2495,11 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 							},
 						},
 					},
+					{
+						Attribute: "${meta.rack}",
+						Weight:    pointer.Of(int8(50)),
+						MaxSkew:   pointer.Of(1),
+					},
 				},
 				EphemeralDisk: &api.EphemeralDisk{
 					SizeMB:  pointer.Of(100),
@@ -2703,8 +2716,19 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 						KillTimeout: pointer.Of(10 * time.Second),
 						KillSignal:  "SIGQUIT",
 						LogConfig: &api.LogConfig{
-							MaxFiles:      pointer.Of(10),
-							MaxFileSizeMB: pointer.Of(100),
+							MaxFiles:         pointer.Of(10),
+							MaxFileSizeMB:    pointer.Of(100),
+							RotationInterval: pointer.Of(24 * time.Hour),
+							Compress:         pointer.Of(true),
+							MaxTotalSizeMB:   pointer.Of(500),
+							Sinks: []*api.LogSink{
+								{
+									Type:          "http",
+									Address:       "http://127.0.0.1:8080/logs",
+									BatchSize:     50,
+									BatchInterval: 2 * time.Second,
+								},
+							},
 						},
 						Artifacts: []*api.TaskArtifact{
 							{
@@ -2768,16 +2792,18 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 	}
 
 	expected := &structs.Job{
-		Stop:           true,
-		Region:         "global",
-		Namespace:      "foo",
-		VaultNamespace: "ghi789",
-		ID:             "foo",
-		Name:           "name",
-		Type:           "service",
-		Priority:       50,
-		AllAtOnce:      true,
-		Datacenters:    []string{"dc1", "dc2"},
+		Stop:               true,
+		Region:             "global",
+		Namespace:          "foo",
+		VaultNamespace:     "ghi789",
+		ID:                 "foo",
+		Name:               "name",
+		Type:               "service",
+		Priority:           50,
+		AllAtOnce:          true,
+		NonPreemptible:     true,
+		SchedulerAlgorithm: "spread",
+		Datacenters:        []string{"dc1", "dc2"},
 		Constraints: []*structs.Constraint{
 			{
 				LTarget: "a",
@@ -2793,6 +2819,12 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 				Weight:  50,
 			},
 		},
+		DependsOn: []*structs.JobDependency{
+			{
+				Job:       "db",
+				Condition: "healthy",
+			},
+		},
 		Spreads: []*structs.Spread{
 			{
 				Attribute: "${meta.rack}",
@@ -2875,6 +2907,11 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 							},
 						},
 					},
+					{
+						Attribute: "${meta.rack}",
+						Weight:    50,
+						MaxSkew:   1,
+					},
 				},
 				ReschedulePolicy: &structs.ReschedulePolicy{
 					Interval:      12 * time.Hour,
@@ -3116,8 +3153,19 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 						KillTimeout: 10 * time.Second,
 						KillSignal:  "SIGQUIT",
 						LogConfig: &structs.LogConfig{
-							MaxFiles:      10,
-							MaxFileSizeMB: 100,
+							MaxFiles:         10,
+							MaxFileSizeMB:    100,
+							RotationInterval: 24 * time.Hour,
+							Compress:         true,
+							MaxTotalSizeMB:   500,
+							Sinks: []*structs.LogSink{
+								{
+									Type:          "http",
+									Address:       "http://127.0.0.1:8080/logs",
+									BatchSize:     50,
+									BatchInterval: 2 * time.Second,
+								},
+							},
 						},
 						Artifacts: []*structs.TaskArtifact{
 							{
@@ -260,6 +260,7 @@ func (s *HTTPServer) schedulerUpdateConfig(resp http.ResponseWriter, req *http.R
 
 	args.Config = structs.SchedulerConfiguration{
 		SchedulerAlgorithm:            structs.SchedulerAlgorithm(conf.SchedulerAlgorithm),
+		HeadroomPercent:               conf.HeadroomPercent,
 		MemoryOversubscriptionEnabled: conf.MemoryOversubscriptionEnabled,
 		RejectJobRegistration:         conf.RejectJobRegistration,
 		PauseEvalBroker:               conf.PauseEvalBroker,
@@ -267,7 +268,8 @@ func (s *HTTPServer) schedulerUpdateConfig(resp http.ResponseWriter, req *http.R
 			SystemSchedulerEnabled:   conf.PreemptionConfig.SystemSchedulerEnabled,
 			SysBatchSchedulerEnabled: conf.PreemptionConfig.SysBatchSchedulerEnabled,
 			BatchSchedulerEnabled:    conf.PreemptionConfig.BatchSchedulerEnabled,
-			ServiceSchedulerEnabled:  conf.PreemptionConfig.ServiceSchedulerEnabled},
+			ServiceSchedulerEnabled:  conf.PreemptionConfig.ServiceSchedulerEnabled,
+			MaxPreemptionsPerMinute:  conf.PreemptionConfig.MaxPreemptionsPerMinute},
 	}
 
 	if err := args.Config.Validate(); err != nil {
@@ -293,6 +295,32 @@ func (s *HTTPServer) schedulerUpdateConfig(resp http.ResponseWriter, req *http.R
 	return reply, nil
 }
 
+// OperatorSchedulerRebalance is used to migrate allocations to nodes where
+// they would score better.
+func (s *HTTPServer) OperatorSchedulerRebalance(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "PUT" && req.Method != "POST" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	var args structs.SchedulerRebalanceRequest
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var rebalance api.SchedulerRebalanceRequest
+	if err := decodeBody(req, &rebalance); err != nil {
+		return nil, CodedError(http.StatusBadRequest, fmt.Sprintf("Error parsing rebalance request: %v", err))
+	}
+	args.JobID = rebalance.JobID
+	args.MinScoreImprovement = rebalance.MinScoreImprovement
+	args.DryRun = rebalance.DryRun
+
+	var reply structs.SchedulerRebalanceResponse
+	if err := s.agent.RPC("Operator.SchedulerRebalance", &args, &reply); err != nil {
+		return nil, err
+	}
+	setIndex(resp, reply.Index)
+	return reply, nil
+}
+
 func (s *HTTPServer) SnapshotRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
 	switch req.Method {
 	case "GET":
@@ -98,6 +98,28 @@ func (s *HTTPServer) serviceGetRequest(
 		return nil, nil
 	}
 
+	// The meta parameter only returns registrations whose metadata holds the
+	// value for the key, in the form "key:value".
+	if meta := req.URL.Query().Get("meta"); meta != "" {
+		key, value, ok := strings.Cut(meta, ":")
+		if !ok || key == "" {
+			return nil, CodedError(http.StatusBadRequest, `meta must be in the form "key:value"`)
+		}
+		args.MetaKey, args.MetaValue = key, value
+	}
+
+	healthy, err := parseBool(req, "healthy")
+	if err != nil {
+		return nil, err
+	}
+	if healthy != nil {
+		args.Healthy = *healthy
+	} else if s.Addr == builtinHTTPAddr {
+		// The nomadService template function can't set the filter, so
+		// templates are only given healthy instances unless asked otherwise.
+		args.Healthy = true
+	}
+
 	var reply structs.ServiceRegistrationByNameResponse
 	if err := s.agent.RPC(structs.ServiceRegistrationGetServiceRPCMethod, &args, &reply); err != nil {
 		return nil, err
@@ -217,6 +217,76 @@ func TestHTTPServer_ServiceRegistrationRequest(t *testing.T) {
 				require.Equal(t, serviceReg, obj.([]*structs.ServiceRegistration)[0])
 			},
 		},
+		{
+			name: "get healthy services from templates",
+			testFn: func(s *TestAgent) {
+
+				// Grab the state, so we can manipulate it and test against it.
+				testState := s.Agent.server.State()
+
+				// Generate two registrations of a service, one of which is
+				// failing its check, and upsert them.
+				serviceRegs := mock.ServiceRegistrations()
+				serviceRegs[1].Namespace = serviceRegs[0].Namespace
+				serviceRegs[1].ServiceName = serviceRegs[0].ServiceName
+				serviceRegs[1].Checks = []*structs.ServiceRegistrationCheck{
+					{Name: "check", Mode: structs.Healthiness, Status: structs.CheckFailure},
+				}
+				require.NoError(t, testState.UpsertServiceRegistrations(
+					structs.MsgTypeTestSetup, 10, serviceRegs))
+
+				// Requests from templates only see the healthy registration,
+				// as if the healthy filter was set.
+				s.Server.Addr = builtinHTTPAddr
+				path := fmt.Sprintf("/v1/service/%s", serviceRegs[0].ServiceName)
+				req, err := http.NewRequest(http.MethodGet, path, nil)
+				require.NoError(t, err)
+				obj, err := s.Server.ServiceRegistrationRequest(httptest.NewRecorder(), req)
+				require.NoError(t, err)
+				require.Len(t, obj.([]*structs.ServiceRegistration), 1)
+				require.Equal(t, serviceRegs[0].ID, obj.([]*structs.ServiceRegistration)[0].ID)
+
+				// Unless the filter is disabled explicitly.
+				req, err = http.NewRequest(http.MethodGet, path+"?healthy=false", nil)
+				require.NoError(t, err)
+				obj, err = s.Server.ServiceRegistrationRequest(httptest.NewRecorder(), req)
+				require.NoError(t, err)
+				require.Len(t, obj.([]*structs.ServiceRegistration), 2)
+			},
+		},
+		{
+			name: "get service using meta",
+			testFn: func(s *TestAgent) {
+
+				// Grab the state, so we can manipulate it and test against it.
+				testState := s.Agent.server.State()
+
+				// Generate two versions of a service and upsert them.
+				serviceRegs := mock.ServiceRegistrations()
+				serviceRegs[1].Namespace = serviceRegs[0].Namespace
+				serviceRegs[1].ServiceName = serviceRegs[0].ServiceName
+				serviceRegs[0].Meta = map[string]string{"version": "v1"}
+				serviceRegs[1].Meta = map[string]string{"version": "v2"}
+				require.NoError(t, testState.UpsertServiceRegistrations(
+					structs.MsgTypeTestSetup, 10, serviceRegs))
+
+				// Only the registration with the metadata is returned.
+				path := fmt.Sprintf("/v1/service/%s?meta=version:v2", serviceRegs[0].ServiceName)
+				req, err := http.NewRequest(http.MethodGet, path, nil)
+				require.NoError(t, err)
+				obj, err := s.Server.ServiceRegistrationRequest(httptest.NewRecorder(), req)
+				require.NoError(t, err)
+				require.Len(t, obj.([]*structs.ServiceRegistration), 1)
+				require.Equal(t, serviceRegs[1].ID, obj.([]*structs.ServiceRegistration)[0].ID)
+
+				// The parameter must hold a key and value.
+				path = fmt.Sprintf("/v1/service/%s?meta=version", serviceRegs[0].ServiceName)
+				req, err = http.NewRequest(http.MethodGet, path, nil)
+				require.NoError(t, err)
+				_, err = s.Server.ServiceRegistrationRequest(httptest.NewRecorder(), req)
+				require.EqualError(t, err, `meta must be in the form "key:value"`)
+			},
+		},
 		{
 			name: "get service using choose",
 			testFn: func(s *TestAgent) {
@@ -117,6 +117,10 @@ server {
   csi_volume_claim_gc_threshold = "12h"
   csi_plugin_gc_threshold       = "12h"
   acl_token_gc_threshold        = "12h"
+  root_key_gc_interval          = "11m"
+  root_key_gc_threshold         = "2h"
+  root_key_rotation_threshold   = "168h"
+  root_key_rotation_rekey       = true
   heartbeat_grace               = "30s"
   min_heartbeat_ttl             = "33s"
   max_heartbeats_per_second     = 11.0
@@ -133,6 +137,16 @@ server {
   raft_multiplier               = 4
   enable_event_broker           = false
   event_buffer_size             = 200
+  variables_version_limit       = 3
+
+  keystore_wrapper {
+    provider   = "transit"
+    address    = "https://vault.example.com:8200"
+    token      = "12345"
+    mount_path = "transit"
+    key_name   = "nomad-keystore"
+    namespace  = "ns1"
+  }
 
   plan_rejection_tracker {
     enabled        = true
@@ -148,11 +162,13 @@ server {
 
   default_scheduler_config {
     scheduler_algorithm = "spread"
+    headroom_percent    = 10
 
     preemption_config {
-      batch_scheduler_enabled   = true
-      system_scheduler_enabled  = true
-      service_scheduler_enabled = true
+      batch_scheduler_enabled    = true
+      system_scheduler_enabled   = true
+      service_scheduler_enabled  = true
+      max_preemptions_per_minute = 30
     }
   }
 
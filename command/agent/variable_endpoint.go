@@ -39,8 +39,24 @@ func (s *HTTPServer) VariableSpecificRequest(resp http.ResponseWriter, req *http
 	}
 	switch req.Method {
 	case http.MethodGet:
+		if _, ok := req.URL.Query()["versions"]; ok {
+			return s.variableListVersions(resp, req, path)
+		}
 		return s.variableQuery(resp, req, path)
 	case http.MethodPut, http.MethodPost:
+		query := req.URL.Query()
+		if query.Get("rollback") != "" {
+			return s.variableRollback(resp, req, path)
+		}
+		if _, ok := query["lock-acquire"]; ok {
+			return s.variableLockOperation(resp, req, path, structs.VarOpLockAcquire)
+		}
+		if _, ok := query["lock-release"]; ok {
+			return s.variableLockOperation(resp, req, path, structs.VarOpLockRelease)
+		}
+		if _, ok := query["lock-renew"]; ok {
+			return s.variableLockRenew(resp, req, path)
+		}
 		return s.variableUpsert(resp, req, path)
 	case http.MethodDelete:
 		return s.variableDelete(resp, req, path)
@@ -57,6 +73,13 @@ func (s *HTTPServer) variableQuery(resp http.ResponseWriter, req *http.Request,
 	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
 		return nil, nil
 	}
+	if vq := req.URL.Query().Get("version"); vq != "" {
+		version, err := strconv.ParseUint(vq, 10, 64)
+		if err != nil {
+			return nil, CodedError(http.StatusBadRequest, fmt.Sprintf("can not parse version: %v", err))
+		}
+		args.Version = version
+	}
 	var out structs.VariablesReadResponse
 	if err := s.agent.RPC(structs.VariablesReadRPCMethod, &args, &out); err != nil {
 		return nil, err
@@ -70,6 +93,120 @@ func (s *HTTPServer) variableQuery(resp http.ResponseWriter, req *http.Request,
 	return out.Data, nil
 }
 
+func (s *HTTPServer) variableListVersions(resp http.ResponseWriter, req *http.Request,
+	path string) (interface{}, error) {
+	args := structs.VariablesListVersionsRequest{
+		Path: path,
+	}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+	var out structs.VariablesListVersionsResponse
+	if err := s.agent.RPC(structs.VariablesListVersionsRPCMethod, &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+
+	if out.Data == nil {
+		return nil, CodedError(http.StatusNotFound, "variable not found")
+	}
+	return out.Data, nil
+}
+
+func (s *HTTPServer) variableRollback(resp http.ResponseWriter, req *http.Request,
+	path string) (interface{}, error) {
+	version, err := strconv.ParseUint(req.URL.Query().Get("rollback"), 10, 64)
+	if err != nil {
+		return nil, CodedError(http.StatusBadRequest, fmt.Sprintf("can not parse rollback: %v", err))
+	}
+
+	args := structs.VariablesApplyRequest{
+		Op: structs.VarOpRollback,
+		Var: &structs.VariableDecrypted{
+			VariableMetadata: structs.VariableMetadata{
+				Path: path,
+			},
+		},
+		Version: version,
+	}
+
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.VariablesApplyResponse
+	if err := s.agent.RPC(structs.VariablesApplyRPCMethod, &args, &out); err != nil {
+		setIndex(resp, out.WriteMeta.Index)
+		return nil, err
+	}
+
+	setIndex(resp, out.WriteMeta.Index)
+	return out.Output, nil
+}
+
+func (s *HTTPServer) variableLockOperation(resp http.ResponseWriter, req *http.Request,
+	path string, op structs.VarOp) (interface{}, error) {
+	// Parse the Variable. The items are optional when acquiring a lock on an
+	// existing variable, and ignored when releasing a lock.
+	var Variable structs.VariableDecrypted
+	if err := decodeBody(req, &Variable); err != nil {
+		return nil, CodedError(http.StatusBadRequest, err.Error())
+	}
+	if Variable.Lock == nil {
+		return nil, CodedError(http.StatusBadRequest, "variable missing required Lock object")
+	}
+
+	Variable.Path = path
+
+	args := structs.VariablesApplyRequest{
+		Op:  op,
+		Var: &Variable,
+	}
+
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.VariablesApplyResponse
+	if err := s.agent.RPC(structs.VariablesApplyRPCMethod, &args, &out); err != nil {
+		setIndex(resp, out.WriteMeta.Index)
+		return nil, err
+	}
+
+	// The lock is already held, or is not held by the caller
+	if out.Conflict != nil {
+		setIndex(resp, out.Conflict.ModifyIndex)
+		resp.WriteHeader(http.StatusConflict)
+		return out.Conflict, nil
+	}
+
+	setIndex(resp, out.WriteMeta.Index)
+	return out.Output, nil
+}
+
+func (s *HTTPServer) variableLockRenew(resp http.ResponseWriter, req *http.Request,
+	path string) (interface{}, error) {
+	var Variable structs.VariableDecrypted
+	if err := decodeBody(req, &Variable); err != nil {
+		return nil, CodedError(http.StatusBadRequest, err.Error())
+	}
+	if Variable.Lock == nil {
+		return nil, CodedError(http.StatusBadRequest, "variable missing required Lock object")
+	}
+
+	args := structs.VariablesRenewLockRequest{
+		Path:   path,
+		LockID: Variable.Lock.ID,
+	}
+
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.VariablesRenewLockResponse
+	if err := s.agent.RPC(structs.VariablesRenewLockRPCMethod, &args, &out); err != nil {
+		return nil, err
+	}
+
+	setIndex(resp, out.WriteMeta.Index)
+	return out.VarMeta, nil
+}
+
 func (s *HTTPServer) variableUpsert(resp http.ResponseWriter, req *http.Request,
 	path string) (interface{}, error) {
 	// Parse the Variable
@@ -9,8 +9,10 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/pointer"
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/stretchr/testify/require"
@@ -468,6 +470,141 @@ func TestHTTP_Variables(t *testing.T) {
 	})
 }
 
+func TestHTTP_Variables_Versions(t *testing.T) {
+	ci.Parallel(t)
+
+	versionsCb := func(c *Config) {
+		cb(c)
+		c.Server.VariablesVersionLimit = pointer.Of(2)
+	}
+
+	httpTest(t, versionsCb, func(s *TestAgent) {
+		sv := mock.Variable()
+		var v1, v2 structs.VariableDecrypted
+		require.NoError(t, rpcWriteSV(s, sv, &v1))
+		sv.Items["extra"] = "value"
+		require.NoError(t, rpcWriteSV(s, sv, &v2))
+
+		t.Run("list_versions", func(t *testing.T) {
+			req, err := http.NewRequest("GET", "/v1/var/"+sv.Path+"?versions", nil)
+			require.NoError(t, err)
+			respW := httptest.NewRecorder()
+			obj, err := s.Server.VariableSpecificRequest(respW, req)
+			require.NoError(t, err)
+
+			versions := obj.([]*structs.VariableMetadata)
+			require.Len(t, versions, 2)
+			require.Equal(t, v2.ModifyIndex, versions[0].ModifyIndex)
+			require.Equal(t, v1.ModifyIndex, versions[1].ModifyIndex)
+		})
+		t.Run("error_parse_version", func(t *testing.T) {
+			req, err := http.NewRequest("GET", "/v1/var/"+sv.Path+"?version=nope", nil)
+			require.NoError(t, err)
+			respW := httptest.NewRecorder()
+			_, err = s.Server.VariableSpecificRequest(respW, req)
+			require.ErrorContains(t, err, "can not parse version")
+		})
+		t.Run("query_version", func(t *testing.T) {
+			req, err := http.NewRequest("GET", fmt.Sprintf("/v1/var/%s?version=%d", sv.Path, v1.ModifyIndex), nil)
+			require.NoError(t, err)
+			respW := httptest.NewRecorder()
+			obj, err := s.Server.VariableSpecificRequest(respW, req)
+			require.NoError(t, err)
+
+			out := obj.(*structs.VariableDecrypted)
+			require.Equal(t, v1.ModifyIndex, out.ModifyIndex)
+			require.NotContains(t, out.Items, "extra")
+		})
+		t.Run("rollback", func(t *testing.T) {
+			req, err := http.NewRequest("PUT", fmt.Sprintf("/v1/var/%s?rollback=%d", sv.Path, v1.ModifyIndex), nil)
+			require.NoError(t, err)
+			respW := httptest.NewRecorder()
+			obj, err := s.Server.VariableSpecificRequest(respW, req)
+			require.NoError(t, err)
+
+			out := obj.(*structs.VariableDecrypted)
+			require.Greater(t, out.ModifyIndex, v2.ModifyIndex)
+			require.Equal(t, v1.Items, out.Items)
+
+			current, err := rpcReadSV(s, sv.Namespace, sv.Path)
+			require.NoError(t, err)
+			require.Equal(t, v1.Items, current.Items)
+		})
+	})
+}
+
+func TestHTTP_Variables_Locks(t *testing.T) {
+	ci.Parallel(t)
+
+	httpTest(t, cb, func(s *TestAgent) {
+		sv := mock.Variable()
+		sv.Lock = &structs.VariableLock{TTL: 30 * time.Second}
+
+		var lockID string
+		t.Run("acquire", func(t *testing.T) {
+			req, err := http.NewRequest("PUT", "/v1/var/"+sv.Path+"?lock-acquire", encodeReq(sv))
+			require.NoError(t, err)
+			respW := httptest.NewRecorder()
+			obj, err := s.Server.VariableSpecificRequest(respW, req)
+			require.NoError(t, err)
+
+			out := obj.(*structs.VariableDecrypted)
+			require.NotNil(t, out.Lock)
+			require.NotEmpty(t, out.Lock.ID)
+			require.Equal(t, sv.Items, out.Items)
+			lockID = out.Lock.ID
+		})
+		t.Run("acquire_conflict", func(t *testing.T) {
+			req, err := http.NewRequest("PUT", "/v1/var/"+sv.Path+"?lock-acquire", encodeReq(sv))
+			require.NoError(t, err)
+			respW := httptest.NewRecorder()
+			obj, err := s.Server.VariableSpecificRequest(respW, req)
+			require.NoError(t, err)
+			require.Equal(t, http.StatusConflict, respW.Code)
+
+			conflict := obj.(*structs.VariableDecrypted)
+			require.Empty(t, conflict.Lock.ID)
+		})
+		t.Run("error_missing_lock", func(t *testing.T) {
+			noLock := sv.Copy()
+			noLock.Lock = nil
+			req, err := http.NewRequest("PUT", "/v1/var/"+sv.Path+"?lock-release", encodeReq(noLock))
+			require.NoError(t, err)
+			respW := httptest.NewRecorder()
+			_, err = s.Server.VariableSpecificRequest(respW, req)
+			require.EqualError(t, err, "variable missing required Lock object")
+		})
+		t.Run("renew", func(t *testing.T) {
+			held := sv.Copy()
+			held.Lock = &structs.VariableLock{ID: lockID}
+			req, err := http.NewRequest("PUT", "/v1/var/"+sv.Path+"?lock-renew", encodeReq(held))
+			require.NoError(t, err)
+			respW := httptest.NewRecorder()
+			obj, err := s.Server.VariableSpecificRequest(respW, req)
+			require.NoError(t, err)
+
+			out := obj.(*structs.VariableMetadata)
+			require.Equal(t, lockID, out.Lock.ID)
+		})
+		t.Run("release", func(t *testing.T) {
+			held := sv.Copy()
+			held.Lock = &structs.VariableLock{ID: lockID}
+			req, err := http.NewRequest("PUT", "/v1/var/"+sv.Path+"?lock-release", encodeReq(held))
+			require.NoError(t, err)
+			respW := httptest.NewRecorder()
+			obj, err := s.Server.VariableSpecificRequest(respW, req)
+			require.NoError(t, err)
+
+			out := obj.(*structs.VariableDecrypted)
+			require.Nil(t, out.Lock)
+
+			current, err := rpcReadSV(s, sv.Namespace, sv.Path)
+			require.NoError(t, err)
+			require.Nil(t, current.Lock)
+		})
+	})
+}
+
 // encodeBrokenReq is a test helper that damages input JSON in order to create
 // a parsing error for testing error pathways.
 func encodeBrokenReq(obj interface{}) io.ReadCloser {
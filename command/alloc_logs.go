@@ -63,6 +63,34 @@ Logs Specific Options:
   -c
     Sets the tail location in number of bytes relative to the end of the logs.
 
+  -grep <regex>
+    Only show lines matching the regular expression. Lines are filtered by the
+    client running the allocation, so only matching lines are transferred. The
+    -tail offsets apply to the logs before they are filtered.
+
+  -since <time>
+    Skip the rotated log files last written to before the time, given either
+    as an RFC 3339 timestamp or as a duration before now, such as "30m".
+    Whole files are skipped, so earlier lines of a file written to after the
+    time are still shown unless -json is set, which also filters each JSON
+    line by its timestamp.
+
+  -until <time>
+    Skip the rotated log files first written to after the time, in the same
+    format as -since. Whole files are skipped, so later lines of a file
+    written to before the time are still shown unless -json is set.
+
+  -json
+    Parse each line as a JSON object. JSON lines are filtered by the first of
+    their "time", "timestamp", "ts" or "@timestamp" fields holding an RFC 3339
+    timestamp or a Unix time. Unix times are read as seconds, milliseconds,
+    microseconds or nanoseconds depending on their magnitude. Other lines are
+    shown unless -fields is set.
+
+  -fields <fields>
+    Comma separated list of fields to show from JSON lines, such as
+    "level,msg,http.status". Requires -json.
+
   Note that the -no-color option applies to Nomad's own output. If the task's
   logs include terminal escape sequences for color codes, Nomad will not
   remove them.
@@ -86,6 +114,11 @@ func (l *AllocLogsCommand) AutocompleteFlags() complete.Flags {
 			"-tail":    complete.PredictAnything,
 			"-n":       complete.PredictAnything,
 			"-c":       complete.PredictAnything,
+			"-grep":    complete.PredictAnything,
+			"-since":   complete.PredictAnything,
+			"-until":   complete.PredictAnything,
+			"-json":    complete.PredictNothing,
+			"-fields":  complete.PredictAnything,
 		})
 }
 
@@ -107,9 +140,9 @@ func (l *AllocLogsCommand) AutocompleteArgs() complete.Predictor {
 func (l *AllocLogsCommand) Name() string { return "alloc logs" }
 
 func (l *AllocLogsCommand) Run(args []string) int {
-	var verbose, job, tail, stderr, follow bool
+	var verbose, job, tail, stderr, follow, jsonLines bool
 	var numLines, numBytes int64
-	var task string
+	var task, grep, since, until, fields string
 
 	flags := l.Meta.FlagSet(l.Name(), FlagSetClient)
 	flags.Usage = func() { l.Ui.Output(l.Help()) }
@@ -121,12 +154,24 @@ func (l *AllocLogsCommand) Run(args []string) int {
 	flags.Int64Var(&numLines, "n", -1, "")
 	flags.Int64Var(&numBytes, "c", -1, "")
 	flags.StringVar(&task, "task", "", "")
+	flags.StringVar(&grep, "grep", "", "")
+	flags.StringVar(&since, "since", "", "")
+	flags.StringVar(&until, "until", "", "")
+	flags.BoolVar(&jsonLines, "json", false, "")
+	flags.StringVar(&fields, "fields", "", "")
 
 	if err := flags.Parse(args); err != nil {
 		return 1
 	}
 	args = flags.Args()
 
+	filter, err := parseLogsFilter(grep, since, until, jsonLines, fields, time.Now())
+	if err != nil {
+		l.Ui.Error(err.Error())
+		l.Ui.Error(commandErrorText(l))
+		return 1
+	}
+
 	if numArgs := len(args); numArgs < 1 {
 		if job {
 			l.Ui.Error("A job ID is required")
@@ -222,7 +267,7 @@ func (l *AllocLogsCommand) Run(args []string) int {
 	var r io.ReadCloser
 	var readErr error
 	if !tail {
-		r, readErr = l.followFile(client, alloc, follow, task, logType, api.OriginStart, 0)
+		r, readErr = l.followFile(client, alloc, follow, task, logType, api.OriginStart, 0, filter)
 		if readErr != nil {
 			readErr = fmt.Errorf("Error reading file: %v", readErr)
 		}
@@ -241,7 +286,7 @@ func (l *AllocLogsCommand) Run(args []string) int {
 			numLines = defaultTailLines
 		}
 
-		r, readErr = l.followFile(client, alloc, follow, task, logType, api.OriginEnd, offset)
+		r, readErr = l.followFile(client, alloc, follow, task, logType, api.OriginEnd, offset, filter)
 
 		// If numLines is set, wrap the reader
 		if numLines != -1 {
@@ -271,10 +316,10 @@ func (l *AllocLogsCommand) Run(args []string) int {
 // followFile outputs the contents of the file to stdout relative to the end of
 // the file.
 func (l *AllocLogsCommand) followFile(client *api.Client, alloc *api.Allocation,
-	follow bool, task, logType, origin string, offset int64) (io.ReadCloser, error) {
+	follow bool, task, logType, origin string, offset int64, filter *api.LogsFilter) (io.ReadCloser, error) {
 
 	cancel := make(chan struct{})
-	frames, errCh := client.AllocFS().Logs(alloc, follow, task, logType, origin, offset, cancel, nil)
+	frames, errCh := client.AllocFS().LogsWithFilter(alloc, follow, task, logType, origin, offset, filter, cancel, nil)
 	select {
 	case err := <-errCh:
 		return nil, err
@@ -299,6 +344,48 @@ func (l *AllocLogsCommand) followFile(client *api.Client, alloc *api.Allocation,
 	return r, nil
 }
 
+// parseLogsFilter returns the filter for the log filtering flags, or nil if
+// none were set. Times are either RFC 3339 timestamps or durations before now.
+func parseLogsFilter(grep, since, until string, jsonLines bool, fields string, now time.Time) (*api.LogsFilter, error) {
+	if grep == "" && since == "" && until == "" && !jsonLines && fields == "" {
+		return nil, nil
+	}
+	if fields != "" && !jsonLines {
+		return nil, errors.New("-fields requires -json")
+	}
+
+	filter := &api.LogsFilter{
+		Match: grep,
+		JSON:  jsonLines,
+	}
+	if fields != "" {
+		filter.Fields = strings.Split(fields, ",")
+	}
+
+	for _, bound := range []struct {
+		flag  string
+		value string
+		out   *time.Time
+	}{
+		{"-since", since, &filter.Since},
+		{"-until", until, &filter.Until},
+	} {
+		if bound.value == "" {
+			continue
+		}
+		if t, err := time.Parse(time.RFC3339Nano, bound.value); err == nil {
+			*bound.out = t
+			continue
+		}
+		d, err := time.ParseDuration(bound.value)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid %s value %q: must be an RFC 3339 timestamp or a duration", bound.flag, bound.value)
+		}
+		*bound.out = now.Add(-d)
+	}
+	return filter, nil
+}
+
 func lookupAllocTask(alloc *api.Allocation) (string, error) {
 	tg := alloc.Job.LookupTaskGroup(alloc.TaskGroup)
 	if tg == nil {
@@ -2,7 +2,9 @@ package command
 
 import (
 	"testing"
+	"time"
 
+	"github.com/hashicorp/nomad/api"
 	"github.com/hashicorp/nomad/ci"
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -90,3 +92,31 @@ func TestLogsCommand_AutocompleteArgs(t *testing.T) {
 	must.Len(t, 1, res)
 	must.Eq(t, a.ID, res[0])
 }
+
+func TestLogsCommand_parseLogsFilter(t *testing.T) {
+	ci.Parallel(t)
+
+	now := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	filter, err := parseLogsFilter("", "", "", false, "", now)
+	must.NoError(t, err)
+	must.Nil(t, filter)
+
+	filter, err = parseLogsFilter("error", "30m", "2022-06-01T11:45:00Z", true, "level,msg", now)
+	must.NoError(t, err)
+	must.Eq(t, &api.LogsFilter{
+		Match:  "error",
+		Since:  now.Add(-30 * time.Minute),
+		Until:  time.Date(2022, 6, 1, 11, 45, 0, 0, time.UTC),
+		JSON:   true,
+		Fields: []string{"level", "msg"},
+	}, filter)
+
+	_, err = parseLogsFilter("", "yesterday", "", false, "", now)
+	must.Error(t, err)
+	must.StrContains(t, err.Error(), "Invalid -since value")
+
+	_, err = parseLogsFilter("", "", "", false, "msg", now)
+	must.Error(t, err)
+	must.StrContains(t, err.Error(), "-fields requires -json")
+}
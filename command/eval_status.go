@@ -32,6 +32,11 @@ Eval Status Options:
   -monitor
     Monitor an outstanding evaluation
 
+  -explain
+    Show the scores given by each scorer to the nodes scored for each
+    placement. Scores are only recorded for evaluations created with
+    "nomad job eval -explain".
+
   -verbose
     Show full information.
 
@@ -52,6 +57,7 @@ func (c *EvalStatusCommand) Synopsis() string {
 func (c *EvalStatusCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
+			"-explain": complete.PredictNothing,
 			"-json":    complete.PredictNothing,
 			"-monitor": complete.PredictNothing,
 			"-t":       complete.PredictAnything,
@@ -81,12 +87,13 @@ func (c *EvalStatusCommand) AutocompleteArgs() complete.Predictor {
 func (c *EvalStatusCommand) Name() string { return "eval status" }
 
 func (c *EvalStatusCommand) Run(args []string) int {
-	var monitor, verbose, json bool
+	var monitor, explain, verbose, json bool
 	var tmpl string
 
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
 	flags.BoolVar(&monitor, "monitor", false, "")
+	flags.BoolVar(&explain, "explain", false, "")
 	flags.BoolVar(&verbose, "verbose", false, "")
 	flags.BoolVar(&json, "json", false, "")
 	flags.StringVar(&tmpl, "t", "", "")
@@ -257,6 +264,51 @@ func (c *EvalStatusCommand) Run(args []string) int {
 		}
 	}
 
+	if explain {
+		return c.outputScoreExplanations(client, eval)
+	}
+
+	return 0
+}
+
+// outputScoreExplanations outputs the scores of every node scored for each
+// placement made by the evaluation, and for its failed placements.
+func (c *EvalStatusCommand) outputScoreExplanations(client *api.Client, eval *api.Evaluation) int {
+	stubs, _, err := client.Evaluations().Allocations(eval.ID, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error querying evaluation allocations: %s", err))
+		return 1
+	}
+	sort.Slice(stubs, func(i, j int) bool { return stubs[i].Name < stubs[j].Name })
+
+	var out string
+	for _, stub := range stubs {
+		alloc, _, err := client.Allocations().Info(stub.ID, nil)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error querying allocation %q: %s", stub.ID, err))
+			return 1
+		}
+		if alloc.Metrics == nil || len(alloc.Metrics.ScoreExplanation) == 0 {
+			continue
+		}
+		out += fmt.Sprintf("Allocation %q placed on node %q:\n", alloc.Name, alloc.NodeID)
+		out += formatScoreExplanation(alloc.Metrics.ScoreExplanation)
+	}
+	for _, tg := range sortedTaskGroupFromMetrics(eval.FailedTGAllocs) {
+		metrics := eval.FailedTGAllocs[tg]
+		if len(metrics.ScoreExplanation) == 0 {
+			continue
+		}
+		out += fmt.Sprintf("Task Group %q failed to place:\n", tg)
+		out += formatScoreExplanation(metrics.ScoreExplanation)
+	}
+
+	c.Ui.Output(c.Colorize().Color("\n[bold]Score Explanations[reset]"))
+	if out == "" {
+		c.Ui.Output("No scores were recorded for the evaluation")
+		return 0
+	}
+	c.Ui.Output(strings.TrimSuffix(out, "\n"))
 	return 0
 }
 
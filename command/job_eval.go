@@ -12,6 +12,7 @@ import (
 type JobEvalCommand struct {
 	Meta
 	forceRescheduling bool
+	explainScores     bool
 }
 
 func (c *JobEvalCommand) Help() string {
@@ -36,6 +37,11 @@ Eval Options:
     Force reschedule failed allocations even if they are not currently
     eligible for rescheduling.
 
+  -explain
+    Record the scores given by each scorer to the nodes scored for each
+    placement made by the evaluation, up to the 50 top scoring nodes. The
+    scores can be shown with "nomad eval status -explain".
+
   -detach
     Return immediately instead of entering monitor mode. The ID
     of the evaluation created will be printed to the screen, which can be
@@ -55,6 +61,7 @@ func (c *JobEvalCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
 			"-force-reschedule": complete.PredictNothing,
+			"-explain":          complete.PredictNothing,
 			"-detach":           complete.PredictNothing,
 			"-verbose":          complete.PredictNothing,
 		})
@@ -83,6 +90,7 @@ func (c *JobEvalCommand) Run(args []string) int {
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
 	flags.BoolVar(&c.forceRescheduling, "force-reschedule", false, "")
+	flags.BoolVar(&c.explainScores, "explain", false, "")
 	flags.BoolVar(&detach, "detach", false, "")
 	flags.BoolVar(&verbose, "verbose", false, "")
 
@@ -115,6 +123,7 @@ func (c *JobEvalCommand) Run(args []string) int {
 
 	opts := api.EvalOptions{
 		ForceReschedule: c.forceRescheduling,
+		ExplainScores:   c.explainScores,
 	}
 	evalId, _, err := client.Jobs().EvaluateWithOpts(jobID, opts, nil)
 	if err != nil {
@@ -81,6 +81,13 @@ Plan Options:
     Determines whether the diff between the remote job and planned job is shown.
     Defaults to true.
 
+  -explain
+    Shows the scores given by each scorer to every feasible node for each
+    placement, to explain why the nodes were selected. To do so the plan
+    scores every feasible node, while running the job only scores a limited
+    number of them, so the job may be placed on different nodes when run.
+    Use "nomad job eval -explain" to explain the placements of a run.
+
   -json
     Parses the job file as JSON. If the outer object has a Job field, such as
     from "nomad job inspect" or "nomad run -output", the value of the field is
@@ -133,6 +140,7 @@ func (c *JobPlanCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
 			"-diff":            complete.PredictNothing,
+			"-explain":         complete.PredictNothing,
 			"-policy-override": complete.PredictNothing,
 			"-verbose":         complete.PredictNothing,
 			"-json":            complete.PredictNothing,
@@ -155,12 +163,13 @@ func (c *JobPlanCommand) AutocompleteArgs() complete.Predictor {
 
 func (c *JobPlanCommand) Name() string { return "job plan" }
 func (c *JobPlanCommand) Run(args []string) int {
-	var diff, policyOverride, verbose bool
+	var diff, explain, policyOverride, verbose bool
 	var vaultToken, vaultNamespace string
 
 	flagSet := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flagSet.Usage = func() { c.Ui.Output(c.Help()) }
 	flagSet.BoolVar(&diff, "diff", true, "")
+	flagSet.BoolVar(&explain, "explain", false, "")
 	flagSet.BoolVar(&policyOverride, "policy-override", false, "")
 	flagSet.BoolVar(&verbose, "verbose", false, "")
 	flagSet.BoolVar(&c.JobGetter.JSON, "json", false, "")
@@ -237,6 +246,9 @@ func (c *JobPlanCommand) Run(args []string) int {
 	if policyOverride {
 		opts.PolicyOverride = true
 	}
+	if explain {
+		opts.Explain = true
+	}
 
 	if job.IsMultiregion() {
 		return c.multiregionPlan(client, job, opts, diff, verbose)
@@ -309,12 +321,27 @@ func (c *JobPlanCommand) outputPlannedJob(job *api.Job, resp *api.JobPlanRespons
 	c.Ui.Output(c.Colorize().Color(formatDryRun(resp, job)))
 	c.Ui.Output("")
 
+	// Print the score explanations if they were requested
+	if explanations := formatPlanScoreExplanations(resp); explanations != "" {
+		c.Ui.Output(c.Colorize().Color("[bold]Score Explanations:[reset]"))
+		c.Ui.Output(explanations)
+		c.Ui.Output("")
+	}
+
 	// Print any warnings if there are any
 	if resp.Warnings != "" {
 		c.Ui.Output(
 			c.Colorize().Color(fmt.Sprintf("[bold][yellow]Job Warnings:\n%s[reset]\n", resp.Warnings)))
 	}
 
+	// Print the preemption priority if the namespace of the job caps it
+	if resp.Annotations != nil && job.Priority != nil &&
+		resp.Annotations.PreemptionPriority > 0 && resp.Annotations.PreemptionPriority < *job.Priority {
+		c.Ui.Output(c.Colorize().Color(fmt.Sprintf(
+			"[bold][yellow]Preemption Priority:\n[reset]Allocations preempt as priority %d, capped by the namespace preemption priority ceiling\n",
+			resp.Annotations.PreemptionPriority)))
+	}
+
 	// Print preemptions if there are any
 	if resp.Annotations != nil && len(resp.Annotations.PreemptedAllocs) > 0 {
 		c.addPreemptions(resp)
@@ -459,6 +486,35 @@ func formatDryRun(resp *api.JobPlanResponse, job *api.Job) string {
 	return out
 }
 
+// formatPlanScoreExplanations produces a table of the scores of every node
+// scored for each placement of the plan, including the failed placements.
+func formatPlanScoreExplanations(resp *api.JobPlanResponse) string {
+	var out string
+	for _, placement := range resp.ScoreExplanations {
+		out += fmt.Sprintf("Allocation %q placed on node %q:\n", placement.AllocName, placement.NodeID)
+		out += formatScoreExplanation(placement.Scores)
+	}
+	for _, tg := range sortedTaskGroupFromMetrics(resp.FailedTGAllocs) {
+		metrics := resp.FailedTGAllocs[tg]
+		if len(metrics.ScoreExplanation) == 0 {
+			continue
+		}
+		out += fmt.Sprintf("Task Group %q failed to place:\n", tg)
+		out += formatScoreExplanation(metrics.ScoreExplanation)
+	}
+
+	out = strings.TrimSuffix(out, "\n")
+	return out
+}
+
+// formatScoreExplanation produces the table of the scores of a placement.
+func formatScoreExplanation(scores []*api.NodeScoreMeta) string {
+	if len(scores) == 0 {
+		return "No nodes were scored\n\n"
+	}
+	return formatNodeScores(scores) + "\n\n"
+}
+
 // formatJobDiff produces an annotated diff of the job. If verbose mode is
 // set, added or deleted task groups and tasks are expanded.
 func formatJobDiff(job *api.JobDiff, verbose bool) string {
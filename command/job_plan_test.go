@@ -326,3 +326,38 @@ func TestPlanCommad_JSON(t *testing.T) {
 	require.Equal(t, 255, code)
 	require.Contains(t, ui.ErrorWriter.String(), "Error during plan: Put")
 }
+
+func TestPlanCommand_ScoreExplanations(t *testing.T) {
+	ci.Parallel(t)
+
+	// No explanations are shown unless requested
+	require.Empty(t, formatPlanScoreExplanations(&api.JobPlanResponse{}))
+
+	resp := &api.JobPlanResponse{
+		ScoreExplanations: []*api.PlacementExplanation{
+			{
+				AllocName: "example.web[0]",
+				TaskGroup: "web",
+				NodeID:    "node1",
+				Scores: []*api.NodeScoreMeta{
+					{NodeID: "node1", Scores: map[string]float64{"binpack": 0.8, "job-anti-affinity": 0}, NormScore: 0.4},
+					{NodeID: "node2", Scores: map[string]float64{"binpack": 0.5, "job-anti-affinity": -0.5}, NormScore: 0},
+				},
+			},
+		},
+		FailedTGAllocs: map[string]*api.AllocationMetric{
+			"cache": {
+				ScoreExplanation: []*api.NodeScoreMeta{
+					{NodeID: "node3", Scores: map[string]float64{"binpack": 0.1, "preemption": 0.2}, NormScore: 0.15},
+				},
+			},
+		},
+	}
+	out := formatPlanScoreExplanations(resp)
+	require.Contains(t, out, `Allocation "example.web[0]" placed on node "node1"`)
+	require.Contains(t, out, `Task Group "cache" failed to place`)
+	require.Regexp(t, `Node\s+binpack\s+job-anti-affinity\s+final score`, out)
+	require.Regexp(t, `node2\s+0.5\s+-0.5\s+0`, out)
+	require.Regexp(t, `Node\s+binpack\s+preemption\s+final score`, out)
+	require.Regexp(t, `node3\s+0.1\s+0.2\s+0.15`, out)
+}
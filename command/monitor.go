@@ -356,34 +356,7 @@ func formatAllocMetrics(metrics *api.AllocationMetric, scores bool, prefix strin
 	// Print scores
 	if scores {
 		if len(metrics.ScoreMetaData) > 0 {
-			scoreOutput := make([]string, len(metrics.ScoreMetaData)+1)
-
-			// Find all possible scores and build header row.
-			allScores := make(map[string]struct{})
-			for _, scoreMeta := range metrics.ScoreMetaData {
-				for score := range scoreMeta.Scores {
-					allScores[score] = struct{}{}
-				}
-			}
-			// Sort scores alphabetically.
-			scores := make([]string, 0, len(allScores))
-			for score := range allScores {
-				scores = append(scores, score)
-			}
-			sort.Strings(scores)
-			scoreOutput[0] = fmt.Sprintf("Node|%s|final score", strings.Join(scores, "|"))
-
-			// Build row for each score.
-			for i, scoreMeta := range metrics.ScoreMetaData {
-				scoreOutput[i+1] = fmt.Sprintf("%v|", scoreMeta.NodeID)
-				for _, scorerName := range scores {
-					scoreVal := scoreMeta.Scores[scorerName]
-					scoreOutput[i+1] += fmt.Sprintf("%.3g|", scoreVal)
-				}
-				scoreOutput[i+1] += fmt.Sprintf("%.3g", scoreMeta.NormScore)
-			}
-
-			out += formatList(scoreOutput)
+			out += formatNodeScores(metrics.ScoreMetaData)
 		} else {
 			// Backwards compatibility for old allocs
 			for name, score := range metrics.Scores {
@@ -395,3 +368,36 @@ func formatAllocMetrics(metrics *api.AllocationMetric, scores bool, prefix strin
 	out = strings.TrimSuffix(out, "\n")
 	return out
 }
+
+// formatNodeScores produces a table of the scores of the given nodes, with a
+// column for each scorer and one for the final normalized score.
+func formatNodeScores(scoreMetas []*api.NodeScoreMeta) string {
+	scoreOutput := make([]string, len(scoreMetas)+1)
+
+	// Find all possible scores and build header row.
+	allScores := make(map[string]struct{})
+	for _, scoreMeta := range scoreMetas {
+		for score := range scoreMeta.Scores {
+			allScores[score] = struct{}{}
+		}
+	}
+	// Sort scores alphabetically.
+	scores := make([]string, 0, len(allScores))
+	for score := range allScores {
+		scores = append(scores, score)
+	}
+	sort.Strings(scores)
+	scoreOutput[0] = fmt.Sprintf("Node|%s|final score", strings.Join(scores, "|"))
+
+	// Build row for each score.
+	for i, scoreMeta := range scoreMetas {
+		scoreOutput[i+1] = fmt.Sprintf("%v|", scoreMeta.NodeID)
+		for _, scorerName := range scores {
+			scoreVal := scoreMeta.Scores[scorerName]
+			scoreOutput[i+1] += fmt.Sprintf("%.3g|", scoreVal)
+		}
+		scoreOutput[i+1] += fmt.Sprintf("%.3g", scoreMeta.NormScore)
+	}
+
+	return formatList(scoreOutput)
+}
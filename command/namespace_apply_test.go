@@ -57,3 +57,24 @@ func TestNamespaceApplyCommand_Good(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Len(t, namespaces, 2)
 }
+
+func TestNamespaceApplyCommand_parseNamespaceSpec(t *testing.T) {
+	ci.Parallel(t)
+
+	spec, err := parseNamespaceSpec([]byte(`
+name                        = "tenant"
+description                 = "A tenant namespace"
+preemption_priority_ceiling = 60
+scheduler_algorithm         = "spread"
+
+capabilities {
+  enabled_task_drivers = ["docker"]
+}
+`))
+	assert.Nil(t, err)
+	assert.Equal(t, "tenant", spec.Name)
+	assert.Equal(t, "A tenant namespace", spec.Description)
+	assert.Equal(t, 60, spec.PreemptionPriorityCeiling)
+	assert.Equal(t, "spread", string(spec.SchedulerAlgorithm))
+	assert.Equal(t, []string{"docker"}, spec.Capabilities.EnabledTaskDrivers)
+}
@@ -139,6 +139,12 @@ func formatNamespaceBasics(ns *api.Namespace) string {
 		fmt.Sprintf("EnabledDrivers|%s", enabled_drivers),
 		fmt.Sprintf("DisabledDrivers|%s", disabled_drivers),
 	}
+	if ns.PreemptionPriorityCeiling != 0 {
+		basic = append(basic, fmt.Sprintf("PreemptionPriorityCeiling|%d", ns.PreemptionPriorityCeiling))
+	}
+	if ns.SchedulerAlgorithm != "" {
+		basic = append(basic, fmt.Sprintf("SchedulerAlgorithm|%s", ns.SchedulerAlgorithm))
+	}
 
 	return formatKV(basic)
 }
@@ -158,8 +158,11 @@ Debug Options:
     no longer in the buffer the stream will start at the next available index.
     Defaults to 0.
 
-  -event-topic=<Allocation,Evaluation,Job,Node,*>:<filter>
+  -event-topic=<topic>:<filter>
     Enable event stream capture, filtered by comma delimited list of topic filters.
+    Topics are Allocation, Deployment, Evaluation, Job, Node, Service, Variable,
+    Namespace, ACLRole and RootKey, or "*" for all topics. The Variable,
+    Namespace, ACLRole and RootKey topics require a management token.
     Examples:
       "all" or "*:*" for all events
       "Evaluation" or "Evaluation:*" for all evaluation events
@@ -1584,6 +1587,21 @@ func parseEventTopics(topicList []string) (map[api.Topic][]string, error) {
 	return topics, mErrs.ErrorOrNil()
 }
 
+// eventTopics are the topics of the event stream, which topics are matched
+// against regardless of case.
+var eventTopics = []api.Topic{
+	api.TopicAllocation,
+	api.TopicDeployment,
+	api.TopicEvaluation,
+	api.TopicJob,
+	api.TopicNode,
+	api.TopicService,
+	api.TopicVariable,
+	api.TopicNamespace,
+	api.TopicACLRole,
+	api.TopicRootKey,
+}
+
 func parseTopic(input string) (string, string, error) {
 	var topic, filter string
 
@@ -1600,6 +1618,11 @@ func parseTopic(input string) (string, string, error) {
 		return "", "", fmt.Errorf("Invalid key value pair for topic: %s", topic)
 	}
 
+	for _, t := range eventTopics {
+		if strings.EqualFold(topic, string(t)) {
+			return string(t), filter, nil
+		}
+	}
 	return strings.Title(topic), filter, nil
 }
 
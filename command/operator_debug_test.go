@@ -950,6 +950,16 @@ func TestDebug_EventStream_TopicsFromString(t *testing.T) {
 				api.TopicNode:       {"*"},
 			},
 		},
+		{
+			name:      "mixed case topics",
+			topicList: "aclrole,ROOTKEY,variable:prod/*,namespace",
+			want: map[api.Topic][]string{
+				api.TopicACLRole:   {"*"},
+				api.TopicRootKey:   {"*"},
+				api.TopicVariable:  {"prod/*"},
+				api.TopicNamespace: {"*"},
+			},
+		},
 		{
 			name:      "all topics for filterKey",
 			topicList: "*:example",
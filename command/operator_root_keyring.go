@@ -79,8 +79,12 @@ func renderVariablesKeysResponse(keys []*api.RootKeyMeta, verbose bool) string {
 	out[0] = "Key|State|Create Time"
 	i := 1
 	for _, k := range keys {
-		out[i] = fmt.Sprintf("%s|%v|%s",
-			k.KeyID[:length], k.State, formatUnixNanoTime(k.CreateTime))
+		state := string(k.State)
+		if k.State == api.RootKeyStateRekeying {
+			state = fmt.Sprintf("%s (%d remaining)", state, k.RekeyRemaining)
+		}
+		out[i] = fmt.Sprintf("%s|%s|%s",
+			k.KeyID[:length], state, formatUnixNanoTime(k.CreateTime))
 		i = i + 1
 	}
 	return formatList(out)
@@ -28,6 +28,14 @@ Usage: nomad operator scheduler <subcommand> [options]
 
       $ nomad operator scheduler set-config -scheduler-algorithm=spread
 
+  Report the allocations which would be rebalanced:
+
+      $ nomad operator scheduler rebalance -dry-run
+
+  Simulate draining a node against a snapshot:
+
+      $ nomad operator scheduler simulate -drain-node=f4a1 backup.snap
+
   Please see the individual subcommand help for detailed usage information.
 `
 	return strings.TrimSpace(helpText)
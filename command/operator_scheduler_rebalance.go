@@ -0,0 +1,198 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
+)
+
+// Ensure OperatorSchedulerRebalance satisfies the cli.Command interface.
+var _ cli.Command = &OperatorSchedulerRebalance{}
+
+type OperatorSchedulerRebalance struct {
+	Meta
+
+	dryRun              bool
+	jobID               string
+	minScoreImprovement float64
+	verbose             bool
+	json                bool
+	tmpl                string
+}
+
+func (o *OperatorSchedulerRebalance) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(o.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-dry-run":               complete.PredictNothing,
+			"-job":                   complete.PredictAnything,
+			"-min-score-improvement": complete.PredictAnything,
+			"-verbose":               complete.PredictNothing,
+			"-json":                  complete.PredictNothing,
+			"-t":                     complete.PredictAnything,
+		},
+	)
+}
+
+func (o *OperatorSchedulerRebalance) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (o *OperatorSchedulerRebalance) Name() string { return "operator scheduler rebalance" }
+
+func (o *OperatorSchedulerRebalance) Run(args []string) int {
+
+	flags := o.Meta.FlagSet("rebalance", FlagSetClient)
+	flags.BoolVar(&o.dryRun, "dry-run", false, "")
+	flags.StringVar(&o.jobID, "job", "", "")
+	flags.Float64Var(&o.minScoreImprovement, "min-score-improvement", 0, "")
+	flags.BoolVar(&o.verbose, "verbose", false, "")
+	flags.BoolVar(&o.json, "json", false, "")
+	flags.StringVar(&o.tmpl, "t", "", "")
+	flags.Usage = func() { o.Ui.Output(o.Help()) }
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got no arguments.
+	if len(flags.Args()) != 0 {
+		o.Ui.Error("This command takes no arguments")
+		o.Ui.Error(commandErrorText(o))
+		return 1
+	}
+
+	if o.minScoreImprovement < 0 {
+		o.Ui.Error("Minimum score improvement must not be negative")
+		return 1
+	}
+
+	// Set up a client.
+	client, err := o.Meta.Client()
+	if err != nil {
+		o.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	req := &api.SchedulerRebalanceRequest{
+		JobID:               o.jobID,
+		MinScoreImprovement: o.minScoreImprovement,
+		DryRun:              o.dryRun,
+	}
+	resp, _, err := client.Operator().SchedulerRebalance(req, nil)
+	if err != nil {
+		o.Ui.Error(fmt.Sprintf("Error rebalancing allocations: %s", err))
+		return 1
+	}
+
+	if o.json || len(o.tmpl) > 0 {
+		out, err := Format(o.json, o.tmpl, resp)
+		if err != nil {
+			o.Ui.Error(err.Error())
+			return 1
+		}
+		o.Ui.Output(out)
+		return 0
+	}
+
+	if len(resp.Migrations) == 0 {
+		o.Ui.Output("No allocations would improve their placement score by migrating")
+		return 0
+	}
+
+	length := shortId
+	if o.verbose {
+		length = fullId
+	}
+
+	total := 0.0
+	rows := make([]string, len(resp.Migrations)+1)
+	rows[0] = "Alloc ID|Namespace|Job ID|Task Group|From Node|To Node|Current Score|Expected Score"
+	for i, m := range resp.Migrations {
+		improvement := m.ExpectedScore - m.CurrentScore
+		total += improvement
+		rows[i+1] = fmt.Sprintf("%s|%s|%s|%s|%s|%s|%.3g|%.3g",
+			limit(m.AllocID, length),
+			m.Namespace,
+			m.JobID,
+			m.TaskGroup,
+			limit(m.FromNodeID, length),
+			limit(m.ToNodeID, length),
+			m.CurrentScore,
+			m.ExpectedScore)
+	}
+
+	if o.dryRun {
+		o.Ui.Output(o.Colorize().Color("[bold]Migrations (dry-run)[reset]"))
+	} else {
+		o.Ui.Output(o.Colorize().Color("[bold]Migrations[reset]"))
+	}
+	o.Ui.Output(formatList(rows))
+	o.Ui.Output("")
+	o.Ui.Output(formatKV([]string{
+		fmt.Sprintf("Allocations|%d", len(resp.Migrations)),
+		fmt.Sprintf("Total Score Improvement|%.3g", total),
+		fmt.Sprintf("Mean Score Improvement|%.3g", total/float64(len(resp.Migrations))),
+	}))
+
+	if len(resp.EvalIDs) > 0 {
+		o.Ui.Output("")
+		o.Ui.Output(o.Colorize().Color("[bold]Evaluations[reset]"))
+		for _, evalID := range resp.EvalIDs {
+			o.Ui.Output(limit(evalID, length))
+		}
+	}
+	return 0
+}
+
+func (o *OperatorSchedulerRebalance) Synopsis() string {
+	return "Migrate allocations to nodes where they would score better"
+}
+
+func (o *OperatorSchedulerRebalance) Help() string {
+	helpText := `
+Usage: nomad operator scheduler rebalance [options]
+
+  Rebalance compares the placement score of each running allocation of service
+  jobs with the best score the scheduler could find for it on another node.
+  Allocations which would improve their score by at least the minimum
+  improvement are migrated, honoring the max_parallel of the task group's
+  migrate block. The -dry-run flag reports the migrations and their expected
+  score improvement without stopping any allocation.
+
+  Jobs in the namespace of the command are rebalanced, or in all namespaces if
+  the namespace is "*".
+
+  If ACLs are enabled, this command requires a token with the 'operator:write'
+  capability, or 'operator:read' for a dry-run.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault) + `
+
+Scheduler Rebalance Options:
+
+  -dry-run
+    Report the migrations without performing them.
+
+  -job=<id>
+    Only rebalance the allocations of the given job.
+
+  -min-score-improvement=<score>
+    The minimum improvement of the normalized placement score for an
+    allocation to be migrated. Defaults to 0.1.
+
+  -verbose
+    Display full information.
+
+  -json
+    Output the rebalance result in its JSON format.
+
+  -t
+    Format and display the rebalance result using a Go template.
+`
+
+	return strings.TrimSpace(helpText)
+}
@@ -0,0 +1,43 @@
+package command
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOperatorSchedulerRebalance_Run(t *testing.T) {
+	ci.Parallel(t)
+
+	srv, _, addr := testServer(t, false, nil)
+	defer srv.Shutdown()
+
+	ui := cli.NewMockUi()
+	c := &OperatorSchedulerRebalance{Meta: Meta{Ui: ui}}
+
+	// Run the command against an empty cluster.
+	require.EqualValues(t, 0, c.Run([]string{"-address=" + addr, "-dry-run"}))
+	require.Contains(t, ui.OutputWriter.String(), "No allocations would improve their placement score")
+	ui.ErrorWriter.Reset()
+	ui.OutputWriter.Reset()
+
+	// Request JSON output and test.
+	require.EqualValues(t, 0, c.Run([]string{"-address=" + addr, "-dry-run", "-json"}))
+	var js api.SchedulerRebalanceResponse
+	require.NoError(t, json.Unmarshal(ui.OutputWriter.Bytes(), &js))
+	require.Empty(t, js.Migrations)
+	ui.ErrorWriter.Reset()
+	ui.OutputWriter.Reset()
+
+	// Test invalid flags.
+	require.EqualValues(t, 1, c.Run([]string{"-address=" + addr, "-min-score-improvement=-1"}))
+	require.Contains(t, ui.ErrorWriter.String(), "must not be negative")
+	ui.ErrorWriter.Reset()
+
+	require.EqualValues(t, 1, c.Run([]string{"-address=" + addr, "-job=unknown"}))
+	require.Contains(t, ui.ErrorWriter.String(), `job "unknown" not found`)
+}
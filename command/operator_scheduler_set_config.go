@@ -2,6 +2,7 @@ package command
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/hashicorp/nomad/api"
@@ -21,6 +22,7 @@ type OperatorSchedulerSetConfig struct {
 	// with user supplied, selective updates.
 	checkIndex               string
 	schedulerAlgorithm       string
+	headroomPercent          string
 	memoryOversubscription   flagHelper.BoolValue
 	rejectJobRegistration    flagHelper.BoolValue
 	pauseEvalBroker          flagHelper.BoolValue
@@ -28,6 +30,7 @@ type OperatorSchedulerSetConfig struct {
 	preemptServiceScheduler  flagHelper.BoolValue
 	preemptSysBatchScheduler flagHelper.BoolValue
 	preemptSystemScheduler   flagHelper.BoolValue
+	maxPreemptionsPerMinute  string
 }
 
 func (o *OperatorSchedulerSetConfig) AutocompleteFlags() complete.Flags {
@@ -37,7 +40,9 @@ func (o *OperatorSchedulerSetConfig) AutocompleteFlags() complete.Flags {
 			"-scheduler-algorithm": complete.PredictSet(
 				string(api.SchedulerAlgorithmBinpack),
 				string(api.SchedulerAlgorithmSpread),
+				string(api.SchedulerAlgorithmLeastRequestedHeadroom),
 			),
+			"-headroom-percent":           complete.PredictAnything,
 			"-memory-oversubscription":    complete.PredictSet("true", "false"),
 			"-reject-job-registration":    complete.PredictSet("true", "false"),
 			"-pause-eval-broker":          complete.PredictSet("true", "false"),
@@ -45,6 +50,7 @@ func (o *OperatorSchedulerSetConfig) AutocompleteFlags() complete.Flags {
 			"-preempt-service-scheduler":  complete.PredictSet("true", "false"),
 			"-preempt-sysbatch-scheduler": complete.PredictSet("true", "false"),
 			"-preempt-system-scheduler":   complete.PredictSet("true", "false"),
+			"-max-preemptions-per-minute": complete.PredictAnything,
 		},
 	)
 }
@@ -62,6 +68,7 @@ func (o *OperatorSchedulerSetConfig) Run(args []string) int {
 
 	flags.StringVar(&o.checkIndex, "check-index", "", "")
 	flags.StringVar(&o.schedulerAlgorithm, "scheduler-algorithm", "", "")
+	flags.StringVar(&o.headroomPercent, "headroom-percent", "", "")
 	flags.Var(&o.memoryOversubscription, "memory-oversubscription", "")
 	flags.Var(&o.rejectJobRegistration, "reject-job-registration", "")
 	flags.Var(&o.pauseEvalBroker, "pause-eval-broker", "")
@@ -69,6 +76,7 @@ func (o *OperatorSchedulerSetConfig) Run(args []string) int {
 	flags.Var(&o.preemptServiceScheduler, "preempt-service-scheduler", "")
 	flags.Var(&o.preemptSysBatchScheduler, "preempt-sysbatch-scheduler", "")
 	flags.Var(&o.preemptSystemScheduler, "preempt-system-scheduler", "")
+	flags.StringVar(&o.maxPreemptionsPerMinute, "max-preemptions-per-minute", "", "")
 
 	if err := flags.Parse(args); err != nil {
 		return 1
@@ -124,6 +132,15 @@ func (o *OperatorSchedulerSetConfig) Run(args []string) int {
 	if o.schedulerAlgorithm != "" {
 		schedulerConfig.SchedulerAlgorithm = api.SchedulerAlgorithm(o.schedulerAlgorithm)
 	}
+	if o.headroomPercent != "" {
+		headroom, err := strconv.Atoi(o.headroomPercent)
+		if err != nil || headroom < 0 || headroom > 99 {
+			o.Ui.Error(fmt.Sprintf("Invalid headroom-percent value %q: must be an integer between 0 and 99",
+				o.headroomPercent))
+			return 1
+		}
+		schedulerConfig.HeadroomPercent = headroom
+	}
 	o.memoryOversubscription.Merge(&schedulerConfig.MemoryOversubscriptionEnabled)
 	o.rejectJobRegistration.Merge(&schedulerConfig.RejectJobRegistration)
 	o.pauseEvalBroker.Merge(&schedulerConfig.PauseEvalBroker)
@@ -131,6 +148,15 @@ func (o *OperatorSchedulerSetConfig) Run(args []string) int {
 	o.preemptServiceScheduler.Merge(&schedulerConfig.PreemptionConfig.ServiceSchedulerEnabled)
 	o.preemptSysBatchScheduler.Merge(&schedulerConfig.PreemptionConfig.SysBatchSchedulerEnabled)
 	o.preemptSystemScheduler.Merge(&schedulerConfig.PreemptionConfig.SystemSchedulerEnabled)
+	if o.maxPreemptionsPerMinute != "" {
+		maxPreemptions, err := strconv.Atoi(o.maxPreemptionsPerMinute)
+		if err != nil || maxPreemptions < 0 {
+			o.Ui.Error(fmt.Sprintf("Invalid max-preemptions-per-minute value %q: must be a non-negative integer",
+				o.maxPreemptionsPerMinute))
+			return 1
+		}
+		schedulerConfig.PreemptionConfig.MaxPreemptionsPerMinute = maxPreemptions
+	}
 
 	// Check-and-set the new configuration.
 	result, _, err := client.Operator().SchedulerCASConfiguration(schedulerConfig, nil)
@@ -170,9 +196,15 @@ Scheduler Set Config Options:
     matches the current server side version. If a non-zero value is passed, it
     ensures that the scheduler config is being updated from a known state.
 
-  -scheduler-algorithm=["binpack"|"spread"]
+  -scheduler-algorithm=["binpack"|"spread"|"least-requested-with-headroom"]
     Specifies whether scheduler binpacks or spreads allocations on available
-    nodes.
+    nodes, or places them on the nodes with the most free resources while
+    keeping the headroom of each node free. Namespaces and jobs may override
+    the algorithm.
+
+  -headroom-percent=<percent>
+    Specifies the percentage of the CPU and memory of each node which the
+    least-requested-with-headroom algorithm keeps free.
 
   -memory-oversubscription=[true|false]
     When true, tasks may exceed their reserved memory limit, if the client has
@@ -205,6 +237,11 @@ Scheduler Set Config Options:
   -preempt-system-scheduler=[true|false]
     Specifies whether preemption for system jobs is enabled. Note that if this
     is set to true, then system jobs can preempt any other jobs.
+
+  -max-preemptions-per-minute=<count>
+    Specifies the budget of allocations which may be preempted within a
+    minute. Placements whose preemptions exceed the budget are rejected by the
+    plan applier and retried later. A value of 0 disables the budget.
 `
 	return strings.TrimSpace(helpText)
 }
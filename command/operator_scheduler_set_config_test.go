@@ -41,6 +41,7 @@ func TestOperatorSchedulerSetConfig_Run(t *testing.T) {
 	modifyingArgs := []string{
 		"-address=" + addr,
 		"-scheduler-algorithm=spread",
+		"-headroom-percent=15",
 		"-pause-eval-broker=true",
 		"-memory-oversubscription=true",
 		"-reject-job-registration=true",
@@ -48,6 +49,7 @@ func TestOperatorSchedulerSetConfig_Run(t *testing.T) {
 		"-preempt-service-scheduler=true",
 		"-preempt-sysbatch-scheduler=true",
 		"-preempt-system-scheduler=false",
+		"-max-preemptions-per-minute=20",
 	}
 	require.EqualValues(t, 0, c.Run(modifyingArgs))
 	s := ui.OutputWriter.String()
@@ -57,11 +59,13 @@ func TestOperatorSchedulerSetConfig_Run(t *testing.T) {
 	require.NoError(t, err)
 	schedulerConfigEquals(t, &api.SchedulerConfiguration{
 		SchedulerAlgorithm: "spread",
+		HeadroomPercent:    15,
 		PreemptionConfig: api.PreemptionConfig{
 			SystemSchedulerEnabled:   false,
 			SysBatchSchedulerEnabled: true,
 			BatchSchedulerEnabled:    true,
 			ServiceSchedulerEnabled:  true,
+			MaxPreemptionsPerMinute:  20,
 		},
 		MemoryOversubscriptionEnabled: true,
 		RejectJobRegistration:         true,
@@ -101,6 +105,7 @@ func TestOperatorSchedulerSetConfig_Run(t *testing.T) {
 
 func schedulerConfigEquals(t *testing.T, expected, actual *api.SchedulerConfiguration) {
 	require.Equal(t, expected.SchedulerAlgorithm, actual.SchedulerAlgorithm)
+	require.Equal(t, expected.HeadroomPercent, actual.HeadroomPercent)
 	require.Equal(t, expected.RejectJobRegistration, actual.RejectJobRegistration)
 	require.Equal(t, expected.MemoryOversubscriptionEnabled, actual.MemoryOversubscriptionEnabled)
 	require.Equal(t, expected.PauseEvalBroker, actual.PauseEvalBroker)
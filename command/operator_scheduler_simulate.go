@@ -0,0 +1,355 @@
+package command
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-hclog"
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/command/agent"
+	flaghelper "github.com/hashicorp/nomad/helper/flags"
+	"github.com/hashicorp/nomad/helper/raftutil"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/scheduler"
+	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
+)
+
+// Ensure OperatorSchedulerSimulate satisfies the cli.Command interface.
+var _ cli.Command = &OperatorSchedulerSimulate{}
+
+type OperatorSchedulerSimulate struct {
+	Meta
+	JobGetter
+
+	drainNodes flaghelper.StringFlag
+	jobFiles   flaghelper.StringFlag
+	verbose    bool
+	json       bool
+	tmpl       string
+}
+
+func (o *OperatorSchedulerSimulate) AutocompleteFlags() complete.Flags {
+	return complete.Flags{
+		"-drain-node": complete.PredictAnything,
+		"-job":        complete.PredictFiles("*"),
+		"-hcl1":       complete.PredictNothing,
+		"-var":        complete.PredictAnything,
+		"-var-file":   complete.PredictFiles("*.var"),
+		"-verbose":    complete.PredictNothing,
+		"-json":       complete.PredictNothing,
+		"-t":          complete.PredictAnything,
+	}
+}
+
+func (o *OperatorSchedulerSimulate) AutocompleteArgs() complete.Predictor {
+	return complete.PredictFiles("*")
+}
+
+func (o *OperatorSchedulerSimulate) Name() string { return "operator scheduler simulate" }
+
+func (o *OperatorSchedulerSimulate) Run(args []string) int {
+
+	flags := o.Meta.FlagSet(o.Name(), FlagSetNone)
+	flags.Var(&o.drainNodes, "drain-node", "")
+	flags.Var(&o.jobFiles, "job", "")
+	flags.BoolVar(&o.JobGetter.HCL1, "hcl1", false, "")
+	flags.Var(&o.JobGetter.Vars, "var", "")
+	flags.Var(&o.JobGetter.VarFiles, "var-file", "")
+	flags.BoolVar(&o.verbose, "verbose", false, "")
+	flags.BoolVar(&o.json, "json", false, "")
+	flags.StringVar(&o.tmpl, "t", "", "")
+	flags.Usage = func() { o.Ui.Output(o.Help()) }
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got exactly one snapshot file.
+	if len(flags.Args()) != 1 {
+		o.Ui.Error("This command takes one argument: <file>")
+		o.Ui.Error(commandErrorText(o))
+		return 1
+	}
+
+	if len(o.drainNodes) == 0 && len(o.jobFiles) == 0 {
+		o.Ui.Error("At least one -drain-node or -job flag must be given")
+		return 1
+	}
+
+	if err := o.JobGetter.Validate(); err != nil {
+		o.Ui.Error(fmt.Sprintf("Invalid job options: %s", err))
+		return 1
+	}
+
+	// Parse the jobs before the snapshot, which may take a while to restore
+	jobs := make([]*structs.Job, 0, len(o.jobFiles))
+	for _, path := range o.jobFiles {
+		aj, err := o.JobGetter.Get(path)
+		if err != nil {
+			o.Ui.Error(fmt.Sprintf("Error getting job struct: %s", err))
+			return 1
+		}
+		job := agent.ApiJobToStructJob(aj)
+		job.Canonicalize()
+		if err := job.Validate(); err != nil {
+			o.Ui.Error(fmt.Sprintf("Job %q is invalid: %s", job.ID, err))
+			return 1
+		}
+		jobs = append(jobs, job)
+	}
+
+	path := flags.Args()[0]
+	f, err := os.Open(path)
+	if err != nil {
+		o.Ui.Error(fmt.Sprintf("Error opening snapshot file: %s", err))
+		return 1
+	}
+	defer f.Close()
+
+	store, _, err := raftutil.RestoreFromArchive(f, nil)
+	if err != nil {
+		o.Ui.Error(fmt.Sprintf("Failed to read archive file: %s", err))
+		return 1
+	}
+
+	sim, err := scheduler.NewSimulation(hclog.NewNullLogger(), store)
+	if err != nil {
+		o.Ui.Error(fmt.Sprintf("Error setting up simulation: %s", err))
+		return 1
+	}
+
+	for _, prefix := range o.drainNodes {
+		nodeID, err := simulateLookupNode(store, prefix)
+		if err != nil {
+			o.Ui.Error(err.Error())
+			return 1
+		}
+		if err := sim.DrainNode(nodeID); err != nil {
+			o.Ui.Error(fmt.Sprintf("Error draining node %q: %s", prefix, err))
+			return 1
+		}
+	}
+	for _, job := range jobs {
+		if err := sim.RegisterJob(job); err != nil {
+			o.Ui.Error(fmt.Sprintf("Error registering job %q: %s", job.ID, err))
+			return 1
+		}
+	}
+
+	result, err := sim.Run()
+	if err != nil {
+		o.Ui.Error(fmt.Sprintf("Error running simulation: %s", err))
+		return 1
+	}
+
+	if o.json || len(o.tmpl) > 0 {
+		out, err := Format(o.json, o.tmpl, result)
+		if err != nil {
+			o.Ui.Error(err.Error())
+			return 1
+		}
+		o.Ui.Output(out)
+		return 0
+	}
+
+	o.outputResult(result)
+	return 0
+}
+
+// simulateLookupNode returns the ID of the node matching the prefix.
+func simulateLookupNode(store *state.StateStore, prefix string) (string, error) {
+	if len(prefix) == 1 {
+		return "", fmt.Errorf("Node ID %q must contain at least two characters", prefix)
+	}
+	prefix = sanitizeUUIDPrefix(prefix)
+
+	iter, err := store.NodesByIDPrefix(memdb.NewWatchSet(), prefix)
+	if err != nil {
+		return "", fmt.Errorf("Error looking up node %q: %s", prefix, err)
+	}
+	var ids []string
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		ids = append(ids, raw.(*structs.Node).ID)
+	}
+
+	switch len(ids) {
+	case 0:
+		return "", fmt.Errorf("No node(s) with prefix %q found", prefix)
+	case 1:
+		return ids[0], nil
+	default:
+		return "", fmt.Errorf("Prefix %q matched multiple nodes: %s", prefix, strings.Join(ids, ", "))
+	}
+}
+
+func (o *OperatorSchedulerSimulate) outputResult(result *scheduler.SimulationResult) {
+	length := shortId
+	if o.verbose {
+		length = fullId
+	}
+
+	rows := make([]string, len(result.Evals)+1)
+	rows[0] = "Job ID|Namespace|Type|Triggered By|Placed|Stopped|Preempted|Failed|Blocked"
+	var failed []*scheduler.SimulationEval
+	for i, eval := range result.Evals {
+		rows[i+1] = fmt.Sprintf("%s|%s|%s|%s|%d|%d|%d|%d|%t",
+			eval.JobID,
+			eval.Namespace,
+			eval.JobType,
+			eval.TriggeredBy,
+			sumCounts(eval.Placed),
+			sumCounts(eval.Stopped),
+			eval.Preempted,
+			sumCounts(eval.Failed),
+			eval.Blocked)
+		if len(eval.Failed) > 0 {
+			failed = append(failed, eval)
+		}
+	}
+	o.Ui.Output(o.Colorize().Color("[bold]Evaluations[reset]"))
+	o.Ui.Output(formatList(rows))
+
+	if len(failed) > 0 {
+		o.Ui.Output("")
+		o.Ui.Output(o.Colorize().Color("[bold]Placement Failures[reset]"))
+		for _, eval := range failed {
+			tgs := make([]string, 0, len(eval.Failed))
+			for tg := range eval.Failed {
+				tgs = append(tgs, tg)
+			}
+			sort.Strings(tgs)
+			for _, tg := range tgs {
+				o.Ui.Output(fmt.Sprintf("Job %q task group %q failed to place %d allocation(s):",
+					eval.JobID, tg, eval.Failed[tg]))
+				metric := eval.FailedTGAllocs[tg]
+				o.Ui.Output(formatAllocMetrics(&api.AllocationMetric{
+					NodesEvaluated:     metric.NodesEvaluated,
+					NodesAvailable:     metric.NodesAvailable,
+					ClassFiltered:      metric.ClassFiltered,
+					ConstraintFiltered: metric.ConstraintFiltered,
+					NodesExhausted:     metric.NodesExhausted,
+					ClassExhausted:     metric.ClassExhausted,
+					DimensionExhausted: metric.DimensionExhausted,
+					QuotaExhausted:     metric.QuotaExhausted,
+				}, false, "  "))
+			}
+		}
+	}
+
+	// Drained nodes only count towards the capacity before the simulation
+	var cpuCapBefore, memCapBefore, cpuCapAfter, memCapAfter int64
+	var cpuBefore, memBefore, cpuAfter, memAfter int64
+	rows = make([]string, len(result.Nodes)+1)
+	rows[0] = "Node ID|Node Name|Draining|CPU Before|CPU After|Memory Before|Memory After"
+	for i, node := range result.Nodes {
+		rows[i+1] = fmt.Sprintf("%s|%s|%t|%s|%s|%s|%s",
+			limit(node.NodeID, length),
+			node.NodeName,
+			node.Draining,
+			formatSimulateUsage(node.CPUBefore, node.CPUCapacity, "MHz"),
+			formatSimulateUsage(node.CPUAfter, node.CPUCapacity, "MHz"),
+			formatSimulateUsage(node.MemoryMBBefore, node.MemoryMBCapacity, "MiB"),
+			formatSimulateUsage(node.MemoryMBAfter, node.MemoryMBCapacity, "MiB"))
+
+		cpuBefore += node.CPUBefore
+		memBefore += node.MemoryMBBefore
+		cpuAfter += node.CPUAfter
+		memAfter += node.MemoryMBAfter
+		cpuCapBefore += node.CPUCapacity
+		memCapBefore += node.MemoryMBCapacity
+		if !node.Draining {
+			cpuCapAfter += node.CPUCapacity
+			memCapAfter += node.MemoryMBCapacity
+		}
+	}
+	o.Ui.Output("")
+	o.Ui.Output(o.Colorize().Color("[bold]Node Utilization[reset]"))
+	o.Ui.Output(formatList(rows))
+	o.Ui.Output("")
+	o.Ui.Output(formatKV([]string{
+		fmt.Sprintf("CPU Before|%s", formatSimulateUsage(cpuBefore, cpuCapBefore, "MHz")),
+		fmt.Sprintf("CPU After|%s", formatSimulateUsage(cpuAfter, cpuCapAfter, "MHz")),
+		fmt.Sprintf("Memory Before|%s", formatSimulateUsage(memBefore, memCapBefore, "MiB")),
+		fmt.Sprintf("Memory After|%s", formatSimulateUsage(memAfter, memCapAfter, "MiB")),
+	}))
+}
+
+// formatSimulateUsage formats the usage of a resource against its capacity.
+func formatSimulateUsage(used, capacity int64, unit string) string {
+	if capacity == 0 {
+		return fmt.Sprintf("%d/0 %s", used, unit)
+	}
+	return fmt.Sprintf("%d/%d %s (%.1f%%)", used, capacity, unit, float64(used)/float64(capacity)*100)
+}
+
+// sumCounts returns the sum of the counts of each task group.
+func sumCounts(counts map[string]int) int {
+	total := 0
+	for _, n := range counts {
+		total += n
+	}
+	return total
+}
+
+func (o *OperatorSchedulerSimulate) Synopsis() string {
+	return "Simulate node drains and job registrations against a snapshot"
+}
+
+func (o *OperatorSchedulerSimulate) Help() string {
+	helpText := `
+Usage: nomad operator scheduler simulate [options] <file>
+
+  Simulate restores a snapshot taken by "nomad operator snapshot save" into an
+  in-memory state store, applies hypothetical node drains and job
+  registrations, and runs the schedulers against it. The placements, placement
+  failures and resulting utilization of the nodes are reported. The cluster
+  is never contacted, so the simulation has no effect on it.
+
+  Drained nodes migrate all of their allocations at once, regardless of the
+  max_parallel of the jobs' migrate blocks.
+
+  To find out whether the allocations of two nodes fit elsewhere:
+
+    $ nomad operator scheduler simulate -drain-node=f4a1 -drain-node=9c2e backup.snap
+
+  To find out whether a job would fit:
+
+    $ nomad operator scheduler simulate -job=example.nomad backup.snap
+
+Scheduler Simulate Options:
+
+  -drain-node=<id>
+    Drain the node with the given ID or ID prefix. Can be specified multiple
+    times.
+
+  -job=<path>
+    Register the job in the given job file. Can be specified multiple times.
+
+  -hcl1
+    Parse the job files as HCLv1.
+
+  -var 'key=value'
+    Variable for the job files, as a key=value pair. Can be specified multiple
+    times.
+
+  -var-file=path
+    Path to an HCL2 file containing variables for the job files. Can be
+    specified multiple times.
+
+  -verbose
+    Display full information.
+
+  -json
+    Output the simulation result in its JSON format.
+
+  -t
+    Format and display the simulation result using a Go template.
+`
+
+	return strings.TrimSpace(helpText)
+}
@@ -0,0 +1,68 @@
+package command
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/command/agent"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/scheduler"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOperatorSchedulerSimulate_Run(t *testing.T) {
+	ci.Parallel(t)
+
+	node := mock.Node()
+	snapPath := generateSnapshotFile(t, func(srv *agent.TestAgent, _ *api.Client, _ string) {
+		req := &structs.NodeRegisterRequest{
+			Node:         node,
+			WriteRequest: structs.WriteRequest{Region: "global"},
+		}
+		var resp structs.NodeUpdateResponse
+		require.NoError(t, srv.Agent.RPC("Node.Register", req, &resp))
+	})
+
+	ui := cli.NewMockUi()
+	c := &OperatorSchedulerSimulate{Meta: Meta{Ui: ui}}
+
+	// Register a job which fits on the node.
+	require.EqualValues(t, 0, c.Run([]string{"-job=testdata/example-basic.nomad", snapPath}))
+	out := ui.OutputWriter.String()
+	require.Contains(t, out, "Evaluations")
+	require.Contains(t, out, "job1")
+	require.Contains(t, out, "Node Utilization")
+	require.Contains(t, out, "1000/")
+	require.NotContains(t, out, "Placement Failures")
+	ui.ErrorWriter.Reset()
+	ui.OutputWriter.Reset()
+
+	// Draining the node leaves no room for the job.
+	c = &OperatorSchedulerSimulate{Meta: Meta{Ui: ui}}
+	require.EqualValues(t, 0, c.Run([]string{
+		"-drain-node=" + node.ID[:8], "-job=testdata/example-basic.nomad", "-json", snapPath}))
+	var result scheduler.SimulationResult
+	require.NoError(t, json.Unmarshal(ui.OutputWriter.Bytes(), &result))
+	require.Len(t, result.Evals, 1)
+	require.Equal(t, "job1", result.Evals[0].JobID)
+	require.Equal(t, 1, result.Evals[0].Failed["group1"])
+	require.True(t, result.Evals[0].Blocked)
+	require.Len(t, result.Nodes, 1)
+	require.True(t, result.Nodes[0].Draining)
+	ui.ErrorWriter.Reset()
+	ui.OutputWriter.Reset()
+
+	// Test invalid arguments.
+	c = &OperatorSchedulerSimulate{Meta: Meta{Ui: ui}}
+	require.EqualValues(t, 1, c.Run([]string{snapPath}))
+	require.Contains(t, ui.ErrorWriter.String(), "At least one -drain-node or -job flag must be given")
+	ui.ErrorWriter.Reset()
+
+	c = &OperatorSchedulerSimulate{Meta: Meta{Ui: ui}}
+	require.EqualValues(t, 1, c.Run([]string{"-drain-node=ffffffff", snapPath}))
+	require.Contains(t, ui.ErrorWriter.String(), "No node(s) with prefix")
+}
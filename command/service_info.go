@@ -1,12 +1,16 @@
 package command
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"os"
+	"os/signal"
 	"sort"
 	"strconv"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/hashicorp/nomad/api"
 	"github.com/mitchellh/cli"
@@ -40,6 +44,14 @@ Service Info Options:
   -verbose
     Display full information.
 
+  -healthy
+    Only display service registrations whose checks are all passing.
+
+  -watch
+    Use blocking queries to continuously watch the service registrations,
+    displaying the registrations which are added, updated or removed as they
+    change. Cannot be used with -json, -t, -per-page or -page-token.
+
   -per-page
     How many results to show per page.
 
@@ -68,10 +80,12 @@ func (s *ServiceInfoCommand) AutocompleteFlags() complete.Flags {
 		complete.Flags{
 			"-json":       complete.PredictNothing,
 			"-filter":     complete.PredictAnything,
+			"-healthy":    complete.PredictNothing,
 			"-per-page":   complete.PredictAnything,
 			"-page-token": complete.PredictAnything,
 			"-t":          complete.PredictAnything,
 			"-verbose":    complete.PredictNothing,
+			"-watch":      complete.PredictNothing,
 		})
 }
 
@@ -81,7 +95,8 @@ func (s *ServiceInfoCommand) Name() string { return "service info" }
 // Run satisfies the cli.Command Run function.
 func (s *ServiceInfoCommand) Run(args []string) int {
 	var (
-		json, verbose           bool
+		json, verbose, healthy  bool
+		watch                   bool
 		perPage                 int
 		tmpl, filter, pageToken string
 	)
@@ -90,6 +105,8 @@ func (s *ServiceInfoCommand) Run(args []string) int {
 	flags.Usage = func() { s.Ui.Output(s.Help()) }
 	flags.BoolVar(&json, "json", false, "")
 	flags.BoolVar(&verbose, "verbose", false, "")
+	flags.BoolVar(&healthy, "healthy", false, "")
+	flags.BoolVar(&watch, "watch", false, "")
 	flags.StringVar(&tmpl, "t", "", "")
 	flags.StringVar(&filter, "filter", "", "")
 	flags.IntVar(&perPage, "per-page", 0, "")
@@ -105,6 +122,12 @@ func (s *ServiceInfoCommand) Run(args []string) int {
 		return 1
 	}
 
+	if watch && (json || len(tmpl) > 0 || perPage != 0 || pageToken != "") {
+		s.Ui.Error("The -watch flag cannot be used with -json, -t, -per-page or -page-token")
+		s.Ui.Error(commandErrorText(s))
+		return 1
+	}
+
 	client, err := s.Meta.Client()
 	if err != nil {
 		s.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
@@ -117,6 +140,16 @@ func (s *ServiceInfoCommand) Run(args []string) int {
 		PerPage:   int32(perPage),
 		NextToken: pageToken,
 	}
+	if healthy {
+		opts.Params = map[string]string{"healthy": "true"}
+	}
+
+	// Watch the service until interrupted.
+	if watch {
+		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer cancel()
+		return s.watchServices(ctx, client, args[0], &opts, verbose)
+	}
 
 	serviceInfo, qm, err := client.Services().Get(args[0], &opts)
 	if err != nil {
@@ -139,6 +172,20 @@ func (s *ServiceInfoCommand) Run(args []string) int {
 		return 0
 	}
 
+	s.outputServices(serviceInfo, verbose)
+
+	if qm.NextToken != "" {
+		s.Ui.Output(fmt.Sprintf("\nResults have been paginated. To get the next page run: \n\n%s ",
+			argsWithNewPageToken(os.Args, qm.NextToken)))
+	}
+
+	return 0
+}
+
+// outputServices outputs the service registrations, in either the standard or
+// verbose format.
+func (s *ServiceInfoCommand) outputServices(services []*api.ServiceRegistration, verbose bool) {
+
 	// It is possible for multiple jobs to register a service with the same
 	// name. In order to provide consistency, sort the output by job ID.
 	sortedJobID := []string{}
@@ -146,7 +193,7 @@ func (s *ServiceInfoCommand) Run(args []string) int {
 
 	// Populate the objects, ensuring we do not add duplicate job IDs to the
 	// array which will be sorted.
-	for _, service := range serviceInfo {
+	for _, service := range services {
 		if _, ok := jobIDServices[service.JobID]; ok {
 			jobIDServices[service.JobID] = append(jobIDServices[service.JobID], service)
 		} else {
@@ -163,13 +210,97 @@ func (s *ServiceInfoCommand) Run(args []string) int {
 	} else {
 		s.formatOutput(sortedJobID, jobIDServices)
 	}
+}
 
-	if qm.NextToken != "" {
-		s.Ui.Output(fmt.Sprintf("\nResults have been paginated. To get the next page run: \n\n%s ",
-			argsWithNewPageToken(os.Args, qm.NextToken)))
+// watchServices uses blocking queries to watch the service registrations of
+// the named service until the context is cancelled. The current registrations
+// are output first, followed by the registrations which are added, updated or
+// removed each time the service changes.
+func (s *ServiceInfoCommand) watchServices(
+	ctx context.Context, client *api.Client, serviceName string, opts *api.QueryOptions, verbose bool) int {
+
+	opts = opts.WithContext(ctx)
+
+	var known map[string]*api.ServiceRegistration
+
+	for {
+		serviceInfo, qm, err := client.Services().Get(serviceName, opts)
+		if err != nil {
+			// Being interrupted is the expected way to stop watching.
+			if ctx.Err() != nil {
+				return 0
+			}
+			s.Ui.Error(fmt.Sprintf("Error listing service registrations: %s", err))
+			return 1
+		}
+		opts.WaitIndex = qm.LastIndex
+
+		current := make(map[string]*api.ServiceRegistration, len(serviceInfo))
+		for _, service := range serviceInfo {
+			current[service.ID] = service
+		}
+
+		// The first query outputs the registrations in the same way as when
+		// not watching.
+		if known == nil {
+			if len(serviceInfo) == 0 {
+				s.Ui.Output("No service registrations found")
+			} else {
+				s.outputServices(serviceInfo, verbose)
+			}
+			known = current
+			continue
+		}
+
+		if changes := formatServiceChanges(known, current); changes != "" {
+			s.Ui.Output(fmt.Sprintf("\n==> %s: Service %q registrations changed",
+				formatTime(time.Now()), serviceName))
+			s.Ui.Output(changes)
+		}
+		known = current
+	}
+}
+
+// formatServiceChanges returns a table of the service registrations which have
+// been added, updated or removed between the previous and current sets of
+// registrations, keyed by ID. An empty string is returned if nothing changed.
+func formatServiceChanges(previous, current map[string]*api.ServiceRegistration) string {
+	var changes []string
+
+	for id, service := range current {
+		if old, ok := previous[id]; !ok {
+			changes = append(changes, formatServiceChange("added", service))
+		} else if old.ModifyIndex != service.ModifyIndex {
+			changes = append(changes, formatServiceChange("updated", service))
+		}
+	}
+	for id, service := range previous {
+		if _, ok := current[id]; !ok {
+			changes = append(changes, formatServiceChange("removed", service))
+		}
 	}
 
-	return 0
+	if len(changes) == 0 {
+		return ""
+	}
+
+	// Sort the changes, so the output of each change is deterministic.
+	sort.Strings(changes)
+
+	return formatList(append([]string{"Change|Job ID|Address|Tags|Health|Node ID|Alloc ID"}, changes...))
+}
+
+func formatServiceChange(change string, service *api.ServiceRegistration) string {
+	return fmt.Sprintf(
+		"%s|%s|%s|[%s]|%s|%s|%s",
+		change,
+		service.JobID,
+		formatAddress(service.Address, service.Port),
+		strings.Join(service.Tags, ","),
+		formatServiceHealth(service),
+		limit(service.NodeID, shortId),
+		limit(service.AllocID, shortId),
+	)
 }
 
 // formatOutput produces the non-verbose output of service registration info
@@ -177,16 +308,17 @@ func (s *ServiceInfoCommand) Run(args []string) int {
 func (s *ServiceInfoCommand) formatOutput(jobIDs []string, jobServices map[string][]*api.ServiceRegistration) {
 
 	// Create the output table header.
-	outputTable := []string{"Job ID|Address|Tags|Node ID|Alloc ID"}
+	outputTable := []string{"Job ID|Address|Tags|Health|Node ID|Alloc ID"}
 
 	// Populate the list.
 	for _, jobID := range jobIDs {
 		for _, service := range jobServices[jobID] {
 			outputTable = append(outputTable, fmt.Sprintf(
-				"%s|%s|[%s]|%s|%s",
+				"%s|%s|[%s]|%s|%s|%s",
 				service.JobID,
 				formatAddress(service.Address, service.Port),
 				strings.Join(service.Tags, ","),
+				formatServiceHealth(service),
 				limit(service.NodeID, shortId),
 				limit(service.AllocID, shortId),
 			))
@@ -195,6 +327,41 @@ func (s *ServiceInfoCommand) formatOutput(jobIDs []string, jobServices map[strin
 	s.Ui.Output(formatList(outputTable))
 }
 
+// formatServiceHealth returns a human readable representation of whether all
+// the checks of a service registration are passing.
+func formatServiceHealth(service *api.ServiceRegistration) string {
+	if service.Healthy() {
+		return "healthy"
+	}
+	return "unhealthy"
+}
+
+// formatServiceChecks returns the name and status of each check of a service
+// registration, in the order they were reported.
+func formatServiceChecks(service *api.ServiceRegistration) string {
+	checks := make([]string, len(service.Checks))
+	for i, check := range service.Checks {
+		checks[i] = fmt.Sprintf("%s=%s", check.Name, check.Status)
+	}
+	return strings.Join(checks, ",")
+}
+
+// formatServiceMeta returns the metadata of a service registration as key/value
+// pairs, sorted by key.
+func formatServiceMeta(meta map[string]string) string {
+	keys := make([]string, 0, len(meta))
+	for k := range meta {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		pairs[i] = fmt.Sprintf("%s=%s", k, meta[k])
+	}
+	return strings.Join(pairs, ",")
+}
+
 func formatAddress(address string, port int) string {
 	if port == 0 {
 		return address
@@ -216,7 +383,11 @@ func (s *ServiceInfoCommand) formatVerboseOutput(jobIDs []string, jobServices ma
 				fmt.Sprintf("Node ID|%s", service.NodeID),
 				fmt.Sprintf("Datacenter|%s", service.Datacenter),
 				fmt.Sprintf("Address|%v", fmt.Sprintf("%s:%v", service.Address, service.Port)),
-				fmt.Sprintf("Tags|[%s]\n", strings.Join(service.Tags, ",")),
+				fmt.Sprintf("Tags|[%s]", strings.Join(service.Tags, ",")),
+				fmt.Sprintf("Meta|[%s]", formatServiceMeta(service.Meta)),
+				fmt.Sprintf("Weight|%d", service.Weight),
+				fmt.Sprintf("Health|%s", formatServiceHealth(service)),
+				fmt.Sprintf("Checks|[%s]\n", formatServiceChecks(service)),
 			}
 			s.Ui.Output(formatKV(out))
 			s.Ui.Output("")
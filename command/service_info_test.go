@@ -1,7 +1,9 @@
 package command
 
 import (
+	"context"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -54,7 +56,8 @@ func TestServiceInfoCommand_Run(t *testing.T) {
 	// Create a test job with a Nomad service.
 	testJob := testJob("service-discovery-nomad-info")
 	testJob.TaskGroups[0].Services = []*api.Service{
-		{Name: "service-discovery-nomad-info", Provider: "nomad", PortLabel: "9999", Tags: []string{"foo", "bar"}}}
+		{Name: "service-discovery-nomad-info", Provider: "nomad", PortLabel: "9999", Tags: []string{"foo", "bar"},
+			Meta: map[string]string{"version": "1", "env": "test"}, Weight: 5}}
 
 	// Register that job.
 	regResp, _, err := client.Jobs().Register(testJob, nil)
@@ -116,11 +119,120 @@ func TestServiceInfoCommand_Run(t *testing.T) {
 	require.Contains(t, s, "Datacenter   = dc1")
 	require.Contains(t, s, "Address      = :9999")
 	require.Contains(t, s, "Tags         = [foo,bar]")
+	require.Contains(t, s, "Meta         = [env=test,version=1]")
+	require.Contains(t, s, "Weight       = 5")
 
 	ui.OutputWriter.Reset()
 	ui.ErrorWriter.Reset()
 }
 
+func TestServiceInfoCommand_Watch(t *testing.T) {
+	ci.Parallel(t)
+
+	srv, client, url := testServer(t, true, nil)
+	defer srv.Shutdown()
+
+	// Wait until our test node is ready.
+	testutil.WaitForResult(func() (bool, error) {
+		nodes, _, err := client.Nodes().List(nil)
+		if err != nil {
+			return false, err
+		}
+		if len(nodes) == 0 {
+			return false, fmt.Errorf("missing node")
+		}
+		if _, ok := nodes[0].Drivers["mock_driver"]; !ok {
+			return false, fmt.Errorf("mock_driver not ready")
+		}
+		return true, nil
+	}, func(err error) {
+		require.NoError(t, err)
+	})
+
+	ui := cli.NewMockUi()
+	cmd := &ServiceInfoCommand{
+		Meta: Meta{
+			Ui:          ui,
+			flagAddress: url,
+		},
+	}
+
+	// The watch flag cannot be used alongside formatted output.
+	require.Equal(t, 1, cmd.Run([]string{"-address=" + url, "-watch", "-json", "service-discovery-nomad-watch"}))
+	require.Contains(t, ui.ErrorWriter.String(), "The -watch flag cannot be used with")
+	ui.ErrorWriter.Reset()
+
+	// Start watching the service before anything is registered.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	codeCh := make(chan int, 1)
+	go func() {
+		codeCh <- cmd.watchServices(ctx, client, "service-discovery-nomad-watch", &api.QueryOptions{}, false)
+	}()
+
+	require.Eventually(t, func() bool {
+		return strings.Contains(ui.OutputWriter.String(), "No service registrations found")
+	}, 5*time.Second, 100*time.Millisecond)
+
+	// Register a job which contains the watched service.
+	testJob := testJob("service-discovery-nomad-watch")
+	testJob.TaskGroups[0].Services = []*api.Service{
+		{Name: "service-discovery-nomad-watch", Provider: "nomad", PortLabel: "9999"}}
+
+	regResp, _, err := client.Jobs().Register(testJob, nil)
+	require.NoError(t, err)
+	require.Equal(t, 0, waitForSuccess(ui, client, fullId, t, regResp.EvalID))
+
+	// The registration of the service should be output as an addition.
+	require.Eventually(t, func() bool {
+		s := ui.OutputWriter.String()
+		return strings.Contains(s, `Service "service-discovery-nomad-watch" registrations changed`) &&
+			strings.Contains(s, "added   service-discovery-nomad-watch")
+	}, 10*time.Second, 100*time.Millisecond)
+
+	// Stop the job, which should remove the registration.
+	_, _, err = client.Jobs().Deregister(*testJob.ID, true, nil)
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		return strings.Contains(ui.OutputWriter.String(), "removed  service-discovery-nomad-watch")
+	}, 10*time.Second, 100*time.Millisecond)
+
+	// Cancelling the context stops the watch without error.
+	cancel()
+	select {
+	case code := <-codeCh:
+		require.Equal(t, 0, code)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for watch to stop")
+	}
+}
+
+func Test_formatServiceChanges(t *testing.T) {
+	ci.Parallel(t)
+
+	unchanged := &api.ServiceRegistration{ID: "a", JobID: "job", Address: "10.0.0.1", Port: 1, ModifyIndex: 10}
+	updated := &api.ServiceRegistration{ID: "b", JobID: "job", Address: "10.0.0.2", Port: 2, ModifyIndex: 10}
+	removed := &api.ServiceRegistration{ID: "c", JobID: "job", Address: "10.0.0.3", Port: 3, ModifyIndex: 10}
+	added := &api.ServiceRegistration{ID: "d", JobID: "job", Address: "10.0.0.4", Port: 4, ModifyIndex: 20}
+
+	updatedNew := *updated
+	updatedNew.ModifyIndex = 20
+
+	previous := map[string]*api.ServiceRegistration{"a": unchanged, "b": updated, "c": removed}
+	current := map[string]*api.ServiceRegistration{"a": unchanged, "b": &updatedNew, "d": added}
+
+	out := formatServiceChanges(previous, current)
+	require.Contains(t, out, "added    job     10.0.0.4:4")
+	require.Contains(t, out, "removed  job     10.0.0.3:3")
+	require.Contains(t, out, "updated  job     10.0.0.2:2")
+	require.NotContains(t, out, "10.0.0.1:1")
+
+	// No changes results in no output.
+	require.Empty(t, formatServiceChanges(current, current))
+}
+
 func Test_argsWithNewPageToken(t *testing.T) {
 	ci.Parallel(t)
 
@@ -1,8 +1,14 @@
 package command
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
 	"strings"
 
+	"github.com/hashicorp/hcl"
+	"github.com/hashicorp/nomad/api"
 	"github.com/hashicorp/nomad/api/contexts"
 	"github.com/mitchellh/cli"
 	"github.com/posener/complete"
@@ -29,18 +35,34 @@ Usage: nomad var <subcommand> [options] [args]
 
       $ nomad var init
 
-  Upsert a variable:
+  Create or update a variable from key/value pairs:
 
-      $ nomad var put <path>
+      $ nomad var put <path> <key>=<value> [<key>=<value>]...
+
+  Create or update a variable from a specification file:
+
+      $ nomad var put @spec.nsv.hcl
+
+  Update a variable only if it has not changed since it was read:
+
+      $ nomad var put -check-index=<index> <path> <key>=<value>
 
   Examine a variable:
 
       $ nomad var get <path>
 
+  Read a single item of a variable:
+
+      $ nomad var get -item=<key> <path>
+
   List existing variables:
 
       $ nomad var list <prefix>
 
+  Purge a variable:
+
+      $ nomad var purge <path>
+
   Please see the individual subcommand help for detailed usage information.
 `
 
@@ -72,3 +94,77 @@ func VariablePathPredictor(factory ApiClientFactory) complete.Predictor {
 		return resp.Matches[contexts.Variables]
 	})
 }
+
+// varSpec is the HCL representation of a variable specification, as created
+// by the var init command.
+type varSpec struct {
+	Path      string            `hcl:"path"`
+	Namespace string            `hcl:"namespace"`
+	Items     map[string]string `hcl:"items"`
+}
+
+// parseVariableSpec parses a variable specification in the given format,
+// which is either "hcl" or "json". If format is empty, JSON is assumed when
+// the input starts with a curly brace and HCL otherwise.
+func parseVariableSpec(input []byte, format string) (*api.Variable, error) {
+	trimmed := strings.TrimSpace(string(input))
+	if trimmed == "" {
+		return nil, errors.New("variable specification is empty")
+	}
+
+	if format == "" {
+		format = "hcl"
+		if strings.HasPrefix(trimmed, "{") {
+			format = "json"
+		}
+	}
+
+	var out api.Variable
+	switch format {
+	case "json":
+		if err := json.Unmarshal(input, &out); err != nil {
+			return nil, fmt.Errorf("failed to parse JSON: %v", err)
+		}
+	case "hcl":
+		var spec varSpec
+		if err := hcl.Decode(&spec, trimmed); err != nil {
+			return nil, fmt.Errorf("failed to parse HCL: %v", err)
+		}
+		out.Path = spec.Path
+		out.Namespace = spec.Namespace
+		out.Items = spec.Items
+	default:
+		return nil, fmt.Errorf("unsupported format %q, must be one of \"hcl\" or \"json\"", format)
+	}
+
+	if out.Items == nil {
+		out.Items = make(api.VariableItems)
+	}
+	return &out, nil
+}
+
+// formatVariable formats the metadata and items of a variable for output.
+func formatVariable(sv *api.Variable) string {
+	out := []string{
+		fmt.Sprintf("Namespace|%s", sv.Namespace),
+		fmt.Sprintf("Path|%s", sv.Path),
+		fmt.Sprintf("Create Time|%v", formatUnixNanoTime(sv.CreateTime)),
+	}
+	if sv.CreateTime != sv.ModifyTime {
+		out = append(out, fmt.Sprintf("Modify Time|%v", formatUnixNanoTime(sv.ModifyTime)))
+	}
+	out = append(out, fmt.Sprintf("Check Index|%v", sv.ModifyIndex))
+
+	keys := make([]string, 0, len(sv.Items))
+	for k := range sv.Items {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	items := make([]string, len(keys))
+	for i, k := range keys {
+		items[i] = fmt.Sprintf("%s|%s", k, sv.Items[k])
+	}
+
+	return fmt.Sprintf("%s\n\n%s\n%s", formatKV(out), "[bold]Items[reset]", formatKV(items))
+}
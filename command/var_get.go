@@ -0,0 +1,139 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/posener/complete"
+)
+
+type VarGetCommand struct {
+	Meta
+}
+
+func (c *VarGetCommand) Help() string {
+	helpText := `
+Usage: nomad var get [options] <path>
+
+  Get is used to read the contents of an existing variable.
+
+  If ACLs are enabled, this command requires a token with the ` + "`read`" + `
+  capability for the target variable's namespace and path.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault) + `
+
+Get Options:
+
+  -item <key>
+    Output only the value of the given item, which makes it suitable for
+    piping to other processes. This option overrides the ` + "`-json`" + ` and
+    ` + "`-t`" + ` options.
+
+  -version <index>
+    Read the version of the variable with the given modify index, rather
+    than the current version. Prior versions of a variable are only
+    available when the servers are configured to retain them.
+
+  -json
+    Output the variable in JSON format.
+
+  -t
+    Format and display the variable using a Go template.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *VarGetCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-item":    complete.PredictAnything,
+			"-version": complete.PredictAnything,
+			"-json":    complete.PredictNothing,
+			"-t":       complete.PredictAnything,
+		},
+	)
+}
+
+func (c *VarGetCommand) AutocompleteArgs() complete.Predictor {
+	return VariablePathPredictor(c.Meta.Client)
+}
+
+func (c *VarGetCommand) Synopsis() string {
+	return "Read a variable"
+}
+
+func (c *VarGetCommand) Name() string { return "var get" }
+
+func (c *VarGetCommand) Run(args []string) int {
+	var json bool
+	var tmpl, item string
+	var version uint64
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&json, "json", false, "")
+	flags.StringVar(&tmpl, "t", "", "")
+	flags.StringVar(&item, "item", "", "")
+	flags.Uint64Var(&version, "version", 0, "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got exactly one argument
+	args = flags.Args()
+	if l := len(args); l != 1 {
+		c.Ui.Error("This command takes one argument: <path>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+	path := args[0]
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	var sv *api.Variable
+	if version != 0 {
+		sv, _, err = client.Variables().ReadVersion(path, version, nil)
+	} else {
+		sv, _, err = client.Variables().Read(path, nil)
+	}
+	if err != nil {
+		if err.Error() == api.ErrVariableNotFound {
+			c.Ui.Error(fmt.Sprintf("Variable %q not found", path))
+		} else {
+			c.Ui.Error(fmt.Sprintf("Error retrieving variable: %s", err))
+		}
+		return 1
+	}
+
+	if item != "" {
+		value, ok := sv.Items[item]
+		if !ok {
+			c.Ui.Error(fmt.Sprintf("Variable %q does not contain item %q", path, item))
+			return 1
+		}
+		c.Ui.Output(value)
+		return 0
+	}
+
+	if json || len(tmpl) > 0 {
+		out, err := Format(json, tmpl, sv)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+		c.Ui.Output(out)
+		return 0
+	}
+
+	c.Ui.Output(c.Colorize().Color(formatVariable(sv)))
+	return 0
+}
@@ -0,0 +1,95 @@
+package command
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/mitchellh/cli"
+	"github.com/shoenig/test/must"
+)
+
+func TestVarGetCommand_Implements(t *testing.T) {
+	ci.Parallel(t)
+	var _ cli.Command = &VarGetCommand{}
+}
+
+func TestVarGetCommand_Fails(t *testing.T) {
+	ci.Parallel(t)
+
+	ui := cli.NewMockUi()
+	cmd := &VarGetCommand{Meta: Meta{Ui: ui}}
+	code := cmd.Run([]string{"some", "bad", "args"})
+	must.One(t, code)
+	must.StrContains(t, ui.ErrorWriter.String(), "This command takes one argument: <path>")
+	must.StrContains(t, ui.ErrorWriter.String(), commandErrorText(cmd))
+}
+
+func TestVarGetCommand_Online(t *testing.T) {
+	ci.Parallel(t)
+
+	srv, client, url := testServer(t, true, nil)
+	defer srv.Shutdown()
+
+	sv, _, err := client.Variables().Create(&api.Variable{
+		Path:  "get/test",
+		Items: api.VariableItems{"k1": "v1", "k2": "v2"},
+	}, nil)
+	must.NoError(t, err)
+
+	t.Run("default", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		cmd := &VarGetCommand{Meta: Meta{Ui: ui}}
+		code := cmd.Run([]string{"-address=" + url, "get/test"})
+		must.Zero(t, code, must.Sprint(ui.ErrorWriter.String()))
+
+		out := ui.OutputWriter.String()
+		must.StrContains(t, out, "get/test")
+		must.StrContains(t, out, "k1 = v1")
+		must.StrContains(t, out, "k2 = v2")
+	})
+
+	t.Run("item", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		cmd := &VarGetCommand{Meta: Meta{Ui: ui}}
+		code := cmd.Run([]string{"-address=" + url, "-item", "k2", "get/test"})
+		must.Zero(t, code, must.Sprint(ui.ErrorWriter.String()))
+		must.Eq(t, "v2", strings.TrimSpace(ui.OutputWriter.String()))
+
+		ui = cli.NewMockUi()
+		cmd = &VarGetCommand{Meta: Meta{Ui: ui}}
+		code = cmd.Run([]string{"-address=" + url, "-item", "nope", "get/test"})
+		must.One(t, code)
+		must.StrContains(t, ui.ErrorWriter.String(), `does not contain item "nope"`)
+	})
+
+	t.Run("json", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		cmd := &VarGetCommand{Meta: Meta{Ui: ui}}
+		code := cmd.Run([]string{"-address=" + url, "-json", "get/test"})
+		must.Zero(t, code, must.Sprint(ui.ErrorWriter.String()))
+
+		var out api.Variable
+		must.NoError(t, json.Unmarshal(ui.OutputWriter.Bytes(), &out))
+		must.Eq(t, sv.ModifyIndex, out.ModifyIndex)
+		must.Eq(t, sv.Items, out.Items)
+	})
+
+	t.Run("template", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		cmd := &VarGetCommand{Meta: Meta{Ui: ui}}
+		code := cmd.Run([]string{"-address=" + url, "-t", "{{.Items.k1}}", "get/test"})
+		must.Zero(t, code, must.Sprint(ui.ErrorWriter.String()))
+		must.Eq(t, "v1", strings.TrimSpace(ui.OutputWriter.String()))
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		cmd := &VarGetCommand{Meta: Meta{Ui: ui}}
+		code := cmd.Run([]string{"-address=" + url, "get/nope"})
+		must.One(t, code)
+		must.StrContains(t, ui.ErrorWriter.String(), `Variable "get/nope" not found`)
+	})
+}
@@ -0,0 +1,113 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/posener/complete"
+)
+
+type VarPurgeCommand struct {
+	Meta
+}
+
+func (c *VarPurgeCommand) Help() string {
+	helpText := `
+Usage: nomad var purge [options] <path>
+
+  Purge is used to permanently delete an existing variable.
+
+  If ACLs are enabled, this command requires a token with the ` + "`destroy`" + `
+  capability for the target variable's namespace and path.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault) + `
+
+Purge Options:
+
+  -check-index
+    If set, the variable is only purged if the server side version's modify
+    index matches the provided value.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *VarPurgeCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-check-index": complete.PredictAnything,
+		},
+	)
+}
+
+func (c *VarPurgeCommand) AutocompleteArgs() complete.Predictor {
+	return VariablePathPredictor(c.Meta.Client)
+}
+
+func (c *VarPurgeCommand) Synopsis() string {
+	return "Purge a variable"
+}
+
+func (c *VarPurgeCommand) Name() string { return "var purge" }
+
+func (c *VarPurgeCommand) Run(args []string) int {
+	var checkIndexStr string
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&checkIndexStr, "check-index", "", "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got exactly one argument
+	args = flags.Args()
+	if l := len(args); l != 1 {
+		c.Ui.Error("This command takes one argument: <path>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+	path := args[0]
+
+	var checkIndex uint64
+	var checked bool
+	if checkIndexStr != "" {
+		var err error
+		checkIndex, err = strconv.ParseUint(checkIndexStr, 10, 64)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Invalid -check-index value %q: must be a non-negative integer", checkIndexStr))
+			return 1
+		}
+		checked = true
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	if checked {
+		_, err = client.Variables().CheckedDelete(path, checkIndex, nil)
+	} else {
+		_, err = client.Variables().Delete(path, nil)
+	}
+	if err != nil {
+		var cErr api.ErrCASConflict
+		if errors.As(err, &cErr) {
+			c.Ui.Error(fmt.Sprintf("Check-index conflict: variable %q was modified at index %d", path, cErr.Conflict.ModifyIndex))
+			return 1
+		}
+		c.Ui.Error(fmt.Sprintf("Error purging variable: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(fmt.Sprintf("Successfully purged variable %q!", path))
+	return 0
+}
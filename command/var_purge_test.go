@@ -0,0 +1,63 @@
+package command
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/mitchellh/cli"
+	"github.com/shoenig/test/must"
+)
+
+func TestVarPurgeCommand_Implements(t *testing.T) {
+	ci.Parallel(t)
+	var _ cli.Command = &VarPurgeCommand{}
+}
+
+func TestVarPurgeCommand_Fails(t *testing.T) {
+	ci.Parallel(t)
+
+	ui := cli.NewMockUi()
+	cmd := &VarPurgeCommand{Meta: Meta{Ui: ui}}
+	code := cmd.Run([]string{"some", "bad", "args"})
+	must.One(t, code)
+	must.StrContains(t, ui.ErrorWriter.String(), "This command takes one argument: <path>")
+
+	ui = cli.NewMockUi()
+	cmd = &VarPurgeCommand{Meta: Meta{Ui: ui}}
+	code = cmd.Run([]string{"-check-index", "-1", "a/b"})
+	must.One(t, code)
+	must.StrContains(t, ui.ErrorWriter.String(), `Invalid -check-index value "-1"`)
+}
+
+func TestVarPurgeCommand_Online(t *testing.T) {
+	ci.Parallel(t)
+
+	srv, client, url := testServer(t, true, nil)
+	defer srv.Shutdown()
+
+	sv, _, err := client.Variables().Create(&api.Variable{
+		Path:  "purge/test",
+		Items: api.VariableItems{"k1": "v1"},
+	}, nil)
+	must.NoError(t, err)
+
+	// purging with a stale check index fails
+	ui := cli.NewMockUi()
+	cmd := &VarPurgeCommand{Meta: Meta{Ui: ui}}
+	code := cmd.Run([]string{"-address=" + url, "-check-index", fmt.Sprint(sv.ModifyIndex - 1), "purge/test"})
+	must.One(t, code)
+	must.StrContains(t, ui.ErrorWriter.String(), "Check-index conflict")
+
+	// purging with the current check index succeeds
+	ui = cli.NewMockUi()
+	cmd = &VarPurgeCommand{Meta: Meta{Ui: ui}}
+	code = cmd.Run([]string{"-address=" + url, "-check-index", fmt.Sprint(sv.ModifyIndex), "purge/test"})
+	must.Zero(t, code, must.Sprint(ui.ErrorWriter.String()))
+	must.StrContains(t, ui.OutputWriter.String(), `Successfully purged variable "purge/test"!`)
+
+	sv, _, err = client.Variables().Peek("purge/test", nil)
+	must.NoError(t, err)
+	must.Nil(t, sv)
+}
@@ -0,0 +1,246 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/posener/complete"
+)
+
+type VarPutCommand struct {
+	Meta
+
+	// testStdin can be set in tests to override os.Stdin
+	testStdin io.Reader
+}
+
+func (c *VarPutCommand) Help() string {
+	helpText := `
+Usage: nomad var put [options] <path> [<key>=<value>]...
+       nomad var put [options] <path> <@file | -> [<key>=<value>]...
+       nomad var put [options] <@file | -> [<key>=<value>]...
+
+  Put is used to create or update a variable. The items of the variable can
+  be given as key/value pairs on the command line, read from a variable
+  specification file prefixed with "@", or read from stdin by passing "-".
+  Specification files use the HCL or JSON format created by "nomad var init".
+  Key/value pairs given on the command line are merged on top of the items of
+  the specification. If no path is given, the path of the specification is
+  used.
+
+  Put replaces all of the items of an existing variable. The -check-index
+  option can be used to ensure the variable has not been modified since it
+  was last read.
+
+  If ACLs are enabled, this command requires a token with the ` + "`write`" + `
+  capability for the target variable's namespace and path.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault) + `
+
+Put Options:
+
+  -check-index
+    If set, the variable is only written if the server side version's modify
+    index matches the provided value. When the variable does not exist, a
+    check index of 0 ensures the variable is only created.
+
+  -in (hcl | json)
+    The format of the variable specification. When not set, JSON is assumed
+    when the specification starts with a curly brace and HCL otherwise.
+
+  -json
+    Output the written variable in JSON format.
+
+  -t
+    Format and display the written variable using a Go template.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *VarPutCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-check-index": complete.PredictAnything,
+			"-in":          complete.PredictSet("hcl", "json"),
+			"-json":        complete.PredictNothing,
+			"-t":           complete.PredictAnything,
+		},
+	)
+}
+
+func (c *VarPutCommand) AutocompleteArgs() complete.Predictor {
+	return VariablePathPredictor(c.Meta.Client)
+}
+
+func (c *VarPutCommand) Synopsis() string {
+	return "Create or update a variable"
+}
+
+func (c *VarPutCommand) Name() string { return "var put" }
+
+func (c *VarPutCommand) Run(args []string) int {
+	var json bool
+	var tmpl, inFormat, checkIndexStr string
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&json, "json", false, "")
+	flags.StringVar(&tmpl, "t", "", "")
+	flags.StringVar(&inFormat, "in", "", "")
+	flags.StringVar(&checkIndexStr, "check-index", "", "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got at least one argument
+	args = flags.Args()
+	if len(args) == 0 {
+		c.Ui.Error("This command takes at least one argument: <path> or <@file | ->")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	if inFormat != "" && inFormat != "hcl" && inFormat != "json" {
+		c.Ui.Error(fmt.Sprintf("Invalid -in value %q, must be one of \"hcl\" or \"json\"", inFormat))
+		return 1
+	}
+
+	var checkIndex uint64
+	var checked bool
+	if checkIndexStr != "" {
+		var err error
+		checkIndex, err = strconv.ParseUint(checkIndexStr, 10, 64)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Invalid -check-index value %q: must be a non-negative integer", checkIndexStr))
+			return 1
+		}
+		checked = true
+	}
+
+	// The path is optional when a specification is given first
+	var path string
+	if !isVarSpecRef(args[0]) {
+		path = args[0]
+		args = args[1:]
+	}
+
+	sv := api.NewVariable(path)
+	if len(args) > 0 && isVarSpecRef(args[0]) {
+		spec, err := c.readSpec(args[0], inFormat)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+		if path == "" {
+			path = spec.Path
+		}
+		sv = spec
+		sv.Path = path
+		args = args[1:]
+	}
+
+	if path == "" {
+		c.Ui.Error("A variable path must be given as an argument or in the specification")
+		return 1
+	}
+
+	for _, arg := range args {
+		k, v, found := strings.Cut(arg, "=")
+		if !found || k == "" {
+			c.Ui.Error(fmt.Sprintf("Invalid item %q, must be in the form <key>=<value>", arg))
+			return 1
+		}
+		sv.Items[k] = v
+	}
+
+	if len(sv.Items) == 0 {
+		c.Ui.Error("A variable must contain at least one item")
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	// A namespace in the specification takes precedence over the flag
+	var wo *api.WriteOptions
+	if sv.Namespace != "" {
+		wo = &api.WriteOptions{Namespace: sv.Namespace}
+	}
+
+	var out *api.Variable
+	if checked {
+		sv.ModifyIndex = checkIndex
+		out, _, err = client.Variables().CheckedUpdate(sv, wo)
+	} else {
+		out, _, err = client.Variables().Update(sv, wo)
+	}
+	if err != nil {
+		var cErr api.ErrCASConflict
+		if errors.As(err, &cErr) {
+			c.Ui.Error(fmt.Sprintf("Check-index conflict: variable %q was modified at index %d", path, cErr.Conflict.ModifyIndex))
+			return 1
+		}
+		c.Ui.Error(fmt.Sprintf("Error writing variable: %s", err))
+		return 1
+	}
+
+	if json || len(tmpl) > 0 {
+		formatted, err := Format(json, tmpl, out)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+		c.Ui.Output(formatted)
+		return 0
+	}
+
+	c.Ui.Output(fmt.Sprintf("Successfully wrote variable %q with check index %d", out.Path, out.ModifyIndex))
+	return 0
+}
+
+// readSpec reads and parses a variable specification from stdin when ref is
+// "-", or otherwise from the file named by ref after its "@" prefix.
+func (c *VarPutCommand) readSpec(ref, format string) (*api.Variable, error) {
+	var raw []byte
+	var err error
+	if ref == "-" {
+		var stdin io.Reader = os.Stdin
+		if c.testStdin != nil {
+			stdin = c.testStdin
+		}
+		raw, err = ioutil.ReadAll(stdin)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read stdin: %v", err)
+		}
+	} else {
+		raw, err = ioutil.ReadFile(strings.TrimPrefix(ref, "@"))
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read file: %v", err)
+		}
+	}
+
+	sv, err := parseVariableSpec(raw, format)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing variable specification: %v", err)
+	}
+	return sv, nil
+}
+
+// isVarSpecRef returns whether the argument refers to a variable
+// specification, either from stdin or from a file.
+func isVarSpecRef(arg string) bool {
+	return arg == "-" || strings.HasPrefix(arg, "@")
+}
@@ -0,0 +1,147 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/mitchellh/cli"
+	"github.com/shoenig/test/must"
+)
+
+func TestVarPutCommand_Implements(t *testing.T) {
+	ci.Parallel(t)
+	var _ cli.Command = &VarPutCommand{}
+}
+
+func TestVarPutCommand_Fails(t *testing.T) {
+	ci.Parallel(t)
+
+	cases := []struct {
+		name   string
+		args   []string
+		expErr string
+	}{
+		{
+			name:   "no args",
+			args:   []string{},
+			expErr: "This command takes at least one argument",
+		},
+		{
+			name:   "bad check index",
+			args:   []string{"-check-index", "nope", "a/b", "k=v"},
+			expErr: `Invalid -check-index value "nope"`,
+		},
+		{
+			name:   "bad in format",
+			args:   []string{"-in", "yaml", "a/b", "k=v"},
+			expErr: `Invalid -in value "yaml"`,
+		},
+		{
+			name:   "bad item",
+			args:   []string{"a/b", "nope"},
+			expErr: `Invalid item "nope"`,
+		},
+		{
+			name:   "no items",
+			args:   []string{"a/b"},
+			expErr: "A variable must contain at least one item",
+		},
+		{
+			name:   "missing file",
+			args:   []string{"a/b", "@does-not-exist.hcl"},
+			expErr: "Failed to read file",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ui := cli.NewMockUi()
+			cmd := &VarPutCommand{Meta: Meta{Ui: ui}}
+			code := cmd.Run(tc.args)
+			must.One(t, code)
+			must.StrContains(t, ui.ErrorWriter.String(), tc.expErr)
+		})
+	}
+}
+
+func TestVarPutCommand_Online(t *testing.T) {
+	ci.Parallel(t)
+
+	srv, client, url := testServer(t, true, nil)
+	defer srv.Shutdown()
+
+	t.Run("items", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		cmd := &VarPutCommand{Meta: Meta{Ui: ui}}
+		code := cmd.Run([]string{"-address=" + url, "put/items", "k1=v1", "k2=v2"})
+		must.Zero(t, code, must.Sprint(ui.ErrorWriter.String()))
+		must.StrContains(t, ui.OutputWriter.String(), `Successfully wrote variable "put/items"`)
+
+		sv, _, err := client.Variables().Read("put/items", nil)
+		must.NoError(t, err)
+		must.Eq(t, api.VariableItems{"k1": "v1", "k2": "v2"}, sv.Items)
+	})
+
+	t.Run("file", func(t *testing.T) {
+		specFile := filepath.Join(t.TempDir(), "spec.nsv.hcl")
+		spec := `
+path = "put/file"
+items {
+  k1 = "v1"
+}
+`
+		must.NoError(t, os.WriteFile(specFile, []byte(spec), 0o600))
+
+		ui := cli.NewMockUi()
+		cmd := &VarPutCommand{Meta: Meta{Ui: ui}}
+		code := cmd.Run([]string{"-address=" + url, "@" + specFile, "k2=v2"})
+		must.Zero(t, code, must.Sprint(ui.ErrorWriter.String()))
+
+		sv, _, err := client.Variables().Read("put/file", nil)
+		must.NoError(t, err)
+		must.Eq(t, api.VariableItems{"k1": "v1", "k2": "v2"}, sv.Items)
+	})
+
+	t.Run("stdin json", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		cmd := &VarPutCommand{
+			Meta:      Meta{Ui: ui},
+			testStdin: strings.NewReader(`{"Items": {"k1": "v1"}}`),
+		}
+		code := cmd.Run([]string{"-address=" + url, "-json", "put/stdin", "-"})
+		must.Zero(t, code, must.Sprint(ui.ErrorWriter.String()))
+
+		var out api.Variable
+		must.NoError(t, json.Unmarshal(ui.OutputWriter.Bytes(), &out))
+		must.Eq(t, "put/stdin", out.Path)
+		must.Eq(t, api.VariableItems{"k1": "v1"}, out.Items)
+	})
+
+	t.Run("check index", func(t *testing.T) {
+		sv, _, err := client.Variables().Create(&api.Variable{
+			Path:  "put/cas",
+			Items: api.VariableItems{"k1": "v1"},
+		}, nil)
+		must.NoError(t, err)
+
+		// creating an existing variable fails
+		ui := cli.NewMockUi()
+		cmd := &VarPutCommand{Meta: Meta{Ui: ui}}
+		code := cmd.Run([]string{"-address=" + url, "-check-index=0", "put/cas", "k1=v2"})
+		must.One(t, code)
+		must.StrContains(t, ui.ErrorWriter.String(), "Check-index conflict")
+
+		// updating with the current index succeeds
+		ui = cli.NewMockUi()
+		cmd = &VarPutCommand{Meta: Meta{Ui: ui}}
+		code = cmd.Run([]string{"-address=" + url, "-check-index", fmt.Sprint(sv.ModifyIndex), "-t", "{{.Items.k1}}", "put/cas", "k1=v2"})
+		must.Zero(t, code, must.Sprint(ui.ErrorWriter.String()))
+		must.Eq(t, "v2", strings.TrimSpace(ui.OutputWriter.String()))
+	})
+}
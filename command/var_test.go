@@ -0,0 +1,77 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/shoenig/test/must"
+)
+
+func TestParseVariableSpec(t *testing.T) {
+	ci.Parallel(t)
+
+	hclSpec := `
+path      = "a/b/c"
+namespace = "dev"
+
+items {
+  key1 = "value 1"
+  key2 = "value 2"
+}
+`
+	jsonSpec := `{
+  "Path": "a/b/c",
+  "Namespace": "dev",
+  "Items": {
+    "key1": "value 1",
+    "key2": "value 2"
+  }
+}`
+	expect := &api.Variable{
+		Path:      "a/b/c",
+		Namespace: "dev",
+		Items: api.VariableItems{
+			"key1": "value 1",
+			"key2": "value 2",
+		},
+	}
+
+	cases := []struct {
+		name   string
+		input  string
+		format string
+		expErr string
+	}{
+		{name: "hcl", input: hclSpec, format: "hcl"},
+		{name: "hcl detected", input: hclSpec},
+		{name: "json", input: jsonSpec, format: "json"},
+		{name: "json detected", input: jsonSpec},
+		{name: "empty", input: " \n", expErr: "variable specification is empty"},
+		{name: "wrong format", input: hclSpec, format: "json", expErr: "failed to parse JSON"},
+		{name: "unknown format", input: hclSpec, format: "yaml", expErr: `unsupported format "yaml"`},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			sv, err := parseVariableSpec([]byte(tc.input), tc.format)
+			if tc.expErr != "" {
+				must.Error(t, err)
+				must.StrContains(t, err.Error(), tc.expErr)
+				return
+			}
+			must.NoError(t, err)
+			must.Eq(t, expect, sv)
+		})
+	}
+}
+
+func TestVarCommand_Help(t *testing.T) {
+	ci.Parallel(t)
+
+	help := (&VarCommand{}).Help()
+	for _, sub := range []string{"init", "put", "get", "list", "purge"} {
+		must.StrContains(t, help, "nomad var "+sub)
+	}
+	must.StrContains(t, help, "-check-index")
+}
@@ -304,6 +304,7 @@ func parseSpread(result *[]*api.Spread, list *ast.ObjectList) error {
 		valid := []string{
 			"attribute",
 			"weight",
+			"max_skew",
 			"target",
 		}
 		if err := checkHCLKeys(o.Val, valid); err != nil {
@@ -58,6 +58,7 @@ func parseGroups(result *api.Job, list *ast.ObjectList) error {
 			"scaling",
 			"stop_after_client_disconnect",
 			"max_client_disconnect",
+			"gang",
 		}
 		if err := checkHCLKeys(listVal, valid); err != nil {
 			return multierror.Prefix(err, fmt.Sprintf("'%s' ->", n))
@@ -29,6 +29,7 @@ func parseJob(result *api.Job, list *ast.ObjectList) error {
 	}
 	delete(m, "constraint")
 	delete(m, "affinity")
+	delete(m, "depends_on")
 	delete(m, "meta")
 	delete(m, "migrate")
 	delete(m, "parameterized")
@@ -63,15 +64,18 @@ func parseJob(result *api.Job, list *ast.ObjectList) error {
 		"affinity",
 		"spread",
 		"datacenters",
+		"depends_on",
 		"group",
 		"id",
 		"meta",
 		"migrate",
 		"name",
 		"namespace",
+		"non_preemptible",
 		"parameterized",
 		"periodic",
 		"priority",
+		"scheduler_algorithm",
 		"region",
 		"reschedule",
 		"task",
@@ -100,6 +104,13 @@ func parseJob(result *api.Job, list *ast.ObjectList) error {
 		}
 	}
 
+	// Parse dependencies
+	if o := listVal.Filter("depends_on"); len(o.Items) > 0 {
+		if err := parseDependsOn(&result.DependsOn, o); err != nil {
+			return multierror.Prefix(err, "depends_on ->")
+		}
+	}
+
 	// If we have an update strategy, then parse that
 	if o := listVal.Filter("update"); len(o.Items) > 0 {
 		if err := parseUpdate(&result.Update, o); err != nil {
@@ -293,3 +304,32 @@ func parseParameterizedJob(result **api.ParameterizedJobConfig, list *ast.Object
 	*result = &d
 	return nil
 }
+
+func parseDependsOn(result *[]*api.JobDependency, list *ast.ObjectList) error {
+	for _, o := range list.Elem().Items {
+		// Check for invalid keys
+		valid := []string{
+			"job",
+			"group",
+			"condition",
+		}
+		if err := checkHCLKeys(o.Val, valid); err != nil {
+			return err
+		}
+
+		var m map[string]interface{}
+		if err := hcl.DecodeObject(&m, o.Val); err != nil {
+			return err
+		}
+
+		// Build the dependency
+		var d api.JobDependency
+		if err := mapstructure.WeakDecode(m, &d); err != nil {
+			return err
+		}
+
+		*result = append(*result, &d)
+	}
+
+	return nil
+}
@@ -53,6 +53,7 @@ func parseService(o *ast.ObjectItem) (*api.Service, error) {
 		"tagged_addresses",
 		"on_update",
 		"provider",
+		"weight",
 	}
 	if err := checkHCLKeys(o.Val, valid); err != nil {
 		return nil, err
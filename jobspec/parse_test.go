@@ -50,16 +50,18 @@ func TestParse(t *testing.T) {
 		{
 			"basic.hcl",
 			&api.Job{
-				ID:          stringToPtr("binstore-storagelocker"),
-				Name:        stringToPtr("binstore-storagelocker"),
-				Type:        stringToPtr("batch"),
-				Priority:    intToPtr(52),
-				AllAtOnce:   boolToPtr(true),
-				Datacenters: []string{"us2", "eu1"},
-				Region:      stringToPtr("fooregion"),
-				Namespace:   stringToPtr("foonamespace"),
-				ConsulToken: stringToPtr("abc"),
-				VaultToken:  stringToPtr("foo"),
+				ID:                 stringToPtr("binstore-storagelocker"),
+				Name:               stringToPtr("binstore-storagelocker"),
+				Type:               stringToPtr("batch"),
+				Priority:           intToPtr(52),
+				AllAtOnce:          boolToPtr(true),
+				NonPreemptible:     boolToPtr(true),
+				SchedulerAlgorithm: stringToPtr("least-requested-with-headroom"),
+				Datacenters:        []string{"us2", "eu1"},
+				Region:             stringToPtr("fooregion"),
+				Namespace:          stringToPtr("foonamespace"),
+				ConsulToken:        stringToPtr("abc"),
+				VaultToken:         stringToPtr("foo"),
 
 				Meta: map[string]string{
 					"foo": "bar",
@@ -87,6 +89,18 @@ func TestParse(t *testing.T) {
 					},
 				},
 
+				DependsOn: []*api.JobDependency{
+					{
+						Job:       "binstore-db",
+						Group:     "db",
+						Condition: "healthy",
+					},
+					{
+						Job:       "binstore-migrations",
+						Condition: "complete",
+					},
+				},
+
 				Spreads: []*api.Spread{
 					{
 						Attribute: "${meta.rack}",
@@ -214,9 +228,15 @@ func TestParse(t *testing.T) {
 									},
 								},
 							},
+							{
+								Attribute: "${meta.rack}",
+								Weight:    int8ToPtr(50),
+								MaxSkew:   intToPtr(1),
+							},
 						},
 						StopAfterClientDisconnect: timeToPtr(120 * time.Second),
 						MaxClientDisconnect:       timeToPtr(120 * time.Hour),
+						Gang:                      boolToPtr(true),
 						ReschedulePolicy: &api.ReschedulePolicy{
 							Interval: timeToPtr(12 * time.Hour),
 							Attempts: intToPtr(5),
@@ -345,8 +365,27 @@ func TestParse(t *testing.T) {
 								KillTimeout:   timeToPtr(22 * time.Second),
 								ShutdownDelay: 11 * time.Second,
 								LogConfig: &api.LogConfig{
-									MaxFiles:      intToPtr(14),
-									MaxFileSizeMB: intToPtr(101),
+									MaxFiles:         intToPtr(14),
+									MaxFileSizeMB:    intToPtr(101),
+									RotationInterval: timeToPtr(24 * time.Hour),
+									Compress:         boolToPtr(true),
+									MaxTotalSizeMB:   intToPtr(1000),
+									Sinks: []*api.LogSink{
+										{
+											Type:     "syslog",
+											Address:  "127.0.0.1:514",
+											Protocol: "tcp",
+											Facility: "local3",
+										},
+										{
+											Type:          "http",
+											Address:       "http://127.0.0.1:8080/logs",
+											BatchSize:     50,
+											BatchInterval: 2 * time.Second,
+											BufferSize:    4096,
+											Backpressure:  true,
+										},
+									},
 								},
 								Artifacts: []*api.TaskArtifact{
 									{
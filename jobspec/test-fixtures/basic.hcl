@@ -1,12 +1,14 @@
 job "binstore-storagelocker" {
-  region       = "fooregion"
-  namespace    = "foonamespace"
-  type         = "batch"
-  priority     = 52
-  all_at_once  = true
-  datacenters  = ["us2", "eu1"]
-  consul_token = "abc"
-  vault_token  = "foo"
+  region              = "fooregion"
+  namespace           = "foonamespace"
+  type                = "batch"
+  priority            = 52
+  all_at_once         = true
+  non_preemptible     = true
+  scheduler_algorithm = "least-requested-with-headroom"
+  datacenters         = ["us2", "eu1"]
+  consul_token        = "abc"
+  vault_token         = "foo"
 
   meta {
     foo = "bar"
@@ -30,6 +32,17 @@ job "binstore-storagelocker" {
     weight    = 50
   }
 
+  depends_on {
+    job       = "binstore-db"
+    group     = "db"
+    condition = "healthy"
+  }
+
+  depends_on {
+    job       = "binstore-migrations"
+    condition = "complete"
+  }
+
   spread {
     attribute = "${meta.rack}"
     weight    = 100
@@ -157,8 +170,15 @@ job "binstore-storagelocker" {
       }
     }
 
+    spread {
+      attribute = "${meta.rack}"
+      weight    = 50
+      max_skew  = 1
+    }
+
     stop_after_client_disconnect = "120s"
     max_client_disconnect        = "120h"
+    gang                         = true
 
     task "binstore" {
       driver = "docker"
@@ -191,8 +211,27 @@ job "binstore-storagelocker" {
       }
 
       logs {
-        max_files     = 14
-        max_file_size = 101
+        max_files         = 14
+        max_file_size     = 101
+        rotation_interval = "24h"
+        compress          = true
+        max_total_size    = 1000
+
+        sink {
+          type     = "syslog"
+          address  = "127.0.0.1:514"
+          protocol = "tcp"
+          facility = "local3"
+        }
+
+        sink {
+          type           = "http"
+          address        = "http://127.0.0.1:8080/logs"
+          batch_size     = 50
+          batch_interval = "2s"
+          buffer_size    = 4096
+          backpressure   = true
+        }
       }
 
       env {
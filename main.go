@@ -13,6 +13,7 @@ import (
 	// into their command logic. This is because they are run as separate
 	// processes along side of a task. By early importing them we can avoid
 	// additional code being imported and thus reserving memory
+	_ "github.com/hashicorp/nomad/client/allocrunner/taskrunner/getter"
 	_ "github.com/hashicorp/nomad/client/logmon"
 	"github.com/hashicorp/nomad/command"
 	_ "github.com/hashicorp/nomad/drivers/docker/docklog"
@@ -29,6 +30,7 @@ var (
 	// commands above.
 	hidden = []string{
 		"alloc-status",
+		"artifact-isolation",
 		"check",
 		"client-config",
 		"debug",
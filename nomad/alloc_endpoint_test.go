@@ -126,7 +126,7 @@ func TestAllocEndpoint_List_PaginationFiltering(t *testing.T) {
 
 	state := s1.fsm.State()
 
-	require.NoError(t, state.UpsertNamespaces(1099, []*structs.Namespace{
+	require.NoError(t, state.UpsertNamespaces(structs.MsgTypeTestSetup, 1099, []*structs.Namespace{
 		{Name: "non-default"},
 	}))
 
@@ -648,7 +648,7 @@ func TestAllocEndpoint_List_AllNamespaces_OSS(t *testing.T) {
 	// two namespaces
 	ns1 := mock.Namespace()
 	ns2 := mock.Namespace()
-	require.NoError(t, state.UpsertNamespaces(900, []*structs.Namespace{ns1, ns2}))
+	require.NoError(t, state.UpsertNamespaces(structs.MsgTypeTestSetup, 900, []*structs.Namespace{ns1, ns2}))
 
 	// Create the allocations
 	uuid1 := uuid.Generate()
@@ -1187,7 +1187,7 @@ func TestAllocEndpoint_List_AllNamespaces_ACL_OSS(t *testing.T) {
 	// two namespaces
 	ns1 := mock.Namespace()
 	ns2 := mock.Namespace()
-	require.NoError(t, state.UpsertNamespaces(900, []*structs.Namespace{ns1, ns2}))
+	require.NoError(t, state.UpsertNamespaces(structs.MsgTypeTestSetup, 900, []*structs.Namespace{ns1, ns2}))
 
 	// Create the allocations
 	alloc1 := mock.Alloc()
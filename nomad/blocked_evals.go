@@ -63,6 +63,15 @@ type BlockedEvals struct {
 	// time they are being blocked.
 	unblockIndexes map[string]uint64
 
+	// dependencyIndexes maps the jobs depended on by blocked evaluations to
+	// the index in which they were last unblocked. They're kept apart from
+	// unblockIndexes so they aren't mistaken for computed node classes.
+	dependencyIndexes map[string]uint64
+
+	// dependents counts the captured evaluations blocked on each job, so
+	// changes to jobs nothing depends on are ignored.
+	dependents map[string]int
+
 	// duplicates is the set of evaluations for jobs that had pre-existing
 	// blocked evaluations. These should be marked as cancelled since only one
 	// blocked eval is needed per job.
@@ -85,6 +94,7 @@ type BlockedEvals struct {
 type capacityUpdate struct {
 	computedClass string
 	quotaChange   string
+	dependency    string
 	index         uint64
 }
 
@@ -98,17 +108,19 @@ type wrappedEval struct {
 // unblocked evals into the passed broker.
 func NewBlockedEvals(evalBroker *EvalBroker, logger hclog.Logger) *BlockedEvals {
 	return &BlockedEvals{
-		logger:           logger.Named("blocked_evals"),
-		evalBroker:       evalBroker,
-		captured:         make(map[string]wrappedEval),
-		escaped:          make(map[string]wrappedEval),
-		system:           newSystemEvals(),
-		jobs:             make(map[structs.NamespacedID]string),
-		unblockIndexes:   make(map[string]uint64),
-		capacityChangeCh: make(chan *capacityUpdate, unblockBuffer),
-		duplicateCh:      make(chan struct{}, 1),
-		stopCh:           make(chan struct{}),
-		stats:            NewBlockedStats(),
+		logger:            logger.Named("blocked_evals"),
+		evalBroker:        evalBroker,
+		captured:          make(map[string]wrappedEval),
+		escaped:           make(map[string]wrappedEval),
+		system:            newSystemEvals(),
+		jobs:              make(map[structs.NamespacedID]string),
+		unblockIndexes:    make(map[string]uint64),
+		dependencyIndexes: make(map[string]uint64),
+		dependents:        make(map[string]int),
+		capacityChangeCh:  make(chan *capacityUpdate, unblockBuffer),
+		duplicateCh:       make(chan struct{}, 1),
+		stopCh:            make(chan struct{}),
+		stats:             NewBlockedStats(),
 	}
 }
 
@@ -225,7 +237,33 @@ func (b *BlockedEvals) processBlock(eval *structs.Evaluation, token string) {
 
 	// Add the eval to the set of blocked evals whose jobs constraints are
 	// captured by computed node class.
-	b.captured[eval.ID] = wrapped
+	b.addCaptured(wrapped)
+}
+
+// addCaptured adds an evaluation to the captured set. This should be called
+// with the lock held.
+func (b *BlockedEvals) addCaptured(wrapped wrappedEval) {
+	b.captured[wrapped.eval.ID] = wrapped
+	if wrapped.eval.BlockedOnJob != "" {
+		b.dependents[dependencyKey(wrapped.eval.BlockedOnJob, wrapped.eval.Namespace)]++
+	}
+}
+
+// removeCaptured removes an evaluation from the captured set. This should be
+// called with the lock held.
+func (b *BlockedEvals) removeCaptured(id string) {
+	wrapped, ok := b.captured[id]
+	if !ok {
+		return
+	}
+	delete(b.captured, id)
+
+	if wrapped.eval.BlockedOnJob != "" {
+		key := dependencyKey(wrapped.eval.BlockedOnJob, wrapped.eval.Namespace)
+		if b.dependents[key]--; b.dependents[key] <= 0 {
+			delete(b.dependents, key)
+		}
+	}
 }
 
 // processBlockJobDuplicate handles the case where the new eval is for a job
@@ -247,7 +285,7 @@ func (b *BlockedEvals) processBlockJobDuplicate(eval *structs.Evaluation) (newCa
 	existingW, ok := b.captured[existingID]
 	if ok {
 		if latestEvalIndex(existingW.eval) <= latestEvalIndex(eval) {
-			delete(b.captured, existingID)
+			b.removeCaptured(existingID)
 			dup = existingW.eval
 			b.stats.Unblock(dup)
 		} else {
@@ -299,6 +337,13 @@ func latestEvalIndex(eval *structs.Evaluation) uint64 {
 // complete. This method returns if that is the case and should be called with
 // the lock held.
 func (b *BlockedEvals) missedUnblock(eval *structs.Evaluation) bool {
+	// The evaluation is blocked because a job it depends on hasn't reached
+	// the required state, so only changes to that job can unblock it.
+	if eval.BlockedOnJob != "" {
+		index, ok := b.dependencyIndexes[dependencyKey(eval.BlockedOnJob, eval.Namespace)]
+		return ok && eval.SnapshotIndex < index
+	}
+
 	var max uint64 = 0
 	for id, index := range b.unblockIndexes {
 		// Calculate the max unblock index
@@ -379,7 +424,7 @@ func (b *BlockedEvals) Untrack(jobID, namespace string) {
 	// Attempt to delete the evaluation
 	if w, ok := b.captured[evalID]; ok {
 		delete(b.jobs, nsID)
-		delete(b.captured, evalID)
+		b.removeCaptured(evalID)
 		b.stats.Unblock(w.eval)
 		if w.eval.QuotaLimitReached != "" {
 			b.stats.TotalQuotaLimit--
@@ -497,6 +542,57 @@ func (b *BlockedEvals) UnblockClassAndQuota(class, quota string, index uint64) {
 	}
 }
 
+// UnblockDependency causes any evaluation blocked on the dependency condition
+// of the passed job to be enqueued into the eval broker. Nothing is done if no
+// evaluation is blocked on the job.
+func (b *BlockedEvals) UnblockDependency(jobID, namespace string, index uint64) {
+	b.l.Lock()
+
+	// Do nothing if not enabled or nothing depends on the job
+	dependency := dependencyKey(jobID, namespace)
+	if !b.enabled || b.dependents[dependency] == 0 {
+		b.l.Unlock()
+		return
+	}
+
+	// Store the index in which the unblock happened. We use this on subsequent
+	// block calls in case the evaluation was in the scheduler when a trigger
+	// occurred.
+	b.dependencyIndexes[dependency] = index
+	ch := b.capacityChangeCh
+	done := b.stopCh
+	b.l.Unlock()
+
+	select {
+	case <-done:
+	case ch <- &capacityUpdate{
+		dependency: dependency,
+		index:      index,
+	}:
+	}
+}
+
+// SetDependencyIndex records the index in which the job depended on by an
+// evaluation last changed. It's called with the index found in the state store
+// before the evaluation is blocked, since UnblockDependency ignores changes
+// made while no evaluation was blocked on the job, including while the
+// evaluation was in the scheduler.
+func (b *BlockedEvals) SetDependencyIndex(jobID, namespace string, index uint64) {
+	b.l.Lock()
+	defer b.l.Unlock()
+
+	dependency := dependencyKey(jobID, namespace)
+	if index > b.dependencyIndexes[dependency] {
+		b.dependencyIndexes[dependency] = index
+	}
+}
+
+// dependencyKey returns the key used to track the unblock index of a job
+// depended on by blocked evaluations.
+func dependencyKey(jobID, namespace string) string {
+	return "job:" + structs.NewNamespacedID(jobID, namespace).String()
+}
+
 // UnblockNode finds any blocked evalution that's node specific (system jobs) and enqueues
 // it on the eval broker
 func (b *BlockedEvals) UnblockNode(nodeID string, index uint64) {
@@ -526,12 +622,12 @@ func (b *BlockedEvals) watchCapacity(stopCh <-chan struct{}, changeCh <-chan *ca
 		case <-stopCh:
 			return
 		case update := <-changeCh:
-			b.unblock(update.computedClass, update.quotaChange, update.index)
+			b.unblock(update.computedClass, update.quotaChange, update.dependency, update.index)
 		}
 	}
 }
 
-func (b *BlockedEvals) unblock(computedClass, quota string, index uint64) {
+func (b *BlockedEvals) unblock(computedClass, quota, dependency string, index uint64) {
 	b.l.Lock()
 	defer b.l.Unlock()
 
@@ -564,7 +660,14 @@ func (b *BlockedEvals) unblock(computedClass, quota string, index uint64) {
 	// never saw a node with the given computed class and thus needs to be
 	// unblocked for correctness.
 	for id, wrapped := range b.captured {
-		if quota != "" && wrapped.eval.QuotaLimitReached != quota {
+		if wrapped.eval.BlockedOnJob != "" || dependency != "" {
+			// Evaluations blocked on a job dependency are only unblocked by
+			// changes to the job depended on.
+			if dependency == "" ||
+				dependencyKey(wrapped.eval.BlockedOnJob, wrapped.eval.Namespace) != dependency {
+				continue
+			}
+		} else if quota != "" && wrapped.eval.QuotaLimitReached != quota {
 			// We are unblocking based on quota and this eval doesn't match
 			continue
 		} else if elig, ok := wrapped.eval.ClassEligibility[computedClass]; ok && !elig {
@@ -573,12 +676,12 @@ func (b *BlockedEvals) unblock(computedClass, quota string, index uint64) {
 			continue
 		}
 
-		// Unblock the evaluation because it is either for the matching quota,
-		// is eligible based on the computed node class, or never seen the
-		// computed node class.
+		// Unblock the evaluation because it is either for the matching quota
+		// or dependency, is eligible based on the computed node class, or
+		// never seen the computed node class.
 		unblocked[wrapped.eval] = wrapped.token
 		delete(b.jobs, structs.NewNamespacedID(wrapped.eval.JobID, wrapped.eval.Namespace))
-		delete(b.captured, id)
+		b.removeCaptured(id)
 		if wrapped.eval.QuotaLimitReached != "" {
 			numQuotaLimit++
 		}
@@ -586,7 +689,9 @@ func (b *BlockedEvals) unblock(computedClass, quota string, index uint64) {
 
 	if len(unblocked) != 0 {
 		// Update the counters
-		b.stats.TotalEscaped = 0
+		if dependency == "" {
+			b.stats.TotalEscaped = 0
+		}
 		b.stats.TotalQuotaLimit -= numQuotaLimit
 		for eval := range unblocked {
 			b.stats.Unblock(eval)
@@ -613,7 +718,7 @@ func (b *BlockedEvals) UnblockFailed() {
 	for id, wrapped := range b.captured {
 		if wrapped.eval.TriggeredBy == structs.EvalTriggerMaxPlans {
 			unblocked[wrapped.eval] = wrapped.token
-			delete(b.captured, id)
+			b.removeCaptured(id)
 			delete(b.jobs, structs.NewNamespacedID(wrapped.eval.JobID, wrapped.eval.Namespace))
 			if wrapped.eval.QuotaLimitReached != "" {
 				quotaLimit++
@@ -694,6 +799,8 @@ func (b *BlockedEvals) Flush() {
 	b.escaped = make(map[string]wrappedEval)
 	b.jobs = make(map[structs.NamespacedID]string)
 	b.unblockIndexes = make(map[string]uint64)
+	b.dependencyIndexes = make(map[string]uint64)
+	b.dependents = make(map[string]int)
 	b.timetable = nil
 	b.duplicates = nil
 	b.capacityChangeCh = make(chan *capacityUpdate, unblockBuffer)
@@ -790,6 +897,11 @@ func (b *BlockedEvals) pruneUnblockIndexes(cutoff time.Time) {
 			delete(b.unblockIndexes, key)
 		}
 	}
+	for key, index := range b.dependencyIndexes {
+		if index < oldThreshold {
+			delete(b.dependencyIndexes, key)
+		}
+	}
 }
 
 // pruneStats is used to prune any zero value stats that are excessively old.
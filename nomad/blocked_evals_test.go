@@ -512,6 +512,123 @@ func TestBlockedEvals_Block_ImmediateUnblock_SeenClass(t *testing.T) {
 
 // Test the block case in which the eval should be immediately unblocked since
 // it a quota has changed that it is using
+func TestBlockedEvals_UnblockDependency(t *testing.T) {
+	ci.Parallel(t)
+	require := require.New(t)
+
+	blocked, broker := testBlockedEvals(t)
+
+	// Create a blocked eval that waits on a job dependency.
+	e := mock.BlockedEval()
+	e.BlockedOnJob = "db"
+	blocked.Block(e)
+
+	// Verify block caused the eval to be tracked.
+	blockedStats := blocked.Stats()
+	require.Equal(1, blockedStats.TotalBlocked)
+	require.Len(blockedStats.BlockedResources.ByJob, 1)
+
+	// Capacity changes and other jobs should do nothing.
+	blocked.Unblock("v1:123", 1000)
+	blocked.UnblockDependency("cache", e.Namespace, 1001)
+
+	testutil.WaitForResult(func() (bool, error) {
+		// Verify Unblock didn't cause an enqueue
+		brokerStats := broker.Stats()
+		if brokerStats.TotalReady != 0 {
+			return false, fmt.Errorf("eval unblocked: %#v", brokerStats)
+		}
+
+		blockedStats := blocked.Stats()
+		if blockedStats.TotalBlocked != 1 {
+			return false, fmt.Errorf("eval unblocked: %#v", blockedStats)
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("err: %s", err)
+	})
+
+	blocked.UnblockDependency("db", e.Namespace, 1002)
+	requireBlockedEvalsEnqueued(t, blocked, broker, 1)
+}
+
+func TestBlockedEvals_Block_ImmediateUnblock_Dependency(t *testing.T) {
+	ci.Parallel(t)
+	require := require.New(t)
+
+	blocked, broker := testBlockedEvals(t)
+
+	// Record a change to the job made while the eval was in the scheduler
+	blocked.SetDependencyIndex("db", structs.DefaultNamespace, 1000)
+
+	// Create a blocked eval that waits on the job and add it to the blocked
+	// tracker.
+	e := mock.BlockedEval()
+	e.BlockedOnJob = "db"
+	e.SnapshotIndex = 900
+	blocked.Block(e)
+
+	// Verify block caused the eval to be immediately unblocked
+	blockedStats := blocked.Stats()
+	require.Equal(0, blockedStats.TotalBlocked)
+	require.Len(blockedStats.BlockedResources.ByJob, 0)
+
+	requireBlockedEvalsEnqueued(t, blocked, broker, 1)
+}
+
+func TestBlockedEvals_UnblockDependency_NoDependents(t *testing.T) {
+	ci.Parallel(t)
+	require := require.New(t)
+
+	blocked, broker := testBlockedEvals(t)
+
+	// Unblocking a job no eval is blocked on is ignored
+	blocked.UnblockDependency("db", structs.DefaultNamespace, 1000)
+
+	e := mock.BlockedEval()
+	e.BlockedOnJob = "db"
+	e.SnapshotIndex = 900
+	blocked.Block(e)
+
+	blockedStats := blocked.Stats()
+	require.Equal(1, blockedStats.TotalBlocked)
+	require.Equal(0, broker.Stats().TotalReady)
+
+	// Once the eval is unblocked the job has no dependents again
+	blocked.UnblockDependency("db", e.Namespace, 1001)
+	requireBlockedEvalsEnqueued(t, blocked, broker, 1)
+
+	blocked.l.RLock()
+	defer blocked.l.RUnlock()
+	require.Empty(blocked.dependents)
+}
+
+func TestBlockedEvals_Block_DependencyUnblockIgnoredByClass(t *testing.T) {
+	ci.Parallel(t)
+	require := require.New(t)
+
+	blocked, broker := testBlockedEvals(t)
+
+	// Unblock an eval waiting on a job dependency
+	dep := mock.BlockedEval()
+	dep.BlockedOnJob = "db"
+	blocked.Block(dep)
+	blocked.UnblockDependency("db", dep.Namespace, 1000)
+	requireBlockedEvalsEnqueued(t, blocked, broker, 1)
+
+	// An eval blocked on capacity, processed before the dependency unblock,
+	// didn't miss an unblock so stays blocked
+	e := mock.BlockedEval()
+	e.ClassEligibility = map[string]bool{"v1:123": true}
+	e.SnapshotIndex = 900
+	blocked.Block(e)
+
+	blockedStats := blocked.Stats()
+	require.Equal(1, blockedStats.TotalBlocked)
+	require.Len(blockedStats.BlockedResources.ByJob, 1)
+	require.Equal(1, broker.Stats().TotalReady)
+}
+
 func TestBlockedEvals_Block_ImmediateUnblock_Quota(t *testing.T) {
 	ci.Parallel(t)
 	require := require.New(t)
@@ -213,10 +213,25 @@ type Config struct {
 	// before it's rotated
 	RootKeyRotationThreshold time.Duration
 
+	// RootKeyRotationRekey is whether the variables encrypted with the
+	// previous active key are rekeyed when the active key is rotated
+	// automatically
+	RootKeyRotationRekey bool
+
 	// VariablesRekeyInterval is how often we dispatch a job to
 	// rekey any variables associated with a key in the Rekeying state
 	VariablesRekeyInterval time.Duration
 
+	// VariablesVersionLimit is the number of prior versions of each
+	// variable to retain. Retained versions count against the variables
+	// quota of their namespace.
+	VariablesVersionLimit int
+
+	// KeystoreWrapper configures the wrapping of the root keys persisted to
+	// the local keystore by an external key encryption key. Root keys are
+	// persisted unwrapped if it is nil.
+	KeystoreWrapper *config.KeystoreWrapperConfig
+
 	// EvalNackTimeout controls how long we allow a sub-scheduler to
 	// work on an evaluation before we consider it failed and Nack it.
 	// This allows that evaluation to be handed to another sub-scheduler
@@ -2,7 +2,6 @@ package nomad
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"math"
 	"strings"
@@ -943,6 +942,13 @@ func (c *CoreScheduler) rootKeyRotateOrGC(eval *structs.Evaluation) error {
 		if varIter.Next() != nil {
 			continue // key is still in use
 		}
+		versionIter, err := c.snap.GetVariablesVersionsByKeyID(ws, keyMeta.KeyID)
+		if err != nil {
+			return err
+		}
+		if versionIter.Next() != nil {
+			continue // key is still in use by a prior version of a variable
+		}
 
 		req := &structs.KeyringDeleteRootKeyRequest{
 			KeyID: keyMeta.KeyID,
@@ -980,7 +986,10 @@ func (c *CoreScheduler) rootKeyRotation(eval *structs.Evaluation) (bool, error)
 		return false, nil // key is too new
 	}
 
+	// A full rotation has the leader start rekeying the variables encrypted
+	// with the previous key once the new key is active
 	req := &structs.KeyringRotateRootKeyRequest{
+		Full: c.srv.config.RootKeyRotationRekey,
 		WriteRequest: structs.WriteRequest{
 			Region:    c.srv.config.Region,
 			AuthToken: eval.LeaderACL,
@@ -996,12 +1005,24 @@ func (c *CoreScheduler) rootKeyRotation(eval *structs.Evaluation) (bool, error)
 }
 
 // variablesReKey is optionally run after rotating the active
-// root key. It iterates over all the variables for the keys in the
-// re-keying state, decrypts them, and re-encrypts them in batches
-// with the currently active key. This job does not GC the keys, which
-// is handled in the normal periodic GC job.
+// root key. It iterates over all the variables and retained prior versions of
+// variables for the keys in the re-keying state, and re-encrypts them with the
+// currently active key. Once a key is no longer used by any variable it is
+// deprecated. This job does not GC the keys, which is handled in the normal
+// periodic GC job.
 func (c *CoreScheduler) variablesRekey(eval *structs.Evaluation) error {
 
+	// We may have to work on a very large number of variables. There's no
+	// BatchApply RPC because it makes for an awkward API around conflict
+	// detection, and even if we did, we'd be blocking this scheduler goroutine
+	// for a very long time using the same snapshot.
+	//
+	// Instead, we'll rate limit RPC requests and have a timeout. If we still
+	// haven't finished the set by the timeout, emit a new eval.
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+	limiter := rate.NewLimiter(rate.Limit(100), 100)
+
 	ws := memdb.NewWatchSet()
 	iter, err := c.snap.RootKeyMetas(ws)
 	if err != nil {
@@ -1021,10 +1042,24 @@ func (c *CoreScheduler) variablesRekey(eval *structs.Evaluation) error {
 		if err != nil {
 			return err
 		}
-		err = c.rotateVariables(varIter, eval)
+		done, err := c.rotateVariables(ctx, limiter, varIter, eval)
 		if err != nil {
 			return err
 		}
+		if !done {
+			return c.variablesRekeyFollowUp(eval)
+		}
+		versionIter, err := c.snap.GetVariablesVersionsByKeyID(ws, keyMeta.KeyID)
+		if err != nil {
+			return err
+		}
+		done, err = c.rotateVariables(ctx, limiter, versionIter, eval)
+		if err != nil {
+			return err
+		}
+		if !done {
+			return c.variablesRekeyFollowUp(eval)
+		}
 
 		// we've now rotated all this key's variables, so set its state
 		keyMeta = keyMeta.Copy()
@@ -1054,96 +1089,72 @@ func (c *CoreScheduler) variablesRekey(eval *structs.Evaluation) error {
 	return nil
 }
 
-// rotateVariables runs over an iterator of variables and decrypts them, and
-// then sends them back to be re-encrypted with the currently active key,
-// checking for conflicts
-func (c *CoreScheduler) rotateVariables(iter memdb.ResultIterator, eval *structs.Evaluation) error {
-
-	args := &structs.VariablesApplyRequest{
-		Op: structs.VarOpCAS,
-		WriteRequest: structs.WriteRequest{
-			Region:    c.srv.config.Region,
-			AuthToken: eval.LeaderACL,
-		},
-	}
-
-	// We may have to work on a very large number of variables. There's no
-	// BatchApply RPC because it makes for an awkward API around conflict
-	// detection, and even if we did, we'd be blocking this scheduler goroutine
-	// for a very long time using the same snapshot. This would increase the
-	// risk that any given batch hits a conflict because of a concurrent change
-	// and make it more likely that we fail the eval. For large sets, this would
-	// likely mean the eval would run out of retries.
-	//
-	// Instead, we'll rate limit RPC requests and have a timeout. If we still
-	// haven't finished the set by the timeout, emit a new eval.
-	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
-	defer cancel()
-	limiter := rate.NewLimiter(rate.Limit(100), 100)
+// rotateVariables runs over an iterator of variables, or of retained prior
+// versions of variables, and sends them to be re-encrypted with the currently
+// active key. It returns false if the context expired before all the
+// variables were rotated.
+func (c *CoreScheduler) rotateVariables(ctx context.Context, limiter *rate.Limiter,
+	iter memdb.ResultIterator, eval *structs.Evaluation) (bool, error) {
 
 	for {
 		raw := iter.Next()
 		if raw == nil {
 			break
 		}
+		ev := raw.(*structs.VariableEncrypted)
 
-		select {
-		case <-ctx.Done():
-			newEval := &structs.Evaluation{
-				ID:          uuid.Generate(),
-				Namespace:   "-",
-				Priority:    structs.CoreJobPriority,
-				Type:        structs.JobTypeCore,
-				TriggeredBy: structs.EvalTriggerScheduled,
-				JobID:       eval.JobID,
-				Status:      structs.EvalStatusPending,
-				LeaderACL:   eval.LeaderACL,
-			}
-			return c.srv.RPC("Eval.Create", &structs.EvalUpdateRequest{
-				Evals:     []*structs.Evaluation{newEval},
-				EvalToken: uuid.Generate(),
-				WriteRequest: structs.WriteRequest{
-					Region:    c.srv.config.Region,
-					AuthToken: eval.LeaderACL,
+		// The servers re-encrypt the variable from their own state, so the
+		// variable's items never leave the leader
+		args := &structs.VariablesApplyRequest{
+			Op: structs.VarOpRekey,
+			Var: &structs.VariableDecrypted{
+				VariableMetadata: structs.VariableMetadata{
+					Namespace: ev.Namespace,
+					Path:      ev.Path,
 				},
-			}, &structs.GenericResponse{})
-
-		default:
-		}
-
-		ev := raw.(*structs.VariableEncrypted)
-		cleartext, err := c.srv.encrypter.Decrypt(ev.Data, ev.KeyID)
-		if err != nil {
-			return err
-		}
-		dv := &structs.VariableDecrypted{
-			VariableMetadata: ev.VariableMetadata,
-		}
-		dv.Items = make(map[string]string)
-		err = json.Unmarshal(cleartext, &dv.Items)
-		if err != nil {
-			return err
+			},
+			Version: ev.ModifyIndex,
+			WriteRequest: structs.WriteRequest{
+				Region:    c.srv.config.Region,
+				Namespace: ev.Namespace,
+				AuthToken: eval.LeaderACL,
+			},
 		}
-		args.Var = dv
-		reply := &structs.VariablesApplyResponse{}
 
 		if err := limiter.Wait(ctx); err != nil {
-			return err
+			return false, nil
 		}
 
-		err = c.srv.RPC("Variables.Apply", args, reply)
-		if err != nil {
-			return err
-		}
-		if reply.IsConflict() {
-			// we've already rotated the key by the time we took this
-			// evaluation's snapshot, so any conflict is going to be on a write
-			// made with the new key, so there's nothing for us to do here
-			continue
+		reply := &structs.VariablesApplyResponse{}
+		if err := c.srv.RPC("Variables.Apply", args, reply); err != nil {
+			return false, err
 		}
 	}
 
-	return nil
+	return true, nil
+}
+
+// variablesRekeyFollowUp creates a new eval to continue rekeying variables
+// once this eval has run out of time.
+func (c *CoreScheduler) variablesRekeyFollowUp(eval *structs.Evaluation) error {
+	newEval := &structs.Evaluation{
+		ID:          uuid.Generate(),
+		Namespace:   "-",
+		Priority:    structs.CoreJobPriority,
+		Type:        structs.JobTypeCore,
+		TriggeredBy: structs.EvalTriggerScheduled,
+		JobID:       eval.JobID,
+		Status:      structs.EvalStatusPending,
+		LeaderACL:   eval.LeaderACL,
+	}
+	return c.srv.RPC("Eval.Create", &structs.EvalUpdateRequest{
+		Evals:     []*structs.Evaluation{newEval},
+		EvalToken: uuid.Generate(),
+		WriteRequest: structs.WriteRequest{
+			Region:    c.srv.config.Region,
+			AuthToken: eval.LeaderACL,
+		},
+	}, &structs.GenericResponse{})
 }
 
 // getThreshold returns the index threshold for determining whether an
@@ -2474,17 +2474,17 @@ func TestCoreScheduler_RootKeyGC(t *testing.T) {
 	// insert an "old" and inactive key
 	key1 := structs.NewRootKeyMeta()
 	key1.SetInactive()
-	require.NoError(t, store.UpsertRootKeyMeta(500, key1, false))
+	require.NoError(t, store.UpsertRootKeyMeta(structs.MsgTypeTestSetup, 500, key1, false))
 
 	// insert an "old" and inactive key with a variable that's using it
 	key2 := structs.NewRootKeyMeta()
 	key2.SetInactive()
-	require.NoError(t, store.UpsertRootKeyMeta(600, key2, false))
+	require.NoError(t, store.UpsertRootKeyMeta(structs.MsgTypeTestSetup, 600, key2, false))
 
 	variable := mock.VariableEncrypted()
 	variable.KeyID = key2.KeyID
 
-	setResp := store.VarSet(601, &structs.VarApplyStateRequest{
+	setResp := store.VarSet(structs.MsgTypeTestSetup, 601, &structs.VarApplyStateRequest{
 		Op:  structs.VarOpSet,
 		Var: variable,
 	})
@@ -2499,7 +2499,7 @@ func TestCoreScheduler_RootKeyGC(t *testing.T) {
 	// insert an "old" key that's newer than oldest alloc
 	key3 := structs.NewRootKeyMeta()
 	key3.SetInactive()
-	require.NoError(t, store.UpsertRootKeyMeta(750, key3, false))
+	require.NoError(t, store.UpsertRootKeyMeta(structs.MsgTypeTestSetup, 750, key3, false))
 
 	// insert a time table index before the last key
 	tt := srv.fsm.TimeTable()
@@ -2508,7 +2508,7 @@ func TestCoreScheduler_RootKeyGC(t *testing.T) {
 	// insert a "new" but inactive key
 	key4 := structs.NewRootKeyMeta()
 	key4.SetInactive()
-	require.NoError(t, store.UpsertRootKeyMeta(1500, key4, false))
+	require.NoError(t, store.UpsertRootKeyMeta(structs.MsgTypeTestSetup, 1500, key4, false))
 
 	// run the core job
 	snap, err := store.Snapshot()
@@ -2544,7 +2544,9 @@ func TestCoreScheduler_RootKeyGC(t *testing.T) {
 func TestCoreScheduler_VariablesRekey(t *testing.T) {
 	ci.Parallel(t)
 
-	srv, cleanup := TestServer(t, nil)
+	srv, cleanup := TestServer(t, func(c *Config) {
+		c.VariablesVersionLimit = 2
+	})
 	defer cleanup()
 	testutil.WaitForLeader(t, srv.RPC)
 
@@ -2581,6 +2583,21 @@ func TestCoreScheduler_VariablesRekey(t *testing.T) {
 		require.NoError(t, srv.RPC("Variables.Apply", req, resp))
 	}
 
+	// overwrite a variable so that its prior version is retained
+	versioned := mock.Variable()
+	for i := 0; i < 2; i++ {
+		versioned.Items["version"] = fmt.Sprint(i)
+		req := &structs.VariablesApplyRequest{
+			Op:           structs.VarOpSet,
+			Var:          versioned,
+			WriteRequest: structs.WriteRequest{Region: srv.config.Region},
+		}
+		resp := &structs.VariablesApplyResponse{}
+		require.NoError(t, srv.RPC("Variables.Apply", req, resp))
+	}
+	versionedBefore, err := store.GetVariable(nil, versioned.Namespace, versioned.Path)
+	require.NoError(t, err)
+
 	rotateReq.Full = true
 	require.NoError(t, srv.RPC("Keyring.Rotate", rotateReq, &rotateResp))
 	newKeyID := rotateResp.Key.KeyID
@@ -2603,6 +2620,28 @@ func TestCoreScheduler_VariablesRekey(t *testing.T) {
 	}, time.Second*5, 100*time.Millisecond,
 		"variable rekey should be complete")
 
+	// rekeying rewrites the retained versions, without retaining new ones or
+	// changing the variables' metadata
+	require.Eventually(t, func() bool {
+		iter, err := store.VariablesVersions(nil)
+		require.NoError(t, err)
+		for raw := iter.Next(); raw != nil; raw = iter.Next() {
+			if raw.(*structs.VariableEncrypted).KeyID != newKeyID {
+				return false
+			}
+		}
+		return true
+	}, time.Second*5, 100*time.Millisecond,
+		"variable version rekey should be complete")
+
+	versions, err := store.GetVariableVersions(nil, versioned.Namespace, versioned.Path)
+	require.NoError(t, err)
+	require.Len(t, versions, 1)
+	versionedAfter, err := store.GetVariable(nil, versioned.Namespace, versioned.Path)
+	require.NoError(t, err)
+	require.Equal(t, versionedBefore.ModifyIndex, versionedAfter.ModifyIndex)
+	require.Equal(t, newKeyID, versionedAfter.KeyID)
+
 	iter, err := store.RootKeyMetas(memdb.NewWatchSet())
 	require.NoError(t, err)
 	for {
@@ -132,7 +132,7 @@ func TestCSIVolumeEndpoint_Register(t *testing.T) {
 
 	// Create the register request
 	ns := mock.Namespace()
-	store.UpsertNamespaces(900, []*structs.Namespace{ns})
+	store.UpsertNamespaces(structs.MsgTypeTestSetup, 900, []*structs.Namespace{ns})
 
 	// Create the node and plugin
 	node := mock.Node()
@@ -702,7 +702,7 @@ func TestCSIVolumeEndpoint_ListAllNamespaces(t *testing.T) {
 	ns0 := structs.DefaultNamespace
 	ns1 := "namespace-1"
 	ns2 := "namespace-2"
-	err := state.UpsertNamespaces(1000, []*structs.Namespace{{Name: ns1}, {Name: ns2}})
+	err := state.UpsertNamespaces(structs.MsgTypeTestSetup, 1000, []*structs.Namespace{{Name: ns1}, {Name: ns2}})
 	require.NoError(t, err)
 
 	// Create volumes in multiple namespaces.
@@ -801,7 +801,7 @@ func TestCSIVolumeEndpoint_List_PaginationFiltering(t *testing.T) {
 	plugin := mock.CSIPlugin()
 
 	// Create namespaces.
-	err := state.UpsertNamespaces(999, []*structs.Namespace{{Name: nonDefaultNS}})
+	err := state.UpsertNamespaces(structs.MsgTypeTestSetup, 999, []*structs.Namespace{{Name: nonDefaultNS}})
 	require.NoError(t, err)
 
 	for i, m := range mocks {
@@ -26,6 +26,7 @@ import (
 	"golang.org/x/time/rate"
 
 	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/nomad/keywrap"
 	"github.com/hashicorp/nomad/nomad/structs"
 )
 
@@ -37,6 +38,9 @@ type Encrypter struct {
 	srv          *Server
 	keystorePath string
 
+	// wrapper wraps the root keys persisted to the keystore, if set
+	wrapper keywrap.Wrapper
+
 	keyring map[string]*keyset
 	lock    sync.RWMutex
 }
@@ -48,13 +52,14 @@ type keyset struct {
 }
 
 // NewEncrypter loads or creates a new local keystore and returns an
-// encryption keyring with the keys it finds.
-func NewEncrypter(srv *Server, keystorePath string) (*Encrypter, error) {
+// encryption keyring with the keys it finds. If wrapper is set, the keys in
+// the keystore are wrapped by it.
+func NewEncrypter(srv *Server, keystorePath string, wrapper keywrap.Wrapper) (*Encrypter, error) {
 	err := os.MkdirAll(keystorePath, 0700)
 	if err != nil {
 		return nil, err
 	}
-	encrypter, err := encrypterFromKeystore(keystorePath)
+	encrypter, err := encrypterFromKeystore(keystorePath, wrapper)
 	if err != nil {
 		return nil, err
 	}
@@ -62,11 +67,12 @@ func NewEncrypter(srv *Server, keystorePath string) (*Encrypter, error) {
 	return encrypter, nil
 }
 
-func encrypterFromKeystore(keystoreDirectory string) (*Encrypter, error) {
+func encrypterFromKeystore(keystoreDirectory string, wrapper keywrap.Wrapper) (*Encrypter, error) {
 
 	encrypter := &Encrypter{
 		keyring:      make(map[string]*keyset),
 		keystorePath: keystoreDirectory,
+		wrapper:      wrapper,
 	}
 
 	err := filepath.Walk(keystoreDirectory, func(path string, info fs.FileInfo, err error) error {
@@ -96,6 +102,8 @@ func encrypterFromKeystore(keystoreDirectory string) (*Encrypter, error) {
 			return fmt.Errorf("root key ID %s must match key file %s", key.Meta.KeyID, path)
 		}
 
+		// Adding the key saves it back to the keystore, which wraps any
+		// key persisted before a wrapper was configured
 		err = encrypter.AddKey(key)
 		if err != nil {
 			return fmt.Errorf("could not add key file %s to keystore: %v", path, err)
@@ -315,11 +323,27 @@ func (e *Encrypter) RemoveKey(keyID string) error {
 	return nil
 }
 
-// saveKeyToStore serializes a root key to the on-disk keystore.
+// saveKeyToStore serializes a root key to the on-disk keystore. The key
+// material is wrapped if a wrapper is configured.
 func (e *Encrypter) saveKeyToStore(rootKey *structs.RootKey) error {
+	var stored interface{} = rootKey
+	if e.wrapper != nil {
+		wrapped, err := e.wrapper.Wrap(rootKey.Key)
+		if err != nil {
+			return err
+		}
+		stored = &struct {
+			Meta       *structs.RootKeyMeta
+			WrappedKey *keywrap.WrappedKey
+		}{
+			Meta:       rootKey.Meta,
+			WrappedKey: wrapped,
+		}
+	}
+
 	var buf bytes.Buffer
 	enc := codec.NewEncoder(&buf, structs.JsonHandleWithExtensions)
-	err := enc.Encode(rootKey)
+	err := enc.Encode(stored)
 	if err != nil {
 		return err
 	}
@@ -340,8 +364,9 @@ func (e *Encrypter) loadKeyFromStore(path string) (*structs.RootKey, error) {
 	}
 
 	storedKey := &struct {
-		Meta *structs.RootKeyMetaStub
-		Key  string
+		Meta       *structs.RootKeyMetaStub
+		Key        string
+		WrappedKey *keywrap.WrappedKey
 	}{}
 
 	if err := json.Unmarshal(raw, storedKey); err != nil {
@@ -358,9 +383,20 @@ func (e *Encrypter) loadKeyFromStore(path string) (*structs.RootKey, error) {
 		return nil, err
 	}
 
-	key, err := base64.StdEncoding.DecodeString(storedKey.Key)
-	if err != nil {
-		return nil, fmt.Errorf("could not decode key: %v", err)
+	var key []byte
+	if storedKey.WrappedKey != nil {
+		if e.wrapper == nil {
+			return nil, fmt.Errorf("key is wrapped but no keystore wrapper is configured")
+		}
+		key, err = e.wrapper.Unwrap(storedKey.WrappedKey)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		key, err = base64.StdEncoding.DecodeString(storedKey.Key)
+		if err != nil {
+			return nil, fmt.Errorf("could not decode key: %v", err)
+		}
 	}
 
 	return &structs.RootKey{
@@ -466,6 +502,8 @@ START:
 						goto ERR_WAIT
 					}
 				}
+				// The replicated key is persisted through the same path
+				// as local keys, so it is wrapped if configured
 				err = krr.encrypter.AddKey(getResp.Key)
 				if err != nil {
 					krr.logger.Error("failed to add key", "key", keyID, "error", err)
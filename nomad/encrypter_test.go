@@ -2,6 +2,7 @@ package nomad
 
 import (
 	"context"
+	"encoding/base64"
 	"os"
 	"path/filepath"
 	"testing"
@@ -11,6 +12,8 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/keywrap"
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/testutil"
@@ -21,7 +24,7 @@ func TestEncrypter_LoadSave(t *testing.T) {
 	ci.Parallel(t)
 
 	tmpDir := t.TempDir()
-	encrypter, err := NewEncrypter(nil, tmpDir)
+	encrypter, err := NewEncrypter(nil, tmpDir, nil)
 	require.NoError(t, err)
 
 	algos := []structs.EncryptionAlgorithm{
@@ -42,6 +45,51 @@ func TestEncrypter_LoadSave(t *testing.T) {
 	}
 }
 
+// TestEncrypter_LoadSaveWrapped exercises round-tripping keys to disk with a
+// keystore wrapper, including the migration of keys saved before the wrapper
+// was configured
+func TestEncrypter_LoadSaveWrapped(t *testing.T) {
+	ci.Parallel(t)
+
+	kek := make([]byte, 32)
+	copy(kek, uuid.Generate())
+	kekPath := filepath.Join(t.TempDir(), "kek")
+	require.NoError(t, os.WriteFile(kekPath,
+		[]byte(base64.StdEncoding.EncodeToString(kek)), 0600))
+	wrapper, err := keywrap.NewFileWrapper(kekPath)
+	require.NoError(t, err)
+
+	// save a key without a wrapper
+	tmpDir := t.TempDir()
+	plain, err := NewEncrypter(nil, tmpDir, nil)
+	require.NoError(t, err)
+	key, err := structs.NewRootKey(structs.EncryptionAlgorithmAES256GCM)
+	require.NoError(t, err)
+	require.NoError(t, plain.AddKey(key))
+
+	keyPath := filepath.Join(tmpDir, key.Meta.KeyID+".nks.json")
+	raw, err := os.ReadFile(keyPath)
+	require.NoError(t, err)
+	require.Contains(t, string(raw), base64.StdEncoding.EncodeToString(key.Key))
+
+	// loading the keystore with a wrapper wraps the existing key
+	wrapped, err := NewEncrypter(nil, tmpDir, wrapper)
+	require.NoError(t, err)
+	raw, err = os.ReadFile(keyPath)
+	require.NoError(t, err)
+	require.NotContains(t, string(raw), base64.StdEncoding.EncodeToString(key.Key))
+	require.Contains(t, string(raw), "WrappedKey")
+
+	gotKey, err := wrapped.loadKeyFromStore(keyPath)
+	require.NoError(t, err)
+	require.Equal(t, key.Key, gotKey.Key)
+	require.Equal(t, key.Meta.KeyID, gotKey.Meta.KeyID)
+
+	// wrapped keys can't be loaded without the wrapper
+	_, err = plain.loadKeyFromStore(keyPath)
+	require.EqualError(t, err, "key is wrapped but no keystore wrapper is configured")
+}
+
 // TestEncrypter_Restore exercises the entire reload of a keystore,
 // including pairing metadata with key material
 func TestEncrypter_Restore(t *testing.T) {
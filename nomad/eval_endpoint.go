@@ -387,6 +387,7 @@ func (e *Eval) Reblock(args *structs.EvalUpdateRequest, reply *structs.GenericRe
 	}
 
 	// Reblock the eval
+	setDependencyIndex(e.srv.blockedEvals, &snap.StateStore, eval)
 	e.srv.blockedEvals.Reblock(eval, args.EvalToken)
 	return nil
 }
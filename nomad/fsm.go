@@ -12,6 +12,7 @@ import (
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/go-memdb"
 	"github.com/hashicorp/go-msgpack/codec"
+	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/helper/pointer"
 	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/state"
@@ -59,6 +60,7 @@ const (
 	VariablesQuotaSnapshot               SnapshotType = 23
 	RootKeyMetaSnapshot                  SnapshotType = 24
 	ACLRoleSnapshot                      SnapshotType = 25
+	VariablesVersionsSnapshot            SnapshotType = 26
 
 	// Namespace appliers were moved from enterprise and therefore start at 64
 	NamespaceSnapshot SnapshotType = 64
@@ -297,9 +299,9 @@ func (n *nomadFSM) Apply(log *raft.Log) interface{} {
 	case structs.CSIPluginDeleteRequestType:
 		return n.applyCSIPluginDelete(buf[1:], log.Index)
 	case structs.NamespaceUpsertRequestType:
-		return n.applyNamespaceUpsert(buf[1:], log.Index)
+		return n.applyNamespaceUpsert(msgType, buf[1:], log.Index)
 	case structs.NamespaceDeleteRequestType:
-		return n.applyNamespaceDelete(buf[1:], log.Index)
+		return n.applyNamespaceDelete(msgType, buf[1:], log.Index)
 	// COMPAT(1.0): These messages were added and removed during the 1.0-beta
 	// series and should not be immediately reused for other purposes
 	case structs.EventSinkUpsertRequestType,
@@ -318,6 +320,8 @@ func (n *nomadFSM) Apply(log *raft.Log) interface{} {
 		return n.applyDeleteServiceRegistrationByID(msgType, buf[1:], log.Index)
 	case structs.ServiceRegistrationDeleteByNodeIDRequestType:
 		return n.applyDeleteServiceRegistrationByNodeID(msgType, buf[1:], log.Index)
+	case structs.ServiceRegistrationUpdateChecksRequestType:
+		return n.applyUpdateServiceRegistrationChecks(msgType, buf[1:], log.Index)
 	case structs.VarApplyStateRequestType:
 		return n.applyVariableOperation(msgType, buf[1:], log.Index)
 	case structs.RootKeyMetaUpsertRequestType:
@@ -653,6 +657,9 @@ func (n *nomadFSM) applyDeregisterJob(msgType structs.MessageType, buf []byte, i
 		return err
 	}
 
+	// Unblock evals for jobs depending on the job, so they fail or stay
+	// blocked with the job stopped or purged.
+	n.blockedEvals.UnblockDependency(req.JobID, req.Namespace, index)
 	return nil
 }
 
@@ -688,6 +695,9 @@ func (n *nomadFSM) applyBatchDeregisterJob(msgType structs.MessageType, buf []by
 
 	// perform the side effects outside the transactions
 	n.handleUpsertedEvals(req.Evals)
+	for jobNS := range req.Jobs {
+		n.blockedEvals.UnblockDependency(jobNS.ID, jobNS.Namespace, index)
+	}
 	return nil
 }
 
@@ -787,11 +797,12 @@ func (n *nomadFSM) handleUpsertedEval(eval *structs.Evaluation) {
 	if eval.ShouldEnqueue() {
 		n.evalBroker.Enqueue(eval)
 	} else if eval.ShouldBlock() {
+		setDependencyIndex(n.blockedEvals, n.state, eval)
 		n.blockedEvals.Block(eval)
 	} else if eval.Status == structs.EvalStatusComplete &&
-		len(eval.FailedTGAllocs) == 0 {
+		len(eval.FailedTGAllocs) == 0 && eval.BlockedEval == "" {
 		// If we have a successful evaluation for a node, untrack any
-		// blocked evaluation
+		// blocked evaluation, unless the evaluation spawned it
 		n.blockedEvals.Untrack(eval.JobID, eval.Namespace)
 	}
 }
@@ -862,10 +873,12 @@ func (n *nomadFSM) applyAllocClientUpdate(msgType structs.MessageType, buf []byt
 	ws := memdb.NewWatchSet()
 
 	// Updating the allocs with the job id and task group name
+	jobs := make(map[structs.NamespacedID]struct{})
 	for _, alloc := range req.Alloc {
 		if existing, _ := n.state.AllocByID(ws, alloc.ID); existing != nil {
 			alloc.JobID = existing.JobID
 			alloc.TaskGroup = existing.TaskGroup
+			jobs[structs.NewNamespacedID(existing.JobID, existing.Namespace)] = struct{}{}
 		}
 	}
 
@@ -908,6 +921,11 @@ func (n *nomadFSM) applyAllocClientUpdate(msgType structs.MessageType, buf []byt
 		}
 	}
 
+	// Unblock evals for jobs depending on the jobs of the allocations.
+	for job := range jobs {
+		n.blockedEvals.UnblockDependency(job.ID, job.Namespace, index)
+	}
+
 	return nil
 }
 
@@ -1049,6 +1067,7 @@ func (n *nomadFSM) applyDeploymentStatusUpdate(msgType structs.MessageType, buf
 	}
 
 	n.handleUpsertedEval(req.Eval)
+	n.unblockDeploymentDependency(req.DeploymentUpdate.DeploymentID, index)
 	return nil
 }
 
@@ -1084,9 +1103,58 @@ func (n *nomadFSM) applyDeploymentAllocHealth(msgType structs.MessageType, buf [
 	}
 
 	n.handleUpsertedEval(req.Eval)
+	n.unblockDeploymentDependency(req.DeploymentID, index)
 	return nil
 }
 
+// setDependencyIndex records the last index in which the job an evaluation is
+// blocked on changed, so the evaluation isn't left blocked by a change that was
+// made while it was in the scheduler.
+func setDependencyIndex(blocked *BlockedEvals, store *state.StateStore, eval *structs.Evaluation) {
+	if eval.BlockedOnJob == "" {
+		return
+	}
+
+	var index uint64
+	job, err := store.JobByID(nil, eval.Namespace, eval.BlockedOnJob)
+	if err != nil {
+		return
+	}
+	if job != nil {
+		index = job.ModifyIndex
+	} else if index, err = store.Index("jobs"); err != nil {
+		// The job may have been purged while the evaluation was in the
+		// scheduler, which the scheduler fails the evaluation for
+		return
+	}
+	allocs, err := store.AllocsByJob(nil, eval.Namespace, eval.BlockedOnJob, true)
+	if err != nil {
+		return
+	}
+	for _, alloc := range allocs {
+		index = helper.Max(index, alloc.ModifyIndex)
+	}
+	deployment, err := store.LatestDeploymentByJobID(nil, eval.Namespace, eval.BlockedOnJob)
+	if err != nil {
+		return
+	}
+	if deployment != nil {
+		index = helper.Max(index, deployment.ModifyIndex)
+	}
+
+	blocked.SetDependencyIndex(eval.BlockedOnJob, eval.Namespace, index)
+}
+
+// unblockDeploymentDependency unblocks evals for jobs depending on the job of
+// the deployment, as the health of the job may have changed.
+func (n *nomadFSM) unblockDeploymentDependency(deploymentID string, index uint64) {
+	deployment, err := n.state.DeploymentByID(nil, deploymentID)
+	if err != nil || deployment == nil {
+		return
+	}
+	n.blockedEvals.UnblockDependency(deployment.JobID, deployment.Namespace, index)
+}
+
 // applyDeploymentDelete is used to delete a set of deployments
 func (n *nomadFSM) applyDeploymentDelete(buf []byte, index uint64) interface{} {
 	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_deployment_delete"}, time.Now())
@@ -1356,7 +1424,7 @@ func (n *nomadFSM) applyCSIPluginDelete(buf []byte, index uint64) interface{} {
 }
 
 // applyNamespaceUpsert is used to upsert a set of namespaces
-func (n *nomadFSM) applyNamespaceUpsert(buf []byte, index uint64) interface{} {
+func (n *nomadFSM) applyNamespaceUpsert(msgType structs.MessageType, buf []byte, index uint64) interface{} {
 	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_namespace_upsert"}, time.Now())
 	var req structs.NamespaceUpsertRequest
 	if err := structs.Decode(buf, &req); err != nil {
@@ -1378,7 +1446,7 @@ func (n *nomadFSM) applyNamespaceUpsert(buf []byte, index uint64) interface{} {
 		}
 	}
 
-	if err := n.state.UpsertNamespaces(index, req.Namespaces); err != nil {
+	if err := n.state.UpsertNamespaces(msgType, index, req.Namespaces); err != nil {
 		n.logger.Error("UpsertNamespaces failed", "error", err)
 		return err
 	}
@@ -1392,14 +1460,14 @@ func (n *nomadFSM) applyNamespaceUpsert(buf []byte, index uint64) interface{} {
 }
 
 // applyNamespaceDelete is used to delete a set of namespaces
-func (n *nomadFSM) applyNamespaceDelete(buf []byte, index uint64) interface{} {
+func (n *nomadFSM) applyNamespaceDelete(msgType structs.MessageType, buf []byte, index uint64) interface{} {
 	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_namespace_delete"}, time.Now())
 	var req structs.NamespaceDeleteRequest
 	if err := structs.Decode(buf, &req); err != nil {
 		panic(fmt.Errorf("failed to decode request: %v", err))
 	}
 
-	if err := n.state.DeleteNamespaces(index, req.Namespaces); err != nil {
+	if err := n.state.DeleteNamespaces(msgType, index, req.Namespaces); err != nil {
 		n.logger.Error("DeleteNamespaces failed", "error", err)
 	}
 
@@ -1734,6 +1802,16 @@ func (n *nomadFSM) restoreImpl(old io.ReadCloser, filter *FSMFilter) error {
 				return err
 			}
 
+		case VariablesVersionsSnapshot:
+			version := new(structs.VariableEncrypted)
+			if err := dec.Decode(version); err != nil {
+				return err
+			}
+
+			if err := restore.VariablesVersionsRestore(version); err != nil {
+				return err
+			}
+
 		case VariablesQuotaSnapshot:
 			quota := new(structs.VariablesQuota)
 			if err := dec.Decode(quota); err != nil {
@@ -2027,6 +2105,22 @@ func (n *nomadFSM) applyDeleteServiceRegistrationByNodeID(msgType structs.Messag
 	return nil
 }
 
+func (n *nomadFSM) applyUpdateServiceRegistrationChecks(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_service_registration_update_checks"}, time.Now())
+	var req structs.ServiceRegistrationUpdateChecksRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.UpdateServiceRegistrationChecks(
+		msgType, index, req.RequestNamespace(), req.AllocID, req.Checks); err != nil {
+		n.logger.Error("UpdateServiceRegistrationChecks failed", "error", err)
+		return err
+	}
+
+	return nil
+}
+
 func (n *nomadFSM) applyACLRolesUpsert(msgType structs.MessageType, buf []byte, index uint64) interface{} {
 	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_acl_role_upsert"}, time.Now())
 	var req structs.ACLRolesUpsertRequest
@@ -2092,13 +2186,21 @@ func (n *nomadFSM) applyVariableOperation(msgType structs.MessageType, buf []byt
 		[]metrics.Label{{Name: "op", Value: string(req.Op)}})
 	switch req.Op {
 	case structs.VarOpSet:
-		return n.state.VarSet(index, &req)
+		return n.state.VarSet(msgType, index, &req)
 	case structs.VarOpDelete:
-		return n.state.VarDelete(index, &req)
+		return n.state.VarDelete(msgType, index, &req)
 	case structs.VarOpDeleteCAS:
-		return n.state.VarDeleteCAS(index, &req)
+		return n.state.VarDeleteCAS(msgType, index, &req)
 	case structs.VarOpCAS:
-		return n.state.VarSetCAS(index, &req)
+		return n.state.VarSetCAS(msgType, index, &req)
+	case structs.VarOpRollback:
+		return n.state.VarRollback(msgType, index, &req)
+	case structs.VarOpLockAcquire:
+		return n.state.VarLockAcquire(msgType, index, &req)
+	case structs.VarOpLockRelease:
+		return n.state.VarLockRelease(msgType, index, &req)
+	case structs.VarOpRekey:
+		return n.state.VarRekey(msgType, index, &req)
 	default:
 		err := fmt.Errorf("Invalid variable operation '%s'", req.Op)
 		n.logger.Warn("Invalid variable operation", "operation", req.Op)
@@ -2114,7 +2216,7 @@ func (n *nomadFSM) applyRootKeyMetaUpsert(msgType structs.MessageType, buf []byt
 		panic(fmt.Errorf("failed to decode request: %v", err))
 	}
 
-	if err := n.state.UpsertRootKeyMeta(index, req.RootKeyMeta, req.Rekey); err != nil {
+	if err := n.state.UpsertRootKeyMeta(msgType, index, req.RootKeyMeta, req.Rekey); err != nil {
 		n.logger.Error("UpsertRootKeyMeta failed", "error", err)
 		return err
 	}
@@ -2130,7 +2232,7 @@ func (n *nomadFSM) applyRootKeyMetaDelete(msgType structs.MessageType, buf []byt
 		panic(fmt.Errorf("failed to decode request: %v", err))
 	}
 
-	if err := n.state.DeleteRootKeyMeta(index, req.KeyID); err != nil {
+	if err := n.state.DeleteRootKeyMeta(msgType, index, req.KeyID); err != nil {
 		n.logger.Error("DeleteRootKeyMeta failed", "error", err)
 		return err
 	}
@@ -2250,6 +2352,10 @@ func (s *nomadSnapshot) Persist(sink raft.SnapshotSink) error {
 		sink.Cancel()
 		return err
 	}
+	if err := s.persistVariablesVersions(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
 	if err := s.persistVariablesQuotas(sink, encoder); err != nil {
 		sink.Cancel()
 		return err
@@ -2843,6 +2949,29 @@ func (s *nomadSnapshot) persistVariables(sink raft.SnapshotSink,
 	return nil
 }
 
+func (s *nomadSnapshot) persistVariablesVersions(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+
+	ws := memdb.NewWatchSet()
+	versions, err := s.snap.VariablesVersions(ws)
+	if err != nil {
+		return err
+	}
+
+	for {
+		raw := versions.Next()
+		if raw == nil {
+			break
+		}
+		version := raw.(*structs.VariableEncrypted)
+		sink.Write([]byte{byte(VariablesVersionsSnapshot)})
+		if err := encoder.Encode(version); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (s *nomadSnapshot) persistVariablesQuotas(sink raft.SnapshotSink,
 	encoder *codec.Encoder) error {
 
@@ -1030,6 +1030,85 @@ func TestFSM_UpdateEval_Blocked(t *testing.T) {
 	}
 }
 
+func TestFSM_UpdateEval_BlockedOnJob_MissedUnblock(t *testing.T) {
+	ci.Parallel(t)
+	require := require.New(t)
+
+	fsm := testFSM(t)
+	fsm.evalBroker.SetEnabled(true)
+	fsm.blockedEvals.SetEnabled(true)
+
+	// The job the eval depends on changes while the eval is in the scheduler,
+	// before any eval is blocked on it
+	dep := mock.Job()
+	require.NoError(fsm.State().UpsertJob(structs.MsgTypeTestSetup, 10, dep))
+
+	eval := mock.Eval()
+	eval.Status = structs.EvalStatusBlocked
+	eval.BlockedOnJob = dep.ID
+	eval.SnapshotIndex = 5
+	req := structs.EvalUpdateRequest{
+		Evals: []*structs.Evaluation{eval},
+	}
+	buf, err := structs.Encode(structs.EvalUpdateRequestType, req)
+	require.NoError(err)
+	require.Nil(fsm.Apply(makeLog(buf)))
+
+	// Verify the eval is unblocked rather than waiting on the missed change
+	testutil.WaitForResult(func() (bool, error) {
+		if bStats := fsm.blockedEvals.Stats(); bStats.TotalBlocked != 0 {
+			return false, fmt.Errorf("bad: %#v", bStats)
+		}
+		if stats := fsm.evalBroker.Stats(); stats.TotalReady != 1 {
+			return false, fmt.Errorf("bad: %#v", stats)
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("err: %s", err)
+	})
+}
+
+func TestFSM_DeregisterJob_UnblockDependency(t *testing.T) {
+	ci.Parallel(t)
+	require := require.New(t)
+
+	fsm := testFSM(t)
+	fsm.evalBroker.SetEnabled(true)
+	fsm.blockedEvals.SetEnabled(true)
+
+	dep := mock.Job()
+	require.NoError(fsm.State().UpsertJob(structs.MsgTypeTestSetup, 10, dep))
+
+	// Block an eval on the job
+	eval := mock.Eval()
+	eval.Status = structs.EvalStatusBlocked
+	eval.BlockedOnJob = dep.ID
+	eval.SnapshotIndex = 10
+	fsm.blockedEvals.Block(eval)
+
+	// Purge the job
+	req := structs.JobDeregisterRequest{
+		JobID: dep.ID,
+		Purge: true,
+		WriteRequest: structs.WriteRequest{
+			Namespace: dep.Namespace,
+		},
+	}
+	buf, err := structs.Encode(structs.JobDeregisterRequestType, req)
+	require.NoError(err)
+	require.Nil(fsm.Apply(makeLog(buf)))
+
+	// Verify the eval was unblocked so the scheduler can fail it
+	testutil.WaitForResult(func() (bool, error) {
+		if bStats := fsm.blockedEvals.Stats(); bStats.TotalBlocked != 0 {
+			return false, fmt.Errorf("bad: %#v", bStats)
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("err: %s", err)
+	})
+}
+
 func TestFSM_UpdateEval_Untrack(t *testing.T) {
 	ci.Parallel(t)
 	fsm := testFSM(t)
@@ -3281,7 +3360,7 @@ func TestFSM_DeleteNamespaces(t *testing.T) {
 
 	ns1 := mock.Namespace()
 	ns2 := mock.Namespace()
-	assert.Nil(fsm.State().UpsertNamespaces(1000, []*structs.Namespace{ns1, ns2}))
+	assert.Nil(fsm.State().UpsertNamespaces(structs.MsgTypeTestSetup, 1000, []*structs.Namespace{ns1, ns2}))
 
 	req := structs.NamespaceDeleteRequest{
 		Namespaces: []string{ns1.Name, ns2.Name},
@@ -3308,7 +3387,7 @@ func TestFSM_SnapshotRestore_Namespaces(t *testing.T) {
 	state := fsm.State()
 	ns1 := mock.Namespace()
 	ns2 := mock.Namespace()
-	state.UpsertNamespaces(1000, []*structs.Namespace{ns1, ns2})
+	state.UpsertNamespaces(structs.MsgTypeTestSetup, 1000, []*structs.Namespace{ns1, ns2})
 
 	// Verify the contents
 	fsm2 := testSnapshotRestore(t, fsm)
@@ -3417,7 +3496,7 @@ func TestFSM_SnapshotRestore_Variables(t *testing.T) {
 	svs := msvs.List()
 
 	for _, sv := range svs {
-		setResp := testState.VarSet(10, &structs.VarApplyStateRequest{
+		setResp := testState.VarSet(structs.MsgTypeTestSetup, 10, &structs.VarApplyStateRequest{
 			Op:  structs.VarOpSet,
 			Var: sv,
 		})
@@ -3457,6 +3536,40 @@ func TestFSM_SnapshotRestore_Variables(t *testing.T) {
 	require.ElementsMatch(t, restoredSVs, svs)
 }
 
+func TestFSM_SnapshotRestore_VariablesVersions(t *testing.T) {
+	ci.Parallel(t)
+
+	// Create our initial FSM which will be snapshotted.
+	fsm := testFSM(t)
+	testState := fsm.State()
+
+	// Write the same variable several times, retaining its prior versions.
+	sv := mock.VariableEncrypted()
+	for i := uint64(10); i <= 30; i += 10 {
+		v := sv.Copy()
+		v.Data = []byte(fmt.Sprint(i))
+		setResp := testState.VarSet(structs.MsgTypeTestSetup, i, &structs.VarApplyStateRequest{
+			Op:           structs.VarOpSet,
+			Var:          &v,
+			VersionLimit: 5,
+		})
+		require.NoError(t, setResp.Error)
+	}
+
+	versions, err := testState.GetVariableVersions(memdb.NewWatchSet(), sv.Namespace, sv.Path)
+	require.NoError(t, err)
+	require.Len(t, versions, 2)
+
+	// Perform a snapshot restore.
+	restoredFSM := testSnapshotRestore(t, fsm)
+	restoredState := restoredFSM.State()
+
+	// Ensure the prior versions were restored.
+	restoredVersions, err := restoredState.GetVariableVersions(memdb.NewWatchSet(), sv.Namespace, sv.Path)
+	require.NoError(t, err)
+	require.Equal(t, versions, restoredVersions)
+}
+
 func TestFSM_ApplyACLRolesUpsert(t *testing.T) {
 	ci.Parallel(t)
 	fsm := testFSM(t)
@@ -750,6 +750,7 @@ func (j *Job) Evaluate(args *structs.JobEvaluateRequest, reply *structs.JobRegis
 		JobID:          job.ID,
 		JobModifyIndex: job.ModifyIndex,
 		Status:         structs.EvalStatusPending,
+		ExplainScores:  args.EvalOptions.ExplainScores,
 		CreateTime:     now,
 		ModifyTime:     now,
 	}
@@ -1708,6 +1709,7 @@ func (j *Job) Plan(args *structs.JobPlanRequest, reply *structs.JobPlanResponse)
 		JobModifyIndex: updatedIndex,
 		Status:         structs.EvalStatusPending,
 		AnnotatePlan:   true,
+		ExplainScores:  args.Explain,
 		// Timestamps are added for consistency but this eval is never persisted
 		CreateTime: now,
 		ModifyTime: now,
@@ -1722,6 +1724,17 @@ func (j *Job) Plan(args *structs.JobPlanRequest, reply *structs.JobPlanResponse)
 		State: &snap.StateStore,
 	}
 
+	// Record the existing allocations of the job so that in-place updates
+	// can be told apart from the placements when explaining the scores, as
+	// the planner applies the plan to the snapshot.
+	var existingAllocs []*structs.Allocation
+	if args.Explain {
+		existingAllocs, err = snap.AllocsByJob(nil, args.RequestNamespace(), args.Job.ID, true)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Create the scheduler and run it
 	sched, err := scheduler.NewScheduler(eval.Type, j.logger, j.srv.workersEventCh, snap, planner)
 	if err != nil {
@@ -1763,6 +1776,28 @@ func (j *Job) Plan(args *structs.JobPlanRequest, reply *structs.JobPlanResponse)
 		}
 	}
 
+	// Warn if the preemptions would currently exceed the preemption budget
+	if annotations != nil && len(annotations.PreemptedAllocs) > 0 {
+		_, schedConfig, err := snap.SchedulerConfig()
+		if err != nil {
+			return err
+		}
+		if schedConfig != nil && schedConfig.PreemptionConfig.MaxPreemptionsPerMinute > 0 {
+			remaining := j.srv.planner.preemptionBudget.Remaining(
+				schedConfig.PreemptionConfig.MaxPreemptionsPerMinute, time.Now())
+			if len(annotations.PreemptedAllocs) > remaining {
+				warnings = append(warnings, fmt.Errorf(
+					"%d allocations would be preempted but the preemption budget only allows %d more within the next minute; placements requiring preemption will be delayed",
+					len(annotations.PreemptedAllocs), remaining))
+				reply.Warnings = structs.MergeMultierrorWarnings(warnings...)
+			}
+		}
+	}
+
+	if args.Explain {
+		reply.ScoreExplanations = planScoreExplanations(planner.Plans[0], existingAllocs)
+	}
+
 	reply.FailedTGAllocs = updatedEval.FailedTGAllocs
 	reply.JobModifyIndex = index
 	reply.Annotations = annotations
@@ -1771,6 +1806,36 @@ func (j *Job) Plan(args *structs.JobPlanRequest, reply *structs.JobPlanResponse)
 	return nil
 }
 
+// planScoreExplanations returns the explanation of the scores of each
+// placement of the plan, sorted by allocation name. Updates of the existing
+// allocations are skipped as their node wasn't selected by scoring.
+func planScoreExplanations(plan *structs.Plan, existing []*structs.Allocation) []*structs.PlacementExplanation {
+	existingIDs := make(map[string]struct{}, len(existing))
+	for _, alloc := range existing {
+		existingIDs[alloc.ID] = struct{}{}
+	}
+
+	var explanations []*structs.PlacementExplanation
+	for _, allocs := range plan.NodeAllocation {
+		for _, alloc := range allocs {
+			if _, ok := existingIDs[alloc.ID]; ok || alloc.Metrics == nil {
+				continue
+			}
+			explanations = append(explanations, &structs.PlacementExplanation{
+				AllocName: alloc.Name,
+				TaskGroup: alloc.TaskGroup,
+				NodeID:    alloc.NodeID,
+				Scores:    alloc.Metrics.ScoreExplanation,
+			})
+		}
+	}
+
+	sort.Slice(explanations, func(i, j int) bool {
+		return explanations[i].AllocName < explanations[j].AllocName
+	})
+	return explanations
+}
+
 // validateJobUpdate ensures updates to a job are valid.
 func validateJobUpdate(old, new *structs.Job) error {
 	// Validate Dispatch not set on new Jobs
@@ -2215,7 +2215,7 @@ func TestJobEndpoint_Register_ACL_Namespace(t *testing.T) {
 	// Upsert namespace
 	ns := mock.Namespace()
 	ns.Name = "test"
-	err = s1.fsm.State().UpsertNamespaces(1000, []*structs.Namespace{ns})
+	err = s1.fsm.State().UpsertNamespaces(structs.MsgTypeTestSetup, 1000, []*structs.Namespace{ns})
 	assert.Nil(err)
 
 	// Create the register request
@@ -2950,7 +2950,8 @@ func TestJobEndpoint_Evaluate(t *testing.T) {
 
 	// Force a re-evaluation
 	reEval := &structs.JobEvaluateRequest{
-		JobID: job.ID,
+		JobID:       job.ID,
+		EvalOptions: structs.EvalOptions{ExplainScores: true},
 		WriteRequest: structs.WriteRequest{
 			Region:    "global",
 			Namespace: job.Namespace,
@@ -3003,6 +3004,9 @@ func TestJobEndpoint_Evaluate(t *testing.T) {
 	if eval.ModifyTime == 0 {
 		t.Fatalf("eval ModifyTime is unset: %#v", eval)
 	}
+	if !eval.ExplainScores {
+		t.Fatalf("eval ExplainScores is unset: %#v", eval)
+	}
 }
 
 func TestJobEndpoint_ForceRescheduleEvaluate(t *testing.T) {
@@ -5174,7 +5178,7 @@ func TestJobEndpoint_ListJobs_PaginationFiltering(t *testing.T) {
 	}
 
 	state := s1.fsm.State()
-	require.NoError(t, state.UpsertNamespaces(999, []*structs.Namespace{{Name: "non-default"}, {Name: "other"}}))
+	require.NoError(t, state.UpsertNamespaces(structs.MsgTypeTestSetup, 999, []*structs.Namespace{{Name: "non-default"}, {Name: "other"}}))
 
 	for i, m := range mocks {
 		if m.name == "" {
@@ -6124,6 +6128,61 @@ func TestJobEndpoint_Plan_NoDiff(t *testing.T) {
 	}
 }
 
+func TestJobEndpoint_Plan_Explain(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Register some nodes
+	for i := 0; i < 2; i++ {
+		nodeReq := &structs.NodeRegisterRequest{
+			Node:         mock.Node(),
+			WriteRequest: structs.WriteRequest{Region: "global"},
+		}
+		var nodeResp structs.NodeUpdateResponse
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "Node.Register", nodeReq, &nodeResp))
+	}
+
+	job := mock.Job()
+	job.TaskGroups[0].Count = 2
+
+	// Plan without and then with explanations
+	for _, explain := range []bool{false, true} {
+		planReq := &structs.JobPlanRequest{
+			Job:     job,
+			Explain: explain,
+			WriteRequest: structs.WriteRequest{
+				Region:    "global",
+				Namespace: job.Namespace,
+			},
+		}
+		var planResp structs.JobPlanResponse
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.Plan", planReq, &planResp))
+		require.Empty(t, planResp.FailedTGAllocs)
+
+		if !explain {
+			require.Empty(t, planResp.ScoreExplanations)
+			continue
+		}
+
+		require.Len(t, planResp.ScoreExplanations, 2)
+		for i, explanation := range planResp.ScoreExplanations {
+			require.Equal(t, fmt.Sprintf("%s.web[%d]", job.ID, i), explanation.AllocName)
+			require.Equal(t, "web", explanation.TaskGroup)
+			require.NotEmpty(t, explanation.NodeID)
+			require.NotEmpty(t, explanation.Scores)
+			for _, scoreMeta := range explanation.Scores {
+				require.Contains(t, scoreMeta.Scores, "binpack")
+			}
+		}
+	}
+}
+
 // TestJobEndpoint_Plan_Scaling asserts that the plan endpoint handles
 // jobs with scaling stanza
 func TestJobEndpoint_Plan_Scaling(t *testing.T) {
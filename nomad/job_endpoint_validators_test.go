@@ -98,7 +98,7 @@ func TestJobNamespaceConstraintCheckHook_validate(t *testing.T) {
 		EnabledTaskDrivers:  []string{"docker", "qemu"},
 		DisabledTaskDrivers: []string{"exec", "raw_exec"},
 	}
-	s1.fsm.State().UpsertNamespaces(1000, []*structs.Namespace{ns})
+	s1.fsm.State().UpsertNamespaces(structs.MsgTypeTestSetup, 1000, []*structs.Namespace{ns})
 
 	hook := jobNamespaceConstraintCheckHook{srv: s1}
 	job := mock.LifecycleJob()
@@ -131,6 +131,13 @@ func (k *Keyring) List(args *structs.KeyringListRootKeyMetaRequest, reply *struc
 					break
 				}
 				keyMeta := raw.(*structs.RootKeyMeta)
+				if keyMeta.Rekeying() {
+					keyMeta = keyMeta.Copy()
+					keyMeta.RekeyRemaining, err = rekeyRemaining(snap, keyMeta.KeyID)
+					if err != nil {
+						return err
+					}
+				}
 				keys = append(keys, keyMeta)
 			}
 			reply.Keys = keys
@@ -320,3 +327,26 @@ func (k *Keyring) Delete(args *structs.KeyringDeleteRootKeyRequest, reply *struc
 	reply.Index = index
 	return nil
 }
+
+// rekeyRemaining returns the number of variables and retained prior versions
+// of variables that are still encrypted with the given key.
+func rekeyRemaining(snap *state.StateSnapshot, keyID string) (int, error) {
+	var remaining int
+
+	iter, err := snap.GetVariablesByKeyID(nil, keyID)
+	if err != nil {
+		return 0, err
+	}
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		remaining++
+	}
+
+	iter, err = snap.GetVariablesVersionsByKeyID(nil, keyID)
+	if err != nil {
+		return 0, err
+	}
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		remaining++
+	}
+	return remaining, nil
+}
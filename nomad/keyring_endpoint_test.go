@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/testutil"
 )
@@ -291,4 +292,36 @@ func TestKeyringEndpoint_Rotate(t *testing.T) {
 
 	gotKey := getResp.Key
 	require.Len(t, gotKey.Key, 32)
+
+	// A full rotation reports the variables still to be rekeyed
+
+	varReq := &structs.VariablesApplyRequest{
+		Op:  structs.VarOpSet,
+		Var: mock.Variable(),
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: rootToken.SecretID,
+		},
+	}
+	var varResp structs.VariablesApplyResponse
+	err = msgpackrpc.CallWithCodec(codec, structs.VariablesApplyRPCMethod, varReq, &varResp)
+	require.NoError(t, err)
+
+	rotateReq.Full = true
+	err = msgpackrpc.CallWithCodec(codec, "Keyring.Rotate", rotateReq, &rotateResp)
+	require.NoError(t, err)
+
+	listReq.AuthToken = rootToken.SecretID
+	err = msgpackrpc.CallWithCodec(codec, "Keyring.List", listReq, &listResp)
+	require.NoError(t, err)
+	for _, keyMeta := range listResp.Keys {
+		switch keyMeta.KeyID {
+		case newID:
+			require.True(t, keyMeta.Rekeying())
+			require.Equal(t, 1, keyMeta.RekeyRemaining)
+		case rotateResp.Key.KeyID:
+			require.True(t, keyMeta.Active())
+			require.Zero(t, keyMeta.RekeyRemaining)
+		}
+	}
 }
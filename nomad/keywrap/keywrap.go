@@ -0,0 +1,66 @@
+// Package keywrap wraps the root keys persisted to a server's local keystore
+// with a key encryption key held outside of the keystore.
+package keywrap
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/nomad/nomad/structs/config"
+)
+
+// Wrapper wraps and unwraps key material with a key encryption key.
+type Wrapper interface {
+	// Provider returns the name of the key wrapping provider.
+	Provider() string
+
+	// Wrap encrypts the key material with the key encryption key.
+	Wrap(key []byte) (*WrappedKey, error)
+
+	// Unwrap decrypts key material previously wrapped by Wrap.
+	Unwrap(wrapped *WrappedKey) ([]byte, error)
+}
+
+// WrappedKey is key material encrypted by a key encryption key. It is safe to
+// persist to disk.
+type WrappedKey struct {
+	// Provider is the name of the provider that wrapped the key.
+	Provider string
+
+	// KeyID identifies the key encryption key used to wrap the key, so that
+	// a key wrapped by another key encryption key is detected on unwrap.
+	KeyID string
+
+	// Ciphertext is the wrapped key material.
+	Ciphertext []byte
+}
+
+// New returns the Wrapper for the provider configured by cfg.
+func New(cfg *config.KeystoreWrapperConfig) (Wrapper, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	switch cfg.Provider {
+	case config.KeystoreWrapperProviderFile:
+		return NewFileWrapper(cfg.KeyFile)
+	case config.KeystoreWrapperProviderTransit:
+		return NewTransitWrapper(cfg), nil
+	default:
+		return nil, fmt.Errorf("unknown key wrapping provider %q", cfg.Provider)
+	}
+}
+
+// checkWrappedKey returns an error if the wrapped key was not wrapped by the
+// given provider and key encryption key.
+func checkWrappedKey(wrapped *WrappedKey, provider, keyID string) error {
+	if wrapped == nil {
+		return fmt.Errorf("missing wrapped key")
+	}
+	if wrapped.Provider != provider {
+		return fmt.Errorf("key was wrapped by the %q provider, not %q", wrapped.Provider, provider)
+	}
+	if wrapped.KeyID != keyID {
+		return fmt.Errorf("key was wrapped by key encryption key %q, not %q", wrapped.KeyID, keyID)
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package keywrap
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+)
+
+// writeKEK writes a new base64 encoded key encryption key to a file and
+// returns its path
+func writeKEK(t *testing.T) string {
+	t.Helper()
+	kek := make([]byte, 32)
+	copy(kek, uuid.Generate())
+	path := filepath.Join(t.TempDir(), "kek")
+	require.NoError(t, os.WriteFile(path,
+		[]byte(base64.StdEncoding.EncodeToString(kek)+"\n"), 0600))
+	return path
+}
+
+func TestFileWrapper(t *testing.T) {
+	ci.Parallel(t)
+
+	wrapper, err := New(&config.KeystoreWrapperConfig{
+		Provider: config.KeystoreWrapperProviderFile,
+		KeyFile:  writeKEK(t),
+	})
+	require.NoError(t, err)
+
+	key := []byte("01234567890123456789012345678901")
+	wrapped, err := wrapper.Wrap(key)
+	require.NoError(t, err)
+	require.Equal(t, config.KeystoreWrapperProviderFile, wrapped.Provider)
+	require.NotContains(t, string(wrapped.Ciphertext), string(key))
+
+	got, err := wrapper.Unwrap(wrapped)
+	require.NoError(t, err)
+	require.Equal(t, key, got)
+
+	// a key wrapped by another key encryption key is detected
+	other, err := NewFileWrapper(writeKEK(t))
+	require.NoError(t, err)
+	_, err = other.Unwrap(wrapped)
+	require.ErrorContains(t, err, "wrapped by key encryption key")
+
+	// tampered key material fails to unwrap
+	wrapped.Ciphertext[len(wrapped.Ciphertext)-1] ^= 0xff
+	_, err = wrapper.Unwrap(wrapped)
+	require.ErrorContains(t, err, "could not unwrap key")
+
+	// the key encryption key must be 256 bits
+	short := filepath.Join(t.TempDir(), "short")
+	require.NoError(t, os.WriteFile(short,
+		[]byte(base64.StdEncoding.EncodeToString([]byte("too short"))), 0600))
+	_, err = NewFileWrapper(short)
+	require.EqualError(t, err, "key encryption key must be 32 bytes, got 9")
+}
+
+func TestTransitWrapper(t *testing.T) {
+	ci.Parallel(t)
+
+	// fake transit secrets engine which "encrypts" by prefixing the
+	// plaintext, enough to check the requests made by the wrapper
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "secret" {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"errors":["permission denied"]}`))
+			return
+		}
+		require.Equal(t, "ns1", r.Header.Get("X-Vault-Namespace"))
+
+		var in map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&in))
+
+		var out map[string]string
+		switch r.URL.Path {
+		case "/v1/kms/encrypt/nomad":
+			out = map[string]string{"ciphertext": "vault:v1:" + in["plaintext"]}
+		case "/v1/kms/decrypt/nomad":
+			out = map[string]string{"plaintext": strings.TrimPrefix(in["ciphertext"], "vault:v1:")}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": out})
+	}))
+	defer ts.Close()
+
+	cfg := &config.KeystoreWrapperConfig{
+		Provider:  config.KeystoreWrapperProviderTransit,
+		Address:   ts.URL + "/",
+		Token:     "secret",
+		MountPath: "/kms/",
+		KeyName:   "nomad",
+		Namespace: "ns1",
+	}
+	wrapper, err := New(cfg)
+	require.NoError(t, err)
+
+	key := []byte("01234567890123456789012345678901")
+	wrapped, err := wrapper.Wrap(key)
+	require.NoError(t, err)
+	require.Equal(t, "nomad", wrapped.KeyID)
+	require.True(t, strings.HasPrefix(string(wrapped.Ciphertext), "vault:v1:"))
+
+	got, err := wrapper.Unwrap(wrapped)
+	require.NoError(t, err)
+	require.Equal(t, key, got)
+
+	// errors from the encryption service are returned
+	badCfg := cfg.Copy()
+	badCfg.Token = "wrong"
+	_, err = NewTransitWrapper(badCfg).Wrap(key)
+	require.ErrorContains(t, err, "unexpected response code 403")
+}
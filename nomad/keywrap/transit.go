@@ -0,0 +1,144 @@
+package keywrap
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-cleanhttp"
+
+	"github.com/hashicorp/nomad/nomad/structs/config"
+)
+
+const (
+	// transitDefaultMountPath is the default path the transit secrets engine
+	// is mounted at.
+	transitDefaultMountPath = "transit"
+
+	// transitRequestTimeout is the timeout of each request to the encryption
+	// service.
+	transitRequestTimeout = 10 * time.Second
+)
+
+// transitWrapper wraps keys with a named key held by an encryption service
+// compatible with the Vault transit secrets engine HTTP API. The key
+// encryption key never leaves the encryption service.
+type transitWrapper struct {
+	client    *http.Client
+	address   string
+	token     string
+	namespace string
+	mountPath string
+	keyName   string
+}
+
+// NewTransitWrapper returns a Wrapper using the transit compatible
+// encryption service configured by cfg.
+func NewTransitWrapper(cfg *config.KeystoreWrapperConfig) Wrapper {
+	client := cleanhttp.DefaultClient()
+	client.Timeout = transitRequestTimeout
+
+	mountPath := strings.Trim(cfg.MountPath, "/")
+	if mountPath == "" {
+		mountPath = transitDefaultMountPath
+	}
+
+	return &transitWrapper{
+		client:    client,
+		address:   strings.TrimSuffix(cfg.Address, "/"),
+		token:     cfg.Token,
+		namespace: cfg.Namespace,
+		mountPath: mountPath,
+		keyName:   cfg.KeyName,
+	}
+}
+
+func (w *transitWrapper) Provider() string {
+	return config.KeystoreWrapperProviderTransit
+}
+
+func (w *transitWrapper) Wrap(key []byte) (*WrappedKey, error) {
+	var resp struct {
+		Data struct {
+			Ciphertext string `json:"ciphertext"`
+		} `json:"data"`
+	}
+	err := w.do("encrypt", map[string]string{
+		"plaintext": base64.StdEncoding.EncodeToString(key),
+	}, &resp)
+	if err != nil {
+		return nil, fmt.Errorf("could not wrap key: %v", err)
+	}
+	if resp.Data.Ciphertext == "" {
+		return nil, fmt.Errorf("could not wrap key: empty ciphertext in response")
+	}
+
+	return &WrappedKey{
+		Provider:   w.Provider(),
+		KeyID:      w.keyName,
+		Ciphertext: []byte(resp.Data.Ciphertext),
+	}, nil
+}
+
+func (w *transitWrapper) Unwrap(wrapped *WrappedKey) ([]byte, error) {
+	if err := checkWrappedKey(wrapped, w.Provider(), w.keyName); err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Data struct {
+			Plaintext string `json:"plaintext"`
+		} `json:"data"`
+	}
+	err := w.do("decrypt", map[string]string{
+		"ciphertext": string(wrapped.Ciphertext),
+	}, &resp)
+	if err != nil {
+		return nil, fmt.Errorf("could not unwrap key: %v", err)
+	}
+
+	key, err := base64.StdEncoding.DecodeString(resp.Data.Plaintext)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode unwrapped key: %v", err)
+	}
+	return key, nil
+}
+
+// do sends a request to the given operation of the transit key, and decodes
+// the response into out.
+func (w *transitWrapper) do(op string, in interface{}, out interface{}) error {
+	body, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/v1/%s/%s/%s", w.address, w.mountPath, op, w.keyName)
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if w.token != "" {
+		req.Header.Set("X-Vault-Token", w.token)
+	}
+	if w.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", w.namespace)
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("unexpected response code %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
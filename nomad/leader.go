@@ -381,6 +381,13 @@ func (s *Server) establishLeadership(stopCh chan struct{}) error {
 		return err
 	}
 
+	// Setup the variable lock timers, for the same reasons as the heartbeat
+	// timers above.
+	if err := s.initializeLockTTLTimers(); err != nil {
+		s.logger.Error("variable lock timer setup failed", "error", err)
+		return err
+	}
+
 	// If ACLs are enabled, the leader needs to start a number of long-lived
 	// routines. Exactly which routines, depends on whether this leader is
 	// running within the authoritative region or not.
@@ -590,6 +597,7 @@ func (s *Server) restoreEvals() error {
 		if eval.ShouldEnqueue() {
 			s.evalBroker.Enqueue(eval)
 		} else if eval.ShouldBlock() {
+			setDependencyIndex(s.blockedEvals, s.fsm.State(), eval)
 			s.blockedEvals.Block(eval)
 		}
 	}
@@ -1208,6 +1216,9 @@ func (s *Server) revokeLeadership() error {
 		return err
 	}
 
+	// Clear the variable lock timers for the same reason.
+	s.clearAllLockTTLTimers()
+
 	// Unpause our worker if we paused previously
 	s.handlePausableWorkers(false)
 
@@ -1658,7 +1658,7 @@ func TestLeader_ReplicateNamespaces(t *testing.T) {
 
 	// Write a namespace to the authoritative region
 	ns1 := mock.Namespace()
-	assert.Nil(s1.State().UpsertNamespaces(100, []*structs.Namespace{ns1}))
+	assert.Nil(s1.State().UpsertNamespaces(structs.MsgTypeTestSetup, 100, []*structs.Namespace{ns1}))
 
 	// Wait for the namespace to replicate
 	testutil.WaitForResult(func() (bool, error) {
@@ -1670,7 +1670,7 @@ func TestLeader_ReplicateNamespaces(t *testing.T) {
 	})
 
 	// Delete the namespace at the authoritative region
-	assert.Nil(s1.State().DeleteNamespaces(200, []string{ns1.Name}))
+	assert.Nil(s1.State().DeleteNamespaces(structs.MsgTypeTestSetup, 200, []string{ns1.Name}))
 
 	// Wait for the namespace deletion to replicate
 	testutil.WaitForResult(func() (bool, error) {
@@ -1691,7 +1691,7 @@ func TestLeader_DiffNamespaces(t *testing.T) {
 	ns1 := mock.Namespace()
 	ns2 := mock.Namespace()
 	ns3 := mock.Namespace()
-	assert.Nil(t, state.UpsertNamespaces(100, []*structs.Namespace{ns1, ns2, ns3}))
+	assert.Nil(t, state.UpsertNamespaces(structs.MsgTypeTestSetup, 100, []*structs.Namespace{ns1, ns2, ns3}))
 
 	// Simulate a remote list
 	rns2 := ns2.Copy()
@@ -25,7 +25,7 @@ func TestNamespaceEndpoint_GetNamespace(t *testing.T) {
 
 	// Create the register request
 	ns := mock.Namespace()
-	s1.fsm.State().UpsertNamespaces(1000, []*structs.Namespace{ns})
+	s1.fsm.State().UpsertNamespaces(structs.MsgTypeTestSetup, 1000, []*structs.Namespace{ns})
 
 	// Lookup the namespace
 	get := &structs.NamespaceSpecificRequest{
@@ -56,7 +56,7 @@ func TestNamespaceEndpoint_GetNamespace_ACL(t *testing.T) {
 	ns1 := mock.Namespace()
 	ns2 := mock.Namespace()
 	state := s1.fsm.State()
-	s1.fsm.State().UpsertNamespaces(1000, []*structs.Namespace{ns1, ns2})
+	s1.fsm.State().UpsertNamespaces(structs.MsgTypeTestSetup, 1000, []*structs.Namespace{ns1, ns2})
 
 	// Create the policy and tokens
 	validToken := mock.CreatePolicyAndToken(t, state, 1002, "test-valid",
@@ -120,12 +120,12 @@ func TestNamespaceEndpoint_GetNamespace_Blocking(t *testing.T) {
 
 	// First create an namespace
 	time.AfterFunc(100*time.Millisecond, func() {
-		assert.Nil(state.UpsertNamespaces(100, []*structs.Namespace{ns1}))
+		assert.Nil(state.UpsertNamespaces(structs.MsgTypeTestSetup, 100, []*structs.Namespace{ns1}))
 	})
 
 	// Upsert the namespace we are watching later
 	time.AfterFunc(200*time.Millisecond, func() {
-		assert.Nil(state.UpsertNamespaces(200, []*structs.Namespace{ns2}))
+		assert.Nil(state.UpsertNamespaces(structs.MsgTypeTestSetup, 200, []*structs.Namespace{ns2}))
 	})
 
 	// Lookup the namespace
@@ -149,7 +149,7 @@ func TestNamespaceEndpoint_GetNamespace_Blocking(t *testing.T) {
 
 	// Namespace delete triggers watches
 	time.AfterFunc(100*time.Millisecond, func() {
-		assert.Nil(state.DeleteNamespaces(300, []string{ns2.Name}))
+		assert.Nil(state.DeleteNamespaces(structs.MsgTypeTestSetup, 300, []string{ns2.Name}))
 	})
 
 	req.QueryOptions.MinQueryIndex = 250
@@ -175,7 +175,7 @@ func TestNamespaceEndpoint_GetNamespaces(t *testing.T) {
 	// Create the register request
 	ns1 := mock.Namespace()
 	ns2 := mock.Namespace()
-	s1.fsm.State().UpsertNamespaces(1000, []*structs.Namespace{ns1, ns2})
+	s1.fsm.State().UpsertNamespaces(structs.MsgTypeTestSetup, 1000, []*structs.Namespace{ns1, ns2})
 
 	// Lookup the namespace
 	get := &structs.NamespaceSetRequest{
@@ -202,7 +202,7 @@ func TestNamespaceEndpoint_GetNamespaces_ACL(t *testing.T) {
 	ns1 := mock.Namespace()
 	ns2 := mock.Namespace()
 	state := s1.fsm.State()
-	state.UpsertNamespaces(1000, []*structs.Namespace{ns1, ns2})
+	state.UpsertNamespaces(structs.MsgTypeTestSetup, 1000, []*structs.Namespace{ns1, ns2})
 
 	// Create the policy and tokens
 	validToken := mock.CreatePolicyAndToken(t, state, 1002, "test-valid",
@@ -254,12 +254,12 @@ func TestNamespaceEndpoint_GetNamespaces_Blocking(t *testing.T) {
 
 	// First create an namespace
 	time.AfterFunc(100*time.Millisecond, func() {
-		assert.Nil(state.UpsertNamespaces(100, []*structs.Namespace{ns1}))
+		assert.Nil(state.UpsertNamespaces(structs.MsgTypeTestSetup, 100, []*structs.Namespace{ns1}))
 	})
 
 	// Upsert the namespace we are watching later
 	time.AfterFunc(200*time.Millisecond, func() {
-		assert.Nil(state.UpsertNamespaces(200, []*structs.Namespace{ns2}))
+		assert.Nil(state.UpsertNamespaces(structs.MsgTypeTestSetup, 200, []*structs.Namespace{ns2}))
 	})
 
 	// Lookup the namespace
@@ -283,7 +283,7 @@ func TestNamespaceEndpoint_GetNamespaces_Blocking(t *testing.T) {
 
 	// Namespace delete triggers watches
 	time.AfterFunc(100*time.Millisecond, func() {
-		assert.Nil(state.DeleteNamespaces(300, []string{ns2.Name}))
+		assert.Nil(state.DeleteNamespaces(structs.MsgTypeTestSetup, 300, []string{ns2.Name}))
 	})
 
 	req.QueryOptions.MinQueryIndex = 250
@@ -312,7 +312,7 @@ func TestNamespaceEndpoint_List(t *testing.T) {
 
 	ns1.Name = "aaaaaaaa-3350-4b4b-d185-0e1992ed43e9"
 	ns2.Name = "aaaabbbb-3350-4b4b-d185-0e1992ed43e9"
-	assert.Nil(s1.fsm.State().UpsertNamespaces(1000, []*structs.Namespace{ns1, ns2}))
+	assert.Nil(s1.fsm.State().UpsertNamespaces(structs.MsgTypeTestSetup, 1000, []*structs.Namespace{ns1, ns2}))
 
 	// Lookup the namespaces
 	get := &structs.NamespaceListRequest{
@@ -351,7 +351,7 @@ func TestNamespaceEndpoint_List_ACL(t *testing.T) {
 
 	ns1.Name = "aaaaaaaa-3350-4b4b-d185-0e1992ed43e9"
 	ns2.Name = "bbbbbbbb-3350-4b4b-d185-0e1992ed43e9"
-	assert.Nil(s1.fsm.State().UpsertNamespaces(1000, []*structs.Namespace{ns1, ns2}))
+	assert.Nil(s1.fsm.State().UpsertNamespaces(structs.MsgTypeTestSetup, 1000, []*structs.Namespace{ns1, ns2}))
 
 	validDefToken := mock.CreatePolicyAndToken(t, state, 1001, "test-def-valid",
 		mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityReadFS}))
@@ -424,7 +424,7 @@ func TestNamespaceEndpoint_List_Blocking(t *testing.T) {
 
 	// Upsert namespace triggers watches
 	time.AfterFunc(100*time.Millisecond, func() {
-		assert.Nil(state.UpsertNamespaces(200, []*structs.Namespace{ns}))
+		assert.Nil(state.UpsertNamespaces(structs.MsgTypeTestSetup, 200, []*structs.Namespace{ns}))
 	})
 
 	req := &structs.NamespaceListRequest{
@@ -445,7 +445,7 @@ func TestNamespaceEndpoint_List_Blocking(t *testing.T) {
 
 	// Namespace deletion triggers watches
 	time.AfterFunc(100*time.Millisecond, func() {
-		assert.Nil(state.DeleteNamespaces(300, []string{ns.Name}))
+		assert.Nil(state.DeleteNamespaces(structs.MsgTypeTestSetup, 300, []string{ns.Name}))
 	})
 
 	req.MinQueryIndex = 200
@@ -471,7 +471,7 @@ func TestNamespaceEndpoint_DeleteNamespaces(t *testing.T) {
 	// Create the register request
 	ns1 := mock.Namespace()
 	ns2 := mock.Namespace()
-	s1.fsm.State().UpsertNamespaces(1000, []*structs.Namespace{ns1, ns2})
+	s1.fsm.State().UpsertNamespaces(structs.MsgTypeTestSetup, 1000, []*structs.Namespace{ns1, ns2})
 
 	// Lookup the namespaces
 	req := &structs.NamespaceDeleteRequest{
@@ -494,7 +494,7 @@ func TestNamespaceEndpoint_DeleteNamespaces_NonTerminal_Local(t *testing.T) {
 	// Create the register request
 	ns1 := mock.Namespace()
 	ns2 := mock.Namespace()
-	s1.fsm.State().UpsertNamespaces(1000, []*structs.Namespace{ns1, ns2})
+	s1.fsm.State().UpsertNamespaces(structs.MsgTypeTestSetup, 1000, []*structs.Namespace{ns1, ns2})
 
 	// Create a job in one
 	j := mock.Job()
@@ -537,7 +537,7 @@ func TestNamespaceEndpoint_DeleteNamespaces_NonTerminal_Federated_ACL(t *testing
 
 	// Create the register request
 	ns1 := mock.Namespace()
-	s1.fsm.State().UpsertNamespaces(1000, []*structs.Namespace{ns1})
+	s1.fsm.State().UpsertNamespaces(structs.MsgTypeTestSetup, 1000, []*structs.Namespace{ns1})
 
 	testutil.WaitForResult(func() (bool, error) {
 		state := s2.State()
@@ -585,7 +585,7 @@ func TestNamespaceEndpoint_DeleteNamespaces_ACL(t *testing.T) {
 	ns1 := mock.Namespace()
 	ns2 := mock.Namespace()
 	state := s1.fsm.State()
-	s1.fsm.State().UpsertNamespaces(1000, []*structs.Namespace{ns1, ns2})
+	s1.fsm.State().UpsertNamespaces(structs.MsgTypeTestSetup, 1000, []*structs.Namespace{ns1, ns2})
 
 	// Create the policy and tokens
 	invalidToken := mock.CreatePolicyAndToken(t, state, 1003, "test-invalid",
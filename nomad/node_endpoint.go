@@ -1322,6 +1322,7 @@ func (n *Node) batchUpdate(future *structs.BatchFuture, updates []*structs.Alloc
 	var (
 		revokeVault []*structs.VaultAccessor
 		revokeSI    []*structs.SITokenAccessor
+		terminated  = make(map[string]struct{})
 	)
 
 	for _, alloc := range updates {
@@ -1329,6 +1330,7 @@ func (n *Node) batchUpdate(future *structs.BatchFuture, updates []*structs.Alloc
 		if !alloc.Terminated() {
 			continue
 		}
+		terminated[alloc.ID] = struct{}{}
 
 		ws := memdb.NewWatchSet()
 
@@ -1349,6 +1351,12 @@ func (n *Node) batchUpdate(future *structs.BatchFuture, updates []*structs.Alloc
 		}
 	}
 
+	// The variable locks held by the allocations were released along with
+	// the update, so their leases no longer need to be tracked
+	if len(terminated) > 0 {
+		n.srv.clearAllocLockTTLTimers(terminated)
+	}
+
 	// Revoke any orphaned Vault token accessors
 	if l := len(revokeVault); l > 0 {
 		n.logger.Debug("revoking vault accessors due to terminal allocations", "num_accessors", l)
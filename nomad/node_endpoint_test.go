@@ -1935,7 +1935,7 @@ func TestClientEndpoint_GetAllocs_ACL_Namespaces(t *testing.T) {
 	allocAltNS.NodeID = node.ID
 	allocOtherNS.NodeID = node.ID
 	state := s1.fsm.State()
-	assert.Nil(state.UpsertNamespaces(1, []*structs.Namespace{ns1, ns2}), "UpsertNamespaces")
+	assert.Nil(state.UpsertNamespaces(structs.MsgTypeTestSetup, 1, []*structs.Namespace{ns1, ns2}), "UpsertNamespaces")
 	assert.Nil(state.UpsertNode(structs.MsgTypeTestSetup, 2, node), "UpsertNode")
 	assert.Nil(state.UpsertJobSummary(3, mock.JobSummary(allocDefaultNS.JobID)), "UpsertJobSummary")
 	assert.Nil(state.UpsertJobSummary(4, mock.JobSummary(allocAltNS.JobID)), "UpsertJobSummary")
@@ -2777,7 +2777,7 @@ func TestClientEndpoint_CreateNodeEvals_MultipleNSes(t *testing.T) {
 
 	idx := uint64(3)
 	ns1 := mock.Namespace()
-	err := state.UpsertNamespaces(idx, []*structs.Namespace{ns1})
+	err := state.UpsertNamespaces(structs.MsgTypeTestSetup, idx, []*structs.Namespace{ns1})
 	require.NoError(t, err)
 	idx++
 
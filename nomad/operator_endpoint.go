@@ -8,12 +8,16 @@ import (
 	"time"
 
 	log "github.com/hashicorp/go-hclog"
+	memdb "github.com/hashicorp/go-memdb"
 	"github.com/hashicorp/go-msgpack/codec"
 
 	"github.com/hashicorp/consul/agent/consul/autopilot"
 	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/helper/pointer"
 	"github.com/hashicorp/nomad/helper/snapshot"
+	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/scheduler"
 	"github.com/hashicorp/raft"
 	"github.com/hashicorp/serf/serf"
 )
@@ -380,6 +384,121 @@ func (op *Operator) SchedulerGetConfiguration(args *structs.GenericRequest, repl
 	return nil
 }
 
+// SchedulerRebalance is used to migrate the allocations of service jobs to
+// nodes where they would score better. It runs on the leader so that the
+// migrations are computed against the latest state.
+func (op *Operator) SchedulerRebalance(args *structs.SchedulerRebalanceRequest, reply *structs.SchedulerRebalanceResponse) error {
+	if done, err := op.srv.forward("Operator.SchedulerRebalance", args, args, reply); done {
+		return err
+	}
+
+	// A dry-run requires operator read access, otherwise operator write
+	rule, err := op.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	} else if rule != nil {
+		if args.DryRun && !rule.AllowOperatorRead() {
+			return structs.ErrPermissionDenied
+		} else if !args.DryRun && !rule.AllowOperatorWrite() {
+			return structs.ErrPermissionDenied
+		}
+	}
+
+	if args.MinScoreImprovement < 0 {
+		return fmt.Errorf("minimum score improvement must not be negative")
+	}
+	minImprovement := args.MinScoreImprovement
+	if minImprovement == 0 {
+		minImprovement = structs.DefaultRebalanceMinScoreImprovement
+	}
+
+	snap, err := op.srv.fsm.State().Snapshot()
+	if err != nil {
+		return err
+	}
+
+	// Find the jobs to rebalance
+	var jobs []*structs.Job
+	if args.JobID != "" {
+		job, err := snap.JobByID(nil, args.RequestNamespace(), args.JobID)
+		if err != nil {
+			return err
+		} else if job == nil {
+			return fmt.Errorf("job %q not found", args.JobID)
+		}
+		jobs = append(jobs, job)
+	} else {
+		var iter memdb.ResultIterator
+		if args.RequestNamespace() == structs.AllNamespacesSentinel {
+			iter, err = snap.Jobs(nil)
+		} else {
+			iter, err = snap.JobsByNamespace(nil, args.RequestNamespace())
+		}
+		if err != nil {
+			return err
+		}
+		for raw := iter.Next(); raw != nil; raw = iter.Next() {
+			jobs = append(jobs, raw.(*structs.Job))
+		}
+	}
+
+	transitions := make(map[string]*structs.DesiredTransition)
+	var evals []*structs.Evaluation
+	now := time.Now().UTC().UnixNano()
+	for _, job := range jobs {
+		migrations, err := scheduler.RebalanceJob(op.logger, snap, job, minImprovement)
+		if err != nil {
+			return fmt.Errorf("failed to rebalance job %q: %v", job.ID, err)
+		}
+		if len(migrations) == 0 {
+			continue
+		}
+
+		reply.Migrations = append(reply.Migrations, migrations...)
+		for _, migration := range migrations {
+			transitions[migration.AllocID] = &structs.DesiredTransition{
+				Migrate: pointer.Of(true),
+			}
+		}
+		evals = append(evals, &structs.Evaluation{
+			ID:          uuid.Generate(),
+			Namespace:   job.Namespace,
+			Priority:    job.Priority,
+			Type:        job.Type,
+			TriggeredBy: structs.EvalTriggerRebalance,
+			JobID:       job.ID,
+			Status:      structs.EvalStatusPending,
+			CreateTime:  now,
+			ModifyTime:  now,
+		})
+	}
+
+	if args.DryRun || len(evals) == 0 {
+		return nil
+	}
+
+	// Mark the allocations for migration and create the evaluations to
+	// migrate them
+	req := &structs.AllocUpdateDesiredTransitionRequest{
+		Allocs:       transitions,
+		Evals:        evals,
+		WriteRequest: structs.WriteRequest{Region: op.srv.config.Region},
+	}
+	resp, index, err := op.srv.raftApply(structs.AllocUpdateDesiredTransitionRequestType, req)
+	if err != nil {
+		op.logger.Error("failed to apply rebalance migrations", "error", err)
+		return err
+	} else if respErr, ok := resp.(error); ok {
+		return respErr
+	}
+
+	for _, eval := range evals {
+		reply.EvalIDs = append(reply.EvalIDs, eval.ID)
+	}
+	reply.Index = index
+	return nil
+}
+
 func (op *Operator) forwardStreamingRPC(region string, method string, args interface{}, in io.ReadWriteCloser) error {
 	server, err := op.srv.findRegionServer(region)
 	if err != nil {
@@ -20,9 +20,11 @@ import (
 	"github.com/hashicorp/nomad/ci"
 	cstructs "github.com/hashicorp/nomad/client/structs"
 	"github.com/hashicorp/nomad/helper/freeport"
+	"github.com/hashicorp/nomad/helper/pointer"
 	"github.com/hashicorp/nomad/helper/snapshot"
 	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/state"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/testutil"
 	"github.com/hashicorp/raft"
@@ -538,6 +540,145 @@ func TestOperator_SchedulerSetConfiguration_ACL(t *testing.T) {
 
 }
 
+// upsertRebalanceJob creates a job with an affinity for a node class and runs
+// its allocations on nodes of another class, so that they can be rebalanced.
+func upsertRebalanceJob(t *testing.T, state *state.StateStore) *structs.Job {
+	fast := mock.Node()
+	fast.NodeClass = "fast"
+	fast.ComputeClass()
+	require.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, 1000, fast))
+
+	job := mock.Job()
+	tg := job.TaskGroups[0]
+	tg.Count = 2
+	tg.Networks = nil
+	tg.Tasks[0].Services = nil
+	tg.Migrate.MaxParallel = 2
+	tg.Affinities = []*structs.Affinity{{
+		LTarget: "${node.class}",
+		RTarget: "fast",
+		Operand: "=",
+		Weight:  100,
+	}}
+	require.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1001, job))
+
+	var allocs []*structs.Allocation
+	for i := 0; i < tg.Count; i++ {
+		node := mock.Node()
+		require.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, uint64(1002+i), node))
+
+		alloc := mock.Alloc()
+		alloc.Job = job
+		alloc.JobID = job.ID
+		alloc.NodeID = node.ID
+		alloc.Name = fmt.Sprintf("my-job.web[%d]", i)
+		alloc.AllocatedResources.Tasks["web"].Networks = nil
+		alloc.AllocatedResources.Shared.Networks = nil
+		alloc.ClientStatus = structs.AllocClientStatusRunning
+		alloc.DeploymentStatus = &structs.AllocDeploymentStatus{Healthy: pointer.Of(true)}
+		allocs = append(allocs, alloc)
+	}
+	require.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 1010, allocs))
+	return job
+}
+
+func TestOperator_SchedulerRebalance(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent the migrations from being scheduled
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+	job := upsertRebalanceJob(t, state)
+
+	// A dry-run reports the migrations without performing them
+	arg := structs.SchedulerRebalanceRequest{
+		JobID:  job.ID,
+		DryRun: true,
+		WriteRequest: structs.WriteRequest{
+			Region:    s1.config.Region,
+			Namespace: job.Namespace,
+		},
+	}
+	var reply structs.SchedulerRebalanceResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Operator.SchedulerRebalance", &arg, &reply))
+	require.NotEmpty(t, reply.Migrations)
+	require.Empty(t, reply.EvalIDs)
+	for _, m := range reply.Migrations {
+		alloc, err := state.AllocByID(nil, m.AllocID)
+		require.NoError(t, err)
+		require.False(t, alloc.DesiredTransition.ShouldMigrate())
+	}
+
+	// Otherwise the allocations are marked for migration
+	arg.DryRun = false
+	reply = structs.SchedulerRebalanceResponse{}
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Operator.SchedulerRebalance", &arg, &reply))
+	require.NotEmpty(t, reply.Migrations)
+	require.Len(t, reply.EvalIDs, 1)
+	require.NotZero(t, reply.Index)
+	for _, m := range reply.Migrations {
+		alloc, err := state.AllocByID(nil, m.AllocID)
+		require.NoError(t, err)
+		require.True(t, alloc.DesiredTransition.ShouldMigrate())
+	}
+	eval, err := state.EvalByID(nil, reply.EvalIDs[0])
+	require.NoError(t, err)
+	require.Equal(t, structs.EvalTriggerRebalance, eval.TriggeredBy)
+	require.Equal(t, job.ID, eval.JobID)
+
+	// Unknown jobs and negative thresholds are rejected
+	arg.JobID = "unknown"
+	err = msgpackrpc.CallWithCodec(codec, "Operator.SchedulerRebalance", &arg, &reply)
+	require.EqualError(t, err, `job "unknown" not found`)
+
+	arg.JobID = job.ID
+	arg.MinScoreImprovement = -1
+	err = msgpackrpc.CallWithCodec(codec, "Operator.SchedulerRebalance", &arg, &reply)
+	require.EqualError(t, err, "minimum score improvement must not be negative")
+}
+
+func TestOperator_SchedulerRebalance_ACL(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, root, cleanupS1 := TestACLServer(t, func(c *Config) {
+		c.NumSchedulers = 0
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	readToken := mock.CreatePolicyAndToken(t, state, 1001, "operator-read", `operator { policy = "read" }`)
+
+	arg := structs.SchedulerRebalanceRequest{
+		DryRun: true,
+		WriteRequest: structs.WriteRequest{
+			Region: s1.config.Region,
+		},
+	}
+	var reply structs.SchedulerRebalanceResponse
+
+	// Try with no token and expect permission denied
+	err := msgpackrpc.CallWithCodec(codec, "Operator.SchedulerRebalance", &arg, &reply)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+
+	// A dry-run only requires operator read
+	arg.AuthToken = readToken.SecretID
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Operator.SchedulerRebalance", &arg, &reply))
+
+	arg.DryRun = false
+	err = msgpackrpc.CallWithCodec(codec, "Operator.SchedulerRebalance", &arg, &reply)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+
+	// Try with root token, should succeed
+	arg.AuthToken = root.SecretID
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Operator.SchedulerRebalance", &arg, &reply))
+}
+
 func TestOperator_SnapshotSave(t *testing.T) {
 	ci.Parallel(t)
 
@@ -31,6 +31,10 @@ type planner struct {
 	// scheduling, but repeated rejections for the same node may indicate an
 	// undetected issue, so we need to track rejection history.
 	badNodeTracker BadNodeTracker
+
+	// preemptionBudget tracks the allocations recently preempted by plans to
+	// enforce the preemption budget of the scheduler configuration.
+	preemptionBudget *PreemptionBudget
 }
 
 // newPlanner returns a new planner to be used for managing allocation plans.
@@ -60,10 +64,11 @@ func newPlanner(s *Server) (*planner, error) {
 	}
 
 	return &planner{
-		Server:         s,
-		log:            log,
-		planQueue:      planQueue,
-		badNodeTracker: badNodeTracker,
+		Server:           s,
+		log:              log,
+		planQueue:        planQueue,
+		badNodeTracker:   badNodeTracker,
+		preemptionBudget: NewPreemptionBudget(preemptionBudgetWindow),
 	}, nil
 }
 
@@ -168,6 +173,14 @@ func (p *planner) planApply() {
 			continue
 		}
 
+		// Reject the placements whose preemptions exceed the budget
+		err = evaluatePreemptionBudget(p.preemptionBudget, snap, pending.plan, result, time.Now(), p.logger)
+		if err != nil {
+			p.logger.Error("failed to evaluate plan preemption budget", "error", err)
+			pending.respond(nil, err)
+			continue
+		}
+
 		// Check if any of the rejected nodes should be made ineligible.
 		for _, nodeID := range result.RejectedNodes {
 			if p.badNodeTracker.Add(nodeID) {
@@ -203,6 +216,10 @@ func (p *planner) planApply() {
 			continue
 		}
 
+		// The preemptions count against the budget while the plan is
+		// applied, so the plans evaluated meanwhile don't exceed it
+		p.preemptionBudget.Reserve(numPreemptions(result))
+
 		// Respond to the plan in async; receive plan's committed index via chan
 		planIndexCh = make(chan uint64, 1)
 		go p.asyncPlanWait(planIndexCh, future, result, pending)
@@ -434,11 +451,14 @@ func (p *planner) asyncPlanWait(indexCh chan<- uint64, future raft.ApplyFuture,
 	defer close(indexCh)
 
 	// Wait for the plan to apply
+	preemptions := numPreemptions(result)
 	if err := future.Error(); err != nil {
+		p.preemptionBudget.Release(preemptions)
 		p.logger.Error("failed to apply plan", "error", err)
 		pending.respond(nil, err)
 		return
 	}
+	p.preemptionBudget.Commit(preemptions, time.Now())
 
 	// Respond to the plan
 	index := future.Index()
@@ -655,6 +675,10 @@ OUTER:
 			mErr.Errors = append(mErr.Errors, err)
 		}
 
+		// If there was a partial commit drop the placements of any gang task
+		// group which wasn't placed completely
+		correctGangPlacements(plan, result)
+
 		// If there was a partial commit and we are operating within a
 		// deployment correct for any canary that may have been desired to be
 		// placed but wasn't actually placed
@@ -705,6 +729,109 @@ func correctDeploymentCanaries(result *structs.PlanResult) {
 	}
 }
 
+// correctGangPlacements ensures that the placements of a task group with gang
+// scheduling are committed all together or not at all. This could be violated
+// if the plan had a partial commit, in which case the committed placements of
+// any gang task group missing some of its placements are removed from the
+// result, along with the allocations they preempted and the stops of the
+// allocations they replace.
+func correctGangPlacements(plan *structs.Plan, result *structs.PlanResult) {
+	// Hot path
+	if plan.Job == nil {
+		return
+	}
+	gang := make(map[string]struct{})
+	for _, tg := range plan.Job.TaskGroups {
+		if tg.Gang {
+			gang[tg.Name] = struct{}{}
+		}
+	}
+	if len(gang) == 0 {
+		return
+	}
+
+	// Find the gang task groups with placements that weren't committed
+	committed := make(map[string]struct{})
+	for _, placed := range result.NodeAllocation {
+		for _, alloc := range placed {
+			committed[alloc.ID] = struct{}{}
+		}
+	}
+	incomplete := make(map[string]struct{})
+	for _, placed := range plan.NodeAllocation {
+		for _, alloc := range placed {
+			if _, ok := gang[alloc.TaskGroup]; !ok {
+				continue
+			}
+			if _, ok := committed[alloc.ID]; !ok {
+				incomplete[alloc.TaskGroup] = struct{}{}
+			}
+		}
+	}
+	if len(incomplete) == 0 {
+		return
+	}
+
+	// The previous allocations replaced by those task groups' placements must
+	// keep running, as their replacements won't be committed
+	replaced := make(map[string]struct{})
+	for _, placed := range plan.NodeAllocation {
+		for _, alloc := range placed {
+			if _, ok := incomplete[alloc.TaskGroup]; ok && alloc.PreviousAllocation != "" {
+				replaced[alloc.PreviousAllocation] = struct{}{}
+			}
+		}
+	}
+
+	// Remove the placements of those task groups, their preemptions and the
+	// stops of the allocations they replace
+	removed := make(map[string]struct{})
+	for nodeID, placed := range result.NodeAllocation {
+		var remaining []*structs.Allocation
+		for _, alloc := range placed {
+			if _, ok := incomplete[alloc.TaskGroup]; ok {
+				removed[alloc.ID] = struct{}{}
+				continue
+			}
+			remaining = append(remaining, alloc)
+		}
+		if len(remaining) == 0 {
+			delete(result.NodeAllocation, nodeID)
+		} else {
+			result.NodeAllocation[nodeID] = remaining
+		}
+	}
+	for nodeID, preempted := range result.NodePreemptions {
+		var remaining []*structs.Allocation
+		for _, alloc := range preempted {
+			if _, ok := removed[alloc.PreemptedByAllocation]; !ok {
+				remaining = append(remaining, alloc)
+			}
+		}
+		if len(remaining) == 0 {
+			delete(result.NodePreemptions, nodeID)
+		} else {
+			result.NodePreemptions[nodeID] = remaining
+		}
+	}
+	if len(replaced) == 0 {
+		return
+	}
+	for nodeID, stopped := range result.NodeUpdate {
+		var remaining []*structs.Allocation
+		for _, alloc := range stopped {
+			if _, ok := replaced[alloc.ID]; !ok {
+				remaining = append(remaining, alloc)
+			}
+		}
+		if len(remaining) == 0 {
+			delete(result.NodeUpdate, nodeID)
+		} else {
+			result.NodeUpdate[nodeID] = remaining
+		}
+	}
+}
+
 // evaluateNodePlan is used to evaluate the plan for a single node,
 // returning if the plan is valid or if an error is encountered
 func evaluateNodePlan(snap *state.StateSnapshot, plan *structs.Plan, nodeID string) (bool, string, error) {
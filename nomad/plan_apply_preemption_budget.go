@@ -0,0 +1,168 @@
+package nomad
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// preemptionBudgetWindow is the time window over which the preemption budget
+// of the scheduler configuration applies.
+const preemptionBudgetWindow = time.Minute
+
+// PreemptionBudget keeps a record of the allocations preempted by the plans
+// applied within a sliding time window, so the plan applier can limit the rate
+// of preemptions to the MaxPreemptionsPerMinute of the scheduler
+// configuration.
+type PreemptionBudget struct {
+	window time.Duration
+
+	// preemptions holds the time of each committed preemption, oldest first.
+	preemptions []time.Time
+
+	// pending is the number of preemptions of plans being applied, which
+	// count against the budget until they're committed or fail to apply.
+	pending int
+	l       sync.Mutex
+}
+
+// NewPreemptionBudget returns a preemption budget over the given window.
+func NewPreemptionBudget(window time.Duration) *PreemptionBudget {
+	return &PreemptionBudget{window: window}
+}
+
+// Remaining returns how many allocations may still be preempted at the given
+// time without exceeding the limit.
+func (b *PreemptionBudget) Remaining(limit int, now time.Time) int {
+	b.l.Lock()
+	defer b.l.Unlock()
+
+	b.expire(now)
+	if remaining := limit - len(b.preemptions) - b.pending; remaining > 0 {
+		return remaining
+	}
+	return 0
+}
+
+// Reserve registers n preemptions of a plan that is being applied.
+func (b *PreemptionBudget) Reserve(n int) {
+	b.l.Lock()
+	defer b.l.Unlock()
+
+	b.pending += n
+}
+
+// Commit records n reserved preemptions as committed at the given time.
+func (b *PreemptionBudget) Commit(n int, now time.Time) {
+	b.l.Lock()
+	defer b.l.Unlock()
+
+	b.pending -= n
+	b.expire(now)
+	for i := 0; i < n; i++ {
+		b.preemptions = append(b.preemptions, now)
+	}
+}
+
+// Release drops n reserved preemptions of a plan that failed to apply.
+func (b *PreemptionBudget) Release(n int) {
+	b.l.Lock()
+	defer b.l.Unlock()
+
+	b.pending -= n
+}
+
+// expire drops the preemptions older than the window. The lock must be held.
+func (b *PreemptionBudget) expire(now time.Time) {
+	cutoff := now.Add(-b.window)
+	i := sort.Search(len(b.preemptions), func(i int) bool {
+		return b.preemptions[i].After(cutoff)
+	})
+	b.preemptions = b.preemptions[i:]
+}
+
+// evaluatePreemptionBudget rejects the nodes of the plan result whose
+// preemptions would exceed the preemption budget of the scheduler
+// configuration, forcing the scheduler to refresh its state and plan again.
+// The preemptions of the remaining nodes are only counted against the budget
+// once the plan result is applied.
+func evaluatePreemptionBudget(budget *PreemptionBudget, snap *state.StateSnapshot,
+	plan *structs.Plan, result *structs.PlanResult, now time.Time, logger log.Logger) error {
+
+	_, schedConfig, err := snap.SchedulerConfig()
+	if err != nil {
+		return err
+	}
+	if schedConfig == nil || schedConfig.PreemptionConfig.MaxPreemptionsPerMinute == 0 {
+		return nil
+	}
+
+	// Walk the nodes in a stable order so the same nodes are accepted when
+	// the scheduler retries the plan
+	nodeIDs := make([]string, 0, len(result.NodePreemptions))
+	for nodeID, preempted := range result.NodePreemptions {
+		if len(preempted) > 0 {
+			nodeIDs = append(nodeIDs, nodeID)
+		}
+	}
+	if len(nodeIDs) == 0 {
+		return nil
+	}
+	sort.Strings(nodeIDs)
+
+	remaining := budget.Remaining(schedConfig.PreemptionConfig.MaxPreemptionsPerMinute, now)
+	var rejected []string
+	for _, nodeID := range nodeIDs {
+		n := len(result.NodePreemptions[nodeID])
+		if n <= remaining {
+			remaining -= n
+			continue
+		}
+		rejected = append(rejected, nodeID)
+	}
+
+	if len(rejected) > 0 {
+		metrics.IncrCounter([]string{"nomad", "plan", "preemption_budget_exceeded"}, 1)
+		logger.Debug("plan preemptions exceed the preemption budget", "eval_id", plan.EvalID, "rejected_nodes", len(rejected))
+
+		if plan.AllAtOnce {
+			result.NodeUpdate = nil
+			result.NodeAllocation = nil
+			result.DeploymentUpdates = nil
+			result.Deployment = nil
+			result.NodePreemptions = nil
+		} else {
+			for _, nodeID := range rejected {
+				delete(result.NodeUpdate, nodeID)
+				delete(result.NodeAllocation, nodeID)
+				delete(result.NodePreemptions, nodeID)
+			}
+			correctGangPlacements(plan, result)
+			correctDeploymentCanaries(result)
+		}
+
+		if result.RefreshIndex == 0 {
+			index, err := refreshIndex(snap)
+			if err != nil {
+				return err
+			}
+			result.RefreshIndex = index
+		}
+	}
+	return nil
+}
+
+// numPreemptions returns the number of allocations preempted by the plan
+// result.
+func numPreemptions(result *structs.PlanResult) int {
+	n := 0
+	for _, preempted := range result.NodePreemptions {
+		n += len(preempted)
+	}
+	return n
+}
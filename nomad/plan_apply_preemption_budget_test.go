@@ -0,0 +1,111 @@
+package nomad
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
+)
+
+func TestPreemptionBudget(t *testing.T) {
+	ci.Parallel(t)
+
+	budget := NewPreemptionBudget(time.Minute)
+	now := time.Now()
+
+	must.Eq(t, 3, budget.Remaining(3, now))
+
+	// Preemptions being applied count against the budget
+	budget.Reserve(2)
+	must.Eq(t, 1, budget.Remaining(3, now))
+
+	// Unless they fail to apply
+	budget.Release(2)
+	must.Eq(t, 3, budget.Remaining(3, now))
+
+	budget.Reserve(2)
+	budget.Commit(2, now)
+	must.Eq(t, 1, budget.Remaining(3, now))
+
+	budget.Reserve(2)
+	budget.Commit(2, now.Add(30*time.Second))
+	must.Eq(t, 0, budget.Remaining(3, now.Add(30*time.Second)))
+
+	// The first preemptions leave the window
+	must.Eq(t, 1, budget.Remaining(3, now.Add(61*time.Second)))
+	must.Eq(t, 3, budget.Remaining(3, now.Add(91*time.Second)))
+}
+
+func TestPlanApply_EvalPreemptionBudget(t *testing.T) {
+	ci.Parallel(t)
+
+	newResult := func(nodeIDs ...string) *structs.PlanResult {
+		result := &structs.PlanResult{
+			NodeUpdate:      make(map[string][]*structs.Allocation),
+			NodeAllocation:  make(map[string][]*structs.Allocation),
+			NodePreemptions: make(map[string][]*structs.Allocation),
+		}
+		for _, nodeID := range nodeIDs {
+			result.NodeAllocation[nodeID] = []*structs.Allocation{mock.Alloc()}
+			result.NodePreemptions[nodeID] = []*structs.Allocation{mock.Alloc(), mock.Alloc()}
+		}
+		return result
+	}
+
+	state := testStateStore(t)
+	must.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, 1000, mock.Node()))
+	snap, err := state.Snapshot()
+	must.NoError(t, err)
+
+	logger := testlog.HCLogger(t)
+	budget := NewPreemptionBudget(time.Minute)
+	now := time.Now()
+	plan := &structs.Plan{}
+
+	// Without a budget every preemption is accepted
+	result := newResult("a", "b", "c")
+	must.NoError(t, evaluatePreemptionBudget(budget, snap, plan, result, now, logger))
+	must.MapLen(t, 3, result.NodePreemptions)
+	must.Zero(t, result.RefreshIndex)
+
+	// With a budget of 5 preemptions, the third node is rejected
+	must.NoError(t, state.SchedulerSetConfig(1001, &structs.SchedulerConfiguration{
+		PreemptionConfig: structs.PreemptionConfig{
+			ServiceSchedulerEnabled: true,
+			MaxPreemptionsPerMinute: 5,
+		},
+	}))
+	snap, err = state.Snapshot()
+	must.NoError(t, err)
+
+	result = newResult("a", "b", "c")
+	must.NoError(t, evaluatePreemptionBudget(budget, snap, plan, result, now, logger))
+	must.MapContainsKeys(t, result.NodePreemptions, []string{"a", "b"})
+	must.MapLen(t, 2, result.NodePreemptions)
+	must.MapLen(t, 2, result.NodeAllocation)
+	must.NonZero(t, result.RefreshIndex)
+
+	// The preemptions are only counted once the plan is applied
+	must.Eq(t, 5, budget.Remaining(5, now))
+	budget.Reserve(numPreemptions(result))
+	budget.Commit(numPreemptions(result), now)
+	must.Eq(t, 1, budget.Remaining(5, now))
+
+	// An all-at-once plan is rejected entirely
+	result = newResult("a")
+	plan.AllAtOnce = true
+	must.NoError(t, evaluatePreemptionBudget(budget, snap, plan, result, now, logger))
+	must.Nil(t, result.NodeAllocation)
+	must.Nil(t, result.NodePreemptions)
+	must.NonZero(t, result.RefreshIndex)
+	must.Eq(t, 1, budget.Remaining(5, now))
+
+	// The budget is replenished once the window has passed
+	result = newResult("a")
+	must.NoError(t, evaluatePreemptionBudget(budget, snap, plan, result, now.Add(time.Minute), logger))
+	must.MapLen(t, 1, result.NodePreemptions)
+}
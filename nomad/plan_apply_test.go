@@ -657,6 +657,134 @@ func TestPlanApply_EvalPlan_Partial_AllAtOnce(t *testing.T) {
 	}
 }
 
+func TestPlanApply_EvalPlan_Partial_Gang(t *testing.T) {
+	ci.Parallel(t)
+	state := testStateStore(t)
+	node := mock.Node()
+	state.UpsertNode(structs.MsgTypeTestSetup, 1000, node)
+	node2 := mock.Node()
+	state.UpsertNode(structs.MsgTypeTestSetup, 1001, node2)
+	node3 := mock.Node()
+	state.UpsertNode(structs.MsgTypeTestSetup, 1002, node3)
+	snap, _ := state.Snapshot()
+
+	job := mock.Job()
+	job.TaskGroups[0].Gang = true
+	job.TaskGroups = append(job.TaskGroups, job.TaskGroups[0].Copy())
+	job.TaskGroups[1].Name = "other"
+	job.TaskGroups[1].Gang = false
+
+	alloc := mock.Alloc()
+	alloc.Job = job
+	alloc2 := mock.Alloc() // Ensure alloc2 does not fit
+	alloc2.Job = job
+	alloc2.AllocatedResources = structs.NodeResourcesToAllocatedResources(node2.NodeResources)
+	alloc3 := mock.Alloc()
+	alloc3.Job = job
+	alloc3.TaskGroup = "other"
+
+	// The allocation preempted by the gang placement on the fitting node
+	preempted := mock.Alloc()
+	preempted.NodeID = node.ID
+	require.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 1003, []*structs.Allocation{preempted}))
+	snap, _ = state.Snapshot()
+
+	plan := &structs.Plan{
+		Job: job,
+		NodeAllocation: map[string][]*structs.Allocation{
+			node.ID:  {alloc},
+			node2.ID: {alloc2},
+			node3.ID: {alloc3},
+		},
+		NodePreemptions: map[string][]*structs.Allocation{
+			node.ID: {{ID: preempted.ID, PreemptedByAllocation: alloc.ID}},
+		},
+	}
+
+	pool := NewEvaluatePool(workerPoolSize, workerPoolBufferSize)
+	defer pool.Shutdown()
+
+	result, err := evaluatePlan(pool, snap, plan, testlog.HCLogger(t))
+	require.NoError(t, err)
+	require.NotNil(t, result)
+
+	// None of the gang placements are committed, but the placement of the
+	// other task group is
+	require.Len(t, result.NodeAllocation, 1)
+	require.Contains(t, result.NodeAllocation, node3.ID)
+	require.Empty(t, result.NodePreemptions)
+	require.Equal(t, uint64(1003), result.RefreshIndex)
+}
+
+func TestPlanApply_EvalPlan_Partial_GangDestructive(t *testing.T) {
+	ci.Parallel(t)
+	state := testStateStore(t)
+	node := mock.Node()
+	state.UpsertNode(structs.MsgTypeTestSetup, 1000, node)
+	node2 := mock.Node()
+	state.UpsertNode(structs.MsgTypeTestSetup, 1001, node2)
+	node3 := mock.Node()
+	state.UpsertNode(structs.MsgTypeTestSetup, 1002, node3)
+
+	job := mock.Job()
+	job.TaskGroups[0].Gang = true
+
+	// The allocations being destructively updated, and an unrelated
+	// allocation being stopped, all on the first node
+	prev := mock.Alloc()
+	prev.Job = job
+	prev.NodeID = node.ID
+	prev2 := mock.Alloc()
+	prev2.Job = job
+	prev2.NodeID = node.ID
+	other := mock.Alloc()
+	other.NodeID = node.ID
+	require.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 1003,
+		[]*structs.Allocation{prev, prev2, other}))
+	snap, _ := state.Snapshot()
+
+	stop := func(alloc *structs.Allocation) *structs.Allocation {
+		stopped := alloc.Copy()
+		stopped.DesiredStatus = structs.AllocDesiredStatusStop
+		return stopped
+	}
+
+	// The replacement on the second node fits, but the replacement on the
+	// third node does not
+	alloc := mock.Alloc()
+	alloc.Job = job
+	alloc.PreviousAllocation = prev.ID
+	alloc2 := mock.Alloc()
+	alloc2.Job = job
+	alloc2.PreviousAllocation = prev2.ID
+	alloc2.AllocatedResources = structs.NodeResourcesToAllocatedResources(node3.NodeResources)
+
+	plan := &structs.Plan{
+		Job: job,
+		NodeUpdate: map[string][]*structs.Allocation{
+			node.ID: {stop(prev), stop(prev2), stop(other)},
+		},
+		NodeAllocation: map[string][]*structs.Allocation{
+			node2.ID: {alloc},
+			node3.ID: {alloc2},
+		},
+	}
+
+	pool := NewEvaluatePool(workerPoolSize, workerPoolBufferSize)
+	defer pool.Shutdown()
+
+	result, err := evaluatePlan(pool, snap, plan, testlog.HCLogger(t))
+	require.NoError(t, err)
+	require.NotNil(t, result)
+
+	// None of the gang placements are committed, so the allocations they
+	// replace aren't stopped, but the unrelated allocation is
+	require.Empty(t, result.NodeAllocation)
+	require.Len(t, result.NodeUpdate, 1)
+	require.Len(t, result.NodeUpdate[node.ID], 1)
+	require.Equal(t, other.ID, result.NodeUpdate[node.ID][0].ID)
+}
+
 func TestPlanApply_EvalNodePlan_Simple(t *testing.T) {
 	ci.Parallel(t)
 	state := testStateStore(t)
@@ -33,6 +33,7 @@ import (
 	"github.com/hashicorp/nomad/helper/tlsutil"
 	"github.com/hashicorp/nomad/nomad/deploymentwatcher"
 	"github.com/hashicorp/nomad/nomad/drainer"
+	"github.com/hashicorp/nomad/nomad/keywrap"
 	"github.com/hashicorp/nomad/nomad/state"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/nomad/structs/config"
@@ -234,6 +235,10 @@ type Server struct {
 	// detects an expired node, the node status is updated to be 'down'.
 	*nodeHeartbeater
 
+	// variableLocker is used to track expiration times of the leases of
+	// variable locks. If it detects an expired lease, the lock is released.
+	*variableLocker
+
 	// consulCatalog is used for discovering other Nomad Servers via Consul
 	consulCatalog consul.CatalogAPI
 
@@ -383,6 +388,9 @@ func NewServer(config *Config, consulCatalog consul.CatalogAPI, consulConfigEntr
 	// Create the node heartbeater
 	s.nodeHeartbeater = newNodeHeartbeater(s)
 
+	// Create the variable locker
+	s.variableLocker = newVariableLocker(s)
+
 	// Create the periodic dispatcher for launching periodic jobs.
 	s.periodicDispatcher = NewPeriodicDispatch(s.logger, s)
 
@@ -407,7 +415,14 @@ func NewServer(config *Config, consulCatalog consul.CatalogAPI, consulConfigEntr
 			return nil, fmt.Errorf("Failed to create keystore tempdir")
 		}
 	}
-	encrypter, err := NewEncrypter(s, keystorePath)
+	var wrapper keywrap.Wrapper
+	if s.config.KeystoreWrapper != nil {
+		wrapper, err = keywrap.New(s.config.KeystoreWrapper)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to setup keystore wrapper: %v", err)
+		}
+	}
+	encrypter, err := NewEncrypter(s, keystorePath, wrapper)
 	if err != nil {
 		return nil, err
 	}
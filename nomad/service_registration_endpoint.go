@@ -88,6 +88,66 @@ func (s *ServiceRegistration) Upsert(
 	return nil
 }
 
+// UpdateChecks updates the check results held on the service registrations
+// of a single allocation. This RPC is only callable by Nomad nodes.
+func (s *ServiceRegistration) UpdateChecks(
+	args *structs.ServiceRegistrationUpdateChecksRequest,
+	reply *structs.ServiceRegistrationUpdateChecksResponse) error {
+
+	// Ensure the connection was initiated by a client if TLS is used.
+	if err := validateTLSCertificateLevel(s.srv, s.ctx, tlsCertificateLevelClient); err != nil {
+		return err
+	}
+
+	if done, err := s.srv.forward(structs.ServiceRegistrationUpdateChecksRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "service_registration", "update_checks"}, time.Now())
+
+	// This endpoint is only callable by nodes in the cluster. Therefore,
+	// perform a node lookup using the secret ID to confirm the caller is a
+	// known node.
+	node, err := s.srv.fsm.State().NodeBySecretID(nil, args.AuthToken)
+	if err != nil {
+		return err
+	}
+	if node == nil {
+		return structs.ErrTokenNotFound
+	}
+
+	if args.AllocID == "" {
+		return structs.NewErrRPCCodedf(http.StatusBadRequest, "missing allocation ID")
+	}
+
+	// Nodes may only update the checks of allocations running on them.
+	alloc, err := s.srv.fsm.State().AllocByID(nil, args.AllocID)
+	if err != nil {
+		return err
+	}
+	if alloc == nil {
+		return structs.NewErrRPCCodedf(http.StatusNotFound, "allocation %q not found", args.AllocID)
+	}
+	if alloc.NodeID != node.ID {
+		return structs.ErrPermissionDenied
+	}
+
+	// Update via Raft.
+	out, index, err := s.srv.raftApply(structs.ServiceRegistrationUpdateChecksRequestType, args)
+	if err != nil {
+		return err
+	}
+
+	// Check if the FSM response, which is an interface, contains an error.
+	if err, ok := out.(error); ok && err != nil {
+		return err
+	}
+
+	// Update the index. There is no need to floor this as we are writing to
+	// state and therefore will get a non-zero index response.
+	reply.Index = index
+	return nil
+}
+
 // DeleteByID removes a single service registration, as specified by its ID
 // from Nomad. This is typically called by Nomad nodes, however, in extreme
 // situations can be used via the CLI and API by operators.
@@ -359,8 +419,23 @@ func (s *ServiceRegistration) GetService(
 		queryMeta: &reply.QueryMeta,
 		run: func(ws memdb.WatchSet, stateStore *state.StateStore) error {
 
-			// Perform the state query to get an iterator.
-			iter, err := stateStore.GetServiceRegistrationByName(ws, args.RequestNamespace(), args.ServiceName)
+			// Perform the state query to get an iterator. Registrations
+			// with the requested metadata are found using the meta index,
+			// which spans service names.
+			var iter memdb.ResultIterator
+			var err error
+			if args.MetaKey != "" {
+				iter, err = stateStore.GetServiceRegistrationsByMeta(
+					ws, args.RequestNamespace(), args.MetaKey, args.MetaValue)
+				if err == nil {
+					iter = memdb.NewFilterIterator(iter, func(raw interface{}) bool {
+						reg := raw.(*structs.ServiceRegistration)
+						return reg.Namespace != args.RequestNamespace() || reg.ServiceName != args.ServiceName
+					})
+				}
+			} else {
+				iter, err = stateStore.GetServiceRegistrationByName(ws, args.RequestNamespace(), args.ServiceName)
+			}
 			if err != nil {
 				return err
 			}
@@ -377,9 +452,20 @@ func (s *ServiceRegistration) GetService(
 			// Set up our output after we have checked the error.
 			var services []*structs.ServiceRegistration
 
+			// If the caller only wants healthy registrations, filter out any
+			// with a check which is not passing.
+			var filters []paginator.Filter
+			if args.Healthy {
+				filters = append(filters, paginator.GenericFilter{
+					Allow: func(raw interface{}) (bool, error) {
+						return raw.(*structs.ServiceRegistration).Healthy(), nil
+					},
+				})
+			}
+
 			// Build the paginator. This includes the function that is
 			// responsible for appending a registration to the services array.
-			paginatorImpl, err := paginator.NewPaginator(iter, tokenizer, nil, args.QueryOptions,
+			paginatorImpl, err := paginator.NewPaginator(iter, tokenizer, filters, args.QueryOptions,
 				func(raw interface{}) error {
 					services = append(services, raw.(*structs.ServiceRegistration))
 					return nil
@@ -200,6 +200,90 @@ func TestServiceRegistration_Upsert(t *testing.T) {
 	}
 }
 
+func TestServiceRegistration_UpdateChecks(t *testing.T) {
+	ci.Parallel(t)
+
+	s, cleanup := TestServer(t, nil)
+	defer cleanup()
+	codec := rpcClient(t, s)
+	testutil.WaitForLeader(t, s.RPC)
+
+	// Generate a node and some service registrations, ensuring the
+	// registrations share a name, namespace and allocation.
+	node := mock.Node()
+	must.NoError(t, s.State().UpsertNode(structs.MsgTypeTestSetup, 10, node))
+
+	services := mock.ServiceRegistrations()
+	services[1].Namespace = services[0].Namespace
+	services[1].ServiceName = services[0].ServiceName
+	services[1].AllocID = services[0].AllocID
+	must.NoError(t, s.State().UpsertServiceRegistrations(structs.MsgTypeTestSetup, 20, services))
+
+	// Generate the allocation of the services, running on the node, and a
+	// second node.
+	alloc := mock.Alloc()
+	alloc.ID = services[0].AllocID
+	alloc.NodeID = node.ID
+	must.NoError(t, s.State().UpsertJobSummary(15, mock.JobSummary(alloc.JobID)))
+	must.NoError(t, s.State().UpsertAllocs(structs.MsgTypeTestSetup, 16, []*structs.Allocation{alloc}))
+
+	otherNode := mock.Node()
+	must.NoError(t, s.State().UpsertNode(structs.MsgTypeTestSetup, 17, otherNode))
+
+	// Attempt to update the checks without a node secret.
+	updateReq := &structs.ServiceRegistrationUpdateChecksRequest{
+		AllocID: services[0].AllocID,
+		Checks: map[string][]*structs.ServiceRegistrationCheck{
+			services[0].ID: {{Name: "check", Mode: structs.Healthiness, Status: structs.CheckSuccess}},
+			services[1].ID: {{Name: "check", Mode: structs.Healthiness, Status: structs.CheckFailure}},
+		},
+		WriteRequest: structs.WriteRequest{
+			Region:    DefaultRegion,
+			Namespace: services[0].Namespace,
+		},
+	}
+	var updateResp structs.ServiceRegistrationUpdateChecksResponse
+	err := msgpackrpc.CallWithCodec(
+		codec, structs.ServiceRegistrationUpdateChecksRPCMethod, updateReq, &updateResp)
+	must.Error(t, err)
+	must.StrContains(t, err.Error(), "node lookup by SecretID failed")
+
+	// Attempt to update the checks from a node the allocation isn't running
+	// on.
+	updateReq.AuthToken = otherNode.SecretID
+	err = msgpackrpc.CallWithCodec(
+		codec, structs.ServiceRegistrationUpdateChecksRPCMethod, updateReq, &updateResp)
+	must.EqError(t, err, structs.ErrPermissionDenied.Error())
+
+	// Retry with the node secret.
+	updateReq.AuthToken = node.SecretID
+	err = msgpackrpc.CallWithCodec(
+		codec, structs.ServiceRegistrationUpdateChecksRPCMethod, updateReq, &updateResp)
+	must.NoError(t, err)
+	must.Greater(t, 20, updateResp.Index)
+
+	// Lookup the service, with and without the healthy filter.
+	serviceRegReq := &structs.ServiceRegistrationByNameRequest{
+		ServiceName: services[0].ServiceName,
+		QueryOptions: structs.QueryOptions{
+			Namespace: services[0].Namespace,
+			Region:    DefaultRegion,
+		},
+	}
+	var serviceRegResp structs.ServiceRegistrationByNameResponse
+	err = msgpackrpc.CallWithCodec(
+		codec, structs.ServiceRegistrationGetServiceRPCMethod, serviceRegReq, &serviceRegResp)
+	must.NoError(t, err)
+	must.Len(t, 2, serviceRegResp.Services)
+
+	serviceRegReq.Healthy = true
+	err = msgpackrpc.CallWithCodec(
+		codec, structs.ServiceRegistrationGetServiceRPCMethod, serviceRegReq, &serviceRegResp)
+	must.NoError(t, err)
+	must.Len(t, 1, serviceRegResp.Services)
+	must.Eq(t, services[0].ID, serviceRegResp.Services[0].ID)
+}
+
 func TestServiceRegistration_DeleteByID(t *testing.T) {
 	ci.Parallel(t)
 
@@ -713,7 +797,7 @@ func TestServiceRegistration_List(t *testing.T) {
 					ModifyIndex: 5,
 				}
 				ns.SetHash()
-				require.NoError(t, s.State().UpsertNamespaces(5, []*structs.Namespace{ns}))
+				require.NoError(t, s.State().UpsertNamespaces(structs.MsgTypeTestSetup, 5, []*structs.Namespace{ns}))
 
 				// Create a policy and grab the token which has the read-job
 				// capability on the platform namespace.
@@ -766,7 +850,7 @@ func TestServiceRegistration_List(t *testing.T) {
 					ModifyIndex: 5,
 				}
 				ns.SetHash()
-				require.NoError(t, s.State().UpsertNamespaces(5, []*structs.Namespace{ns}))
+				require.NoError(t, s.State().UpsertNamespaces(structs.MsgTypeTestSetup, 5, []*structs.Namespace{ns}))
 
 				// Create a policy and grab the token which has the read policy
 				// on the platform namespace.
@@ -819,7 +903,7 @@ func TestServiceRegistration_List(t *testing.T) {
 					ModifyIndex: 5,
 				}
 				ns.SetHash()
-				require.NoError(t, s.State().UpsertNamespaces(5, []*structs.Namespace{ns}))
+				require.NoError(t, s.State().UpsertNamespaces(structs.MsgTypeTestSetup, 5, []*structs.Namespace{ns}))
 
 				// Generate a node.
 				node := mock.Node()
@@ -877,7 +961,7 @@ func TestServiceRegistration_List(t *testing.T) {
 					ModifyIndex: 5,
 				}
 				ns.SetHash()
-				require.NoError(t, s.State().UpsertNamespaces(5, []*structs.Namespace{ns}))
+				require.NoError(t, s.State().UpsertNamespaces(structs.MsgTypeTestSetup, 5, []*structs.Namespace{ns}))
 
 				// Generate an allocation with a signed identity
 				allocs := []*structs.Allocation{mock.Alloc()}
@@ -1221,6 +1305,24 @@ func TestServiceRegistration_GetService(t *testing.T) {
 				require.NoError(t, err)
 				require.ElementsMatch(t, []*structs.ServiceRegistration{nextServices[0]}, serviceRegResp.Services)
 
+				// Service metadata and weights can be filtered on too.
+				weighted := mock.ServiceRegistrations()[0]
+				weighted.ID += "_weighted"
+				weighted.Meta = map[string]string{"version": "v2"}
+				weighted.Weight = 10
+				require.NoError(t, s.fsm.State().UpsertServiceRegistrations(
+					structs.MsgTypeTestSetup, 30, []*structs.ServiceRegistration{weighted}))
+
+				serviceRegReq.Filter = `"version" in Meta and Meta.version == "v2" and Weight == 10`
+				serviceRegResp = structs.ServiceRegistrationByNameResponse{}
+				err = msgpackrpc.CallWithCodec(
+					codec, structs.ServiceRegistrationGetServiceRPCMethod, serviceRegReq, &serviceRegResp)
+				require.NoError(t, err)
+				require.Len(t, serviceRegResp.Services, 1)
+				require.Equal(t, weighted.ID, serviceRegResp.Services[0].ID)
+				require.NoError(t, s.fsm.State().DeleteServiceRegistrationByID(
+					structs.MsgTypeTestSetup, 40, weighted.Namespace, weighted.ID))
+
 				// Create a test function which can be used for each namespace
 				// to ensure cross-namespace functionality of pagination.
 				namespaceTestFn := func(
@@ -1279,6 +1381,74 @@ func TestServiceRegistration_GetService(t *testing.T) {
 			},
 			name: "filtering and pagination",
 		},
+		{
+			name: "meta",
+			serverFn: func(t *testing.T) (*Server, *structs.ACLToken, func()) {
+				server, cleanup := TestServer(t, nil)
+				return server, nil, cleanup
+			},
+			testFn: func(t *testing.T, s *Server, _ *structs.ACLToken) {
+				codec := rpcClient(t, s)
+				testutil.WaitForLeader(t, s.RPC)
+
+				// Insert two versions of service s1, and a service s2 with
+				// the same metadata
+				newService := func(id, name, version string) *structs.ServiceRegistration {
+					return &structs.ServiceRegistration{
+						ID:          id,
+						Namespace:   "default",
+						ServiceName: name,
+						NodeID:      "node_id",
+						Datacenter:  "dc1",
+						JobID:       "job_id",
+						AllocID:     "alloc_id",
+						Meta:        map[string]string{"version": version},
+						Address:     "10.0.0.1",
+						Port:        9001,
+					}
+				}
+				services := []*structs.ServiceRegistration{
+					newService("id_1", "s1", "v1"),
+					newService("id_2", "s1", "v2"),
+					newService("id_3", "s2", "v2"),
+					{
+						ID:          "id_4",
+						Namespace:   "default",
+						ServiceName: "s1",
+						NodeID:      "node_id",
+						Datacenter:  "dc1",
+						JobID:       "job_id",
+						AllocID:     "alloc_id",
+						Address:     "10.0.0.1",
+						Port:        9001,
+					},
+				}
+				must.NoError(t, s.fsm.State().UpsertServiceRegistrations(structs.MsgTypeTestSetup, 10, services))
+
+				serviceRegReq := &structs.ServiceRegistrationByNameRequest{
+					ServiceName: "s1",
+					MetaKey:     "version",
+					MetaValue:   "v2",
+					QueryOptions: structs.QueryOptions{
+						Namespace: structs.DefaultNamespace,
+						Region:    DefaultRegion,
+					},
+				}
+				var serviceRegResp structs.ServiceRegistrationByNameResponse
+				err := msgpackrpc.CallWithCodec(
+					codec, structs.ServiceRegistrationGetServiceRPCMethod, serviceRegReq, &serviceRegResp)
+				must.NoError(t, err)
+				must.Len(t, 1, serviceRegResp.Services)
+				must.Eq(t, "id_2", serviceRegResp.Services[0].ID)
+
+				// No registration of the service is in another namespace
+				serviceRegReq.Namespace = "platform"
+				err = msgpackrpc.CallWithCodec(
+					codec, structs.ServiceRegistrationGetServiceRPCMethod, serviceRegReq, &serviceRegResp)
+				must.NoError(t, err)
+				must.Len(t, 0, serviceRegResp.Services)
+			},
+		},
 		{
 			name: "choose 2 of 3",
 			serverFn: func(t *testing.T) (*Server, *structs.ACLToken, func()) {
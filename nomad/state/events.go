@@ -31,6 +31,14 @@ var MsgTypeEvents = map[structs.MessageType]string{
 	structs.ServiceRegistrationUpsertRequestType:         structs.TypeServiceRegistration,
 	structs.ServiceRegistrationDeleteByIDRequestType:     structs.TypeServiceDeregistration,
 	structs.ServiceRegistrationDeleteByNodeIDRequestType: structs.TypeServiceDeregistration,
+	structs.ServiceRegistrationUpdateChecksRequestType:   structs.TypeServiceRegistration,
+	structs.VarApplyStateRequestType:                     structs.TypeVariableUpserted,
+	structs.NamespaceUpsertRequestType:                   structs.TypeNamespaceUpserted,
+	structs.NamespaceDeleteRequestType:                   structs.TypeNamespaceDeleted,
+	structs.ACLRolesUpsertRequestType:                    structs.TypeACLRoleUpserted,
+	structs.ACLRolesDeleteByIDRequestType:                structs.TypeACLRoleDeleted,
+	structs.RootKeyMetaUpsertRequestType:                 structs.TypeRootKeyMetaUpserted,
+	structs.RootKeyMetaDeleteRequestType:                 structs.TypeRootKeyMetaDeleted,
 }
 
 func eventsFromChanges(tx ReadTxn, changes Changes) *structs.Events {
@@ -42,7 +50,12 @@ func eventsFromChanges(tx ReadTxn, changes Changes) *structs.Events {
 	var events []structs.Event
 	for _, change := range changes.Changes {
 		if event, ok := eventFromChange(change); ok {
-			event.Type = eventType
+			// Some message types, such as variable operations, can both
+			// upsert and delete objects, so the event type is set when
+			// converting the change.
+			if event.Type == "" {
+				event.Type = eventType
+			}
 			event.Index = changes.Index
 			events = append(events, event)
 		}
@@ -108,6 +121,57 @@ func eventFromChange(change memdb.Change) (structs.Event, bool) {
 					Service: before,
 				},
 			}, true
+		case TableVariables:
+			before, ok := change.Before.(*structs.VariableEncrypted)
+			if !ok {
+				return structs.Event{}, false
+			}
+			return structs.Event{
+				Topic:     structs.TopicVariable,
+				Type:      structs.TypeVariableDeleted,
+				Key:       before.Path,
+				Namespace: before.Namespace,
+				Payload:   newVariableEvent(before),
+			}, true
+		case TableNamespaces:
+			before, ok := change.Before.(*structs.Namespace)
+			if !ok {
+				return structs.Event{}, false
+			}
+			return structs.Event{
+				Topic: structs.TopicNamespace,
+				Key:   before.Name,
+				Payload: &structs.NamespaceEvent{
+					Namespace: before,
+				},
+			}, true
+		case TableACLRoles:
+			before, ok := change.Before.(*structs.ACLRole)
+			if !ok {
+				return structs.Event{}, false
+			}
+			return structs.Event{
+				Topic: structs.TopicACLRole,
+				Key:   before.ID,
+				FilterKeys: []string{
+					before.Name,
+				},
+				Payload: &structs.ACLRoleStreamEvent{
+					ACLRole: before,
+				},
+			}, true
+		case TableRootKeyMeta:
+			before, ok := change.Before.(*structs.RootKeyMeta)
+			if !ok {
+				return structs.Event{}, false
+			}
+			return structs.Event{
+				Topic: structs.TopicRootKey,
+				Key:   before.KeyID,
+				Payload: &structs.RootKeyMetaEvent{
+					RootKeyMeta: before,
+				},
+			}, true
 		}
 		return structs.Event{}, false
 	}
@@ -235,7 +299,67 @@ func eventFromChange(change memdb.Change) (structs.Event, bool) {
 				Service: after,
 			},
 		}, true
+	case TableVariables:
+		after, ok := change.After.(*structs.VariableEncrypted)
+		if !ok {
+			return structs.Event{}, false
+		}
+		return structs.Event{
+			Topic:     structs.TopicVariable,
+			Type:      structs.TypeVariableUpserted,
+			Key:       after.Path,
+			Namespace: after.Namespace,
+			Payload:   newVariableEvent(after),
+		}, true
+	case TableNamespaces:
+		after, ok := change.After.(*structs.Namespace)
+		if !ok {
+			return structs.Event{}, false
+		}
+		return structs.Event{
+			Topic: structs.TopicNamespace,
+			Key:   after.Name,
+			Payload: &structs.NamespaceEvent{
+				Namespace: after,
+			},
+		}, true
+	case TableACLRoles:
+		after, ok := change.After.(*structs.ACLRole)
+		if !ok {
+			return structs.Event{}, false
+		}
+		return structs.Event{
+			Topic: structs.TopicACLRole,
+			Key:   after.ID,
+			FilterKeys: []string{
+				after.Name,
+			},
+			Payload: &structs.ACLRoleStreamEvent{
+				ACLRole: after,
+			},
+		}, true
+	case TableRootKeyMeta:
+		after, ok := change.After.(*structs.RootKeyMeta)
+		if !ok {
+			return structs.Event{}, false
+		}
+		return structs.Event{
+			Topic: structs.TopicRootKey,
+			Key:   after.KeyID,
+			Payload: &structs.RootKeyMetaEvent{
+				RootKeyMeta: after,
+			},
+		}, true
 	}
 
 	return structs.Event{}, false
 }
+
+// newVariableEvent creates a VariableEvent containing only the metadata of the
+// variable, so that the encrypted items are never sent to subscribers.
+func newVariableEvent(sv *structs.VariableEncrypted) *structs.VariableEvent {
+	// The lock ID is only ever returned to the lock holder
+	return &structs.VariableEvent{
+		Variable: sv.VariableMetadata.RedactLockID(),
+	}
+}
@@ -1059,3 +1059,118 @@ func testNodeID() string {
 func testNodeIDTwo() string {
 	return "694ff31d-8c59-4030-ac83-e15692560c8d"
 }
+
+func TestEventsFromChanges_Variable(t *testing.T) {
+	ci.Parallel(t)
+	s := TestStateStoreCfg(t, TestStateStorePublisher(t))
+	defer s.StopEventBroker()
+
+	sv := mock.VariableEncrypted()
+
+	resp := s.VarSet(structs.VarApplyStateRequestType, 10, &structs.VarApplyStateRequest{
+		Op:  structs.VarOpSet,
+		Var: sv,
+	})
+	require.NoError(t, resp.Error)
+
+	events := WaitForEvents(t, s, 10, 1, 1*time.Second)
+	require.Len(t, events, 1)
+
+	got := events[0]
+	require.Equal(t, structs.TopicVariable, got.Topic)
+	require.Equal(t, structs.TypeVariableUpserted, got.Type)
+	require.Equal(t, sv.Path, got.Key)
+	require.Equal(t, sv.Namespace, got.Namespace)
+
+	// only the metadata of the variable is published
+	payload := got.Payload.(*structs.VariableEvent)
+	require.Equal(t, sv.Path, payload.Variable.Path)
+	require.Equal(t, uint64(10), payload.Variable.ModifyIndex)
+
+	resp = s.VarDelete(structs.VarApplyStateRequestType, 20, &structs.VarApplyStateRequest{
+		Op:  structs.VarOpDelete,
+		Var: sv,
+	})
+	require.NoError(t, resp.Error)
+
+	events = WaitForEvents(t, s, 20, 1, 1*time.Second)
+	require.Len(t, events, 1)
+	require.Equal(t, structs.TopicVariable, events[0].Topic)
+	require.Equal(t, structs.TypeVariableDeleted, events[0].Type)
+	require.Equal(t, sv.Path, events[0].Key)
+}
+
+func TestEventsFromChanges_Namespace(t *testing.T) {
+	ci.Parallel(t)
+	s := TestStateStoreCfg(t, TestStateStorePublisher(t))
+	defer s.StopEventBroker()
+
+	ns := mock.Namespace()
+
+	require.NoError(t, s.UpsertNamespaces(structs.NamespaceUpsertRequestType, 10, []*structs.Namespace{ns}))
+
+	events := WaitForEvents(t, s, 10, 1, 1*time.Second)
+	require.Len(t, events, 1)
+	require.Equal(t, structs.TopicNamespace, events[0].Topic)
+	require.Equal(t, structs.TypeNamespaceUpserted, events[0].Type)
+	require.Equal(t, ns.Name, events[0].Key)
+	require.Equal(t, ns.Name, events[0].Payload.(*structs.NamespaceEvent).Namespace.Name)
+
+	require.NoError(t, s.DeleteNamespaces(structs.NamespaceDeleteRequestType, 20, []string{ns.Name}))
+
+	events = WaitForEvents(t, s, 20, 1, 1*time.Second)
+	require.Len(t, events, 1)
+	require.Equal(t, structs.TopicNamespace, events[0].Topic)
+	require.Equal(t, structs.TypeNamespaceDeleted, events[0].Type)
+	require.Equal(t, ns.Name, events[0].Key)
+}
+
+func TestEventsFromChanges_ACLRole(t *testing.T) {
+	ci.Parallel(t)
+	s := TestStateStoreCfg(t, TestStateStorePublisher(t))
+	defer s.StopEventBroker()
+
+	role := mock.ACLRole()
+
+	require.NoError(t, s.UpsertACLRoles(structs.ACLRolesUpsertRequestType, 10, []*structs.ACLRole{role}, true))
+
+	events := WaitForEvents(t, s, 10, 1, 1*time.Second)
+	require.Len(t, events, 1)
+	require.Equal(t, structs.TopicACLRole, events[0].Topic)
+	require.Equal(t, structs.TypeACLRoleUpserted, events[0].Type)
+	require.Equal(t, role.ID, events[0].Key)
+	require.Equal(t, []string{role.Name}, events[0].FilterKeys)
+
+	require.NoError(t, s.DeleteACLRolesByID(structs.ACLRolesDeleteByIDRequestType, 20, []string{role.ID}))
+
+	events = WaitForEvents(t, s, 20, 1, 1*time.Second)
+	require.Len(t, events, 1)
+	require.Equal(t, structs.TopicACLRole, events[0].Topic)
+	require.Equal(t, structs.TypeACLRoleDeleted, events[0].Type)
+	require.Equal(t, role.ID, events[0].Key)
+}
+
+func TestEventsFromChanges_RootKeyMeta(t *testing.T) {
+	ci.Parallel(t)
+	s := TestStateStoreCfg(t, TestStateStorePublisher(t))
+	defer s.StopEventBroker()
+
+	key := structs.NewRootKeyMeta()
+	key.SetActive()
+
+	require.NoError(t, s.UpsertRootKeyMeta(structs.RootKeyMetaUpsertRequestType, 10, key, false))
+
+	events := WaitForEvents(t, s, 10, 1, 1*time.Second)
+	require.Len(t, events, 1)
+	require.Equal(t, structs.TopicRootKey, events[0].Topic)
+	require.Equal(t, structs.TypeRootKeyMetaUpserted, events[0].Type)
+	require.Equal(t, key.KeyID, events[0].Key)
+
+	require.NoError(t, s.DeleteRootKeyMeta(structs.RootKeyMetaDeleteRequestType, 20, key.KeyID))
+
+	events = WaitForEvents(t, s, 20, 1, 1*time.Second)
+	require.Len(t, events, 1)
+	require.Equal(t, structs.TopicRootKey, events[0].Topic)
+	require.Equal(t, structs.TypeRootKeyMetaDeleted, events[0].Type)
+	require.Equal(t, key.KeyID, events[0].Key)
+}
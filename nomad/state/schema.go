@@ -16,6 +16,7 @@ const (
 	TableServiceRegistrations = "service_registrations"
 	TableVariables            = "variables"
 	TableVariablesQuotas      = "variables_quota"
+	TableVariablesVersions    = "variables_versions"
 	TableRootKeyMeta          = "root_key_meta"
 	TableACLRoles             = "acl_roles"
 )
@@ -26,6 +27,7 @@ const (
 	indexNodeID        = "node_id"
 	indexAllocID       = "alloc_id"
 	indexServiceName   = "service_name"
+	indexMeta          = "meta"
 	indexExpiresGlobal = "expires-global"
 	indexExpiresLocal  = "expires-local"
 	indexKeyID         = "key_id"
@@ -81,6 +83,7 @@ func init() {
 		serviceRegistrationsTableSchema,
 		variablesTableSchema,
 		variablesQuotasTableSchema,
+		variablesVersionsTableSchema,
 		variablesRootKeyMetaSchema,
 		aclRolesTableSchema,
 	}...)
@@ -1332,6 +1335,17 @@ func serviceRegistrationsTableSchema() *memdb.TableSchema {
 					Field: "AllocID",
 				},
 			},
+			// The meta index allows looking up registrations by a key and
+			// value of their metadata. Map indexes can't be compounded, so
+			// the namespace is filtered on lookup.
+			indexMeta: {
+				Name:         indexMeta,
+				AllowMissing: true,
+				Unique:       false,
+				Indexer: &memdb.StringMapFieldIndex{
+					Field: "Meta",
+				},
+			},
 		},
 	}
 }
@@ -1361,6 +1375,12 @@ func variablesTableSchema() *memdb.TableSchema {
 				AllowMissing: false,
 				Indexer:      &variableKeyIDFieldIndexer{},
 			},
+			indexAllocID: {
+				Name:         indexAllocID,
+				AllowMissing: true,
+				Unique:       false,
+				Indexer:      &variableLockAllocIDFieldIndexer{},
+			},
 			indexPath: {
 				Name:         indexPath,
 				AllowMissing: false,
@@ -1425,6 +1445,30 @@ func (s *variableKeyIDFieldIndexer) FromObject(obj interface{}) (bool, []byte, e
 	return true, []byte(keyID), nil
 }
 
+// variableLockAllocIDFieldIndexer indexes variables by the ID of the
+// allocation holding a lock on them. Variables that are not locked, or whose
+// lock is not held by an allocation, are missing from the index.
+type variableLockAllocIDFieldIndexer struct {
+	variableKeyIDFieldIndexer
+}
+
+// FromObject implements go-memdb/SingleIndexer and is used to extract
+// an index value from an object or to indicate that the index value
+// is missing.
+func (s *variableLockAllocIDFieldIndexer) FromObject(obj interface{}) (bool, []byte, error) {
+	variable, ok := obj.(*structs.VariableEncrypted)
+	if !ok {
+		return false, nil, fmt.Errorf("object %#v is not a Variable", obj)
+	}
+
+	if variable.Lock == nil || variable.Lock.AllocID == "" {
+		return false, nil, nil
+	}
+
+	// Add the null character as a terminator
+	return true, []byte(variable.Lock.AllocID + "\x00"), nil
+}
+
 // variablesQuotasTableSchema returns the MemDB schema for Nomad variables
 // quotas tracking
 func variablesQuotasTableSchema() *memdb.TableSchema {
@@ -1444,6 +1488,39 @@ func variablesQuotasTableSchema() *memdb.TableSchema {
 	}
 }
 
+// variablesVersionsTableSchema returns the MemDB schema for the retained prior
+// versions of Nomad variables
+func variablesVersionsTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: TableVariablesVersions,
+		Indexes: map[string]*memdb.IndexSchema{
+			indexID: {
+				Name:         indexID,
+				AllowMissing: false,
+				Unique:       true,
+				Indexer: &memdb.CompoundIndex{
+					Indexes: []memdb.Indexer{
+						&memdb.StringFieldIndex{
+							Field: "Namespace",
+						},
+						&memdb.StringFieldIndex{
+							Field: "Path",
+						},
+						&memdb.UintFieldIndex{
+							Field: "ModifyIndex",
+						},
+					},
+				},
+			},
+			indexKeyID: {
+				Name:         indexKeyID,
+				AllowMissing: false,
+				Indexer:      &variableKeyIDFieldIndexer{},
+			},
+		},
+	}
+}
+
 // variablesRootKeyMetaSchema returns the MemDB schema for Nomad root keys
 func variablesRootKeyMetaSchema() *memdb.TableSchema {
 	return &memdb.TableSchema{
@@ -184,7 +184,7 @@ func (s *StateStore) namespaceInit() error {
 		Description: structs.DefaultNamespaceDescription,
 	}
 
-	if err := s.UpsertNamespaces(1, []*structs.Namespace{defaultNs}); err != nil {
+	if err := s.UpsertNamespaces(structs.IgnoreUnknownTypeFlag, 1, []*structs.Namespace{defaultNs}); err != nil {
 		return fmt.Errorf("inserting default namespace failed: %v", err)
 	}
 
@@ -3521,6 +3521,10 @@ func (s *StateStore) nestedUpdateAllocFromClient(txn *txn, index uint64, alloc *
 		return err
 	}
 
+	if err := s.releaseVarLocksForTerminalAlloc(index, copyAlloc, txn); err != nil {
+		return err
+	}
+
 	// Update the allocation
 	if err := txn.Insert("allocs", copyAlloc); err != nil {
 		return fmt.Errorf("alloc insert failed: %v", err)
@@ -3631,6 +3635,10 @@ func (s *StateStore) upsertAllocsImpl(index uint64, allocs []*structs.Allocation
 			return err
 		}
 
+		if err := s.releaseVarLocksForTerminalAlloc(index, alloc, txn); err != nil {
+			return err
+		}
+
 		if err := txn.Insert("allocs", alloc); err != nil {
 			return fmt.Errorf("alloc insert failed: %v", err)
 		}
@@ -6270,8 +6278,8 @@ func (s *StateStore) NamespaceNames() ([]string, error) {
 }
 
 // UpsertNamespaces is used to register or update a set of namespaces.
-func (s *StateStore) UpsertNamespaces(index uint64, namespaces []*structs.Namespace) error {
-	txn := s.db.WriteTxn(index)
+func (s *StateStore) UpsertNamespaces(msgType structs.MessageType, index uint64, namespaces []*structs.Namespace) error {
+	txn := s.db.WriteTxnMsgT(msgType, index)
 	defer txn.Abort()
 
 	for _, ns := range namespaces {
@@ -6337,8 +6345,8 @@ func (s *StateStore) upsertNamespaceImpl(index uint64, txn *txn, namespace *stru
 }
 
 // DeleteNamespaces is used to remove a set of namespaces
-func (s *StateStore) DeleteNamespaces(index uint64, names []string) error {
-	txn := s.db.WriteTxn(index)
+func (s *StateStore) DeleteNamespaces(msgType structs.MessageType, index uint64, names []string) error {
+	txn := s.db.WriteTxnMsgT(msgType, index)
 	defer txn.Abort()
 
 	for _, name := range names {
@@ -6721,8 +6729,8 @@ func getPreemptedAllocDesiredDescription(preemptedByAllocID string) string {
 }
 
 // UpsertRootKeyMeta saves root key meta or updates it in-place.
-func (s *StateStore) UpsertRootKeyMeta(index uint64, rootKeyMeta *structs.RootKeyMeta, rekey bool) error {
-	txn := s.db.WriteTxn(index)
+func (s *StateStore) UpsertRootKeyMeta(msgType structs.MessageType, index uint64, rootKeyMeta *structs.RootKeyMeta, rekey bool) error {
+	txn := s.db.WriteTxnMsgT(msgType, index)
 	defer txn.Abort()
 
 	// get any existing key for updating
@@ -6803,8 +6811,8 @@ func (s *StateStore) UpsertRootKeyMeta(index uint64, rootKeyMeta *structs.RootKe
 
 // DeleteRootKeyMeta deletes a single root key, or returns an error if
 // it doesn't exist.
-func (s *StateStore) DeleteRootKeyMeta(index uint64, keyID string) error {
-	txn := s.db.WriteTxn(index)
+func (s *StateStore) DeleteRootKeyMeta(msgType structs.MessageType, index uint64, keyID string) error {
+	txn := s.db.WriteTxnMsgT(msgType, index)
 	defer txn.Abort()
 
 	// find the old key
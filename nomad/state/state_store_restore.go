@@ -207,6 +207,15 @@ func (r *StateRestore) VariablesRestore(variable *structs.VariableEncrypted) err
 	return nil
 }
 
+// VariablesVersionsRestore is used to restore a single prior version of a
+// variable into the variables_versions table.
+func (r *StateRestore) VariablesVersionsRestore(version *structs.VariableEncrypted) error {
+	if err := r.txn.Insert(TableVariablesVersions, version); err != nil {
+		return fmt.Errorf("variable version insert failed: %v", err)
+	}
+	return nil
+}
+
 // VariablesQuotaRestore is used to restore a single variable quota into the
 // variables_quota table.
 func (r *StateRestore) VariablesQuotaRestore(quota *structs.VariablesQuota) error {
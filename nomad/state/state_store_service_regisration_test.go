@@ -141,6 +141,87 @@ func TestStateStore_UpsertServiceRegistrations(t *testing.T) {
 		require.Equal(t, expectedModifyIndex, serviceReg.ModifyIndex, "incorrect modify index", serviceReg.ID)
 		require.True(t, expectedServiceReg.Equals(serviceReg))
 	}
+
+	// SubTest Marker: Modifying only the meta and weight of a registration
+	// must be detected as a change and persisted.
+	service3Update := service1Update.Copy()
+	service3Update.Meta = map[string]string{"version": "2"}
+	service3Update.Weight = 10
+
+	update3Index := uint64(60)
+	require.NoError(t, testState.UpsertServiceRegistrations(
+		structs.MsgTypeTestSetup, update3Index, []*structs.ServiceRegistration{service3Update}))
+
+	update3ActualIndex, err := testState.Index(TableServiceRegistrations)
+	require.NoError(t, err)
+	require.Equal(t, update3Index, update3ActualIndex, "index should have changed")
+
+	serviceReg, err := testState.GetServiceRegistrationByID(ws, service3Update.Namespace, service3Update.ID)
+	require.NoError(t, err)
+	require.Equal(t, update3Index, serviceReg.ModifyIndex)
+	require.Equal(t, map[string]string{"version": "2"}, serviceReg.Meta)
+	require.Equal(t, 10, serviceReg.Weight)
+}
+
+func TestStateStore_UpdateServiceRegistrationChecks(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	// Generate some test services and insert them into state.
+	services := mock.ServiceRegistrations()
+	insertIndex := uint64(10)
+	require.NoError(t, testState.UpsertServiceRegistrations(structs.MsgTypeTestSetup, insertIndex, services))
+
+	checks := []*structs.ServiceRegistrationCheck{
+		{Name: "db-check", Mode: structs.Healthiness, Status: structs.CheckSuccess, Timestamp: 100},
+	}
+
+	// SubTest Marker: This section updates the checks of a registration,
+	// ensuring the registration and index table are updated.
+	update1Index := uint64(20)
+	require.NoError(t, testState.UpdateServiceRegistrationChecks(
+		structs.MsgTypeTestSetup, update1Index, services[0].Namespace, services[0].AllocID,
+		map[string][]*structs.ServiceRegistrationCheck{services[0].ID: checks}))
+
+	actualIndex, err := testState.Index(TableServiceRegistrations)
+	require.NoError(t, err)
+	require.Equal(t, update1Index, actualIndex, "index should have changed")
+
+	ws := memdb.NewWatchSet()
+	serviceReg, err := testState.GetServiceRegistrationByID(ws, services[0].Namespace, services[0].ID)
+	require.NoError(t, err)
+	require.Equal(t, checks, serviceReg.Checks)
+	require.Equal(t, insertIndex, serviceReg.CreateIndex)
+	require.Equal(t, update1Index, serviceReg.ModifyIndex)
+	require.True(t, serviceReg.Healthy())
+
+	// SubTest Marker: This section performs the same update again, which
+	// should not modify the index table.
+	require.NoError(t, testState.UpdateServiceRegistrationChecks(
+		structs.MsgTypeTestSetup, 30, services[0].Namespace, services[0].AllocID,
+		map[string][]*structs.ServiceRegistrationCheck{services[0].ID: checks}))
+
+	actualIndex, err = testState.Index(TableServiceRegistrations)
+	require.NoError(t, err)
+	require.Equal(t, update1Index, actualIndex, "index should not have changed")
+
+	// SubTest Marker: This section attempts to update the checks of a
+	// registration using the wrong allocation ID and a registration that does
+	// not exist. Neither should modify state.
+	require.NoError(t, testState.UpdateServiceRegistrationChecks(
+		structs.MsgTypeTestSetup, 40, services[1].Namespace, services[0].AllocID,
+		map[string][]*structs.ServiceRegistrationCheck{
+			services[1].ID:   checks,
+			"does-not-exist": checks,
+		}))
+
+	actualIndex, err = testState.Index(TableServiceRegistrations)
+	require.NoError(t, err)
+	require.Equal(t, update1Index, actualIndex, "index should not have changed")
+
+	serviceReg, err = testState.GetServiceRegistrationByID(ws, services[1].Namespace, services[1].ID)
+	require.NoError(t, err)
+	require.Nil(t, serviceReg.Checks)
 }
 
 func TestStateStore_DeleteServiceRegistrationByID(t *testing.T) {
@@ -595,6 +676,50 @@ func TestStateStore_GetServiceRegistrationsByJobID(t *testing.T) {
 	require.ElementsMatch(t, outputList2, []*structs.ServiceRegistration{services[1]})
 }
 
+func TestStateStore_GetServiceRegistrationsByMeta(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	// Generate some test services, sharing a meta value across namespaces,
+	// and upsert them.
+	services := mock.ServiceRegistrations()
+	services[0].Meta = map[string]string{"version": "v2", "team": "cache"}
+	services[0].Weight = 10
+	services[1].Meta = map[string]string{"version": "v2"}
+	initialIndex := uint64(10)
+	require.NoError(t, testState.UpsertServiceRegistrations(structs.MsgTypeTestSetup, initialIndex, services))
+
+	collect := func(namespace, key, value string) []*structs.ServiceRegistration {
+		iter, err := testState.GetServiceRegistrationsByMeta(memdb.NewWatchSet(), namespace, key, value)
+		require.NoError(t, err)
+
+		var out []*structs.ServiceRegistration
+		for raw := iter.Next(); raw != nil; raw = iter.Next() {
+			out = append(out, raw.(*structs.ServiceRegistration))
+		}
+		return out
+	}
+
+	// Lookups are scoped to the namespace and match both the key and value.
+	out := collect(services[0].Namespace, "version", "v2")
+	require.ElementsMatch(t, []*structs.ServiceRegistration{services[0]}, out)
+	require.Equal(t, 10, out[0].Weight)
+	require.ElementsMatch(t, []*structs.ServiceRegistration{services[1]}, collect(services[1].Namespace, "version", "v2"))
+	require.Empty(t, collect(services[1].Namespace, "team", "cache"))
+	require.Empty(t, collect(services[0].Namespace, "version", "v1"))
+
+	// The wildcard namespace matches registrations of all namespaces.
+	require.ElementsMatch(t, services, collect(structs.AllNamespacesSentinel, "version", "v2"))
+
+	// Updating the meta of a registration updates the index.
+	updated := services[0].Copy()
+	updated.Meta = map[string]string{"version": "v3"}
+	require.NoError(t, testState.UpsertServiceRegistrations(
+		structs.MsgTypeTestSetup, initialIndex+10, []*structs.ServiceRegistration{updated}))
+	require.Empty(t, collect(services[0].Namespace, "version", "v2"))
+	require.Len(t, collect(services[0].Namespace, "version", "v3"), 1)
+}
+
 func TestStateStore_GetServiceRegistrationsByNodeID(t *testing.T) {
 	ci.Parallel(t)
 	testState := testStateStore(t)
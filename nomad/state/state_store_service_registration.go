@@ -77,6 +77,63 @@ func (s *StateStore) upsertServiceRegistrationTxn(
 	return true, nil
 }
 
+// UpdateServiceRegistrationChecks is used to update the check results held
+// on the service registrations of a single allocation. Registrations which
+// cannot be found are skipped, as the client may report check results before
+// the registration has been written, or after it has been removed.
+func (s *StateStore) UpdateServiceRegistrationChecks(
+	msgType structs.MessageType, index uint64, namespace, allocID string,
+	checks map[string][]*structs.ServiceRegistrationCheck) error {
+
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	// updated tracks whether any registrations have been modified. This allows
+	// us to skip updating the index table if we do not need to.
+	var updated bool
+
+	for id, results := range checks {
+		existing, err := txn.First(TableServiceRegistrations, indexID, namespace, id)
+		if err != nil {
+			return fmt.Errorf("service registration lookup failed: %v", err)
+		}
+		if existing == nil {
+			continue
+		}
+
+		// Guard against a caller updating the checks of a registration which
+		// does not belong to the allocation it specified.
+		exist := existing.(*structs.ServiceRegistration)
+		if exist.AllocID != allocID {
+			continue
+		}
+
+		// Copy the existing object, so we do not modify the object held in
+		// state, and perform the upsert. The upsert is responsible for
+		// detecting no-op updates.
+		service := exist.Copy()
+		service.Checks = results
+
+		serviceUpdated, err := s.upsertServiceRegistrationTxn(index, txn, service)
+		if err != nil {
+			return err
+		}
+		updated = updated || serviceUpdated
+	}
+
+	// If we did not perform any updates, exit early.
+	if !updated {
+		return nil
+	}
+
+	// Perform the index table update to mark the updates.
+	if err := txn.Insert(tableIndex, &IndexEntry{TableServiceRegistrations, index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	return txn.Commit()
+}
+
 // DeleteServiceRegistrationByID is responsible for deleting a single service
 // registration based on it's ID and namespace. If the service registration is
 // not found within state, an error will be returned.
@@ -196,6 +253,29 @@ func (s *StateStore) GetServiceRegistrationByName(
 	return iter, nil
 }
 
+// GetServiceRegistrationsByMeta returns an iterator containing all the
+// service registrations within the namespace whose metadata holds the value
+// for the key. The wildcard namespace returns matching registrations of all
+// namespaces, which the caller is responsible for filtering by ACL access.
+func (s *StateStore) GetServiceRegistrationsByMeta(
+	ws memdb.WatchSet, namespace, key, value string) (memdb.ResultIterator, error) {
+
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get(TableServiceRegistrations, indexMeta, key, value)
+	if err != nil {
+		return nil, fmt.Errorf("service registration lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	if namespace == structs.AllNamespacesSentinel {
+		return iter, nil
+	}
+	return memdb.NewFilterIterator(iter, func(raw interface{}) bool {
+		return raw.(*structs.ServiceRegistration).Namespace != namespace
+	}), nil
+}
+
 // GetServiceRegistrationByID returns a single registration. The registration
 // will be nil, if no matching entry was found; it is the responsibility of the
 // caller to check for this.
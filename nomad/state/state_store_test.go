@@ -705,7 +705,7 @@ func TestStateStore_Deployments_Namespace(t *testing.T) {
 	deploy3.Namespace = ns2.Name
 	deploy4.Namespace = ns2.Name
 
-	require.NoError(t, state.UpsertNamespaces(998, []*structs.Namespace{ns1, ns2}))
+	require.NoError(t, state.UpsertNamespaces(structs.MsgTypeTestSetup, 998, []*structs.Namespace{ns1, ns2}))
 
 	// Create watchsets so we can test that update fires the watch
 	watches := []memdb.WatchSet{memdb.NewWatchSet(), memdb.NewWatchSet()}
@@ -864,7 +864,7 @@ func TestStateStore_DeploymentsByIDPrefix_Namespaces(t *testing.T) {
 	deploy1.Namespace = ns1.Name
 	deploy2.Namespace = ns2.Name
 
-	require.NoError(t, state.UpsertNamespaces(998, []*structs.Namespace{ns1, ns2}))
+	require.NoError(t, state.UpsertNamespaces(structs.MsgTypeTestSetup, 998, []*structs.Namespace{ns1, ns2}))
 	require.NoError(t, state.UpsertDeployment(1000, deploy1))
 	require.NoError(t, state.UpsertDeployment(1001, deploy2))
 
@@ -912,7 +912,7 @@ func TestStateStore_UpsertNamespaces(t *testing.T) {
 	_, err := state.NamespaceByName(ws, ns1.Name)
 	require.NoError(t, err)
 
-	require.NoError(t, state.UpsertNamespaces(1000, []*structs.Namespace{ns1, ns2}))
+	require.NoError(t, state.UpsertNamespaces(structs.MsgTypeTestSetup, 1000, []*structs.Namespace{ns1, ns2}))
 	require.True(t, watchFired(ws))
 
 	ws = memdb.NewWatchSet()
@@ -937,14 +937,14 @@ func TestStateStore_DeleteNamespaces(t *testing.T) {
 	ns1 := mock.Namespace()
 	ns2 := mock.Namespace()
 
-	require.NoError(t, state.UpsertNamespaces(1000, []*structs.Namespace{ns1, ns2}))
+	require.NoError(t, state.UpsertNamespaces(structs.MsgTypeTestSetup, 1000, []*structs.Namespace{ns1, ns2}))
 
 	// Create a watchset so we can test that delete fires the watch
 	ws := memdb.NewWatchSet()
 	_, err := state.NamespaceByName(ws, ns1.Name)
 	require.NoError(t, err)
 
-	require.NoError(t, state.DeleteNamespaces(1001, []string{ns1.Name, ns2.Name}))
+	require.NoError(t, state.DeleteNamespaces(structs.MsgTypeTestSetup, 1001, []string{ns1.Name, ns2.Name}))
 	require.True(t, watchFired(ws))
 
 	ws = memdb.NewWatchSet()
@@ -969,9 +969,9 @@ func TestStateStore_DeleteNamespaces_Default(t *testing.T) {
 
 	ns := mock.Namespace()
 	ns.Name = structs.DefaultNamespace
-	require.NoError(t, state.UpsertNamespaces(1000, []*structs.Namespace{ns}))
+	require.NoError(t, state.UpsertNamespaces(structs.MsgTypeTestSetup, 1000, []*structs.Namespace{ns}))
 
-	err := state.DeleteNamespaces(1002, []string{ns.Name})
+	err := state.DeleteNamespaces(structs.MsgTypeTestSetup, 1002, []string{ns.Name})
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "can not be deleted")
 }
@@ -982,7 +982,7 @@ func TestStateStore_DeleteNamespaces_NonTerminalJobs(t *testing.T) {
 	state := testStateStore(t)
 
 	ns := mock.Namespace()
-	require.NoError(t, state.UpsertNamespaces(1000, []*structs.Namespace{ns}))
+	require.NoError(t, state.UpsertNamespaces(structs.MsgTypeTestSetup, 1000, []*structs.Namespace{ns}))
 
 	job := mock.Job()
 	job.Namespace = ns.Name
@@ -993,7 +993,7 @@ func TestStateStore_DeleteNamespaces_NonTerminalJobs(t *testing.T) {
 	_, err := state.NamespaceByName(ws, ns.Name)
 	require.NoError(t, err)
 
-	err = state.DeleteNamespaces(1002, []string{ns.Name})
+	err = state.DeleteNamespaces(structs.MsgTypeTestSetup, 1002, []string{ns.Name})
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "one non-terminal")
 	require.False(t, watchFired(ws))
@@ -1015,7 +1015,7 @@ func TestStateStore_DeleteNamespaces_CSIVolumes(t *testing.T) {
 	state := testStateStore(t)
 
 	ns := mock.Namespace()
-	require.NoError(t, state.UpsertNamespaces(1000, []*structs.Namespace{ns}))
+	require.NoError(t, state.UpsertNamespaces(structs.MsgTypeTestSetup, 1000, []*structs.Namespace{ns}))
 
 	plugin := mock.CSIPlugin()
 	vol := mock.CSIVolume(plugin)
@@ -1028,7 +1028,7 @@ func TestStateStore_DeleteNamespaces_CSIVolumes(t *testing.T) {
 	_, err := state.NamespaceByName(ws, ns.Name)
 	require.NoError(t, err)
 
-	err = state.DeleteNamespaces(1002, []string{ns.Name})
+	err = state.DeleteNamespaces(structs.MsgTypeTestSetup, 1002, []string{ns.Name})
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "one CSI volume")
 	require.False(t, watchFired(ws))
@@ -1050,12 +1050,12 @@ func TestStateStore_DeleteNamespaces_Variables(t *testing.T) {
 	state := testStateStore(t)
 
 	ns := mock.Namespace()
-	require.NoError(t, state.UpsertNamespaces(1000, []*structs.Namespace{ns}))
+	require.NoError(t, state.UpsertNamespaces(structs.MsgTypeTestSetup, 1000, []*structs.Namespace{ns}))
 
 	sv := mock.VariableEncrypted()
 	sv.Namespace = ns.Name
 
-	resp := state.VarSet(1001, &structs.VarApplyStateRequest{
+	resp := state.VarSet(structs.MsgTypeTestSetup, 1001, &structs.VarApplyStateRequest{
 		Op:  structs.VarOpSet,
 		Var: sv,
 	})
@@ -1066,7 +1066,7 @@ func TestStateStore_DeleteNamespaces_Variables(t *testing.T) {
 	_, err := state.NamespaceByName(ws, ns.Name)
 	require.NoError(t, err)
 
-	err = state.DeleteNamespaces(1002, []string{ns.Name})
+	err = state.DeleteNamespaces(structs.MsgTypeTestSetup, 1002, []string{ns.Name})
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "one variable")
 	require.False(t, watchFired(ws))
@@ -1093,7 +1093,7 @@ func TestStateStore_Namespaces(t *testing.T) {
 		namespaces = append(namespaces, ns)
 	}
 
-	require.NoError(t, state.UpsertNamespaces(1000, namespaces))
+	require.NoError(t, state.UpsertNamespaces(structs.MsgTypeTestSetup, 1000, namespaces))
 
 	// Create a watchset so we can test that getters don't cause it to fire
 	ws := memdb.NewWatchSet()
@@ -1132,7 +1132,7 @@ func TestStateStore_NamespaceNames(t *testing.T) {
 		expectedNames = append(expectedNames, ns.Name)
 	}
 
-	err := state.UpsertNamespaces(1000, namespaces)
+	err := state.UpsertNamespaces(structs.MsgTypeTestSetup, 1000, namespaces)
 	require.NoError(t, err)
 
 	found, err := state.NamespaceNames()
@@ -1151,7 +1151,7 @@ func TestStateStore_NamespaceByNamePrefix(t *testing.T) {
 	ns := mock.Namespace()
 
 	ns.Name = "foobar"
-	require.NoError(t, state.UpsertNamespaces(1000, []*structs.Namespace{ns}))
+	require.NoError(t, state.UpsertNamespaces(structs.MsgTypeTestSetup, 1000, []*structs.Namespace{ns}))
 
 	// Create a watchset so we can test that getters don't cause it to fire
 	ws := memdb.NewWatchSet()
@@ -1183,7 +1183,7 @@ func TestStateStore_NamespaceByNamePrefix(t *testing.T) {
 
 	ns = mock.Namespace()
 	ns.Name = "foozip"
-	err = state.UpsertNamespaces(1001, []*structs.Namespace{ns})
+	err = state.UpsertNamespaces(structs.MsgTypeTestSetup, 1001, []*structs.Namespace{ns})
 	require.NoError(t, err)
 	require.True(t, watchFired(ws))
 
@@ -2641,7 +2641,7 @@ func TestStateStore_JobsByIDPrefix_Namespaces(t *testing.T) {
 	job1.Namespace = ns1.Name
 	job2.Namespace = ns2.Name
 
-	require.NoError(t, state.UpsertNamespaces(998, []*structs.Namespace{ns1, ns2}))
+	require.NoError(t, state.UpsertNamespaces(structs.MsgTypeTestSetup, 998, []*structs.Namespace{ns1, ns2}))
 	require.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1000, job1))
 	require.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1001, job2))
 
@@ -2724,7 +2724,7 @@ func TestStateStore_JobsByNamespace(t *testing.T) {
 	job3.Namespace = ns2.Name
 	job4.Namespace = ns2.Name
 
-	require.NoError(t, state.UpsertNamespaces(998, []*structs.Namespace{ns1, ns2}))
+	require.NoError(t, state.UpsertNamespaces(structs.MsgTypeTestSetup, 998, []*structs.Namespace{ns1, ns2}))
 
 	// Create watchsets so we can test that update fires the watch
 	watches := []memdb.WatchSet{memdb.NewWatchSet(), memdb.NewWatchSet()}
@@ -4030,7 +4030,7 @@ func TestStateStore_UpsertEvals_Namespace(t *testing.T) {
 	eval3.Namespace = ns2.Name
 	eval4.Namespace = ns2.Name
 
-	require.NoError(t, state.UpsertNamespaces(998, []*structs.Namespace{ns1, ns2}))
+	require.NoError(t, state.UpsertNamespaces(structs.MsgTypeTestSetup, 998, []*structs.Namespace{ns1, ns2}))
 
 	// Create watchsets so we can test that update fires the watch
 	watches := []memdb.WatchSet{memdb.NewWatchSet(), memdb.NewWatchSet()}
@@ -4689,7 +4689,7 @@ func TestStateStore_EvalsByIDPrefix_Namespaces(t *testing.T) {
 	eval1.Namespace = ns1.Name
 	eval2.Namespace = ns2.Name
 
-	require.NoError(t, state.UpsertNamespaces(998, []*structs.Namespace{ns1, ns2}))
+	require.NoError(t, state.UpsertNamespaces(structs.MsgTypeTestSetup, 998, []*structs.Namespace{ns1, ns2}))
 	require.NoError(t, state.UpsertEvals(structs.MsgTypeTestSetup, 1000, []*structs.Evaluation{eval1, eval2}))
 
 	gatherEvals := func(iter memdb.ResultIterator) []*structs.Evaluation {
@@ -5378,7 +5378,7 @@ func TestStateStore_UpsertAlloc_AllocsByNamespace(t *testing.T) {
 	alloc4.Namespace = ns2.Name
 	alloc4.Job.Namespace = ns2.Name
 
-	require.NoError(t, state.UpsertNamespaces(998, []*structs.Namespace{ns1, ns2}))
+	require.NoError(t, state.UpsertNamespaces(structs.MsgTypeTestSetup, 998, []*structs.Namespace{ns1, ns2}))
 	require.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 999, alloc1.Job))
 	require.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1000, alloc3.Job))
 
@@ -6462,7 +6462,7 @@ func TestStateStore_AllocsByIDPrefix_Namespaces(t *testing.T) {
 	alloc1.Namespace = ns1.Name
 	alloc2.Namespace = ns2.Name
 
-	require.NoError(t, state.UpsertNamespaces(998, []*structs.Namespace{ns1, ns2}))
+	require.NoError(t, state.UpsertNamespaces(structs.MsgTypeTestSetup, 998, []*structs.Namespace{ns1, ns2}))
 	require.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 1000, []*structs.Allocation{alloc1, alloc2}))
 
 	gatherAllocs := func(iter memdb.ResultIterator) []*structs.Allocation {
@@ -9996,7 +9996,7 @@ func TestStateStore_RootKeyMetaData_CRUD(t *testing.T) {
 			key.SetActive()
 		}
 		index++
-		require.NoError(t, store.UpsertRootKeyMeta(index, key, false))
+		require.NoError(t, store.UpsertRootKeyMeta(structs.MsgTypeTestSetup, index, key, false))
 	}
 
 	// retrieve the active key
@@ -10012,7 +10012,7 @@ func TestStateStore_RootKeyMetaData_CRUD(t *testing.T) {
 	newlyActiveKey := inactiveKey.Copy()
 	newlyActiveKey.SetActive()
 	index++
-	require.NoError(t, store.UpsertRootKeyMeta(index, newlyActiveKey, false))
+	require.NoError(t, store.UpsertRootKeyMeta(structs.MsgTypeTestSetup, index, newlyActiveKey, false))
 
 	iter, err := store.RootKeyMetas(nil)
 	require.NoError(t, err)
@@ -10032,7 +10032,7 @@ func TestStateStore_RootKeyMetaData_CRUD(t *testing.T) {
 
 	// delete the active key and verify it's been deleted
 	index++
-	require.NoError(t, store.DeleteRootKeyMeta(index, keyIDs[1]))
+	require.NoError(t, store.DeleteRootKeyMeta(structs.MsgTypeTestSetup, index, keyIDs[1]))
 
 	iter, err = store.RootKeyMetas(nil)
 	require.NoError(t, err)
@@ -3,6 +3,7 @@ package state
 import (
 	"fmt"
 	"math"
+	"sort"
 
 	"github.com/hashicorp/go-memdb"
 
@@ -113,8 +114,8 @@ func (s *StateStore) GetVariable(
 }
 
 // VarSet is used to store a variable object.
-func (s *StateStore) VarSet(idx uint64, sv *structs.VarApplyStateRequest) *structs.VarApplyStateResponse {
-	tx := s.db.WriteTxn(idx)
+func (s *StateStore) VarSet(msgType structs.MessageType, idx uint64, sv *structs.VarApplyStateRequest) *structs.VarApplyStateResponse {
+	tx := s.db.WriteTxnMsgT(msgType, idx)
 	defer tx.Abort()
 
 	// Perform the actual set.
@@ -132,8 +133,8 @@ func (s *StateStore) VarSet(idx uint64, sv *structs.VarApplyStateRequest) *struc
 // VarSetCAS is used to do a check-and-set operation on a
 // variable. The ModifyIndex in the provided entry is used to determine if
 // we should write the entry to the state store or not.
-func (s *StateStore) VarSetCAS(idx uint64, sv *structs.VarApplyStateRequest) *structs.VarApplyStateResponse {
-	tx := s.db.WriteTxn(idx)
+func (s *StateStore) VarSetCAS(msgType structs.MessageType, idx uint64, sv *structs.VarApplyStateRequest) *structs.VarApplyStateResponse {
+	tx := s.db.WriteTxnMsgT(msgType, idx)
 	defer tx.Abort()
 
 	resp := s.varSetCASTxn(tx, idx, sv)
@@ -194,6 +195,21 @@ func (s *StateStore) varSetTxn(tx WriteTxn, idx uint64, req *structs.VarApplySta
 	}
 	existing, _ := existingRaw.(*structs.VariableEncrypted)
 
+	// Only the holder of the lock on a variable may write it, and a locked
+	// variable can't be rolled back
+	if existing != nil && existing.Lock != nil && req.Op != structs.VarOpLockAcquire &&
+		(req.Op == structs.VarOpRollback || sv.Lock == nil || sv.Lock.ID != existing.Lock.ID) {
+		return req.ConflictResponse(idx, existing)
+	}
+
+	// Only lock operations change the lock held on a variable
+	if req.Op != structs.VarOpLockAcquire {
+		sv.Lock = nil
+		if existing != nil {
+			sv.Lock = existing.Lock.Copy()
+		}
+	}
+
 	existingQuota, err := tx.First(TableVariablesQuotas, indexID, sv.Namespace)
 	if err != nil {
 		return req.ErrorResponse(idx, fmt.Errorf("variable quota lookup failed: %v", err))
@@ -216,6 +232,17 @@ func (s *StateStore) varSetTxn(tx WriteTxn, idx uint64, req *structs.VarApplySta
 		}
 		sv.ModifyIndex = idx
 		quotaChange = int64(len(sv.Data) - len(existing.Data))
+
+		// The existing variable is either retained as a prior version, and
+		// so still counts against the quota, or is dropped. Changes to the
+		// lock alone do not create a new version.
+		if !existing.VariableData.Equals(sv.VariableData) {
+			versionsChange, err := s.varRetainVersionTxn(tx, existing, req.VersionLimit)
+			if err != nil {
+				return req.ErrorResponse(idx, err)
+			}
+			quotaChange += versionsChange
+		}
 	} else {
 		sv.CreateIndex = idx
 		sv.ModifyIndex = idx
@@ -274,6 +301,432 @@ func (s *StateStore) varSetTxn(tx WriteTxn, idx uint64, req *structs.VarApplySta
 	return req.SuccessResponse(idx, &sv.VariableMetadata)
 }
 
+// VarRollback is used to restore a retained prior version of a variable. The
+// current version of the variable is retained as a prior version in turn.
+func (s *StateStore) VarRollback(msgType structs.MessageType, idx uint64, req *structs.VarApplyStateRequest) *structs.VarApplyStateResponse {
+	tx := s.db.WriteTxnMsgT(msgType, idx)
+	defer tx.Abort()
+
+	resp := s.varRollbackTxn(tx, idx, req)
+	if !resp.IsOk() {
+		return resp
+	}
+
+	if err := tx.Commit(); err != nil {
+		return req.ErrorResponse(idx, err)
+	}
+	return resp
+}
+
+// varRollbackTxn is the inner method used to restore a prior version of a
+// variable within an existing transaction.
+func (s *StateStore) varRollbackTxn(tx WriteTxn, idx uint64, req *structs.VarApplyStateRequest) *structs.VarApplyStateResponse {
+	raw, err := tx.First(TableVariables, indexID, req.Var.Namespace, req.Var.Path)
+	if err != nil {
+		return req.ErrorResponse(idx, fmt.Errorf("failed variable lookup: %s", err))
+	}
+	if raw == nil {
+		return req.ErrorResponse(idx, fmt.Errorf("variable not found"))
+	}
+
+	// Rolling back to the current version is a no-op
+	current := raw.(*structs.VariableEncrypted)
+	if current.ModifyIndex == req.Version {
+		return req.SuccessResponse(idx, &current.VariableMetadata)
+	}
+
+	raw, err = tx.First(TableVariablesVersions, indexID, req.Var.Namespace, req.Var.Path, req.Version)
+	if err != nil {
+		return req.ErrorResponse(idx, fmt.Errorf("failed variable version lookup: %s", err))
+	}
+	if raw == nil {
+		return req.ErrorResponse(idx, fmt.Errorf("variable version %d not found", req.Version))
+	}
+
+	// The restored version keeps its encrypted data, so it does not need to
+	// be decrypted and encrypted again.
+	version := raw.(*structs.VariableEncrypted).Copy()
+	version.ModifyTime = req.Var.ModifyTime
+
+	setReq := *req
+	setReq.Var = &version
+	return s.varSetTxn(tx, idx, &setReq)
+}
+
+// VarLockAcquire is used to acquire a lock on a variable, creating the
+// variable if it does not exist. The lock is only acquired if the variable is
+// not already locked.
+func (s *StateStore) VarLockAcquire(msgType structs.MessageType, idx uint64, req *structs.VarApplyStateRequest) *structs.VarApplyStateResponse {
+	tx := s.db.WriteTxnMsgT(msgType, idx)
+	defer tx.Abort()
+
+	resp := s.varLockAcquireTxn(tx, idx, req)
+	if !resp.IsOk() {
+		return resp
+	}
+
+	if err := tx.Commit(); err != nil {
+		return req.ErrorResponse(idx, err)
+	}
+	return resp
+}
+
+// varLockAcquireTxn is the inner method used to acquire a lock on a variable
+// within an existing transaction.
+func (s *StateStore) varLockAcquireTxn(tx WriteTxn, idx uint64, req *structs.VarApplyStateRequest) *structs.VarApplyStateResponse {
+	sv := req.Var
+	if sv.Lock == nil {
+		return req.ErrorResponse(idx, fmt.Errorf("lock acquire requires a lock"))
+	}
+
+	raw, err := tx.First(TableVariables, indexID, sv.Namespace, sv.Path)
+	if err != nil {
+		return req.ErrorResponse(idx, fmt.Errorf("failed variable lookup: %s", err))
+	}
+
+	if raw != nil {
+		existing := raw.(*structs.VariableEncrypted)
+		if existing.Lock != nil {
+			return req.ConflictResponse(idx, existing)
+		}
+
+		// Acquiring a lock without items keeps the existing items
+		if len(sv.Data) == 0 {
+			sv.VariableData = existing.VariableData.Copy()
+		}
+	} else if len(sv.Data) == 0 {
+		return req.ErrorResponse(idx, fmt.Errorf("variable not found"))
+	}
+
+	return s.varSetTxn(tx, idx, req)
+}
+
+// VarLockRelease is used to release a lock held on a variable. The lock is
+// only released if the ID of the lock in the request matches the lock held.
+func (s *StateStore) VarLockRelease(msgType structs.MessageType, idx uint64, req *structs.VarApplyStateRequest) *structs.VarApplyStateResponse {
+	tx := s.db.WriteTxnMsgT(msgType, idx)
+	defer tx.Abort()
+
+	resp := s.varLockReleaseTxn(tx, idx, req)
+	if !resp.IsOk() {
+		return resp
+	}
+
+	if err := tx.Commit(); err != nil {
+		return req.ErrorResponse(idx, err)
+	}
+	return resp
+}
+
+// varLockReleaseTxn is the inner method used to release a lock on a variable
+// within an existing transaction.
+func (s *StateStore) varLockReleaseTxn(tx WriteTxn, idx uint64, req *structs.VarApplyStateRequest) *structs.VarApplyStateResponse {
+	sv := req.Var
+	if sv.Lock == nil {
+		return req.ErrorResponse(idx, fmt.Errorf("lock release requires a lock"))
+	}
+
+	raw, err := tx.First(TableVariables, indexID, sv.Namespace, sv.Path)
+	if err != nil {
+		return req.ErrorResponse(idx, fmt.Errorf("failed variable lookup: %s", err))
+	}
+
+	// If the variable doesn't exist, return a plausible zero value as the
+	// conflict
+	if raw == nil {
+		zeroVal := &structs.VariableEncrypted{
+			VariableMetadata: structs.VariableMetadata{
+				Namespace: sv.Namespace,
+				Path:      sv.Path,
+			},
+		}
+		return req.ConflictResponse(idx, zeroVal)
+	}
+
+	existing := raw.(*structs.VariableEncrypted)
+	if existing.Lock == nil || existing.Lock.ID != sv.Lock.ID {
+		return req.ConflictResponse(idx, existing)
+	}
+
+	released := existing.Copy()
+	released.Lock = nil
+	released.ModifyIndex = idx
+	released.ModifyTime = sv.ModifyTime
+
+	if err := tx.Insert(TableVariables, &released); err != nil {
+		return req.ErrorResponse(idx, fmt.Errorf("failed inserting variable: %s", err))
+	}
+	if err := tx.Insert(tableIndex, &IndexEntry{TableVariables, idx}); err != nil {
+		return req.ErrorResponse(idx, fmt.Errorf("failed updating variable index: %s", err))
+	}
+
+	return req.SuccessResponse(idx, &released.VariableMetadata)
+}
+
+// VarRekey is used to replace the encrypted data of a variable, or of a
+// retained prior version of a variable, with the same items encrypted by
+// another key. The entry is identified by its ModifyIndex, and its metadata is
+// left unchanged so that rekeying does not look like a write to its readers.
+func (s *StateStore) VarRekey(msgType structs.MessageType, idx uint64, req *structs.VarApplyStateRequest) *structs.VarApplyStateResponse {
+	tx := s.db.WriteTxnMsgT(msgType, idx)
+	defer tx.Abort()
+
+	resp := s.varRekeyTxn(tx, idx, req)
+	if !resp.IsOk() {
+		return resp
+	}
+
+	if err := tx.Commit(); err != nil {
+		return req.ErrorResponse(idx, err)
+	}
+	return resp
+}
+
+// varRekeyTxn is the inner method used to rekey a variable within an existing
+// transaction.
+func (s *StateStore) varRekeyTxn(tx WriteTxn, idx uint64, req *structs.VarApplyStateRequest) *structs.VarApplyStateResponse {
+	sv := req.Var
+	if len(sv.Data) == 0 || sv.KeyID == "" {
+		return req.ErrorResponse(idx, fmt.Errorf("rekey requires encrypted data"))
+	}
+
+	table := TableVariables
+	raw, err := tx.First(TableVariables, indexID, sv.Namespace, sv.Path)
+	if err != nil {
+		return req.ErrorResponse(idx, fmt.Errorf("failed variable lookup: %s", err))
+	}
+	if raw == nil || raw.(*structs.VariableEncrypted).ModifyIndex != sv.ModifyIndex {
+		table = TableVariablesVersions
+		raw, err = tx.First(TableVariablesVersions, indexID, sv.Namespace, sv.Path, sv.ModifyIndex)
+		if err != nil {
+			return req.ErrorResponse(idx, fmt.Errorf("failed variable version lookup: %s", err))
+		}
+	}
+
+	// The entry was deleted, or its version pruned, since it was read to be
+	// rekeyed, so there is nothing left to do
+	if raw == nil {
+		return req.SuccessResponse(idx, nil)
+	}
+
+	existing := raw.(*structs.VariableEncrypted)
+	rekeyed := existing.Copy()
+	rekeyed.VariableData = sv.VariableData.Copy()
+
+	if err := tx.Insert(table, &rekeyed); err != nil {
+		return req.ErrorResponse(idx, fmt.Errorf("failed inserting variable: %s", err))
+	}
+
+	// The size of the encrypted data only changes if the algorithm of the
+	// key does, so quota is tracked but not enforced
+	if sizeChange := int64(len(rekeyed.Data) - len(existing.Data)); sizeChange != 0 {
+		raw, err := tx.First(TableVariablesQuotas, indexID, sv.Namespace)
+		if err != nil {
+			return req.ErrorResponse(idx, fmt.Errorf("variable quota lookup failed: %v", err))
+		}
+		if raw != nil {
+			quotaUsed := raw.(*structs.VariablesQuota).Copy()
+			if sizeChange > 0 {
+				quotaUsed.Size += sizeChange
+			} else {
+				quotaUsed.Size -= helper.Min(quotaUsed.Size, -sizeChange)
+			}
+			quotaUsed.ModifyIndex = idx
+			if err := tx.Insert(TableVariablesQuotas, quotaUsed); err != nil {
+				return req.ErrorResponse(idx, fmt.Errorf("variable quota insert failed: %v", err))
+			}
+		}
+	}
+
+	if err := tx.Insert(tableIndex, &IndexEntry{TableVariables, idx}); err != nil {
+		return req.ErrorResponse(idx, fmt.Errorf("failed updating variable index: %s", err))
+	}
+
+	return req.SuccessResponse(idx, &rekeyed.VariableMetadata)
+}
+
+// releaseVarLocksForTerminalAlloc releases all of the variable locks held by
+// an allocation once it has stopped on its client.
+func (s *StateStore) releaseVarLocksForTerminalAlloc(index uint64, alloc *structs.Allocation, txn WriteTxn) error {
+	if !alloc.ClientTerminalStatus() {
+		return nil
+	}
+
+	iter, err := txn.Get(TableVariables, indexAllocID, alloc.ID)
+	if err != nil {
+		return fmt.Errorf("variable lookup failed: %v", err)
+	}
+
+	// The variables are collected first, as updating them modifies the
+	// index being iterated over
+	var released []*structs.VariableEncrypted
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		sv := raw.(*structs.VariableEncrypted).Copy()
+		sv.Lock = nil
+		sv.ModifyIndex = index
+		sv.ModifyTime = alloc.ModifyTime
+		released = append(released, &sv)
+	}
+	if len(released) == 0 {
+		return nil
+	}
+
+	for _, sv := range released {
+		if err := txn.Insert(TableVariables, sv); err != nil {
+			return fmt.Errorf("variable insert failed: %v", err)
+		}
+	}
+	if err := txn.Insert(tableIndex, &IndexEntry{TableVariables, index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	return nil
+}
+
+// GetVariablesByLockAllocID returns an iterator that contains all variables
+// locked by a particular allocation.
+func (s *StateStore) GetVariablesByLockAllocID(
+	ws memdb.WatchSet, allocID string) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get(TableVariables, indexAllocID, allocID)
+	if err != nil {
+		return nil, fmt.Errorf("variable lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	return iter, nil
+}
+
+// varRetainVersionTxn stores the given variable as a prior version of itself,
+// and prunes the oldest prior versions so that at most limit are retained. It
+// returns the change in the size of the retained versions.
+func (s *StateStore) varRetainVersionTxn(tx WriteTxn, sv *structs.VariableEncrypted, limit int) (int64, error) {
+	var sizeChange int64
+
+	if limit > 0 {
+		version := sv.Copy()
+		version.Lock = nil
+		if err := tx.Insert(TableVariablesVersions, &version); err != nil {
+			return 0, fmt.Errorf("failed inserting variable version: %s", err)
+		}
+		sizeChange += int64(len(version.Data))
+	}
+
+	versions, err := variableVersionsTxn(tx, nil, sv.Namespace, sv.Path)
+	if err != nil {
+		return 0, err
+	}
+
+	// Versions are ordered oldest first
+	for len(versions) > limit {
+		if err := tx.Delete(TableVariablesVersions, versions[0]); err != nil {
+			return 0, fmt.Errorf("failed deleting variable version: %s", err)
+		}
+		sizeChange -= int64(len(versions[0].Data))
+		versions = versions[1:]
+	}
+
+	return sizeChange, nil
+}
+
+// varDeleteVersionsTxn deletes all of the retained prior versions of a
+// variable. It returns the total size of the deleted versions.
+func (s *StateStore) varDeleteVersionsTxn(tx WriteTxn, namespace, path string) (int64, error) {
+	versions, err := variableVersionsTxn(tx, nil, namespace, path)
+	if err != nil {
+		return 0, err
+	}
+
+	var size int64
+	for _, version := range versions {
+		if err := tx.Delete(TableVariablesVersions, version); err != nil {
+			return 0, fmt.Errorf("failed deleting variable version: %s", err)
+		}
+		size += int64(len(version.Data))
+	}
+	return size, nil
+}
+
+// VariablesVersions queries all the retained prior versions of variables and
+// is used only for snapshot/restore
+func (s *StateStore) VariablesVersions(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get(TableVariablesVersions, indexID)
+	if err != nil {
+		return nil, err
+	}
+
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// GetVariableVersions returns the retained prior versions of the variable at
+// a given namespace and path, ordered from oldest to newest.
+func (s *StateStore) GetVariableVersions(
+	ws memdb.WatchSet, namespace, path string) ([]*structs.VariableEncrypted, error) {
+	txn := s.db.ReadTxn()
+	return variableVersionsTxn(txn, ws, namespace, path)
+}
+
+// GetVariableVersion returns a single retained prior version of the variable
+// at a given namespace and path, identified by its modify index.
+func (s *StateStore) GetVariableVersion(
+	ws memdb.WatchSet, namespace, path string, version uint64) (*structs.VariableEncrypted, error) {
+	txn := s.db.ReadTxn()
+
+	watchCh, raw, err := txn.FirstWatch(TableVariablesVersions, indexID, namespace, path, version)
+	if err != nil {
+		return nil, fmt.Errorf("variable version lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+	if raw == nil {
+		return nil, nil
+	}
+	return raw.(*structs.VariableEncrypted), nil
+}
+
+// GetVariablesVersionsByKeyID returns an iterator that contains all retained
+// prior versions of variables that were encrypted with a particular key
+func (s *StateStore) GetVariablesVersionsByKeyID(
+	ws memdb.WatchSet, keyID string) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get(TableVariablesVersions, indexKeyID, keyID)
+	if err != nil {
+		return nil, fmt.Errorf("variable version lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	return iter, nil
+}
+
+// variableVersionsTxn returns the retained prior versions of a variable
+// within an existing transaction, ordered from oldest to newest.
+func variableVersionsTxn(tx ReadTxn,
+	ws memdb.WatchSet, namespace, path string) ([]*structs.VariableEncrypted, error) {
+
+	iter, err := tx.Get(TableVariablesVersions, indexID+"_prefix", namespace, path)
+	if err != nil {
+		return nil, fmt.Errorf("variable version lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	// The prefix lookup also matches longer paths, which are skipped
+	var versions []*structs.VariableEncrypted
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		version := raw.(*structs.VariableEncrypted)
+		if version.Path == path {
+			versions = append(versions, version)
+		}
+	}
+
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i].ModifyIndex < versions[j].ModifyIndex
+	})
+	return versions, nil
+}
+
 // VarGet is used to retrieve a key/value pair from the state store.
 func (s *StateStore) VarGet(ws memdb.WatchSet, namespace, path string) (uint64, *structs.VariableEncrypted, error) {
 	tx := s.db.ReadTxn()
@@ -303,8 +756,8 @@ func svGetTxn(tx ReadTxn,
 
 // VarDelete is used to delete a single variable in the
 // the state store.
-func (s *StateStore) VarDelete(idx uint64, req *structs.VarApplyStateRequest) *structs.VarApplyStateResponse {
-	tx := s.db.WriteTxn(idx)
+func (s *StateStore) VarDelete(msgType structs.MessageType, idx uint64, req *structs.VarApplyStateRequest) *structs.VarApplyStateResponse {
+	tx := s.db.WriteTxnMsgT(msgType, idx)
 	defer tx.Abort()
 
 	// Perform the actual delete
@@ -325,8 +778,8 @@ func (s *StateStore) VarDelete(idx uint64, req *structs.VarApplyStateRequest) *s
 // a given modify index. If the CAS index (cidx) specified is not equal to the
 // last observed index for the given variable, then the call is a noop,
 // otherwise a normal delete is invoked.
-func (s *StateStore) VarDeleteCAS(idx uint64, req *structs.VarApplyStateRequest) *structs.VarApplyStateResponse {
-	tx := s.db.WriteTxn(idx)
+func (s *StateStore) VarDeleteCAS(msgType structs.MessageType, idx uint64, req *structs.VarApplyStateRequest) *structs.VarApplyStateResponse {
+	tx := s.db.WriteTxnMsgT(msgType, idx)
 	defer tx.Abort()
 
 	resp := s.svDeleteCASTxn(tx, idx, req)
@@ -401,11 +854,22 @@ func (s *StateStore) svDeleteTxn(tx WriteTxn, idx uint64, req *structs.VarApplyS
 
 	sv := existingRaw.(*structs.VariableEncrypted)
 
+	// A locked variable can't be deleted until the lock is released
+	if sv.Lock != nil {
+		return req.ConflictResponse(idx, sv)
+	}
+
+	// The prior versions of the variable are deleted along with it
+	versionsSize, err := s.varDeleteVersionsTxn(tx, sv.Namespace, sv.Path)
+	if err != nil {
+		return req.ErrorResponse(idx, err)
+	}
+
 	// Track quota usage
 	if existingQuota != nil {
 		quotaUsed := existingQuota.(*structs.VariablesQuota)
 		quotaUsed = quotaUsed.Copy()
-		quotaUsed.Size -= helper.Min(quotaUsed.Size, int64(len(sv.Data)))
+		quotaUsed.Size -= helper.Min(quotaUsed.Size, int64(len(sv.Data))+versionsSize)
 		quotaUsed.ModifyIndex = idx
 		if err := tx.Insert(TableVariablesQuotas, quotaUsed); err != nil {
 			return req.ErrorResponse(idx, fmt.Errorf("variable quota insert failed: %v", err))
@@ -5,6 +5,7 @@ import (
 	"sort"
 	"strings"
 	"testing"
+	"time"
 
 	memdb "github.com/hashicorp/go-memdb"
 	"github.com/stretchr/testify/require"
@@ -49,7 +50,7 @@ func TestStateStore_UpsertVariables(t *testing.T) {
 		// Perform the initial upsert of variables.
 		for _, sv := range svs {
 			insertIndex++
-			resp := testState.VarSet(insertIndex, &structs.VarApplyStateRequest{
+			resp := testState.VarSet(structs.MsgTypeTestSetup, insertIndex, &structs.VarApplyStateRequest{
 				Op:  structs.VarOpSet,
 				Var: sv,
 			})
@@ -107,7 +108,7 @@ func TestStateStore_UpsertVariables(t *testing.T) {
 				Var: sv,
 			}
 			reInsertIndex++
-			resp := testState.VarSet(reInsertIndex, svReq)
+			resp := testState.VarSet(structs.MsgTypeTestSetup, reInsertIndex, svReq)
 			require.NoError(t, resp.Error)
 		}
 
@@ -134,7 +135,7 @@ func TestStateStore_UpsertVariables(t *testing.T) {
 
 		update1Index := uint64(40)
 
-		resp := testState.VarSet(update1Index, &structs.VarApplyStateRequest{
+		resp := testState.VarSet(structs.MsgTypeTestSetup, update1Index, &structs.VarApplyStateRequest{
 			Op:  structs.VarOpSet,
 			Var: &sv1Update,
 		})
@@ -179,7 +180,7 @@ func TestStateStore_UpsertVariables(t *testing.T) {
 		sv2.KeyID = "sv2-update"
 		sv2.ModifyIndex = update2Index
 
-		resp := testState.VarSet(update2Index, &structs.VarApplyStateRequest{
+		resp := testState.VarSet(structs.MsgTypeTestSetup, update2Index, &structs.VarApplyStateRequest{
 			Op:  structs.VarOpSet,
 			Var: &sv2,
 		})
@@ -231,7 +232,7 @@ func TestStateStore_DeleteVariable(t *testing.T) {
 
 	t.Run("1 delete a variable that does not exist", func(t *testing.T) {
 
-		resp := testState.VarDelete(initialIndex, &structs.VarApplyStateRequest{
+		resp := testState.VarDelete(structs.MsgTypeTestSetup, initialIndex, &structs.VarApplyStateRequest{
 			Op:  structs.VarOpDelete,
 			Var: svs[0],
 		})
@@ -252,7 +253,7 @@ func TestStateStore_DeleteVariable(t *testing.T) {
 
 		ns := mock.Namespace()
 		ns.Name = svs[0].Namespace
-		require.NoError(t, testState.UpsertNamespaces(initialIndex, []*structs.Namespace{ns}))
+		require.NoError(t, testState.UpsertNamespaces(structs.MsgTypeTestSetup, initialIndex, []*structs.Namespace{ns}))
 
 		for _, sv := range svs {
 			svReq := &structs.VarApplyStateRequest{
@@ -260,14 +261,14 @@ func TestStateStore_DeleteVariable(t *testing.T) {
 				Var: sv,
 			}
 			initialIndex++
-			resp := testState.VarSet(initialIndex, svReq)
+			resp := testState.VarSet(structs.MsgTypeTestSetup, initialIndex, svReq)
 			require.NoError(t, resp.Error)
 		}
 
 		// Perform the delete.
 		delete1Index := uint64(20)
 
-		resp := testState.VarDelete(delete1Index, &structs.VarApplyStateRequest{
+		resp := testState.VarDelete(structs.MsgTypeTestSetup, delete1Index, &structs.VarApplyStateRequest{
 			Op:  structs.VarOpDelete,
 			Var: svs[0],
 		})
@@ -303,7 +304,7 @@ func TestStateStore_DeleteVariable(t *testing.T) {
 	t.Run("3 delete remaining variable", func(t *testing.T) {
 		delete2Index := uint64(30)
 
-		resp := testState.VarDelete(delete2Index, &structs.VarApplyStateRequest{
+		resp := testState.VarDelete(structs.MsgTypeTestSetup, delete2Index, &structs.VarApplyStateRequest{
 			Op:  structs.VarOpDelete,
 			Var: svs[1],
 		})
@@ -333,6 +334,315 @@ func TestStateStore_DeleteVariable(t *testing.T) {
 	})
 }
 
+func TestStateStore_VariableVersions(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	sv := mock.VariableEncrypted()
+	sv.Path = "versioned"
+
+	// write five versions of the variable with data of increasing size,
+	// retaining at most two prior versions
+	for i := 1; i <= 5; i++ {
+		v := sv.Copy()
+		v.Data = []byte(strings.Repeat("x", i))
+		resp := testState.VarSet(structs.MsgTypeTestSetup, uint64(10*i), &structs.VarApplyStateRequest{
+			Op:           structs.VarOpSet,
+			Var:          &v,
+			VersionLimit: 2,
+		})
+		require.NoError(t, resp.Error)
+	}
+
+	ws := memdb.NewWatchSet()
+	versions, err := testState.GetVariableVersions(ws, sv.Namespace, sv.Path)
+	require.NoError(t, err)
+	require.Len(t, versions, 2)
+	require.Equal(t, uint64(30), versions[0].ModifyIndex)
+	require.Equal(t, uint64(40), versions[1].ModifyIndex)
+
+	// the current version and the retained versions count against the quota
+	quotaUsed, err := testState.VariablesQuotaByNamespace(ws, sv.Namespace)
+	require.NoError(t, err)
+	require.Equal(t, int64(5+4+3), quotaUsed.Size)
+
+	version, err := testState.GetVariableVersion(ws, sv.Namespace, sv.Path, 30)
+	require.NoError(t, err)
+	require.Equal(t, []byte("xxx"), version.Data)
+
+	version, err = testState.GetVariableVersion(ws, sv.Namespace, sv.Path, 20)
+	require.NoError(t, err)
+	require.Nil(t, version)
+
+	// rolling back to a pruned version fails
+	resp := testState.VarRollback(structs.MsgTypeTestSetup, 60, &structs.VarApplyStateRequest{
+		Op:           structs.VarOpRollback,
+		Var:          sv,
+		Version:      20,
+		VersionLimit: 2,
+	})
+	require.EqualError(t, resp.Error, "variable version 20 not found")
+
+	// rolling back restores the version and retains the current one
+	resp = testState.VarRollback(structs.MsgTypeTestSetup, 60, &structs.VarApplyStateRequest{
+		Op:           structs.VarOpRollback,
+		Var:          sv,
+		Version:      30,
+		VersionLimit: 2,
+	})
+	require.NoError(t, resp.Error)
+	require.Equal(t, uint64(60), resp.WrittenSVMeta.ModifyIndex)
+
+	current, err := testState.GetVariable(ws, sv.Namespace, sv.Path)
+	require.NoError(t, err)
+	require.Equal(t, []byte("xxx"), current.Data)
+	require.Equal(t, uint64(10), current.CreateIndex)
+	require.Equal(t, uint64(60), current.ModifyIndex)
+
+	versions, err = testState.GetVariableVersions(ws, sv.Namespace, sv.Path)
+	require.NoError(t, err)
+	require.Len(t, versions, 2)
+	require.Equal(t, uint64(40), versions[0].ModifyIndex)
+	require.Equal(t, uint64(50), versions[1].ModifyIndex)
+
+	quotaUsed, err = testState.VariablesQuotaByNamespace(ws, sv.Namespace)
+	require.NoError(t, err)
+	require.Equal(t, int64(3+5+4), quotaUsed.Size)
+
+	// lowering the limit prunes versions on the next write
+	v := sv.Copy()
+	v.Data = []byte("y")
+	resp = testState.VarSet(structs.MsgTypeTestSetup, 70, &structs.VarApplyStateRequest{
+		Op:  structs.VarOpSet,
+		Var: &v,
+	})
+	require.NoError(t, resp.Error)
+
+	versions, err = testState.GetVariableVersions(ws, sv.Namespace, sv.Path)
+	require.NoError(t, err)
+	require.Empty(t, versions)
+
+	quotaUsed, err = testState.VariablesQuotaByNamespace(ws, sv.Namespace)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), quotaUsed.Size)
+
+	// deleting the variable deletes its versions
+	v = sv.Copy()
+	v.Data = []byte("zz")
+	resp = testState.VarSet(structs.MsgTypeTestSetup, 80, &structs.VarApplyStateRequest{
+		Op:           structs.VarOpSet,
+		Var:          &v,
+		VersionLimit: 2,
+	})
+	require.NoError(t, resp.Error)
+
+	resp = testState.VarDelete(structs.MsgTypeTestSetup, 90, &structs.VarApplyStateRequest{
+		Op:  structs.VarOpDelete,
+		Var: sv,
+	})
+	require.NoError(t, resp.Error)
+
+	versions, err = testState.GetVariableVersions(ws, sv.Namespace, sv.Path)
+	require.NoError(t, err)
+	require.Empty(t, versions)
+
+	quotaUsed, err = testState.VariablesQuotaByNamespace(ws, sv.Namespace)
+	require.NoError(t, err)
+	require.Equal(t, int64(0), quotaUsed.Size)
+}
+
+func TestStateStore_VariableRekey(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	sv := mock.VariableEncrypted()
+	sv.Path = "rekeyed"
+	sv.KeyID = "old-key"
+
+	for i := 1; i <= 2; i++ {
+		v := sv.Copy()
+		v.Data = []byte(strings.Repeat("x", i))
+		resp := testState.VarSet(structs.MsgTypeTestSetup, uint64(10*i), &structs.VarApplyStateRequest{
+			Op:           structs.VarOpSet,
+			Var:          &v,
+			VersionLimit: 2,
+		})
+		require.NoError(t, resp.Error)
+	}
+
+	rekey := func(idx, modifyIndex uint64, data string) *structs.VarApplyStateResponse {
+		v := sv.Copy()
+		v.ModifyIndex = modifyIndex
+		v.KeyID = "new-key"
+		v.Data = []byte(data)
+		return testState.VarRekey(structs.MsgTypeTestSetup, idx, &structs.VarApplyStateRequest{
+			Op:  structs.VarOpRekey,
+			Var: &v,
+		})
+	}
+
+	// rekeying the current version keeps its metadata
+	resp := rekey(30, 20, "yy")
+	require.NoError(t, resp.Error)
+
+	ws := memdb.NewWatchSet()
+	current, err := testState.GetVariable(ws, sv.Namespace, sv.Path)
+	require.NoError(t, err)
+	require.Equal(t, "new-key", current.KeyID)
+	require.Equal(t, []byte("yy"), current.Data)
+	require.Equal(t, uint64(10), current.CreateIndex)
+	require.Equal(t, uint64(20), current.ModifyIndex)
+
+	// rekeying does not retain a new version
+	versions, err := testState.GetVariableVersions(ws, sv.Namespace, sv.Path)
+	require.NoError(t, err)
+	require.Len(t, versions, 1)
+	require.Equal(t, "old-key", versions[0].KeyID)
+
+	// rekeying a prior version with data of a different size
+	resp = rekey(40, 10, "yyy")
+	require.NoError(t, resp.Error)
+
+	version, err := testState.GetVariableVersion(ws, sv.Namespace, sv.Path, 10)
+	require.NoError(t, err)
+	require.Equal(t, "new-key", version.KeyID)
+	require.Equal(t, []byte("yyy"), version.Data)
+
+	iter, err := testState.GetVariablesVersionsByKeyID(ws, "old-key")
+	require.NoError(t, err)
+	require.Nil(t, iter.Next())
+
+	quotaUsed, err := testState.VariablesQuotaByNamespace(ws, sv.Namespace)
+	require.NoError(t, err)
+	require.Equal(t, int64(2+3), quotaUsed.Size)
+
+	// rekeying a version which no longer exists is a no-op
+	resp = rekey(50, 5, "zz")
+	require.NoError(t, resp.Error)
+	require.Nil(t, resp.WrittenSVMeta)
+
+	// rekeying requires encrypted data
+	resp = rekey(60, 20, "")
+	require.EqualError(t, resp.Error, "rekey requires encrypted data")
+}
+
+func TestStateStore_VariableLocks(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	alloc := mock.Alloc()
+	require.NoError(t, testState.UpsertAllocs(structs.MsgTypeTestSetup, 5, []*structs.Allocation{alloc}))
+
+	sv := mock.VariableEncrypted()
+	sv.Path = "locked"
+
+	acquire := func(index uint64, lockID, allocID string, data []byte) *structs.VarApplyStateResponse {
+		v := sv.Copy()
+		v.Data = data
+		v.Lock = &structs.VariableLock{ID: lockID, TTL: 15 * time.Second, AllocID: allocID}
+		return testState.VarLockAcquire(structs.MsgTypeTestSetup, index, &structs.VarApplyStateRequest{
+			Op:           structs.VarOpLockAcquire,
+			Var:          &v,
+			VersionLimit: 2,
+		})
+	}
+	release := func(index uint64, lockID string) *structs.VarApplyStateResponse {
+		v := sv.Copy()
+		v.Lock = &structs.VariableLock{ID: lockID}
+		return testState.VarLockRelease(structs.MsgTypeTestSetup, index, &structs.VarApplyStateRequest{
+			Op:  structs.VarOpLockRelease,
+			Var: &v,
+		})
+	}
+
+	// acquiring a lock on a missing variable requires items
+	resp := acquire(10, "lock1", "", nil)
+	require.EqualError(t, resp.Error, "variable not found")
+
+	resp = acquire(10, "lock1", "", []byte("data"))
+	require.True(t, resp.IsOk())
+	require.Equal(t, "lock1", resp.WrittenSVMeta.Lock.ID)
+
+	// a held lock can't be acquired again
+	resp = acquire(20, "lock2", "", nil)
+	require.True(t, resp.IsConflict())
+	require.Equal(t, "lock1", resp.Conflict.Lock.ID)
+
+	// only the lock holder can write the variable
+	v := sv.Copy()
+	v.Data = []byte("updated")
+	resp = testState.VarSet(structs.MsgTypeTestSetup, 30, &structs.VarApplyStateRequest{
+		Op:           structs.VarOpSet,
+		Var:          &v,
+		VersionLimit: 2,
+	})
+	require.True(t, resp.IsConflict())
+
+	// a locked variable can't be deleted
+	resp = testState.VarDelete(structs.MsgTypeTestSetup, 30, &structs.VarApplyStateRequest{
+		Op:  structs.VarOpDelete,
+		Var: &v,
+	})
+	require.True(t, resp.IsConflict())
+
+	// writing the variable keeps the lock
+	v.Lock = &structs.VariableLock{ID: "lock1"}
+	resp = testState.VarSet(structs.MsgTypeTestSetup, 30, &structs.VarApplyStateRequest{
+		Op:           structs.VarOpSet,
+		Var:          &v,
+		VersionLimit: 2,
+	})
+	require.NoError(t, resp.Error)
+	require.True(t, resp.IsOk())
+
+	ws := memdb.NewWatchSet()
+	current, err := testState.GetVariable(ws, sv.Namespace, sv.Path)
+	require.NoError(t, err)
+	require.Equal(t, "lock1", current.Lock.ID)
+
+	// a lock is only released by its holder
+	resp = release(40, "lock2")
+	require.True(t, resp.IsConflict())
+
+	resp = release(40, "lock1")
+	require.True(t, resp.IsOk())
+	require.Nil(t, resp.WrittenSVMeta.Lock)
+
+	// acquiring a lock without items keeps the existing items, and lock
+	// changes alone do not create versions
+	resp = acquire(50, "lock3", alloc.ID, nil)
+	require.True(t, resp.IsOk())
+
+	current, err = testState.GetVariable(ws, sv.Namespace, sv.Path)
+	require.NoError(t, err)
+	require.Equal(t, []byte("updated"), current.Data)
+	require.Equal(t, uint64(10), current.CreateIndex)
+	require.Equal(t, uint64(50), current.ModifyIndex)
+
+	versions, err := testState.GetVariableVersions(ws, sv.Namespace, sv.Path)
+	require.NoError(t, err)
+	require.Len(t, versions, 1)
+	require.Nil(t, versions[0].Lock)
+
+	iter, err := testState.GetVariablesByLockAllocID(ws, alloc.ID)
+	require.NoError(t, err)
+	require.NotNil(t, iter.Next())
+
+	// the lock is released when the holding allocation stops
+	update := alloc.Copy()
+	update.ClientStatus = structs.AllocClientStatusComplete
+	require.NoError(t, testState.UpdateAllocsFromClient(structs.MsgTypeTestSetup, 60, []*structs.Allocation{update}))
+
+	current, err = testState.GetVariable(ws, sv.Namespace, sv.Path)
+	require.NoError(t, err)
+	require.Nil(t, current.Lock)
+	require.Equal(t, uint64(60), current.ModifyIndex)
+
+	iter, err = testState.GetVariablesByLockAllocID(ws, alloc.ID)
+	require.NoError(t, err)
+	require.Nil(t, iter.Next())
+}
+
 func TestStateStore_GetVariables(t *testing.T) {
 	ci.Parallel(t)
 	testState := testStateStore(t)
@@ -340,7 +650,7 @@ func TestStateStore_GetVariables(t *testing.T) {
 	ns := mock.Namespace()
 	ns.Name = "~*magical*~"
 	initialIndex := uint64(10)
-	require.NoError(t, testState.UpsertNamespaces(initialIndex, []*structs.Namespace{ns}))
+	require.NoError(t, testState.UpsertNamespaces(structs.MsgTypeTestSetup, initialIndex, []*structs.Namespace{ns}))
 
 	// Generate some test variables in different namespaces and upsert them.
 	svs := []*structs.VariableEncrypted{
@@ -357,7 +667,7 @@ func TestStateStore_GetVariables(t *testing.T) {
 			Var: sv,
 		}
 		initialIndex++
-		resp := testState.VarSet(initialIndex, svReq)
+		resp := testState.VarSet(structs.MsgTypeTestSetup, initialIndex, svReq)
 		require.NoError(t, resp.Error)
 	}
 
@@ -430,7 +740,7 @@ func TestStateStore_ListVariablesByNamespaceAndPrefix(t *testing.T) {
 	ns := mock.Namespace()
 	ns.Name = "other"
 	initialIndex := uint64(10)
-	require.NoError(t, testState.UpsertNamespaces(initialIndex, []*structs.Namespace{ns}))
+	require.NoError(t, testState.UpsertNamespaces(structs.MsgTypeTestSetup, initialIndex, []*structs.Namespace{ns}))
 
 	for _, sv := range svs {
 		svReq := &structs.VarApplyStateRequest{
@@ -438,7 +748,7 @@ func TestStateStore_ListVariablesByNamespaceAndPrefix(t *testing.T) {
 			Var: sv,
 		}
 		initialIndex++
-		resp := testState.VarSet(initialIndex, svReq)
+		resp := testState.VarSet(structs.MsgTypeTestSetup, initialIndex, svReq)
 		require.NoError(t, resp.Error)
 	}
 
@@ -609,7 +919,7 @@ func TestStateStore_ListVariablesByKeyID(t *testing.T) {
 			Var: sv,
 		}
 		initialIndex++
-		resp := testState.VarSet(initialIndex, svReq)
+		resp := testState.VarSet(structs.MsgTypeTestSetup, initialIndex, svReq)
 		require.NoError(t, resp.Error)
 	}
 
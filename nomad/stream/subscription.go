@@ -152,10 +152,11 @@ func filter(req *SubscribeRequest, events []structs.Event) []structs.Event {
 			continue
 		}
 
+		// Only include the event once, even if it matches multiple keys.
 		for _, key := range keys {
 			if eventMatchesKey(event, key) {
 				result = append(result, event)
-				continue
+				break
 			}
 		}
 	}
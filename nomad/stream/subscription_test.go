@@ -208,3 +208,30 @@ func TestFilter_FilterKeys(t *testing.T) {
 
 	require.Equal(t, 1, cap(actual))
 }
+
+func TestFilter_FilterKeys_ServiceName(t *testing.T) {
+	ci.Parallel(t)
+
+	// Service events can be filtered by the service name, which is included
+	// as a filter key alongside the job ID.
+	events := []structs.Event{
+		{Topic: structs.TopicService, Key: "_nomad-task-1", FilterKeys: []string{"example", "web"}},
+		{Topic: structs.TopicService, Key: "_nomad-task-2", FilterKeys: []string{"example", "db"}},
+		{Topic: structs.TopicService, Key: "_nomad-task-3", FilterKeys: []string{"api", "web"}},
+	}
+
+	req := &SubscribeRequest{
+		Topics: map[structs.Topic][]string{
+			structs.TopicService: {"web", "api"},
+		},
+		Namespace: "default",
+	}
+	actual := filter(req, events)
+
+	// The third event matches both keys but must only be included once.
+	expected := []structs.Event{
+		{Topic: structs.TopicService, Key: "_nomad-task-1", FilterKeys: []string{"example", "web"}},
+		{Topic: structs.TopicService, Key: "_nomad-task-3", FilterKeys: []string{"api", "web"}},
+	}
+	require.Equal(t, expected, actual)
+}
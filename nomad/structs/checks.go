@@ -57,6 +57,18 @@ func (r *CheckQueryResult) String() string {
 	return fmt.Sprintf("(%s %s %s %v)", r.ID, r.Mode, r.Status, r.Timestamp)
 }
 
+// ToServiceRegistrationCheck converts the result into the form stored on the
+// ServiceRegistration the check belongs to.
+func (r *CheckQueryResult) ToServiceRegistrationCheck() *ServiceRegistrationCheck {
+	return &ServiceRegistrationCheck{
+		Name:      r.Check,
+		Mode:      r.Mode,
+		Status:    r.Status,
+		Output:    r.Output,
+		Timestamp: r.Timestamp,
+	}
+}
+
 // A CheckStatus is the result of executing a check. The status of a query is
 // ternary - success, failure, or pending (not yet executed). Deployments treat
 // pending and failure as the same - a deployment does not continue until a check
@@ -34,6 +34,38 @@ type ArtifactConfig struct {
 	// S3Timeout is the duration in which an S3 operation must complete or
 	// it will be canceled. Defaults to 30m.
 	S3Timeout *string `hcl:"s3_timeout"`
+
+	// CacheMaxSize is the maximum size of the node-local cache of artifacts
+	// with a checksum, which is shared across allocations. Set to 0 to
+	// disable the cache. Defaults to 10GB.
+	CacheMaxSize *string `hcl:"cache_max_size"`
+
+	// DisableSandbox downloads artifacts within the client process rather
+	// than in a separate sandboxed process. Defaults to false.
+	DisableSandbox *bool `hcl:"disable_sandbox"`
+
+	// DisableFilesystemIsolation allows the sandboxed process to access the
+	// whole filesystem rather than only the task directory and the system
+	// directories needed to download artifacts. Defaults to false.
+	DisableFilesystemIsolation *bool `hcl:"disable_filesystem_isolation"`
+
+	// SandboxCPULimit is the CPU time the sandboxed process may use, as a
+	// percentage of one core. Set to 0 for no limit. Defaults to 100.
+	SandboxCPULimit *int `hcl:"sandbox_cpu_limit"`
+
+	// SandboxMemoryLimit is the maximum memory the sandboxed process may
+	// use. Set to 0 for no limit. Defaults to 1GB.
+	SandboxMemoryLimit *string `hcl:"sandbox_memory_limit"`
+
+	// DecompressionSizeLimit is the maximum total size of the files
+	// decompressed from an artifact. Set to 0 for no limit. Defaults to
+	// 100GB.
+	DecompressionSizeLimit *string `hcl:"decompression_size_limit"`
+
+	// DecompressionFileCountLimit is the maximum number of files
+	// decompressed from an artifact. Set to 0 for no limit. Defaults to
+	// 4096.
+	DecompressionFileCountLimit *int `hcl:"decompression_file_count_limit"`
 }
 
 func (a *ArtifactConfig) Copy() *ArtifactConfig {
@@ -60,6 +92,27 @@ func (a *ArtifactConfig) Copy() *ArtifactConfig {
 	if a.S3Timeout != nil {
 		newCopy.S3Timeout = pointer.Of(*a.S3Timeout)
 	}
+	if a.CacheMaxSize != nil {
+		newCopy.CacheMaxSize = pointer.Of(*a.CacheMaxSize)
+	}
+	if a.DisableSandbox != nil {
+		newCopy.DisableSandbox = pointer.Of(*a.DisableSandbox)
+	}
+	if a.DisableFilesystemIsolation != nil {
+		newCopy.DisableFilesystemIsolation = pointer.Of(*a.DisableFilesystemIsolation)
+	}
+	if a.SandboxCPULimit != nil {
+		newCopy.SandboxCPULimit = pointer.Of(*a.SandboxCPULimit)
+	}
+	if a.SandboxMemoryLimit != nil {
+		newCopy.SandboxMemoryLimit = pointer.Of(*a.SandboxMemoryLimit)
+	}
+	if a.DecompressionSizeLimit != nil {
+		newCopy.DecompressionSizeLimit = pointer.Of(*a.DecompressionSizeLimit)
+	}
+	if a.DecompressionFileCountLimit != nil {
+		newCopy.DecompressionFileCountLimit = pointer.Of(*a.DecompressionFileCountLimit)
+	}
 
 	return newCopy
 }
@@ -91,6 +144,27 @@ func (a *ArtifactConfig) Merge(o *ArtifactConfig) *ArtifactConfig {
 	if o.S3Timeout != nil {
 		newCopy.S3Timeout = pointer.Of(*o.S3Timeout)
 	}
+	if o.CacheMaxSize != nil {
+		newCopy.CacheMaxSize = pointer.Of(*o.CacheMaxSize)
+	}
+	if o.DisableSandbox != nil {
+		newCopy.DisableSandbox = pointer.Of(*o.DisableSandbox)
+	}
+	if o.DisableFilesystemIsolation != nil {
+		newCopy.DisableFilesystemIsolation = pointer.Of(*o.DisableFilesystemIsolation)
+	}
+	if o.SandboxCPULimit != nil {
+		newCopy.SandboxCPULimit = pointer.Of(*o.SandboxCPULimit)
+	}
+	if o.SandboxMemoryLimit != nil {
+		newCopy.SandboxMemoryLimit = pointer.Of(*o.SandboxMemoryLimit)
+	}
+	if o.DecompressionSizeLimit != nil {
+		newCopy.DecompressionSizeLimit = pointer.Of(*o.DecompressionSizeLimit)
+	}
+	if o.DecompressionFileCountLimit != nil {
+		newCopy.DecompressionFileCountLimit = pointer.Of(*o.DecompressionFileCountLimit)
+	}
 
 	return newCopy
 }
@@ -154,6 +228,55 @@ func (a *ArtifactConfig) Validate() error {
 		return fmt.Errorf("s3_timeout must be > 0")
 	}
 
+	if a.CacheMaxSize == nil {
+		return fmt.Errorf("cache_max_size must be set")
+	}
+	if v, err := humanize.ParseBytes(*a.CacheMaxSize); err != nil {
+		return fmt.Errorf("cache_max_size not a valid size: %w", err)
+	} else if v > math.MaxInt64 {
+		return fmt.Errorf("cache_max_size must be < %d but found %d", int64(math.MaxInt64), v)
+	}
+
+	if a.DisableSandbox == nil {
+		return fmt.Errorf("disable_sandbox must be set")
+	}
+
+	if a.DisableFilesystemIsolation == nil {
+		return fmt.Errorf("disable_filesystem_isolation must be set")
+	}
+
+	if a.SandboxCPULimit == nil {
+		return fmt.Errorf("sandbox_cpu_limit must be set")
+	}
+	if *a.SandboxCPULimit < 0 {
+		return fmt.Errorf("sandbox_cpu_limit must be >= 0")
+	}
+
+	if a.SandboxMemoryLimit == nil {
+		return fmt.Errorf("sandbox_memory_limit must be set")
+	}
+	if v, err := humanize.ParseBytes(*a.SandboxMemoryLimit); err != nil {
+		return fmt.Errorf("sandbox_memory_limit not a valid size: %w", err)
+	} else if v > math.MaxInt64 {
+		return fmt.Errorf("sandbox_memory_limit must be < %d but found %d", int64(math.MaxInt64), v)
+	}
+
+	if a.DecompressionSizeLimit == nil {
+		return fmt.Errorf("decompression_size_limit must be set")
+	}
+	if v, err := humanize.ParseBytes(*a.DecompressionSizeLimit); err != nil {
+		return fmt.Errorf("decompression_size_limit not a valid size: %w", err)
+	} else if v > math.MaxInt64 {
+		return fmt.Errorf("decompression_size_limit must be < %d but found %d", int64(math.MaxInt64), v)
+	}
+
+	if a.DecompressionFileCountLimit == nil {
+		return fmt.Errorf("decompression_file_count_limit must be set")
+	}
+	if *a.DecompressionFileCountLimit < 0 {
+		return fmt.Errorf("decompression_file_count_limit must be >= 0")
+	}
+
 	return nil
 }
 
@@ -182,5 +305,24 @@ func DefaultArtifactConfig() *ArtifactConfig {
 		// Timeout for S3 operations. Must be long enough to
 		// accommodate large/slow downloads.
 		S3Timeout: pointer.Of("30m"),
+
+		// Maximum size of the artifact cache. Must be large enough to
+		// hold the artifacts used by the allocations on the node.
+		CacheMaxSize: pointer.Of("10GB"),
+
+		// Download artifacts in a sandboxed process confined to the task
+		// directory.
+		DisableSandbox:             pointer.Of(false),
+		DisableFilesystemIsolation: pointer.Of(false),
+
+		// Resource limits of the sandboxed process. Must be large enough
+		// to accommodate decompressing large artifacts and git clones.
+		SandboxCPULimit:    pointer.Of(100),
+		SandboxMemoryLimit: pointer.Of("1GB"),
+
+		// Limits on the files decompressed from an artifact. Must be large
+		// enough to accommodate large archives.
+		DecompressionSizeLimit:      pointer.Of("100GB"),
+		DecompressionFileCountLimit: pointer.Of(4096),
 	}
 }
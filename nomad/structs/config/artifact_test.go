@@ -40,6 +40,14 @@ func TestArtifactConfig_Merge(t *testing.T) {
 				GitTimeout:      pointer.Of("30m"),
 				HgTimeout:       pointer.Of("30m"),
 				S3Timeout:       pointer.Of("30m"),
+				CacheMaxSize:    pointer.Of("10GB"),
+
+				DisableSandbox:              pointer.Of(false),
+				DisableFilesystemIsolation:  pointer.Of(false),
+				SandboxCPULimit:             pointer.Of(100),
+				SandboxMemoryLimit:          pointer.Of("1GB"),
+				DecompressionSizeLimit:      pointer.Of("100GB"),
+				DecompressionFileCountLimit: pointer.Of(4096),
 			},
 			other: &ArtifactConfig{
 				HTTPReadTimeout: pointer.Of("5m"),
@@ -48,6 +56,14 @@ func TestArtifactConfig_Merge(t *testing.T) {
 				GitTimeout:      pointer.Of("2m"),
 				HgTimeout:       pointer.Of("3m"),
 				S3Timeout:       pointer.Of("4m"),
+				CacheMaxSize:    pointer.Of("1GB"),
+
+				DisableSandbox:              pointer.Of(true),
+				DisableFilesystemIsolation:  pointer.Of(true),
+				SandboxCPULimit:             pointer.Of(200),
+				SandboxMemoryLimit:          pointer.Of("2GB"),
+				DecompressionSizeLimit:      pointer.Of("1GB"),
+				DecompressionFileCountLimit: pointer.Of(100),
 			},
 			expected: &ArtifactConfig{
 				HTTPReadTimeout: pointer.Of("5m"),
@@ -56,6 +72,14 @@ func TestArtifactConfig_Merge(t *testing.T) {
 				GitTimeout:      pointer.Of("2m"),
 				HgTimeout:       pointer.Of("3m"),
 				S3Timeout:       pointer.Of("4m"),
+				CacheMaxSize:    pointer.Of("1GB"),
+
+				DisableSandbox:              pointer.Of(true),
+				DisableFilesystemIsolation:  pointer.Of(true),
+				SandboxCPULimit:             pointer.Of(200),
+				SandboxMemoryLimit:          pointer.Of("2GB"),
+				DecompressionSizeLimit:      pointer.Of("1GB"),
+				DecompressionFileCountLimit: pointer.Of(100),
 			},
 		},
 		{
@@ -331,6 +355,79 @@ func TestArtifactConfig_Validate(t *testing.T) {
 			},
 			expectedError: "s3_timeout not a valid duration",
 		},
+		{
+			name: "cache max size is nil",
+			config: func(a *ArtifactConfig) {
+				a.CacheMaxSize = nil
+			},
+			expectedError: "cache_max_size must be set",
+		},
+		{
+			name: "cache max size is invalid",
+			config: func(a *ArtifactConfig) {
+				a.CacheMaxSize = pointer.Of("lots")
+			},
+			expectedError: "cache_max_size not a valid size",
+		},
+		{
+			name: "cache max size is zero",
+			config: func(a *ArtifactConfig) {
+				a.CacheMaxSize = pointer.Of("0")
+			},
+			expectedError: "",
+		},
+		{
+			name: "missing disable sandbox",
+			config: func(a *ArtifactConfig) {
+				a.DisableSandbox = nil
+			},
+			expectedError: "disable_sandbox must be set",
+		},
+		{
+			name: "missing disable filesystem isolation",
+			config: func(a *ArtifactConfig) {
+				a.DisableFilesystemIsolation = nil
+			},
+			expectedError: "disable_filesystem_isolation must be set",
+		},
+		{
+			name: "sandbox cpu limit is negative",
+			config: func(a *ArtifactConfig) {
+				a.SandboxCPULimit = pointer.Of(-1)
+			},
+			expectedError: "sandbox_cpu_limit must be >= 0",
+		},
+		{
+			name: "sandbox memory limit is invalid",
+			config: func(a *ArtifactConfig) {
+				a.SandboxMemoryLimit = pointer.Of("lots")
+			},
+			expectedError: "sandbox_memory_limit not a valid size",
+		},
+		{
+			name: "decompression size limit is invalid",
+			config: func(a *ArtifactConfig) {
+				a.DecompressionSizeLimit = pointer.Of("lots")
+			},
+			expectedError: "decompression_size_limit not a valid size",
+		},
+		{
+			name: "decompression file count limit is negative",
+			config: func(a *ArtifactConfig) {
+				a.DecompressionFileCountLimit = pointer.Of(-1)
+			},
+			expectedError: "decompression_file_count_limit must be >= 0",
+		},
+		{
+			name: "limits are zero",
+			config: func(a *ArtifactConfig) {
+				a.SandboxCPULimit = pointer.Of(0)
+				a.SandboxMemoryLimit = pointer.Of("0")
+				a.DecompressionSizeLimit = pointer.Of("0")
+				a.DecompressionFileCountLimit = pointer.Of(0)
+			},
+			expectedError: "",
+		},
 	}
 
 	for _, tc := range testCases {
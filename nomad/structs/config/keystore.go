@@ -0,0 +1,119 @@
+package config
+
+import (
+	"fmt"
+)
+
+const (
+	// KeystoreWrapperProviderFile wraps keys with a key encryption key read
+	// from a local file. It is a stand-in for a hardware security module.
+	KeystoreWrapperProviderFile = "file"
+
+	// KeystoreWrapperProviderTransit wraps keys with a named key held by a
+	// Vault transit compatible encryption service.
+	KeystoreWrapperProviderTransit = "transit"
+)
+
+// KeystoreWrapperConfig configures the wrapping of the root keys persisted to
+// a server's local keystore by a key encryption key held outside of it.
+type KeystoreWrapperConfig struct {
+	// Provider is the name of the key wrapping provider, either "file" or
+	// "transit".
+	Provider string `hcl:"provider"`
+
+	// KeyFile is the path to the file holding the base64 encoded 256-bit key
+	// encryption key used by the "file" provider.
+	KeyFile string `hcl:"key_file"`
+
+	// Address is the address of the encryption service used by the
+	// "transit" provider, ex: https://vault.example.com:8200
+	Address string `hcl:"address"`
+
+	// Token is the token used to authenticate with the encryption service.
+	Token string `hcl:"token"`
+
+	// MountPath is the path the transit secrets engine is mounted at.
+	// Defaults to "transit".
+	MountPath string `hcl:"mount_path"`
+
+	// KeyName is the name of the key encryption key in the transit secrets
+	// engine.
+	KeyName string `hcl:"key_name"`
+
+	// Namespace is the Vault Enterprise namespace of the transit secrets
+	// engine, if any.
+	Namespace string `hcl:"namespace"`
+}
+
+// Copy returns a copy of this keystore wrapper config.
+func (k *KeystoreWrapperConfig) Copy() *KeystoreWrapperConfig {
+	if k == nil {
+		return nil
+	}
+
+	nk := new(KeystoreWrapperConfig)
+	*nk = *k
+	return nk
+}
+
+// Merge returns a new keystore wrapper configuration by merging another
+// keystore wrapper configuration into this one.
+func (k *KeystoreWrapperConfig) Merge(o *KeystoreWrapperConfig) *KeystoreWrapperConfig {
+	if k == nil {
+		return o.Copy()
+	}
+
+	result := k.Copy()
+	if o == nil {
+		return result
+	}
+
+	if o.Provider != "" {
+		result.Provider = o.Provider
+	}
+	if o.KeyFile != "" {
+		result.KeyFile = o.KeyFile
+	}
+	if o.Address != "" {
+		result.Address = o.Address
+	}
+	if o.Token != "" {
+		result.Token = o.Token
+	}
+	if o.MountPath != "" {
+		result.MountPath = o.MountPath
+	}
+	if o.KeyName != "" {
+		result.KeyName = o.KeyName
+	}
+	if o.Namespace != "" {
+		result.Namespace = o.Namespace
+	}
+	return result
+}
+
+// Validate returns an error if the configuration of the provider is
+// incomplete.
+func (k *KeystoreWrapperConfig) Validate() error {
+	if k == nil {
+		return nil
+	}
+
+	switch k.Provider {
+	case KeystoreWrapperProviderFile:
+		if k.KeyFile == "" {
+			return fmt.Errorf("keystore_wrapper.key_file must be set for the %q provider", k.Provider)
+		}
+	case KeystoreWrapperProviderTransit:
+		if k.Address == "" {
+			return fmt.Errorf("keystore_wrapper.address must be set for the %q provider", k.Provider)
+		}
+		if k.KeyName == "" {
+			return fmt.Errorf("keystore_wrapper.key_name must be set for the %q provider", k.Provider)
+		}
+	default:
+		return fmt.Errorf("keystore_wrapper.provider must be one of %q or %q, got %q",
+			KeystoreWrapperProviderFile, KeystoreWrapperProviderTransit, k.Provider)
+	}
+	return nil
+}
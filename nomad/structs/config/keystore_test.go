@@ -0,0 +1,92 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeystoreWrapperConfig_Merge(t *testing.T) {
+	ci.Parallel(t)
+
+	a := &KeystoreWrapperConfig{
+		Provider: KeystoreWrapperProviderTransit,
+		Address:  "https://vault.example.com:8200",
+		KeyName:  "nomad",
+	}
+	b := &KeystoreWrapperConfig{
+		Token:   "secret",
+		KeyName: "nomad-keystore",
+	}
+
+	result := a.Merge(b)
+	require.Equal(t, &KeystoreWrapperConfig{
+		Provider: KeystoreWrapperProviderTransit,
+		Address:  "https://vault.example.com:8200",
+		Token:    "secret",
+		KeyName:  "nomad-keystore",
+	}, result)
+
+	// the merge does not modify the source
+	require.Equal(t, "nomad", a.KeyName)
+
+	var empty *KeystoreWrapperConfig
+	require.Equal(t, b, empty.Merge(b))
+	require.Equal(t, a, a.Merge(nil))
+}
+
+func TestKeystoreWrapperConfig_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	testCases := []struct {
+		name   string
+		config *KeystoreWrapperConfig
+		expErr string
+	}{
+		{
+			name:   "nil",
+			config: nil,
+		},
+		{
+			name: "file",
+			config: &KeystoreWrapperConfig{
+				Provider: KeystoreWrapperProviderFile,
+				KeyFile:  "/etc/nomad.d/kek",
+			},
+		},
+		{
+			name: "file missing key file",
+			config: &KeystoreWrapperConfig{
+				Provider: KeystoreWrapperProviderFile,
+			},
+			expErr: `keystore_wrapper.key_file must be set for the "file" provider`,
+		},
+		{
+			name: "transit missing key name",
+			config: &KeystoreWrapperConfig{
+				Provider: KeystoreWrapperProviderTransit,
+				Address:  "https://vault.example.com:8200",
+			},
+			expErr: `keystore_wrapper.key_name must be set for the "transit" provider`,
+		},
+		{
+			name: "unknown provider",
+			config: &KeystoreWrapperConfig{
+				Provider: "awskms",
+			},
+			expErr: `keystore_wrapper.provider must be one of "file" or "transit", got "awskms"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.config.Validate()
+			if tc.expErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tc.expErr)
+			}
+		})
+	}
+}
@@ -123,6 +123,17 @@ func (j *Job) Diff(other *Job, contextual bool) (*JobDiff, error) {
 		diff.Objects = append(diff.Objects, affinitiesDiff...)
 	}
 
+	// Dependencies diff
+	depDiff := primitiveObjectSetDiff(
+		interfaceSlice(j.DependsOn),
+		interfaceSlice(other.DependsOn),
+		nil,
+		"DependsOn",
+		contextual)
+	if depDiff != nil {
+		diff.Objects = append(diff.Objects, depDiff...)
+	}
+
 	// Task groups diff
 	tgs, err := taskGroupDiffs(j.TaskGroups, other.TaskGroups, contextual)
 	if err != nil {
@@ -505,7 +516,7 @@ func (t *Task) Diff(other *Task, contextual bool) (*TaskDiff, error) {
 	}
 
 	// LogConfig diff
-	lDiff := primitiveObjectDiff(t.LogConfig, other.LogConfig, nil, "LogConfig", contextual)
+	lDiff := logConfigDiff(t.LogConfig, other.LogConfig, contextual)
 	if lDiff != nil {
 		diff.Objects = append(diff.Objects, lDiff)
 	}
@@ -1442,7 +1453,7 @@ func sidecarTaskDiff(old, new *SidecarTask, contextual bool) *ObjectDiff {
 	}
 
 	// LogConfig diff
-	lDiff := primitiveObjectDiff(old.LogConfig, new.LogConfig, nil, "LogConfig", contextual)
+	lDiff := logConfigDiff(old.LogConfig, new.LogConfig, contextual)
 	if lDiff != nil {
 		diff.Objects = append(diff.Objects, lDiff)
 	}
@@ -2681,6 +2692,29 @@ func primitiveObjectSetDiff(old, new []interface{}, filter []string, name string
 	return diffs
 }
 
+// logConfigDiff returns the diff of two log configs, including their sinks.
+func logConfigDiff(old, new *LogConfig, contextual bool) *ObjectDiff {
+	diff := primitiveObjectDiff(old, new, nil, "LogConfig", contextual)
+
+	var oldSinks, newSinks []*LogSink
+	if old != nil {
+		oldSinks = old.Sinks
+	}
+	if new != nil {
+		newSinks = new.Sinks
+	}
+	sinkDiffs := primitiveObjectSetDiff(interfaceSlice(oldSinks), interfaceSlice(newSinks), nil, "Sink", contextual)
+	if len(sinkDiffs) == 0 {
+		return diff
+	}
+
+	if diff == nil {
+		diff = &ObjectDiff{Type: DiffTypeEdited, Name: "LogConfig"}
+	}
+	diff.Objects = append(diff.Objects, sinkDiffs...)
+	return diff
+}
+
 // interfaceSlice is a helper method that takes a slice of typed elements and
 // returns a slice of interface. This method will panic if given a non-slice
 // input.
@@ -174,6 +174,12 @@ func TestJobDiff(t *testing.T) {
 						Old:  "foo",
 						New:  "",
 					},
+					{
+						Type: DiffTypeDeleted,
+						Name: "NonPreemptible",
+						Old:  "false",
+						New:  "",
+					},
 					{
 						Type: DiffTypeDeleted,
 						Name: "Priority",
@@ -243,6 +249,12 @@ func TestJobDiff(t *testing.T) {
 						Old:  "",
 						New:  "foo",
 					},
+					{
+						Type: DiffTypeAdded,
+						Name: "NonPreemptible",
+						Old:  "",
+						New:  "false",
+					},
 					{
 						Type: DiffTypeAdded,
 						Name: "Priority",
@@ -902,6 +914,12 @@ func TestJobDiff(t *testing.T) {
 								Old:  "",
 								New:  "1",
 							},
+							{
+								Type: DiffTypeAdded,
+								Name: "Gang",
+								Old:  "",
+								New:  "false",
+							},
 						},
 					},
 					{
@@ -930,6 +948,12 @@ func TestJobDiff(t *testing.T) {
 								Old:  "1",
 								New:  "",
 							},
+							{
+								Type: DiffTypeDeleted,
+								Name: "Gang",
+								Old:  "false",
+								New:  "",
+							},
 						},
 					},
 				},
@@ -2896,6 +2920,12 @@ func TestTaskGroupDiff(t *testing.T) {
 								Old:  "task1",
 								New:  "task2",
 							},
+							{
+								Type: DiffTypeNone,
+								Name: "Weight",
+								Old:  "0",
+								New:  "0",
+							},
 						},
 						Objects: []*ObjectDiff{
 							{
@@ -4412,6 +4442,12 @@ func TestTaskDiff(t *testing.T) {
 						Type: DiffTypeAdded,
 						Name: "LogConfig",
 						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeAdded,
+								Name: "Compress",
+								Old:  "",
+								New:  "false",
+							},
 							{
 								Type: DiffTypeAdded,
 								Name: "MaxFileSizeMB",
@@ -4424,6 +4460,18 @@ func TestTaskDiff(t *testing.T) {
 								Old:  "",
 								New:  "1",
 							},
+							{
+								Type: DiffTypeAdded,
+								Name: "MaxTotalSizeMB",
+								Old:  "",
+								New:  "0",
+							},
+							{
+								Type: DiffTypeAdded,
+								Name: "RotationInterval",
+								Old:  "",
+								New:  "0",
+							},
 						},
 					},
 				},
@@ -4445,6 +4493,12 @@ func TestTaskDiff(t *testing.T) {
 						Type: DiffTypeDeleted,
 						Name: "LogConfig",
 						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeDeleted,
+								Name: "Compress",
+								Old:  "false",
+								New:  "",
+							},
 							{
 								Type: DiffTypeDeleted,
 								Name: "MaxFileSizeMB",
@@ -4457,6 +4511,18 @@ func TestTaskDiff(t *testing.T) {
 								Old:  "1",
 								New:  "",
 							},
+							{
+								Type: DiffTypeDeleted,
+								Name: "MaxTotalSizeMB",
+								Old:  "0",
+								New:  "",
+							},
+							{
+								Type: DiffTypeDeleted,
+								Name: "RotationInterval",
+								Old:  "0",
+								New:  "",
+							},
 						},
 					},
 				},
@@ -4500,6 +4566,80 @@ func TestTaskDiff(t *testing.T) {
 				},
 			},
 		},
+		{
+			Name: "LogConfig sink added",
+			Old: &Task{
+				LogConfig: &LogConfig{
+					MaxFiles:      1,
+					MaxFileSizeMB: 10,
+				},
+			},
+			New: &Task{
+				LogConfig: &LogConfig{
+					MaxFiles:      1,
+					MaxFileSizeMB: 10,
+					Sinks: []*LogSink{
+						{
+							Type:    LogSinkTypeTCP,
+							Address: "127.0.0.1:5000",
+						},
+					},
+				},
+			},
+			Expected: &TaskDiff{
+				Type: DiffTypeEdited,
+				Objects: []*ObjectDiff{
+					{
+						Type: DiffTypeEdited,
+						Name: "LogConfig",
+						Objects: []*ObjectDiff{
+							{
+								Type: DiffTypeAdded,
+								Name: "Sink",
+								Fields: []*FieldDiff{
+									{
+										Type: DiffTypeAdded,
+										Name: "Address",
+										Old:  "",
+										New:  "127.0.0.1:5000",
+									},
+									{
+										Type: DiffTypeAdded,
+										Name: "Backpressure",
+										Old:  "",
+										New:  "false",
+									},
+									{
+										Type: DiffTypeAdded,
+										Name: "BatchInterval",
+										Old:  "",
+										New:  "0",
+									},
+									{
+										Type: DiffTypeAdded,
+										Name: "BatchSize",
+										Old:  "",
+										New:  "0",
+									},
+									{
+										Type: DiffTypeAdded,
+										Name: "BufferSize",
+										Old:  "",
+										New:  "0",
+									},
+									{
+										Type: DiffTypeAdded,
+										Name: "Type",
+										Old:  "",
+										New:  "tcp",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			Name:       "LogConfig edited with context",
 			Contextual: true,
@@ -4522,6 +4662,12 @@ func TestTaskDiff(t *testing.T) {
 						Type: DiffTypeEdited,
 						Name: "LogConfig",
 						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeNone,
+								Name: "Compress",
+								Old:  "false",
+								New:  "false",
+							},
 							{
 								Type: DiffTypeEdited,
 								Name: "MaxFileSizeMB",
@@ -4534,6 +4680,18 @@ func TestTaskDiff(t *testing.T) {
 								Old:  "1",
 								New:  "1",
 							},
+							{
+								Type: DiffTypeNone,
+								Name: "MaxTotalSizeMB",
+								Old:  "0",
+								New:  "0",
+							},
+							{
+								Type: DiffTypeNone,
+								Name: "RotationInterval",
+								Old:  "0",
+								New:  "0",
+							},
 						},
 					},
 				},
@@ -5615,6 +5773,12 @@ func TestTaskDiff(t *testing.T) {
 								Old:  "",
 								New:  "bam",
 							},
+							{
+								Type: DiffTypeAdded,
+								Name: "Weight",
+								Old:  "",
+								New:  "0",
+							},
 						},
 					},
 					{
@@ -5639,6 +5803,12 @@ func TestTaskDiff(t *testing.T) {
 								Old:  "foo",
 								New:  "",
 							},
+							{
+								Type: DiffTypeDeleted,
+								Name: "Weight",
+								Old:  "0",
+								New:  "",
+							},
 						},
 					},
 				},
@@ -5725,6 +5895,12 @@ func TestTaskDiff(t *testing.T) {
 								Old:  "",
 								New:  "task1",
 							},
+							{
+								Type: DiffTypeNone,
+								Name: "Weight",
+								Old:  "0",
+								New:  "0",
+							},
 						},
 					},
 				},
@@ -5875,6 +6051,12 @@ func TestTaskDiff(t *testing.T) {
 								Type: DiffTypeNone,
 								Name: "TaskName",
 							},
+							{
+								Type: DiffTypeNone,
+								Name: "Weight",
+								Old:  "0",
+								New:  "0",
+							},
 						},
 					},
 				},
@@ -6406,6 +6588,12 @@ func TestTaskDiff(t *testing.T) {
 								Old:  "",
 								New:  "",
 							},
+							{
+								Type: DiffTypeNone,
+								Name: "Weight",
+								Old:  "0",
+								New:  "0",
+							},
 						},
 						Objects: []*ObjectDiff{
 							{
@@ -7635,6 +7823,12 @@ func TestServicesDiff(t *testing.T) {
 							Type: DiffTypeNone,
 							Name: "TaskName",
 						},
+						{
+							Type: DiffTypeNone,
+							Name: "Weight",
+							Old:  "0",
+							New:  "0",
+						},
 					},
 					Objects: []*ObjectDiff{
 						{
@@ -7728,6 +7922,12 @@ func TestServicesDiff(t *testing.T) {
 							Type: DiffTypeNone,
 							Name: "TaskName",
 						},
+						{
+							Type: DiffTypeAdded,
+							Name: "Weight",
+							Old:  "",
+							New:  "0",
+						},
 					},
 				},
 			},
@@ -7795,6 +7995,12 @@ func TestServicesDiff(t *testing.T) {
 							Type: DiffTypeNone,
 							Name: "TaskName",
 						},
+						{
+							Type: DiffTypeAdded,
+							Name: "Weight",
+							Old:  "",
+							New:  "0",
+						},
 					},
 				},
 			},
@@ -7868,6 +8074,12 @@ func TestServicesDiff(t *testing.T) {
 							Type: DiffTypeNone,
 							Name: "TaskName",
 						},
+						{
+							Type: DiffTypeNone,
+							Name: "Weight",
+							Old:  "0",
+							New:  "0",
+						},
 					},
 				},
 			},
@@ -7946,6 +8158,12 @@ func TestServicesDiff(t *testing.T) {
 							Type: DiffTypeNone,
 							Name: "TaskName",
 						},
+						{
+							Type: DiffTypeNone,
+							Name: "Weight",
+							Old:  "0",
+							New:  "0",
+						},
 					},
 					Objects: []*ObjectDiff{
 						{
@@ -8035,6 +8253,12 @@ func TestServicesDiff(t *testing.T) {
 							Type: DiffTypeNone,
 							Name: "TaskName",
 						},
+						{
+							Type: DiffTypeNone,
+							Name: "Weight",
+							Old:  "0",
+							New:  "0",
+						},
 					},
 				},
 			},
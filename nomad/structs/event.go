@@ -24,6 +24,10 @@ const (
 	TopicACLPolicy  Topic = "ACLPolicy"
 	TopicACLToken   Topic = "ACLToken"
 	TopicService    Topic = "Service"
+	TopicVariable   Topic = "Variable"
+	TopicNamespace  Topic = "Namespace"
+	TopicACLRole    Topic = "ACLRole"
+	TopicRootKey    Topic = "RootKey"
 	TopicAll        Topic = "*"
 
 	TypeNodeRegistration              = "NodeRegistration"
@@ -48,6 +52,14 @@ const (
 	TypeACLPolicyUpserted             = "ACLPolicyUpserted"
 	TypeServiceRegistration           = "ServiceRegistration"
 	TypeServiceDeregistration         = "ServiceDeregistration"
+	TypeVariableUpserted              = "VariableUpserted"
+	TypeVariableDeleted               = "VariableDeleted"
+	TypeNamespaceUpserted             = "NamespaceUpserted"
+	TypeNamespaceDeleted              = "NamespaceDeleted"
+	TypeACLRoleUpserted               = "ACLRoleUpserted"
+	TypeACLRoleDeleted                = "ACLRoleDeleted"
+	TypeRootKeyMetaUpserted           = "RootKeyMetaUpserted"
+	TypeRootKeyMetaDeleted            = "RootKeyMetaDeleted"
 )
 
 // Event represents a change in Nomads state.
@@ -151,3 +163,26 @@ func (a *ACLTokenEvent) SecretID() string {
 type ACLPolicyEvent struct {
 	ACLPolicy *ACLPolicy
 }
+
+// VariableEvent holds a newly updated or deleted variable. Only the metadata
+// of the variable is included, so the event never contains its items.
+type VariableEvent struct {
+	Variable *VariableMetadata
+}
+
+// NamespaceEvent holds a newly updated or deleted namespace.
+type NamespaceEvent struct {
+	Namespace *Namespace
+}
+
+// ACLRoleStreamEvent holds a newly updated or deleted ACL role.
+type ACLRoleStreamEvent struct {
+	ACLRole *ACLRole
+}
+
+// RootKeyMetaEvent holds newly updated or deleted root key metadata. The key
+// material itself is never stored in the state store, and so is never
+// included in events.
+type RootKeyMetaEvent struct {
+	RootKeyMeta *RootKeyMeta
+}
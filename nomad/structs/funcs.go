@@ -299,6 +299,41 @@ func ScoreFitSpread(node *Node, util *ComparableResources) float64 {
 	return score
 }
 
+// ScoreFitLeastRequested computes a fit score favoring the nodes with the
+// largest share of free resources. Score is in [0, 18]
+//
+// Unlike ScoreFitSpread, the score grows linearly with the free resources.
+func ScoreFitLeastRequested(node *Node, util *ComparableResources) float64 {
+	freePctCpu, freePctRam := computeFreePercentage(node, util)
+	score := 18.0 * (freePctCpu + freePctRam) / 2
+
+	if score > 18.0 {
+		score = 18.0
+	} else if score < 0 {
+		score = 0
+	}
+	return score
+}
+
+// FitsHeadroom returns whether the node keeps at least headroomPercent of its
+// CPU and memory free given the utilization. If not, the exhausted dimension
+// is returned.
+func FitsHeadroom(node *Node, util *ComparableResources, headroomPercent int) (bool, string) {
+	if headroomPercent <= 0 {
+		return true, ""
+	}
+
+	headroom := float64(headroomPercent) / 100
+	freePctCpu, freePctRam := computeFreePercentage(node, util)
+	if freePctCpu < headroom {
+		return false, "cpu headroom"
+	}
+	if freePctRam < headroom {
+		return false, "memory headroom"
+	}
+	return true, ""
+}
+
 func CopySliceConstraints(s []*Constraint) []*Constraint {
 	l := len(s)
 	if l == 0 {
@@ -312,6 +347,19 @@ func CopySliceConstraints(s []*Constraint) []*Constraint {
 	return c
 }
 
+func CopySliceJobDependencies(s []*JobDependency) []*JobDependency {
+	l := len(s)
+	if l == 0 {
+		return nil
+	}
+
+	c := make([]*JobDependency, l)
+	for i, v := range s {
+		c[i] = v.Copy()
+	}
+	return c
+}
+
 func CopySliceAffinities(s []*Affinity) []*Affinity {
 	l := len(s)
 	if l == 0 {
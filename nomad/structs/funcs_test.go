@@ -760,6 +760,131 @@ func TestScoreFitBinPack(t *testing.T) {
 	}
 }
 
+func TestScoreFitLeastRequested(t *testing.T) {
+	ci.Parallel(t)
+
+	node := &Node{}
+	node.NodeResources = &NodeResources{
+		Cpu: NodeCpuResources{
+			CpuShares: 4096,
+		},
+		Memory: NodeMemoryResources{
+			MemoryMB: 8192,
+		},
+	}
+	node.ReservedResources = &NodeReservedResources{
+		Cpu: NodeReservedCpuResources{
+			CpuShares: 2048,
+		},
+		Memory: NodeReservedMemoryResources{
+			MemoryMB: 4096,
+		},
+	}
+
+	cases := []struct {
+		name      string
+		flattened AllocatedTaskResources
+		score     float64
+	}{
+		{
+			name: "filled node",
+			flattened: AllocatedTaskResources{
+				Cpu:    AllocatedCpuResources{CpuShares: 2048},
+				Memory: AllocatedMemoryResources{MemoryMB: 4096},
+			},
+			score: 0,
+		},
+		{
+			name: "unutilized node",
+			flattened: AllocatedTaskResources{
+				Cpu:    AllocatedCpuResources{CpuShares: 0},
+				Memory: AllocatedMemoryResources{MemoryMB: 0},
+			},
+			score: 18,
+		},
+		{
+			name: "half utilized node",
+			flattened: AllocatedTaskResources{
+				Cpu:    AllocatedCpuResources{CpuShares: 1024},
+				Memory: AllocatedMemoryResources{MemoryMB: 2048},
+			},
+			score: 9,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			util := &ComparableResources{Flattened: c.flattened}
+			require.InDelta(t, c.score, ScoreFitLeastRequested(node, util), 0.001)
+		})
+	}
+}
+
+func TestFitsHeadroom(t *testing.T) {
+	ci.Parallel(t)
+
+	node := &Node{
+		NodeResources: &NodeResources{
+			Cpu:    NodeCpuResources{CpuShares: 4000},
+			Memory: NodeMemoryResources{MemoryMB: 4000},
+		},
+	}
+
+	cases := []struct {
+		name      string
+		flattened AllocatedTaskResources
+		headroom  int
+		fits      bool
+		dimension string
+	}{
+		{
+			name: "no headroom",
+			flattened: AllocatedTaskResources{
+				Cpu:    AllocatedCpuResources{CpuShares: 4000},
+				Memory: AllocatedMemoryResources{MemoryMB: 4000},
+			},
+			headroom: 0,
+			fits:     true,
+		},
+		{
+			name: "within headroom",
+			flattened: AllocatedTaskResources{
+				Cpu:    AllocatedCpuResources{CpuShares: 3000},
+				Memory: AllocatedMemoryResources{MemoryMB: 3000},
+			},
+			headroom: 25,
+			fits:     true,
+		},
+		{
+			name: "cpu exceeds headroom",
+			flattened: AllocatedTaskResources{
+				Cpu:    AllocatedCpuResources{CpuShares: 3500},
+				Memory: AllocatedMemoryResources{MemoryMB: 1000},
+			},
+			headroom:  25,
+			dimension: "cpu headroom",
+		},
+		{
+			name: "memory exceeds headroom",
+			flattened: AllocatedTaskResources{
+				Cpu:    AllocatedCpuResources{CpuShares: 1000},
+				Memory: AllocatedMemoryResources{MemoryMB: 3500},
+			},
+			headroom:  25,
+			dimension: "memory headroom",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			util := &ComparableResources{Flattened: c.flattened}
+			fits, dim := FitsHeadroom(node, util, c.headroom)
+			require.Equal(t, c.fits, fits)
+			require.Equal(t, c.dimension, dim)
+		})
+	}
+}
+
 func TestACLPolicyListHash(t *testing.T) {
 	ci.Parallel(t)
 
@@ -53,6 +53,11 @@ type RootKeyMeta struct {
 	CreateIndex uint64
 	ModifyIndex uint64
 	State       RootKeyState
+
+	// RekeyRemaining is the number of variables, including retained prior
+	// versions of variables, still encrypted with a key being rekeyed. It is
+	// computed when listing keys and is not stored.
+	RekeyRemaining int
 }
 
 // RootKeyState enum describes the lifecycle of a root key.
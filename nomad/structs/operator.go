@@ -147,13 +147,34 @@ const (
 	// SchedulerAlgorithmSpread indicates that the scheduler should spread
 	// allocations as evenly as possible over the available hardware.
 	SchedulerAlgorithmSpread SchedulerAlgorithm = "spread"
+
+	// SchedulerAlgorithmLeastRequestedHeadroom indicates that the scheduler
+	// should prefer the nodes with the most free resources, and never place
+	// allocations which would leave less than the headroom percentage of a
+	// node's resources free.
+	SchedulerAlgorithmLeastRequestedHeadroom SchedulerAlgorithm = "least-requested-with-headroom"
 )
 
+// Validate returns an error if the algorithm is not a known scheduler
+// algorithm. The empty algorithm is valid and means the default is used.
+func (a SchedulerAlgorithm) Validate() error {
+	switch a {
+	case "", SchedulerAlgorithmBinpack, SchedulerAlgorithmSpread, SchedulerAlgorithmLeastRequestedHeadroom:
+		return nil
+	default:
+		return fmt.Errorf("invalid scheduler algorithm: %v", a)
+	}
+}
+
 // SchedulerConfiguration is the config for controlling scheduler behavior
 type SchedulerConfiguration struct {
 	// SchedulerAlgorithm lets you select between available scheduling algorithms.
 	SchedulerAlgorithm SchedulerAlgorithm `hcl:"scheduler_algorithm"`
 
+	// HeadroomPercent is the percentage of the CPU and memory of each node
+	// which the least-requested-with-headroom algorithm keeps free.
+	HeadroomPercent int `hcl:"headroom_percent"`
+
 	// PreemptionConfig specifies whether to enable eviction of lower
 	// priority jobs to place higher priority jobs.
 	PreemptionConfig PreemptionConfig `hcl:"preemption_config"`
@@ -204,10 +225,16 @@ func (s *SchedulerConfiguration) Validate() error {
 		return nil
 	}
 
-	switch s.SchedulerAlgorithm {
-	case "", SchedulerAlgorithmBinpack, SchedulerAlgorithmSpread:
-	default:
-		return fmt.Errorf("invalid scheduler algorithm: %v", s.SchedulerAlgorithm)
+	if err := s.SchedulerAlgorithm.Validate(); err != nil {
+		return err
+	}
+
+	if s.HeadroomPercent < 0 || s.HeadroomPercent > 99 {
+		return fmt.Errorf("headroom percent must be between [0, 99]")
+	}
+
+	if s.PreemptionConfig.MaxPreemptionsPerMinute < 0 {
+		return fmt.Errorf("max preemptions per minute must not be negative")
 	}
 
 	return nil
@@ -244,6 +271,10 @@ type PreemptionConfig struct {
 
 	// ServiceSchedulerEnabled specifies if preemption is enabled for service jobs
 	ServiceSchedulerEnabled bool `hcl:"service_scheduler_enabled"`
+
+	// MaxPreemptionsPerMinute is the budget of allocations the plan applier
+	// allows to be preempted within a minute. Zero means no budget.
+	MaxPreemptionsPerMinute int `hcl:"max_preemptions_per_minute"`
 }
 
 // SchedulerSetConfigRequest is used by the Operator endpoint to update the
@@ -259,6 +290,67 @@ type SchedulerSetConfigRequest struct {
 	WriteRequest
 }
 
+// DefaultRebalanceMinScoreImprovement is the default minimum improvement of
+// an allocation's normalized placement score for it to be migrated by a
+// rebalance.
+const DefaultRebalanceMinScoreImprovement = 0.1
+
+// SchedulerRebalanceRequest is used by the Operator endpoint to migrate
+// allocations of service jobs to nodes where they would score better.
+type SchedulerRebalanceRequest struct {
+	// JobID limits the rebalance to a single job. If empty all the service
+	// jobs in the namespace are rebalanced, or in every namespace if the
+	// namespace is the wildcard.
+	JobID string
+
+	// MinScoreImprovement is the minimum improvement of the normalized
+	// placement score for an allocation to be migrated. Defaults to
+	// DefaultRebalanceMinScoreImprovement if zero.
+	MinScoreImprovement float64
+
+	// DryRun computes the migrations without marking any allocation for
+	// migration.
+	DryRun bool
+
+	WriteRequest
+}
+
+// RebalanceMigration is a proposed migration of an allocation to a node
+// where it would have a better placement score.
+type RebalanceMigration struct {
+	AllocID    string
+	AllocName  string
+	Namespace  string
+	JobID      string
+	TaskGroup  string
+	FromNodeID string
+	ToNodeID   string
+
+	// CurrentScore is the normalized score of the allocation on its node
+	CurrentScore float64
+
+	// ExpectedScore is the normalized score of the allocation on the node it
+	// would be migrated to
+	ExpectedScore float64
+}
+
+// ScoreImprovement returns the expected improvement of the placement score
+func (m *RebalanceMigration) ScoreImprovement() float64 {
+	return m.ExpectedScore - m.CurrentScore
+}
+
+// SchedulerRebalanceResponse is the response to a SchedulerRebalanceRequest.
+type SchedulerRebalanceResponse struct {
+	// Migrations are the allocations migrated, or that would be migrated if
+	// the request was a dry-run
+	Migrations []*RebalanceMigration
+
+	// EvalIDs are the evaluations created for the jobs with migrations
+	EvalIDs []string
+
+	WriteMeta
+}
+
 // SnapshotSaveRequest is used by the Operator endpoint to get a Raft snapshot
 type SnapshotSaveRequest struct {
 	QueryOptions
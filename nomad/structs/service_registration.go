@@ -37,6 +37,14 @@ const (
 	// Args: ServiceRegistrationByNameRequest
 	// Reply: ServiceRegistrationByNameResponse
 	ServiceRegistrationGetServiceRPCMethod = "ServiceRegistration.GetService"
+
+	// ServiceRegistrationUpdateChecksRPCMethod is the RPC method for updating
+	// the check results stored alongside the service registrations of an
+	// allocation.
+	//
+	// Args: ServiceRegistrationUpdateChecksRequest
+	// Reply: ServiceRegistrationUpdateChecksResponse
+	ServiceRegistrationUpdateChecksRPCMethod = "ServiceRegistration.UpdateChecks"
 )
 
 // ServiceRegistration is the internal representation of a Nomad service
@@ -78,6 +86,16 @@ type ServiceRegistration struct {
 	// services depending on their state and role.
 	Tags []string
 
+	// Meta is determined from either Service.Meta or Service.CanaryMeta and
+	// provides arbitrary metadata about this service, such as a version, for
+	// consumers of the registration.
+	Meta map[string]string
+
+	// Weight is the relative weight of this service registration compared to
+	// other registrations of the same service, as set by Service.Weight. It
+	// is intended for use by load balancers and is zero if not set.
+	Weight int
+
 	// Address is the IP address of this service registration. This information
 	// comes from the client and is not guaranteed to be routable; this depends
 	// on cluster network topology.
@@ -87,10 +105,55 @@ type ServiceRegistration struct {
 	// is determined by a combination of factors on the client.
 	Port int
 
+	// Checks contains the latest result of each check defined on the service
+	// block. The results are produced by the client running the allocation
+	// and are updated independently of the registration itself.
+	Checks []*ServiceRegistrationCheck
+
 	CreateIndex uint64
 	ModifyIndex uint64
 }
 
+// ServiceRegistrationCheck is the server side view of the latest result of a
+// single Nomad service check.
+type ServiceRegistrationCheck struct {
+
+	// Name is the name of the check as defined within the service block.
+	Name string
+
+	// Mode indicates whether the check is a healthiness or readiness check.
+	Mode CheckMode
+
+	// Status is the result of the most recent execution of the check.
+	Status CheckStatus
+
+	// Output is the output of the most recent execution of the check.
+	Output string
+
+	// Timestamp is the time, in seconds since the Unix epoch, the most recent
+	// execution of the check took place.
+	Timestamp int64
+}
+
+// Copy creates a copy of the check. It handles nil objects.
+func (c *ServiceRegistrationCheck) Copy() *ServiceRegistrationCheck {
+	if c == nil {
+		return nil
+	}
+	nc := new(ServiceRegistrationCheck)
+	*nc = *c
+	return nc
+}
+
+// Equals performs an equality check on the two checks. It handles nil
+// objects.
+func (c *ServiceRegistrationCheck) Equals(o *ServiceRegistrationCheck) bool {
+	if c == nil || o == nil {
+		return c == o
+	}
+	return *c == *o
+}
+
 // Copy creates a deep copy of the service registration. This copy can then be
 // safely modified. It handles nil objects.
 func (s *ServiceRegistration) Copy() *ServiceRegistration {
@@ -101,6 +164,14 @@ func (s *ServiceRegistration) Copy() *ServiceRegistration {
 	ns := new(ServiceRegistration)
 	*ns = *s
 	ns.Tags = helper.CopySliceString(ns.Tags)
+	ns.Meta = helper.CopyMapStringString(ns.Meta)
+
+	if s.Checks != nil {
+		ns.Checks = make([]*ServiceRegistrationCheck, len(s.Checks))
+		for i, check := range s.Checks {
+			ns.Checks[i] = check.Copy()
+		}
+	}
 
 	return ns
 }
@@ -141,6 +212,32 @@ func (s *ServiceRegistration) Equals(o *ServiceRegistration) bool {
 	if !helper.CompareSliceSetString(s.Tags, o.Tags) {
 		return false
 	}
+	if !helper.CompareMapStringString(s.Meta, o.Meta) {
+		return false
+	}
+	if s.Weight != o.Weight {
+		return false
+	}
+	if len(s.Checks) != len(o.Checks) {
+		return false
+	}
+	for i := range s.Checks {
+		if !s.Checks[i].Equals(o.Checks[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Healthy returns whether all checks of the service registration are
+// currently passing. A registration without checks is always considered
+// healthy, whereas a registration with a check which has not yet run is not.
+func (s *ServiceRegistration) Healthy() bool {
+	for _, check := range s.Checks {
+		if check.Status != CheckSuccess {
+			return false
+		}
+	}
 	return true
 }
 
@@ -267,6 +364,13 @@ type ServiceRegistrationStub struct {
 type ServiceRegistrationByNameRequest struct {
 	ServiceName string
 	Choose      string // stable selection of n services
+	Healthy     bool   // only return registrations with passing checks
+
+	// MetaKey and MetaValue only return registrations whose metadata holds
+	// the value for the key, when the key is set.
+	MetaKey   string
+	MetaValue string
+
 	QueryOptions
 }
 
@@ -276,3 +380,26 @@ type ServiceRegistrationByNameResponse struct {
 	Services []*ServiceRegistration
 	QueryMeta
 }
+
+// ServiceRegistrationUpdateChecksRequest is the request object used by Nomad
+// clients to update the check results of the service registrations belonging
+// to a single allocation.
+type ServiceRegistrationUpdateChecksRequest struct {
+
+	// AllocID is the allocation which the updated service registrations
+	// belong to.
+	AllocID string
+
+	// Checks maps the ID of each service registration to the latest results
+	// of its checks.
+	Checks map[string][]*ServiceRegistrationCheck
+
+	WriteRequest
+}
+
+// ServiceRegistrationUpdateChecksResponse is the response object when the
+// check results of one or more service registrations have been successfully
+// updated.
+type ServiceRegistrationUpdateChecksResponse struct {
+	WriteMeta
+}
@@ -383,6 +383,99 @@ func TestServiceRegistration_Equal(t *testing.T) {
 	}
 }
 
+func TestServiceRegistration_Equal_Checks(t *testing.T) {
+	sr := &ServiceRegistration{
+		ID:          "_nomad-task-2873cf75-42e5-7c45-ca1c-415f3e18be3d-group-cache-example-cache-db",
+		ServiceName: "example-cache",
+		Namespace:   "default",
+		AllocID:     "2873cf75-42e5-7c45-ca1c-415f3e18be3d",
+		Checks: []*ServiceRegistrationCheck{
+			{Name: "db-check", Mode: Healthiness, Status: CheckPending},
+		},
+	}
+
+	// A copy should be equal, and modifying its checks should not modify the
+	// original.
+	newSR := sr.Copy()
+	must.True(t, sr.Equals(newSR))
+
+	newSR.Checks[0].Status = CheckSuccess
+	must.Eq(t, CheckPending, sr.Checks[0].Status)
+	must.False(t, sr.Equals(newSR))
+
+	newSR.Checks = nil
+	must.False(t, sr.Equals(newSR))
+}
+
+func TestServiceRegistration_Equal_MetaWeight(t *testing.T) {
+	sr := &ServiceRegistration{
+		ID:          "_nomad-task-2873cf75-42e5-7c45-ca1c-415f3e18be3d-group-cache-example-cache-db",
+		ServiceName: "example-cache",
+		Namespace:   "default",
+		AllocID:     "2873cf75-42e5-7c45-ca1c-415f3e18be3d",
+		Meta:        map[string]string{"version": "1"},
+		Weight:      10,
+	}
+
+	// A copy should be equal, and modifying its meta should not modify the
+	// original.
+	newSR := sr.Copy()
+	must.True(t, sr.Equals(newSR))
+
+	newSR.Meta["version"] = "2"
+	must.Eq(t, "1", sr.Meta["version"])
+	must.False(t, sr.Equals(newSR))
+
+	newSR = sr.Copy()
+	newSR.Weight = 20
+	must.False(t, sr.Equals(newSR))
+}
+
+func TestServiceRegistration_Healthy(t *testing.T) {
+	testCases := []struct {
+		name           string
+		checks         []*ServiceRegistrationCheck
+		expectedOutput bool
+	}{
+		{
+			name:           "no checks",
+			checks:         nil,
+			expectedOutput: true,
+		},
+		{
+			name: "all passing",
+			checks: []*ServiceRegistrationCheck{
+				{Name: "http", Mode: Healthiness, Status: CheckSuccess},
+				{Name: "tcp", Mode: Readiness, Status: CheckSuccess},
+			},
+			expectedOutput: true,
+		},
+		{
+			name: "pending",
+			checks: []*ServiceRegistrationCheck{
+				{Name: "http", Mode: Healthiness, Status: CheckSuccess},
+				{Name: "tcp", Mode: Healthiness, Status: CheckPending},
+			},
+			expectedOutput: false,
+		},
+		{
+			name: "failing readiness",
+			checks: []*ServiceRegistrationCheck{
+				{Name: "http", Mode: Healthiness, Status: CheckSuccess},
+				{Name: "tcp", Mode: Readiness, Status: CheckFailure},
+			},
+			expectedOutput: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sr := &ServiceRegistration{Checks: tc.checks}
+			must.Eq(t, tc.expectedOutput, sr.Healthy())
+		})
+	}
+}
+
 func TestServiceRegistration_GetID(t *testing.T) {
 	testCases := []struct {
 		inputServiceRegistration *ServiceRegistration
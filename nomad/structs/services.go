@@ -325,7 +325,7 @@ func (sc *ServiceCheck) validateCommon(allowableTypes []string) error {
 
 // validate a Service's ServiceCheck in the context of the Nomad provider.
 func (sc *ServiceCheck) validateNomad() error {
-	allowable := []string{ServiceCheckTCP, ServiceCheckHTTP}
+	allowable := []string{ServiceCheckTCP, ServiceCheckHTTP, ServiceCheckGRPC, ServiceCheckScript}
 	if err := sc.validateCommon(allowable); err != nil {
 		return err
 	}
@@ -587,6 +587,11 @@ type Service struct {
 	// either ServiceProviderConsul or ServiceProviderNomad and defaults to the former when
 	// left empty by the operator.
 	Provider string
+
+	// Weight is the relative weight of the service compared to other
+	// instances, for use by load balancers. Only supported by the Nomad
+	// provider.
+	Weight int
 }
 
 // Copy the stanza recursively. Returns nil if nil.
@@ -761,6 +766,11 @@ func (s *Service) validateConsulService(mErr *multierror.Error) {
 			mErr.Errors = append(mErr.Errors, fmt.Errorf("Service %s is Connect Native and requires setting the task", s.Name))
 		}
 	}
+
+	// weight is only stored on nomad service registrations
+	if s.Weight != 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("Service %s weight may only be set for Nomad services", s.Name))
+	}
 }
 
 // validateNomadService performs validation on a service which is using the
@@ -777,6 +787,12 @@ func (s *Service) validateNomadService(mErr *multierror.Error) {
 		// validate the nomad check
 		if err := c.validateNomad(); err != nil {
 			mErr.Errors = append(mErr.Errors, err)
+			continue
+		}
+
+		// script checks are run in a task, which group services must name
+		if c.Type == ServiceCheckScript && s.TaskName == "" && c.TaskName == "" {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Check %s invalid: script checks of service %s must specify task", c.Name, s.Name))
 		}
 	}
 
@@ -784,6 +800,10 @@ func (s *Service) validateNomadService(mErr *multierror.Error) {
 	if s.Connect != nil {
 		mErr.Errors = append(mErr.Errors, errors.New("Service with provider nomad cannot include Connect blocks"))
 	}
+
+	if s.Weight < 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("Service %s weight must not be negative", s.Name))
+	}
 }
 
 // ValidateName checks if the service Name is valid and should be called after
@@ -822,6 +842,12 @@ func (s *Service) Hash(allocID, taskName string, canary bool) string {
 	hashString(h, s.OnUpdate)
 	hashString(h, s.Namespace)
 
+	// Only hash the weight when set, so services without one keep their
+	// existing hash.
+	if s.Weight != 0 {
+		hashString(h, strconv.Itoa(s.Weight))
+	}
+
 	// Don't hash the provider parameter, so we don't cause churn of all
 	// registered services when upgrading Nomad versions. The provider is not
 	// used at the level the hash is and therefore is not needed to tell
@@ -943,6 +969,10 @@ func (s *Service) Equals(o *Service) bool {
 		return false
 	}
 
+	if s.Weight != o.Weight {
+		return false
+	}
+
 	return true
 }
 
@@ -232,8 +232,34 @@ func TestServiceCheck_validateNomad(t *testing.T) {
 		sc   *ServiceCheck
 		exp  string
 	}{
-		{name: "grpc", sc: &ServiceCheck{Type: ServiceCheckGRPC}, exp: `invalid check type ("grpc"), must be one of tcp, http`},
-		{name: "script", sc: &ServiceCheck{Type: ServiceCheckScript}, exp: `invalid check type ("script"), must be one of tcp, http`},
+		{name: "ttl", sc: &ServiceCheck{Type: "ttl"}, exp: `invalid check type ("ttl"), must be one of tcp, http, grpc, script`},
+		{
+			name: "grpc",
+			sc: &ServiceCheck{
+				Type:        ServiceCheckGRPC,
+				Interval:    3 * time.Second,
+				Timeout:     1 * time.Second,
+				GRPCService: "health",
+			},
+		},
+		{
+			name: "script",
+			sc: &ServiceCheck{
+				Type:     ServiceCheckScript,
+				Interval: 3 * time.Second,
+				Timeout:  1 * time.Second,
+				Command:  "/bin/true",
+			},
+		},
+		{
+			name: "script without command",
+			sc: &ServiceCheck{
+				Type:     ServiceCheckScript,
+				Interval: 3 * time.Second,
+				Timeout:  1 * time.Second,
+			},
+			exp: `script type must have a valid script path`,
+		},
 		{
 			name: "expose",
 			sc: &ServiceCheck{
@@ -1939,10 +1965,73 @@ func TestService_validateNomadService(t *testing.T) {
 			},
 			inputErr: &multierror.Error{},
 			expectedOutputErrors: []error{
-				errors.New(`invalid check type (""), must be one of tcp, http`),
+				errors.New(`invalid check type (""), must be one of tcp, http, grpc, script`),
 			},
 			name: "bad nomad check",
 		},
+		{
+			inputService: &Service{
+				Name:      "webapp",
+				PortLabel: "http",
+				Namespace: "default",
+				Provider:  "nomad",
+				Weight:    10,
+			},
+			inputErr:             &multierror.Error{},
+			expectedOutputErrors: nil,
+			name:                 "valid service with weight",
+		},
+		{
+			inputService: &Service{
+				Name:      "webapp",
+				PortLabel: "http",
+				Namespace: "default",
+				Provider:  "nomad",
+				Weight:    -1,
+			},
+			inputErr:             &multierror.Error{},
+			expectedOutputErrors: []error{errors.New("Service webapp weight must not be negative")},
+			name:                 "invalid service due to negative weight",
+		},
+		{
+			inputService: &Service{
+				Name:      "webapp",
+				PortLabel: "http",
+				Namespace: "default",
+				Provider:  "nomad",
+				Checks: []*ServiceCheck{{
+					Name:     "webapp-script",
+					Type:     ServiceCheckScript,
+					Command:  "/bin/true",
+					Interval: 3 * time.Second,
+					Timeout:  1 * time.Second,
+				}},
+			},
+			inputErr: &multierror.Error{},
+			expectedOutputErrors: []error{
+				errors.New("Check webapp-script invalid: script checks of service webapp must specify task"),
+			},
+			name: "invalid script check without task",
+		},
+		{
+			inputService: &Service{
+				Name:      "webapp",
+				PortLabel: "http",
+				Namespace: "default",
+				Provider:  "nomad",
+				Checks: []*ServiceCheck{{
+					Name:     "webapp-script",
+					Type:     ServiceCheckScript,
+					Command:  "/bin/true",
+					Interval: 3 * time.Second,
+					Timeout:  1 * time.Second,
+					TaskName: "web",
+				}},
+			},
+			inputErr:             &multierror.Error{},
+			expectedOutputErrors: nil,
+			name:                 "valid script check with task",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -16,6 +16,7 @@ import (
 	"hash/crc32"
 	"math"
 	"net"
+	"net/url"
 	"os"
 	"reflect"
 	"regexp"
@@ -116,6 +117,7 @@ const (
 	RootKeyMetaDeleteRequestType                 MessageType = 52
 	ACLRolesUpsertRequestType                    MessageType = 53
 	ACLRolesDeleteByIDRequestType                MessageType = 54
+	ServiceRegistrationUpdateChecksRequestType   MessageType = 55
 
 	// Namespace types were moved from enterprise and therefore start at 64
 	NamespaceUpsertRequestType MessageType = 64
@@ -176,6 +178,11 @@ const (
 	// retain scoring metadata
 	MaxRetainedNodeScores = 5
 
+	// MaxPersistedScoreExplanations is the number of top scoring nodes for
+	// which the scores are explained in the metrics of a placement made by
+	// an evaluation, bounding the size of the metrics written to raft
+	MaxPersistedScoreExplanations = 50
+
 	// Normalized scorer name
 	NormScorerName = "normalized-score"
 
@@ -676,6 +683,10 @@ type JobEvaluateRequest struct {
 // EvalOptions is used to encapsulate options when forcing a job evaluation
 type EvalOptions struct {
 	ForceReschedule bool
+
+	// ExplainScores records the scores of the nodes scored for each
+	// placement made by the evaluation.
+	ExplainScores bool
 }
 
 // JobSpecificRequest is used when we just need to specify a target job
@@ -693,8 +704,9 @@ type JobListRequest struct {
 // JobPlanRequest is used for the Job.Plan endpoint to trigger a dry-run
 // evaluation of the Job.
 type JobPlanRequest struct {
-	Job  *Job
-	Diff bool // Toggles an annotated diff
+	Job     *Job
+	Diff    bool // Toggles an annotated diff
+	Explain bool // Toggles the explanation of placement scores
 	// PolicyOverride is set when the user is attempting to override any policies
 	PolicyOverride bool
 	WriteRequest
@@ -1503,9 +1515,28 @@ type JobPlanResponse struct {
 	// deprecation warnings.
 	Warnings string
 
+	// ScoreExplanations explains the node selected for each placement, sorted
+	// by allocation name. It is only populated when the request set Explain.
+	ScoreExplanations []*PlacementExplanation
+
 	WriteMeta
 }
 
+// PlacementExplanation explains the node selected for a placement by listing
+// the scores of every node scored for it.
+type PlacementExplanation struct {
+	// AllocName and TaskGroup identify the placement
+	AllocName string
+	TaskGroup string
+
+	// NodeID is the ID of the node selected for the placement
+	NodeID string
+
+	// Scores holds the scores of every node scored, sorted by normalized
+	// score.
+	Scores []*NodeScoreMeta
+}
+
 // SingleAllocResponse is used to return a single allocation
 type SingleAllocResponse struct {
 	Alloc *Allocation
@@ -4074,6 +4105,14 @@ type Job struct {
 	// can slow down larger jobs if resources are not available.
 	AllAtOnce bool
 
+	// NonPreemptible prevents the allocations of the job from being
+	// preempted by higher priority jobs.
+	NonPreemptible bool
+
+	// SchedulerAlgorithm overrides the scheduler algorithm of the namespace
+	// and the cluster for the job.
+	SchedulerAlgorithm SchedulerAlgorithm
+
 	// Datacenters contains all the datacenters this job is allowed to span
 	Datacenters []string
 
@@ -4089,6 +4128,10 @@ type Job struct {
 	// allocations across a desired attribute, such as datacenter
 	Spreads []*Spread
 
+	// DependsOn holds back the placement of the job until other jobs of its
+	// namespace reach the required state.
+	DependsOn []*JobDependency
+
 	// TaskGroups are the collections of task groups that this job needs
 	// to run. Each task group is an atomic unit of scheduling and placement.
 	TaskGroups []*TaskGroup
@@ -4251,6 +4294,7 @@ func (j *Job) Copy() *Job {
 	nj.Datacenters = helper.CopySliceString(nj.Datacenters)
 	nj.Constraints = CopySliceConstraints(nj.Constraints)
 	nj.Affinities = CopySliceAffinities(nj.Affinities)
+	nj.DependsOn = CopySliceJobDependencies(nj.DependsOn)
 	nj.Multiregion = nj.Multiregion.Copy()
 
 	if j.TaskGroups != nil {
@@ -4299,6 +4343,9 @@ func (j *Job) Validate() error {
 	if j.Priority < JobMinPriority || j.Priority > JobMaxPriority {
 		mErr.Errors = append(mErr.Errors, fmt.Errorf("Job priority must be between [%d, %d]", JobMinPriority, JobMaxPriority))
 	}
+	if err := j.SchedulerAlgorithm.Validate(); err != nil {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("Invalid scheduler algorithm: %q", j.SchedulerAlgorithm))
+	}
 	if len(j.Datacenters) == 0 && !j.IsMultiregion() {
 		mErr.Errors = append(mErr.Errors, errors.New("Missing job datacenters"))
 	} else {
@@ -4343,6 +4390,18 @@ func (j *Job) Validate() error {
 		}
 	}
 
+	if len(j.DependsOn) > 0 && j.Type != JobTypeService && j.Type != JobTypeBatch {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf(
+			"Dependencies can only be used with %q or %q scheduler", JobTypeService, JobTypeBatch,
+		))
+	}
+	for idx, dep := range j.DependsOn {
+		if err := dep.Validate(j); err != nil {
+			outer := fmt.Errorf("Dependency %d validation failed: %s", idx+1, err)
+			mErr.Errors = append(mErr.Errors, outer)
+		}
+	}
+
 	// Check for duplicate task groups
 	taskGroups := make(map[string]int)
 	for idx, tg := range j.TaskGroups {
@@ -4993,6 +5052,14 @@ type Namespace struct {
 	// Capabilities is the set of capabilities allowed for this namespace
 	Capabilities *NamespaceCapabilities
 
+	// PreemptionPriorityCeiling caps the priority with which jobs of the
+	// namespace may preempt allocations. Zero means no ceiling.
+	PreemptionPriorityCeiling int
+
+	// SchedulerAlgorithm overrides the scheduler algorithm of the cluster
+	// for the jobs of the namespace which don't set their own.
+	SchedulerAlgorithm SchedulerAlgorithm
+
 	// Meta is the set of metadata key/value pairs that attached to the namespace
 	Meta map[string]string
 
@@ -5024,6 +5091,13 @@ func (n *Namespace) Validate() error {
 		err := fmt.Errorf("description longer than %d", maxNamespaceDescriptionLength)
 		mErr.Errors = append(mErr.Errors, err)
 	}
+	if n.PreemptionPriorityCeiling < 0 || n.PreemptionPriorityCeiling > JobMaxPriority {
+		err := fmt.Errorf("preemption priority ceiling must be between [0, %d]", JobMaxPriority)
+		mErr.Errors = append(mErr.Errors, err)
+	}
+	if err := n.SchedulerAlgorithm.Validate(); err != nil {
+		mErr.Errors = append(mErr.Errors, err)
+	}
 
 	return mErr.ErrorOrNil()
 }
@@ -5048,6 +5122,12 @@ func (n *Namespace) SetHash() []byte {
 			_, _ = hash.Write([]byte(driver))
 		}
 	}
+	if n.PreemptionPriorityCeiling != 0 {
+		_, _ = hash.Write([]byte(strconv.Itoa(n.PreemptionPriorityCeiling)))
+	}
+	if n.SchedulerAlgorithm != "" {
+		_, _ = hash.Write([]byte(n.SchedulerAlgorithm))
+	}
 
 	// sort keys to ensure hash stability when meta is stored later
 	var keys []string
@@ -5137,6 +5217,63 @@ type NamespaceUpsertRequest struct {
 	WriteRequest
 }
 
+const (
+	// JobDependencyConditionHealthy is met once the latest deployment of the
+	// job or group is healthy. For jobs without deployments, all of the
+	// allocations of the job or group must be running.
+	JobDependencyConditionHealthy = "healthy"
+
+	// JobDependencyConditionRunning is met once an allocation of the job or
+	// group is running.
+	JobDependencyConditionRunning = "running"
+
+	// JobDependencyConditionComplete is met once all of the allocations of
+	// the job or group have completed successfully.
+	JobDependencyConditionComplete = "complete"
+)
+
+// JobDependency holds back the placement of a job until another job of the
+// same namespace, or one of its groups, reaches the required state.
+type JobDependency struct {
+	// Job is the ID of the job depended on.
+	Job string
+
+	// Group optionally restricts the dependency to a group of the job.
+	Group string
+
+	// Condition is the state the job or group must reach.
+	Condition string
+}
+
+func (d *JobDependency) Copy() *JobDependency {
+	if d == nil {
+		return nil
+	}
+	nd := new(JobDependency)
+	*nd = *d
+	return nd
+}
+
+// Validate is used to check a job dependency for reasonable configuration.
+func (d *JobDependency) Validate(job *Job) error {
+	var mErr multierror.Error
+
+	if d.Job == "" {
+		mErr.Errors = append(mErr.Errors, errors.New("Missing job ID"))
+	} else if d.Job == job.ID {
+		mErr.Errors = append(mErr.Errors, errors.New("Job can not depend on itself"))
+	}
+
+	switch d.Condition {
+	case JobDependencyConditionHealthy, JobDependencyConditionRunning, JobDependencyConditionComplete:
+	default:
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("Invalid condition %q: must be one of %q, %q or %q", d.Condition,
+			JobDependencyConditionHealthy, JobDependencyConditionRunning, JobDependencyConditionComplete))
+	}
+
+	return mErr.ErrorOrNil()
+}
+
 const (
 	// PeriodicSpecCron is used for a cron spec.
 	PeriodicSpecCron = "cron"
@@ -6156,6 +6293,10 @@ type TaskGroup struct {
 	// MaxClientDisconnect, if set, configures the client to allow placed
 	// allocations for tasks in this group to attempt to resume running without a restart.
 	MaxClientDisconnect *time.Duration
+
+	// Gang requires all the allocations of the task group placed by an
+	// evaluation to be placed at once, or none of them.
+	Gang bool
 }
 
 func (tg *TaskGroup) Copy() *TaskGroup {
@@ -6306,6 +6447,10 @@ func (tg *TaskGroup) Validate(j *Job) error {
 		mErr.Errors = append(mErr.Errors, errors.New("max_client_disconnect cannot be negative"))
 	}
 
+	if tg.Gang && j.Type != JobTypeBatch {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("Task group gang scheduling is only supported for %q jobs", JobTypeBatch))
+	}
+
 	for idx, constr := range tg.Constraints {
 		if err := constr.Validate(); err != nil {
 			outer := fmt.Errorf("Constraint %d validation failed: %s", idx+1, err)
@@ -6894,6 +7039,22 @@ const (
 type LogConfig struct {
 	MaxFiles      int
 	MaxFileSizeMB int
+
+	// RotationInterval rotates the current log file once it has been
+	// written to for this long, regardless of its size. Zero disables
+	// time-based rotation.
+	RotationInterval time.Duration
+
+	// Compress gzips log files once they are rotated.
+	Compress bool
+
+	// MaxTotalSizeMB caps the combined size of the task's stdout and stderr
+	// log files, deleting the oldest files first. Zero disables the cap.
+	MaxTotalSizeMB int
+
+	// Sinks are external destinations the task's stdout and stderr are
+	// forwarded to in addition to the rotated log files.
+	Sinks []*LogSink
 }
 
 func (l *LogConfig) Equals(o *LogConfig) bool {
@@ -6909,6 +7070,27 @@ func (l *LogConfig) Equals(o *LogConfig) bool {
 		return false
 	}
 
+	if l.RotationInterval != o.RotationInterval {
+		return false
+	}
+
+	if l.Compress != o.Compress {
+		return false
+	}
+
+	if l.MaxTotalSizeMB != o.MaxTotalSizeMB {
+		return false
+	}
+
+	if len(l.Sinks) != len(o.Sinks) {
+		return false
+	}
+	for i, sink := range l.Sinks {
+		if !sink.Equals(o.Sinks[i]) {
+			return false
+		}
+	}
+
 	return true
 }
 
@@ -6916,10 +7098,20 @@ func (l *LogConfig) Copy() *LogConfig {
 	if l == nil {
 		return nil
 	}
-	return &LogConfig{
-		MaxFiles:      l.MaxFiles,
-		MaxFileSizeMB: l.MaxFileSizeMB,
+	nl := &LogConfig{
+		MaxFiles:         l.MaxFiles,
+		MaxFileSizeMB:    l.MaxFileSizeMB,
+		RotationInterval: l.RotationInterval,
+		Compress:         l.Compress,
+		MaxTotalSizeMB:   l.MaxTotalSizeMB,
 	}
+	if l.Sinks != nil {
+		nl.Sinks = make([]*LogSink, len(l.Sinks))
+		for i, sink := range l.Sinks {
+			nl.Sinks[i] = sink.Copy()
+		}
+	}
+	return nl
 }
 
 // DefaultLogConfig returns the default LogConfig values.
@@ -6932,6 +7124,11 @@ func DefaultLogConfig() *LogConfig {
 
 // Validate returns an error if the log config specified are less than
 // the minimum allowed.
+// maxCompressedLogFileSizeMB is the largest file size of compressed logs. The
+// uncompressed size of a log is read from the gzip trailer, which records it
+// modulo 4GiB.
+const maxCompressedLogFileSizeMB = 4095
+
 func (l *LogConfig) Validate() error {
 	var mErr multierror.Error
 	if l.MaxFiles < 1 {
@@ -6939,6 +7136,130 @@ func (l *LogConfig) Validate() error {
 	}
 	if l.MaxFileSizeMB < 1 {
 		mErr.Errors = append(mErr.Errors, fmt.Errorf("minimum file size is 1MB; got %d", l.MaxFileSizeMB))
+	} else if l.Compress && l.MaxFileSizeMB > maxCompressedLogFileSizeMB {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("maximum file size with compression is %dMB; got %d", maxCompressedLogFileSizeMB, l.MaxFileSizeMB))
+	}
+	if l.RotationInterval < 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("rotation interval must be greater than or equal to 0; got %v", l.RotationInterval))
+	} else if l.RotationInterval > 0 && l.RotationInterval < time.Minute {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("minimum rotation interval is 1m; got %v", l.RotationInterval))
+	}
+	if l.MaxTotalSizeMB < 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("max total size must be greater than or equal to 0; got %d", l.MaxTotalSizeMB))
+	} else if l.MaxTotalSizeMB > 0 && l.MaxTotalSizeMB < 2*l.MaxFileSizeMB {
+		// The file currently being written to for each of stdout and stderr
+		// is never deleted, so the cap must leave room for both
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("max total size must be at least twice the max file size (%dMB); got %d", 2*l.MaxFileSizeMB, l.MaxTotalSizeMB))
+	}
+	for i, sink := range l.Sinks {
+		if err := sink.Validate(); err != nil {
+			mErr.Errors = append(mErr.Errors, multierror.Prefix(err, fmt.Sprintf("sink %d:", i+1)))
+		}
+	}
+	return mErr.ErrorOrNil()
+}
+
+const (
+	LogSinkTypeSyslog = "syslog"
+	LogSinkTypeTCP    = "tcp"
+	LogSinkTypeUDP    = "udp"
+	LogSinkTypeHTTP   = "http"
+)
+
+// LogSink is an external destination task logs are shipped to.
+type LogSink struct {
+	// Type is the kind of sink: syslog, tcp, udp or http
+	Type string
+
+	// Address is the host:port of the sink, or the URL for http sinks
+	Address string
+
+	// Protocol is the transport used by syslog sinks, udp or tcp
+	Protocol string
+
+	// Facility is the syslog facility, such as local0
+	Facility string
+
+	// Tag is the syslog app name. It defaults to the task name.
+	Tag string
+
+	// BatchSize is the maximum number of log lines sent in a single http
+	// request.
+	BatchSize int
+
+	// BatchInterval is the maximum time lines are held before being sent.
+	BatchInterval time.Duration
+
+	// BufferSize is the number of log lines buffered while the sink is
+	// slow or unavailable.
+	BufferSize int
+
+	// Backpressure blocks the task's output while the buffer is full
+	// instead of dropping log lines.
+	Backpressure bool
+}
+
+func (s *LogSink) Equals(o *LogSink) bool {
+	if s == nil || o == nil {
+		return s == o
+	}
+	return *s == *o
+}
+
+func (s *LogSink) Copy() *LogSink {
+	if s == nil {
+		return nil
+	}
+	ns := new(LogSink)
+	*ns = *s
+	return ns
+}
+
+// syslogFacilities are the facilities accepted by syslog sinks.
+var syslogFacilities = map[string]struct{}{
+	"kern": {}, "user": {}, "mail": {}, "daemon": {}, "auth": {}, "syslog": {},
+	"lpr": {}, "news": {}, "uucp": {}, "cron": {}, "authpriv": {}, "ftp": {},
+	"local0": {}, "local1": {}, "local2": {}, "local3": {},
+	"local4": {}, "local5": {}, "local6": {}, "local7": {},
+}
+
+// Validate returns an error if the sink is misconfigured.
+func (s *LogSink) Validate() error {
+	var mErr multierror.Error
+	switch s.Type {
+	case LogSinkTypeSyslog:
+		if s.Protocol != "" && s.Protocol != "udp" && s.Protocol != "tcp" {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("syslog protocol must be udp or tcp; got %q", s.Protocol))
+		}
+		if _, ok := syslogFacilities[s.Facility]; s.Facility != "" && !ok {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("unknown syslog facility %q", s.Facility))
+		}
+		fallthrough
+	case LogSinkTypeTCP, LogSinkTypeUDP:
+		if _, _, err := net.SplitHostPort(s.Address); err != nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("address must be host:port: %v", err))
+		}
+	case LogSinkTypeHTTP:
+		if u, err := url.Parse(s.Address); err != nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid address: %v", err))
+		} else if u.Scheme != "http" && u.Scheme != "https" {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("address must be an http or https URL; got %q", s.Address))
+		}
+	default:
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("unknown sink type %q", s.Type))
+	}
+
+	if s.Type != LogSinkTypeSyslog && (s.Protocol != "" || s.Facility != "") {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("protocol and facility are only valid for syslog sinks"))
+	}
+	if s.BatchSize < 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("batch size must be greater than or equal to 0; got %d", s.BatchSize))
+	}
+	if s.BatchInterval < 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("batch interval must be greater than or equal to 0; got %v", s.BatchInterval))
+	}
+	if s.BufferSize < 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("buffer size must be greater than or equal to 0; got %d", s.BufferSize))
 	}
 	return mErr.ErrorOrNil()
 }
@@ -7233,6 +7554,9 @@ func (t *Task) Validate(ephemeralDisk *EphemeralDisk, jobType string, tgServices
 
 	if t.LogConfig != nil && ephemeralDisk != nil {
 		logUsage := (t.LogConfig.MaxFiles * t.LogConfig.MaxFileSizeMB)
+		if t.LogConfig.MaxTotalSizeMB > 0 && t.LogConfig.MaxTotalSizeMB < logUsage {
+			logUsage = t.LogConfig.MaxTotalSizeMB
+		}
 		if ephemeralDisk.SizeMB <= logUsage {
 			mErr.Errors = append(mErr.Errors,
 				fmt.Errorf("log storage (%d MB) must be less than requested disk capacity (%d MB)",
@@ -8702,10 +9026,101 @@ const (
 	ConstraintSetContains       = "set_contains"
 	ConstraintSetContainsAll    = "set_contains_all"
 	ConstraintSetContainsAny    = "set_contains_any"
+	ConstraintSetContainsNone   = "set_contains_none"
 	ConstraintAttributeIsSet    = "is_set"
 	ConstraintAttributeIsNotSet = "is_not_set"
+	ConstraintCIDRContains      = "cidr_contains"
+
+	// The numeric operators compare both values as numbers, and are never
+	// satisfied if either value is not a number.
+	ConstraintNumericLess         = "num_lt"
+	ConstraintNumericLessEqual    = "num_lte"
+	ConstraintNumericGreater      = "num_gt"
+	ConstraintNumericGreaterEqual = "num_gte"
 )
 
+const (
+	// ConstraintAllocsPrefix is the prefix of constraint and affinity
+	// targets that resolve against the allocations already running on a
+	// node rather than the node itself.
+	ConstraintAllocsPrefix = "${allocs."
+
+	// ConstraintAllocsJobID resolves to the set of job IDs of the
+	// allocations on a node.
+	ConstraintAllocsJobID = "${allocs.job_id}"
+
+	// ConstraintAllocsMetaPrefix resolves to the set of values of a meta key
+	// across the allocations on a node, such as "${allocs.meta.tier}".
+	ConstraintAllocsMetaPrefix = "${allocs.meta."
+)
+
+// IsAllocsTarget returns true if the target of a constraint or affinity
+// resolves against the allocations on a node.
+func IsAllocsTarget(target string) bool {
+	return strings.HasPrefix(target, ConstraintAllocsPrefix)
+}
+
+// validateAllocsTarget validates the use of allocation targets by a
+// constraint or affinity. Allocation targets resolve to a set of values so
+// they may only be used as the LTarget of a set operator.
+func validateAllocsTarget(ltarget, rtarget, operand string) error {
+	if IsAllocsTarget(rtarget) {
+		return fmt.Errorf("Allocation target %q can only be used as the LTarget", rtarget)
+	}
+	if !IsAllocsTarget(ltarget) {
+		return nil
+	}
+
+	switch {
+	case ltarget == ConstraintAllocsJobID:
+	case strings.HasPrefix(ltarget, ConstraintAllocsMetaPrefix) &&
+		strings.HasSuffix(ltarget, "}") &&
+		len(ltarget) > len(ConstraintAllocsMetaPrefix)+1:
+	default:
+		return fmt.Errorf("Unknown allocation target %q", ltarget)
+	}
+
+	switch operand {
+	case ConstraintSetContains, ConstraintSetContainsAll,
+		ConstraintSetContainsAny, ConstraintSetContainsNone:
+		return nil
+	default:
+		return fmt.Errorf("Allocation target %q requires a set operator, got %q", ltarget, operand)
+	}
+}
+
+// validateCIDRTarget validates the RTarget of the cidr_contains operator,
+// which is a comma separated list of CIDR blocks unless it is interpolated.
+func validateCIDRTarget(rtarget string) error {
+	if rtarget == "" {
+		return fmt.Errorf("Operator %q requires an RTarget", ConstraintCIDRContains)
+	}
+	if strings.HasPrefix(rtarget, "${") {
+		return nil
+	}
+	for _, cidr := range strings.Split(rtarget, ",") {
+		if _, _, err := net.ParseCIDR(strings.TrimSpace(cidr)); err != nil {
+			return fmt.Errorf("CIDR block %q is invalid: %v", cidr, err)
+		}
+	}
+	return nil
+}
+
+// validateNumericTarget validates the RTarget of a numeric operator, which
+// must be a number unless it is interpolated.
+func validateNumericTarget(operand, rtarget string) error {
+	if rtarget == "" {
+		return fmt.Errorf("Operator %q requires an RTarget", operand)
+	}
+	if strings.HasPrefix(rtarget, "${") {
+		return nil
+	}
+	if _, err := strconv.ParseFloat(rtarget, 64); err != nil {
+		return fmt.Errorf("Operator %q requires a numeric RTarget: %q", operand, rtarget)
+	}
+	return nil
+}
+
 // A Constraint is used to restrict placement options.
 type Constraint struct {
 	LTarget string // Left-hand target
@@ -8755,7 +9170,7 @@ func (c *Constraint) Validate() error {
 	switch c.Operand {
 	case ConstraintDistinctHosts:
 		requireLtarget = false
-	case ConstraintSetContainsAll, ConstraintSetContainsAny, ConstraintSetContains:
+	case ConstraintSetContainsAll, ConstraintSetContainsAny, ConstraintSetContains, ConstraintSetContainsNone:
 		if c.RTarget == "" {
 			mErr.Errors = append(mErr.Errors, fmt.Errorf("Set contains constraint requires an RTarget"))
 		}
@@ -8785,6 +9200,14 @@ func (c *Constraint) Validate() error {
 		if c.RTarget != "" {
 			mErr.Errors = append(mErr.Errors, fmt.Errorf("Operator %q does not support an RTarget", c.Operand))
 		}
+	case ConstraintCIDRContains:
+		if err := validateCIDRTarget(c.RTarget); err != nil {
+			mErr.Errors = append(mErr.Errors, err)
+		}
+	case ConstraintNumericLess, ConstraintNumericLessEqual, ConstraintNumericGreater, ConstraintNumericGreaterEqual:
+		if err := validateNumericTarget(c.Operand, c.RTarget); err != nil {
+			mErr.Errors = append(mErr.Errors, err)
+		}
 	case "=", "==", "is", "!=", "not", "<", "<=", ">", ">=":
 		if c.RTarget == "" {
 			mErr.Errors = append(mErr.Errors, fmt.Errorf("Operator %q requires an RTarget", c.Operand))
@@ -8798,6 +9221,10 @@ func (c *Constraint) Validate() error {
 		mErr.Errors = append(mErr.Errors, fmt.Errorf("No LTarget provided but is required by constraint"))
 	}
 
+	if err := validateAllocsTarget(c.LTarget, c.RTarget, c.Operand); err != nil {
+		mErr.Errors = append(mErr.Errors, err)
+	}
+
 	return mErr.ErrorOrNil()
 }
 
@@ -8871,7 +9298,7 @@ func (a *Affinity) Validate() error {
 
 	// Perform additional validation based on operand
 	switch a.Operand {
-	case ConstraintSetContainsAll, ConstraintSetContainsAny, ConstraintSetContains:
+	case ConstraintSetContainsAll, ConstraintSetContainsAny, ConstraintSetContains, ConstraintSetContainsNone:
 		if a.RTarget == "" {
 			mErr.Errors = append(mErr.Errors, fmt.Errorf("Set contains operators require an RTarget"))
 		}
@@ -8887,6 +9314,14 @@ func (a *Affinity) Validate() error {
 		if _, err := semver.NewConstraint(a.RTarget); err != nil {
 			mErr.Errors = append(mErr.Errors, fmt.Errorf("Semver affinity is invalid: %v", err))
 		}
+	case ConstraintCIDRContains:
+		if err := validateCIDRTarget(a.RTarget); err != nil {
+			mErr.Errors = append(mErr.Errors, err)
+		}
+	case ConstraintNumericLess, ConstraintNumericLessEqual, ConstraintNumericGreater, ConstraintNumericGreaterEqual:
+		if err := validateNumericTarget(a.Operand, a.RTarget); err != nil {
+			mErr.Errors = append(mErr.Errors, err)
+		}
 	case "=", "==", "is", "!=", "not", "<", "<=", ">", ">=":
 		if a.RTarget == "" {
 			mErr.Errors = append(mErr.Errors, fmt.Errorf("Operator %q requires an RTarget", a.Operand))
@@ -8900,6 +9335,10 @@ func (a *Affinity) Validate() error {
 		mErr.Errors = append(mErr.Errors, fmt.Errorf("No LTarget provided but is required"))
 	}
 
+	if err := validateAllocsTarget(a.LTarget, a.RTarget, a.Operand); err != nil {
+		mErr.Errors = append(mErr.Errors, err)
+	}
+
 	// Ensure that weight is between -100 and 100, and not zero
 	if a.Weight == 0 {
 		mErr.Errors = append(mErr.Errors, fmt.Errorf("Affinity weight cannot be zero"))
@@ -8924,6 +9363,12 @@ type Spread struct {
 	// SpreadTarget is used to describe desired percentages for each attribute value
 	SpreadTarget []*SpreadTarget
 
+	// MaxSkew, when non-zero, makes the spread a hard constraint. A placement
+	// is infeasible if it would leave the most used value of the attribute
+	// with more than MaxSkew allocations of the task group over the least
+	// used value.
+	MaxSkew int
+
 	// Memoized string representation
 	str string
 }
@@ -8969,6 +9414,9 @@ func (s *Spread) String() string {
 		return s.str
 	}
 	s.str = fmt.Sprintf("%s %s %v", s.Attribute, s.SpreadTarget, s.Weight)
+	if s.MaxSkew > 0 {
+		s.str += fmt.Sprintf(" max_skew=%d", s.MaxSkew)
+	}
 	return s.str
 }
 
@@ -8980,6 +9428,12 @@ func (s *Spread) Validate() error {
 	if s.Weight <= 0 || s.Weight > 100 {
 		mErr.Errors = append(mErr.Errors, errors.New("Spread stanza must have a positive weight from 0 to 100"))
 	}
+	if s.MaxSkew < 0 {
+		mErr.Errors = append(mErr.Errors, errors.New("Spread max_skew must not be negative"))
+	}
+	if s.MaxSkew > 0 && len(s.SpreadTarget) > 0 {
+		mErr.Errors = append(mErr.Errors, errors.New("Spread max_skew cannot be combined with spread targets"))
+	}
 	seen := make(map[string]struct{})
 	sumPercent := uint32(0)
 
@@ -10555,6 +11009,16 @@ type AllocMetric struct {
 	// ScoreMetaData is a slice of top scoring nodes displayed in the CLI
 	ScoreMetaData []*NodeScoreMeta
 
+	// ScoreExplanation is a slice of the nodes scored for the placement,
+	// sorted by normalized score. It is only populated when the evaluation
+	// requested the scores to be explained.
+	ScoreExplanation []*NodeScoreMeta
+
+	// explainScores enables retaining the scores of the nodes scored, up to
+	// explainLimit nodes unless it's zero
+	explainScores bool
+	explainLimit  int
+
 	// nodeScoreMeta is used to keep scores for a single node id. It is cleared out after
 	// we receive normalized score during the last step of the scoring stack.
 	nodeScoreMeta *NodeScoreMeta
@@ -10588,6 +11052,7 @@ func (a *AllocMetric) Copy() *AllocMetric {
 	na.QuotaExhausted = helper.CopySliceString(na.QuotaExhausted)
 	na.Scores = helper.CopyMapStringFloat64(na.Scores)
 	na.ScoreMetaData = CopySliceNodeScoreMeta(na.ScoreMetaData)
+	na.ScoreExplanation = CopySliceNodeScoreMeta(na.ScoreExplanation)
 	return na
 }
 
@@ -10684,6 +11149,10 @@ func (a *AllocMetric) ScoreNode(node *Node, name string, score float64) {
 		}
 		heap.Push(a.topScores, a.nodeScoreMeta)
 
+		if a.explainScores {
+			a.ScoreExplanation = append(a.ScoreExplanation, a.nodeScoreMeta)
+		}
+
 		// Clear out this entry because its now in the heap
 		a.nodeScoreMeta = nil
 	} else {
@@ -10706,6 +11175,21 @@ func (a *AllocMetric) PopulateScoreMetaData() {
 	for i, item := range heapItems {
 		a.ScoreMetaData[i] = item.(*NodeScoreMeta)
 	}
+
+	sort.SliceStable(a.ScoreExplanation, func(i, j int) bool {
+		return a.ScoreExplanation[i].NormScore > a.ScoreExplanation[j].NormScore
+	})
+	if a.explainLimit > 0 && len(a.ScoreExplanation) > a.explainLimit {
+		a.ScoreExplanation = a.ScoreExplanation[:a.explainLimit]
+	}
+}
+
+// ExplainScores enables retaining the scores of the nodes scored in
+// ScoreExplanation, rather than only those of the top K nodes. Only the limit
+// top scoring nodes are retained, unless the limit is zero.
+func (a *AllocMetric) ExplainScores(limit int) {
+	a.explainScores = true
+	a.explainLimit = limit
 }
 
 // MaxNormScore returns the ScoreMetaData entry with the highest normalized
@@ -10868,6 +11352,7 @@ const (
 	EvalTriggerScaling              = "job-scaling"
 	EvalTriggerMaxDisconnectTimeout = "max-disconnect-timeout"
 	EvalTriggerReconnect            = "reconnect"
+	EvalTriggerRebalance            = "rebalance"
 )
 
 const (
@@ -11024,6 +11509,10 @@ type Evaluation struct {
 	// evaluation.
 	QuotaLimitReached string
 
+	// BlockedOnJob is the ID of the job, in the namespace of the evaluation,
+	// whose dependency condition wasn't met when the evaluation was blocked.
+	BlockedOnJob string
+
 	// EscapedComputedClass marks whether the job has constraints that are not
 	// captured by computed node classes.
 	EscapedComputedClass bool
@@ -11032,6 +11521,13 @@ type Evaluation struct {
 	// during the evaluation. This should not be set during normal operations.
 	AnnotatePlan bool
 
+	// ExplainScores triggers the scheduler to record the scores of the nodes
+	// scored for each placement in the allocation metrics, rather than only
+	// those of the top scoring nodes. Along with AnnotatePlan every feasible
+	// node is scored, otherwise the explanations are bounded by
+	// MaxPersistedScoreExplanations.
+	ExplainScores bool
+
 	// QueuedAllocations is the number of unplaced allocations at the time the
 	// evaluation was processed. The map is keyed by Task Group names.
 	QueuedAllocations map[string]int
@@ -11647,6 +12143,11 @@ type PlanAnnotations struct {
 
 	// PreemptedAllocs is the set of allocations to be preempted to make the placement successful.
 	PreemptedAllocs []*AllocListStub
+
+	// PreemptionPriority is the priority with which the job preempts
+	// allocations, after applying the preemption priority ceiling of its
+	// namespace.
+	PreemptionPriority int
 }
 
 // DesiredUpdates is the set of changes the scheduler would like to make given
@@ -18,6 +18,26 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+func TestNamespace_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	ns := &Namespace{Name: "tenant", PreemptionPriorityCeiling: 60}
+	require.NoError(t, ns.Validate())
+
+	ns.PreemptionPriorityCeiling = -1
+	require.ErrorContains(t, ns.Validate(), "preemption priority ceiling must be between [0, 100]")
+
+	ns.PreemptionPriorityCeiling = JobMaxPriority + 1
+	require.ErrorContains(t, ns.Validate(), "preemption priority ceiling must be between [0, 100]")
+
+	ns.PreemptionPriorityCeiling = 0
+	ns.SchedulerAlgorithm = SchedulerAlgorithmLeastRequestedHeadroom
+	require.NoError(t, ns.Validate())
+
+	ns.SchedulerAlgorithm = "tetris"
+	require.ErrorContains(t, ns.Validate(), "invalid scheduler algorithm")
+}
+
 func TestJob_Validate(t *testing.T) {
 	ci.Parallel(t)
 
@@ -97,6 +117,41 @@ func TestJob_Validate(t *testing.T) {
 	}
 	err = j.Validate()
 	require.Error(t, err, "datacenter must be non-empty string")
+
+	j = &Job{
+		Type:               JobTypeBatch,
+		SchedulerAlgorithm: "tetris",
+	}
+	err = j.Validate()
+	require.ErrorContains(t, err, `Invalid scheduler algorithm: "tetris"`)
+}
+
+func TestJob_Validate_DependsOn(t *testing.T) {
+	ci.Parallel(t)
+
+	j := testJob()
+	j.DependsOn = []*JobDependency{
+		{Job: "db", Group: "cache", Condition: JobDependencyConditionHealthy},
+		{Job: "migrations", Condition: JobDependencyConditionComplete},
+	}
+	require.NoError(t, j.Validate())
+
+	j.DependsOn = []*JobDependency{
+		{Job: j.ID, Condition: JobDependencyConditionRunning},
+		{Condition: "started"},
+	}
+	requireErrors(t, j.Validate(),
+		"Job can not depend on itself",
+		"Missing job ID",
+		`Invalid condition "started"`,
+	)
+
+	j = testJob()
+	j.Type = JobTypeSystem
+	j.DependsOn = []*JobDependency{
+		{Job: "db", Condition: JobDependencyConditionHealthy},
+	}
+	require.ErrorContains(t, j.Validate(), "Dependencies can only be used")
 }
 
 func TestJob_ValidateScaling(t *testing.T) {
@@ -2451,6 +2506,93 @@ func TestTask_Validate_LogConfig(t *testing.T) {
 	require.Error(t, err, "log storage")
 }
 
+func TestLogConfig_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	cases := []struct {
+		name string
+		mod  func(*LogConfig)
+		err  string
+	}{
+		{
+			name: "default",
+			mod:  func(*LogConfig) {},
+		},
+		{
+			name: "rotation and cap",
+			mod: func(l *LogConfig) {
+				l.RotationInterval = time.Hour
+				l.Compress = true
+				l.MaxTotalSizeMB = 20
+			},
+		},
+		{
+			name: "short rotation interval",
+			mod:  func(l *LogConfig) { l.RotationInterval = time.Second },
+			err:  "minimum rotation interval is 1m",
+		},
+		{
+			name: "negative rotation interval",
+			mod:  func(l *LogConfig) { l.RotationInterval = -time.Hour },
+			err:  "rotation interval must be greater than or equal to 0",
+		},
+		{
+			name: "cap below current files",
+			mod:  func(l *LogConfig) { l.MaxTotalSizeMB = 15 },
+			err:  "max total size must be at least twice the max file size (20MB)",
+		},
+		{
+			name: "large files",
+			mod:  func(l *LogConfig) { l.MaxFileSizeMB = 4096 },
+		},
+		{
+			name: "large compressed files",
+			mod: func(l *LogConfig) {
+				l.MaxFileSizeMB = 4096
+				l.Compress = true
+			},
+			err: "maximum file size with compression is 4095MB",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			l := DefaultLogConfig()
+			tc.mod(l)
+			err := l.Validate()
+			if tc.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.err)
+			}
+		})
+	}
+}
+
+func TestTask_Validate_LogConfig_MaxTotalSize(t *testing.T) {
+	ci.Parallel(t)
+
+	task := &Task{
+		LogConfig: &LogConfig{
+			MaxFiles:       100,
+			MaxFileSizeMB:  10,
+			MaxTotalSizeMB: 200,
+		},
+	}
+	ephemeralDisk := &EphemeralDisk{
+		SizeMB: 300,
+	}
+
+	// The total size cap bounds the log storage rather than the file count
+	err := task.Validate(ephemeralDisk, JobTypeService, nil, nil)
+	require.NotContains(t, err.Error(), "log storage")
+
+	task.LogConfig.MaxTotalSizeMB = 300
+	err = task.Validate(ephemeralDisk, JobTypeService, nil, nil)
+	require.Contains(t, err.Error(), "log storage (300 MB)")
+}
+
 func TestLogConfig_Equals(t *testing.T) {
 	ci.Parallel(t)
 
@@ -2478,6 +2620,13 @@ func TestLogConfig_Equals(t *testing.T) {
 		require.False(t, a.Equals(b))
 	})
 
+	t.Run("sinks", func(t *testing.T) {
+		a := &LogConfig{MaxFiles: 1, MaxFileSizeMB: 200, Sinks: []*LogSink{{Type: LogSinkTypeTCP, Address: "127.0.0.1:5000"}}}
+		b := &LogConfig{MaxFiles: 1, MaxFileSizeMB: 200, Sinks: []*LogSink{{Type: LogSinkTypeUDP, Address: "127.0.0.1:5000"}}}
+		require.False(t, a.Equals(b))
+		require.True(t, a.Equals(a.Copy()))
+	})
+
 	t.Run("same", func(t *testing.T) {
 		a := &LogConfig{MaxFiles: 1, MaxFileSizeMB: 200}
 		b := &LogConfig{MaxFiles: 1, MaxFileSizeMB: 200}
@@ -2485,6 +2634,67 @@ func TestLogConfig_Equals(t *testing.T) {
 	})
 }
 
+func TestLogSink_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	cases := []struct {
+		name string
+		sink *LogSink
+		err  string
+	}{
+		{
+			name: "syslog",
+			sink: &LogSink{Type: LogSinkTypeSyslog, Address: "127.0.0.1:514", Protocol: "tcp", Facility: "local3"},
+		},
+		{
+			name: "http",
+			sink: &LogSink{Type: LogSinkTypeHTTP, Address: "https://logs.example.com/ingest", BatchSize: 50, BatchInterval: time.Second},
+		},
+		{
+			name: "unknown type",
+			sink: &LogSink{Type: "kafka", Address: "127.0.0.1:9092"},
+			err:  `unknown sink type "kafka"`,
+		},
+		{
+			name: "bad address",
+			sink: &LogSink{Type: LogSinkTypeTCP, Address: "127.0.0.1"},
+			err:  "address must be host:port",
+		},
+		{
+			name: "bad url",
+			sink: &LogSink{Type: LogSinkTypeHTTP, Address: "localhost:8080"},
+			err:  "address must be an http or https URL",
+		},
+		{
+			name: "bad facility",
+			sink: &LogSink{Type: LogSinkTypeSyslog, Address: "127.0.0.1:514", Facility: "local9"},
+			err:  `unknown syslog facility "local9"`,
+		},
+		{
+			name: "facility on tcp sink",
+			sink: &LogSink{Type: LogSinkTypeTCP, Address: "127.0.0.1:5000", Facility: "local0"},
+			err:  "only valid for syslog sinks",
+		},
+		{
+			name: "negative buffer",
+			sink: &LogSink{Type: LogSinkTypeUDP, Address: "127.0.0.1:5000", BufferSize: -1},
+			err:  "buffer size must be greater than or equal to 0",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.sink.Validate()
+			if tc.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.err)
+			}
+		})
+	}
+}
+
 func TestTask_Validate_CSIPluginConfig(t *testing.T) {
 	ci.Parallel(t)
 
@@ -2919,7 +3129,7 @@ func TestConstraint_Validate(t *testing.T) {
 
 	// Perform set_contains* validation
 	c.RTarget = ""
-	for _, o := range []string{ConstraintSetContains, ConstraintSetContainsAll, ConstraintSetContainsAny} {
+	for _, o := range []string{ConstraintSetContains, ConstraintSetContainsAll, ConstraintSetContainsAny, ConstraintSetContainsNone} {
 		c.Operand = o
 		err = c.Validate()
 		require.Error(t, err, "requires an RTarget")
@@ -2936,6 +3146,62 @@ func TestConstraint_Validate(t *testing.T) {
 	c.Operand = "foo"
 	err = c.Validate()
 	require.Error(t, err, "Unknown constraint type")
+
+	// Perform allocation target validation
+	c = &Constraint{
+		LTarget: ConstraintAllocsJobID,
+		RTarget: "db,cache",
+		Operand: ConstraintSetContainsNone,
+	}
+	require.NoError(t, c.Validate())
+
+	c.LTarget = "${allocs.meta.tier}"
+	require.NoError(t, c.Validate())
+
+	c.Operand = "="
+	require.ErrorContains(t, c.Validate(), "requires a set operator")
+
+	c.Operand = ConstraintSetContainsAny
+	c.LTarget = "${allocs.node_id}"
+	require.ErrorContains(t, c.Validate(), "Unknown allocation target")
+
+	c.LTarget = "${attr.kernel.name}"
+	c.RTarget = ConstraintAllocsJobID
+	require.ErrorContains(t, c.Validate(), "can only be used as the LTarget")
+
+	// Perform cidr_contains validation
+	c = &Constraint{
+		LTarget: "${attr.unique.network.ip-address}",
+		RTarget: "10.0.0.0/16, 192.168.0.0/24",
+		Operand: ConstraintCIDRContains,
+	}
+	require.NoError(t, c.Validate())
+
+	c.RTarget = "10.0.0.0/16,10.1.0.0"
+	require.ErrorContains(t, c.Validate(), "CIDR block \"10.1.0.0\" is invalid")
+
+	c.RTarget = "${meta.subnet}"
+	require.NoError(t, c.Validate())
+
+	c.RTarget = ""
+	require.ErrorContains(t, c.Validate(), "requires an RTarget")
+
+	// Perform numeric operator validation
+	c.LTarget = "${attr.memory.totalbytes}"
+	for _, o := range []string{ConstraintNumericLess, ConstraintNumericLessEqual, ConstraintNumericGreater, ConstraintNumericGreaterEqual} {
+		c.Operand = o
+		c.RTarget = "17179869184"
+		require.NoError(t, c.Validate())
+
+		c.RTarget = "1.5e3"
+		require.NoError(t, c.Validate())
+
+		c.RTarget = "16GB"
+		require.ErrorContains(t, c.Validate(), "requires a numeric RTarget")
+
+		c.RTarget = ""
+		require.ErrorContains(t, c.Validate(), "requires an RTarget")
+	}
 }
 
 func TestAffinity_Validate(t *testing.T) {
@@ -3011,6 +3277,49 @@ func TestAffinity_Validate(t *testing.T) {
 			},
 			err: fmt.Errorf("Regular expression failed to compile"),
 		},
+		{
+			affinity: &Affinity{
+				Operand: ConstraintSetContainsAny,
+				LTarget: "${allocs.job_id}",
+				RTarget: "db",
+				Weight:  50,
+			},
+		},
+		{
+			affinity: &Affinity{
+				Operand: "regexp",
+				LTarget: "${allocs.job_id}",
+				RTarget: "db-.*",
+				Weight:  50,
+			},
+			err: fmt.Errorf("Allocation target \"${allocs.job_id}\" requires a set operator"),
+		},
+		{
+			affinity: &Affinity{
+				Operand: ConstraintCIDRContains,
+				LTarget: "${attr.unique.network.ip-address}",
+				RTarget: "10.0.0.0/33",
+				Weight:  50,
+			},
+			err: fmt.Errorf("CIDR block \"10.0.0.0/33\" is invalid"),
+		},
+		{
+			affinity: &Affinity{
+				Operand: ConstraintNumericGreaterEqual,
+				LTarget: "${attr.cpu.numcores}",
+				RTarget: "many",
+				Weight:  50,
+			},
+			err: fmt.Errorf("Operator \"num_gte\" requires a numeric RTarget"),
+		},
+		{
+			affinity: &Affinity{
+				Operand: ConstraintNumericGreaterEqual,
+				LTarget: "${attr.cpu.numcores}",
+				RTarget: "8",
+				Weight:  50,
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -5864,6 +6173,23 @@ func TestJobConfig_Validate_MaxClientDisconnect(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestJobConfig_Validate_Gang(t *testing.T) {
+	ci.Parallel(t)
+
+	// Gang scheduling isn't supported for service jobs
+	job := testJob()
+	job.Type = JobTypeService
+	job.TaskGroups[0].Gang = true
+
+	err := job.Validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "gang scheduling is only supported")
+
+	job.Type = JobTypeBatch
+	err = job.Validate()
+	require.NoError(t, err)
+}
+
 func TestParameterizedJobConfig_Canonicalize(t *testing.T) {
 	ci.Parallel(t)
 
@@ -6659,6 +6985,39 @@ func TestSpread_Validate(t *testing.T) {
 			err:  nil,
 			name: "Valid spread",
 		},
+		{
+			spread: &Spread{
+				Attribute: "${node.datacenter}",
+				Weight:    50,
+				MaxSkew:   -1,
+			},
+			err:  fmt.Errorf("Spread max_skew must not be negative"),
+			name: "Invalid max skew",
+		},
+		{
+			spread: &Spread{
+				Attribute: "${node.datacenter}",
+				Weight:    50,
+				MaxSkew:   1,
+				SpreadTarget: []*SpreadTarget{
+					{
+						Value:   "dc1",
+						Percent: 50,
+					},
+				},
+			},
+			err:  fmt.Errorf("Spread max_skew cannot be combined with spread targets"),
+			name: "Max skew with spread targets",
+		},
+		{
+			spread: &Spread{
+				Attribute: "${node.datacenter}",
+				Weight:    50,
+				MaxSkew:   1,
+			},
+			err:  nil,
+			name: "Valid max skew",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -7157,3 +7516,32 @@ func requireErrors(t *testing.T, err error, expected ...string) {
 
 	require.Equal(t, expected, found)
 }
+
+func TestAllocMetric_ExplainScores(t *testing.T) {
+	ci.Parallel(t)
+
+	score := func(limit int) *AllocMetric {
+		metrics := new(AllocMetric)
+		metrics.ExplainScores(limit)
+		for i := 0; i < 10; i++ {
+			node := &Node{ID: fmt.Sprintf("node-%d", i)}
+			metrics.ScoreNode(node, "binpack", float64(i))
+			metrics.ScoreNode(node, NormScorerName, float64(i)/10)
+		}
+		metrics.PopulateScoreMetaData()
+		return metrics
+	}
+
+	// Every node scored is explained without a limit, sorted by score
+	metrics := score(0)
+	require.Len(t, metrics.ScoreExplanation, 10)
+	require.Equal(t, "node-9", metrics.ScoreExplanation[0].NodeID)
+	require.Equal(t, 9.0, metrics.ScoreExplanation[0].Scores["binpack"])
+	require.Len(t, metrics.ScoreMetaData, MaxRetainedNodeScores)
+
+	// Only the top scoring nodes are explained with a limit
+	metrics = score(3)
+	require.Len(t, metrics.ScoreExplanation, 3)
+	require.Equal(t, "node-9", metrics.ScoreExplanation[0].NodeID)
+	require.Equal(t, "node-7", metrics.ScoreExplanation[2].NodeID)
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"reflect"
 	"strings"
+	"time"
 )
 
 const (
@@ -30,6 +31,21 @@ const (
 	// Reply: VariablesByNameResponse
 	VariablesReadRPCMethod = "Variables.Read"
 
+	// VariablesListVersionsRPCMethod is the RPC method for listing the
+	// retained prior versions of a variable according to its namespace and
+	// path.
+	//
+	// Args: VariablesListVersionsRequest
+	// Reply: VariablesListVersionsResponse
+	VariablesListVersionsRPCMethod = "Variables.ListVersions"
+
+	// VariablesRenewLockRPCMethod is the RPC method for renewing the lease
+	// of a lock held on a variable.
+	//
+	// Args: VariablesRenewLockRequest
+	// Reply: VariablesRenewLockResponse
+	VariablesRenewLockRPCMethod = "Variables.RenewLock"
+
 	// maxVariableSize is the maximum size of the unencrypted contents of a
 	// variable. This size is deliberately set low and is not configurable, to
 	// discourage DoS'ing the cluster
@@ -46,6 +62,61 @@ type VariableMetadata struct {
 	CreateTime  int64
 	ModifyIndex uint64
 	ModifyTime  int64
+
+	// Lock is the lock currently held on the variable, if any.
+	Lock *VariableLock
+}
+
+const (
+	// VariableLockMinTTL and VariableLockMaxTTL bound the lease TTL a lock
+	// holder may request.
+	VariableLockMinTTL = 10 * time.Second
+	VariableLockMaxTTL = 24 * time.Hour
+
+	// VariableLockDefaultTTL is the lease TTL used when none is requested.
+	VariableLockDefaultTTL = 15 * time.Second
+)
+
+// VariableLock is a lock held on a variable. The lock is a lease which
+// expires after TTL unless it is renewed by its holder, and is released
+// automatically when the holding allocation stops. Only the holder may write
+// a locked variable, and it can't be deleted or rolled back.
+type VariableLock struct {
+	// ID is generated by the servers when the lock is acquired. It is only
+	// returned to the holder and is required to renew or release the lock,
+	// and to write the variable.
+	ID string
+
+	// TTL is the duration of the lease.
+	TTL time.Duration
+
+	// AllocID is the optional ID of the allocation holding the lock.
+	AllocID string
+}
+
+func (l *VariableLock) Copy() *VariableLock {
+	if l == nil {
+		return nil
+	}
+	nl := new(VariableLock)
+	*nl = *l
+	return nl
+}
+
+func (l *VariableLock) Equals(l2 *VariableLock) bool {
+	if l == nil || l2 == nil {
+		return l == l2
+	}
+	return *l == *l2
+}
+
+// Validate checks the lock requested by a client. The ID is not validated as
+// it is set by the servers.
+func (l *VariableLock) Validate() error {
+	if l.TTL < VariableLockMinTTL || l.TTL > VariableLockMaxTTL {
+		return fmt.Errorf("lock TTL must be between %v and %v", VariableLockMinTTL, VariableLockMaxTTL)
+	}
+	return nil
 }
 
 // VariableEncrypted structs are returned from the Encrypter's encrypt
@@ -91,7 +162,9 @@ func (v1 VariableDecrypted) Equals(v2 VariableDecrypted) bool {
 // Equals is a convenience method to provide similar equality checking syntax
 // for metadata and the VariablesData or VariableItems struct
 func (sv VariableMetadata) Equals(sv2 VariableMetadata) bool {
-	return sv == sv2
+	l1, l2 := sv.Lock, sv2.Lock
+	sv.Lock, sv2.Lock = nil, nil
+	return sv == sv2 && l1.Equals(l2)
 }
 
 // Equals performs deep equality checking on the cleartext items of a
@@ -116,7 +189,7 @@ func (d1 VariableData) Equals(d2 VariableData) bool {
 
 func (sv VariableDecrypted) Copy() VariableDecrypted {
 	return VariableDecrypted{
-		VariableMetadata: sv.VariableMetadata,
+		VariableMetadata: *sv.VariableMetadata.Copy(),
 		Items:            sv.Items.Copy(),
 	}
 }
@@ -131,7 +204,7 @@ func (sv VariableItems) Copy() VariableItems {
 
 func (sv VariableEncrypted) Copy() VariableEncrypted {
 	return VariableEncrypted{
-		VariableMetadata: sv.VariableMetadata,
+		VariableMetadata: *sv.VariableMetadata.Copy(),
 		VariableData:     sv.VariableData.Copy(),
 	}
 }
@@ -176,12 +249,24 @@ func (sv *VariableDecrypted) Canonicalize() {
 	}
 }
 
-// GetNamespace returns the variable's namespace. Used for pagination.
+// Copy returns a deep copy of the variable's metadata.
 func (sv *VariableMetadata) Copy() *VariableMetadata {
 	var out VariableMetadata = *sv
+	out.Lock = sv.Lock.Copy()
 	return &out
 }
 
+// RedactLockID returns a copy of the metadata without the ID of the lock
+// held on the variable, so that it can be returned to callers other than
+// the lock holder.
+func (sv *VariableMetadata) RedactLockID() *VariableMetadata {
+	out := sv.Copy()
+	if out.Lock != nil {
+		out.Lock.ID = ""
+	}
+	return out
+}
+
 // GetNamespace returns the variable's namespace. Used for pagination.
 func (sv VariableMetadata) GetNamespace() string {
 	return sv.Namespace
@@ -229,6 +314,14 @@ const (
 	VarOpDelete    VarOp = "delete"
 	VarOpDeleteCAS VarOp = "delete-cas"
 	VarOpCAS       VarOp = "cas"
+	VarOpRollback  VarOp = "rollback"
+
+	VarOpLockAcquire VarOp = "lock-acquire"
+	VarOpLockRelease VarOp = "lock-release"
+
+	// VarOpRekey re-encrypts a variable, or a retained prior version of a
+	// variable, with the active root key. It is only used by the servers.
+	VarOpRekey VarOp = "rekey"
 )
 
 // VarOpResult constants give possible operations results from a transaction.
@@ -243,8 +336,9 @@ const (
 
 // VariablesApplyRequest is used by users to operate on the variable store
 type VariablesApplyRequest struct {
-	Op  VarOp              // Operation to be performed during apply
-	Var *VariableDecrypted // Variable-shaped request data
+	Op      VarOp              // Operation to be performed during apply
+	Var     *VariableDecrypted // Variable-shaped request data
+	Version uint64             // Version to restore for rollback operations
 	WriteRequest
 }
 
@@ -277,8 +371,15 @@ func (r *VariablesApplyResponse) IsRedacted() bool {
 
 // VarApplyStateRequest is used by the FSM to modify the variable store
 type VarApplyStateRequest struct {
-	Op  VarOp              // Which operation are we performing
-	Var *VariableEncrypted // Which directory entry
+	Op      VarOp              // Which operation are we performing
+	Var     *VariableEncrypted // Which directory entry
+	Version uint64             // Version to restore for rollback operations
+
+	// VersionLimit is the number of prior versions of the variable to
+	// retain. It is set by the leader, so all servers retain the same
+	// versions.
+	VersionLimit int
+
 	WriteRequest
 }
 
@@ -350,6 +451,11 @@ type VariablesListResponse struct {
 
 type VariablesReadRequest struct {
 	Path string
+
+	// Version is the modify index of a retained prior version of the
+	// variable to read. The current version is read if it is zero.
+	Version uint64
+
 	QueryOptions
 }
 
@@ -357,3 +463,24 @@ type VariablesReadResponse struct {
 	Data *VariableDecrypted
 	QueryMeta
 }
+
+type VariablesListVersionsRequest struct {
+	Path string
+	QueryOptions
+}
+
+type VariablesListVersionsResponse struct {
+	Data []*VariableMetadata
+	QueryMeta
+}
+
+type VariablesRenewLockRequest struct {
+	Path   string
+	LockID string
+	WriteRequest
+}
+
+type VariablesRenewLockResponse struct {
+	VarMeta *VariableMetadata
+	WriteMeta
+}
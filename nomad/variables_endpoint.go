@@ -13,6 +13,7 @@ import (
 
 	"github.com/hashicorp/nomad/acl"
 	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/state"
 	"github.com/hashicorp/nomad/nomad/state/paginator"
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -70,12 +71,52 @@ func (sv *Variables) Apply(args *structs.VariablesApplyRequest, reply *structs.V
 				ModifyIndex: args.Var.ModifyIndex,
 			},
 		}
+	case structs.VarOpRollback:
+		if err := sv.rollbackPreApply(args); err != nil {
+			return err
+		}
+		ev = &structs.VariableEncrypted{
+			VariableMetadata: structs.VariableMetadata{
+				Namespace:  args.Var.Namespace,
+				Path:       args.Var.Path,
+				ModifyTime: time.Now().UnixNano(),
+			},
+		}
+	case structs.VarOpLockAcquire:
+		ev, err = sv.lockAcquireVariable(args.Var)
+		if err != nil {
+			return err
+		}
+	case structs.VarOpLockRelease:
+		ev = &structs.VariableEncrypted{
+			VariableMetadata: structs.VariableMetadata{
+				Namespace:  args.Var.Namespace,
+				Path:       args.Var.Path,
+				ModifyTime: time.Now().UnixNano(),
+				Lock:       &structs.VariableLock{ID: args.Var.Lock.ID},
+			},
+		}
+	case structs.VarOpRekey:
+		ev, err = sv.rekeyVariable(args)
+		if err != nil {
+			return err
+		}
+		if ev == nil {
+			// The variable was deleted, or the version pruned, since the
+			// rekey was scheduled
+			reply.Op = args.Op
+			reply.Input = args.Var
+			reply.Result = structs.VarOpResultOk
+			return nil
+		}
 	}
 
 	// Make a SVEArgs
 	sveArgs := structs.VarApplyStateRequest{
 		Op:           args.Op,
 		Var:          ev,
+		Version:      args.Version,
+		VersionLimit: sv.srv.config.VariablesVersionLimit,
 		WriteRequest: args.WriteRequest,
 	}
 
@@ -88,11 +129,152 @@ func (sv *Variables) Apply(args *structs.VariablesApplyRequest, reply *structs.V
 	if err != nil {
 		return err
 	}
+
+	// The items of a rolled back variable, or of a variable locked or
+	// released without items, are only known once it has been written.
+	if r.IsOk() && (args.Op == structs.VarOpRollback ||
+		args.Op == structs.VarOpLockRelease ||
+		(args.Op == structs.VarOpLockAcquire && len(args.Var.Items) == 0)) {
+		if r.Output, err = sv.writtenOutput(r.Output, canRead); err != nil {
+			return err
+		}
+	}
+
+	// The leader tracks the lease of held locks
+	if r.IsOk() {
+		switch args.Op {
+		case structs.VarOpLockAcquire:
+			if err := sv.srv.resetLockTTLTimer(ev.Namespace, ev.Path, ev.Lock); err != nil {
+				return err
+			}
+		case structs.VarOpLockRelease:
+			sv.srv.clearLockTTLTimer(ev.Lock.ID)
+		}
+	}
+
 	*reply = *r
 	reply.Index = index
 	return nil
 }
 
+// rollbackPreApply ensures the version of the variable to roll back to
+// exists, so that a missing version is reported to the caller without
+// applying anything to raft.
+func (sv *Variables) rollbackPreApply(args *structs.VariablesApplyRequest) error {
+	snap, err := sv.srv.fsm.State().Snapshot()
+	if err != nil {
+		return err
+	}
+
+	current, err := snap.GetVariable(nil, args.Var.Namespace, args.Var.Path)
+	if err != nil {
+		return err
+	}
+	if current == nil {
+		return structs.NewErrRPCCoded(http.StatusNotFound, "variable not found")
+	}
+	if current.ModifyIndex == args.Version {
+		return nil
+	}
+
+	version, err := snap.GetVariableVersion(nil, args.Var.Namespace, args.Var.Path, args.Version)
+	if err != nil {
+		return err
+	}
+	if version == nil {
+		return structs.NewErrRPCCodedf(http.StatusNotFound, "variable version %d not found", args.Version)
+	}
+	return nil
+}
+
+// rekeyVariable returns the variable, or the retained prior version of the
+// variable, with the requested modify index encrypted with the active root
+// key. It returns nil if there is no such variable or version.
+func (sv *Variables) rekeyVariable(args *structs.VariablesApplyRequest) (*structs.VariableEncrypted, error) {
+	snap, err := sv.srv.fsm.State().Snapshot()
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := snap.GetVariable(nil, args.Var.Namespace, args.Var.Path)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil || existing.ModifyIndex != args.Version {
+		existing, err = snap.GetVariableVersion(nil, args.Var.Namespace, args.Var.Path, args.Version)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if existing == nil {
+		return nil, nil
+	}
+
+	dv, err := sv.decrypt(existing)
+	if err != nil {
+		return nil, fmt.Errorf("variable error: decrypt: %w", err)
+	}
+	ev, err := sv.encrypt(dv)
+	if err != nil {
+		return nil, fmt.Errorf("variable error: encrypt: %w", err)
+	}
+	return ev, nil
+}
+
+// lockAcquireVariable returns the encrypted variable used to acquire a lock.
+// The items of the variable are only written if any are given.
+func (sv *Variables) lockAcquireVariable(vd *structs.VariableDecrypted) (*structs.VariableEncrypted, error) {
+	ev := &structs.VariableEncrypted{
+		VariableMetadata: vd.VariableMetadata,
+	}
+	if len(vd.Items) > 0 {
+		var err error
+		ev, err = sv.encrypt(vd)
+		if err != nil {
+			return nil, fmt.Errorf("variable error: encrypt: %w", err)
+		}
+	}
+
+	now := time.Now().UnixNano()
+	ev.CreateTime = now // existing will override if it exists
+	ev.ModifyTime = now
+	ev.Lock = &structs.VariableLock{
+		ID:      uuid.Generate(),
+		TTL:     vd.Lock.TTL,
+		AllocID: vd.Lock.AllocID,
+	}
+	return ev, nil
+}
+
+// writtenOutput returns the variable written by an operation which does not
+// supply all of its items, including its items if the caller is allowed to
+// read them.
+func (sv *Variables) writtenOutput(out *structs.VariableDecrypted, canRead bool) (*structs.VariableDecrypted, error) {
+	if out == nil || !canRead {
+		return out, nil
+	}
+
+	snap, err := sv.srv.fsm.State().Snapshot()
+	if err != nil {
+		return nil, err
+	}
+	ev, err := snap.GetVariable(nil, out.Namespace, out.Path)
+	if err != nil {
+		return nil, err
+	}
+	if ev == nil {
+		return out, nil
+	}
+	dv, err := sv.decrypt(ev)
+	if err != nil {
+		return nil, err
+	}
+
+	// The metadata of the write has the lock ID redacted as needed
+	dv.VariableMetadata = out.VariableMetadata
+	return dv, nil
+}
+
 func svePreApply(sv *Variables, args *structs.VariablesApplyRequest, vd *structs.VariableDecrypted) (canRead bool, err error) {
 
 	canRead = false
@@ -119,6 +301,18 @@ func svePreApply(sv *Variables, args *structs.VariablesApplyRequest, vd *structs
 				err = structs.ErrPermissionDenied
 				return
 			}
+		case structs.VarOpRollback, structs.VarOpLockAcquire, structs.VarOpLockRelease:
+			if !hasPerm(acl.VariablesCapabilityWrite) {
+				err = structs.ErrPermissionDenied
+				return
+			}
+		case structs.VarOpRekey:
+			// Variables are only rekeyed by the servers, using the leader's
+			// management token
+			if !aclObj.IsManagement() {
+				err = structs.ErrPermissionDenied
+				return
+			}
 		default:
 			err = fmt.Errorf("svPreApply: unexpected VarOp received: %q", args.Op)
 			return
@@ -140,11 +334,121 @@ func svePreApply(sv *Variables, args *structs.VariablesApplyRequest, vd *structs
 			err = fmt.Errorf("delete requires a Path")
 			return
 		}
+
+	case structs.VarOpRollback:
+		if args.Var == nil || args.Var.Path == "" {
+			err = fmt.Errorf("rollback requires a Path")
+			return
+		}
+		if args.Version == 0 {
+			err = fmt.Errorf("rollback requires a Version")
+			return
+		}
+
+	case structs.VarOpLockAcquire:
+		args.Var.Canonicalize()
+		if args.Var.Lock == nil {
+			args.Var.Lock = &structs.VariableLock{}
+		}
+		if args.Var.Lock.TTL == 0 {
+			args.Var.Lock.TTL = structs.VariableLockDefaultTTL
+		}
+		if err = args.Var.Lock.Validate(); err != nil {
+			return
+		}
+		// The items are optional when locking an existing variable
+		if len(args.Var.Items) > 0 {
+			err = args.Var.Validate()
+		} else if args.Var.Path == "" {
+			err = fmt.Errorf("lock acquire requires a Path")
+		}
+		if err != nil {
+			return
+		}
+		if args.Var.Lock.AllocID != "" {
+			err = sv.lockAllocPreApply(args.Var.Lock.AllocID)
+		}
+
+	case structs.VarOpRekey:
+		if args.Var == nil || args.Var.Path == "" {
+			err = fmt.Errorf("rekey requires a Path")
+			return
+		}
+		if args.Version == 0 {
+			err = fmt.Errorf("rekey requires a Version")
+			return
+		}
+
+	case structs.VarOpLockRelease:
+		if args.Var == nil || args.Var.Path == "" {
+			err = fmt.Errorf("lock release requires a Path")
+			return
+		}
+		if args.Var.Lock == nil || args.Var.Lock.ID == "" {
+			err = fmt.Errorf("lock release requires a lock ID")
+			return
+		}
+	}
+
+	switch args.Op {
+	case structs.VarOpSet, structs.VarOpCAS, structs.VarOpDelete,
+		structs.VarOpDeleteCAS, structs.VarOpRollback:
+		err = sv.lockHolderPreApply(args)
 	}
 
 	return
 }
 
+// lockHolderPreApply ensures a locked variable is only modified by the holder
+// of the lock, who passes the ID of the lock along with the variable. Locked
+// variables can't be deleted or rolled back until the lock is released. The
+// state store enforces the same, this only fails early with a clearer error.
+func (sv *Variables) lockHolderPreApply(args *structs.VariablesApplyRequest) error {
+	snap, err := sv.srv.fsm.State().Snapshot()
+	if err != nil {
+		return err
+	}
+	_, existing, err := snap.VarGet(nil, args.Var.Namespace, args.Var.Path)
+	if err != nil {
+		return err
+	}
+	if existing == nil || existing.Lock == nil {
+		return nil
+	}
+
+	switch args.Op {
+	case structs.VarOpSet, structs.VarOpCAS:
+		if args.Var.Lock != nil && args.Var.Lock.ID == existing.Lock.ID {
+			return nil
+		}
+		return structs.NewErrRPCCodedf(http.StatusConflict,
+			"variable %q is locked and can only be written by the lock holder", args.Var.Path)
+	default:
+		return structs.NewErrRPCCodedf(http.StatusConflict,
+			"variable %q is locked and the lock must be released first", args.Var.Path)
+	}
+}
+
+// lockAllocPreApply ensures the allocation which will hold a lock exists and
+// is running, as the locks of stopped allocations are released.
+func (sv *Variables) lockAllocPreApply(allocID string) error {
+	snap, err := sv.srv.fsm.State().Snapshot()
+	if err != nil {
+		return err
+	}
+	alloc, err := snap.AllocByID(nil, allocID)
+	if err != nil {
+		return err
+	}
+	if alloc == nil {
+		return structs.NewErrRPCCodedf(http.StatusNotFound, "allocation %q not found", allocID)
+	}
+	if alloc.ClientTerminalStatus() {
+		return structs.NewErrRPCCodedf(http.StatusBadRequest, "allocation %q is not running", allocID)
+	}
+	return nil
+}
+
 // MakeVariablesApplyResponse merges the output of this VarApplyStateResponse with the
 // VariableDataItems
 func (sv *Variables) makeVariablesApplyResponse(
@@ -159,18 +463,32 @@ func (sv *Variables) makeVariablesApplyResponse(
 		WriteMeta: eResp.WriteMeta,
 	}
 
+	if eResp.IsError() {
+		return nil, eResp.Error
+	}
+
 	if eResp.IsOk() {
 		if eResp.WrittenSVMeta != nil {
+			// Only the lock holder knows the ID of a lock held on the
+			// written variable
+			meta := eResp.WrittenSVMeta
+			if req.Op != structs.VarOpLockAcquire {
+				meta = meta.RedactLockID()
+			}
+
 			// The writer is allowed to read their own write
 			out.Output = &structs.VariableDecrypted{
-				VariableMetadata: *eResp.WrittenSVMeta,
+				VariableMetadata: *meta,
 				Items:            req.Var.Items.Copy(),
 			}
 		}
 		return &out, nil
 	}
 
-	// At this point, the response is necessarily a conflict.
+	// At this point, the response is necessarily a conflict. The ID of a
+	// lock held on the conflicting value is only known by the lock holder.
+	eResp.Conflict.VariableMetadata = *eResp.Conflict.RedactLockID()
+
 	// Prime output from the encrypted responses metadata
 	out.Conflict = &structs.VariableDecrypted{
 		VariableMetadata: eResp.Conflict.VariableMetadata,
@@ -226,6 +544,14 @@ func (sv *Variables) Read(args *structs.VariablesReadRequest, reply *structs.Var
 				return err
 			}
 
+			// A prior version is requested unless it is the current one
+			if args.Version != 0 && (out == nil || out.ModifyIndex != args.Version) {
+				out, err = s.GetVariableVersion(ws, args.RequestNamespace(), args.Path, args.Version)
+				if err != nil {
+					return err
+				}
+			}
+
 			// Setup the output
 			reply.Data = nil
 			if out != nil {
@@ -234,6 +560,7 @@ func (sv *Variables) Read(args *structs.VariablesReadRequest, reply *structs.Var
 					return err
 				}
 				ov := dv.Copy()
+				ov.VariableMetadata = *ov.RedactLockID()
 				reply.Data = &ov
 				reply.Index = out.ModifyIndex
 			} else {
@@ -244,6 +571,55 @@ func (sv *Variables) Read(args *structs.VariablesReadRequest, reply *structs.Var
 	return sv.srv.blockingRPC(&opts)
 }
 
+// ListVersions is used to list the metadata of the current version and the
+// retained prior versions of a specific variable, ordered from newest to
+// oldest.
+func (sv *Variables) ListVersions(
+	args *structs.VariablesListVersionsRequest,
+	reply *structs.VariablesListVersionsResponse) error {
+
+	if done, err := sv.srv.forward(structs.VariablesListVersionsRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "variables", "list_versions"}, time.Now())
+
+	_, err := sv.handleMixedAuthEndpoint(args.QueryOptions,
+		acl.PolicyRead, args.Path)
+	if err != nil {
+		return err
+	}
+
+	return sv.srv.blockingRPC(&blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, s *state.StateStore) error {
+			current, err := s.GetVariable(ws, args.RequestNamespace(), args.Path)
+			if err != nil {
+				return err
+			}
+
+			// Prior versions are deleted along with the variable
+			reply.Data = nil
+			if current != nil {
+				versions, err := s.GetVariableVersions(ws, args.RequestNamespace(), args.Path)
+				if err != nil {
+					return err
+				}
+
+				data := make([]*structs.VariableMetadata, 0, len(versions)+1)
+				data = append(data, current.RedactLockID())
+				for i := len(versions) - 1; i >= 0; i-- {
+					meta := versions[i].VariableMetadata
+					data = append(data, &meta)
+				}
+				reply.Data = data
+			}
+
+			return sv.srv.setReplyQueryMeta(s, state.TableVariables, &reply.QueryMeta)
+		},
+	})
+}
+
 // List is used to list variables held within state. It supports single
 // and wildcard namespace listings.
 func (sv *Variables) List(
@@ -310,8 +686,7 @@ func (sv *Variables) List(
 			paginatorImpl, err := paginator.NewPaginator(iter, tokenizer, filters, args.QueryOptions,
 				func(raw interface{}) error {
 					sv := raw.(*structs.VariableEncrypted)
-					svStub := sv.VariableMetadata
-					svs = append(svs, &svStub)
+					svs = append(svs, sv.RedactLockID())
 					return nil
 				})
 			if err != nil {
@@ -409,8 +784,7 @@ func (s *Variables) listAllVariables(
 			paginatorImpl, err := paginator.NewPaginator(iter, tokenizer, filters, args.QueryOptions,
 				func(raw interface{}) error {
 					sv := raw.(*structs.VariableEncrypted)
-					svStub := sv.VariableMetadata
-					svs = append(svs, &svStub)
+					svs = append(svs, sv.RedactLockID())
 					return nil
 				})
 			if err != nil {
@@ -468,6 +842,54 @@ func (sv *Variables) decrypt(v *structs.VariableEncrypted) (*structs.VariableDec
 	return &dv, nil
 }
 
+// RenewLock is used to renew the lease of a lock held on a variable. The
+// lease is tracked by the leader, so renewals are not written to raft.
+func (sv *Variables) RenewLock(args *structs.VariablesRenewLockRequest, reply *structs.VariablesRenewLockResponse) error {
+	if done, err := sv.srv.forward(structs.VariablesRenewLockRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "variables", "renew_lock"}, time.Now())
+
+	aclObj, err := sv.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	}
+	if aclObj != nil && !aclObj.AllowVariableOperation(
+		args.RequestNamespace(), args.Path, acl.VariablesCapabilityWrite) {
+		return structs.ErrPermissionDenied
+	}
+
+	if args.Path == "" {
+		return structs.NewErrRPCCoded(http.StatusBadRequest, "lock renew requires a Path")
+	}
+	if args.LockID == "" {
+		return structs.NewErrRPCCoded(http.StatusBadRequest, "lock renew requires a lock ID")
+	}
+
+	snap, err := sv.srv.fsm.State().Snapshot()
+	if err != nil {
+		return err
+	}
+	ev, err := snap.GetVariable(nil, args.RequestNamespace(), args.Path)
+	if err != nil {
+		return err
+	}
+	if ev == nil {
+		return structs.NewErrRPCCoded(http.StatusNotFound, "variable not found")
+	}
+	if ev.Lock == nil || ev.Lock.ID != args.LockID {
+		return structs.NewErrRPCCoded(http.StatusConflict, "lock is not held")
+	}
+
+	if err := sv.srv.resetLockTTLTimer(ev.Namespace, ev.Path, ev.Lock); err != nil {
+		return err
+	}
+
+	reply.VarMeta = ev.VariableMetadata.Copy()
+	reply.Index = ev.ModifyIndex
+	return nil
+}
+
 // handleMixedAuthEndpoint is a helper to handle auth on RPC endpoints that can
 // either be called by external clients or by workload identity
 func (sv *Variables) handleMixedAuthEndpoint(args structs.QueryOptions, cap, pathOrPrefix string) (*acl.ACL, error) {
@@ -51,7 +51,7 @@ func TestVariablesEndpoint_auth(t *testing.T) {
 	alloc3.Job.ParentID = jobID
 
 	store := srv.fsm.State()
-	must.NoError(t, store.UpsertNamespaces(1000, []*structs.Namespace{{Name: ns}}))
+	must.NoError(t, store.UpsertNamespaces(structs.MsgTypeTestSetup, 1000, []*structs.Namespace{{Name: ns}}))
 	must.NoError(t, store.UpsertAllocs(
 		structs.MsgTypeTestSetup, 1001, []*structs.Allocation{alloc1, alloc2, alloc3}))
 
@@ -487,7 +487,7 @@ namespace "*" {}
 
 	store := srv.fsm.State()
 
-	must.NoError(t, store.UpsertNamespaces(1000, []*structs.Namespace{
+	must.NoError(t, store.UpsertNamespaces(structs.MsgTypeTestSetup, 1000, []*structs.Namespace{
 		{Name: "dev"}, {Name: "prod"}, {Name: "other"}}))
 
 	idx++
@@ -498,7 +498,7 @@ namespace "*" {}
 		sv := mock.VariableEncrypted()
 		sv.Namespace = ns
 		sv.Path = path
-		resp := store.VarSet(idx, &structs.VarApplyStateRequest{
+		resp := store.VarSet(structs.MsgTypeTestSetup, idx, &structs.VarApplyStateRequest{
 			Op:  structs.VarOpSet,
 			Var: sv,
 		})
@@ -657,7 +657,7 @@ func TestVariablesEndpoint_GetVariable_Blocking(t *testing.T) {
 	time.AfterFunc(delay, func() {
 		sv := mock.VariableEncrypted()
 		sv.Path = "bbb"
-		if resp := state.VarDelete(400, &structs.VarApplyStateRequest{Op: structs.VarOpDelete, Var: sv}); !resp.IsOk() {
+		if resp := state.VarDelete(structs.MsgTypeTestSetup, 400, &structs.VarApplyStateRequest{Op: structs.VarOpDelete, Var: sv}); !resp.IsOk() {
 			t.Fatalf("err: %v", resp.Error)
 		}
 	})
@@ -700,9 +700,290 @@ func writeVar(t *testing.T, s *Server, idx uint64, ns, path string) {
 			KeyID: kID,
 		},
 	}
-	resp := store.VarSet(idx, &structs.VarApplyStateRequest{
+	resp := store.VarSet(structs.MsgTypeTestSetup, idx, &structs.VarApplyStateRequest{
 		Op:  structs.VarOpSet,
 		Var: sve,
 	})
 	must.NoError(t, resp.Error)
 }
+
+func TestVariablesEndpoint_Versions(t *testing.T) {
+	ci.Parallel(t)
+
+	srv, cleanup := TestServer(t, func(c *Config) {
+		c.VariablesVersionLimit = 2
+	})
+	defer cleanup()
+	testutil.WaitForLeader(t, srv.RPC)
+	codec := rpcClient(t, srv)
+
+	// write three versions of a variable
+	var indexes []uint64
+	for i := 1; i <= 3; i++ {
+		applyReq := structs.VariablesApplyRequest{
+			Op: structs.VarOpSet,
+			Var: &structs.VariableDecrypted{
+				VariableMetadata: structs.VariableMetadata{Path: "versioned"},
+				Items:            structs.VariableItems{"value": fmt.Sprint(i)},
+			},
+			WriteRequest: structs.WriteRequest{Region: "global"},
+		}
+		var applyResp structs.VariablesApplyResponse
+		must.NoError(t, msgpackrpc.CallWithCodec(codec, structs.VariablesApplyRPCMethod, &applyReq, &applyResp))
+		must.True(t, applyResp.IsOk())
+		indexes = append(indexes, applyResp.Output.ModifyIndex)
+	}
+
+	// list the versions, newest first
+	listReq := structs.VariablesListVersionsRequest{
+		Path:         "versioned",
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var listResp structs.VariablesListVersionsResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, structs.VariablesListVersionsRPCMethod, &listReq, &listResp))
+	must.Len(t, 3, listResp.Data)
+	must.Eq(t, indexes[2], listResp.Data[0].ModifyIndex)
+	must.Eq(t, indexes[1], listResp.Data[1].ModifyIndex)
+	must.Eq(t, indexes[0], listResp.Data[2].ModifyIndex)
+
+	// read a prior version
+	readReq := structs.VariablesReadRequest{
+		Path:         "versioned",
+		Version:      indexes[0],
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var readResp structs.VariablesReadResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, structs.VariablesReadRPCMethod, &readReq, &readResp))
+	must.NotNil(t, readResp.Data)
+	must.Eq(t, "1", readResp.Data.Items["value"])
+
+	// roll back to the prior version
+	rollbackReq := structs.VariablesApplyRequest{
+		Op: structs.VarOpRollback,
+		Var: &structs.VariableDecrypted{
+			VariableMetadata: structs.VariableMetadata{Path: "versioned"},
+		},
+		Version:      indexes[0],
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var rollbackResp structs.VariablesApplyResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, structs.VariablesApplyRPCMethod, &rollbackReq, &rollbackResp))
+	must.True(t, rollbackResp.IsOk())
+	must.Eq(t, "1", rollbackResp.Output.Items["value"])
+
+	readReq.Version = 0
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, structs.VariablesReadRPCMethod, &readReq, &readResp))
+	must.Eq(t, "1", readResp.Data.Items["value"])
+	must.Eq(t, rollbackResp.Output.ModifyIndex, readResp.Data.ModifyIndex)
+
+	// the oldest version was pruned by the rollback
+	rollbackReq.Version = indexes[0]
+	err := msgpackrpc.CallWithCodec(codec, structs.VariablesApplyRPCMethod, &rollbackReq, &rollbackResp)
+	must.Error(t, err)
+	must.StrContains(t, err.Error(), "not found")
+}
+
+func TestVariablesEndpoint_Locks(t *testing.T) {
+	ci.Parallel(t)
+
+	srv, cleanup := TestServer(t, nil)
+	defer cleanup()
+	testutil.WaitForLeader(t, srv.RPC)
+	codec := rpcClient(t, srv)
+
+	// acquire a lock, creating the variable
+	acquireReq := structs.VariablesApplyRequest{
+		Op: structs.VarOpLockAcquire,
+		Var: &structs.VariableDecrypted{
+			VariableMetadata: structs.VariableMetadata{
+				Path: "locked",
+				Lock: &structs.VariableLock{TTL: 30 * time.Second},
+			},
+			Items: structs.VariableItems{"owner": "first"},
+		},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var acquireResp structs.VariablesApplyResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, structs.VariablesApplyRPCMethod, &acquireReq, &acquireResp))
+	must.True(t, acquireResp.IsOk())
+	must.NotNil(t, acquireResp.Output.Lock)
+	lockID := acquireResp.Output.Lock.ID
+	must.NotEq(t, "", lockID)
+
+	srv.lockTTLTimersLock.Lock()
+	_, ok := srv.lockTTLTimers[lockID]
+	srv.lockTTLTimersLock.Unlock()
+	must.True(t, ok)
+
+	// a second acquire conflicts, without returning the lock ID
+	acquireReq.Var.Lock = &structs.VariableLock{TTL: 30 * time.Second}
+	acquireReq.Var.Items = nil
+	acquireResp = structs.VariablesApplyResponse{}
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, structs.VariablesApplyRPCMethod, &acquireReq, &acquireResp))
+	must.True(t, acquireResp.IsConflict())
+	must.NotNil(t, acquireResp.Conflict.Lock)
+	must.Eq(t, "", acquireResp.Conflict.Lock.ID)
+
+	// reads don't return the lock ID either
+	readReq := structs.VariablesReadRequest{
+		Path:         "locked",
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var readResp structs.VariablesReadResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, structs.VariablesReadRPCMethod, &readReq, &readResp))
+	must.NotNil(t, readResp.Data.Lock)
+	must.Eq(t, "", readResp.Data.Lock.ID)
+	must.Eq(t, 30*time.Second, readResp.Data.Lock.TTL)
+
+	// renewing requires the lock ID
+	renewReq := structs.VariablesRenewLockRequest{
+		Path:         "locked",
+		LockID:       "not-the-lock",
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var renewResp structs.VariablesRenewLockResponse
+	err := msgpackrpc.CallWithCodec(codec, structs.VariablesRenewLockRPCMethod, &renewReq, &renewResp)
+	must.StrContains(t, err.Error(), "lock is not held")
+
+	renewReq.LockID = lockID
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, structs.VariablesRenewLockRPCMethod, &renewReq, &renewResp))
+	must.Eq(t, lockID, renewResp.VarMeta.Lock.ID)
+
+	// only the lock holder can write the variable
+	writeReq := structs.VariablesApplyRequest{
+		Op: structs.VarOpSet,
+		Var: &structs.VariableDecrypted{
+			VariableMetadata: structs.VariableMetadata{
+				Path: "locked",
+			},
+			Items: structs.VariableItems{"owner": "second"},
+		},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var writeResp structs.VariablesApplyResponse
+	err = msgpackrpc.CallWithCodec(codec, structs.VariablesApplyRPCMethod, &writeReq, &writeResp)
+	must.Error(t, err)
+	must.StrContains(t, err.Error(), "can only be written by the lock holder")
+
+	writeReq.Var.Lock = &structs.VariableLock{ID: lockID}
+	writeReq.Var.Items = structs.VariableItems{"owner": "first"}
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, structs.VariablesApplyRPCMethod, &writeReq, &writeResp))
+	must.True(t, writeResp.IsOk())
+
+	// a locked variable can't be deleted
+	deleteReq := structs.VariablesApplyRequest{
+		Op: structs.VarOpDelete,
+		Var: &structs.VariableDecrypted{
+			VariableMetadata: structs.VariableMetadata{
+				Path: "locked",
+			},
+		},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var deleteResp structs.VariablesApplyResponse
+	err = msgpackrpc.CallWithCodec(codec, structs.VariablesApplyRPCMethod, &deleteReq, &deleteResp)
+	must.Error(t, err)
+	must.StrContains(t, err.Error(), "lock must be released first")
+
+	// a new leader restores the lease timers of held locks
+	srv.clearAllLockTTLTimers()
+	must.NoError(t, srv.initializeLockTTLTimers())
+	srv.lockTTLTimersLock.Lock()
+	_, ok = srv.lockTTLTimers[lockID]
+	srv.lockTTLTimersLock.Unlock()
+	must.True(t, ok)
+
+	// release the lock
+	releaseReq := structs.VariablesApplyRequest{
+		Op: structs.VarOpLockRelease,
+		Var: &structs.VariableDecrypted{
+			VariableMetadata: structs.VariableMetadata{
+				Path: "locked",
+				Lock: &structs.VariableLock{ID: lockID},
+			},
+		},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var releaseResp structs.VariablesApplyResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, structs.VariablesApplyRPCMethod, &releaseReq, &releaseResp))
+	must.True(t, releaseResp.IsOk())
+	must.Nil(t, releaseResp.Output.Lock)
+	must.Eq(t, "first", releaseResp.Output.Items["owner"])
+
+	srv.lockTTLTimersLock.Lock()
+	_, ok = srv.lockTTLTimers[lockID]
+	srv.lockTTLTimersLock.Unlock()
+	must.False(t, ok)
+
+	// the lock is released once its lease expires
+	acquireResp = structs.VariablesApplyResponse{}
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, structs.VariablesApplyRPCMethod, &acquireReq, &acquireResp))
+	must.True(t, acquireResp.IsOk())
+	must.Eq(t, "first", acquireResp.Output.Items["owner"])
+
+	srv.invalidateLock(acquireResp.Output.Namespace, "locked", acquireResp.Output.Lock.ID)
+
+	readResp = structs.VariablesReadResponse{}
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, structs.VariablesReadRPCMethod, &readReq, &readResp))
+	must.Nil(t, readResp.Data.Lock)
+
+	// locks can only be held by running allocations
+	acquireReq.Var.Lock = &structs.VariableLock{TTL: 30 * time.Second, AllocID: "8b3e2fd0-ef2c-4bcf-9c6b-5f3c5d6e7a1b"}
+	err = msgpackrpc.CallWithCodec(codec, structs.VariablesApplyRPCMethod, &acquireReq, &acquireResp)
+	must.StrContains(t, err.Error(), "not found")
+}
+
+func TestVariablesEndpoint_Locks_AllocStopped(t *testing.T) {
+	ci.Parallel(t)
+
+	srv, cleanup := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0
+	})
+	defer cleanup()
+	testutil.WaitForLeader(t, srv.RPC)
+	codec := rpcClient(t, srv)
+
+	node := mock.Node()
+	alloc := mock.Alloc()
+	alloc.NodeID = node.ID
+	store := srv.fsm.State()
+	must.NoError(t, store.UpsertNode(structs.MsgTypeTestSetup, 100, node))
+	must.NoError(t, store.UpsertJobSummary(101, mock.JobSummary(alloc.JobID)))
+	must.NoError(t, store.UpsertAllocs(structs.MsgTypeTestSetup, 102, []*structs.Allocation{alloc}))
+
+	// acquire a lock held by the allocation
+	acquireReq := structs.VariablesApplyRequest{
+		Op: structs.VarOpLockAcquire,
+		Var: &structs.VariableDecrypted{
+			VariableMetadata: structs.VariableMetadata{
+				Path: "locked",
+				Lock: &structs.VariableLock{TTL: 30 * time.Second, AllocID: alloc.ID},
+			},
+			Items: structs.VariableItems{"owner": "alloc"},
+		},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var acquireResp structs.VariablesApplyResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, structs.VariablesApplyRPCMethod, &acquireReq, &acquireResp))
+	must.True(t, acquireResp.IsOk())
+	lockID := acquireResp.Output.Lock.ID
+
+	// the lock and its lease timer are released once the allocation stops
+	stopped := alloc.Copy()
+	stopped.ClientStatus = structs.AllocClientStatusComplete
+	updateReq := &structs.AllocUpdateRequest{
+		Alloc:        []*structs.Allocation{stopped},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var updateResp structs.NodeAllocsResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Node.UpdateAlloc", updateReq, &updateResp))
+
+	_, sv, err := store.VarGet(nil, structs.DefaultNamespace, "locked")
+	must.NoError(t, err)
+	must.Nil(t, sv.Lock)
+
+	srv.lockTTLTimersLock.Lock()
+	_, ok := srv.lockTTLTimers[lockID]
+	srv.lockTTLTimersLock.Unlock()
+	must.False(t, ok)
+}
@@ -0,0 +1,213 @@
+package nomad
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	log "github.com/hashicorp/go-hclog"
+	memdb "github.com/hashicorp/go-memdb"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+var (
+	// lockTTLNotLeaderErr is the error returned when the lease of a variable
+	// lock couldn't be renewed since the server is not the leader.
+	lockTTLNotLeaderErr = errors.New("failed to renew lock since server is not leader")
+)
+
+// variableLocker is used to track expiration times of the leases of variable
+// locks. If it detects an expired lease, the lock is released.
+type variableLocker struct {
+	*Server
+	logger log.Logger
+
+	// lockTTLTimers track the expiration time of each lock lease, by lock
+	// ID. On expiration, the lock is released.
+	lockTTLTimers     map[string]*lockTTLTimer
+	lockTTLTimersLock sync.Mutex
+}
+
+// lockTTLTimer is the timer of a lock lease, along with the allocation
+// holding the lock, if any.
+type lockTTLTimer struct {
+	timer   *time.Timer
+	allocID string
+}
+
+// newVariableLocker returns a new variable locker used to detect and release
+// expired variable locks.
+func newVariableLocker(s *Server) *variableLocker {
+	return &variableLocker{
+		Server: s,
+		logger: s.logger.Named("variable_locks"),
+	}
+}
+
+// initializeLockTTLTimers is used when a leader is newly elected to reset the
+// timers of all the held variable locks. The leases are renewed for their full
+// TTL, as the previous leader may have renewed them just before failing over.
+func (l *variableLocker) initializeLockTTLTimers() error {
+	snap, err := l.fsm.State().Snapshot()
+	if err != nil {
+		return err
+	}
+
+	iter, err := snap.Variables(memdb.NewWatchSet())
+	if err != nil {
+		return err
+	}
+
+	l.lockTTLTimersLock.Lock()
+	defer l.lockTTLTimersLock.Unlock()
+
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		sv := raw.(*structs.VariableEncrypted)
+		if sv.Lock == nil {
+			continue
+		}
+		l.resetLockTTLTimerLocked(sv.Namespace, sv.Path, sv.Lock)
+	}
+	return nil
+}
+
+// resetLockTTLTimer is used to renew the lease of a variable lock. This can
+// be used for new locks and existing ones.
+func (l *variableLocker) resetLockTTLTimer(namespace, path string, lock *structs.VariableLock) error {
+	l.lockTTLTimersLock.Lock()
+	defer l.lockTTLTimersLock.Unlock()
+
+	// Do not create a timer for the lock since we are not the leader. This
+	// check avoids the race in which leadership is lost but a timer is
+	// created on this server since it was servicing an RPC during a
+	// leadership loss.
+	if !l.IsLeader() {
+		l.logger.Debug("ignoring resetting lock TTL since this server is not the leader",
+			"namespace", namespace, "path", path)
+		return lockTTLNotLeaderErr
+	}
+
+	l.resetLockTTLTimerLocked(namespace, path, lock)
+	return nil
+}
+
+// resetLockTTLTimerLocked is used to reset a lock timer assuming the
+// lockTTLTimersLock is already held
+func (l *variableLocker) resetLockTTLTimerLocked(namespace, path string, lock *structs.VariableLock) {
+	if l.lockTTLTimers == nil {
+		l.lockTTLTimers = make(map[string]*lockTTLTimer)
+	}
+
+	if t, ok := l.lockTTLTimers[lock.ID]; ok {
+		t.timer.Reset(lock.TTL)
+		return
+	}
+
+	lockID := lock.ID
+	l.lockTTLTimers[lockID] = &lockTTLTimer{
+		timer: time.AfterFunc(lock.TTL, func() {
+			l.invalidateLock(namespace, path, lockID)
+		}),
+		allocID: lock.AllocID,
+	}
+}
+
+// invalidateLock is invoked when the lease of a lock expires and the lock
+// needs to be released.
+func (l *variableLocker) invalidateLock(namespace, path, lockID string) {
+	defer metrics.MeasureSince([]string{"nomad", "variables", "lock", "invalidate"}, time.Now())
+
+	l.clearLockTTLTimer(lockID)
+
+	// Do not release the lock since we are not the leader. This check avoids
+	// the race in which leadership is lost but a timer is created on this
+	// server since it was servicing an RPC during a leadership loss.
+	if !l.IsLeader() {
+		l.logger.Debug("ignoring lock TTL since this server is not the leader",
+			"namespace", namespace, "path", path)
+		return
+	}
+
+	// The lock may have been released without the timer being cleared, such
+	// as when the allocation holding it was lost with its node.
+	_, sv, err := l.fsm.State().VarGet(nil, namespace, path)
+	if err != nil {
+		l.logger.Error("looking up expired lock failed", "error", err)
+		return
+	}
+	if sv == nil || sv.Lock == nil || sv.Lock.ID != lockID {
+		l.logger.Debug("expired lock already released", "namespace", namespace, "path", path)
+		return
+	}
+
+	l.logger.Debug("lock TTL expired", "namespace", namespace, "path", path)
+
+	req := structs.VarApplyStateRequest{
+		Op: structs.VarOpLockRelease,
+		Var: &structs.VariableEncrypted{
+			VariableMetadata: structs.VariableMetadata{
+				Namespace:  namespace,
+				Path:       path,
+				ModifyTime: time.Now().UnixNano(),
+				Lock:       &structs.VariableLock{ID: lockID},
+			},
+		},
+		WriteRequest: structs.WriteRequest{
+			Region:    l.config.Region,
+			Namespace: namespace,
+		},
+	}
+
+	out, _, err := l.raftApply(structs.VarApplyStateRequestType, req)
+	if err != nil {
+		l.logger.Error("releasing expired lock failed", "error", err)
+		return
+	}
+
+	// A conflict means the lock was already released, or the variable was
+	// deleted, since the lease expired.
+	if resp := out.(*structs.VarApplyStateResponse); resp.IsError() {
+		l.logger.Error("releasing expired lock failed", "error", resp.Error)
+	}
+}
+
+// clearLockTTLTimer is used to clear the lease timer of a single lock. This
+// is used when a lock is released explicitly and no longer needed.
+func (l *variableLocker) clearLockTTLTimer(lockID string) {
+	l.lockTTLTimersLock.Lock()
+	defer l.lockTTLTimersLock.Unlock()
+
+	if t, ok := l.lockTTLTimers[lockID]; ok {
+		t.timer.Stop()
+		delete(l.lockTTLTimers, lockID)
+	}
+}
+
+// clearAllocLockTTLTimers is used to clear the lease timers of the locks held
+// by allocations. This is used when the allocations stop on their client, as
+// the state store releases the locks they held.
+func (l *variableLocker) clearAllocLockTTLTimers(allocIDs map[string]struct{}) {
+	l.lockTTLTimersLock.Lock()
+	defer l.lockTTLTimersLock.Unlock()
+
+	for lockID, t := range l.lockTTLTimers {
+		if _, ok := allocIDs[t.allocID]; ok {
+			t.timer.Stop()
+			delete(l.lockTTLTimers, lockID)
+		}
+	}
+}
+
+// clearAllLockTTLTimers is used when a leader is stepping down and we no
+// longer need to track any lock timers.
+func (l *variableLocker) clearAllLockTTLTimers() {
+	l.lockTTLTimersLock.Lock()
+	defer l.lockTTLTimersLock.Unlock()
+
+	for _, t := range l.lockTTLTimers {
+		t.timer.Stop()
+	}
+	l.lockTTLTimers = nil
+}
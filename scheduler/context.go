@@ -43,6 +43,10 @@ type Context interface {
 	// eval.
 	Eligibility() *EvalEligibility
 
+	// ScoringAllNodes returns whether every feasible node must be scored for
+	// a placement, rather than the limited number normally scored.
+	ScoringAllNodes() bool
+
 	// SendEvent provides best-effort delivery of scheduling and placement
 	// events.
 	SendEvent(event interface{})
@@ -132,6 +136,11 @@ type EvalContext struct {
 	logger      log.Logger
 	metrics     *structs.AllocMetric
 	eligibility *EvalEligibility
+
+	// explainScores is set when the metrics must retain the scores of the
+	// nodes scored, and scoreAllNodes when every feasible node is scored
+	explainScores bool
+	scoreAllNodes bool
 }
 
 // NewEvalContext constructs a new EvalContext
@@ -168,6 +177,32 @@ func (e *EvalContext) SetState(s State) {
 
 func (e *EvalContext) Reset() {
 	e.metrics = new(structs.AllocMetric)
+	if e.explainScores {
+		e.metrics.ExplainScores(e.explainLimit())
+	}
+}
+
+// ExplainScores makes the metrics of each placement retain the scores of the
+// nodes scored rather than only those of the top scoring nodes. If
+// scoreAllNodes is set every feasible node is scored and explained, which may
+// select different nodes than the limited scoring of placements otherwise
+// does. Otherwise only the top MaxPersistedScoreExplanations nodes are
+// explained, as the metrics are persisted.
+func (e *EvalContext) ExplainScores(scoreAllNodes bool) {
+	e.explainScores = true
+	e.scoreAllNodes = scoreAllNodes
+	e.metrics.ExplainScores(e.explainLimit())
+}
+
+func (e *EvalContext) explainLimit() int {
+	if e.scoreAllNodes {
+		return 0
+	}
+	return structs.MaxPersistedScoreExplanations
+}
+
+func (e *EvalContext) ScoringAllNodes() bool {
+	return e.scoreAllNodes
 }
 
 func (e *EvalContext) ProposedAllocs(nodeID string) ([]*structs.Allocation, error) {
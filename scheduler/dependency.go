@@ -0,0 +1,148 @@
+package scheduler
+
+import (
+	"fmt"
+
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// unmetDependency returns the first dependency of the job whose condition
+// isn't met, along with a description of why. Dependencies are only checked
+// while the job has no non-terminal allocations, so allocations that are
+// already placed are never held back because a dependency changed state.
+func unmetDependency(state State, job *structs.Job) (*structs.JobDependency, string, error) {
+	if job == nil || job.Stopped() || len(job.DependsOn) == 0 {
+		return nil, "", nil
+	}
+
+	ws := memdb.NewWatchSet()
+	allocs, err := state.AllocsByJob(ws, job.Namespace, job.ID, false)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get allocs for job %q: %v", job.ID, err)
+	}
+	for _, alloc := range allocs {
+		if !alloc.TerminalStatus() {
+			return nil, "", nil
+		}
+	}
+
+	for _, dep := range job.DependsOn {
+		met, desc, err := dependencyMet(state, job.Namespace, dep)
+		if err != nil {
+			return nil, "", err
+		}
+		if !met {
+			return dep, desc, nil
+		}
+	}
+	return nil, "", nil
+}
+
+// dependencyMet returns whether the job or group depended on has reached the
+// required state. If not, a description of why is returned.
+func dependencyMet(state State, namespace string, dep *structs.JobDependency) (bool, string, error) {
+	ws := memdb.NewWatchSet()
+	job, err := state.JobByID(ws, namespace, dep.Job)
+	if err != nil {
+		return false, "", fmt.Errorf("failed to get job %q: %v", dep.Job, err)
+	}
+	if job == nil {
+		return false, fmt.Sprintf("job %q not found", dep.Job), nil
+	}
+	if job.Stopped() {
+		return false, fmt.Sprintf("job %q is stopped", dep.Job), nil
+	}
+
+	groups := job.TaskGroups
+	if dep.Group != "" {
+		tg := job.LookupTaskGroup(dep.Group)
+		if tg == nil {
+			return false, fmt.Sprintf("group %q not found in job %q", dep.Group, dep.Job), nil
+		}
+		groups = []*structs.TaskGroup{tg}
+	}
+
+	allocs, err := state.AllocsByJob(ws, namespace, dep.Job, false)
+	if err != nil {
+		return false, "", fmt.Errorf("failed to get allocs for job %q: %v", dep.Job, err)
+	}
+
+	// Only consider the latest allocation of each placement, ignoring the
+	// allocations which have been stopped or replaced.
+	current := make(map[string][]*structs.Allocation, len(groups))
+	for _, alloc := range allocs {
+		if alloc.DesiredStatus != structs.AllocDesiredStatusRun || alloc.NextAllocation != "" {
+			continue
+		}
+		current[alloc.TaskGroup] = append(current[alloc.TaskGroup], alloc)
+	}
+
+	switch dep.Condition {
+	case structs.JobDependencyConditionRunning:
+		for _, tg := range groups {
+			for _, alloc := range current[tg.Name] {
+				if alloc.ClientStatus == structs.AllocClientStatusRunning {
+					return true, "", nil
+				}
+			}
+		}
+		return false, fmt.Sprintf("%s has no running allocation", dependencyTarget(dep)), nil
+
+	case structs.JobDependencyConditionComplete:
+		for _, tg := range groups {
+			if len(current[tg.Name]) == 0 {
+				return false, fmt.Sprintf("%s has not completed", dependencyTarget(dep)), nil
+			}
+			for _, alloc := range current[tg.Name] {
+				if alloc.ClientStatus != structs.AllocClientStatusComplete {
+					return false, fmt.Sprintf("%s has not completed", dependencyTarget(dep)), nil
+				}
+			}
+		}
+		return true, "", nil
+
+	case structs.JobDependencyConditionHealthy:
+		deployment, err := state.LatestDeploymentByJobID(ws, namespace, dep.Job)
+		if err != nil {
+			return false, "", fmt.Errorf("failed to get deployment for job %q: %v", dep.Job, err)
+		}
+
+		// Use the deployment of the current version of the job if there is
+		// one, otherwise all of the allocations must be running.
+		if deployment != nil && deployment.JobVersion == job.Version {
+			healthy := deployment.Status == structs.DeploymentStatusSuccessful
+			if dep.Group != "" {
+				dstate := deployment.TaskGroups[dep.Group]
+				healthy = dstate != nil && dstate.HealthyAllocs >= dstate.DesiredTotal
+			}
+			if !healthy {
+				return false, fmt.Sprintf("%s is not healthy", dependencyTarget(dep)), nil
+			}
+			return true, "", nil
+		}
+
+		for _, tg := range groups {
+			running := 0
+			for _, alloc := range current[tg.Name] {
+				if alloc.ClientStatus == structs.AllocClientStatusRunning {
+					running++
+				}
+			}
+			if running < tg.Count {
+				return false, fmt.Sprintf("%s is not healthy", dependencyTarget(dep)), nil
+			}
+		}
+		return true, "", nil
+	}
+
+	return false, fmt.Sprintf("unknown dependency condition %q", dep.Condition), nil
+}
+
+// dependencyTarget returns a description of the job or group depended on.
+func dependencyTarget(dep *structs.JobDependency) string {
+	if dep.Group != "" {
+		return fmt.Sprintf("group %q of job %q", dep.Group, dep.Job)
+	}
+	return fmt.Sprintf("job %q", dep.Job)
+}
@@ -0,0 +1,203 @@
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmetDependency(t *testing.T) {
+	ci.Parallel(t)
+
+	// depAlloc returns an allocation of the job depended on with the given
+	// client status.
+	depAlloc := func(job *structs.Job, clientStatus string) *structs.Allocation {
+		alloc := mock.Alloc()
+		alloc.Job = job
+		alloc.JobID = job.ID
+		alloc.TaskGroup = job.TaskGroups[0].Name
+		alloc.ClientStatus = clientStatus
+		return alloc
+	}
+
+	cases := []struct {
+		name      string
+		condition string
+		group     string
+		setup     func(h *Harness, depJob *structs.Job)
+		met       bool
+		desc      string
+	}{
+		{
+			name:      "missing job",
+			condition: structs.JobDependencyConditionRunning,
+			desc:      `job "db" not found`,
+		},
+		{
+			name:      "not running",
+			condition: structs.JobDependencyConditionRunning,
+			setup: func(h *Harness, depJob *structs.Job) {
+				require.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), depJob))
+				require.NoError(t, h.State.UpsertAllocs(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Allocation{
+					depAlloc(depJob, structs.AllocClientStatusPending),
+				}))
+			},
+			desc: `job "db" has no running allocation`,
+		},
+		{
+			name:      "running",
+			condition: structs.JobDependencyConditionRunning,
+			setup: func(h *Harness, depJob *structs.Job) {
+				require.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), depJob))
+				require.NoError(t, h.State.UpsertAllocs(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Allocation{
+					depAlloc(depJob, structs.AllocClientStatusPending),
+					depAlloc(depJob, structs.AllocClientStatusRunning),
+				}))
+			},
+			met: true,
+		},
+		{
+			name:      "missing group",
+			condition: structs.JobDependencyConditionRunning,
+			group:     "cache",
+			setup: func(h *Harness, depJob *structs.Job) {
+				require.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), depJob))
+			},
+			desc: `group "cache" not found in job "db"`,
+		},
+		{
+			name:      "not complete",
+			condition: structs.JobDependencyConditionComplete,
+			setup: func(h *Harness, depJob *structs.Job) {
+				require.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), depJob))
+				require.NoError(t, h.State.UpsertAllocs(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Allocation{
+					depAlloc(depJob, structs.AllocClientStatusComplete),
+					depAlloc(depJob, structs.AllocClientStatusRunning),
+				}))
+			},
+			desc: `job "db" has not completed`,
+		},
+		{
+			name:      "complete after reschedule",
+			condition: structs.JobDependencyConditionComplete,
+			setup: func(h *Harness, depJob *structs.Job) {
+				require.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), depJob))
+				failed := depAlloc(depJob, structs.AllocClientStatusFailed)
+				replacement := depAlloc(depJob, structs.AllocClientStatusComplete)
+				failed.NextAllocation = replacement.ID
+				require.NoError(t, h.State.UpsertAllocs(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Allocation{
+					failed, replacement,
+				}))
+			},
+			met: true,
+		},
+		{
+			name:      "deployment not healthy",
+			condition: structs.JobDependencyConditionHealthy,
+			setup: func(h *Harness, depJob *structs.Job) {
+				require.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), depJob))
+				d := mock.Deployment()
+				d.JobID = depJob.ID
+				d.JobVersion = 0
+				require.NoError(t, h.State.UpsertDeployment(h.NextIndex(), d))
+			},
+			desc: `job "db" is not healthy`,
+		},
+		{
+			name:      "deployment healthy",
+			condition: structs.JobDependencyConditionHealthy,
+			setup: func(h *Harness, depJob *structs.Job) {
+				require.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), depJob))
+				d := mock.Deployment()
+				d.JobID = depJob.ID
+				d.JobVersion = 0
+				d.Status = structs.DeploymentStatusSuccessful
+				require.NoError(t, h.State.UpsertDeployment(h.NextIndex(), d))
+			},
+			met: true,
+		},
+		{
+			name:      "deployment group healthy",
+			condition: structs.JobDependencyConditionHealthy,
+			group:     "web",
+			setup: func(h *Harness, depJob *structs.Job) {
+				require.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), depJob))
+				d := mock.Deployment()
+				d.JobID = depJob.ID
+				d.JobVersion = 0
+				d.TaskGroups["web"].HealthyAllocs = 10
+				require.NoError(t, h.State.UpsertDeployment(h.NextIndex(), d))
+			},
+			met: true,
+		},
+		{
+			name:      "healthy without deployment",
+			condition: structs.JobDependencyConditionHealthy,
+			setup: func(h *Harness, depJob *structs.Job) {
+				depJob.TaskGroups[0].Count = 2
+				require.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), depJob))
+				require.NoError(t, h.State.UpsertAllocs(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Allocation{
+					depAlloc(depJob, structs.AllocClientStatusRunning),
+					depAlloc(depJob, structs.AllocClientStatusRunning),
+				}))
+			},
+			met: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := NewHarness(t)
+
+			depJob := mock.Job()
+			depJob.ID = "db"
+			if tc.setup != nil {
+				tc.setup(h, depJob)
+			}
+
+			job := mock.Job()
+			job.DependsOn = []*structs.JobDependency{
+				{Job: "db", Group: tc.group, Condition: tc.condition},
+			}
+
+			dep, desc, err := unmetDependency(h.State, job)
+			require.NoError(t, err)
+			if tc.met {
+				require.Nil(t, dep)
+			} else {
+				require.Equal(t, job.DependsOn[0], dep)
+				require.Equal(t, tc.desc, desc)
+			}
+		})
+	}
+}
+
+func TestUnmetDependency_Started(t *testing.T) {
+	ci.Parallel(t)
+
+	h := NewHarness(t)
+
+	job := mock.Job()
+	job.DependsOn = []*structs.JobDependency{
+		{Job: "db", Condition: structs.JobDependencyConditionHealthy},
+	}
+	require.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), job))
+
+	// Dependencies are checked while the job hasn't started
+	dep, _, err := unmetDependency(h.State, job)
+	require.NoError(t, err)
+	require.NotNil(t, dep)
+
+	// Dependencies aren't checked once the job has started
+	alloc := mock.Alloc()
+	alloc.Job = job
+	alloc.JobID = job.ID
+	require.NoError(t, h.State.UpsertAllocs(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Allocation{alloc}))
+
+	dep, _, err = unmetDependency(h.State, job)
+	require.NoError(t, err)
+	require.Nil(t, dep)
+}
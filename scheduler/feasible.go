@@ -2,8 +2,10 @@ package scheduler
 
 import (
 	"fmt"
+	"net"
 	"reflect"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -756,6 +758,12 @@ func (c *ConstraintChecker) Feasible(option *structs.Node) bool {
 }
 
 func (c *ConstraintChecker) meetsConstraint(constraint *structs.Constraint, option *structs.Node) bool {
+	// Constraints on the allocations of a node are handled by the
+	// AllocConstraintChecker, as they can't be cached by computed class.
+	if structs.IsAllocsTarget(constraint.LTarget) {
+		return true
+	}
+
 	// Resolve the targets. Targets that are not present are treated as `nil`.
 	// This is to allow for matching constraints where a target is not present.
 	lVal, lOk := resolveTarget(constraint.LTarget, option)
@@ -765,6 +773,129 @@ func (c *ConstraintChecker) meetsConstraint(constraint *structs.Constraint, opti
 	return checkConstraint(c.ctx, constraint.Operand, lVal, rVal, lOk, rOk)
 }
 
+// AllocConstraintChecker is a FeasibilityChecker which returns nodes whose
+// proposed allocations match a given set of constraints. This is used to
+// filter on job, task group, and task constraints that target "${allocs.*}"
+// rather than the node itself.
+type AllocConstraintChecker struct {
+	ctx            Context
+	namespace      string
+	jobConstraints []*structs.Constraint
+	constraints    []*structs.Constraint
+}
+
+// NewAllocConstraintChecker creates an AllocConstraintChecker
+func NewAllocConstraintChecker(ctx Context) *AllocConstraintChecker {
+	return &AllocConstraintChecker{
+		ctx: ctx,
+	}
+}
+
+// SetJob sets the namespace whose allocations are considered and the job
+// level allocation constraints.
+func (c *AllocConstraintChecker) SetJob(job *structs.Job) {
+	c.namespace = job.Namespace
+	c.jobConstraints = allocsConstraints(job.Constraints)
+}
+
+// SetTaskGroup sets the allocation constraints of the task group and its
+// tasks.
+func (c *AllocConstraintChecker) SetTaskGroup(tg *structs.TaskGroup) {
+	tgConstr := taskGroupConstraints(tg)
+	c.constraints = append(c.jobConstraints[:len(c.jobConstraints):len(c.jobConstraints)],
+		allocsConstraints(tgConstr.constraints)...)
+}
+
+func (c *AllocConstraintChecker) Feasible(option *structs.Node) bool {
+	if len(c.constraints) == 0 {
+		return true
+	}
+
+	allocs, err := c.ctx.ProposedAllocs(option.ID)
+	if err != nil {
+		c.ctx.Logger().Named("alloc_constraint").Error("failed to get proposed allocations", "error", err)
+		return false
+	}
+	allocs = filterAllocsByNamespace(allocs, c.namespace)
+
+	for _, constraint := range c.constraints {
+		lVal, lOk := resolveAllocsTarget(constraint.LTarget, allocs)
+		if !checkConstraint(c.ctx, constraint.Operand, lVal, constraint.RTarget, lOk, true) {
+			c.ctx.Metrics().FilterNode(option, constraint.String())
+			return false
+		}
+	}
+	return true
+}
+
+// allocsConstraints returns the constraints that target the allocations on a
+// node.
+func allocsConstraints(constraints []*structs.Constraint) []*structs.Constraint {
+	var out []*structs.Constraint
+	for _, constraint := range constraints {
+		if structs.IsAllocsTarget(constraint.LTarget) {
+			out = append(out, constraint)
+		}
+	}
+	return out
+}
+
+// filterAllocsByNamespace returns the allocations in the given namespace.
+func filterAllocsByNamespace(allocs []*structs.Allocation, namespace string) []*structs.Allocation {
+	out := make([]*structs.Allocation, 0, len(allocs))
+	for _, alloc := range allocs {
+		if alloc.Namespace == namespace {
+			out = append(out, alloc)
+		}
+	}
+	return out
+}
+
+// resolveAllocsTarget is used to resolve an "${allocs.*}" target against the
+// allocations on a node. The result is the sorted, comma separated set of
+// distinct values so that it can be compared with the set operators. Targets
+// with no values resolve to the empty set.
+func resolveAllocsTarget(target string, allocs []*structs.Allocation) (string, bool) {
+	seen := make(map[string]struct{}, len(allocs))
+	for _, alloc := range allocs {
+		switch {
+		case target == structs.ConstraintAllocsJobID:
+			seen[alloc.JobID] = struct{}{}
+
+		case strings.HasPrefix(target, structs.ConstraintAllocsMetaPrefix):
+			key := strings.TrimSuffix(strings.TrimPrefix(target, structs.ConstraintAllocsMetaPrefix), "}")
+			if val, ok := allocMeta(alloc, key); ok {
+				seen[val] = struct{}{}
+			}
+
+		default:
+			return "", false
+		}
+	}
+
+	values := make([]string, 0, len(seen))
+	for val := range seen {
+		values = append(values, val)
+	}
+	sort.Strings(values)
+	return strings.Join(values, ","), true
+}
+
+// allocMeta returns the value of a meta key for an allocation, with the task
+// group meta taking precedence over the job meta.
+func allocMeta(alloc *structs.Allocation, key string) (string, bool) {
+	if alloc.Job == nil {
+		return "", false
+	}
+	if tg := alloc.Job.LookupTaskGroup(alloc.TaskGroup); tg != nil {
+		if val, ok := tg.Meta[key]; ok {
+			return val, true
+		}
+	}
+	val, ok := alloc.Job.Meta[key]
+	return val, ok
+}
+
 // resolveTarget is used to resolve the LTarget and RTarget of a Constraint.
 func resolveTarget(target string, node *structs.Node) (string, bool) {
 	// If no prefix, this must be a literal value
@@ -819,6 +950,11 @@ func checkConstraint(ctx Context, operand string, lVal, rVal interface{}, lFound
 		return !reflect.DeepEqual(lVal, rVal)
 	case "<", "<=", ">", ">=":
 		return lFound && rFound && checkLexicalOrder(operand, lVal, rVal)
+	case structs.ConstraintNumericLess, structs.ConstraintNumericLessEqual,
+		structs.ConstraintNumericGreater, structs.ConstraintNumericGreaterEqual:
+		return lFound && rFound && checkNumericOrder(operand, lVal, rVal)
+	case structs.ConstraintCIDRContains:
+		return lFound && rFound && checkCIDRContains(lVal, rVal)
 	case structs.ConstraintAttributeIsSet:
 		return lFound
 	case structs.ConstraintAttributeIsNotSet:
@@ -835,6 +971,8 @@ func checkConstraint(ctx Context, operand string, lVal, rVal interface{}, lFound
 		return lFound && rFound && checkSetContainsAll(ctx, lVal, rVal)
 	case structs.ConstraintSetContainsAny:
 		return lFound && rFound && checkSetContainsAny(lVal, rVal)
+	case structs.ConstraintSetContainsNone:
+		return rFound && !checkSetContainsAny(lVal, rVal)
 	default:
 		return false
 	}
@@ -876,6 +1014,78 @@ func checkLexicalOrder(op string, lVal, rVal interface{}) bool {
 	}
 }
 
+// checkNumericOrder is used to check for numeric ordering. Unlike
+// checkLexicalOrder, values which are not numbers never satisfy the order.
+func checkNumericOrder(op string, lVal, rVal interface{}) bool {
+	// Ensure the values are strings
+	lStr, ok := lVal.(string)
+	if !ok {
+		return false
+	}
+	rStr, ok := rVal.(string)
+	if !ok {
+		return false
+	}
+
+	// Parse the numbers
+	l, err := strconv.ParseFloat(strings.TrimSpace(lStr), 64)
+	if err != nil {
+		return false
+	}
+	r, err := strconv.ParseFloat(strings.TrimSpace(rStr), 64)
+	if err != nil {
+		return false
+	}
+
+	return compareNumericOrder(op, l, r)
+}
+
+// compareNumericOrder applies a numeric operator to the two numbers.
+func compareNumericOrder(op string, l, r float64) bool {
+	switch op {
+	case structs.ConstraintNumericLess:
+		return l < r
+	case structs.ConstraintNumericLessEqual:
+		return l <= r
+	case structs.ConstraintNumericGreater:
+		return l > r
+	case structs.ConstraintNumericGreaterEqual:
+		return l >= r
+	default:
+		return false
+	}
+}
+
+// checkCIDRContains is used to check if the IP address on the left hand side
+// is within any of the comma separated CIDR blocks on the right hand side
+func checkCIDRContains(lVal, rVal interface{}) bool {
+	// Ensure the values are strings
+	lStr, ok := lVal.(string)
+	if !ok {
+		return false
+	}
+	rStr, ok := rVal.(string)
+	if !ok {
+		return false
+	}
+
+	ip := net.ParseIP(strings.TrimSpace(lStr))
+	if ip == nil {
+		return false
+	}
+
+	for _, cidr := range strings.Split(rStr, ",") {
+		_, network, err := net.ParseCIDR(strings.TrimSpace(cidr))
+		if err != nil {
+			return false
+		}
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
 // checkVersionMatch is used to compare a version on the
 // left hand side with a set of constraints on the right hand side
 func checkVersionMatch(ctx Context, parse verConstraintParser, lVal, rVal interface{}) bool {
@@ -912,6 +1122,15 @@ func checkVersionMatch(ctx Context, parse verConstraintParser, lVal, rVal interf
 	return constraints.Check(vers)
 }
 
+// isNumericAttribute returns whether the attribute is an int or a float.
+func isNumericAttribute(a *psstructs.Attribute) bool {
+	if _, ok := a.GetInt(); ok {
+		return true
+	}
+	_, ok := a.GetFloat()
+	return ok
+}
+
 // checkAttributeVersionMatch is used to compare a version on the
 // left hand side with a set of constraints on the right hand side
 func checkAttributeVersionMatch(ctx Context, parse verConstraintParser, lVal, rVal *psstructs.Attribute) bool {
@@ -1405,6 +1624,36 @@ func checkAttributeConstraint(ctx Context, operand string, lVal, rVal *psstructs
 			return false
 		}
 
+	case structs.ConstraintNumericLess, structs.ConstraintNumericLessEqual,
+		structs.ConstraintNumericGreater, structs.ConstraintNumericGreaterEqual:
+		if !(lFound && rFound) {
+			return false
+		}
+
+		if !isNumericAttribute(lVal) || !isNumericAttribute(rVal) {
+			return false
+		}
+
+		// Compare the values with their units, so only the sign of the
+		// comparison matters
+		v, ok := lVal.Compare(rVal)
+		if !ok {
+			return false
+		}
+		return compareNumericOrder(operand, float64(v), 0)
+
+	case structs.ConstraintCIDRContains:
+		if !(lFound && rFound) {
+			return false
+		}
+
+		ls, ok := lVal.GetString()
+		rs, ok2 := rVal.GetString()
+		if !ok || !ok2 {
+			return false
+		}
+		return checkCIDRContains(ls, rs)
+
 	case structs.ConstraintVersion:
 		if !(lFound && rFound) {
 			return false
@@ -1456,6 +1705,21 @@ func checkAttributeConstraint(ctx Context, operand string, lVal, rVal *psstructs
 		}
 
 		return checkSetContainsAny(ls, rs)
+	case structs.ConstraintSetContainsNone:
+		if !rFound {
+			return false
+		}
+		if !lFound {
+			return true
+		}
+
+		ls, ok := lVal.GetString()
+		rs, ok2 := rVal.GetString()
+		if !ok || !ok2 {
+			return false
+		}
+
+		return !checkSetContainsAny(ls, rs)
 	case structs.ConstraintAttributeIsSet:
 		return lFound
 	case structs.ConstraintAttributeIsNotSet:
@@ -912,6 +912,101 @@ func TestConstraintChecker(t *testing.T) {
 	}
 }
 
+func TestAllocConstraintChecker(t *testing.T) {
+	ci.Parallel(t)
+
+	_, ctx := testContext(t)
+	nodes := []*structs.Node{
+		mock.Node(),
+		mock.Node(),
+		mock.Node(),
+	}
+
+	db := mock.Job()
+	db.ID = "db"
+	db.Meta = map[string]string{"tier": "storage"}
+	batch := mock.BatchJob()
+	batch.ID = "batch"
+	other := mock.Job()
+	other.ID = "db"
+	other.Namespace = "other"
+
+	// Node 0 runs the database, node 1 runs the database and a batch job
+	// and node 2 only runs a job with the same ID in another namespace.
+	plan := ctx.Plan()
+	newAlloc := func(job *structs.Job) *structs.Allocation {
+		return &structs.Allocation{
+			ID:        uuid.Generate(),
+			Namespace: job.Namespace,
+			JobID:     job.ID,
+			Job:       job,
+			TaskGroup: job.TaskGroups[0].Name,
+		}
+	}
+	plan.NodeAllocation[nodes[0].ID] = []*structs.Allocation{newAlloc(db)}
+	plan.NodeAllocation[nodes[1].ID] = []*structs.Allocation{newAlloc(db), newAlloc(batch)}
+	plan.NodeAllocation[nodes[2].ID] = []*structs.Allocation{newAlloc(other)}
+
+	job := mock.Job()
+	job.Constraints = append(job.Constraints, &structs.Constraint{
+		Operand: structs.ConstraintSetContains,
+		LTarget: "${allocs.meta.tier}",
+		RTarget: "storage",
+	})
+	tg := job.TaskGroups[0]
+	tg.Constraints = append(tg.Constraints, &structs.Constraint{
+		Operand: structs.ConstraintSetContainsNone,
+		LTarget: "${allocs.job_id}",
+		RTarget: "batch",
+	})
+
+	checker := NewAllocConstraintChecker(ctx)
+	checker.SetJob(job)
+	checker.SetTaskGroup(tg)
+
+	require.True(t, checker.Feasible(nodes[0]))
+	require.False(t, checker.Feasible(nodes[1]))
+	require.False(t, checker.Feasible(nodes[2]))
+
+	// Node constraints are ignored by the checker and allocation constraints
+	// are ignored by the ConstraintChecker
+	require.True(t, NewConstraintChecker(ctx, tg.Constraints).Feasible(nodes[1]))
+}
+
+func TestResolveAllocsTarget(t *testing.T) {
+	ci.Parallel(t)
+
+	job := mock.Job()
+	job.Meta = map[string]string{"tier": "web", "owner": "ops"}
+	job.TaskGroups[0].Meta = map[string]string{"tier": "cache"}
+
+	allocs := []*structs.Allocation{
+		{JobID: "b", Job: job, TaskGroup: job.TaskGroups[0].Name},
+		{JobID: "a", Job: job, TaskGroup: "missing"},
+		{JobID: "b"},
+	}
+
+	cases := []struct {
+		target string
+		result string
+		found  bool
+	}{
+		{target: "${allocs.job_id}", result: "a,b", found: true},
+		{target: "${allocs.meta.tier}", result: "cache,web", found: true},
+		{target: "${allocs.meta.owner}", result: "ops", found: true},
+		{target: "${allocs.meta.missing}", result: "", found: true},
+		{target: "${allocs.unknown}", result: "", found: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.target, func(t *testing.T) {
+			res, ok := resolveAllocsTarget(tc.target, allocs)
+			require.Equal(t, tc.found, ok)
+			require.Equal(t, tc.result, res)
+		})
+	}
+}
+
 func TestResolveConstraintTarget(t *testing.T) {
 	ci.Parallel(t)
 
@@ -1098,6 +1193,21 @@ func TestCheckConstraint(t *testing.T) {
 			lVal: "foo,bar,baz", rVal: "foo,bam",
 			result: false,
 		},
+		{
+			op:   structs.ConstraintSetContainsNone,
+			lVal: "foo,bar,baz", rVal: "bam, baz",
+			result: false,
+		},
+		{
+			op:   structs.ConstraintSetContainsNone,
+			lVal: "foo,bar,baz", rVal: "bam",
+			result: true,
+		},
+		{
+			op:   structs.ConstraintSetContainsNone,
+			lVal: nil, rVal: "bam",
+			result: true,
+		},
 		{
 			op:     structs.ConstraintAttributeIsSet,
 			lVal:   "foo",
@@ -1118,6 +1228,26 @@ func TestCheckConstraint(t *testing.T) {
 			lVal:   "foo",
 			result: false,
 		},
+		{
+			op:   structs.ConstraintNumericGreater,
+			lVal: "10", rVal: "9",
+			result: true,
+		},
+		{
+			op:     structs.ConstraintNumericGreater,
+			rVal:   "9",
+			result: false,
+		},
+		{
+			op:   structs.ConstraintCIDRContains,
+			lVal: "10.0.1.5", rVal: "10.0.0.0/16",
+			result: true,
+		},
+		{
+			op:     structs.ConstraintCIDRContains,
+			rVal:   "10.0.0.0/16",
+			result: false,
+		},
 	}
 
 	for _, tc := range cases {
@@ -1170,6 +1300,107 @@ func TestCheckLexicalOrder(t *testing.T) {
 	}
 }
 
+func TestCheckNumericOrder(t *testing.T) {
+	ci.Parallel(t)
+
+	type tcase struct {
+		op         string
+		lVal, rVal interface{}
+		result     bool
+	}
+	cases := []tcase{
+		{
+			// Lexically "10" < "9"
+			op:   structs.ConstraintNumericLess,
+			lVal: "9", rVal: "10",
+			result: true,
+		},
+		{
+			op:   structs.ConstraintNumericLessEqual,
+			lVal: "1.0", rVal: "1",
+			result: true,
+		},
+		{
+			op:   structs.ConstraintNumericGreater,
+			lVal: "17179869184", rVal: "8589934592",
+			result: true,
+		},
+		{
+			op:   structs.ConstraintNumericGreaterEqual,
+			lVal: "-2", rVal: "-1.5",
+			result: false,
+		},
+		{
+			op:   structs.ConstraintNumericGreater,
+			lVal: "foo", rVal: "1",
+			result: false,
+		},
+		{
+			op:   structs.ConstraintNumericLess,
+			lVal: "1", rVal: "foo",
+			result: false,
+		},
+		{
+			op:   structs.ConstraintNumericLess,
+			lVal: 1, rVal: "2",
+			result: false,
+		},
+	}
+	for _, tc := range cases {
+		if res := checkNumericOrder(tc.op, tc.lVal, tc.rVal); res != tc.result {
+			t.Fatalf("TC: %#v, Result: %v", tc, res)
+		}
+	}
+}
+
+func TestCheckCIDRContains(t *testing.T) {
+	ci.Parallel(t)
+
+	type tcase struct {
+		lVal, rVal interface{}
+		result     bool
+	}
+	cases := []tcase{
+		{
+			lVal: "10.0.1.5", rVal: "10.0.0.0/16",
+			result: true,
+		},
+		{
+			lVal: "10.1.1.5", rVal: "10.0.0.0/16",
+			result: false,
+		},
+		{
+			lVal: "192.168.1.20", rVal: "10.0.0.0/16, 192.168.1.0/24",
+			result: true,
+		},
+		{
+			lVal: "2001:db8::1", rVal: "2001:db8::/32",
+			result: true,
+		},
+		{
+			lVal: "2001:db8::1", rVal: "10.0.0.0/8",
+			result: false,
+		},
+		{
+			lVal: "not-an-ip", rVal: "10.0.0.0/8",
+			result: false,
+		},
+		{
+			lVal: "10.0.0.1", rVal: "10.0.0.0",
+			result: false,
+		},
+		{
+			lVal: 1, rVal: "10.0.0.0/8",
+			result: false,
+		},
+	}
+	for _, tc := range cases {
+		if res := checkCIDRContains(tc.lVal, tc.rVal); res != tc.result {
+			t.Fatalf("TC: %#v, Result: %v", tc, res)
+		}
+	}
+}
+
 func TestCheckVersionConstraint(t *testing.T) {
 	ci.Parallel(t)
 
@@ -2936,6 +3167,36 @@ func TestCheckAttributeConstraint(t *testing.T) {
 			lVal:   nil,
 			result: true,
 		},
+		{
+			op:     structs.ConstraintNumericGreater,
+			lVal:   psstructs.NewIntAttribute(2, psstructs.UnitGiB),
+			rVal:   psstructs.NewIntAttribute(1024, psstructs.UnitMiB),
+			result: true,
+		},
+		{
+			op:     structs.ConstraintNumericLessEqual,
+			lVal:   psstructs.NewFloatAttribute(1.5, ""),
+			rVal:   psstructs.NewIntAttribute(2, ""),
+			result: true,
+		},
+		{
+			op:     structs.ConstraintNumericLess,
+			lVal:   psstructs.NewStringAttribute("1"),
+			rVal:   psstructs.NewIntAttribute(2, ""),
+			result: false,
+		},
+		{
+			op:     structs.ConstraintCIDRContains,
+			lVal:   psstructs.NewStringAttribute("10.0.1.5"),
+			rVal:   psstructs.NewStringAttribute("10.0.0.0/16"),
+			result: true,
+		},
+		{
+			op:     structs.ConstraintCIDRContains,
+			lVal:   psstructs.NewIntAttribute(10, ""),
+			rVal:   psstructs.NewStringAttribute("10.0.0.0/16"),
+			result: false,
+		},
 	}
 
 	for _, tc := range cases {
@@ -59,6 +59,14 @@ const (
 	// that are a result of failing to place all allocations.
 	blockedEvalFailedPlacements = "created to place remaining allocations"
 
+	// blockedEvalDependency is the description used for blocked evals that
+	// are a result of a job dependency not being met.
+	blockedEvalDependency = "created to wait on job dependency"
+
+	// failedEvalDependencyNotFound is the description used for evals that
+	// fail because a job depended on doesn't exist.
+	failedEvalDependencyNotFound = "job dependency can never be met"
+
 	// reschedulingFollowupEvalDesc is the description used when creating follow
 	// up evals for delayed rescheduling
 	reschedulingFollowupEvalDesc = "created for delayed rescheduling"
@@ -164,7 +172,8 @@ func (s *GenericScheduler) Process(eval *structs.Evaluation) (err error) {
 		structs.EvalTriggerPeriodicJob, structs.EvalTriggerMaxPlans,
 		structs.EvalTriggerDeploymentWatcher, structs.EvalTriggerRetryFailedAlloc,
 		structs.EvalTriggerFailedFollowUp, structs.EvalTriggerPreemption,
-		structs.EvalTriggerScaling, structs.EvalTriggerMaxDisconnectTimeout, structs.EvalTriggerReconnect:
+		structs.EvalTriggerScaling, structs.EvalTriggerMaxDisconnectTimeout, structs.EvalTriggerReconnect,
+		structs.EvalTriggerRebalance:
 	default:
 		desc := fmt.Sprintf("scheduler cannot handle '%s' evaluation reason",
 			eval.TriggeredBy)
@@ -173,6 +182,11 @@ func (s *GenericScheduler) Process(eval *structs.Evaluation) (err error) {
 			s.deployment.GetID())
 	}
 
+	// Hold the evaluation until the dependencies of the job are met.
+	if held, err := s.holdForDependencies(); held || err != nil {
+		return err
+	}
+
 	// Retry up to the maxScheduleAttempts and reset if progress is made.
 	progress := func() bool { return progressMade(s.planResult) }
 	limit := maxServiceScheduleAttempts
@@ -237,6 +251,61 @@ func (s *GenericScheduler) createBlockedEval(planFailure bool) error {
 	return s.planner.CreateEval(s.blocked)
 }
 
+// holdForDependencies blocks the evaluation until the dependencies of the job
+// are met. It returns whether the evaluation was held.
+func (s *GenericScheduler) holdForDependencies() (bool, error) {
+	job, err := s.state.JobByID(nil, s.eval.Namespace, s.eval.JobID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get job %q: %v", s.eval.JobID, err)
+	}
+	dep, desc, err := unmetDependency(s.state, job)
+	if err != nil || dep == nil {
+		return false, err
+	}
+
+	// A job depended on that doesn't exist, because it was never registered
+	// or has been purged or garbage collected, will never change to unblock
+	// the evaluation, so it fails instead of being held forever.
+	depJob, err := s.state.JobByID(nil, s.eval.Namespace, dep.Job)
+	if err != nil {
+		return false, fmt.Errorf("failed to get job %q: %v", dep.Job, err)
+	}
+	if depJob == nil {
+		s.logger.Debug("job dependency not found, failing evaluation", "dependency", dep.Job)
+		s.queuedAllocs = make(map[string]int, len(job.TaskGroups))
+		for _, tg := range job.TaskGroups {
+			s.queuedAllocs[tg.Name] = 0
+		}
+		return true, setStatus(s.logger, s.planner, s.eval, nil, nil, nil,
+			structs.EvalStatusFailed, fmt.Sprintf("%s: %s", failedEvalDependencyNotFound, desc),
+			s.queuedAllocs, "")
+	}
+
+	s.logger.Debug("job dependency not met, holding evaluation", "dependency", dep.Job, "reason", desc)
+
+	// If the evaluation is already blocked on the same job, reblock it.
+	// Otherwise a new blocked eval is created so that the job it waits on is
+	// persisted.
+	if s.eval.Status == structs.EvalStatusBlocked && s.eval.BlockedOnJob == dep.Job {
+		return true, s.planner.ReblockEval(s.eval.Copy())
+	}
+
+	s.queuedAllocs = make(map[string]int, len(job.TaskGroups))
+	for _, tg := range job.TaskGroups {
+		s.queuedAllocs[tg.Name] = tg.Count
+	}
+
+	s.blocked = s.eval.CreateBlockedEval(nil, false, "", nil)
+	s.blocked.BlockedOnJob = dep.Job
+	s.blocked.StatusDescription = fmt.Sprintf("%s: %s", blockedEvalDependency, desc)
+	if err := s.planner.CreateEval(s.blocked); err != nil {
+		return true, err
+	}
+
+	return true, setStatus(s.logger, s.planner, s.eval, nil, s.blocked,
+		nil, structs.EvalStatusComplete, "", s.queuedAllocs, "")
+}
+
 // process is wrapped in retryMax to iteratively run the handler until we have no
 // further work or we've made the maximum number of attempts.
 func (s *GenericScheduler) process() (bool, error) {
@@ -272,6 +341,11 @@ func (s *GenericScheduler) process() (bool, error) {
 
 	// Create an evaluation context
 	s.ctx = NewEvalContext(s.eventsCh, s.state, s.plan, s.logger)
+	// Every node is scored when explaining the scores of a plan, while the
+	// placements of an evaluation are scored as usual
+	if s.eval.ExplainScores {
+		s.ctx.ExplainScores(s.eval.AnnotatePlan)
+	}
 
 	// Construct the placement stack
 	s.stack = NewGenericStack(s.batch, s.ctx)
@@ -387,6 +461,13 @@ func (s *GenericScheduler) computeJobAllocs() error {
 		s.plan.Annotations = &structs.PlanAnnotations{
 			DesiredTGUpdates: results.desiredTGUpdates,
 		}
+		if s.job != nil {
+			priority, err := preemptionPriority(s.state, s.job)
+			if err != nil {
+				return err
+			}
+			s.plan.Annotations.PreemptionPriority = priority
+		}
 	}
 
 	// Add the deployment changes to the plan
@@ -514,6 +595,10 @@ func (s *GenericScheduler) computePlacements(destructive, place []placementResul
 	// Capture current time to use as the start time for any rescheduled allocations
 	now := time.Now()
 
+	// Track the placements of gang task groups so they can be undone if any
+	// placement of the group fails
+	gangPlaced := make(map[string][]gangPlacement)
+
 	// Have to handle destructive changes first as we need to discount their
 	// resources. To understand this imagine the resources were reduced and the
 	// count was scaled up.
@@ -653,6 +738,14 @@ func (s *GenericScheduler) computePlacements(destructive, place []placementResul
 				// Track the placement
 				s.plan.AppendAlloc(alloc, downgradedJob)
 
+				if tg.Gang {
+					placed := gangPlacement{alloc: alloc}
+					if stopPrevAlloc {
+						placed.stoppedPrev = prevAllocation
+					}
+					gangPlaced[tg.Name] = append(gangPlaced[tg.Name], placed)
+				}
+
 			} else {
 				// Lazy initialize the failed map
 				if s.failedTGAllocs == nil {
@@ -675,9 +768,86 @@ func (s *GenericScheduler) computePlacements(destructive, place []placementResul
 		}
 	}
 
+	// Gang task groups are placed all at once or not at all, so undo the
+	// placements of those which failed to place any allocation. The failed
+	// placements cause a blocked eval to be created for the whole group.
+	for tgName, placed := range gangPlaced {
+		metric, ok := s.failedTGAllocs[tgName]
+		if !ok {
+			continue
+		}
+		s.undoGangPlacements(tgName, placed)
+		metric.CoalescedFailures += len(placed)
+	}
+
 	return nil
 }
 
+// gangPlacement is a placement made for a gang task group, along with the
+// previous allocation whose stop was added to the plan for it.
+type gangPlacement struct {
+	alloc       *structs.Allocation
+	stoppedPrev *structs.Allocation
+}
+
+// undoGangPlacements removes the placements of a gang task group from the
+// plan, along with the preemptions and stops of previous allocations made for
+// them.
+func (s *GenericScheduler) undoGangPlacements(tgName string, placed []gangPlacement) {
+	for _, p := range placed {
+		alloc := p.alloc
+		s.plan.NodeAllocation[alloc.NodeID] = structs.RemoveAllocs(
+			s.plan.NodeAllocation[alloc.NodeID], []*structs.Allocation{alloc})
+		if len(s.plan.NodeAllocation[alloc.NodeID]) == 0 {
+			delete(s.plan.NodeAllocation, alloc.NodeID)
+		}
+
+		if p.stoppedPrev != nil {
+			prev := p.stoppedPrev
+			s.plan.NodeUpdate[prev.NodeID] = structs.RemoveAllocs(
+				s.plan.NodeUpdate[prev.NodeID], []*structs.Allocation{prev})
+			if len(s.plan.NodeUpdate[prev.NodeID]) == 0 {
+				delete(s.plan.NodeUpdate, prev.NodeID)
+			}
+		}
+
+		if len(alloc.PreemptedAllocations) == 0 {
+			continue
+		}
+
+		preempted := make(map[string]struct{}, len(alloc.PreemptedAllocations))
+		for _, id := range alloc.PreemptedAllocations {
+			preempted[id] = struct{}{}
+		}
+		for nodeID, preemptions := range s.plan.NodePreemptions {
+			var remaining []*structs.Allocation
+			for _, stop := range preemptions {
+				if stop.PreemptedByAllocation != alloc.ID {
+					remaining = append(remaining, stop)
+				}
+			}
+			if len(remaining) == 0 {
+				delete(s.plan.NodePreemptions, nodeID)
+			} else {
+				s.plan.NodePreemptions[nodeID] = remaining
+			}
+		}
+
+		if s.eval.AnnotatePlan && s.plan.Annotations != nil {
+			var stubs []*structs.AllocListStub
+			for _, stub := range s.plan.Annotations.PreemptedAllocs {
+				if _, ok := preempted[stub.ID]; !ok {
+					stubs = append(stubs, stub)
+				}
+			}
+			s.plan.Annotations.PreemptedAllocs = stubs
+			if desired, ok := s.plan.Annotations.DesiredTGUpdates[tgName]; ok {
+				desired.Preemptions -= uint64(len(alloc.PreemptedAllocations))
+			}
+		}
+	}
+}
+
 // propagateTaskState copies task handles from previous allocations to
 // replacement allocations when the previous allocation is being drained or was
 // lost. Remote task drivers rely on this to reconnect to remote tasks when the
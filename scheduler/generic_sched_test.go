@@ -110,6 +110,75 @@ func TestServiceSched_JobRegister(t *testing.T) {
 	h.AssertEvalStatus(t, structs.EvalStatusComplete)
 }
 
+func TestServiceSched_JobRegister_ExplainScores(t *testing.T) {
+	ci.Parallel(t)
+
+	// Plans score and explain every node, while evaluations only explain
+	// the nodes scored as usual
+	for _, plan := range []bool{false, true} {
+		t.Run(fmt.Sprintf("plan=%v", plan), func(t *testing.T) {
+			h := NewHarness(t)
+
+			// Create more nodes than the limit iterator would score
+			for i := 0; i < 10; i++ {
+				node := mock.Node()
+				require.NoError(t, h.State.UpsertNode(structs.MsgTypeTestSetup, h.NextIndex(), node))
+			}
+
+			// Create a job
+			job := mock.Job()
+			require.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), job))
+
+			// Create a mock evaluation to register the job
+			eval := &structs.Evaluation{
+				Namespace:     structs.DefaultNamespace,
+				ID:            uuid.Generate(),
+				Priority:      job.Priority,
+				TriggeredBy:   structs.EvalTriggerJobRegister,
+				JobID:         job.ID,
+				Status:        structs.EvalStatusPending,
+				AnnotatePlan:  plan,
+				ExplainScores: true,
+			}
+			require.NoError(t, h.State.UpsertEvals(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Evaluation{eval}))
+
+			// Process the evaluation
+			require.NoError(t, h.Process(NewServiceScheduler, eval))
+			require.Len(t, h.Plans, 1)
+
+			var planned []*structs.Allocation
+			for _, allocList := range h.Plans[0].NodeAllocation {
+				planned = append(planned, allocList...)
+			}
+			require.Len(t, planned, 10)
+
+			for _, alloc := range planned {
+				// The nodes are retained sorted by normalized score and with
+				// the score of each scorer.
+				explanation := alloc.Metrics.ScoreExplanation
+				if plan {
+					require.Len(t, explanation, 10)
+				} else {
+					require.NotEmpty(t, explanation)
+					require.Less(t, len(explanation), 10)
+				}
+				require.True(t, sort.SliceIsSorted(explanation, func(i, j int) bool {
+					return explanation[i].NormScore > explanation[j].NormScore
+				}))
+				var selected bool
+				for _, scoreMeta := range explanation {
+					require.Contains(t, scoreMeta.Scores, "binpack")
+					require.Contains(t, scoreMeta.Scores, "job-anti-affinity")
+					if scoreMeta.NodeID == alloc.NodeID {
+						selected = true
+					}
+				}
+				require.True(t, selected, "selected node missing from the explanation")
+			}
+		})
+	}
+}
+
 func TestServiceSched_JobRegister_MemoryMaxHonored(t *testing.T) {
 	ci.Parallel(t)
 
@@ -912,6 +981,81 @@ func TestServiceSched_EvenSpread(t *testing.T) {
 	h.AssertEvalStatus(t, structs.EvalStatusComplete)
 }
 
+// Test job registration with a spread max skew across dc
+func TestServiceSched_SpreadMaxSkew(t *testing.T) {
+	ci.Parallel(t)
+
+	cases := []struct {
+		name string
+
+		// dc2Feasible sets whether the single node in dc2 passes the job
+		// constraints. An infeasible dc2 is left out of the spread domain
+		dc2Feasible bool
+		expected    map[string]int
+	}{
+		{
+			name:        "balanced",
+			dc2Feasible: true,
+			expected:    map[string]int{"dc1": 3, "dc2": 3},
+		},
+		{
+			name:        "infeasible",
+			dc2Feasible: false,
+			expected:    map[string]int{"dc1": 6},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := NewHarness(t)
+
+			job := mock.Job()
+			job.Datacenters = []string{"dc1", "dc2"}
+			job.TaskGroups[0].Count = 6
+			job.TaskGroups[0].Spreads = []*structs.Spread{{
+				Attribute: "${node.datacenter}",
+				Weight:    50,
+				MaxSkew:   1,
+			}}
+			require.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), job))
+
+			// Create four nodes in dc1 and one in dc2
+			nodeMap := make(map[string]*structs.Node)
+			for i := 0; i < 5; i++ {
+				node := mock.Node()
+				if i == 0 {
+					node.Datacenter = "dc2"
+					if !tc.dc2Feasible {
+						node.Attributes["kernel.name"] = "windows"
+					}
+					require.NoError(t, node.ComputeClass())
+				}
+				require.NoError(t, h.State.UpsertNode(structs.MsgTypeTestSetup, h.NextIndex(), node))
+				nodeMap[node.ID] = node
+			}
+
+			eval := &structs.Evaluation{
+				Namespace:   structs.DefaultNamespace,
+				ID:          uuid.Generate(),
+				Priority:    job.Priority,
+				TriggeredBy: structs.EvalTriggerJobRegister,
+				JobID:       job.ID,
+				Status:      structs.EvalStatusPending,
+			}
+			require.NoError(t, h.State.UpsertEvals(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Evaluation{eval}))
+			require.NoError(t, h.Process(NewServiceScheduler, eval))
+
+			require.Len(t, h.Plans, 1)
+			dcAllocsMap := make(map[string]int)
+			for nodeID, allocList := range h.Plans[0].NodeAllocation {
+				dcAllocsMap[nodeMap[nodeID].Datacenter] += len(allocList)
+			}
+			require.Equal(t, tc.expected, dcAllocsMap)
+			require.Empty(t, h.CreateEvals)
+		})
+	}
+}
+
 func TestServiceSched_JobRegister_Annotate(t *testing.T) {
 	ci.Parallel(t)
 
@@ -4928,6 +5072,80 @@ func TestBatchSched_ScaleDown_SameName(t *testing.T) {
 	h.AssertEvalStatus(t, structs.EvalStatusComplete)
 }
 
+func TestBatchSched_Gang(t *testing.T) {
+	ci.Parallel(t)
+
+	cases := []struct {
+		name           string
+		gang           bool
+		expectedPlaced int
+	}{
+		{
+			name:           "partial placement without gang",
+			gang:           false,
+			expectedPlaced: 2,
+		},
+		{
+			name:           "no placement with gang",
+			gang:           true,
+			expectedPlaced: 0,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := NewHarness(t)
+
+			// Create two nodes which each fit a single allocation
+			for i := 0; i < 2; i++ {
+				node := mock.Node()
+				require.NoError(t, h.State.UpsertNode(structs.MsgTypeTestSetup, h.NextIndex(), node))
+			}
+
+			// Create a job which needs four allocations
+			job := mock.Job()
+			job.Type = structs.JobTypeBatch
+			job.TaskGroups[0].Count = 4
+			job.TaskGroups[0].Gang = tc.gang
+			job.TaskGroups[0].Tasks[0].Resources.CPU = 3000
+			require.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), job))
+
+			// Create a mock evaluation to register the job
+			eval := &structs.Evaluation{
+				Namespace:   structs.DefaultNamespace,
+				ID:          uuid.Generate(),
+				Priority:    job.Priority,
+				TriggeredBy: structs.EvalTriggerJobRegister,
+				JobID:       job.ID,
+				Status:      structs.EvalStatusPending,
+			}
+			require.NoError(t, h.State.UpsertEvals(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Evaluation{eval}))
+
+			// Process the evaluation
+			require.NoError(t, h.Process(NewBatchScheduler, eval))
+
+			// Ensure the expected allocations were placed
+			ws := memdb.NewWatchSet()
+			out, err := h.State.AllocsByJob(ws, job.Namespace, job.ID, false)
+			require.NoError(t, err)
+			require.Len(t, out, tc.expectedPlaced)
+
+			// Ensure the remaining allocations are blocked
+			require.Len(t, h.CreateEvals, 1)
+			require.Equal(t, structs.EvalStatusBlocked, h.CreateEvals[0].Status)
+
+			require.Len(t, h.Evals, 1)
+			outEval := h.Evals[0]
+			metrics, ok := outEval.FailedTGAllocs[job.TaskGroups[0].Name]
+			require.True(t, ok)
+			require.Equal(t, 4-tc.expectedPlaced-1, metrics.CoalescedFailures)
+			require.Equal(t, 4-tc.expectedPlaced, outEval.QueuedAllocations[job.TaskGroups[0].Name])
+
+			h.AssertEvalStatus(t, structs.EvalStatusComplete)
+		})
+	}
+}
+
 func TestGenericSched_AllocFit_Lifecycle(t *testing.T) {
 	ci.Parallel(t)
 
@@ -6716,3 +6934,125 @@ func initNodeAndAllocs(t *testing.T, h *Harness, allocCount int,
 	require.NoError(t, h.State.UpsertAllocs(structs.MsgTypeTestSetup, h.NextIndex(), allocs))
 	return node, job, allocs
 }
+
+func TestServiceSched_JobDependency(t *testing.T) {
+	ci.Parallel(t)
+
+	h := NewHarness(t)
+
+	// Create some nodes
+	for i := 0; i < 10; i++ {
+		node := mock.Node()
+		require.NoError(t, h.State.UpsertNode(structs.MsgTypeTestSetup, h.NextIndex(), node))
+	}
+
+	// Create a job which depends on a job that isn't running
+	dep := mock.Job()
+	require.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), dep))
+
+	job := mock.Job()
+	job.DependsOn = []*structs.JobDependency{
+		{Job: dep.ID, Condition: structs.JobDependencyConditionRunning},
+	}
+	require.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), job))
+
+	eval := &structs.Evaluation{
+		Namespace:   structs.DefaultNamespace,
+		ID:          uuid.Generate(),
+		Priority:    job.Priority,
+		TriggeredBy: structs.EvalTriggerJobRegister,
+		JobID:       job.ID,
+		Status:      structs.EvalStatusPending,
+	}
+	require.NoError(t, h.State.UpsertEvals(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Evaluation{eval}))
+
+	// Process the evaluation
+	require.NoError(t, h.Process(NewServiceScheduler, eval))
+
+	// Ensure nothing was placed and a blocked eval waits on the dependency
+	require.Empty(t, h.Plans)
+	require.Len(t, h.CreateEvals, 1)
+	blocked := h.CreateEvals[0]
+	require.Equal(t, structs.EvalStatusBlocked, blocked.Status)
+	require.Equal(t, dep.ID, blocked.BlockedOnJob)
+	require.Contains(t, blocked.StatusDescription, "has no running allocation")
+
+	require.Len(t, h.Evals, 1)
+	require.Equal(t, structs.EvalStatusComplete, h.Evals[0].Status)
+	require.Equal(t, blocked.ID, h.Evals[0].BlockedEval)
+	require.Equal(t, 10, h.Evals[0].QueuedAllocations["web"])
+
+	// Processing the blocked eval while the dependency isn't met reblocks it
+	require.NoError(t, h.State.UpsertEvals(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Evaluation{blocked}))
+	require.NoError(t, h.Process(NewServiceScheduler, blocked))
+	require.Empty(t, h.Plans)
+	require.Len(t, h.ReblockEvals, 1)
+	require.Equal(t, blocked.ID, h.ReblockEvals[0].ID)
+
+	// Once the dependency is running, the blocked eval places the job
+	alloc := mock.Alloc()
+	alloc.Job = dep
+	alloc.JobID = dep.ID
+	alloc.ClientStatus = structs.AllocClientStatusRunning
+	require.NoError(t, h.State.UpsertAllocs(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Allocation{alloc}))
+
+	require.NoError(t, h.Process(NewServiceScheduler, blocked))
+	require.Len(t, h.Plans, 1)
+	var planned []*structs.Allocation
+	for _, allocList := range h.Plans[0].NodeAllocation {
+		planned = append(planned, allocList...)
+	}
+	require.Len(t, planned, 10)
+	require.Len(t, h.ReblockEvals, 1)
+	require.Len(t, h.Evals, 2)
+	require.Equal(t, blocked.ID, h.Evals[1].ID)
+	require.Equal(t, structs.EvalStatusComplete, h.Evals[1].Status)
+}
+
+func TestServiceSched_JobDependency_NotFound(t *testing.T) {
+	ci.Parallel(t)
+
+	h := NewHarness(t)
+
+	// Create a job which depends on a job that is later purged
+	dep := mock.Job()
+	require.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), dep))
+
+	job := mock.Job()
+	job.DependsOn = []*structs.JobDependency{
+		{Job: dep.ID, Condition: structs.JobDependencyConditionRunning},
+	}
+	require.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), job))
+
+	eval := &structs.Evaluation{
+		Namespace:   structs.DefaultNamespace,
+		ID:          uuid.Generate(),
+		Priority:    job.Priority,
+		TriggeredBy: structs.EvalTriggerJobRegister,
+		JobID:       job.ID,
+		Status:      structs.EvalStatusPending,
+	}
+	require.NoError(t, h.State.UpsertEvals(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Evaluation{eval}))
+
+	// Process the evaluation, which waits on the dependency
+	require.NoError(t, h.Process(NewServiceScheduler, eval))
+	require.Len(t, h.CreateEvals, 1)
+	blocked := h.CreateEvals[0]
+	require.Equal(t, dep.ID, blocked.BlockedOnJob)
+
+	// Once the dependency is purged the blocked eval fails rather than
+	// waiting forever
+	require.NoError(t, h.State.DeleteJob(h.NextIndex(), dep.Namespace, dep.ID))
+	require.NoError(t, h.State.UpsertEvals(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Evaluation{blocked}))
+	require.NoError(t, h.Process(NewServiceScheduler, blocked))
+
+	require.Empty(t, h.Plans)
+	require.Empty(t, h.ReblockEvals)
+	require.Len(t, h.CreateEvals, 1)
+	require.Len(t, h.Evals, 2)
+	failed := h.Evals[1]
+	require.Equal(t, blocked.ID, failed.ID)
+	require.Equal(t, structs.EvalStatusFailed, failed.Status)
+	require.Equal(t, fmt.Sprintf("job dependency can never be met: job %q not found", dep.ID), failed.StatusDescription)
+	require.Equal(t, 0, failed.QueuedAllocations["web"])
+}
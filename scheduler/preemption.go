@@ -307,7 +307,7 @@ func (p *Preemptor) PreemptForNetwork(networkResourceAsk *structs.NetworkResourc
 		net := networks[0]
 
 		// Filter out alloc that's ineligible due to priority
-		if p.jobPriority-alloc.Job.Priority < 10 {
+		if !isPreemptible(p.jobPriority, alloc) {
 			// Populate any reserved ports used by
 			// this allocation that cannot be preempted
 			for _, port := range net.ReservedPorts {
@@ -658,6 +658,30 @@ func scoreForNetwork(resourceUsed *structs.NetworkResource, resourceNeeded *stru
 	return networkResourceDistance(resourceUsed, resourceNeeded) + maxParallelScorePenalty
 }
 
+// isPreemptible returns whether the allocation may be preempted by a job of
+// the given priority. The priority of the allocation's job must be at least 10
+// lower, and its job must not be non-preemptible.
+func isPreemptible(jobPriority int, alloc *structs.Allocation) bool {
+	if alloc.Job.NonPreemptible {
+		return false
+	}
+	return jobPriority-alloc.Job.Priority >= 10
+}
+
+// preemptionPriority returns the priority with which the job may preempt
+// allocations, which is capped by the preemption priority ceiling of the
+// job's namespace.
+func preemptionPriority(state State, job *structs.Job) (int, error) {
+	ns, err := state.NamespaceByName(nil, job.Namespace)
+	if err != nil {
+		return 0, err
+	}
+	if ns != nil && ns.PreemptionPriorityCeiling > 0 && job.Priority > ns.PreemptionPriorityCeiling {
+		return ns.PreemptionPriorityCeiling, nil
+	}
+	return job.Priority, nil
+}
+
 // filterAndGroupPreemptibleAllocs groups allocations by priority after filtering allocs
 // that are not preemptible based on the jobPriority arg
 func filterAndGroupPreemptibleAllocs(jobPriority int, current []*structs.Allocation) []*groupedAllocs {
@@ -667,10 +691,10 @@ func filterAndGroupPreemptibleAllocs(jobPriority int, current []*structs.Allocat
 			continue
 		}
 
-		// Skip allocs whose priority is within a delta of 10
-		// This also skips any allocs of the current job
+		// Skip allocs whose priority is within a delta of 10 or whose job
+		// is non-preemptible. This also skips any allocs of the current job
 		// for which we are attempting preemption
-		if jobPriority-alloc.Job.Priority < 10 {
+		if !isPreemptible(jobPriority, alloc) {
 			continue
 		}
 		grpAllocs, ok := allocsByPriority[alloc.Job.Priority]
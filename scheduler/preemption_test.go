@@ -1385,6 +1385,86 @@ func TestPreemption(t *testing.T) {
 	}
 }
 
+// TestPreemption_PriorityBands tests that non-preemptible jobs and the
+// preemption priority ceiling of namespaces are honored
+func TestPreemption_PriorityBands(t *testing.T) {
+	ci.Parallel(t)
+
+	testCases := []struct {
+		desc           string
+		nonPreemptible bool
+		ceiling        int
+		preempted      bool
+	}{
+		{
+			desc:      "preempts low priority alloc",
+			preempted: true,
+		},
+		{
+			desc:           "non-preemptible job",
+			nonPreemptible: true,
+		},
+		{
+			desc:    "ceiling within priority delta",
+			ceiling: 35,
+		},
+		{
+			desc:      "ceiling above priority delta",
+			ceiling:   50,
+			preempted: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			state, ctx := testContext(t)
+
+			node := mock.Node()
+			require.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, 1000, node))
+
+			ns := mock.Namespace()
+			ns.PreemptionPriorityCeiling = tc.ceiling
+			require.NoError(t, state.UpsertNamespaces(structs.MsgTypeTestSetup, 1001, []*structs.Namespace{ns}))
+
+			lowPrioJob := mock.Job()
+			lowPrioJob.Priority = 30
+			lowPrioJob.NonPreemptible = tc.nonPreemptible
+			alloc := createAlloc(uuid.Generate(), lowPrioJob, &structs.Resources{
+				CPU:      3200,
+				MemoryMB: 256,
+			})
+			alloc.NodeID = node.ID
+			require.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 1002, []*structs.Allocation{alloc}))
+
+			job := mock.Job()
+			job.Namespace = ns.Name
+			job.Priority = 100
+
+			static := NewStaticRankIterator(ctx, []*RankedNode{{Node: node}})
+			binPackIter := NewBinPackIterator(ctx, static, true, job.Priority, testSchedulerConfig)
+			binPackIter.SetJob(job)
+			binPackIter.SetTaskGroup(&structs.TaskGroup{
+				EphemeralDisk: &structs.EphemeralDisk{},
+				Tasks: []*structs.Task{
+					{
+						Name:      "web",
+						Resources: &structs.Resources{CPU: 1000, MemoryMB: 256},
+					},
+				},
+			})
+
+			option := binPackIter.Next()
+			if !tc.preempted {
+				require.Nil(t, option)
+				return
+			}
+			require.NotNil(t, option)
+			require.Len(t, option.PreemptedAllocs, 1)
+			require.Equal(t, alloc.ID, option.PreemptedAllocs[0].ID)
+		})
+	}
+}
+
 // TestPreemptionMultiple tests evicting multiple allocations in the same time
 func TestPreemptionMultiple(t *testing.T) {
 	ci.Parallel(t)
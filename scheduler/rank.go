@@ -156,34 +156,82 @@ type BinPackIterator struct {
 	jobId                  structs.NamespacedID
 	taskGroup              *structs.TaskGroup
 	memoryOversubscription bool
+	schedConfig            *structs.SchedulerConfiguration
+	algorithm              structs.SchedulerAlgorithm
 	scoreFit               func(*structs.Node, *structs.ComparableResources) float64
+	headroomPercent        int
 }
 
 // NewBinPackIterator returns a BinPackIterator which tries to fit tasks
 // potentially evicting other tasks based on a given priority.
 func NewBinPackIterator(ctx Context, source RankIterator, evict bool, priority int, schedConfig *structs.SchedulerConfiguration) *BinPackIterator {
-
-	algorithm := schedConfig.EffectiveSchedulerAlgorithm()
-	scoreFn := structs.ScoreFitBinPack
-	if algorithm == structs.SchedulerAlgorithmSpread {
-		scoreFn = structs.ScoreFitSpread
-	}
-
 	iter := &BinPackIterator{
 		ctx:                    ctx,
 		source:                 source,
 		evict:                  evict,
 		priority:               priority,
 		memoryOversubscription: schedConfig != nil && schedConfig.MemoryOversubscriptionEnabled,
-		scoreFit:               scoreFn,
+		schedConfig:            schedConfig,
 	}
-	iter.ctx.Logger().Named("binpack").Trace("NewBinPackIterator created", "algorithm", algorithm)
+	iter.setAlgorithm(schedConfig.EffectiveSchedulerAlgorithm())
+	iter.ctx.Logger().Named("binpack").Trace("NewBinPackIterator created", "algorithm", iter.algorithm)
 	return iter
 }
 
+// setAlgorithm sets the scheduler algorithm used to score the fit of nodes.
+func (iter *BinPackIterator) setAlgorithm(algorithm structs.SchedulerAlgorithm) {
+	iter.algorithm = algorithm
+	iter.headroomPercent = 0
+
+	switch algorithm {
+	case structs.SchedulerAlgorithmSpread:
+		iter.scoreFit = structs.ScoreFitSpread
+	case structs.SchedulerAlgorithmLeastRequestedHeadroom:
+		iter.scoreFit = structs.ScoreFitLeastRequested
+		if iter.schedConfig != nil {
+			iter.headroomPercent = iter.schedConfig.HeadroomPercent
+		}
+	default:
+		iter.scoreFit = structs.ScoreFitBinPack
+	}
+}
+
 func (iter *BinPackIterator) SetJob(job *structs.Job) {
-	iter.priority = job.Priority
+	priority, err := preemptionPriority(iter.ctx.State(), job)
+	if err != nil {
+		iter.ctx.Logger().Named("binpack").Error("failed to look up preemption priority ceiling", "error", err)
+		priority = job.Priority
+	}
+	iter.priority = priority
 	iter.jobId = job.NamespacedID()
+
+	algorithm, err := schedulerAlgorithm(iter.ctx.State(), iter.schedConfig, job)
+	if err != nil {
+		iter.ctx.Logger().Named("binpack").Error("failed to look up namespace scheduler algorithm", "error", err)
+		algorithm = iter.schedConfig.EffectiveSchedulerAlgorithm()
+	}
+	if algorithm != iter.algorithm {
+		iter.setAlgorithm(algorithm)
+		iter.ctx.Logger().Named("binpack").Trace("scheduler algorithm overridden", "algorithm", algorithm)
+	}
+}
+
+// schedulerAlgorithm returns the scheduler algorithm to use for the job. The
+// algorithm of the job takes precedence over the one of its namespace, which
+// takes precedence over the one of the cluster.
+func schedulerAlgorithm(state State, schedConfig *structs.SchedulerConfiguration, job *structs.Job) (structs.SchedulerAlgorithm, error) {
+	if job.SchedulerAlgorithm != "" {
+		return job.SchedulerAlgorithm, nil
+	}
+
+	ns, err := state.NamespaceByName(nil, job.Namespace)
+	if err != nil {
+		return "", err
+	}
+	if ns != nil && ns.SchedulerAlgorithm != "" {
+		return ns.SchedulerAlgorithm, nil
+	}
+	return schedConfig.EffectiveSchedulerAlgorithm(), nil
 }
 
 func (iter *BinPackIterator) SetTaskGroup(taskGroup *structs.TaskGroup) {
@@ -529,6 +577,17 @@ OUTER:
 				continue
 			}
 		}
+
+		// Keep the headroom of the node free. Placements requiring
+		// preemption are not held to the headroom, as the utilization of the
+		// node after preemption isn't known here.
+		if fit && iter.headroomPercent > 0 {
+			if ok, dim := structs.FitsHeadroom(option.Node, util, iter.headroomPercent); !ok {
+				iter.ctx.Metrics().ExhaustedNode(option.Node, dim)
+				continue
+			}
+		}
+
 		if len(allocsToPreempt) > 0 {
 			option.PreemptedAllocs = allocsToPreempt
 		}
@@ -707,6 +766,10 @@ func (iter *NodeAffinityIterator) SetTaskGroup(tg *structs.TaskGroup) {
 			iter.affinities = append(iter.affinities, task.Affinities...)
 		}
 	}
+
+	// Affinities on the allocations of a node are scored by the
+	// AllocAffinityIterator
+	iter.affinities = filterAllocsAffinities(iter.affinities, false)
 }
 
 func (iter *NodeAffinityIterator) Reset() {
@@ -758,6 +821,98 @@ func matchesAffinity(ctx Context, affinity *structs.Affinity, option *structs.No
 	return checkAffinity(ctx, affinity.Operand, lVal, rVal, lOk, rOk)
 }
 
+// AllocAffinityIterator is used to resolve any affinity rules in the job or
+// task group that target the allocations already on a node, such as
+// "${allocs.job_id}", and apply a weighted score to nodes if they match.
+type AllocAffinityIterator struct {
+	ctx           Context
+	source        RankIterator
+	namespace     string
+	jobAffinities []*structs.Affinity
+	affinities    []*structs.Affinity
+}
+
+// NewAllocAffinityIterator is used to create an AllocAffinityIterator that
+// applies a weighted score according to whether the allocations on nodes
+// match any allocation affinities in the job or task group.
+func NewAllocAffinityIterator(ctx Context, source RankIterator) *AllocAffinityIterator {
+	return &AllocAffinityIterator{
+		ctx:    ctx,
+		source: source,
+	}
+}
+
+func (iter *AllocAffinityIterator) SetJob(job *structs.Job) {
+	iter.namespace = job.Namespace
+	iter.jobAffinities = filterAllocsAffinities(job.Affinities, true)
+}
+
+func (iter *AllocAffinityIterator) SetTaskGroup(tg *structs.TaskGroup) {
+	// Merge job, task group and task affinities
+	iter.affinities = append(iter.affinities, iter.jobAffinities...)
+	iter.affinities = append(iter.affinities, filterAllocsAffinities(tg.Affinities, true)...)
+	for _, task := range tg.Tasks {
+		iter.affinities = append(iter.affinities, filterAllocsAffinities(task.Affinities, true)...)
+	}
+}
+
+func (iter *AllocAffinityIterator) Reset() {
+	iter.source.Reset()
+	// This method is called between each task group, so only reset the merged list
+	iter.affinities = nil
+}
+
+func (iter *AllocAffinityIterator) hasAffinities() bool {
+	return len(iter.affinities) > 0
+}
+
+func (iter *AllocAffinityIterator) Next() *RankedNode {
+	option := iter.source.Next()
+	if option == nil {
+		return nil
+	}
+	if !iter.hasAffinities() {
+		iter.ctx.Metrics().ScoreNode(option.Node, "alloc-affinity", 0)
+		return option
+	}
+
+	allocs, err := iter.ctx.ProposedAllocs(option.Node.ID)
+	if err != nil {
+		iter.ctx.Logger().Named("alloc_affinity").Error("failed to get proposed allocations", "error", err)
+		return option
+	}
+	allocs = filterAllocsByNamespace(allocs, iter.namespace)
+
+	sumWeight := 0.0
+	totalAffinityScore := 0.0
+	for _, affinity := range iter.affinities {
+		sumWeight += math.Abs(float64(affinity.Weight))
+
+		lVal, lOk := resolveAllocsTarget(affinity.LTarget, allocs)
+		if checkAffinity(iter.ctx, affinity.Operand, lVal, affinity.RTarget, lOk, true) {
+			totalAffinityScore += float64(affinity.Weight)
+		}
+	}
+	normScore := totalAffinityScore / sumWeight
+	if totalAffinityScore != 0.0 {
+		option.Scores = append(option.Scores, normScore)
+		iter.ctx.Metrics().ScoreNode(option.Node, "alloc-affinity", normScore)
+	}
+	return option
+}
+
+// filterAllocsAffinities returns the affinities that target the allocations
+// on a node if allocs is true, or the node itself otherwise.
+func filterAllocsAffinities(affinities []*structs.Affinity, allocs bool) []*structs.Affinity {
+	var out []*structs.Affinity
+	for _, affinity := range affinities {
+		if structs.IsAllocsTarget(affinity.LTarget) == allocs {
+			out = append(out, affinity)
+		}
+	}
+	return out
+}
+
 // ScoreNormalizationIterator is used to combine scores from various prior
 // iterators and combine them into one final score. The current implementation
 // averages the scores together.
@@ -133,6 +133,108 @@ func TestBinPackIterator_NoExistingAlloc(t *testing.T) {
 	}
 }
 
+// TestBinPackIterator_LeastRequestedHeadroom asserts that nodes left with less
+// free capacity than the headroom are exhausted and that the remaining nodes
+// are scored by how much capacity is left free.
+func TestBinPackIterator_LeastRequestedHeadroom(t *testing.T) {
+	_, ctx := testContext(t)
+	nodes := []*RankedNode{
+		{
+			// Fits, but leaves no headroom
+			Node: &structs.Node{
+				Name: "perfect-fit",
+				NodeResources: &structs.NodeResources{
+					Cpu:    structs.NodeCpuResources{CpuShares: 1024},
+					Memory: structs.NodeMemoryResources{MemoryMB: 1024},
+				},
+			},
+		},
+		{
+			Node: &structs.Node{
+				Name: "medium",
+				NodeResources: &structs.NodeResources{
+					Cpu:    structs.NodeCpuResources{CpuShares: 4096},
+					Memory: structs.NodeMemoryResources{MemoryMB: 4096},
+				},
+			},
+		},
+		{
+			Node: &structs.Node{
+				Name: "large",
+				NodeResources: &structs.NodeResources{
+					Cpu:    structs.NodeCpuResources{CpuShares: 8192},
+					Memory: structs.NodeMemoryResources{MemoryMB: 8192},
+				},
+			},
+		},
+	}
+	static := NewStaticRankIterator(ctx, nodes)
+
+	taskGroup := &structs.TaskGroup{
+		EphemeralDisk: &structs.EphemeralDisk{},
+		Tasks: []*structs.Task{
+			{
+				Name: "web",
+				Resources: &structs.Resources{
+					CPU:      1024,
+					MemoryMB: 1024,
+				},
+			},
+		},
+	}
+	schedConfig := &structs.SchedulerConfiguration{
+		SchedulerAlgorithm: structs.SchedulerAlgorithmLeastRequestedHeadroom,
+		HeadroomPercent:    25,
+	}
+	binp := NewBinPackIterator(ctx, static, false, 0, schedConfig)
+	binp.SetTaskGroup(taskGroup)
+
+	scoreNorm := NewScoreNormalizationIterator(ctx, binp)
+
+	out := collectRanked(scoreNorm)
+	require.Len(t, out, 2)
+	require.Equal(t, nodes[1], out[0])
+	require.Equal(t, nodes[2], out[1])
+	require.InDelta(t, 0.75, out[0].FinalScore, 0.001)
+	require.InDelta(t, 0.875, out[1].FinalScore, 0.001)
+	require.Equal(t, 1, ctx.Metrics().DimensionExhausted["cpu headroom"])
+}
+
+// TestBinPackIterator_SchedulerAlgorithm asserts that the scheduler algorithm
+// of the job takes precedence over the one of its namespace, which takes
+// precedence over the one of the cluster.
+func TestBinPackIterator_SchedulerAlgorithm(t *testing.T) {
+	state, ctx := testContext(t)
+
+	ns := mock.Namespace()
+	ns.SchedulerAlgorithm = structs.SchedulerAlgorithmSpread
+	require.NoError(t, state.UpsertNamespaces(structs.MsgTypeTestSetup, 1000, []*structs.Namespace{ns}))
+
+	schedConfig := &structs.SchedulerConfiguration{
+		SchedulerAlgorithm: structs.SchedulerAlgorithmBinpack,
+		HeadroomPercent:    20,
+	}
+	binp := NewBinPackIterator(ctx, NewStaticRankIterator(ctx, nil), false, 0, schedConfig)
+	require.Equal(t, structs.SchedulerAlgorithmBinpack, binp.algorithm)
+
+	job := mock.Job()
+	binp.SetJob(job)
+	require.Equal(t, structs.SchedulerAlgorithmBinpack, binp.algorithm)
+
+	job = mock.Job()
+	job.Namespace = ns.Name
+	binp.SetJob(job)
+	require.Equal(t, structs.SchedulerAlgorithmSpread, binp.algorithm)
+	require.Zero(t, binp.headroomPercent)
+
+	job = mock.Job()
+	job.Namespace = ns.Name
+	job.SchedulerAlgorithm = structs.SchedulerAlgorithmLeastRequestedHeadroom
+	binp.SetJob(job)
+	require.Equal(t, structs.SchedulerAlgorithmLeastRequestedHeadroom, binp.algorithm)
+	require.Equal(t, 20, binp.headroomPercent)
+}
+
 // TestBinPackIterator_NoExistingAlloc_MixedReserve asserts that node's with
 // reserved resources are scored equivalent to as if they had a lower amount of
 // resources.
@@ -2197,3 +2299,74 @@ func TestNodeAffinityIterator(t *testing.T) {
 	}
 
 }
+
+func TestAllocAffinityIterator(t *testing.T) {
+	_, ctx := testContext(t)
+	nodes := []*RankedNode{
+		{Node: mock.Node()},
+		{Node: mock.Node()},
+		{Node: mock.Node()},
+	}
+
+	// Node 0 runs the database, node 1 runs a noisy batch job and node 2
+	// runs nothing
+	plan := ctx.Plan()
+	plan.NodeAllocation[nodes[0].Node.ID] = []*structs.Allocation{
+		{ID: uuid.Generate(), Namespace: structs.DefaultNamespace, JobID: "db"},
+	}
+	plan.NodeAllocation[nodes[1].Node.ID] = []*structs.Allocation{
+		{ID: uuid.Generate(), Namespace: structs.DefaultNamespace, JobID: "batch"},
+	}
+
+	job := mock.Job()
+	job.Affinities = []*structs.Affinity{
+		{
+			Operand: structs.ConstraintSetContains,
+			LTarget: "${allocs.job_id}",
+			RTarget: "db",
+			Weight:  100,
+		},
+	}
+	tg := job.TaskGroups[0]
+	tg.Affinities = []*structs.Affinity{
+		{
+			Operand: structs.ConstraintSetContainsAny,
+			LTarget: "${allocs.job_id}",
+			RTarget: "batch",
+			Weight:  -50,
+		},
+		{
+			Operand: "=",
+			LTarget: "${node.datacenter}",
+			RTarget: "dc1",
+			Weight:  50,
+		},
+	}
+
+	static := NewStaticRankIterator(ctx, nodes)
+	allocAffinity := NewAllocAffinityIterator(ctx, static)
+	allocAffinity.SetJob(job)
+	allocAffinity.SetTaskGroup(tg)
+
+	scoreNorm := NewScoreNormalizationIterator(ctx, allocAffinity)
+
+	out := collectRanked(scoreNorm)
+	require.Len(t, out, 3)
+
+	// Total weight = 150, node affinities are left to the
+	// NodeAffinityIterator
+	expectedScores := map[string]float64{
+		nodes[0].Node.ID: 100.0 / 150.0,
+		nodes[1].Node.ID: -50.0 / 150.0,
+		nodes[2].Node.ID: 0,
+	}
+	for _, n := range out {
+		require.Equal(t, expectedScores[n.Node.ID], n.FinalScore)
+	}
+
+	// Allocation affinities are left to the AllocAffinityIterator
+	nodeAffinity := NewNodeAffinityIterator(ctx, static)
+	nodeAffinity.SetJob(job)
+	nodeAffinity.SetTaskGroup(tg)
+	require.Len(t, nodeAffinity.affinities, 1)
+}
@@ -0,0 +1,167 @@
+package scheduler
+
+import (
+	"sort"
+
+	log "github.com/hashicorp/go-hclog"
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// RebalanceJob computes the migrations which would improve the placement of
+// the running allocations of a service job. For each allocation the score of
+// its current node is compared with the best node found by the GenericStack,
+// with the allocation itself removed from the cluster. Migrations whose score
+// improvement is at least minImprovement are returned, limited per task group
+// by the max_parallel of its migrate stanza in the same way as node drains.
+//
+// Each migration is staged in the plan so later allocations are scored
+// against the cluster as it would be after the previous migrations.
+func RebalanceJob(logger log.Logger, state State, job *structs.Job, minImprovement float64) ([]*structs.RebalanceMigration, error) {
+	if job == nil || job.Type != structs.JobTypeService || job.Stopped() {
+		return nil, nil
+	}
+
+	// Don't move allocations while a deployment is placing them
+	ws := memdb.NewWatchSet()
+	deployment, err := state.LatestDeploymentByJobID(ws, job.Namespace, job.ID)
+	if err != nil {
+		return nil, err
+	}
+	if deployment != nil && deployment.Active() {
+		return nil, nil
+	}
+
+	allocs, err := state.AllocsByJob(ws, job.Namespace, job.ID, false)
+	if err != nil {
+		return nil, err
+	}
+	nodes, _, _, err := readyNodesInDCs(state, job.Datacenters)
+	if err != nil {
+		return nil, err
+	}
+	if len(nodes) == 0 {
+		return nil, nil
+	}
+	nodesByID := make(map[string]*structs.Node, len(nodes))
+	for _, node := range nodes {
+		nodesByID[node.ID] = node
+	}
+
+	plan := &structs.Plan{
+		EvalID:          uuid.Generate(),
+		Job:             job,
+		NodeUpdate:      make(map[string][]*structs.Allocation),
+		NodeAllocation:  make(map[string][]*structs.Allocation),
+		NodePreemptions: make(map[string][]*structs.Allocation),
+	}
+	ctx := NewEvalContext(nil, state, plan, logger)
+
+	// The best stack visits every node, the current stack only scores the
+	// node an allocation is running on
+	best := NewGenericStack(false, ctx)
+	best.SetNodes(nodes)
+	best.limit.SetLimit(len(nodes))
+	best.SetJob(job)
+	current := NewGenericStack(false, ctx)
+	current.SetJob(job)
+
+	// Group the allocations by task group in a stable order
+	byTG := make(map[string][]*structs.Allocation)
+	for _, alloc := range allocs {
+		if alloc.Job == nil || alloc.Job.Version != job.Version {
+			continue
+		}
+		byTG[alloc.TaskGroup] = append(byTG[alloc.TaskGroup], alloc)
+	}
+
+	var migrations []*structs.RebalanceMigration
+	for _, tg := range job.TaskGroups {
+		tgAllocs := byTG[tg.Name]
+		sort.Slice(tgAllocs, func(i, j int) bool {
+			return tgAllocs[i].CreateIndex < tgAllocs[j].CreateIndex
+		})
+
+		candidates, limit := rebalanceCandidates(tg, tgAllocs)
+		migrated := 0
+		for _, alloc := range candidates {
+			if migrated >= limit {
+				break
+			}
+
+			node, ok := nodesByID[alloc.NodeID]
+			if !ok {
+				continue
+			}
+
+			// Score the allocation as if it were being placed again
+			plan.AppendStoppedAlloc(alloc, allocMigrating, "", "")
+			options := &SelectOptions{AllocName: alloc.Name}
+
+			current.SetNodes([]*structs.Node{node})
+			currentOption := current.Select(tg, options)
+			bestOption := best.Select(tg, options)
+			if currentOption == nil || bestOption == nil ||
+				bestOption.Node.ID == alloc.NodeID ||
+				bestOption.FinalScore-currentOption.FinalScore < minImprovement {
+				plan.PopUpdate(alloc)
+				continue
+			}
+
+			// Stage the migration so the following allocations account
+			// for it
+			staged := alloc.Copy()
+			staged.ID = uuid.Generate()
+			staged.NodeID = bestOption.Node.ID
+			plan.AppendAlloc(staged, job)
+			migrated++
+
+			migrations = append(migrations, &structs.RebalanceMigration{
+				AllocID:       alloc.ID,
+				AllocName:     alloc.Name,
+				Namespace:     alloc.Namespace,
+				JobID:         alloc.JobID,
+				TaskGroup:     alloc.TaskGroup,
+				FromNodeID:    alloc.NodeID,
+				ToNodeID:      bestOption.Node.ID,
+				CurrentScore:  currentOption.FinalScore,
+				ExpectedScore: bestOption.FinalScore,
+			})
+		}
+	}
+
+	return migrations, nil
+}
+
+// rebalanceCandidates returns the healthy running allocations of a task
+// group which may be migrated, along with how many of them may be migrated so
+// that no more than the migrate max_parallel allocations are unhealthy at
+// once.
+func rebalanceCandidates(tg *structs.TaskGroup, allocs []*structs.Allocation) ([]*structs.Allocation, int) {
+	migrate := tg.Migrate
+	if migrate == nil {
+		migrate = structs.DefaultMigrateStrategy()
+	}
+
+	healthy := 0
+	var candidates []*structs.Allocation
+	for _, alloc := range allocs {
+		if alloc.TerminalStatus() {
+			continue
+		}
+		if alloc.DesiredTransition.ShouldMigrate() {
+			// Already being migrated, so it will soon be unhealthy
+			continue
+		}
+		if alloc.DeploymentStatus.HasHealth() {
+			healthy++
+		}
+		if alloc.ClientStatus == structs.AllocClientStatusRunning &&
+			alloc.DeploymentStatus.IsHealthy() {
+			candidates = append(candidates, alloc)
+		}
+	}
+
+	return candidates, healthy - (tg.Count - migrate.MaxParallel)
+}
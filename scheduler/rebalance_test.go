@@ -0,0 +1,144 @@
+package scheduler
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/pointer"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+// rebalanceTestCluster creates one node of the class preferred by the returned
+// job and three nodes of another class, with one healthy allocation of the
+// job on each of the latter.
+func rebalanceTestCluster(t *testing.T, h *Harness) (*structs.Job, *structs.Node, []*structs.Allocation) {
+	fast := mock.Node()
+	fast.NodeClass = "fast"
+	fast.ComputeClass()
+	require.NoError(t, h.State.UpsertNode(structs.MsgTypeTestSetup, h.NextIndex(), fast))
+
+	job := mock.Job()
+	tg := job.TaskGroups[0]
+	tg.Count = 3
+	tg.Networks = nil
+	tg.Tasks[0].Services = nil
+	tg.Affinities = []*structs.Affinity{{
+		LTarget: "${node.class}",
+		RTarget: "fast",
+		Operand: "=",
+		Weight:  100,
+	}}
+	require.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), job))
+
+	var allocs []*structs.Allocation
+	for i := 0; i < 3; i++ {
+		node := mock.Node()
+		require.NoError(t, h.State.UpsertNode(structs.MsgTypeTestSetup, h.NextIndex(), node))
+
+		alloc := mock.Alloc()
+		alloc.Job = job
+		alloc.JobID = job.ID
+		alloc.NodeID = node.ID
+		alloc.Name = fmt.Sprintf("my-job.web[%d]", i)
+		alloc.AllocatedResources.Tasks["web"].Networks = nil
+		alloc.AllocatedResources.Shared.Networks = nil
+		alloc.ClientStatus = structs.AllocClientStatusRunning
+		alloc.DeploymentStatus = &structs.AllocDeploymentStatus{Healthy: pointer.Of(true)}
+		allocs = append(allocs, alloc)
+	}
+	require.NoError(t, h.State.UpsertAllocs(structs.MsgTypeTestSetup, h.NextIndex(), allocs))
+
+	return job, fast, allocs
+}
+
+func TestRebalanceJob(t *testing.T) {
+	ci.Parallel(t)
+
+	h := NewHarness(t)
+	job, fast, _ := rebalanceTestCluster(t, h)
+
+	migrations, err := RebalanceJob(testlog.HCLogger(t), h.State, job, structs.DefaultRebalanceMinScoreImprovement)
+	require.NoError(t, err)
+
+	// The default max_parallel of 1 only allows one migration
+	require.Len(t, migrations, 1)
+	m := migrations[0]
+	require.Equal(t, fast.ID, m.ToNodeID)
+	require.GreaterOrEqual(t, m.ScoreImprovement(), structs.DefaultRebalanceMinScoreImprovement)
+
+	// Nothing is written to the state
+	out, err := h.State.AllocByID(nil, m.AllocID)
+	require.NoError(t, err)
+	require.Equal(t, out.NodeID, m.FromNodeID)
+	require.False(t, out.DesiredTransition.ShouldMigrate())
+}
+
+func TestRebalanceJob_MaxParallel(t *testing.T) {
+	ci.Parallel(t)
+
+	h := NewHarness(t)
+	job, _, _ := rebalanceTestCluster(t, h)
+	job.TaskGroups[0].Migrate.MaxParallel = 3
+
+	migrations, err := RebalanceJob(testlog.HCLogger(t), h.State, job, 0.01)
+	require.NoError(t, err)
+	require.NotEmpty(t, migrations)
+	require.LessOrEqual(t, len(migrations), 3)
+}
+
+func TestRebalanceJob_Skip(t *testing.T) {
+	ci.Parallel(t)
+
+	// No migration improves the score enough
+	h := NewHarness(t)
+	job, _, _ := rebalanceTestCluster(t, h)
+	migrations, err := RebalanceJob(testlog.HCLogger(t), h.State, job, 1)
+	require.NoError(t, err)
+	require.Empty(t, migrations)
+
+	// Allocations of jobs with an active deployment are left alone
+	d := mock.Deployment()
+	d.JobID = job.ID
+	d.Namespace = job.Namespace
+	require.NoError(t, h.State.UpsertDeployment(h.NextIndex(), d))
+	migrations, err = RebalanceJob(testlog.HCLogger(t), h.State, job, structs.DefaultRebalanceMinScoreImprovement)
+	require.NoError(t, err)
+	require.Empty(t, migrations)
+
+	// Batch jobs are not rebalanced
+	batch := mock.BatchJob()
+	migrations, err = RebalanceJob(testlog.HCLogger(t), h.State, batch, 0)
+	require.NoError(t, err)
+	require.Empty(t, migrations)
+}
+
+func TestRebalanceCandidates(t *testing.T) {
+	ci.Parallel(t)
+
+	tg := &structs.TaskGroup{
+		Count:   4,
+		Migrate: &structs.MigrateStrategy{MaxParallel: 2},
+	}
+
+	healthy := func() *structs.Allocation {
+		a := mock.Alloc()
+		a.ClientStatus = structs.AllocClientStatusRunning
+		a.DeploymentStatus = &structs.AllocDeploymentStatus{Healthy: pointer.Of(true)}
+		return a
+	}
+	migrating := healthy()
+	migrating.DesiredTransition.Migrate = pointer.Of(true)
+	unhealthy := healthy()
+	unhealthy.DeploymentStatus.Healthy = pointer.Of(false)
+
+	// Allocations with a health status count towards the healthy total, as
+	// for drains, but only healthy ones are candidates
+	allocs := []*structs.Allocation{healthy(), healthy(), healthy(), migrating, unhealthy}
+	candidates, limit := rebalanceCandidates(tg, allocs)
+	require.Len(t, candidates, 3)
+	require.Equal(t, 2, limit)
+}
@@ -103,6 +103,9 @@ type State interface {
 	// SchedulerConfig returns config options for the scheduler
 	SchedulerConfig() (uint64, *structs.SchedulerConfiguration, error)
 
+	// NamespaceByName returns the namespace with the given name
+	NamespaceByName(ws memdb.WatchSet, name string) (*structs.Namespace, error)
+
 	// CSIVolumeByID fetch CSI volumes, containing controller jobs
 	CSIVolumeByID(memdb.WatchSet, string, string) (*structs.CSIVolume, error)
 
@@ -146,6 +146,11 @@ func (s *SystemScheduler) process() (bool, error) {
 
 	// Create an evaluation context
 	s.ctx = NewEvalContext(s.eventsCh, s.state, s.plan, s.logger)
+	// Every node is scored when explaining the scores of a plan, while the
+	// placements of an evaluation are scored as usual
+	if s.eval.ExplainScores {
+		s.ctx.ExplainScores(s.eval.AnnotatePlan)
+	}
 
 	// Construct the placement stack
 	s.stack = NewSystemStack(s.sysbatch, s.ctx)
@@ -265,6 +270,13 @@ func (s *SystemScheduler) computeJobAllocs() error {
 		s.plan.Annotations = &structs.PlanAnnotations{
 			DesiredTGUpdates: desiredUpdates(diff, inplaceUpdates, destructiveUpdates),
 		}
+		if s.job != nil {
+			priority, err := preemptionPriority(s.state, s.job)
+			if err != nil {
+				return err
+			}
+			s.plan.Annotations.PreemptionPriority = priority
+		}
 	}
 
 	// Check if a rolling upgrade strategy is being used
@@ -0,0 +1,303 @@
+package scheduler
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	log "github.com/hashicorp/go-hclog"
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/helper/pointer"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// Simulation runs the schedulers against a copy of the cluster state, such as
+// one restored from a snapshot, to find out how hypothetical node drains and
+// job registrations would be handled. Plans are applied to the state through
+// a Harness and never leave it.
+type Simulation struct {
+	harness *Harness
+
+	// evals are the evaluations to process, at most one per job
+	evals     []*structs.Evaluation
+	evalsJobs map[structs.NamespacedID]struct{}
+}
+
+// SimulationResult is the outcome of a simulation.
+type SimulationResult struct {
+	// Evals holds the outcome of each evaluation in the order processed
+	Evals []*SimulationEval
+
+	// Nodes holds the utilization of each node before and after the
+	// simulation, sorted by node ID
+	Nodes []*SimulationNode
+}
+
+// SimulationEval is the outcome of processing a single evaluation of a
+// simulation.
+type SimulationEval struct {
+	EvalID      string
+	Namespace   string
+	JobID       string
+	JobType     string
+	TriggeredBy string
+
+	// Placed and Stopped are the number of allocations placed and stopped
+	// by task group
+	Placed  map[string]int
+	Stopped map[string]int
+
+	// Preempted is the number of allocations of other jobs preempted
+	Preempted int
+
+	// Failed is the number of allocations which could not be placed by task
+	// group, with FailedTGAllocs holding the metrics explaining why
+	Failed         map[string]int
+	FailedTGAllocs map[string]*structs.AllocMetric
+
+	// Blocked is set when a blocked evaluation was created for the
+	// allocations which could not be placed
+	Blocked bool
+}
+
+// SimulationNode is the utilization of a node before and after a simulation.
+type SimulationNode struct {
+	NodeID   string
+	NodeName string
+	Draining bool
+
+	CPUCapacity      int64
+	MemoryMBCapacity int64
+
+	CPUBefore      int64
+	MemoryMBBefore int64
+	CPUAfter       int64
+	MemoryMBAfter  int64
+}
+
+// NewSimulation returns a simulation running against the given state, which
+// is modified by the simulation.
+func NewSimulation(logger log.Logger, state *state.StateStore) (*Simulation, error) {
+	harness, err := NewSimulationHarness(logger, state)
+	if err != nil {
+		return nil, err
+	}
+	return &Simulation{
+		harness:   harness,
+		evalsJobs: make(map[structs.NamespacedID]struct{}),
+	}, nil
+}
+
+// DrainNode marks the node as draining and all of its allocations for
+// migration, queueing an evaluation for each job with allocations on it. The
+// drain doesn't honor the migrate max_parallel of the jobs, so all the
+// allocations are migrated at once.
+func (s *Simulation) DrainNode(nodeID string) error {
+	ws := memdb.NewWatchSet()
+	node, err := s.harness.State.NodeByID(ws, nodeID)
+	if err != nil {
+		return err
+	}
+	if node == nil {
+		return fmt.Errorf("node %q not found", nodeID)
+	}
+
+	now := time.Now()
+	drain := &structs.DrainStrategy{StartedAt: now}
+	if err := s.harness.State.UpdateNodeDrain(structs.MsgTypeTestSetup, s.harness.NextIndex(),
+		node.ID, drain, false, now.Unix(), nil, nil, ""); err != nil {
+		return err
+	}
+
+	allocs, err := s.harness.State.AllocsByNode(ws, node.ID)
+	if err != nil {
+		return err
+	}
+	transitions := make(map[string]*structs.DesiredTransition)
+	for _, alloc := range allocs {
+		if alloc.TerminalStatus() || alloc.Job == nil {
+			continue
+		}
+		transitions[alloc.ID] = &structs.DesiredTransition{Migrate: pointer.Of(true)}
+		s.queueEval(alloc.Job, structs.EvalTriggerNodeDrain, node.ID)
+	}
+	if len(transitions) == 0 {
+		return nil
+	}
+
+	return s.harness.State.UpdateAllocsDesiredTransitions(structs.MsgTypeTestSetup,
+		s.harness.NextIndex(), transitions, nil)
+}
+
+// RegisterJob registers the job, queueing an evaluation for it. The job must
+// already be canonicalized and valid.
+func (s *Simulation) RegisterJob(job *structs.Job) error {
+	if err := s.harness.State.UpsertJob(structs.MsgTypeTestSetup, s.harness.NextIndex(), job); err != nil {
+		return err
+	}
+	s.queueEval(job, structs.EvalTriggerJobRegister, "")
+	return nil
+}
+
+// queueEval queues an evaluation for the job unless one is already queued.
+func (s *Simulation) queueEval(job *structs.Job, triggeredBy, nodeID string) {
+	id := structs.NewNamespacedID(job.ID, job.Namespace)
+	if _, ok := s.evalsJobs[id]; ok {
+		return
+	}
+	s.evalsJobs[id] = struct{}{}
+
+	s.evals = append(s.evals, &structs.Evaluation{
+		ID:          uuid.Generate(),
+		Namespace:   job.Namespace,
+		Priority:    job.Priority,
+		Type:        job.Type,
+		TriggeredBy: triggeredBy,
+		JobID:       job.ID,
+		NodeID:      nodeID,
+		Status:      structs.EvalStatusPending,
+	})
+}
+
+// Run processes the queued evaluations in order with the schedulers and
+// returns their outcome along with the resulting utilization of the nodes.
+func (s *Simulation) Run() (*SimulationResult, error) {
+	before, err := s.utilization()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &SimulationResult{}
+	for _, eval := range s.evals {
+		factory, ok := BuiltinSchedulers[eval.Type]
+		if !ok {
+			return nil, fmt.Errorf("unknown scheduler for job type %q", eval.Type)
+		}
+		if err := s.harness.State.UpsertEvals(structs.MsgTypeTestSetup,
+			s.harness.NextIndex(), []*structs.Evaluation{eval}); err != nil {
+			return nil, err
+		}
+
+		plans, updates, creates := len(s.harness.Plans), len(s.harness.Evals), len(s.harness.CreateEvals)
+		if err := s.harness.Process(factory, eval); err != nil {
+			return nil, fmt.Errorf("failed to process evaluation for job %q: %v", eval.JobID, err)
+		}
+
+		result.Evals = append(result.Evals, simulationEval(eval,
+			s.harness.Plans[plans:], s.harness.Evals[updates:], s.harness.CreateEvals[creates:]))
+	}
+	s.evals = nil
+	s.evalsJobs = make(map[structs.NamespacedID]struct{})
+
+	after, err := s.utilization()
+	if err != nil {
+		return nil, err
+	}
+	for _, node := range after {
+		if b, ok := before[node.NodeID]; ok {
+			node.CPUBefore = b.CPUAfter
+			node.MemoryMBBefore = b.MemoryMBAfter
+		}
+		result.Nodes = append(result.Nodes, node)
+	}
+	sort.Slice(result.Nodes, func(i, j int) bool {
+		return result.Nodes[i].NodeID < result.Nodes[j].NodeID
+	})
+
+	return result, nil
+}
+
+// simulationEval summarizes the plans and evaluations resulting from
+// processing an evaluation.
+func simulationEval(eval *structs.Evaluation, plans []*structs.Plan,
+	updates, creates []*structs.Evaluation) *SimulationEval {
+
+	out := &SimulationEval{
+		EvalID:      eval.ID,
+		Namespace:   eval.Namespace,
+		JobID:       eval.JobID,
+		JobType:     eval.Type,
+		TriggeredBy: eval.TriggeredBy,
+		Placed:      make(map[string]int),
+		Stopped:     make(map[string]int),
+		Failed:      make(map[string]int),
+	}
+
+	for _, plan := range plans {
+		for _, allocs := range plan.NodeAllocation {
+			for _, alloc := range allocs {
+				out.Placed[alloc.TaskGroup]++
+			}
+		}
+		for _, allocs := range plan.NodeUpdate {
+			for _, alloc := range allocs {
+				out.Stopped[alloc.TaskGroup]++
+			}
+		}
+		for _, allocs := range plan.NodePreemptions {
+			out.Preempted += len(allocs)
+		}
+	}
+
+	// The last update of the evaluation holds the placement failures
+	if n := len(updates); n > 0 {
+		out.FailedTGAllocs = updates[n-1].FailedTGAllocs
+		for tg, metric := range out.FailedTGAllocs {
+			out.Failed[tg] = metric.CoalescedFailures + 1
+		}
+	}
+	for _, create := range creates {
+		if create.Status == structs.EvalStatusBlocked {
+			out.Blocked = true
+		}
+	}
+
+	return out
+}
+
+// utilization returns the resources allocated on each node, indexed by node
+// ID, as the After fields of the result.
+func (s *Simulation) utilization() (map[string]*SimulationNode, error) {
+	ws := memdb.NewWatchSet()
+	iter, err := s.harness.State.Nodes(ws)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make(map[string]*SimulationNode)
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		node := raw.(*structs.Node)
+		if node.TerminalStatus() {
+			continue
+		}
+
+		capacity := node.ComparableResources()
+		capacity.Subtract(node.ComparableReservedResources())
+		out := &SimulationNode{
+			NodeID:           node.ID,
+			NodeName:         node.Name,
+			Draining:         node.DrainStrategy != nil,
+			CPUCapacity:      capacity.Flattened.Cpu.CpuShares,
+			MemoryMBCapacity: capacity.Flattened.Memory.MemoryMB,
+		}
+
+		allocs, err := s.harness.State.AllocsByNode(ws, node.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, alloc := range allocs {
+			if alloc.TerminalStatus() {
+				continue
+			}
+			used := alloc.ComparableResources()
+			out.CPUAfter += used.Flattened.Cpu.CpuShares
+			out.MemoryMBAfter += used.Flattened.Memory.MemoryMB
+		}
+
+		nodes[node.ID] = out
+	}
+
+	return nodes, nil
+}
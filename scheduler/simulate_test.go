@@ -0,0 +1,95 @@
+package scheduler
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimulation(t *testing.T) {
+	ci.Parallel(t)
+
+	store := state.TestStateStore(t)
+	for i := 0; i < 2; i++ {
+		require.NoError(t, store.UpsertNode(structs.MsgTypeTestSetup, uint64(100+i), mock.Node()))
+	}
+
+	sim, err := NewSimulation(testlog.HCLogger(t), store)
+	require.NoError(t, err)
+
+	// Register a job which fits
+	job := mock.Job()
+	job.TaskGroups[0].Count = 2
+	job.Canonicalize()
+	require.NoError(t, sim.RegisterJob(job))
+
+	result, err := sim.Run()
+	require.NoError(t, err)
+	require.Len(t, result.Evals, 1)
+	require.Equal(t, structs.EvalTriggerJobRegister, result.Evals[0].TriggeredBy)
+	require.Equal(t, 2, result.Evals[0].Placed["web"])
+	require.Empty(t, result.Evals[0].Failed)
+	require.False(t, result.Evals[0].Blocked)
+
+	require.Len(t, result.Nodes, 2)
+	var used int64
+	var drained *SimulationNode
+	for _, node := range result.Nodes {
+		require.Zero(t, node.CPUBefore)
+		used += node.CPUAfter
+		if node.CPUAfter > 0 && drained == nil {
+			drained = node
+		}
+	}
+	require.Equal(t, int64(1000), used)
+
+	// Drain a node running allocations of the job
+	require.NoError(t, sim.DrainNode(drained.NodeID))
+	result, err = sim.Run()
+	require.NoError(t, err)
+	require.Len(t, result.Evals, 1)
+	require.Equal(t, structs.EvalTriggerNodeDrain, result.Evals[0].TriggeredBy)
+	migrated := int(drained.CPUAfter / 500)
+	require.Equal(t, migrated, result.Evals[0].Placed["web"])
+	require.Equal(t, migrated, result.Evals[0].Stopped["web"])
+
+	for _, node := range result.Nodes {
+		if node.NodeID == drained.NodeID {
+			require.True(t, node.Draining)
+			require.Equal(t, drained.CPUAfter, node.CPUBefore)
+			require.Zero(t, node.CPUAfter)
+		} else {
+			require.Equal(t, int64(1000), node.CPUAfter)
+		}
+	}
+
+	// Register a job which doesn't fit
+	job = mock.Job()
+	job.TaskGroups[0].Tasks[0].Resources.CPU = 100000
+	job.Canonicalize()
+	require.NoError(t, sim.RegisterJob(job))
+
+	result, err = sim.Run()
+	require.NoError(t, err)
+	require.Len(t, result.Evals, 1)
+	require.Empty(t, result.Evals[0].Placed)
+	require.Equal(t, 10, result.Evals[0].Failed["web"])
+	require.Contains(t, result.Evals[0].FailedTGAllocs, "web")
+	require.True(t, result.Evals[0].Blocked)
+}
+
+func TestSimulation_DrainNode_Unknown(t *testing.T) {
+	ci.Parallel(t)
+
+	sim, err := NewSimulation(testlog.HCLogger(t), state.TestStateStore(t))
+	require.NoError(t, err)
+	nodeID := uuid.Generate()
+	require.EqualError(t, sim.DrainNode(nodeID), fmt.Sprintf("node %q not found", nodeID))
+}
@@ -1,6 +1,8 @@
 package scheduler
 
 import (
+	"fmt"
+
 	"github.com/hashicorp/nomad/nomad/structs"
 )
 
@@ -8,6 +10,10 @@ const (
 	// implicitTarget is used to represent any remaining attribute values
 	// when target percentages don't add up to 100
 	implicitTarget = "*"
+
+	// spreadMaxSkewDimension is the exhausted dimension reported in the
+	// AllocMetric when a placement would exceed the max_skew of a spread
+	spreadMaxSkewDimension = "spread max_skew on %s"
 )
 
 // SpreadIterator is used to spread allocations across a specified attribute
@@ -270,3 +276,170 @@ func (iter *SpreadIterator) computeSpreadInfo(tg *structs.TaskGroup) {
 	}
 	iter.tgSpreadInfo[tg.Name] = spreadInfos
 }
+
+// SpreadMaxSkewIterator is a FeasibleIterator which filters out nodes where a
+// placement would exceed the max_skew of a spread. The skew is the difference
+// between the number of allocations of the task group on the node's value of
+// the spread attribute and on the least used value across the nodes its
+// source finds feasible.
+type SpreadMaxSkewIterator struct {
+	ctx    Context
+	source FeasibleIterator
+	job    *structs.Job
+	tg     *structs.TaskGroup
+
+	// jobSpreads is a slice of spreads with a max_skew stored at the job
+	// level which apply to all task groups
+	jobSpreads []*structs.Spread
+
+	// domains is a map from spread attribute to the set of values of that
+	// attribute across the feasible nodes. It's built from the source once
+	// per placement, as the feasibility of nodes may change with each one.
+	domains map[string]map[string]struct{}
+
+	// groupSkewSets is a memoized map from task group to the max_skew
+	// property sets
+	groupSkewSets map[string][]*skewSet
+	hasSkew       bool
+}
+
+// skewSet tracks the use of a spread attribute along with its max_skew
+type skewSet struct {
+	pset    *propertySet
+	maxSkew uint64
+}
+
+// NewSpreadMaxSkewIterator creates a SpreadMaxSkewIterator from a source
+func NewSpreadMaxSkewIterator(ctx Context, source FeasibleIterator) *SpreadMaxSkewIterator {
+	return &SpreadMaxSkewIterator{
+		ctx:           ctx,
+		source:        source,
+		groupSkewSets: make(map[string][]*skewSet),
+	}
+}
+
+func (iter *SpreadMaxSkewIterator) SetJob(job *structs.Job) {
+	iter.job = job
+	iter.jobSpreads = nil
+	for _, spread := range job.Spreads {
+		if spread.MaxSkew > 0 {
+			iter.jobSpreads = append(iter.jobSpreads, spread)
+		}
+	}
+
+	// reset the property sets so that older job versions used to compute
+	// stops don't leak into the new job version
+	iter.groupSkewSets = make(map[string][]*skewSet)
+}
+
+func (iter *SpreadMaxSkewIterator) SetTaskGroup(tg *structs.TaskGroup) {
+	iter.tg = tg
+	iter.domains = nil
+
+	if _, ok := iter.groupSkewSets[tg.Name]; !ok {
+		// Combine the job level spreads with the task group spreads
+		spreads := make([]*structs.Spread, 0, len(iter.jobSpreads)+len(tg.Spreads))
+		spreads = append(spreads, iter.jobSpreads...)
+		spreads = append(spreads, tg.Spreads...)
+
+		sets := []*skewSet{}
+		for _, spread := range spreads {
+			if spread.MaxSkew <= 0 {
+				continue
+			}
+			pset := NewPropertySet(iter.ctx, iter.job)
+			pset.SetTargetAttribute(spread.Attribute, tg.Name)
+			sets = append(sets, &skewSet{pset: pset, maxSkew: uint64(spread.MaxSkew)})
+		}
+		iter.groupSkewSets[tg.Name] = sets
+	}
+
+	iter.hasSkew = len(iter.groupSkewSets[tg.Name]) != 0
+}
+
+func (iter *SpreadMaxSkewIterator) Next() *structs.Node {
+	// The source must be configured for the task group before the domains
+	// can be built, so they're built when the first node is requested
+	if iter.hasSkew && iter.domains == nil {
+		iter.buildDomains()
+	}
+
+	for {
+		option := iter.source.Next()
+
+		// Hot path if there is nothing to check
+		if option == nil || !iter.hasSkew {
+			return option
+		}
+
+		if iter.satisfiesMaxSkew(option) {
+			return option
+		}
+	}
+}
+
+// satisfiesMaxSkew returns whether placing on the option keeps every spread
+// within its max_skew. If not it is marked as exhausted.
+func (iter *SpreadMaxSkewIterator) satisfiesMaxSkew(option *structs.Node) bool {
+	for _, set := range iter.groupSkewSets[iter.tg.Name] {
+		_, errorMsg, usedCount := set.pset.UsedCount(option, iter.tg.Name)
+		if errorMsg != "" {
+			iter.ctx.Metrics().FilterNode(option, errorMsg)
+			return false
+		}
+
+		// Find the least used value, counting values with no allocations
+		minCount := usedCount
+		combinedUse := set.pset.GetCombinedUseMap()
+		for value := range iter.domains[set.pset.targetAttribute] {
+			if count := combinedUse[value]; count < minCount {
+				minCount = count
+			}
+		}
+
+		// Add one to include placement on this node
+		if usedCount+1-minCount > set.maxSkew {
+			iter.ctx.Metrics().ExhaustedNode(option,
+				fmt.Sprintf(spreadMaxSkewDimension, set.pset.targetAttribute))
+			return false
+		}
+	}
+
+	return true
+}
+
+// buildDomains collects the values of the spread attributes across the nodes
+// the source finds feasible for the task group. Values only found on
+// infeasible nodes can never be used, so they must not hold the least used
+// count at zero. The source is drained and reset, and the metrics it records
+// are discarded as they'll be recorded again when it's iterated for the
+// placement.
+func (iter *SpreadMaxSkewIterator) buildDomains() {
+	sets := iter.groupSkewSets[iter.tg.Name]
+	iter.domains = make(map[string]map[string]struct{}, len(sets))
+	for _, set := range sets {
+		iter.domains[set.pset.targetAttribute] = make(map[string]struct{})
+	}
+
+	metrics := iter.ctx.Metrics()
+	saved := metrics.Copy()
+	for node := iter.source.Next(); node != nil; node = iter.source.Next() {
+		for attribute, values := range iter.domains {
+			if value, ok := getProperty(node, attribute); ok {
+				values[value] = struct{}{}
+			}
+		}
+	}
+	iter.source.Reset()
+	*metrics = *saved
+}
+
+func (iter *SpreadMaxSkewIterator) Reset() {
+	iter.source.Reset()
+
+	for _, sets := range iter.groupSkewSets {
+		for _, set := range sets {
+			set.pset.PopulateProposed()
+		}
+	}
+}
@@ -918,3 +918,138 @@ func TestSpreadPanicDowngrade(t *testing.T) {
 	require.NoError(t, processErr, "failed to process eval")
 	require.Len(t, h.Plans, 1)
 }
+
+func TestSpreadMaxSkewIterator(t *testing.T) {
+	ci.Parallel(t)
+
+	state, ctx := testContext(t)
+	dcs := []string{"dc1", "dc1", "dc2", "dc3"}
+	var nodes []*structs.Node
+	for i, dc := range dcs {
+		node := mock.Node()
+		node.Datacenter = dc
+		require.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, uint64(100+i), node))
+		nodes = append(nodes, node)
+	}
+
+	job := mock.Job()
+	tg := job.TaskGroups[0]
+	tg.Spreads = []*structs.Spread{{
+		Weight:    50,
+		Attribute: "${node.datacenter}",
+		MaxSkew:   1,
+	}}
+
+	// Two allocs in dc1 and one in dc2, none in dc3
+	var upserting []*structs.Allocation
+	for _, node := range []*structs.Node{nodes[0], nodes[1], nodes[2]} {
+		upserting = append(upserting, &structs.Allocation{
+			Namespace: structs.DefaultNamespace,
+			TaskGroup: tg.Name,
+			JobID:     job.ID,
+			Job:       job,
+			ID:        uuid.Generate(),
+			EvalID:    uuid.Generate(),
+			NodeID:    node.ID,
+		})
+	}
+	require.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 1000, upserting))
+
+	static := NewStaticIterator(ctx, nodes)
+	skewIter := NewSpreadMaxSkewIterator(ctx, static)
+	skewIter.SetJob(job)
+	skewIter.SetTaskGroup(tg)
+
+	// Only dc3 is within the max skew
+	out := collectFeasible(skewIter)
+	require.Len(t, out, 1)
+	require.Equal(t, nodes[3], out[0])
+
+	metrics := ctx.Metrics()
+	require.Equal(t, 3, metrics.NodesExhausted)
+	require.Equal(t, 3, metrics.DimensionExhausted["spread max_skew on ${node.datacenter}"])
+
+	// Once dc3 is used dc2 is within the max skew too
+	ctx.Plan().NodeAllocation[nodes[3].ID] = []*structs.Allocation{{
+		Namespace: structs.DefaultNamespace,
+		TaskGroup: tg.Name,
+		JobID:     job.ID,
+		Job:       job,
+		ID:        uuid.Generate(),
+		NodeID:    nodes[3].ID,
+	}}
+	skewIter.Reset()
+
+	out = collectFeasible(skewIter)
+	require.ElementsMatch(t, []*structs.Node{nodes[2], nodes[3]}, out)
+
+	// Spreads without a max skew are not enforced
+	tg.Spreads[0].MaxSkew = 0
+	skewIter.SetJob(job)
+	skewIter.SetTaskGroup(tg)
+	skewIter.Reset()
+	require.Len(t, collectFeasible(skewIter), 4)
+}
+
+func TestSpreadMaxSkewIterator_InfeasibleDomain(t *testing.T) {
+	ci.Parallel(t)
+
+	state, ctx := testContext(t)
+	dcs := []string{"dc1", "dc1", "dc2", "dc3"}
+	var nodes []*structs.Node
+	for i, dc := range dcs {
+		node := mock.Node()
+		node.Datacenter = dc
+		require.NoError(t, node.ComputeClass())
+		require.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, uint64(100+i), node))
+		nodes = append(nodes, node)
+	}
+
+	// The task group can't be placed in dc3
+	job := mock.Job()
+	tg := job.TaskGroups[0]
+	tg.Constraints = append(tg.Constraints, &structs.Constraint{
+		LTarget: "${node.datacenter}",
+		RTarget: "dc3",
+		Operand: "!=",
+	})
+	tg.Spreads = []*structs.Spread{{
+		Weight:    50,
+		Attribute: "${node.datacenter}",
+		MaxSkew:   1,
+	}}
+
+	// One alloc each in dc1 and dc2
+	var upserting []*structs.Allocation
+	for _, node := range []*structs.Node{nodes[0], nodes[2]} {
+		upserting = append(upserting, &structs.Allocation{
+			Namespace: structs.DefaultNamespace,
+			TaskGroup: tg.Name,
+			JobID:     job.ID,
+			Job:       job,
+			ID:        uuid.Generate(),
+			EvalID:    uuid.Generate(),
+			NodeID:    node.ID,
+		})
+	}
+	require.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 1000, upserting))
+
+	static := NewStaticIterator(ctx, nodes)
+	constraints := NewConstraintChecker(ctx, tg.Constraints)
+	wrapper := NewFeasibilityWrapper(ctx, static, nil, []FeasibilityChecker{constraints}, nil)
+	wrapper.SetTaskGroup(tg.Name)
+	skewIter := NewSpreadMaxSkewIterator(ctx, wrapper)
+	skewIter.SetJob(job)
+	skewIter.SetTaskGroup(tg)
+
+	// dc3 has no allocs but is infeasible, so it doesn't pin the least used
+	// count at zero and dc1 and dc2 are within the max skew
+	out := collectFeasible(skewIter)
+	require.ElementsMatch(t, nodes[:3], out)
+
+	// Building the domains doesn't record metrics against the placement
+	metrics := ctx.Metrics()
+	require.Equal(t, 4, metrics.NodesEvaluated)
+	require.Equal(t, 1, metrics.NodesFiltered)
+	require.Zero(t, metrics.NodesExhausted)
+}
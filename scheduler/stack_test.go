@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/stretchr/testify/require"
@@ -364,6 +365,44 @@ func TestServiceStack_Select_ConstraintFilter(t *testing.T) {
 	}
 }
 
+func TestServiceStack_Select_AllocConstraintFilter(t *testing.T) {
+	ci.Parallel(t)
+
+	_, ctx := testContext(t)
+	nodes := []*structs.Node{
+		mock.Node(),
+		mock.Node(),
+	}
+
+	busy, idle := nodes[0], nodes[1]
+
+	// Both nodes share a computed class, so the allocation constraint must
+	// not be cached by class
+	ctx.Plan().NodeAllocation[busy.ID] = []*structs.Allocation{{
+		ID:        uuid.Generate(),
+		Namespace: structs.DefaultNamespace,
+		JobID:     "batch",
+	}}
+
+	stack := NewGenericStack(false, ctx)
+	stack.SetNodes(nodes)
+
+	job := mock.Job()
+	job.Constraints = append(job.Constraints, &structs.Constraint{
+		LTarget: structs.ConstraintAllocsJobID,
+		RTarget: "batch",
+		Operand: structs.ConstraintSetContainsNone,
+	})
+	stack.SetJob(job)
+	node := stack.Select(job.TaskGroups[0], &SelectOptions{})
+	require.NotNil(t, node, "missing node %#v", ctx.Metrics())
+	require.Equal(t, idle, node.Node)
+
+	met := ctx.Metrics()
+	require.Equal(t, 1, met.NodesFiltered)
+	require.Equal(t, 1, met.ConstraintFiltered["${allocs.job_id} set_contains_none batch"])
+}
+
 func TestServiceStack_Select_BinPack_Overflow(t *testing.T) {
 	ci.Parallel(t)
 
@@ -8,6 +8,7 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	log "github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/go-memdb"
 	"github.com/hashicorp/go-version"
 	"github.com/hashicorp/nomad/helper/testlog"
@@ -46,8 +47,9 @@ func (r *RejectPlan) ReblockEval(*structs.Evaluation) error {
 // store copy and provides the planner interface. It can be extended for various
 // testing uses or for invoking the scheduler without side effects.
 type Harness struct {
-	t     testing.TB
-	State *state.StateStore
+	t      testing.TB
+	logger log.Logger
+	State  *state.StateStore
 
 	Planner  Planner
 	planLock sync.Mutex
@@ -86,6 +88,22 @@ func NewHarnessWithState(t testing.TB, state *state.StateStore) *Harness {
 	}
 }
 
+// NewSimulationHarness creates a new harness with the given state for
+// invoking the schedulers outside of tests. Its indexes follow the latest
+// index of the state.
+func NewSimulationHarness(logger log.Logger, state *state.StateStore) (*Harness, error) {
+	index, err := state.LatestIndex()
+	if err != nil {
+		return nil, err
+	}
+	return &Harness{
+		logger:                    logger,
+		State:                     state,
+		nextIndex:                 index + 1,
+		serversMeetMinimumVersion: true,
+	}, nil
+}
+
 // SubmitPlan is used to handle plan submission
 func (h *Harness) SubmitPlan(plan *structs.Plan) (*structs.PlanResult, State, error) {
 	// Ensure sequential plan application
@@ -272,7 +290,10 @@ func (h *Harness) Snapshot() State {
 // Scheduler is used to return a new scheduler from
 // a snapshot of current state using the harness for planning.
 func (h *Harness) Scheduler(factory Factory) Scheduler {
-	logger := testlog.HCLogger(h.t)
+	logger := h.logger
+	if logger == nil {
+		logger = testlog.HCLogger(h.t)
+	}
 	eventsCh := make(chan interface{})
 
 	// Listen for and log events from the scheduler.
@@ -280,6 +301,10 @@ func (h *Harness) Scheduler(factory Factory) Scheduler {
 		for e := range eventsCh {
 			switch event := e.(type) {
 			case *PortCollisionEvent:
+				if h.t == nil {
+					logger.Warn("unexpected worker eval event", "reason", event.Reason)
+					continue
+				}
 				h.t.Errorf("unexpected worker eval event: %v", event.Reason)
 			}
 		}